@@ -41,6 +41,12 @@ func main() {
 	Generate2d3dTemplate("surface_estimator_test", checkNoChange)
 	Generate2d3dTemplate("metaball", checkNoChange)
 	Generate2d3dTemplate("metaball_test", checkNoChange)
+	Generate2d3dTemplate("poisson_disk", checkNoChange)
+	Generate2d3dTemplate("poisson_disk_test", checkNoChange)
+	Generate2d3dTemplate("sync_mesh", checkNoChange)
+	Generate2d3dTemplate("sync_mesh_test", checkNoChange)
+	Generate2d3dTemplate("spatial_hash", checkNoChange)
+	Generate2d3dTemplate("spatial_hash_test", checkNoChange)
 }
 
 func Generate2d3dTemplate(name string, checkNoChange bool) {
@@ -0,0 +1,318 @@
+package fileformats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	glbMagic     = 0x46546C67
+	glbVersion   = 2
+	glbChunkJSON = 0x4E4F534A
+	glbChunkBin  = 0x004E4942
+
+	gltfComponentFloat  = 5126
+	gltfComponentUint32 = 5125
+
+	gltfTargetArrayBuffer        = 34962
+	gltfTargetElementArrayBuffer = 34963
+)
+
+// A GLTFMesh is a single triangle mesh to be encoded as a
+// glTF 2.0 asset.
+type GLTFMesh struct {
+	// Name is an optional name for the mesh.
+	Name string
+
+	// Positions stores the coordinates of every vertex.
+	Positions [][3]float32
+
+	// UVs optionally stores a texture coordinate for every
+	// vertex, using the glTF convention that (0, 0) is the
+	// top-left corner of the texture.
+	UVs [][2]float32
+
+	// Colors optionally stores a linear RGB color for every
+	// vertex.
+	Colors [][3]float32
+
+	// Indices stores three vertex indices per triangle.
+	Indices []uint32
+
+	// BaseColor is the color factor of the material, which
+	// is multiplied by the texture and vertex colors.
+	BaseColor [4]float32
+
+	// TexturePNG is optional PNG-encoded image data for the
+	// base color texture of the material.
+	TexturePNG []byte
+}
+
+// WriteGLB encodes the meshes as a binary glTF 2.0 (.glb)
+// file.
+//
+// Each mesh receives its own node and material, and no
+// normals are written, so that viewers use flat shading.
+func WriteGLB(w io.Writer, meshes []*GLTFMesh) error {
+	doc, bin, err := buildGLTF(meshes)
+	if err != nil {
+		return errors.Wrap(err, "write GLB")
+	}
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return errors.Wrap(err, "write GLB")
+	}
+	jsonData = padBytes(jsonData, ' ')
+	bin = padBytes(bin, 0)
+
+	totalSize := 12 + 8 + len(jsonData) + 8 + len(bin)
+	if int(uint32(totalSize)) != totalSize {
+		return errors.New("write GLB: file is too large")
+	}
+
+	var header bytes.Buffer
+	binary.Write(&header, binary.LittleEndian, []uint32{
+		glbMagic, glbVersion, uint32(totalSize),
+		uint32(len(jsonData)), glbChunkJSON,
+	})
+	for _, data := range [][]byte{header.Bytes(), jsonData} {
+		if _, err := w.Write(data); err != nil {
+			return errors.Wrap(err, "write GLB")
+		}
+	}
+	err = binary.Write(w, binary.LittleEndian, []uint32{uint32(len(bin)), glbChunkBin})
+	if err != nil {
+		return errors.Wrap(err, "write GLB")
+	}
+	if _, err := w.Write(bin); err != nil {
+		return errors.Wrap(err, "write GLB")
+	}
+	return nil
+}
+
+type gltfDocument struct {
+	Asset       gltfAsset         `json:"asset"`
+	Scene       int               `json:"scene"`
+	Scenes      []gltfScene       `json:"scenes"`
+	Nodes       []gltfNode        `json:"nodes"`
+	Meshes      []gltfMeshJSON    `json:"meshes"`
+	Materials   []gltfMaterial    `json:"materials"`
+	Accessors   []gltfAccessor    `json:"accessors"`
+	BufferViews []gltfBufferView  `json:"bufferViews"`
+	Buffers     []gltfBuffer      `json:"buffers"`
+	Images      []gltfImage       `json:"images,omitempty"`
+	Textures    []gltfTexture     `json:"textures,omitempty"`
+	Samplers    []json.RawMessage `json:"samplers,omitempty"`
+}
+
+type gltfAsset struct {
+	Version   string `json:"version"`
+	Generator string `json:"generator"`
+}
+
+type gltfScene struct {
+	Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+	Name string `json:"name,omitempty"`
+	Mesh int    `json:"mesh"`
+}
+
+type gltfMeshJSON struct {
+	Name       string          `json:"name,omitempty"`
+	Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+	Attributes map[string]int `json:"attributes"`
+	Indices    int            `json:"indices"`
+	Material   int            `json:"material"`
+}
+
+type gltfMaterial struct {
+	PBRMetallicRoughness gltfPBR `json:"pbrMetallicRoughness"`
+	DoubleSided          bool    `json:"doubleSided,omitempty"`
+}
+
+type gltfPBR struct {
+	BaseColorFactor  [4]float32       `json:"baseColorFactor"`
+	BaseColorTexture *gltfTextureInfo `json:"baseColorTexture,omitempty"`
+	MetallicFactor   float32          `json:"metallicFactor"`
+	RoughnessFactor  float32          `json:"roughnessFactor"`
+}
+
+type gltfTextureInfo struct {
+	Index int `json:"index"`
+}
+
+type gltfAccessor struct {
+	BufferView    int       `json:"bufferView"`
+	ComponentType int       `json:"componentType"`
+	Count         int       `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float32 `json:"min,omitempty"`
+	Max           []float32 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+	Buffer     int `json:"buffer"`
+	ByteOffset int `json:"byteOffset"`
+	ByteLength int `json:"byteLength"`
+	Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+	ByteLength int `json:"byteLength"`
+}
+
+type gltfImage struct {
+	BufferView int    `json:"bufferView"`
+	MimeType   string `json:"mimeType"`
+}
+
+type gltfTexture struct {
+	Source  int `json:"source"`
+	Sampler int `json:"sampler"`
+}
+
+func buildGLTF(meshes []*GLTFMesh) (*gltfDocument, []byte, error) {
+	doc := &gltfDocument{
+		Asset:  gltfAsset{Version: "2.0", Generator: "model3d"},
+		Scenes: []gltfScene{{Nodes: []int{}}},
+	}
+	var bin bytes.Buffer
+
+	addView := func(data any, target int) int {
+		offset := bin.Len()
+		if raw, ok := data.([]byte); ok {
+			bin.Write(raw)
+		} else {
+			binary.Write(&bin, binary.LittleEndian, data)
+		}
+		doc.BufferViews = append(doc.BufferViews, gltfBufferView{
+			ByteOffset: offset,
+			ByteLength: bin.Len() - offset,
+			Target:     target,
+		})
+		// Keep every view aligned to four bytes.
+		for bin.Len()%4 != 0 {
+			bin.WriteByte(0)
+		}
+		return len(doc.BufferViews) - 1
+	}
+	addAccessor := func(a gltfAccessor) int {
+		doc.Accessors = append(doc.Accessors, a)
+		return len(doc.Accessors) - 1
+	}
+
+	for i, mesh := range meshes {
+		numVerts := len(mesh.Positions)
+		if len(mesh.Indices)%3 != 0 {
+			return nil, nil, errors.New("number of indices must be divisible by 3")
+		}
+		if mesh.UVs != nil && len(mesh.UVs) != numVerts {
+			return nil, nil, errors.New("number of UVs must match number of positions")
+		}
+		if mesh.Colors != nil && len(mesh.Colors) != numVerts {
+			return nil, nil, errors.New("number of colors must match number of positions")
+		}
+		for _, idx := range mesh.Indices {
+			if int(idx) >= numVerts {
+				return nil, nil, errors.New("vertex index out of bounds")
+			}
+		}
+
+		min := [3]float32{}
+		max := [3]float32{}
+		for j, p := range mesh.Positions {
+			for k, x := range p {
+				if j == 0 {
+					min[k], max[k] = x, x
+				} else {
+					min[k] = float32(math.Min(float64(min[k]), float64(x)))
+					max[k] = float32(math.Max(float64(max[k]), float64(x)))
+				}
+			}
+		}
+
+		attributes := map[string]int{}
+		attributes["POSITION"] = addAccessor(gltfAccessor{
+			BufferView:    addView(mesh.Positions, gltfTargetArrayBuffer),
+			ComponentType: gltfComponentFloat,
+			Count:         numVerts,
+			Type:          "VEC3",
+			Min:           min[:],
+			Max:           max[:],
+		})
+		if mesh.UVs != nil {
+			attributes["TEXCOORD_0"] = addAccessor(gltfAccessor{
+				BufferView:    addView(mesh.UVs, gltfTargetArrayBuffer),
+				ComponentType: gltfComponentFloat,
+				Count:         numVerts,
+				Type:          "VEC2",
+			})
+		}
+		if mesh.Colors != nil {
+			attributes["COLOR_0"] = addAccessor(gltfAccessor{
+				BufferView:    addView(mesh.Colors, gltfTargetArrayBuffer),
+				ComponentType: gltfComponentFloat,
+				Count:         numVerts,
+				Type:          "VEC3",
+			})
+		}
+		indices := addAccessor(gltfAccessor{
+			BufferView:    addView(mesh.Indices, gltfTargetElementArrayBuffer),
+			ComponentType: gltfComponentUint32,
+			Count:         len(mesh.Indices),
+			Type:          "SCALAR",
+		})
+
+		material := gltfMaterial{
+			PBRMetallicRoughness: gltfPBR{
+				BaseColorFactor: mesh.BaseColor,
+				RoughnessFactor: 1,
+			},
+		}
+		if mesh.TexturePNG != nil {
+			if len(doc.Samplers) == 0 {
+				doc.Samplers = append(doc.Samplers, json.RawMessage("{}"))
+			}
+			doc.Images = append(doc.Images, gltfImage{
+				BufferView: addView(mesh.TexturePNG, 0),
+				MimeType:   "image/png",
+			})
+			doc.Textures = append(doc.Textures, gltfTexture{Source: len(doc.Images) - 1})
+			material.PBRMetallicRoughness.BaseColorTexture = &gltfTextureInfo{
+				Index: len(doc.Textures) - 1,
+			}
+		}
+		doc.Materials = append(doc.Materials, material)
+
+		doc.Meshes = append(doc.Meshes, gltfMeshJSON{
+			Name: mesh.Name,
+			Primitives: []gltfPrimitive{{
+				Attributes: attributes,
+				Indices:    indices,
+				Material:   len(doc.Materials) - 1,
+			}},
+		})
+		doc.Nodes = append(doc.Nodes, gltfNode{Name: mesh.Name, Mesh: i})
+		doc.Scenes[0].Nodes = append(doc.Scenes[0].Nodes, i)
+	}
+
+	doc.Buffers = []gltfBuffer{{ByteLength: bin.Len()}}
+	return doc, bin.Bytes(), nil
+}
+
+func padBytes(data []byte, pad byte) []byte {
+	for len(data)%4 != 0 {
+		data = append(data, pad)
+	}
+	return data
+}
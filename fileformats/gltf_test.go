@@ -0,0 +1,86 @@
+package fileformats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteGLB(t *testing.T) {
+	mesh := &GLTFMesh{
+		Name: "tetrahedron",
+		Positions: [][3]float32{
+			{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1},
+		},
+		UVs:        [][2]float32{{0, 0}, {1, 0}, {0, 1}, {1, 1}},
+		Indices:    []uint32{0, 2, 1, 0, 1, 3, 0, 3, 2, 1, 2, 3},
+		BaseColor:  [4]float32{1, 1, 1, 1},
+		TexturePNG: []byte{1, 2, 3, 4, 5},
+	}
+	var buf bytes.Buffer
+	if err := WriteGLB(&buf, []*GLTFMesh{mesh}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	var header [5]uint32
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header); err != nil {
+		t.Fatal(err)
+	}
+	if header[0] != glbMagic || header[1] != glbVersion || int(header[2]) != len(data) {
+		t.Fatalf("unexpected header: %v", header)
+	}
+	if header[4] != glbChunkJSON || header[3]%4 != 0 {
+		t.Fatalf("unexpected JSON chunk header: %v", header[3:])
+	}
+	jsonData := data[20 : 20+header[3]]
+	var doc gltfDocument
+	if err := json.Unmarshal(jsonData, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	binHeader := data[20+header[3] : 28+header[3]]
+	binLength := binary.LittleEndian.Uint32(binHeader)
+	if binary.LittleEndian.Uint32(binHeader[4:]) != glbChunkBin {
+		t.Fatal("missing binary chunk")
+	}
+	if int(28+header[3]+binLength) != len(data) {
+		t.Fatal("unexpected binary chunk length")
+	}
+	if doc.Buffers[0].ByteLength > int(binLength) {
+		t.Fatal("buffer is larger than binary chunk")
+	}
+	bin := data[28+header[3]:]
+
+	prim := doc.Meshes[0].Primitives[0]
+	pos := doc.Accessors[prim.Attributes["POSITION"]]
+	if pos.Count != 4 || pos.Type != "VEC3" || pos.Max[0] != 1 || pos.Min[0] != 0 {
+		t.Errorf("unexpected position accessor: %+v", pos)
+	}
+	indices := doc.Accessors[prim.Indices]
+	if indices.Count != 12 || indices.ComponentType != gltfComponentUint32 {
+		t.Errorf("unexpected index accessor: %+v", indices)
+	}
+	view := doc.BufferViews[indices.BufferView]
+	for i, idx := range mesh.Indices {
+		actual := binary.LittleEndian.Uint32(bin[view.ByteOffset+i*4:])
+		if actual != idx {
+			t.Fatalf("index %d: expected %d but got %d", i, idx, actual)
+		}
+	}
+	if _, ok := prim.Attributes["TEXCOORD_0"]; !ok {
+		t.Error("missing texture coordinates")
+	}
+
+	imageView := doc.BufferViews[doc.Images[0].BufferView]
+	if !bytes.Equal(bin[imageView.ByteOffset:imageView.ByteOffset+imageView.ByteLength],
+		mesh.TexturePNG) {
+		t.Error("unexpected image data")
+	}
+	for _, view := range doc.BufferViews {
+		if view.ByteOffset%4 != 0 {
+			t.Errorf("unaligned buffer view: %+v", view)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/unixpickle/essentials"
 )
 
 type PLYFormat int
@@ -687,3 +688,169 @@ func (p *PLYReader) Read() ([]PLYValue, *PLYElement, error) {
 	}
 	return values, curElem, nil
 }
+
+// A PLYMesh is a polygon mesh decoded from a PLY file.
+type PLYMesh struct {
+	Vertices [][3]float64
+
+	// Colors stores an sRGB color for every vertex, or is
+	// nil if the file has no vertex colors.
+	Colors [][3]uint8
+
+	// Normals stores a normal for every vertex, or is nil
+	// if the file has no vertex normals.
+	Normals [][3]float64
+
+	// Faces stores the vertex indices of every polygon.
+	Faces [][]int
+}
+
+// ReadPLYMesh decodes a mesh from an ASCII or binary PLY
+// file.
+//
+// Vertex colors may be stored as integers in [0, 255] or
+// as floating point values in [0, 1]. Elements besides
+// vertices and faces are ignored.
+func ReadPLYMesh(r io.Reader) (mesh *PLYMesh, err error) {
+	defer essentials.AddCtxTo("read PLY mesh", &err)
+
+	reader, err := NewPLYReader(r)
+	if err != nil {
+		return nil, err
+	}
+	header := reader.Header()
+	res := &PLYMesh{}
+	var vertexElem, faceElem *PLYElement
+	for _, elem := range header.Elements {
+		switch elem.Name {
+		case "vertex":
+			vertexElem = elem
+		case "face":
+			faceElem = elem
+		}
+	}
+	if vertexElem == nil {
+		return nil, errors.New("missing vertex element")
+	}
+
+	coordIndices := [3]int{-1, -1, -1}
+	colorIndices := [3]int{-1, -1, -1}
+	normalIndices := [3]int{-1, -1, -1}
+	for i, prop := range vertexElem.Properties {
+		if prop.LenType != PLYPropertyTypeNone {
+			continue
+		}
+		switch prop.Name {
+		case "x", "y", "z":
+			coordIndices[prop.Name[0]-'x'] = i
+		case "red", "diffuse_red", "r":
+			colorIndices[0] = i
+		case "green", "diffuse_green", "g":
+			colorIndices[1] = i
+		case "blue", "diffuse_blue", "b":
+			colorIndices[2] = i
+		case "nx", "ny", "nz":
+			normalIndices[prop.Name[1]-'x'] = i
+		}
+	}
+	hasIndices := func(indices [3]int) bool {
+		return indices[0] != -1 && indices[1] != -1 && indices[2] != -1
+	}
+	if !hasIndices(coordIndices) {
+		return nil, errors.New("missing vertex coordinate properties")
+	}
+	hasColors := hasIndices(colorIndices)
+	hasNormals := hasIndices(normalIndices)
+
+	faceIndex := -1
+	if faceElem != nil {
+		for i, prop := range faceElem.Properties {
+			if prop.LenType != PLYPropertyTypeNone &&
+				(prop.Name == "vertex_index" || prop.Name == "vertex_indices") {
+				faceIndex = i
+			}
+		}
+		if faceIndex == -1 {
+			return nil, errors.New("missing face vertex index property")
+		}
+	}
+
+	for {
+		values, elem, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if elem == vertexElem {
+			var coord, normal [3]float64
+			var color [3]uint8
+			for i := 0; i < 3; i++ {
+				if coord[i], err = plyValueFloat(values[coordIndices[i]]); err != nil {
+					return nil, err
+				}
+				if hasNormals {
+					if normal[i], err = plyValueFloat(values[normalIndices[i]]); err != nil {
+						return nil, err
+					}
+				}
+				if hasColors {
+					if color[i], err = plyValueColor(values[colorIndices[i]]); err != nil {
+						return nil, err
+					}
+				}
+			}
+			res.Vertices = append(res.Vertices, coord)
+			if hasNormals {
+				res.Normals = append(res.Normals, normal)
+			}
+			if hasColors {
+				res.Colors = append(res.Colors, color)
+			}
+		} else if elem == faceElem {
+			list := values[faceIndex].(PLYValueList)
+			face := make([]int, len(list.Values))
+			for i, v := range list.Values {
+				idx, err := v.LengthValue()
+				if err != nil {
+					return nil, err
+				}
+				face[i] = idx
+			}
+			res.Faces = append(res.Faces, face)
+		}
+	}
+	for _, face := range res.Faces {
+		for _, idx := range face {
+			if idx < 0 || idx >= len(res.Vertices) {
+				return nil, fmt.Errorf("face index out of bounds: %d", idx)
+			}
+		}
+	}
+	return res, nil
+}
+
+func plyValueFloat(v PLYValue) (float64, error) {
+	switch v := v.(type) {
+	case PLYValueFloat32:
+		return float64(v.Value), nil
+	case PLYValueFloat64:
+		return v.Value, nil
+	case PLYValueList:
+		return 0, errors.New("unexpected list value")
+	default:
+		x, err := v.LengthValue()
+		return float64(x), err
+	}
+}
+
+func plyValueColor(v PLYValue) (uint8, error) {
+	switch v.(type) {
+	case PLYValueFloat32, PLYValueFloat64:
+		x, _ := plyValueFloat(v)
+		return uint8(math.Round(255 * math.Max(0, math.Min(1, x)))), nil
+	default:
+		x, err := plyValueFloat(v)
+		return uint8(math.Max(0, math.Min(255, x))), err
+	}
+}
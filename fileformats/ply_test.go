@@ -2,6 +2,8 @@ package fileformats
 
 import (
 	"bytes"
+	"encoding/binary"
+	"reflect"
 	"testing"
 )
 
@@ -145,3 +147,61 @@ func TestPLYReader(t *testing.T) {
 	})
 
 }
+
+func TestReadPLYMesh(t *testing.T) {
+	t.Run("ASCII", func(t *testing.T) {
+		data := "ply\nformat ascii 1.0\ncomment test\nelement vertex 4\nproperty float x\n" +
+			"property float y\nproperty float z\nproperty uchar red\nproperty uchar green\n" +
+			"property uchar blue\nelement face 1\nproperty list uchar int vertex_indices\n" +
+			"end_header\n0 0 0 1 2 3\n1 0 0 4 5 6\n1 1 0 7 8 9\n0 1 0 10 11 12\n4 0 1 2 3\n"
+		mesh, err := ReadPLYMesh(bytes.NewReader([]byte(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := &PLYMesh{
+			Vertices: [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+			Colors:   [][3]uint8{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {10, 11, 12}},
+			Faces:    [][]int{{0, 1, 2, 3}},
+		}
+		if !reflect.DeepEqual(mesh, expected) {
+			t.Errorf("unexpected mesh: %#v", mesh)
+		}
+	})
+	t.Run("Binary", func(t *testing.T) {
+		var buf bytes.Buffer
+		buf.WriteString("ply\nformat binary_little_endian 1.0\nelement vertex 3\n" +
+			"property double x\nproperty double y\nproperty double z\n" +
+			"property float nx\nproperty float ny\nproperty float nz\n" +
+			"property float red\nproperty float green\nproperty float blue\n" +
+			"element face 1\nproperty list uchar uint vertex_index\nend_header\n")
+		vertices := [][3]float64{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}
+		for _, v := range vertices {
+			binary.Write(&buf, binary.LittleEndian, v)
+			binary.Write(&buf, binary.LittleEndian, [3]float32{0, 0, 1})
+			binary.Write(&buf, binary.LittleEndian, [3]float32{1, 0.5, 0})
+		}
+		buf.WriteByte(3)
+		binary.Write(&buf, binary.LittleEndian, [3]uint32{0, 1, 2})
+		mesh, err := ReadPLYMesh(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := &PLYMesh{
+			Vertices: vertices,
+			Colors:   [][3]uint8{{255, 128, 0}, {255, 128, 0}, {255, 128, 0}},
+			Normals:  [][3]float64{{0, 0, 1}, {0, 0, 1}, {0, 0, 1}},
+			Faces:    [][]int{{0, 1, 2}},
+		}
+		if !reflect.DeepEqual(mesh, expected) {
+			t.Errorf("unexpected mesh: %#v", mesh)
+		}
+	})
+	t.Run("OutOfBounds", func(t *testing.T) {
+		data := "ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\n" +
+			"property float y\nproperty float z\nelement face 1\n" +
+			"property list uchar int vertex_index\nend_header\n0 0 0\n3 0 0 1\n"
+		if _, err := ReadPLYMesh(bytes.NewReader([]byte(data))); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package fileformats
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const threeMFContentTypes = `<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+  <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+  <Default Extension="model" ContentType="application/vnd.ms-package.3dmanufacturing-3dmodel+xml"/>
+</Types>
+`
+
+const threeMFRels = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Target="/3D/3dmodel.model" Id="rel0" Type="http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"/>
+</Relationships>
+`
+
+// DefaultThreeMFUnit is the default unit of 3MF files
+// created by NewThreeMFWriter.
+const DefaultThreeMFUnit = "millimeter"
+
+// A ThreeMFWriter encodes a 3MF file, which is a zip
+// archive containing one or more meshes for 3D printing.
+//
+// Objects are streamed to the underlying writer as they
+// are added, and Close() must be called to finish the
+// file.
+type ThreeMFWriter struct {
+	zip   *zip.Writer
+	model *bufio.Writer
+
+	nextID    int
+	objectIDs []int
+	closed    bool
+}
+
+// NewThreeMFWriter creates a ThreeMFWriter that writes to
+// w using the given unit, such as "millimeter" or "inch".
+//
+// If unit is empty, DefaultThreeMFUnit is used.
+func NewThreeMFWriter(w io.Writer, unit string) (*ThreeMFWriter, error) {
+	if unit == "" {
+		unit = DefaultThreeMFUnit
+	}
+	zipWriter := zip.NewWriter(w)
+	for _, file := range [][2]string{
+		{"[Content_Types].xml", threeMFContentTypes},
+		{"_rels/.rels", threeMFRels},
+	} {
+		fw, err := zipWriter.Create(file[0])
+		if err != nil {
+			return nil, errors.Wrap(err, "write 3MF header")
+		}
+		if _, err := fw.Write([]byte(file[1])); err != nil {
+			return nil, errors.Wrap(err, "write 3MF header")
+		}
+	}
+	fw, err := zipWriter.Create("3D/3dmodel.model")
+	if err != nil {
+		return nil, errors.Wrap(err, "write 3MF header")
+	}
+	res := &ThreeMFWriter{
+		zip:    zipWriter,
+		model:  bufio.NewWriter(fw),
+		nextID: 1,
+	}
+	header := `<?xml version="1.0" encoding="UTF-8"?>
+<model unit="` + threeMFEscape(unit) + `" xml:lang="en-US" ` +
+		`xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">
+  <resources>
+`
+	if _, err := res.model.WriteString(header); err != nil {
+		return nil, errors.Wrap(err, "write 3MF header")
+	}
+	return res, nil
+}
+
+// AddObject writes a named mesh object to the file.
+//
+// Each triangle is specified as three indices into the
+// vertices slice, ordered counter-clockwise when viewed
+// from outside the object.
+//
+// If colors is non-nil, it specifies an RGB color for
+// every triangle.
+func (t *ThreeMFWriter) AddObject(name string, vertices [][3]float64, triangles [][3]int,
+	colors [][3]uint8) error {
+	if t.closed {
+		return errors.New("write 3MF object: writer is closed")
+	}
+	if err := t.addObject(name, vertices, triangles, colors); err != nil {
+		return errors.Wrap(err, "write 3MF object")
+	}
+	return nil
+}
+
+func (t *ThreeMFWriter) addObject(name string, vertices [][3]float64, triangles [][3]int,
+	colors [][3]uint8) error {
+	if colors != nil && len(colors) != len(triangles) {
+		return errors.New("number of colors must match number of triangles")
+	}
+	for _, tri := range triangles {
+		for _, idx := range tri {
+			if idx < 0 || idx >= len(vertices) {
+				return errors.New("vertex index out of bounds")
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	var colorIndices []int
+	materialID := -1
+	if colors != nil {
+		materialID = t.nextID
+		t.nextID++
+		colorToIndex := map[[3]uint8]int{}
+		buf.WriteString(`    <basematerials id="` + strconv.Itoa(materialID) + `">` + "\n")
+		colorIndices = make([]int, len(colors))
+		for i, c := range colors {
+			idx, ok := colorToIndex[c]
+			if !ok {
+				idx = len(colorToIndex)
+				colorToIndex[c] = idx
+				fmt.Fprintf(&buf, `      <base name="color%d" displaycolor="#%02X%02X%02X"/>`+"\n",
+					idx, c[0], c[1], c[2])
+			}
+			colorIndices[i] = idx
+		}
+		buf.WriteString("    </basematerials>\n")
+	}
+
+	objectID := t.nextID
+	t.nextID++
+	t.objectIDs = append(t.objectIDs, objectID)
+	buf.WriteString(`    <object id="` + strconv.Itoa(objectID) + `" type="model"`)
+	if name != "" {
+		buf.WriteString(` name="` + threeMFEscape(name) + `"`)
+	}
+	if materialID != -1 && len(colors) > 0 {
+		buf.WriteString(` pid="` + strconv.Itoa(materialID) + `" pindex="` +
+			strconv.Itoa(colorIndices[0]) + `"`)
+	}
+	buf.WriteString(">\n      <mesh>\n        <vertices>\n")
+	if _, err := t.model.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	for _, v := range vertices {
+		line := `          <vertex x="` + threeMFFloat(v[0]) + `" y="` + threeMFFloat(v[1]) +
+			`" z="` + threeMFFloat(v[2]) + `"/>` + "\n"
+		if _, err := t.model.WriteString(line); err != nil {
+			return err
+		}
+	}
+	if _, err := t.model.WriteString("        </vertices>\n        <triangles>\n"); err != nil {
+		return err
+	}
+	for i, tri := range triangles {
+		line := `          <triangle v1="` + strconv.Itoa(tri[0]) + `" v2="` +
+			strconv.Itoa(tri[1]) + `" v3="` + strconv.Itoa(tri[2]) + `"`
+		if colorIndices != nil {
+			line += ` pid="` + strconv.Itoa(materialID) + `" p1="` +
+				strconv.Itoa(colorIndices[i]) + `"`
+		}
+		line += "/>\n"
+		if _, err := t.model.WriteString(line); err != nil {
+			return err
+		}
+	}
+	_, err := t.model.WriteString("        </triangles>\n      </mesh>\n    </object>\n")
+	return err
+}
+
+// Close writes the build section of the file, which
+// includes every object, and finishes the archive.
+//
+// This does not close the underlying writer.
+func (t *ThreeMFWriter) Close() error {
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	var buf bytes.Buffer
+	buf.WriteString("  </resources>\n  <build>\n")
+	for _, id := range t.objectIDs {
+		buf.WriteString(`    <item objectid="` + strconv.Itoa(id) + `"/>` + "\n")
+	}
+	buf.WriteString("  </build>\n</model>\n")
+	if _, err := t.model.Write(buf.Bytes()); err != nil {
+		return errors.Wrap(err, "close 3MF writer")
+	}
+	if err := t.model.Flush(); err != nil {
+		return errors.Wrap(err, "close 3MF writer")
+	}
+	if err := t.zip.Close(); err != nil {
+		return errors.Wrap(err, "close 3MF writer")
+	}
+	return nil
+}
+
+func threeMFFloat(x float64) string {
+	return strconv.FormatFloat(x, 'g', -1, 32)
+}
+
+func threeMFEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
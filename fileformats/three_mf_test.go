@@ -0,0 +1,144 @@
+package fileformats
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+)
+
+func TestThreeMFWriter(t *testing.T) {
+	vertices := [][3]float64{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	triangles := [][3]int{{0, 2, 1}, {0, 1, 3}, {0, 3, 2}, {1, 2, 3}}
+	colors := [][3]uint8{{255, 0, 0}, {0, 255, 0}, {255, 0, 0}, {1, 2, 3}}
+
+	var buf bytes.Buffer
+	w, err := NewThreeMFWriter(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddObject("colored & <named>", vertices, triangles, colors); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddObject("plain", vertices, triangles[:2], nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.AddObject("bad", vertices, [][3]int{{0, 1, 4}}, nil); err == nil {
+		t.Error("expected error for out-of-bounds index")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, f := range reader.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = data
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "3D/3dmodel.model"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("missing file: %s", name)
+		}
+	}
+
+	var model struct {
+		Unit      string `xml:"unit,attr"`
+		Materials []struct {
+			ID    int `xml:"id,attr"`
+			Bases []struct {
+				Color string `xml:"displaycolor,attr"`
+			} `xml:"base"`
+		} `xml:"resources>basematerials"`
+		Objects []struct {
+			ID       int    `xml:"id,attr"`
+			Name     string `xml:"name,attr"`
+			Vertices []struct {
+				X float64 `xml:"x,attr"`
+				Y float64 `xml:"y,attr"`
+				Z float64 `xml:"z,attr"`
+			} `xml:"mesh>vertices>vertex"`
+			Triangles []struct {
+				V1  int  `xml:"v1,attr"`
+				V2  int  `xml:"v2,attr"`
+				V3  int  `xml:"v3,attr"`
+				PID *int `xml:"pid,attr"`
+				P1  *int `xml:"p1,attr"`
+			} `xml:"mesh>triangles>triangle"`
+		} `xml:"resources>object"`
+		Items []struct {
+			ObjectID int `xml:"objectid,attr"`
+		} `xml:"build>item"`
+	}
+	if err := xml.Unmarshal(files["3D/3dmodel.model"], &model); err != nil {
+		t.Fatal(err)
+	}
+
+	if model.Unit != DefaultThreeMFUnit {
+		t.Errorf("unexpected unit: %s", model.Unit)
+	}
+	if len(model.Objects) != 2 || len(model.Items) != 2 {
+		t.Fatalf("unexpected object count: %d objects, %d items", len(model.Objects),
+			len(model.Items))
+	}
+	for i, obj := range model.Objects {
+		if model.Items[i].ObjectID != obj.ID {
+			t.Errorf("item %d: expected object %d but got %d", i, obj.ID, model.Items[i].ObjectID)
+		}
+		if len(obj.Vertices) != len(vertices) {
+			t.Errorf("object %d: unexpected vertex count %d", i, len(obj.Vertices))
+		}
+		for j, v := range obj.Vertices {
+			if [3]float64{v.X, v.Y, v.Z} != vertices[j] {
+				t.Errorf("object %d: unexpected vertex %d: %v", i, j, v)
+			}
+		}
+	}
+	if name := model.Objects[0].Name; name != "colored & <named>" {
+		t.Errorf("unexpected name: %s", name)
+	}
+	if name := model.Objects[1].Name; name != "plain" {
+		t.Errorf("unexpected name: %s", name)
+	}
+
+	if len(model.Materials) != 1 {
+		t.Fatalf("unexpected material count: %d", len(model.Materials))
+	}
+	bases := model.Materials[0].Bases
+	expectedColors := []string{"#FF0000", "#00FF00", "#FF0000", "#010203"}
+	colored := model.Objects[0]
+	if len(colored.Triangles) != len(triangles) {
+		t.Fatalf("unexpected triangle count: %d", len(colored.Triangles))
+	}
+	for i, tri := range colored.Triangles {
+		if [3]int{tri.V1, tri.V2, tri.V3} != triangles[i] {
+			t.Errorf("triangle %d: unexpected indices", i)
+		}
+		if tri.PID == nil || tri.P1 == nil || *tri.PID != model.Materials[0].ID {
+			t.Fatalf("triangle %d: missing material", i)
+		}
+		if color := bases[*tri.P1].Color; color != expectedColors[i] {
+			t.Errorf("triangle %d: expected color %s but got %s", i, expectedColors[i], color)
+		}
+	}
+	if len(bases) != 3 {
+		t.Errorf("expected 3 unique colors but got %d", len(bases))
+	}
+	for i, tri := range model.Objects[1].Triangles {
+		if tri.PID != nil || tri.P1 != nil {
+			t.Errorf("triangle %d: unexpected material", i)
+		}
+	}
+}
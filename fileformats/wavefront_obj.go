@@ -3,8 +3,13 @@ package fileformats
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/unixpickle/essentials"
 )
 
 // An OBJFileFaceGroup is a group of faces with one
@@ -70,6 +75,105 @@ func (o *OBJFile) Write(w io.Writer) error {
 	return buf.Flush()
 }
 
+// ReadOBJFile decodes a Wavefront obj file.
+//
+// Faces with more than three vertices are split into
+// triangles around their first vertex, and negative
+// (relative) indices are converted to absolute indices.
+// Unsupported statements, such as object names, groups,
+// and smoothing groups, are ignored.
+func ReadOBJFile(r io.Reader) (o *OBJFile, err error) {
+	defer essentials.AddCtxTo("read OBJ file", &err)
+
+	res := &OBJFile{}
+	var group *OBJFileFaceGroup
+	err = readWavefrontLines(r, func(lineIdx int, fields []string) error {
+		args := fields[1:]
+		switch fields[0] {
+		case "mtllib":
+			if len(args) == 0 {
+				return fmt.Errorf("line %d: missing material file", lineIdx)
+			}
+			res.MaterialFiles = append(res.MaterialFiles, strings.Join(args, " "))
+		case "v", "vn":
+			c, err := parseWavefrontFloats(args, 3, 3)
+			if err != nil {
+				return errors.Wrapf(err, "line %d", lineIdx)
+			}
+			if fields[0] == "v" {
+				res.Vertices = append(res.Vertices, [3]float64{c[0], c[1], c[2]})
+			} else {
+				res.Normals = append(res.Normals, [3]float64{c[0], c[1], c[2]})
+			}
+		case "vt":
+			c, err := parseWavefrontFloats(args, 1, 2)
+			if err != nil {
+				return errors.Wrapf(err, "line %d", lineIdx)
+			}
+			if len(c) == 1 {
+				c = append(c, 0)
+			}
+			res.UVs = append(res.UVs, [2]float64{c[0], c[1]})
+		case "usemtl":
+			group = &OBJFileFaceGroup{Material: strings.Join(args, " ")}
+			res.FaceGroups = append(res.FaceGroups, group)
+		case "f":
+			if len(args) < 3 {
+				return fmt.Errorf("line %d: face has fewer than three vertices", lineIdx)
+			}
+			poly := make([][3]int, len(args))
+			for i, arg := range args {
+				v, err := res.parseFaceVertex(arg)
+				if err != nil {
+					return errors.Wrapf(err, "line %d", lineIdx)
+				}
+				poly[i] = v
+			}
+			if group == nil {
+				group = &OBJFileFaceGroup{}
+				res.FaceGroups = append(res.FaceGroups, group)
+			}
+			for i := 2; i < len(poly); i++ {
+				group.Faces = append(group.Faces, [3][3]int{poly[0], poly[i-1], poly[i]})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (o *OBJFile) parseFaceVertex(arg string) ([3]int, error) {
+	var res [3]int
+	parts := strings.Split(arg, "/")
+	if len(parts) > 3 {
+		return res, errors.New("invalid face vertex: " + arg)
+	}
+	counts := [3]int{len(o.Vertices), len(o.UVs), len(o.Normals)}
+	for i, part := range parts {
+		if part == "" {
+			if i == 0 {
+				return res, errors.New("invalid face vertex: " + arg)
+			}
+			continue
+		}
+		idx, err := strconv.Atoi(part)
+		if err != nil {
+			return res, errors.New("invalid face vertex: " + arg)
+		}
+		if idx < 0 {
+			idx += counts[i] + 1
+		}
+		if idx < 1 || idx > counts[i] {
+			return res, errors.New("face index out of bounds: " + arg)
+		}
+		res[i] = idx
+	}
+	return res, nil
+}
+
 func (o *OBJFile) encode2D(name string, c [2]float64) string {
 	return name + " " + strconv.FormatFloat(c[0], 'f', -1, 32) +
 		" " + strconv.FormatFloat(c[1], 'f', -1, 32) + "\n"
@@ -174,6 +278,109 @@ type MTLFile struct {
 	Materials []*MTLFileMaterial
 }
 
+// ReadMTLFile decodes a Wavefront mtl file.
+//
+// Only the fields of MTLFileMaterial are decoded, and
+// other statements are ignored.
+func ReadMTLFile(r io.Reader) (m *MTLFile, err error) {
+	defer essentials.AddCtxTo("read MTL file", &err)
+
+	res := &MTLFile{}
+	var material *MTLFileMaterial
+	err = readWavefrontLines(r, func(lineIdx int, fields []string) error {
+		args := fields[1:]
+		if fields[0] == "newmtl" {
+			material = &MTLFileMaterial{Name: strings.Join(args, " ")}
+			res.Materials = append(res.Materials, material)
+			return nil
+		}
+		var color *[3]float32
+		var texture **MTLFileTextureMap
+		switch fields[0] {
+		case "Ka", "Kd", "Ks", "Ns", "map_Ka", "map_Kd", "map_Ks", "map_Ns":
+			if material == nil {
+				return fmt.Errorf("line %d: %s before newmtl", lineIdx, fields[0])
+			}
+		default:
+			return nil
+		}
+		switch fields[0] {
+		case "Ka":
+			color = &material.Ambient
+		case "Kd":
+			color = &material.Diffuse
+		case "Ks":
+			color = &material.Specular
+		case "Ns":
+			x, err := parseWavefrontFloats(args, 1, 1)
+			if err != nil {
+				return errors.Wrapf(err, "line %d", lineIdx)
+			}
+			material.SpecularExponent = float32(x[0])
+		case "map_Ka":
+			texture = &material.AmbientMap
+		case "map_Kd":
+			texture = &material.DiffuseMap
+		case "map_Ks":
+			texture = &material.SpecularMap
+		case "map_Ns":
+			texture = &material.HighlightMap
+		}
+		if color != nil {
+			c, err := parseWavefrontFloats(args, 1, 3)
+			if err != nil {
+				return errors.Wrapf(err, "line %d", lineIdx)
+			}
+			if len(c) == 1 {
+				c = append(c, c[0], c[0])
+			}
+			*color = [3]float32{float32(c[0]), float32(c[1]), float32(c[2])}
+		} else if texture != nil {
+			tex, err := parseMTLTextureMap(args)
+			if err != nil {
+				return errors.Wrapf(err, "line %d", lineIdx)
+			}
+			*texture = tex
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func parseMTLTextureMap(args []string) (*MTLFileTextureMap, error) {
+	if len(args) == 0 {
+		return nil, errors.New("missing texture filename")
+	}
+	res := &MTLFileTextureMap{Filename: args[len(args)-1]}
+	var name string
+	var values []string
+	flush := func() {
+		if name != "" {
+			if res.Options == nil {
+				res.Options = map[string]string{}
+			}
+			res.Options[name] = strings.Join(values, " ")
+		}
+		name, values = "", nil
+	}
+	for _, arg := range args[:len(args)-1] {
+		_, numErr := strconv.ParseFloat(arg, 64)
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 && numErr != nil {
+			flush()
+			name = arg[1:]
+		} else if name == "" {
+			return nil, errors.New("unexpected texture option value: " + arg)
+		} else {
+			values = append(values, arg)
+		}
+	}
+	flush()
+	return res, nil
+}
+
 func (m *MTLFile) Write(w io.Writer) error {
 	buf := bufio.NewWriter(w)
 	for _, mat := range m.Materials {
@@ -183,3 +390,52 @@ func (m *MTLFile) Write(w io.Writer) error {
 	}
 	return buf.Flush()
 }
+
+// readWavefrontLines calls f with the whitespace-separated
+// fields of every non-empty, non-comment line of r.
+func readWavefrontLines(r io.Reader, f func(lineIdx int, fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	var lineIdx int
+	var continued string
+	for scanner.Scan() {
+		lineIdx++
+		line := continued + scanner.Text()
+		if strings.HasSuffix(line, "\\") {
+			continued = line[:len(line)-1] + " "
+			continue
+		}
+		continued = ""
+		if idx := strings.IndexByte(line, '#'); idx != -1 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := f(lineIdx, fields); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// parseWavefrontFloats parses the first maxCount numbers
+// of args, requiring at least minCount of them.
+func parseWavefrontFloats(args []string, minCount, maxCount int) ([]float64, error) {
+	if len(args) < minCount {
+		return nil, fmt.Errorf("expected at least %d values but got %d", minCount, len(args))
+	}
+	if len(args) > maxCount {
+		args = args[:maxCount]
+	}
+	res := make([]float64, len(args))
+	for i, arg := range args {
+		x, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, errors.New("invalid number: " + arg)
+		}
+		res[i] = x
+	}
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package fileformats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadOBJFile(t *testing.T) {
+	data := `# A unit square and a triangle.
+mtllib materials.mtl
+o square
+v 0 0 0
+v 1 0 0
+v 1 1 0 0.5 0.5 0.5
+v 0 1 0
+vt 0 0
+vt 1 0
+vt 1 1 0
+vt 0 1
+vn 0 0 1
+usemtl red
+f 1/1/1 2/2/1 3/3/1 4/4/1
+usemtl blue
+f -4//-1 -2//-1 \
+  -1//-1
+`
+	obj, err := ReadOBJFile(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &OBJFile{
+		MaterialFiles: []string{"materials.mtl"},
+		Vertices:      [][3]float64{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+		UVs:           [][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}},
+		Normals:       [][3]float64{{0, 0, 1}},
+		FaceGroups: []*OBJFileFaceGroup{
+			{
+				Material: "red",
+				Faces: [][3][3]int{
+					{{1, 1, 1}, {2, 2, 1}, {3, 3, 1}},
+					{{1, 1, 1}, {3, 3, 1}, {4, 4, 1}},
+				},
+			},
+			{
+				Material: "blue",
+				Faces:    [][3][3]int{{{1, 0, 1}, {3, 0, 1}, {4, 0, 1}}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(obj, expected) {
+		t.Fatalf("unexpected file: %#v", obj)
+	}
+
+	var buf bytes.Buffer
+	if err := obj.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadOBJFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Fatalf("unexpected re-decoded file: %#v", decoded)
+	}
+
+	for _, bad := range []string{"v 1 2\n", "v 1 2 3\nf 1 2\n", "v 1 2 3\nf 1 1 2\n",
+		"v 1 2 3\nf 1/1 1 1\n"} {
+		if _, err := ReadOBJFile(strings.NewReader(bad)); err == nil {
+			t.Errorf("expected error for file: %q", bad)
+		}
+	}
+}
+
+func TestReadMTLFile(t *testing.T) {
+	original := &MTLFile{
+		Materials: []*MTLFileMaterial{
+			{
+				Name:     "red",
+				Diffuse:  [3]float32{1, 0, 0},
+				Specular: [3]float32{0.5, 0.5, 0.5},
+
+				SpecularExponent: 10,
+				DiffuseMap: &MTLFileTextureMap{
+					Filename: "texture.png",
+					Options:  map[string]string{"bm": "-0.5"},
+				},
+			},
+			{
+				Name:    "blue",
+				Ambient: [3]float32{0, 0, 0.25},
+				Diffuse: [3]float32{0, 0, 1},
+			},
+		},
+	}
+	var buf bytes.Buffer
+	if err := original.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ReadMTLFile(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Fatalf("unexpected file: %#v", decoded)
+	}
+
+	decoded, err = ReadMTLFile(strings.NewReader("newmtl gray\nKd 0.5\nillum 2\n" +
+		"map_Kd -s 2 2 1 -clamp on tex.png\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gray := decoded.Materials[0]
+	if gray.Diffuse != [3]float32{0.5, 0.5, 0.5} {
+		t.Errorf("unexpected diffuse color: %v", gray.Diffuse)
+	}
+	expectedMap := &MTLFileTextureMap{
+		Filename: "tex.png",
+		Options:  map[string]string{"s": "2 2 1", "clamp": "on"},
+	}
+	if !reflect.DeepEqual(gray.DiffuseMap, expectedMap) {
+		t.Errorf("unexpected texture map: %#v", gray.DiffuseMap)
+	}
+}
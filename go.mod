@@ -6,4 +6,7 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/unixpickle/essentials v1.3.0
 	github.com/unixpickle/splaytree v1.1.0
+	golang.org/x/image v0.18.0
 )
+
+require golang.org/x/text v0.16.0 // indirect
@@ -0,0 +1,132 @@
+package model2d
+
+import "math"
+
+// AffineTransform is a Transform that multiplies
+// coordinates by a matrix and then adds a translation.
+//
+// Any composition of rotations, scales, shears,
+// reflections, and translations can be represented as a
+// single AffineTransform.
+type AffineTransform struct {
+	Matrix      *Matrix2
+	Translation Coord
+}
+
+// NewAffineTransform creates an AffineTransform equivalent
+// to applying t1, then t2, etc.
+//
+// Each transform must be an *AffineTransform, *Translate,
+// *Scale, *VecScale, a Matrix2Transform (such as the
+// result of Rotation), or a JoinedTransform of these.
+// Otherwise, this panics.
+func NewAffineTransform(ts ...Transform) *AffineTransform {
+	res := &AffineTransform{Matrix: &Matrix2{1, 0, 0, 1}}
+	for _, t := range ts {
+		res = res.Compose(affineFromTransform(t))
+	}
+	return res
+}
+
+// Shear creates a transform which shears coordinates,
+// mapping (x, y) to (x + xy*y, y + yx*x).
+func Shear(xy, yx float64) *AffineTransform {
+	return &AffineTransform{Matrix: &Matrix2{1, xy, yx, 1}}
+}
+
+// ShearSolid creates a new Solid by shearing a Solid.
+//
+// See Shear for the meaning of the arguments.
+func ShearSolid(solid Solid, xy, yx float64) Solid {
+	return TransformSolid(Shear(xy, yx), solid)
+}
+
+func (a *AffineTransform) Apply(c Coord) Coord {
+	return a.Matrix.MulColumn(c).Add(a.Translation)
+}
+
+// ApplyBounds transforms the corners of the bounds, giving
+// the tightest possible bounds for the transformed
+// rectangle.
+func (a *AffineTransform) ApplyBounds(min, max Coord) (Coord, Coord) {
+	newMin, newMax := (&Matrix2Transform{Matrix: a.Matrix}).ApplyBounds(min, max)
+	return newMin.Add(a.Translation), newMax.Add(a.Translation)
+}
+
+func (a *AffineTransform) Inverse() Transform {
+	inv := a.Matrix.Inverse()
+	return &AffineTransform{
+		Matrix:      inv,
+		Translation: inv.MulColumn(a.Translation).Scale(-1),
+	}
+}
+
+// Compose creates a transform equivalent to applying a and
+// then next.
+func (a *AffineTransform) Compose(next *AffineTransform) *AffineTransform {
+	return &AffineTransform{
+		Matrix:      next.Matrix.Mul(a.Matrix),
+		Translation: next.Apply(a.Translation),
+	}
+}
+
+// DistTransform returns a DistTransform equivalent to a if
+// a uniformly scales distances, i.e. if it is a
+// composition of rotations, reflections, uniform scales,
+// and translations.
+//
+// If a distorts distances non-uniformly (for example, if
+// it contains a shear), then false is returned.
+func (a *AffineTransform) DistTransform() (DistTransform, bool) {
+	m := a.Matrix
+	col1 := XY(m[0], m[2])
+	col2 := XY(m[1], m[3])
+	scale1, scale2 := col1.Norm(), col2.Norm()
+	epsilon := 1e-8 * math.Max(scale1, scale2)
+	if scale1 == 0 || math.Abs(scale1-scale2) > epsilon ||
+		math.Abs(col1.Dot(col2)) > epsilon*scale1 {
+		return nil, false
+	}
+	return &similarityTransform{AffineTransform: *a, scale: scale1}, true
+}
+
+// similarityTransform is an AffineTransform which scales
+// distances uniformly.
+type similarityTransform struct {
+	AffineTransform
+	scale float64
+}
+
+func (s *similarityTransform) ApplyDistance(d float64) float64 {
+	return d * s.scale
+}
+
+func (s *similarityTransform) Inverse() Transform {
+	return &similarityTransform{
+		AffineTransform: *s.AffineTransform.Inverse().(*AffineTransform),
+		scale:           1 / s.scale,
+	}
+}
+
+func affineFromTransform(t Transform) *AffineTransform {
+	switch t := t.(type) {
+	case *AffineTransform:
+		return t
+	case *similarityTransform:
+		return &t.AffineTransform
+	case *Translate:
+		return &AffineTransform{Matrix: &Matrix2{1, 0, 0, 1}, Translation: t.Offset}
+	case *Scale:
+		return &AffineTransform{Matrix: &Matrix2{t.Scale, 0, 0, t.Scale}}
+	case *VecScale:
+		return &AffineTransform{Matrix: &Matrix2{t.Scale.X, 0, 0, t.Scale.Y}}
+	case *Matrix2Transform:
+		return &AffineTransform{Matrix: t.Matrix}
+	case *orthoMatrix2Transform:
+		return &AffineTransform{Matrix: t.Matrix}
+	case JoinedTransform:
+		return NewAffineTransform(t...)
+	default:
+		panic("transform is not affine")
+	}
+}
@@ -0,0 +1,91 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAffineTransform(t *testing.T) {
+	joined := JoinedTransform{
+		Rotation(0.7),
+		&VecScale{Scale: XY(2, 0.5)},
+		Shear(0.3, -0.2),
+		&Translate{Offset: XY(1, -3)},
+	}
+	affine := NewAffineTransform(joined...)
+	inv := affine.Inverse()
+	for i := 0; i < 100; i++ {
+		c := NewCoordRandNorm()
+		expected := joined.Apply(c)
+		actual := affine.Apply(c)
+		if actual.Dist(expected) > 1e-8 {
+			t.Fatalf("expected %v but got %v", expected, actual)
+		}
+		if c1 := inv.Apply(actual); c1.Dist(c) > 1e-8 {
+			t.Fatalf("inverse mapped %v to %v", c, c1)
+		}
+	}
+
+	// Bounds should be tight around the transformed corners.
+	min, max := XY(-1, -2), XY(3, 1)
+	newMin, newMax := affine.ApplyBounds(min, max)
+	expectedMin, expectedMax := joined.Apply(min), joined.Apply(min)
+	for _, c := range []Coord{min, max, XY(min.X, max.Y), XY(max.X, min.Y)} {
+		expectedMin = expectedMin.Min(joined.Apply(c))
+		expectedMax = expectedMax.Max(joined.Apply(c))
+	}
+	if newMin.Dist(expectedMin) > 1e-8 || newMax.Dist(expectedMax) > 1e-8 {
+		t.Errorf("expected bounds %v, %v but got %v, %v", expectedMin, expectedMax,
+			newMin, newMax)
+	}
+
+	if _, ok := affine.DistTransform(); ok {
+		t.Error("sheared transform should not be a DistTransform")
+	}
+}
+
+func TestAffineTransformDistTransform(t *testing.T) {
+	affine := NewAffineTransform(Rotation(1.3), &Scale{Scale: 2.5},
+		&Translate{Offset: XY(2, 3)})
+	dt, ok := affine.DistTransform()
+	if !ok {
+		t.Fatal("expected a DistTransform")
+	}
+	for i := 0; i < 100; i++ {
+		c1, c2 := NewCoordRandNorm(), NewCoordRandNorm()
+		expected := dt.Apply(c1).Dist(dt.Apply(c2))
+		actual := dt.ApplyDistance(c1.Dist(c2))
+		if math.Abs(actual-expected) > 1e-8 {
+			t.Fatalf("expected distance %f but got %f", expected, actual)
+		}
+	}
+	inv := dt.Inverse().(DistTransform)
+	if d := inv.ApplyDistance(2.5); math.Abs(d-1) > 1e-8 {
+		t.Errorf("expected inverse distance 1 but got %f", d)
+	}
+
+	// TransformSDF should work with the result.
+	sdf := TransformSDF(dt, &Circle{Radius: 1})
+	for i := 0; i < 100; i++ {
+		c := NewCoordRandBounds(sdf.Min(), sdf.Max())
+		expected := (&Circle{Center: XY(2, 3), Radius: 2.5}).SDF(c)
+		if actual := sdf.SDF(c); math.Abs(actual-expected) > 1e-8 {
+			t.Fatalf("expected SDF %f but got %f", expected, actual)
+		}
+	}
+}
+
+func TestShearSolid(t *testing.T) {
+	rect := &Rect{MinVal: XY(0, 0), MaxVal: XY(1, 1)}
+	sheared := ShearSolid(rect, 0.5, 0)
+	for i := 0; i < 1000; i++ {
+		c := NewCoordRandBounds(XY(-1, -1), XY(2, 2))
+		expected := c.Y >= 0 && c.Y <= 1 && c.X-0.5*c.Y >= 0 && c.X-0.5*c.Y <= 1
+		if sheared.Contains(c) != expected {
+			t.Fatalf("unexpected containment at %v", c)
+		}
+	}
+	if sheared.Max().Dist(XY(1.5, 1)) > 1e-8 {
+		t.Errorf("unexpected max: %v", sheared.Max())
+	}
+}
@@ -0,0 +1,92 @@
+package model2d
+
+import "math"
+
+// An Arc is a section of a circle, traced from the angle
+// StartAngle to the angle EndAngle.
+//
+// If EndAngle is less than StartAngle, the arc is traced
+// clockwise. Angles are measured in radians.
+type Arc struct {
+	Center     Coord
+	Radius     float64
+	StartAngle float64
+	EndAngle   float64
+}
+
+// NewArcPoints creates the arc which starts at p1, ends at
+// p2, and has the given radius.
+//
+// Of the two possible circles, the one producing the
+// shorter arc is chosen, unless largeArc is true.
+// If clockwise is true, the arc is traced clockwise.
+//
+// If the radius is too small to connect the two points,
+// it is increased to half of the distance between them.
+func NewArcPoints(p1, p2 Coord, radius float64, largeArc, clockwise bool) *Arc {
+	chord := p2.Sub(p1)
+	halfDist := chord.Norm() / 2
+	radius = math.Max(math.Abs(radius), halfDist)
+	if halfDist == 0 {
+		return &Arc{Center: p1, Radius: 0}
+	}
+
+	// Distance from the chord midpoint to the center.
+	h := math.Sqrt(math.Max(0, radius*radius-halfDist*halfDist))
+	perp := XY(-chord.Y, chord.X).Scale(1 / (2 * halfDist))
+	if largeArc != clockwise {
+		perp = perp.Scale(-1)
+	}
+	center := p1.Mid(p2).Add(perp.Scale(h))
+
+	start := math.Atan2(p1.Y-center.Y, p1.X-center.X)
+	end := math.Atan2(p2.Y-center.Y, p2.X-center.X)
+	if clockwise {
+		for end > start {
+			end -= 2 * math.Pi
+		}
+	} else {
+		for end < start {
+			end += 2 * math.Pi
+		}
+	}
+	return &Arc{Center: center, Radius: radius, StartAngle: start, EndAngle: end}
+}
+
+// Eval evaluates the arc at time t, where 0 <= t <= 1.
+func (a *Arc) Eval(t float64) Coord {
+	theta := a.StartAngle + t*(a.EndAngle-a.StartAngle)
+	return a.Center.Add(NewCoordPolar(theta, a.Radius))
+}
+
+// Length computes the arclength of the arc.
+func (a *Arc) Length() float64 {
+	return math.Abs(a.EndAngle-a.StartAngle) * a.Radius
+}
+
+// Flatten approximates the arc as a polyline whose
+// segments are within tol of the true arc.
+//
+// The first and last points of the result are the end
+// points of the arc.
+func (a *Arc) Flatten(tol float64) []Coord {
+	sweep := math.Abs(a.EndAngle - a.StartAngle)
+	n := 1
+	if tol < a.Radius {
+		// The sagitta of a chord spanning an angle theta is
+		// r*(1-cos(theta/2)).
+		maxAngle := 2 * math.Acos(1-tol/a.Radius)
+		n = int(math.Max(1, math.Ceil(sweep/maxAngle)))
+	}
+	res := make([]Coord, n+1)
+	for i := range res {
+		res[i] = a.Eval(float64(i) / float64(n))
+	}
+	return res
+}
+
+// Mesh creates a mesh approximating the arc within the
+// given tolerance, using Flatten.
+func (a *Arc) Mesh(tol float64) *Mesh {
+	return PolylineMesh(a.Flatten(tol), false)
+}
@@ -138,6 +138,267 @@ func (b *Bitmap) Invert() *Bitmap {
 	return res
 }
 
+// Dilate creates a new bitmap where every pixel is true if
+// any pixel within the given radius is true in b.
+//
+// The neighborhood of each pixel is a disk, so that
+// dilation grows shapes uniformly in every direction.
+func (b *Bitmap) Dilate(radius int) *Bitmap {
+	return b.morphology(radius, true)
+}
+
+// Erode creates a new bitmap where every pixel is true
+// only if every pixel within the given radius is true in
+// b.
+//
+// Pixels outside of the bitmap are considered false, so
+// shapes are also eroded away from the edges of the
+// bitmap.
+func (b *Bitmap) Erode(radius int) *Bitmap {
+	return b.morphology(radius, false)
+}
+
+// Open erodes and then dilates the bitmap, removing
+// features (such as noise and thin protrusions) which are
+// smaller than the radius.
+func (b *Bitmap) Open(radius int) *Bitmap {
+	return b.Erode(radius).Dilate(radius)
+}
+
+// Close dilates and then erodes the bitmap, filling gaps
+// and holes which are smaller than the radius.
+func (b *Bitmap) Close(radius int) *Bitmap {
+	return b.Dilate(radius).Erode(radius)
+}
+
+func (b *Bitmap) morphology(radius int, dilate bool) *Bitmap {
+	var offsets [][2]int
+	for y := -radius; y <= radius; y++ {
+		for x := -radius; x <= radius; x++ {
+			if x*x+y*y <= radius*radius {
+				offsets = append(offsets, [2]int{x, y})
+			}
+		}
+	}
+	res := NewBitmap(b.Width, b.Height)
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			value := !dilate
+			for _, o := range offsets {
+				if b.Get(x+o[0], y+o[1]) == dilate {
+					value = dilate
+					break
+				}
+			}
+			res.Data[x+y*b.Width] = value
+		}
+	}
+	return res
+}
+
+// FillHoles creates a new bitmap where holes (regions of
+// false pixels which do not touch the edge of the bitmap)
+// are filled in with true pixels.
+//
+// If maxSize is non-zero, only holes with at most maxSize
+// pixels are filled, which can be used to remove noise
+// without affecting intended holes.
+// Connectivity is determined using the four direct
+// neighbors of each pixel.
+//
+// To remove small islands rather than holes, use
+// b.Invert().FillHoles(maxSize).Invert().
+func (b *Bitmap) FillHoles(maxSize int) *Bitmap {
+	res := NewBitmap(b.Width, b.Height)
+	copy(res.Data, b.Data)
+
+	visited := make([]bool, len(b.Data))
+	var component []int
+	for start, value := range b.Data {
+		if value || visited[start] {
+			continue
+		}
+		component = append(component[:0], start)
+		visited[start] = true
+		touchesEdge := false
+		for i := 0; i < len(component); i++ {
+			idx := component[i]
+			x, y := idx%b.Width, idx/b.Width
+			if x == 0 || y == 0 || x == b.Width-1 || y == b.Height-1 {
+				touchesEdge = true
+			}
+			for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] < 0 || n[1] < 0 || n[0] >= b.Width || n[1] >= b.Height {
+					continue
+				}
+				nIdx := n[0] + n[1]*b.Width
+				if !b.Data[nIdx] && !visited[nIdx] {
+					visited[nIdx] = true
+					component = append(component, nIdx)
+				}
+			}
+		}
+		if !touchesEdge && (maxSize == 0 || len(component) <= maxSize) {
+			for _, idx := range component {
+				res.Data[idx] = true
+			}
+		}
+	}
+	return res
+}
+
+// Thin reduces the shapes in the bitmap to skeletons
+// which are one pixel wide, using the Zhang-Suen thinning
+// algorithm.
+//
+// Skeletons preserve the connectivity of the original
+// shapes, and run roughly along their centerlines. This
+// can be used to turn drawn strokes into paths with
+// Polylines().
+func (b *Bitmap) Thin() *Bitmap {
+	res := NewBitmap(b.Width, b.Height)
+	copy(res.Data, b.Data)
+
+	var toRemove []int
+	for changed := true; changed; {
+		changed = false
+		for step := 0; step < 2; step++ {
+			toRemove = toRemove[:0]
+			for y := 0; y < res.Height; y++ {
+				for x := 0; x < res.Width; x++ {
+					if res.Data[x+y*res.Width] && res.thinRemovable(x, y, step) {
+						toRemove = append(toRemove, x+y*res.Width)
+					}
+				}
+			}
+			for _, idx := range toRemove {
+				res.Data[idx] = false
+			}
+			changed = changed || len(toRemove) > 0
+		}
+	}
+	return res
+}
+
+func (b *Bitmap) thinRemovable(x, y, step int) bool {
+	// Neighbors in clockwise order, starting above.
+	n := [8]bool{
+		b.Get(x, y-1), b.Get(x+1, y-1), b.Get(x+1, y), b.Get(x+1, y+1),
+		b.Get(x, y+1), b.Get(x-1, y+1), b.Get(x-1, y), b.Get(x-1, y-1),
+	}
+	var count, transitions int
+	for i, v := range n {
+		if v {
+			count++
+		} else if n[(i+1)%8] {
+			transitions++
+		}
+	}
+	if count < 2 || count > 6 || transitions != 1 {
+		return false
+	}
+	if step == 0 {
+		return !(n[0] && n[2] && n[4]) && !(n[2] && n[4] && n[6])
+	}
+	return !(n[0] && n[2] && n[6]) && !(n[0] && n[4] && n[6])
+}
+
+// Polylines converts the true pixels of a skeleton, such
+// as the result of Thin(), into polylines through the
+// centers of the pixels.
+//
+// Pixels are connected to their horizontal, vertical, and
+// diagonal neighbors. Diagonal neighbors are only
+// connected when they are not already connected through a
+// common horizontal or vertical neighbor.
+//
+// Polylines end at endpoints and junctions of the
+// skeleton. Loops are returned with their first point
+// repeated at the end, and isolated pixels are returned as
+// polylines with a single point.
+func (b *Bitmap) Polylines() [][]Coord {
+	neighbors := func(idx int) []int {
+		x, y := idx%b.Width, idx/b.Width
+		var res []int
+		for _, d := range [8][2]int{
+			{1, 0}, {0, 1}, {-1, 0}, {0, -1}, {1, 1}, {-1, 1}, {-1, -1}, {1, -1},
+		} {
+			if !b.Get(x+d[0], y+d[1]) {
+				continue
+			}
+			if d[0] != 0 && d[1] != 0 && (b.Get(x+d[0], y) || b.Get(x, y+d[1])) {
+				continue
+			}
+			res = append(res, x+d[0]+(y+d[1])*b.Width)
+		}
+		return res
+	}
+	center := func(idx int) Coord {
+		return XY(float64(idx%b.Width)+0.5, float64(idx/b.Width)+0.5)
+	}
+
+	visitedEdges := map[[2]int]bool{}
+	markEdge := func(i1, i2 int) bool {
+		if i1 > i2 {
+			i1, i2 = i2, i1
+		}
+		if visitedEdges[[2]int{i1, i2}] {
+			return false
+		}
+		visitedEdges[[2]int{i1, i2}] = true
+		return true
+	}
+	trace := func(start, next int) []Coord {
+		points := []Coord{center(start), center(next)}
+		prev, cur := start, next
+		for {
+			ns := neighbors(cur)
+			if len(ns) != 2 {
+				return points
+			}
+			n := ns[0]
+			if n == prev {
+				n = ns[1]
+			}
+			if !markEdge(cur, n) {
+				return points
+			}
+			points = append(points, center(n))
+			prev, cur = cur, n
+		}
+	}
+
+	var res [][]Coord
+	// Paths starting at endpoints and junctions.
+	for idx, v := range b.Data {
+		if !v {
+			continue
+		}
+		ns := neighbors(idx)
+		if len(ns) == 0 {
+			res = append(res, []Coord{center(idx)})
+		} else if len(ns) != 2 {
+			for _, n := range ns {
+				if markEdge(idx, n) {
+					res = append(res, trace(idx, n))
+				}
+			}
+		}
+	}
+	// Loops without any endpoints or junctions.
+	for idx, v := range b.Data {
+		if !v {
+			continue
+		}
+		for _, n := range neighbors(idx) {
+			if markEdge(idx, n) {
+				res = append(res, trace(idx, n))
+			}
+		}
+	}
+	return res
+}
+
 // Mesh converts the bitmap to a mesh by creating boxes
 // around every true pixel and deleting duplicate
 // segments.
@@ -1,6 +1,7 @@
 package model2d
 
 import (
+	"math"
 	"math/rand"
 	"testing"
 )
@@ -93,6 +94,178 @@ func TestBitmapMesh(t *testing.T) {
 	})
 }
 
+func TestBitmapMorphology(t *testing.T) {
+	bmp := NewBitmap(30, 20)
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 25; x++ {
+			bmp.Set(x, y, true)
+		}
+	}
+	// Noise pixel and a one-pixel hole.
+	bmp.Set(1, 1, true)
+	bmp.Set(10, 10, false)
+
+	opened := bmp.Open(1)
+	if opened.Get(1, 1) {
+		t.Error("noise should be removed by opening")
+	}
+	if !opened.Get(15, 8) {
+		t.Error("interior should survive opening")
+	}
+
+	closed := bmp.Close(1)
+	if !closed.Get(10, 10) {
+		t.Error("hole should be filled by closing")
+	}
+	if closed.Get(3, 10) {
+		t.Error("exterior should remain empty after closing")
+	}
+
+	dilated := bmp.Dilate(2)
+	if !dilated.Get(3, 10) || dilated.Get(2, 10) || !dilated.Get(4, 4) || dilated.Get(3, 3) {
+		t.Error("unexpected dilation result")
+	}
+	eroded := bmp.Erode(2)
+	if !eroded.Get(7, 7) || eroded.Get(6, 7) || eroded.Get(10, 12) || eroded.Get(9, 10) {
+		t.Error("unexpected erosion result")
+	}
+}
+
+func TestBitmapFillHoles(t *testing.T) {
+	bmp := NewBitmap(20, 20)
+	for y := 2; y < 18; y++ {
+		for x := 2; x < 18; x++ {
+			bmp.Set(x, y, true)
+		}
+	}
+	// Small hole.
+	bmp.Set(4, 4, false)
+	// Large hole.
+	for y := 8; y < 12; y++ {
+		for x := 8; x < 12; x++ {
+			bmp.Set(x, y, false)
+		}
+	}
+
+	filled := bmp.FillHoles(0)
+	if !filled.Get(4, 4) || !filled.Get(10, 10) {
+		t.Error("all holes should be filled")
+	}
+	if filled.Get(0, 0) {
+		t.Error("exterior should not be filled")
+	}
+
+	filled = bmp.FillHoles(4)
+	if !filled.Get(4, 4) || filled.Get(10, 10) {
+		t.Error("only the small hole should be filled")
+	}
+}
+
+func TestBitmapThin(t *testing.T) {
+	// A thick horizontal bar joined to a thick ring.
+	bmp := NewBitmap(60, 40)
+	for y := 0; y < bmp.Height; y++ {
+		for x := 0; x < bmp.Width; x++ {
+			d := XY(float64(x)+0.5, float64(y)+0.5).Dist(XY(40, 20))
+			if (d > 8 && d < 14) || (x >= 5 && x < 30 && y >= 17 && y < 23) {
+				bmp.Set(x, y, true)
+			}
+		}
+	}
+	thin := bmp.Thin()
+
+	for y := 0; y < thin.Height-1; y++ {
+		for x := 0; x < thin.Width-1; x++ {
+			if thin.Get(x, y) && thin.Get(x+1, y) && thin.Get(x, y+1) && thin.Get(x+1, y+1) {
+				t.Fatalf("skeleton is not thin at (%d, %d)", x, y)
+			}
+		}
+	}
+	for i, v := range thin.Data {
+		if v && !bmp.Data[i] {
+			t.Fatal("skeleton should be a subset of the original")
+		}
+	}
+	var barCount int
+	for y := 17; y < 23; y++ {
+		if thin.Get(20, y) {
+			barCount++
+		}
+	}
+	if barCount != 1 {
+		t.Errorf("skeleton should cross the bar once, but got %d pixels", barCount)
+	}
+	if thin.Get(40, 20) {
+		t.Error("ring hole should remain empty")
+	}
+
+	lines := thin.Polylines()
+	var numPoints, numClosed int
+	for _, line := range lines {
+		for i := 1; i < len(line); i++ {
+			if d := line[i].Dist(line[i-1]); d > math.Sqrt2+1e-8 {
+				t.Fatalf("polyline has gap of %f", d)
+			}
+		}
+		if len(line) > 2 && line[0] == line[len(line)-1] {
+			numClosed++
+		}
+		numPoints += len(line)
+	}
+	if numPoints < 60 {
+		t.Errorf("too few polyline points: %d", numPoints)
+	}
+	if numClosed == 0 && len(lines) < 3 {
+		t.Errorf("expected the ring to be traced, got %d polylines", len(lines))
+	}
+}
+
+func TestBitmapPolylines(t *testing.T) {
+	bmp := NewBitmap(10, 10)
+	for x := 1; x < 6; x++ {
+		bmp.Set(x, 2, true)
+	}
+	bmp.Set(6, 3, true)
+	bmp.Set(8, 8, true)
+
+	lines := bmp.Polylines()
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 polylines but got %d", len(lines))
+	}
+	var path, point []Coord
+	for _, l := range lines {
+		if len(l) == 1 {
+			point = l
+		} else {
+			path = l
+		}
+	}
+	if point == nil || point[0] != XY(8.5, 8.5) {
+		t.Errorf("unexpected isolated point: %v", point)
+	}
+	if len(path) != 6 {
+		t.Fatalf("unexpected path length: %d", len(path))
+	}
+	ends := [2]Coord{path[0], path[len(path)-1]}
+	if ends != [2]Coord{XY(1.5, 2.5), XY(6.5, 3.5)} &&
+		ends != [2]Coord{XY(6.5, 3.5), XY(1.5, 2.5)} {
+		t.Errorf("unexpected endpoints: %v", ends)
+	}
+
+	// A square loop of pixels.
+	bmp = NewBitmap(5, 5)
+	for i := 1; i < 4; i++ {
+		bmp.Set(i, 1, true)
+		bmp.Set(i, 3, true)
+		bmp.Set(1, i, true)
+		bmp.Set(3, i, true)
+	}
+	lines = bmp.Polylines()
+	if len(lines) != 1 || len(lines[0]) != 9 || lines[0][0] != lines[0][8] {
+		t.Errorf("unexpected loop: %v", lines)
+	}
+}
+
 func testingBitmap() *Bitmap {
 	bmp := NewBitmap(200, 300)
 	for i := range bmp.Data {
@@ -102,3 +275,59 @@ func testingBitmap() *Bitmap {
 	}
 	return bmp
 }
+
+func TestBitmapDistanceTransform(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for _, density := range []float64{0, 0.01, 0.3} {
+		b := NewBitmap(37, 23)
+		for i := range b.Data {
+			b.Data[i] = rng.Float64() < density
+		}
+		actual := b.DistanceTransform()
+		for y := 0; y < b.Height; y++ {
+			for x := 0; x < b.Width; x++ {
+				expected := math.Inf(1)
+				for y1 := 0; y1 < b.Height; y1++ {
+					for x1 := 0; x1 < b.Width; x1++ {
+						if b.Get(x1, y1) {
+							expected = math.Min(expected, math.Hypot(float64(x-x1), float64(y-y1)))
+						}
+					}
+				}
+				a := actual[x+y*b.Width]
+				if a != expected && math.Abs(a-expected) > 1e-8 {
+					t.Fatalf("density %f at (%d, %d): expected %f but got %f",
+						density, x, y, expected, a)
+				}
+			}
+		}
+	}
+}
+
+func TestBitmapToSDF(t *testing.T) {
+	circle := &Circle{Center: XY(30, 25), Radius: 20}
+	b := NewBitmap(50, 60)
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			b.Set(x, y, circle.Contains(XY(float64(x)+0.5, float64(y)+0.5)))
+		}
+	}
+	sdf := BitmapToSDF(b)
+	solid := BitmapToSolid(b)
+	for i := 0; i < 1000; i++ {
+		c := NewCoordRandBounds(sdf.Min(), sdf.Max())
+		expected := circle.SDF(c)
+		actual := sdf.SDF(c)
+		if math.Abs(actual-expected) > 1.5 {
+			t.Fatalf("at %v: expected %f but got %f", c, expected, actual)
+		}
+		if math.Abs(actual) > 1 && (actual > 0) != solid.Contains(c) {
+			t.Fatalf("at %v: sign of %f does not match solid", c, actual)
+		}
+		outside := c.Sub(XY(25, 30)).Scale(3).Add(XY(25, 30))
+		if sdf.Min().Max(outside).Min(sdf.Max()) != outside &&
+			sdf.SDF(outside) >= 0 {
+			t.Fatalf("at %v: expected negative SDF", outside)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package model2d
+
+import "sort"
+
+// ClipMesh computes the intersection of a closed mesh with
+// a convex polytope.
+//
+// The mesh should be closed and have outward-facing
+// normals, and the result will as well. Loops which cross
+// the boundary of the polytope are closed off with new
+// segments along the boundary, so the result can still be
+// used as a solid.
+//
+// Concave and multi-part meshes are supported, in which
+// case one loop may be split into several.
+func ClipMesh(m *Mesh, p ConvexPolytope) *Mesh {
+	if len(p) == 0 {
+		return m.Copy()
+	}
+	for _, l := range p {
+		m = clipMeshHalfSpace(m, l)
+	}
+	return m
+}
+
+// ClipMeshRect is like ClipMesh, but clips the mesh to an
+// axis-aligned rectangle.
+//
+// This can be used to split a large design into tiles
+// which each fit on a separate sheet.
+func ClipMeshRect(m *Mesh, r *Rect) *Mesh {
+	return ClipMesh(m, NewConvexPolytopeRect(r.MinVal, r.MaxVal))
+}
+
+// ClipMeshPolygon is like ClipMesh, but clips the mesh to
+// a convex polygon given by its vertices.
+//
+// The vertices may be ordered clockwise or
+// counter-clockwise. If the polygon is not convex, the
+// result is clipped to the intersection of the half-planes
+// of its edges rather than to the polygon itself.
+func ClipMeshPolygon(m *Mesh, polygon []Coord) *Mesh {
+	if len(polygon) < 3 {
+		panic("polygon must have at least three vertices")
+	}
+	var center Coord
+	for _, c := range polygon {
+		center = center.Add(c)
+	}
+	center = center.Scale(1 / float64(len(polygon)))
+
+	var p ConvexPolytope
+	for i, c := range polygon {
+		next := polygon[(i+1)%len(polygon)]
+		if next == c {
+			continue
+		}
+		normal := (&Segment{c, next}).Normal()
+		if normal.Dot(center.Sub(c)) > 0 {
+			normal = normal.Scale(-1)
+		}
+		p = append(p, &LinearConstraint{Normal: normal, Max: normal.Dot(c)})
+	}
+	return ClipMesh(m, p)
+}
+
+type clipCrossing struct {
+	Point    Coord
+	Position float64
+	Exit     bool
+}
+
+// clipMeshHalfSpace removes the part of a closed mesh
+// outside of a half-space, and closes the resulting loops
+// along the boundary line.
+func clipMeshHalfSpace(m *Mesh, l *LinearConstraint) *Mesh {
+	// Direction along the boundary whose left-hand normal
+	// points out of the half-space, so that new segments
+	// in this direction face outward.
+	dir := XY(l.Normal.Y, -l.Normal.X)
+
+	res := NewMesh()
+	var crossings []clipCrossing
+	addSegment := func(p1, p2 Coord) {
+		if p1 != p2 {
+			res.Add(&Segment{p1, p2})
+		}
+	}
+	m.Iterate(func(s *Segment) {
+		d1 := s[0].Dot(l.Normal) - l.Max
+		d2 := s[1].Dot(l.Normal) - l.Max
+		inside1, inside2 := d1 <= 0, d2 <= 0
+		if inside1 && inside2 {
+			s1 := *s
+			res.Add(&s1)
+			return
+		} else if !inside1 && !inside2 {
+			return
+		}
+		frac := d1 / (d1 - d2)
+		mid := s[0].Add(s[1].Sub(s[0]).Scale(frac))
+		crossing := clipCrossing{Point: mid, Position: mid.Dot(dir), Exit: inside1}
+		crossings = append(crossings, crossing)
+		if inside1 {
+			addSegment(s[0], mid)
+		} else {
+			addSegment(mid, s[1])
+		}
+	})
+
+	sort.Slice(crossings, func(i, j int) bool {
+		c1, c2 := crossings[i], crossings[j]
+		if c1.Position == c2.Position {
+			return c1.Exit && !c2.Exit
+		}
+		return c1.Position < c2.Position
+	})
+	exitIdx := -1
+	for i, c := range crossings {
+		if c.Exit {
+			exitIdx = i
+		} else if exitIdx != -1 {
+			addSegment(crossings[exitIdx].Point, c.Point)
+			exitIdx = -1
+		}
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestClipMeshRect(t *testing.T) {
+	mesh := NewMeshRect(XY(0, 0), XY(4, 4))
+	clipped := ClipMeshRect(mesh, NewRect(XY(2, -1), XY(5, 2)))
+	MustValidateMesh(t, clipped, true)
+	if a := clipped.Area(); math.Abs(a-4) > 1e-8 {
+		t.Errorf("expected area 4 but got %f", a)
+	}
+
+	// A U shape whose arms are separated by the clip.
+	u := PolylineMesh([]Coord{
+		XY(0, 0), XY(0, 3), XY(1, 3), XY(1, 1), XY(2, 1), XY(2, 3), XY(3, 3), XY(3, 0),
+	}, true)
+	if u.SignedArea() < 0 {
+		u = u.InvertNormals()
+	}
+	clipped = ClipMeshRect(u, NewRect(XY(-1, 2), XY(4, 4)))
+	MustValidateMesh(t, clipped, true)
+	if n := len(clipped.Loops()); n != 2 {
+		t.Errorf("expected 2 loops but got %d", n)
+	}
+	if a := clipped.Area(); math.Abs(a-2) > 1e-8 {
+		t.Errorf("expected area 2 but got %f", a)
+	}
+
+	// Clipping to a rect containing the mesh is a no-op.
+	clipped = ClipMeshRect(u, NewRect(XY(-1, -1), XY(4, 4)))
+	if !meshesEqual(clipped, u) {
+		t.Error("mesh should not be changed")
+	}
+	clipped.Iterate(func(s *Segment) {
+		if u.Contains(s) {
+			t.Fatal("segments should not be shared with the original mesh")
+		}
+	})
+
+	// Clipping to a disjoint rect yields nothing.
+	clipped = ClipMeshRect(u, NewRect(XY(5, 5), XY(6, 6)))
+	if clipped.NumSegments() != 0 {
+		t.Error("mesh should be empty")
+	}
+}
+
+func TestClipMeshPolygon(t *testing.T) {
+	mesh := MarchingSquaresSearch(&Circle{Radius: 1}, 0.01, 8)
+	triangle := []Coord{XY(-0.5, -0.5), XY(2, -0.5), XY(-0.5, 2)}
+	expected := IntersectedSolid{
+		&Circle{Radius: 1},
+		NewColliderSolid(MeshToCollider(PolylineMesh(triangle, true))),
+	}
+	for _, reverse := range []bool{false, true} {
+		poly := append([]Coord{}, triangle...)
+		if reverse {
+			poly[1], poly[2] = poly[2], poly[1]
+		}
+		clipped := ClipMeshPolygon(mesh, poly)
+		MustValidateMesh(t, clipped, true)
+
+		actual := MeshToSDF(clipped)
+		for i := 0; i < 1000; i++ {
+			c := NewCoordRandBounds(XY(-1.5, -1.5), XY(1.5, 1.5))
+			if math.Abs(actual.SDF(c)) < 0.02 {
+				continue
+			}
+			if (actual.SDF(c) > 0) != expected.Contains(c) {
+				t.Errorf("unexpected containment at %v (reverse=%v)", c, reverse)
+				break
+			}
+		}
+	}
+}
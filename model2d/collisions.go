@@ -59,12 +59,20 @@ type Collider interface {
 // the point is less than -margin away from the surface.
 func ColliderContains(c Collider, coord Coord, margin float64) bool {
 	r := &Ray{
-		Origin: coord,
-		// Random direction; any direction should work, but we
-		// want to avoid edge cases and rounding errors.
-		Direction: Coord{0.5224892708603626, 0.10494477243214506},
+		Origin:    coord,
+		Direction: colliderContainsDirection,
 	}
-	collisions := c.RayCollisions(r, nil)
+	return colliderContainsCount(c, coord, margin, c.RayCollisions(r, nil))
+}
+
+// colliderContainsDirection is the ray direction used to
+// count collisions for ColliderContains.
+//
+// This is a random direction; any direction should work,
+// but we want to avoid edge cases and rounding errors.
+var colliderContainsDirection = Coord{0.5224892708603626, 0.10494477243214506}
+
+func colliderContainsCount(c Collider, coord Coord, margin float64, collisions int) bool {
 	if collisions%2 == 0 {
 		if margin < 0 {
 			return c.CircleCollision(coord, -margin)
@@ -176,6 +184,31 @@ func (j *JoinedCollider) Max() Coord {
 	return j.max
 }
 
+// Refit recomputes the bounding box of the collider, and
+// of any nested JoinedColliders, from the current bounds
+// of the underlying colliders.
+//
+// This can be used to update a collider after the shapes
+// it contains have been modified in place, for example
+// when animating a deforming mesh, without rebuilding
+// the entire hierarchy.
+func (j *JoinedCollider) Refit() {
+	for i, c := range j.colliders {
+		switch c := c.(type) {
+		case *JoinedCollider:
+			c.Refit()
+		case joinedMultiCollider:
+			c.Refit()
+		}
+		if i == 0 {
+			j.min, j.max = c.Min(), c.Max()
+		} else {
+			j.min = j.min.Min(c.Min())
+			j.max = j.max.Max(c.Max())
+		}
+	}
+}
+
 func (j *JoinedCollider) RayCollisions(r *Ray, f func(RayCollision)) int {
 	if !j.rayCollidesWithBounds(r) {
 		return 0
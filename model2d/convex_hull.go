@@ -0,0 +1,254 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+// ConvexHull computes the convex hull of a set of points.
+//
+// The resulting points are the vertices of the hull in
+// clockwise order (assuming the y-axis points upward),
+// without repeating the first point at the end.
+// Points along the edges of the hull are not included.
+func ConvexHull(points []Coord) []Coord {
+	hull := convexHullCCW(points)
+	for i := 0; i < len(hull)/2; i++ {
+		hull[i], hull[len(hull)-1-i] = hull[len(hull)-1-i], hull[i]
+	}
+	return hull
+}
+
+// ConvexHull computes the convex hull of the vertices of
+// the mesh, as a closed mesh with outward-facing normals.
+//
+// If the mesh has fewer than three non-colinear vertices,
+// the result is empty.
+func (m *Mesh) ConvexHull() *Mesh {
+	hull := ConvexHull(m.VertexSlice())
+	if len(hull) < 3 {
+		return NewMesh()
+	}
+	return PolylineMesh(hull, true)
+}
+
+// An OrientedRect is a rectangle which may be rotated
+// about its center.
+type OrientedRect struct {
+	Center Coord
+
+	// Axis is a unit vector along the first side of the
+	// rectangle. The second side is perpendicular.
+	Axis Coord
+
+	// Size stores the lengths of the first and second
+	// sides of the rectangle.
+	Size Coord
+}
+
+// Angle gets the angle of the rectangle's Axis relative to
+// the x-axis.
+//
+// Rotating the rectangle by -Angle() about its center
+// makes it axis-aligned.
+func (o *OrientedRect) Angle() float64 {
+	return math.Atan2(o.Axis.Y, o.Axis.X)
+}
+
+// Area computes the area of the rectangle.
+func (o *OrientedRect) Area() float64 {
+	return o.Size.X * o.Size.Y
+}
+
+// Corners gets the corners of the rectangle in clockwise
+// order.
+func (o *OrientedRect) Corners() [4]Coord {
+	axis1 := o.Axis.Scale(o.Size.X / 2)
+	axis2 := XY(-o.Axis.Y, o.Axis.X).Scale(o.Size.Y / 2)
+	return [4]Coord{
+		o.Center.Sub(axis1).Sub(axis2),
+		o.Center.Sub(axis1).Add(axis2),
+		o.Center.Add(axis1).Add(axis2),
+		o.Center.Add(axis1).Sub(axis2),
+	}
+}
+
+// Mesh creates a closed mesh of the rectangle with
+// outward-facing normals.
+func (o *OrientedRect) Mesh() *Mesh {
+	corners := o.Corners()
+	return PolylineMesh(corners[:], true)
+}
+
+// MinAreaRect finds the smallest-area rectangle, in any
+// orientation, which contains all of the points.
+//
+// This uses rotating calipers on the convex hull of the
+// points, taking linear time in the size of the hull.
+//
+// This can be used to automatically orient parts so that
+// they use as little stock material as possible.
+func MinAreaRect(points []Coord) *OrientedRect {
+	hull := convexHullCCW(points)
+	if len(hull) == 0 {
+		panic("cannot bound empty set of points")
+	} else if len(hull) == 1 {
+		return &OrientedRect{Center: hull[0], Axis: X(1)}
+	} else if len(hull) == 2 {
+		axis := hull[1].Sub(hull[0])
+		return &OrientedRect{
+			Center: hull[0].Mid(hull[1]),
+			Axis:   axis.Normalize(),
+			Size:   XY(axis.Norm(), 0),
+		}
+	}
+
+	n := len(hull)
+	var best *OrientedRect
+	bestArea := math.Inf(1)
+
+	// Indices of the extreme points in the edge direction,
+	// the perpendicular direction, and the opposite of the
+	// edge direction, respectively.
+	right, top, left := 1, 1, 1
+	for i := 0; i < n; i++ {
+		p := hull[i]
+		dir := hull[(i+1)%n].Sub(p).Normalize()
+		perp := XY(-dir.Y, dir.X)
+
+		for dir.Dot(hull[(right+1)%n].Sub(p)) >= dir.Dot(hull[right].Sub(p)) {
+			right = (right + 1) % n
+			if right == i {
+				break
+			}
+		}
+		if i == 0 {
+			top = right
+		}
+		for perp.Dot(hull[(top+1)%n].Sub(p)) >= perp.Dot(hull[top].Sub(p)) {
+			top = (top + 1) % n
+			if top == i {
+				break
+			}
+		}
+		if i == 0 {
+			left = top
+		}
+		for dir.Dot(hull[(left+1)%n].Sub(p)) <= dir.Dot(hull[left].Sub(p)) {
+			left = (left + 1) % n
+			if left == i {
+				break
+			}
+		}
+
+		minX := dir.Dot(hull[left].Sub(p))
+		maxX := dir.Dot(hull[right].Sub(p))
+		maxY := perp.Dot(hull[top].Sub(p))
+		area := (maxX - minX) * maxY
+		if area < bestArea {
+			bestArea = area
+			best = &OrientedRect{
+				Center: p.Add(dir.Scale((minX + maxX) / 2)).Add(perp.Scale(maxY / 2)),
+				Axis:   dir,
+				Size:   XY(maxX-minX, maxY),
+			}
+		}
+	}
+	return best
+}
+
+// Diameter finds the two points in a set which are the
+// furthest apart, using rotating calipers on the convex
+// hull of the points.
+//
+// The two points are returned along with the distance
+// between them.
+func Diameter(points []Coord) (p1, p2 Coord, dist float64) {
+	hull := convexHullCCW(points)
+	if len(hull) == 0 {
+		panic("cannot compute diameter of empty set of points")
+	} else if len(hull) == 1 {
+		return hull[0], hull[0], 0
+	}
+
+	n := len(hull)
+	j := 1
+	for i := 0; i < n; i++ {
+		edgeStart := hull[i]
+		edge := hull[(i+1)%n].Sub(edgeStart)
+
+		// Advance j while it gets further from the current
+		// edge, to find the antipodal point.
+		for {
+			next := (j + 1) % n
+			if edgeCross(edge, hull[next].Sub(edgeStart)) <= edgeCross(edge, hull[j].Sub(edgeStart)) {
+				break
+			}
+			j = next
+		}
+		for _, candidate := range []Coord{edgeStart, hull[(i+1)%n]} {
+			if d := candidate.Dist(hull[j]); d > dist {
+				p1, p2, dist = candidate, hull[j], d
+			}
+		}
+	}
+	return
+}
+
+// convexHullCCW computes the convex hull using Andrew's
+// monotone chain algorithm, returning the points in
+// counter-clockwise order.
+func convexHullCCW(points []Coord) []Coord {
+	sorted := append([]Coord{}, points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X == sorted[j].X {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+
+	// Remove duplicates.
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	hull := make([]Coord, 0, 2*len(unique))
+	for _, p := range unique {
+		for len(hull) >= 2 && convexHullTurn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lowerSize := len(hull)
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) > lowerSize && convexHullTurn(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	hull = hull[:len(hull)-1]
+	if len(hull) == 2 && hull[0] == hull[1] {
+		hull = hull[:1]
+	}
+	return hull
+}
+
+// convexHullTurn is positive if p1, p2, p3 make a
+// counter-clockwise turn, negative if they make a
+// clockwise turn, and exactly zero if they are colinear.
+func convexHullTurn(p1, p2, p3 Coord) float64 {
+	return numerical.Orient2D(p1.Array(), p2.Array(), p3.Array())
+}
+
+func edgeCross(v1, v2 Coord) float64 {
+	return v1.X*v2.Y - v1.Y*v2.X
+}
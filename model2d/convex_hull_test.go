@@ -0,0 +1,98 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestConvexHull(t *testing.T) {
+	for trial := 0; trial < 20; trial++ {
+		points := make([]Coord, 3+rand.Intn(100))
+		for i := range points {
+			points[i] = NewCoordRandNorm()
+		}
+		hull := ConvexHull(points)
+		if !isPolygonClockwise(hull) {
+			t.Fatal("hull should be clockwise")
+		}
+		mesh := PolylineMesh(hull, true)
+		MustValidateMesh(t, mesh, true)
+		solid := NewColliderSolid(MeshToCollider(mesh))
+		sdf := MeshToSDF(mesh)
+		for _, p := range points {
+			if !solid.Contains(p) && sdf.SDF(p) < -1e-8 {
+				t.Fatalf("point %v is outside of hull", p)
+			}
+		}
+		for i, p := range hull {
+			next := hull[(i+1)%len(hull)]
+			prev := hull[(i+len(hull)-1)%len(hull)]
+			if convexHullTurn(prev, p, next) >= 0 {
+				t.Fatal("hull is not strictly convex")
+			}
+		}
+	}
+
+	// Colinear and duplicate points.
+	hull := ConvexHull([]Coord{XY(0, 0), XY(1, 1), XY(2, 2), XY(1, 1), XY(0, 0)})
+	if len(hull) != 2 {
+		t.Errorf("unexpected hull: %v", hull)
+	}
+}
+
+func TestMinAreaRect(t *testing.T) {
+	for trial := 0; trial < 20; trial++ {
+		points := make([]Coord, 3+rand.Intn(100))
+		for i := range points {
+			points[i] = NewCoordRandNorm().Mul(XY(3, 1))
+		}
+		rect := MinAreaRect(points)
+
+		// Brute force search over every hull edge.
+		hull := ConvexHull(points)
+		expected := math.Inf(1)
+		for i, p := range hull {
+			dir := hull[(i+1)%len(hull)].Sub(p).Normalize()
+			perp := XY(-dir.Y, dir.X)
+			minX, maxX := math.Inf(1), math.Inf(-1)
+			minY, maxY := math.Inf(1), math.Inf(-1)
+			for _, p1 := range hull {
+				minX = math.Min(minX, p1.Dot(dir))
+				maxX = math.Max(maxX, p1.Dot(dir))
+				minY = math.Min(minY, p1.Dot(perp))
+				maxY = math.Max(maxY, p1.Dot(perp))
+			}
+			expected = math.Min(expected, (maxX-minX)*(maxY-minY))
+		}
+		if math.Abs(rect.Area()-expected) > 1e-8 {
+			t.Fatalf("expected area %f but got %f", expected, rect.Area())
+		}
+
+		sdf := MeshToSDF(rect.Mesh())
+		for _, p := range points {
+			if sdf.SDF(p) < -1e-8 {
+				t.Fatalf("point %v is outside of rect", p)
+			}
+		}
+	}
+}
+
+func TestDiameter(t *testing.T) {
+	for trial := 0; trial < 20; trial++ {
+		points := make([]Coord, 2+rand.Intn(100))
+		for i := range points {
+			points[i] = NewCoordRandNorm()
+		}
+		var expected float64
+		for i, p1 := range points {
+			for _, p2 := range points[:i] {
+				expected = math.Max(expected, p1.Dist(p2))
+			}
+		}
+		p1, p2, actual := Diameter(points)
+		if math.Abs(actual-expected) > 1e-8 || math.Abs(p1.Dist(p2)-actual) > 1e-8 {
+			t.Fatalf("expected diameter %f but got %f", expected, actual)
+		}
+	}
+}
@@ -180,6 +180,45 @@ func (c *CoordTree) sphereCollision(p Coord, rSquared float64) bool {
 	}
 }
 
+// WithinRadius gets all of the points in the tree within
+// a distance r of p, in no particular order.
+func (c *CoordTree) WithinRadius(p Coord, r float64) []Coord {
+	var res []Coord
+	c.withinRadius(p, r*r, func(c Coord) {
+		res = append(res, c)
+	})
+	return res
+}
+
+// CountWithinRadius counts the points in the tree within a
+// distance r of p.
+//
+// This is equivalent to len(c.WithinRadius(p, r)), but
+// does not allocate memory.
+func (c *CoordTree) CountWithinRadius(p Coord, r float64) int {
+	var count int
+	c.withinRadius(p, r*r, func(c Coord) {
+		count++
+	})
+	return count
+}
+
+func (c *CoordTree) withinRadius(p Coord, rSquared float64, f func(c Coord)) {
+	if c == nil {
+		return
+	}
+	if p.SquaredDist(c.Coord) <= rSquared {
+		f(c.Coord)
+	}
+	planeDist := c.Coord.Array()[c.SplitAxis] - p.Array()[c.SplitAxis]
+	if planeDist > 0 || planeDist*planeDist <= rSquared {
+		c.LessThan.withinRadius(p, rSquared, f)
+	}
+	if planeDist <= 0 || planeDist*planeDist <= rSquared {
+		c.GreaterEqual.withinRadius(p, rSquared, f)
+	}
+}
+
 // Slice combines the points back into a slice.
 //
 // The order will be from the first (less than) leaf to
@@ -78,6 +78,73 @@ func CurveMesh(c Curve, n int) *Mesh {
 	return m
 }
 
+// CurveFlatten approximates a curve as a polyline by
+// adaptively subdividing the range of t until the curve is
+// within tol of each segment.
+//
+// Flatness is measured at a few points inside each
+// segment, so features which are much smaller than a
+// segment may be missed. To avoid this, the curve is
+// always subdivided at least minSplits times.
+//
+// If maxSplits is specified, it determines the maximum
+// depth of sub-divisions to perform. Otherwise,
+// DefaultBezierMaxSplits is used.
+func CurveFlatten(c Curve, tol float64, minSplits, maxSplits int) []Coord {
+	if maxSplits == 0 {
+		maxSplits = DefaultBezierMaxSplits
+	}
+	if minSplits > maxSplits {
+		minSplits = maxSplits
+	}
+	start := c.Eval(0)
+	res := []Coord{start}
+	curveFlatten(c, tol, 0, 1, start, c.Eval(1), minSplits, maxSplits, &res)
+	return res
+}
+
+func curveFlatten(c Curve, tol, t1, t2 float64, p1, p2 Coord, minSplits, maxSplits int,
+	res *[]Coord) {
+	if maxSplits == 0 {
+		*res = append(*res, p2)
+		return
+	}
+	mid := c.Eval((t1 + t2) / 2)
+	if minSplits <= 0 {
+		seg := Segment{p1, p2}
+		flat := seg.Dist(mid) <= tol
+		for _, frac := range []float64{0.25, 0.75} {
+			if !flat {
+				break
+			}
+			flat = seg.Dist(c.Eval(t1+(t2-t1)*frac)) <= tol
+		}
+		if flat {
+			*res = append(*res, p2)
+			return
+		}
+	}
+	tMid := (t1 + t2) / 2
+	curveFlatten(c, tol, t1, tMid, p1, mid, minSplits-1, maxSplits-1, res)
+	curveFlatten(c, tol, tMid, t2, mid, p2, minSplits-1, maxSplits-1, res)
+}
+
+// PolylineMesh creates a mesh connecting each point to
+// the next. If closed is true, the last point is also
+// connected back to the first.
+func PolylineMesh(points []Coord, closed bool) *Mesh {
+	res := NewMesh()
+	for i := 1; i < len(points); i++ {
+		if points[i-1] != points[i] {
+			res.Add(&Segment{points[i-1], points[i]})
+		}
+	}
+	if closed && len(points) > 2 && points[0] != points[len(points)-1] {
+		res.Add(&Segment{points[len(points)-1], points[0]})
+	}
+	return res
+}
+
 // A JoinedCurve combines Curves into a single curve.
 // Each curve should end where the next curve begins.
 type JoinedCurve []Curve
@@ -244,6 +311,128 @@ func (b BezierCurve) Split(t float64) (BezierCurve, BezierCurve) {
 	return c1, c2
 }
 
+// Derivative computes the derivative of the curve with
+// respect to t, which is itself a Bezier curve of one
+// lower degree (also known as the hodograph).
+func (b BezierCurve) Derivative() BezierCurve {
+	if len(b) < 2 {
+		panic("need at least two points")
+	}
+	n := float64(len(b) - 1)
+	res := make(BezierCurve, len(b)-1)
+	for i := range res {
+		res[i] = b[i+1].Sub(b[i]).Scale(n)
+	}
+	return res
+}
+
+// Tangent computes the derivative of the curve at time t.
+func (b BezierCurve) Tangent(t float64) Coord {
+	if len(b) == 2 {
+		return b[1].Sub(b[0])
+	}
+	return b.Derivative().Eval(t)
+}
+
+// Closest finds the point on the curve which is nearest
+// to c, returning the t value and the point itself.
+//
+// The search starts from a dense sampling of the curve and
+// refines the best sample using Newton's method.
+func (b BezierCurve) Closest(c Coord) (float64, Coord) {
+	if len(b) == 2 {
+		seg := Segment{b[0], b[1]}
+		p := seg.Closest(c)
+		length := b[1].Sub(b[0]).Norm()
+		if length == 0 {
+			return 0, p
+		}
+		return p.Dist(b[0]) / length, p
+	}
+
+	numSamples := 8 * len(b)
+	bestT := 0.0
+	bestDist := math.Inf(1)
+	for i := 0; i <= numSamples; i++ {
+		t := float64(i) / float64(numSamples)
+		if d := b.Eval(t).SquaredDist(c); d < bestDist {
+			bestT, bestDist = t, d
+		}
+	}
+
+	d1 := b.Derivative()
+	d2 := d1.Derivative()
+	t := bestT
+	for i := 0; i < 16; i++ {
+		offset := b.Eval(t).Sub(c)
+		deriv := d1.Eval(t)
+		var secondDeriv Coord
+		if len(d2) >= 2 {
+			secondDeriv = d2.Eval(t)
+		} else {
+			secondDeriv = d2[0]
+		}
+		num := offset.Dot(deriv)
+		denom := deriv.Dot(deriv) + offset.Dot(secondDeriv)
+		if denom <= 0 {
+			break
+		}
+		t = math.Max(0, math.Min(1, t-num/denom))
+	}
+	p := b.Eval(t)
+	if p.SquaredDist(c) > bestDist {
+		t = bestT
+		p = b.Eval(t)
+	}
+	return t, p
+}
+
+// Flatten approximates the curve as a polyline whose
+// points are within tol of the true curve.
+//
+// The curve is adaptively subdivided, so that flatter
+// regions use fewer points than sharply curved ones.
+// The first and last points of the result are the end
+// points of the curve.
+//
+// If maxSplits is specified, it determines the maximum
+// depth of sub-divisions to perform. Otherwise,
+// DefaultBezierMaxSplits is used.
+func (b BezierCurve) Flatten(tol float64, maxSplits int) []Coord {
+	if maxSplits == 0 {
+		maxSplits = DefaultBezierMaxSplits
+	}
+	res := []Coord{b[0]}
+	b.flatten(tol, maxSplits, &res)
+	return res
+}
+
+func (b BezierCurve) flatten(tol float64, maxSplits int, res *[]Coord) {
+	chord := Segment{b[0], b[len(b)-1]}
+	flat := true
+	for _, c := range b[1 : len(b)-1] {
+		// The curve lies within the convex hull of its control
+		// points, so this bounds the flattening error.
+		if chord.Dist(c) > tol {
+			flat = false
+			break
+		}
+	}
+	if flat || maxSplits == 0 {
+		*res = append(*res, b[len(b)-1])
+		return
+	}
+	b1, b2 := b.Split(0.5)
+	b1.flatten(tol, maxSplits-1, res)
+	b2.flatten(tol, maxSplits-1, res)
+}
+
+// Mesh creates a mesh approximating the curve within the
+// given tolerance, using Flatten.
+func (b BezierCurve) Mesh(tol float64) *Mesh {
+	return PolylineMesh(b.Flatten(tol, 0), false)
+}
+
 // Polynomials converts the X and Y coordinates of the
 // curve into polynomials of t.
 func (b BezierCurve) Polynomials() [2]numerical.Polynomial {
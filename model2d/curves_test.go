@@ -231,6 +231,73 @@ func TestBezierLength(t *testing.T) {
 	})
 }
 
+func TestBezierDerivative(t *testing.T) {
+	for size := 2; size < 6; size++ {
+		c := make(BezierCurve, size)
+		for i := range c {
+			c[i] = NewCoordRandNorm()
+		}
+		for i := 0; i < 10; i++ {
+			x := rand.Float64()
+			const eps = 1e-5
+			expected := c.Eval(x + eps).Sub(c.Eval(x - eps)).Scale(1 / (2 * eps))
+			actual := c.Tangent(x)
+			if actual.Dist(expected) > 1e-5 {
+				t.Errorf("size %d: expected %v but got %v", size, expected, actual)
+			}
+		}
+	}
+}
+
+func TestBezierClosest(t *testing.T) {
+	for size := 2; size < 5; size++ {
+		c := make(BezierCurve, size)
+		for i := range c {
+			c[i] = NewCoordRandNorm()
+		}
+		for i := 0; i < 10; i++ {
+			p := NewCoordRandNorm()
+			expected := math.Inf(1)
+			for j := 0; j <= 10000; j++ {
+				expected = math.Min(expected, c.Eval(float64(j)/10000).Dist(p))
+			}
+			tVal, actual := c.Closest(p)
+			if actual.Dist(c.Eval(tVal)) > 1e-8 {
+				t.Errorf("size %d: point does not match t value", size)
+			}
+			if d := actual.Dist(p); d > expected+1e-8 || d < expected-1e-3 {
+				t.Errorf("size %d: expected distance %f but got %f", size, expected, d)
+			}
+		}
+	}
+}
+
+func TestBezierFlatten(t *testing.T) {
+	for size := 3; size < 6; size++ {
+		c := make(BezierCurve, size)
+		for i := range c {
+			c[i] = NewCoordRandNorm()
+		}
+		for _, tol := range []float64{1e-1, 1e-2, 1e-3} {
+			points := c.Flatten(tol, 0)
+			if points[0] != c[0] || points[len(points)-1] != c[len(c)-1] {
+				t.Fatal("endpoints not preserved")
+			}
+			mesh := c.Mesh(tol)
+			if mesh.NumSegments() != len(points)-1 {
+				t.Errorf("unexpected segment count %d", mesh.NumSegments())
+			}
+			sdf := MeshToSDF(mesh)
+			for i := 0; i <= 1000; i++ {
+				p := c.Eval(float64(i) / 1000)
+				if d := math.Abs(sdf.SDF(p)); d > tol+1e-8 {
+					t.Errorf("size %d tol %f: point %v has distance %f", size, tol, p, d)
+				}
+			}
+		}
+	}
+}
+
 func TestSmoothBezier(t *testing.T) {
 	actualCurve := SmoothBezier(
 		Coord{}, Coord{X: 1, Y: 1}, Coord{X: 2, Y: 1}, Coord{X: 2},
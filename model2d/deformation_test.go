@@ -0,0 +1,73 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestARAPRigid(t *testing.T) {
+	mesh := NewMeshRect(XY(0, 0), XY(4, 1))
+	arap := NewARAP(mesh)
+
+	transform := JoinedTransform{Rotation(0.3), &Translate{Offset: XY(1, 2)}}
+	constraints := ARAPConstraints{}
+	for _, c := range []Coord{XY(0, 0), XY(0, 1)} {
+		constraints[c] = transform.Apply(c)
+	}
+	constraints[XY(4, 0)] = transform.Apply(XY(4, 0))
+
+	deformed := arap.Deform(constraints)
+	MustValidateMesh(t, deformed, true)
+	expected := mesh.Transform(transform)
+	expected.Iterate(func(s *Segment) {
+		found := false
+		deformed.Iterate(func(s1 *Segment) {
+			if s1[0].Dist(s[0]) < 1e-5 && s1[1].Dist(s[1]) < 1e-5 {
+				found = true
+			}
+		})
+		if !found {
+			t.Errorf("missing segment %v", s)
+		}
+	})
+}
+
+func TestARAPBend(t *testing.T) {
+	// A bar with interior vertices.
+	var tris [][3]Coord
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 2; y++ {
+			p := XY(float64(x), float64(y))
+			tris = append(tris,
+				clockwiseTriangle(p, p.Add(X(1)), p.Add(Y(1))),
+				clockwiseTriangle(p.Add(X(1)), p.Add(XY(1, 1)), p.Add(Y(1))),
+			)
+		}
+	}
+	arap := NewARAPTriangles(tris)
+
+	constraints := ARAPConstraints{}
+	for y := 0; y <= 2; y++ {
+		c := XY(0, float64(y))
+		constraints[c] = c
+		c = XY(20, float64(y))
+		constraints[c] = XY(14+float64(y), 8)
+	}
+	deformed := arap.Deform(constraints)
+	MustValidateMesh(t, deformed, true)
+
+	area := deformed.Area()
+	if math.Abs(area-40) > 4 {
+		t.Errorf("area should be roughly preserved, but got %f", area)
+	}
+
+	// Segments in the middle of the bar should stay
+	// roughly the same length.
+	mapping := arap.DeformMap(constraints, nil)
+	for x := 8; x < 12; x++ {
+		p1, p2 := XY(float64(x), 0), XY(float64(x+1), 0)
+		if d := mapping[p1].Dist(mapping[p2]); math.Abs(d-1) > 0.2 {
+			t.Errorf("edge length changed from 1 to %f", d)
+		}
+	}
+}
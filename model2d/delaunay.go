@@ -0,0 +1,459 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+// TriangulateMeshDelaunay is like TriangulateMesh, but
+// produces a constrained Delaunay triangulation.
+//
+// Every segment of m appears as an edge in the result,
+// and, subject to this constraint, triangles maximize
+// their minimum angles. This avoids the long, thin
+// triangles that TriangulateMesh may produce, which is
+// useful when the triangles are used to cap extrusions or
+// cross sections of 3D models.
+//
+// The mesh has the same requirements as for
+// TriangulateMesh, and the resulting triangles are also
+// ordered clockwise.
+func TriangulateMeshDelaunay(m *Mesh) [][3]Coord {
+	tris := TriangulateMesh(m)
+	constraints := map[[2]Coord]bool{}
+	m.Iterate(func(s *Segment) {
+		constraints[canonicalDelaunayEdge(s[0], s[1])] = true
+	})
+	delaunayFlip(tris, func(edge [2]Coord) bool {
+		return constraints[edge]
+	})
+	return tris
+}
+
+// Delaunay computes the Delaunay triangulation of a set of
+// points.
+//
+// The resulting triangles are ordered clockwise, and
+// cover the convex hull of the points. Duplicate points
+// are ignored, and the result is empty if all of the
+// points are colinear.
+func Delaunay(points []Coord) [][3]Coord {
+	tris := sweepTriangulate(points)
+	delaunayFlip(tris, func(edge [2]Coord) bool {
+		return false
+	})
+	return tris
+}
+
+// sweepTriangulate creates an arbitrary triangulation of
+// the convex hull of a set of points by adding the points
+// in order of x coordinate and connecting each one to the
+// edges of the hull which it can see.
+func sweepTriangulate(points []Coord) [][3]Coord {
+	sorted := append([]Coord{}, points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X == sorted[j].X {
+			return sorted[i].Y < sorted[j].Y
+		}
+		return sorted[i].X < sorted[j].X
+	})
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+
+	// Find the first point which is not colinear with the
+	// points before it.
+	firstIdx := -1
+	for i := 2; i < len(unique); i++ {
+		if convexHullTurn(unique[0], unique[1], unique[i]) != 0 {
+			firstIdx = i
+			break
+		}
+	}
+	if firstIdx == -1 {
+		return nil
+	}
+
+	var tris [][3]Coord
+	first := unique[firstIdx]
+	for i := 0; i+1 < firstIdx; i++ {
+		tris = append(tris, clockwiseTriangle(unique[i], unique[i+1], first))
+	}
+
+	// The hull is stored in counter-clockwise order.
+	hull := make([]Coord, 0, firstIdx+1)
+	if convexHullTurn(unique[0], unique[1], first) > 0 {
+		hull = append(hull, unique[:firstIdx]...)
+		hull = append(hull, first)
+	} else {
+		hull = append(hull, unique[0], first)
+		for i := firstIdx - 1; i > 0; i-- {
+			hull = append(hull, unique[i])
+		}
+	}
+
+	for _, p := range unique[firstIdx+1:] {
+		n := len(hull)
+		visible := func(i int) bool {
+			i = ((i % n) + n) % n
+			return convexHullTurn(hull[i], hull[(i+1)%n], p) < 0
+		}
+		start := -1
+		for i := 0; i < n; i++ {
+			if visible(i) {
+				start = i
+				break
+			}
+		}
+		if start == -1 {
+			panic("point is not outside of the hull")
+		}
+		for visible(start - 1) {
+			start--
+		}
+		end := start
+		for visible(end + 1) {
+			end++
+		}
+		for i := start; i <= end; i++ {
+			e1, e2 := hull[((i%n)+n)%n], hull[(((i+1)%n)+n)%n]
+			tris = append(tris, clockwiseTriangle(e1, e2, p))
+		}
+
+		// Replace the vertices between the visible edges
+		// with the new point.
+		newHull := make([]Coord, 0, n+1)
+		for i := end + 1; i <= start+n; i++ {
+			newHull = append(newHull, hull[((i%n)+n)%n])
+		}
+		newHull = append(newHull, p)
+		hull = newHull
+	}
+	return tris
+}
+
+// ConstrainedDelaunay computes a constrained Delaunay
+// triangulation of a set of points, in which every segment
+// appears as an edge.
+//
+// Segment endpoints are added to the points if necessary,
+// and segments which pass exactly through other points are
+// split at those points. Segments should not cross each
+// other, and segments which cannot be inserted because of
+// crossings are skipped.
+//
+// As with Delaunay, the resulting triangles are ordered
+// clockwise and cover the convex hull of the points.
+func ConstrainedDelaunay(points []Coord, segments [][2]Coord) [][3]Coord {
+	allPoints := append([]Coord{}, points...)
+	for _, s := range segments {
+		allPoints = append(allPoints, s[0], s[1])
+	}
+	tris := sweepTriangulate(allPoints)
+	if len(tris) == 0 {
+		return nil
+	}
+	d := newDelaunayTriangulation(tris)
+
+	var vertices []Coord
+	for v := range d.vertexSet() {
+		vertices = append(vertices, v)
+	}
+	constraints := map[[2]Coord]bool{}
+	for _, s := range segments {
+		for _, piece := range splitDelaunaySegment(vertices, s[0], s[1]) {
+			if d.insertConstraint(piece[0], piece[1]) {
+				constraints[canonicalDelaunayEdge(piece[0], piece[1])] = true
+			}
+		}
+	}
+	d.flip(func(edge [2]Coord) bool {
+		return constraints[edge]
+	})
+	return d.tris
+}
+
+// splitDelaunaySegment splits a segment at every vertex
+// which lies exactly in its interior.
+func splitDelaunaySegment(vertices []Coord, a, b Coord) [][2]Coord {
+	if a == b {
+		return nil
+	}
+	dir := b.Sub(a)
+	type splitPoint struct {
+		Coord Coord
+		Frac  float64
+	}
+	var splits []splitPoint
+	for _, v := range vertices {
+		if v == a || v == b || convexHullTurn(a, b, v) != 0 {
+			continue
+		}
+		frac := v.Sub(a).Dot(dir) / dir.Dot(dir)
+		if frac > 0 && frac < 1 {
+			splits = append(splits, splitPoint{Coord: v, Frac: frac})
+		}
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		return splits[i].Frac < splits[j].Frac
+	})
+	res := make([][2]Coord, 0, len(splits)+1)
+	prev := a
+	for _, s := range splits {
+		res = append(res, [2]Coord{prev, s.Coord})
+		prev = s.Coord
+	}
+	return append(res, [2]Coord{prev, b})
+}
+
+// delaunayFlip performs Lawson edge flips on a clockwise
+// triangulation in place until every non-fixed edge is
+// locally Delaunay.
+func delaunayFlip(tris [][3]Coord, fixed func(edge [2]Coord) bool) {
+	newDelaunayTriangulation(tris).flip(fixed)
+}
+
+// delaunayTriangulation tracks the edges of a clockwise
+// triangulation so that edges can be flipped in place.
+type delaunayTriangulation struct {
+	tris       [][3]Coord
+	edgeToTris map[[2]Coord][]int
+}
+
+func newDelaunayTriangulation(tris [][3]Coord) *delaunayTriangulation {
+	d := &delaunayTriangulation{
+		tris:       tris,
+		edgeToTris: map[[2]Coord][]int{},
+	}
+	for i := range tris {
+		d.addTri(i)
+	}
+	return d
+}
+
+func (d *delaunayTriangulation) vertexSet() map[Coord]bool {
+	res := map[Coord]bool{}
+	for _, t := range d.tris {
+		for _, c := range t {
+			res[c] = true
+		}
+	}
+	return res
+}
+
+func (d *delaunayTriangulation) addTri(idx int) {
+	t := d.tris[idx]
+	for i := 0; i < 3; i++ {
+		e := canonicalDelaunayEdge(t[i], t[(i+1)%3])
+		d.edgeToTris[e] = append(d.edgeToTris[e], idx)
+	}
+}
+
+func (d *delaunayTriangulation) removeTri(idx int) {
+	t := d.tris[idx]
+	for i := 0; i < 3; i++ {
+		e := canonicalDelaunayEdge(t[i], t[(i+1)%3])
+		list := d.edgeToTris[e]
+		for j, x := range list {
+			if x == idx {
+				list[j] = list[len(list)-1]
+				list = list[:len(list)-1]
+				break
+			}
+		}
+		if len(list) == 0 {
+			delete(d.edgeToTris, e)
+		} else {
+			d.edgeToTris[e] = list
+		}
+	}
+}
+
+// flipEdge replaces the two triangles adjacent to edge
+// with two triangles sharing the opposite diagonal, and
+// returns the new diagonal.
+func (d *delaunayTriangulation) flipEdge(edge [2]Coord) [2]Coord {
+	neighbors := d.edgeToTris[edge]
+	idx1, idx2 := neighbors[0], neighbors[1]
+	c := triangleOppositeVertex(d.tris[idx1], edge)
+	e := triangleOppositeVertex(d.tris[idx2], edge)
+	d.removeTri(idx1)
+	d.removeTri(idx2)
+	d.tris[idx1] = clockwiseTriangle(c, e, edge[0])
+	d.tris[idx2] = clockwiseTriangle(c, e, edge[1])
+	d.addTri(idx1)
+	d.addTri(idx2)
+	return canonicalDelaunayEdge(c, e)
+}
+
+func (d *delaunayTriangulation) flip(fixed func(edge [2]Coord) bool) {
+	var queue [][2]Coord
+	inQueue := map[[2]Coord]bool{}
+	for e := range d.edgeToTris {
+		queue = append(queue, e)
+		inQueue[e] = true
+	}
+	for len(queue) > 0 {
+		edge := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		delete(inQueue, edge)
+
+		neighbors := d.edgeToTris[edge]
+		if len(neighbors) != 2 || fixed(edge) {
+			continue
+		}
+		c := triangleOppositeVertex(d.tris[neighbors[0]], edge)
+		e := triangleOppositeVertex(d.tris[neighbors[1]], edge)
+		if delaunayInCircle(d.tris[neighbors[0]], e) <= 0 {
+			continue
+		}
+		d.flipEdge(edge)
+
+		for _, e := range [][2]Coord{
+			canonicalDelaunayEdge(c, edge[0]),
+			canonicalDelaunayEdge(c, edge[1]),
+			canonicalDelaunayEdge(e, edge[0]),
+			canonicalDelaunayEdge(e, edge[1]),
+		} {
+			if !inQueue[e] {
+				inQueue[e] = true
+				queue = append(queue, e)
+			}
+		}
+	}
+}
+
+// insertConstraint flips edges until the segment from a
+// to b is an edge of the triangulation, following Sloan
+// (1993). It returns false if the segment could not be
+// inserted, for example because it crosses another
+// constraint.
+func (d *delaunayTriangulation) insertConstraint(a, b Coord) bool {
+	target := canonicalDelaunayEdge(a, b)
+	if _, ok := d.edgeToTris[target]; ok {
+		return true
+	}
+	var crossing [][2]Coord
+	for _, t := range d.tris {
+		for i := 0; i < 3; i++ {
+			e := canonicalDelaunayEdge(t[i], t[(i+1)%3])
+			if delaunaySegmentsCross(e[0], e[1], a, b) {
+				crossing = append(crossing, e)
+			}
+		}
+	}
+	if len(crossing) == 0 {
+		return false
+	}
+
+	// Each crossing edge was found twice, once per triangle.
+	sort.Slice(crossing, func(i, j int) bool {
+		return delaunayEdgeLess(crossing[i], crossing[j])
+	})
+	unique := crossing[:0]
+	for i, e := range crossing {
+		if i == 0 || e != crossing[i-1] {
+			unique = append(unique, e)
+		}
+	}
+	crossing = unique
+
+	maxIters := len(crossing)*len(crossing) + 10
+	for i := 0; i < maxIters && len(crossing) > 0; i++ {
+		edge := crossing[0]
+		crossing = crossing[1:]
+		neighbors := d.edgeToTris[edge]
+		if len(neighbors) != 2 {
+			return false
+		}
+		c := triangleOppositeVertex(d.tris[neighbors[0]], edge)
+		e := triangleOppositeVertex(d.tris[neighbors[1]], edge)
+		if !delaunaySegmentsCross(c, e, edge[0], edge[1]) {
+			// The quadrilateral is not strictly convex, so
+			// we must wait for other flips to change it.
+			crossing = append(crossing, edge)
+			continue
+		}
+		newEdge := d.flipEdge(edge)
+		if delaunaySegmentsCross(newEdge[0], newEdge[1], a, b) {
+			crossing = append(crossing, newEdge)
+		}
+	}
+	_, ok := d.edgeToTris[target]
+	return ok
+}
+
+// delaunaySegmentsCross checks if two segments cross at a
+// single point in both of their interiors.
+func delaunaySegmentsCross(a1, a2, b1, b2 Coord) bool {
+	if a1 == b1 || a1 == b2 || a2 == b1 || a2 == b2 {
+		return false
+	}
+	o1 := convexHullTurn(b1, b2, a1)
+	o2 := convexHullTurn(b1, b2, a2)
+	if o1 == 0 || o2 == 0 || (o1 > 0) == (o2 > 0) {
+		return false
+	}
+	o3 := convexHullTurn(a1, a2, b1)
+	o4 := convexHullTurn(a1, a2, b2)
+	return o3 != 0 && o4 != 0 && (o3 > 0) != (o4 > 0)
+}
+
+func delaunayEdgeLess(e1, e2 [2]Coord) bool {
+	for i := 0; i < 2; i++ {
+		if e1[i] != e2[i] {
+			if e1[i].X != e2[i].X {
+				return e1[i].X < e2[i].X
+			}
+			return e1[i].Y < e2[i].Y
+		}
+	}
+	return false
+}
+
+// delaunayInCircle returns a positive value if p is
+// strictly inside the circumcircle of the clockwise
+// triangle t, and a negative value if it is outside.
+//
+// The sign of the result is exact, so cocircular points
+// give exactly zero. The magnitude is normalized to be
+// scale-invariant.
+func delaunayInCircle(t [3]Coord, p Coord) float64 {
+	// The predicate expects a counter-clockwise triangle.
+	det := numerical.InCircle(t[0].Array(), t[2].Array(), t[1].Array(), p.Array())
+
+	scale := math.Max(t[0].Dist(p), math.Max(t[1].Dist(p), t[2].Dist(p)))
+	if scale == 0 {
+		return 0
+	}
+	scale *= scale
+	return det / (scale * scale)
+}
+
+func triangleOppositeVertex(t [3]Coord, edge [2]Coord) Coord {
+	for _, c := range t {
+		if c != edge[0] && c != edge[1] {
+			return c
+		}
+	}
+	panic("triangle does not have an opposite vertex")
+}
+
+func clockwiseTriangle(a, b, c Coord) [3]Coord {
+	if convexHullTurn(a, b, c) > 0 {
+		return [3]Coord{a, c, b}
+	}
+	return [3]Coord{a, b, c}
+}
+
+func canonicalDelaunayEdge(c1, c2 Coord) [2]Coord {
+	if c1.X < c2.X || (c1.X == c2.X && c1.Y < c2.Y) {
+		return [2]Coord{c1, c2}
+	}
+	return [2]Coord{c2, c1}
+}
@@ -0,0 +1,152 @@
+package model2d
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTriangulateMeshDelaunay(t *testing.T) {
+	bitmap := MustReadBitmap("test_data/test_bitmap_small.png", func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return r == 0 && g == 0 && b == 0
+	})
+	mesh := bitmap.Mesh().SmoothSq(30)
+	MustValidateMesh(t, mesh, true)
+
+	tris := TriangulateMeshDelaunay(mesh)
+	if len(tris) != len(TriangulateMesh(mesh)) {
+		t.Errorf("unexpected number of triangles: %d", len(tris))
+	}
+	testTriangulatedEdgeCounts(t, tris, mesh)
+	testTriangulatedContainment(t, tris, mesh, 1000)
+
+	for _, tri := range tris {
+		if !isPolygonClockwise(tri[:]) {
+			t.Fatalf("triangle is not clockwise: %v", tri)
+		}
+	}
+
+	// Every unconstrained edge should be locally Delaunay.
+	edgeTris := map[[2]Coord][]int{}
+	for i, tri := range tris {
+		for j := 0; j < 3; j++ {
+			e := canonicalDelaunayEdge(tri[j], tri[(j+1)%3])
+			edgeTris[e] = append(edgeTris[e], i)
+		}
+	}
+	for e, idxs := range edgeTris {
+		if len(idxs) != 2 || len(mesh.Find(e[0], e[1])) > 0 {
+			continue
+		}
+		d := triangleOppositeVertex(tris[idxs[1]], e)
+		if delaunayInCircle(tris[idxs[0]], d) > 1e-8 {
+			t.Fatalf("edge %v is not locally Delaunay", e)
+		}
+	}
+
+	if a1, a2 := minTriangleAngle(tris), minTriangleAngle(TriangulateMesh(mesh)); a1 < a2 {
+		t.Errorf("minimum angle should not decrease, but went from %f to %f", a2, a1)
+	}
+}
+
+func minTriangleAngle(tris [][3]Coord) float64 {
+	res := math.Inf(1)
+	for _, tri := range tris {
+		for i := 0; i < 3; i++ {
+			v1 := tri[(i+1)%3].Sub(tri[i]).Normalize()
+			v2 := tri[(i+2)%3].Sub(tri[i]).Normalize()
+			res = math.Min(res, math.Acos(math.Max(-1, math.Min(1, v1.Dot(v2)))))
+		}
+	}
+	return res
+}
+
+func TestDelaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	randomPoints := make([]Coord, 200)
+	for i := range randomPoints {
+		randomPoints[i] = XY(rng.NormFloat64(), rng.NormFloat64())
+	}
+	var gridPoints []Coord
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 5; y++ {
+			gridPoints = append(gridPoints, XY(float64(x), float64(y)))
+		}
+	}
+	// Start with colinear points and include duplicates.
+	gridPoints = append(gridPoints, gridPoints[:10]...)
+
+	for name, points := range map[string][]Coord{"Random": randomPoints, "Grid": gridPoints} {
+		t.Run(name, func(t *testing.T) {
+			tris := Delaunay(points)
+
+			var area float64
+			for _, tri := range tris {
+				if !isPolygonClockwise(tri[:]) {
+					t.Fatalf("triangle is not clockwise: %v", tri)
+				}
+				area += math.Abs(edgeCross(tri[1].Sub(tri[0]), tri[2].Sub(tri[0]))) / 2
+				for _, p := range points {
+					if delaunayInCircle(tri, p) > 1e-8 {
+						t.Fatalf("point %v is inside circumcircle of %v", p, tri)
+					}
+				}
+			}
+			hullArea := PolylineMesh(ConvexHull(points), true).Area()
+			if math.Abs(area-hullArea) > 1e-8 {
+				t.Errorf("expected area %f but got %f", hullArea, area)
+			}
+		})
+	}
+
+	if tris := Delaunay([]Coord{XY(0, 0), XY(1, 1), XY(2, 2), XY(3, 3)}); len(tris) != 0 {
+		t.Errorf("colinear points should have no triangles, but got %d", len(tris))
+	}
+}
+
+func TestConstrainedDelaunay(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	points := []Coord{XY(0, 0), XY(1, 0), XY(1, 1), XY(0, 1)}
+	for i := 0; i < 200; i++ {
+		points = append(points, XY(rng.Float64(), rng.Float64()))
+	}
+
+	// A zig-zag chain of long constraints, where the middle
+	// segment passes exactly through an existing point.
+	segments := [][2]Coord{
+		{XY(0.0625, 0.125), XY(0.875, 0.25)},
+		{XY(0.875, 0.25), XY(0.125, 0.5)},
+		{XY(0.125, 0.5), XY(0.9375, 0.875)},
+	}
+	points = append(points, XY(0.5, 0.375))
+	expectedEdges := [][2]Coord{
+		segments[0],
+		{XY(0.875, 0.25), XY(0.5, 0.375)},
+		{XY(0.5, 0.375), XY(0.125, 0.5)},
+		segments[2],
+	}
+
+	tris := ConstrainedDelaunay(points, segments)
+
+	var area float64
+	edges := map[[2]Coord]bool{}
+	for _, tri := range tris {
+		if !isPolygonClockwise(tri[:]) {
+			t.Fatalf("triangle is not clockwise: %v", tri)
+		}
+		area += math.Abs(edgeCross(tri[1].Sub(tri[0]), tri[2].Sub(tri[0]))) / 2
+		for i := 0; i < 3; i++ {
+			edges[canonicalDelaunayEdge(tri[i], tri[(i+1)%3])] = true
+		}
+	}
+	if math.Abs(area-1) > 1e-8 {
+		t.Errorf("expected area 1 but got %f", area)
+	}
+	for _, e := range expectedEdges {
+		if !edges[canonicalDelaunayEdge(e[0], e[1])] {
+			t.Errorf("missing constraint: %v", e)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package model2d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// DistanceTransform computes the exact Euclidean distance
+// from the center of every pixel to the center of the
+// nearest true pixel.
+//
+// The result is stored in row-major order, like b.Data.
+// True pixels have a distance of 0. If there are no true
+// pixels, every distance is infinite.
+//
+// This runs in linear time using the algorithm from
+// Felzenszwalb and Huttenlocher, "Distance Transforms of
+// Sampled Functions".
+func (b *Bitmap) DistanceTransform() []float64 {
+	sqDists := make([]float64, len(b.Data))
+	for i, x := range b.Data {
+		if !x {
+			sqDists[i] = math.Inf(1)
+		}
+	}
+	squaredDistanceTransform(sqDists, b.Width, b.Height)
+	for i, x := range sqDists {
+		sqDists[i] = math.Sqrt(x)
+	}
+	return sqDists
+}
+
+// BitmapToSDF creates an SDF approximating the boundary of
+// BitmapToSolid(b).
+//
+// The SDF is computed with a distance transform and is
+// bilinearly interpolated between pixel centers, so it is
+// very cheap to evaluate. Like other SDFs, it is positive
+// inside the shape and negative outside.
+// Pixels outside of the bitmap are treated as false.
+//
+// The result can be used with SDFToSolid to quickly inset
+// or outset shapes derived from images.
+func BitmapToSDF(b *Bitmap) SDF {
+	// Pad the bitmap with a border of false pixels, so that
+	// the SDF is accurate near the edges.
+	width, height := b.Width+2, b.Height+2
+	inside := make([]float64, width*height)
+	outside := make([]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			idx := x + y*width
+			if b.Get(x-1, y-1) {
+				outside[idx] = math.Inf(1)
+			} else {
+				inside[idx] = math.Inf(1)
+			}
+		}
+	}
+	squaredDistanceTransform(inside, width, height)
+	squaredDistanceTransform(outside, width, height)
+
+	values := make([]float64, width*height)
+	for i, x := range inside {
+		if x == 0 {
+			values[i] = math.Sqrt(outside[i]) - 0.5
+		} else {
+			values[i] = 0.5 - math.Sqrt(x)
+		}
+	}
+
+	return &bitmapSDF{
+		width:  b.Width,
+		height: b.Height,
+		values: values,
+	}
+}
+
+type bitmapSDF struct {
+	width  int
+	height int
+
+	// values stores the SDF at the centers of the pixels
+	// of a padded version of the bitmap.
+	values []float64
+}
+
+func (b *bitmapSDF) Min() Coord {
+	return Coord{}
+}
+
+func (b *bitmapSDF) Max() Coord {
+	return XY(float64(b.width), float64(b.height))
+}
+
+func (b *bitmapSDF) SDF(c Coord) float64 {
+	width, height := b.width+2, b.height+2
+
+	// Points outside of the padded bitmap are at least as
+	// far from the shape as the closest padding pixel.
+	min := XY(-0.5, -0.5)
+	max := XY(float64(b.width)+0.5, float64(b.height)+0.5)
+	clamped := c.Max(min).Min(max)
+	outsideDist := c.Dist(clamped)
+
+	// Interpolate between pixel centers.
+	px, py := clamped.X+0.5, clamped.Y+0.5
+	x0 := essentials.MinInt(int(px), width-2)
+	y0 := essentials.MinInt(int(py), height-2)
+	fx, fy := px-float64(x0), py-float64(y0)
+	v00 := b.values[x0+y0*width]
+	v10 := b.values[x0+1+y0*width]
+	v01 := b.values[x0+(y0+1)*width]
+	v11 := b.values[x0+1+(y0+1)*width]
+	value := (v00*(1-fx)+v10*fx)*(1-fy) + (v01*(1-fx)+v11*fx)*fy
+	return value - outsideDist
+}
+
+// squaredDistanceTransform replaces every value in a grid
+// with the minimum over all cells of that cell's value
+// plus the squared distance to it.
+func squaredDistanceTransform(grid []float64, width, height int) {
+	size := essentials.MaxInt(width, height)
+	f := make([]float64, size)
+	out := make([]float64, size)
+	v := make([]int, size)
+	z := make([]float64, size+1)
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			f[y] = grid[x+y*width]
+		}
+		distanceTransform1D(f[:height], out[:height], v, z)
+		for y := 0; y < height; y++ {
+			grid[x+y*width] = out[y]
+		}
+	}
+	for y := 0; y < height; y++ {
+		row := grid[y*width : (y+1)*width]
+		copy(f, row)
+		distanceTransform1D(f[:width], row, v, z)
+	}
+}
+
+// distanceTransform1D computes the lower envelope of
+// parabolas rooted at each value of f.
+func distanceTransform1D(f, out []float64, v []int, z []float64) {
+	n := len(f)
+	intersect := func(q, p int) float64 {
+		return ((f[q] + float64(q*q)) - (f[p] + float64(p*p))) / float64(2*(q-p))
+	}
+
+	// Compute the lower envelope, skipping infinite values
+	// which do not contribute to it.
+	k := -1
+	for q := 0; q < n; q++ {
+		if math.IsInf(f[q], 1) {
+			continue
+		}
+		if k == -1 {
+			k = 0
+			v[0] = q
+			z[0] = math.Inf(-1)
+			z[1] = math.Inf(1)
+			continue
+		}
+		s := intersect(q, v[k])
+		for s <= z[k] {
+			k--
+			s = intersect(q, v[k])
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = math.Inf(1)
+	}
+	if k == -1 {
+		for q := range out {
+			out[q] = math.Inf(1)
+		}
+		return
+	}
+
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d := float64(q - v[k])
+		out[q] = d*d + f[v[k]]
+	}
+}
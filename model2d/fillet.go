@@ -0,0 +1,107 @@
+package model2d
+
+import "math"
+
+// FilletCorners replaces the corners of a mesh with
+// circular arcs of the given radius, tangent to both of
+// the corner's segments.
+//
+// Corners are vertices joined by exactly two consistently
+// oriented segments. When segments are too short for the
+// full radius, the radius of the corresponding fillets is
+// reduced so that neighboring fillets do not overlap.
+//
+// Arcs are approximated by segments with a maximum error
+// of one thousandth of the radius.
+func FilletCorners(m *Mesh, radius float64) *Mesh {
+	return replaceCorners(m, func(v, u1, u2 Coord, maxDist float64) []Coord {
+		// Distance from the corner to the tangent points.
+		halfAngle := math.Acos(math.Max(-1, math.Min(1, u1.Dot(u2)))) / 2
+		dist := radius / math.Tan(halfAngle)
+		r := radius
+		if dist > maxDist {
+			dist = maxDist
+			r = dist * math.Tan(halfAngle)
+		}
+		p1 := v.Add(u1.Scale(dist))
+		p2 := v.Add(u2.Scale(dist))
+		clockwise := u1.X*u2.Y-u1.Y*u2.X > 0
+		arc := NewArcPoints(p1, p2, r, false, clockwise)
+		points := arc.Flatten(r * 1e-3)
+		points[0], points[len(points)-1] = p1, p2
+		return points
+	})
+}
+
+// ChamferCorners replaces the corners of a mesh with
+// bevels, cutting the given distance off of each of the
+// corner's segments.
+//
+// Corners are selected and distances are limited like in
+// FilletCorners.
+func ChamferCorners(m *Mesh, dist float64) *Mesh {
+	return replaceCorners(m, func(v, u1, u2 Coord, maxDist float64) []Coord {
+		d := math.Min(dist, maxDist)
+		return []Coord{v.Add(u1.Scale(d)), v.Add(u2.Scale(d))}
+	})
+}
+
+// replaceCorners replaces every corner of a mesh with a
+// polyline.
+//
+// The function f is called with the corner vertex, the
+// unit directions from the vertex along the incoming and
+// outgoing segments, and the maximum distance from the
+// vertex that the polyline may start or end at.
+// It returns the polyline, which goes from a point along
+// the incoming segment to a point along the outgoing
+// segment.
+func replaceCorners(m *Mesh, f func(v, u1, u2 Coord, maxDist float64) []Coord) *Mesh {
+	trimmedStart := map[*Segment]Coord{}
+	trimmedEnd := map[*Segment]Coord{}
+	res := NewMesh()
+	m.IterateVertices(func(v Coord) {
+		segs := m.Find(v)
+		if len(segs) != 2 {
+			return
+		}
+		in, out := segs[0], segs[1]
+		if in[0] == v {
+			in, out = out, in
+		}
+		if in[1] != v || out[0] != v {
+			return
+		}
+		len1, len2 := in.Length(), out.Length()
+		if len1 == 0 || len2 == 0 {
+			return
+		}
+		u1 := in[0].Sub(v).Scale(1 / len1)
+		u2 := out[1].Sub(v).Scale(1 / len2)
+		if u1.Dot(u2) < -1+1e-8 {
+			// The vertex is not really a corner.
+			return
+		}
+		points := f(v, u1, u2, math.Min(len1, len2)/2)
+		trimmedEnd[in] = points[0]
+		trimmedStart[out] = points[len(points)-1]
+		for i := 1; i < len(points); i++ {
+			if points[i-1] != points[i] {
+				res.Add(&Segment{points[i-1], points[i]})
+			}
+		}
+	})
+	m.Iterate(func(s *Segment) {
+		seg := *s
+		if p, ok := trimmedStart[s]; ok {
+			seg[0] = p
+		}
+		if p, ok := trimmedEnd[s]; ok {
+			seg[1] = p
+		}
+		if seg[0] != seg[1] {
+			res.Add(&seg)
+		}
+	})
+	return res
+}
@@ -0,0 +1,48 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFilletCorners(t *testing.T) {
+	rect := NewMeshRect(XY(0, 0), XY(4, 2))
+	for _, radius := range []float64{0.5, 5} {
+		filleted := FilletCorners(rect, radius)
+		MustValidateMesh(t, filleted, true)
+
+		r := math.Min(radius, 1)
+		expectedArea := 8 - (4-math.Pi)*r*r
+		if a := filleted.Area(); math.Abs(a-expectedArea) > 1e-2 {
+			t.Errorf("radius %f: expected area %f but got %f", radius, expectedArea, a)
+		}
+		expectedPerim := 12 - 8*r + 2*math.Pi*r
+		if p := filleted.Perimeter(); math.Abs(p-expectedPerim) > 1e-2 {
+			t.Errorf("radius %f: expected perimeter %f but got %f", radius, expectedPerim,
+				p)
+		}
+	}
+
+	// Concave corners should be filleted as well.
+	lShape := PolylineMesh([]Coord{
+		XY(0, 0), XY(0, 2), XY(1, 2), XY(1, 1), XY(2, 1), XY(2, 0),
+	}, true)
+	filleted := FilletCorners(lShape, 0.2)
+	MustValidateMesh(t, filleted, true)
+	expectedArea := 3 - 5*(4-math.Pi)*0.04/4 + (4-math.Pi)*0.04/4
+	if a := filleted.Area(); math.Abs(a-expectedArea) > 1e-2 {
+		t.Errorf("expected area %f but got %f", expectedArea, a)
+	}
+}
+
+func TestChamferCorners(t *testing.T) {
+	rect := NewMeshRect(XY(0, 0), XY(4, 2))
+	chamfered := ChamferCorners(rect, 0.5)
+	MustValidateMesh(t, chamfered, true)
+	if n := chamfered.NumSegments(); n != 8 {
+		t.Errorf("expected 8 segments but got %d", n)
+	}
+	if a := chamfered.Area(); math.Abs(a-7.5) > 1e-8 {
+		t.Errorf("expected area 7.5 but got %f", a)
+	}
+}
@@ -0,0 +1,178 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+)
+
+const DefaultHatcherSearchIters = 8
+
+// HatchPattern determines the layout of the segments
+// produced by a Hatcher.
+type HatchPattern int
+
+const (
+	// HatchPatternLines fills a shape with parallel lines.
+	HatchPatternLines HatchPattern = iota
+
+	// HatchPatternCross fills a shape with two sets of
+	// perpendicular parallel lines.
+	HatchPatternCross
+
+	// HatchPatternConcentric fills a shape with inward
+	// offsets of its outline.
+	HatchPatternConcentric
+)
+
+// A Hatcher fills 2D shapes with patterns of segments.
+//
+// This can be used to create toolpaths for engraving and
+// pen plotting, or custom infill patterns.
+type Hatcher struct {
+	Pattern HatchPattern
+
+	// Spacing is the distance between adjacent lines or
+	// loops of the pattern.
+	Spacing float64
+
+	// Angle is the direction of the lines, in radians
+	// relative to the x-axis.
+	//
+	// This is unused for concentric patterns.
+	Angle float64
+
+	// Delta is the grid size used to find the boundary of
+	// a Solid in Hatch().
+	//
+	// If 0, Spacing/4 is used.
+	Delta float64
+
+	// SearchIters is the number of search iterations used
+	// to refine the boundary of a Solid in Hatch().
+	//
+	// If 0, DefaultHatcherSearchIters is used.
+	SearchIters int
+}
+
+// Hatch fills a solid with the pattern, returning a mesh
+// of (usually disconnected) segments.
+//
+// The boundary of the solid is first approximated with
+// marching squares, and then the result is computed like
+// HatchMesh.
+func (h *Hatcher) Hatch(s Solid) *Mesh {
+	delta := h.Delta
+	if delta == 0 {
+		delta = h.Spacing / 4
+	}
+	iters := h.SearchIters
+	if iters == 0 {
+		iters = DefaultHatcherSearchIters
+	}
+	return h.HatchMesh(MarchingSquaresSearch(s, delta, iters))
+}
+
+// HatchMesh fills the inside of a closed mesh with the
+// pattern, returning a mesh of segments.
+//
+// For line patterns, the lines are aligned to a global
+// grid, so that hatching neighboring shapes with the same
+// settings produces continuous lines. The insides of the
+// mesh are determined using the even-odd rule.
+//
+// For concentric patterns, the mesh must be manifold and
+// have outward-facing normals, and the first loop is
+// placed half of the spacing inside of the boundary.
+func (h *Hatcher) HatchMesh(m *Mesh) *Mesh {
+	if h.Spacing <= 0 {
+		panic("hatch spacing must be positive")
+	}
+	switch h.Pattern {
+	case HatchPatternLines:
+		return hatchLines(m, h.Spacing, h.Angle)
+	case HatchPatternCross:
+		res := hatchLines(m, h.Spacing, h.Angle)
+		res.AddMesh(hatchLines(m, h.Spacing, h.Angle+math.Pi/2))
+		return res
+	case HatchPatternConcentric:
+		res := NewMesh()
+		for i := 0; ; i++ {
+			loops := OffsetMesh(m, -h.Spacing*(float64(i)+0.5))
+			if loops.NumSegments() == 0 {
+				break
+			}
+			res.AddMesh(loops)
+		}
+		return res
+	default:
+		panic("unknown hatch pattern")
+	}
+}
+
+// hatchLines clips evenly spaced parallel lines to the
+// inside of a mesh.
+func hatchLines(m *Mesh, spacing, angle float64) *Mesh {
+	res := NewMesh()
+	if m.NumSegments() == 0 {
+		return res
+	}
+	dir := XY(math.Cos(angle), math.Sin(angle))
+	normal := XY(-dir.Y, dir.X)
+
+	// Sort the segments by their minimum offset along the
+	// normal, so that each line only checks the segments
+	// which may cross it.
+	segs := m.SegmentSlice()
+	sort.Slice(segs, func(i, j int) bool {
+		return hatchMinOffset(segs[i], normal) < hatchMinOffset(segs[j], normal)
+	})
+	minOffset := hatchMinOffset(segs[0], normal)
+	maxOffset := math.Inf(-1)
+	for _, s := range segs {
+		maxOffset = math.Max(maxOffset, math.Max(normal.Dot(s[0]), normal.Dot(s[1])))
+	}
+
+	var active []*Segment
+	nextSeg := 0
+	var ts []float64
+	for k := math.Ceil(minOffset / spacing); k*spacing <= maxOffset; k++ {
+		offset := k * spacing
+		for nextSeg < len(segs) && hatchMinOffset(segs[nextSeg], normal) <= offset {
+			active = append(active, segs[nextSeg])
+			nextSeg++
+		}
+
+		ts = ts[:0]
+		remaining := active[:0]
+		for _, s := range active {
+			o1, o2 := normal.Dot(s[0])-offset, normal.Dot(s[1])-offset
+			if o1 < 0 && o2 < 0 {
+				// This segment is behind every remaining line.
+				continue
+			}
+			remaining = append(remaining, s)
+
+			// Use a half-open rule so that lines through
+			// vertices are handled consistently.
+			if (o1 >= 0) != (o2 >= 0) {
+				frac := o1 / (o1 - o2)
+				p := s[0].Add(s[1].Sub(s[0]).Scale(frac))
+				ts = append(ts, dir.Dot(p))
+			}
+		}
+		active = remaining
+
+		sort.Float64s(ts)
+		base := normal.Scale(offset)
+		for i := 0; i+1 < len(ts); i += 2 {
+			if ts[i] < ts[i+1] {
+				res.Add(&Segment{base.Add(dir.Scale(ts[i])), base.Add(dir.Scale(ts[i+1]))})
+			}
+		}
+	}
+	return res
+}
+
+func hatchMinOffset(s *Segment, normal Coord) float64 {
+	return math.Min(normal.Dot(s[0]), normal.Dot(s[1]))
+}
@@ -0,0 +1,69 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHatcherRect(t *testing.T) {
+	rect := NewMeshRect(XY(0.3, 0.2), XY(10.3, 5.2))
+
+	t.Run("Lines", func(t *testing.T) {
+		h := &Hatcher{Spacing: 1}
+		hatch := h.HatchMesh(rect)
+		if n := hatch.NumSegments(); n != 5 {
+			t.Errorf("expected 5 segments but got %d", n)
+		}
+		hatch.Iterate(func(s *Segment) {
+			if math.Abs(s.Length()-10) > 1e-8 || math.Abs(s[0].Y-s[1].Y) > 1e-8 {
+				t.Errorf("unexpected segment: %v", s)
+			}
+		})
+	})
+
+	t.Run("Cross", func(t *testing.T) {
+		h := &Hatcher{Pattern: HatchPatternCross, Spacing: 1}
+		hatch := h.HatchMesh(rect)
+		if n := hatch.NumSegments(); n != 15 {
+			t.Errorf("expected 15 segments but got %d", n)
+		}
+		if l := hatch.Perimeter(); math.Abs(l-100) > 1e-8 {
+			t.Errorf("expected total length 100 but got %f", l)
+		}
+	})
+
+	t.Run("Rotated", func(t *testing.T) {
+		h := &Hatcher{Spacing: 0.1, Angle: 0.7}
+		hatch := h.HatchMesh(rect)
+		expected := rect.Area() / h.Spacing
+		if l := hatch.Perimeter(); math.Abs(l-expected) > 0.02*expected {
+			t.Errorf("expected total length %f but got %f", expected, l)
+		}
+		hatch.Iterate(func(s *Segment) {
+			if math.Abs(s[1].Sub(s[0]).Normalize().Dot(XY(-math.Sin(0.7), math.Cos(0.7)))) > 1e-8 {
+				t.Errorf("unexpected segment direction: %v", s)
+			}
+		})
+	})
+}
+
+func TestHatcherSolid(t *testing.T) {
+	solid := &Circle{Center: XY(1, 2), Radius: 3}
+
+	for _, pattern := range []HatchPattern{HatchPatternLines, HatchPatternConcentric} {
+		h := &Hatcher{Pattern: pattern, Spacing: 0.2, Angle: 0.3}
+		hatch := h.Hatch(solid)
+		if pattern == HatchPatternConcentric {
+			MustValidateMesh(t, hatch, false)
+		}
+		hatch.Iterate(func(s *Segment) {
+			if !solid.Contains(s.Mid()) {
+				t.Fatalf("pattern %d: segment outside of solid: %v", pattern, s)
+			}
+		})
+		expected := math.Pi * 9 / h.Spacing
+		if l := hatch.Perimeter(); math.Abs(l-expected) > 0.03*expected {
+			t.Errorf("pattern %d: expected total length %f but got %f", pattern, expected, l)
+		}
+	}
+}
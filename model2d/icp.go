@@ -0,0 +1,213 @@
+package model2d
+
+import "math"
+
+const (
+	DefaultICPMaxIters  = 100
+	DefaultICPTolerance = 1e-10
+)
+
+// ICP aligns 2D shapes using the iterative closest point
+// algorithm.
+//
+// In each iteration, every source point is matched to its
+// closest point on the target, and the similarity
+// transform that best aligns the matched pairs in the
+// least-squares sense is computed in closed form.
+//
+// This can be used to align scanned outlines with the
+// profiles they were designed from.
+type ICP struct {
+	// MaxIters is the maximum number of iterations.
+	//
+	// If 0, DefaultICPMaxIters is used.
+	MaxIters int
+
+	// Tolerance is the minimum relative decrease in the
+	// mean squared error for the algorithm to continue.
+	//
+	// If 0, DefaultICPTolerance is used.
+	Tolerance float64
+
+	// Scale, if true, allows uniform scaling in addition to
+	// rotation and translation.
+	Scale bool
+
+	// InitialRotations, if greater than 1, is the number of
+	// evenly spaced initial rotations to try.
+	// The best resulting alignment is returned.
+	//
+	// ICP only finds a local optimum, so this is helpful
+	// when the shapes may be far from aligned.
+	InitialRotations int
+}
+
+// An ICPResult is a similarity transform which maps a
+// source shape onto a target shape.
+//
+// Points are first rotated and scaled about the origin,
+// and then translated.
+type ICPResult struct {
+	Rotation    float64
+	Scale       float64
+	Translation Coord
+
+	// Error is the root mean squared distance from the
+	// transformed source points to the target.
+	Error float64
+
+	// Iters is the number of iterations that were run.
+	Iters int
+}
+
+// Apply applies the transformation to a point.
+func (i *ICPResult) Apply(c Coord) Coord {
+	return NewMatrix2Rotation(i.Rotation).MulColumn(c).Scale(i.Scale).Add(i.Translation)
+}
+
+// Transform gets the transformation as a Transform.
+func (i *ICPResult) Transform() Transform {
+	return JoinedTransform{
+		Rotation(i.Rotation),
+		&Scale{Scale: i.Scale},
+		&Translate{Offset: i.Translation},
+	}
+}
+
+// Align aligns a set of source points to a set of target
+// points.
+func (i *ICP) Align(source, target []Coord) *ICPResult {
+	if len(target) == 0 {
+		panic("cannot align to empty target")
+	}
+	tree := NewCoordTree(target)
+	return i.align(source, target, tree.NearestNeighbor)
+}
+
+// AlignMeshes aligns the vertices of a source mesh to the
+// segments of a target mesh.
+//
+// Matching points to the closest points on segments,
+// rather than to the closest vertices, makes the alignment
+// insensitive to how finely the target is subdivided.
+func (i *ICP) AlignMeshes(source, target *Mesh) *ICPResult {
+	if target.NumSegments() == 0 {
+		panic("cannot align to empty target")
+	}
+	sdf := MeshToSDF(target)
+	return i.align(source.VertexSlice(), target.VertexSlice(), func(c Coord) Coord {
+		p, _ := sdf.PointSDF(c)
+		return p
+	})
+}
+
+func (i *ICP) align(source, target []Coord, closest func(Coord) Coord) *ICPResult {
+	if len(source) == 0 {
+		panic("cannot align empty source")
+	}
+	sourceCenter := icpMean(source)
+	targetCenter := icpMean(target)
+
+	// When scaling is allowed, start by matching the spread
+	// of the two point sets.
+	initScale := 1.0
+	if i.Scale {
+		sourceSpread := icpSpread(source, sourceCenter)
+		if sourceSpread > 0 {
+			initScale = math.Sqrt(icpSpread(target, targetCenter) / sourceSpread)
+		}
+	}
+
+	numRotations := i.InitialRotations
+	if numRotations < 1 {
+		numRotations = 1
+	}
+	var best *ICPResult
+	for j := 0; j < numRotations; j++ {
+		theta := 2 * math.Pi * float64(j) / float64(numRotations)
+		init := &ICPResult{Rotation: theta, Scale: initScale}
+		init.Translation = targetCenter.Sub(init.Apply(sourceCenter))
+		result := i.refine(source, closest, init)
+		if best == nil || result.Error < best.Error {
+			best = result
+		}
+	}
+	return best
+}
+
+func (i *ICP) refine(source []Coord, closest func(Coord) Coord, init *ICPResult) *ICPResult {
+	maxIters := i.MaxIters
+	if maxIters == 0 {
+		maxIters = DefaultICPMaxIters
+	}
+	tolerance := i.Tolerance
+	if tolerance == 0 {
+		tolerance = DefaultICPTolerance
+	}
+
+	current := *init
+	matches := make([]Coord, len(source))
+	lastError := math.Inf(1)
+	for iter := 0; iter < maxIters; iter++ {
+		var sqError float64
+		for j, c := range source {
+			p := current.Apply(c)
+			matches[j] = closest(p)
+			sqError += p.SquaredDist(matches[j])
+		}
+		sqError /= float64(len(source))
+		current.Error = math.Sqrt(sqError)
+		current.Iters = iter
+		if sqError == 0 || (iter > 0 && lastError-sqError <= tolerance*lastError) {
+			break
+		}
+		lastError = sqError
+
+		next := icpFit(source, matches, i.Scale)
+		next.Error = current.Error
+		current = *next
+	}
+	return &current
+}
+
+// icpFit computes the similarity transform that maps
+// source points to target points with the least squared
+// error.
+func icpFit(source, target []Coord, scale bool) *ICPResult {
+	sourceCenter := icpMean(source)
+	targetCenter := icpMean(target)
+
+	var dot, cross, sourceNorm float64
+	for i, s := range source {
+		p := s.Sub(sourceCenter)
+		q := target[i].Sub(targetCenter)
+		dot += p.Dot(q)
+		cross += p.X*q.Y - p.Y*q.X
+		sourceNorm += p.Dot(p)
+	}
+	res := &ICPResult{
+		Rotation: math.Atan2(cross, dot),
+		Scale:    1,
+	}
+	if scale && sourceNorm > 0 {
+		res.Scale = math.Sqrt(dot*dot+cross*cross) / sourceNorm
+	}
+	res.Translation = targetCenter.Sub(res.Apply(sourceCenter))
+	return res
+}
+
+func icpMean(points []Coord) Coord {
+	var sum Coord
+	for _, p := range points {
+		sum = sum.Add(p)
+	}
+	return sum.Scale(1 / float64(len(points)))
+}
+
+func icpSpread(points []Coord, center Coord) float64 {
+	var sum float64
+	for _, p := range points {
+		sum += p.SquaredDist(center)
+	}
+	return sum / float64(len(points))
+}
@@ -0,0 +1,67 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestICPAlign(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	var target []Coord
+	for i := 0; i < 200; i++ {
+		target = append(target, XY(rng.NormFloat64()*3, rng.NormFloat64()))
+	}
+
+	for _, scale := range []bool{false, true} {
+		expected := &ICPResult{Rotation: 0.3, Scale: 1, Translation: XY(0.5, -0.2)}
+		if scale {
+			expected.Scale = 1.2
+		}
+		inverse := expected.Transform().Inverse()
+		source := make([]Coord, len(target))
+		for i, c := range target {
+			source[i] = inverse.Apply(c)
+		}
+		rng.Shuffle(len(source), func(i, j int) {
+			source[i], source[j] = source[j], source[i]
+		})
+
+		actual := (&ICP{Scale: scale}).Align(source, target)
+		if math.Abs(actual.Rotation-expected.Rotation) > 1e-5 ||
+			math.Abs(actual.Scale-expected.Scale) > 1e-5 ||
+			actual.Translation.Dist(expected.Translation) > 1e-5 {
+			t.Errorf("scale=%v: expected %v but got %v", scale, expected, actual)
+		}
+		if actual.Error > 1e-5 {
+			t.Errorf("scale=%v: unexpected error %f", scale, actual.Error)
+		}
+	}
+}
+
+func TestICPAlignMeshes(t *testing.T) {
+	shape := func(theta float64) float64 {
+		return 2 + 0.5*math.Cos(3*theta) + 0.2*math.Sin(theta)
+	}
+	target := NewMeshPolar(shape, 50)
+
+	// The source is more finely sampled and noisy, like a
+	// scanned outline.
+	rng := rand.New(rand.NewSource(0))
+	xform := JoinedTransform{Rotation(2.5), &Translate{Offset: XY(3, 4)}}
+	source := NewMeshPolar(shape, 500).MapCoords(func(c Coord) Coord {
+		return xform.Apply(c).Add(XY(rng.NormFloat64(), rng.NormFloat64()).Scale(0.01))
+	})
+
+	icp := &ICP{InitialRotations: 8}
+	result := icp.AlignMeshes(source, target)
+	if result.Error > 0.03 {
+		t.Errorf("unexpected error: %f", result.Error)
+	}
+	for _, c := range []Coord{XY(0, 0), XY(1, 0), XY(0, 1)} {
+		actual := result.Apply(xform.Apply(c))
+		if actual.Dist(c) > 0.05 {
+			t.Errorf("point %v mapped to %v", c, actual)
+		}
+	}
+}
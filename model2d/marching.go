@@ -394,7 +394,7 @@ type squareSpacer struct {
 	Ys []float64
 }
 
-func newSquareSpacer(s Solid, delta float64) *squareSpacer {
+func newSquareSpacer(s Bounder, delta float64) *squareSpacer {
 	var xs, ys []float64
 	min := s.Min()
 	max := s.Max()
@@ -429,11 +429,18 @@ func (s *solidCache) FetchY(y int) {
 	maxX := len(s.spacer.Xs) - 1
 	onEdge := y == 0 || y == len(s.spacer.Ys)-1
 
-	var idx int
-	for i := 0; i < len(s.spacer.Xs); i++ {
-		b := s.solid.Contains(s.spacer.CornerCoord(i, y))
-		s.values[idx] = b
-		idx++
+	if batch, ok := s.solid.(BatchSolid); ok {
+		coords := make([]Coord, len(s.spacer.Xs))
+		for i := range coords {
+			coords[i] = s.spacer.CornerCoord(i, y)
+		}
+		copy(s.values, batch.ContainsMany(coords))
+	} else {
+		for i := range s.spacer.Xs {
+			s.values[i] = s.solid.Contains(s.spacer.CornerCoord(i, y))
+		}
+	}
+	for i, b := range s.values {
 		if b && (onEdge || i == 0 || i == maxX) {
 			panic("solid is true outside of bounds")
 		}
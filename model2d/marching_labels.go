@@ -0,0 +1,136 @@
+package model2d
+
+// MarchingSquaresLabels is like MarchingSquares, but
+// partitions space into regions according to a label
+// function rather than a single Solid.
+//
+// The result maps each label to a mesh enclosing the
+// region where labelFn returns that label.
+// Neighboring regions share the exact same boundary
+// vertices, with each boundary segment appearing once in
+// each of the two adjacent meshes with opposite
+// orientations.
+// This makes the results suitable for multi-color 2D
+// designs, or for extruding into stacked multi-material
+// parts without gaps or overlaps.
+//
+// The label function must return the same label for every
+// point outside of the bounds b. That label's mesh has
+// inward-facing boundaries around all of the other
+// regions.
+func MarchingSquaresLabels[L comparable](b Bounder, delta float64,
+	labelFn func(c Coord) L) map[L]*Mesh {
+	if !BoundsValid(b) {
+		panic("invalid bounds")
+	}
+	spacer := newSquareSpacer(b, delta)
+
+	fetchRow := func(y int, row []L) {
+		onEdge := y == 0 || y == len(spacer.Ys)-1
+		for x := range spacer.Xs {
+			row[x] = labelFn(spacer.CornerCoord(x, y))
+			if (onEdge || x == 0 || x == len(spacer.Xs)-1) && row[x] != row[0] {
+				panic("label is not constant outside of bounds")
+			}
+		}
+	}
+	bottomRow := make([]L, len(spacer.Xs))
+	topRow := make([]L, len(spacer.Xs))
+	fetchRow(0, topRow)
+	background := topRow[0]
+
+	result := map[L]*Mesh{}
+	addSegment := func(label L, p1, p2 Coord) {
+		mesh, ok := result[label]
+		if !ok {
+			mesh = NewMesh()
+			result[label] = mesh
+		}
+		mesh.Add(&Segment{p1, p2})
+	}
+
+	for y := 1; y < len(spacer.Ys); y++ {
+		bottomRow, topRow = topRow, bottomRow
+		fetchRow(y, topRow)
+		for x := 0; x < len(spacer.Xs)-1; x++ {
+			labels := [4]L{bottomRow[x], bottomRow[x+1], topRow[x], topRow[x+1]}
+			if labels[0] == labels[1] && labels[0] == labels[2] && labels[0] == labels[3] {
+				continue
+			}
+			corners := msCornerCoordinates(spacer.CornerCoord(x, y-1), spacer.CornerCoord(x+1, y))
+			msLabelSquare(corners, labels, addSegment)
+		}
+	}
+
+	if _, ok := result[background]; !ok {
+		result[background] = NewMesh()
+	}
+	return result
+}
+
+// msLabelPerimeter lists the corners of a square in
+// counter-clockwise order.
+var msLabelPerimeter = [4]msCorner{0, 1, 3, 2}
+
+// msLabelSquare creates the boundary segments within a
+// single square of a multi-label grid.
+//
+// For each boundary segment, f is called twice: once for
+// the label on each side, with the points ordered so that
+// the normal faces away from that label.
+func msLabelSquare[L comparable](corners [4]Coord, labels [4]L, f func(L, Coord, Coord)) {
+	// Edge i connects perimeter corner i to corner i+1.
+	var mids [4]Coord
+	var crossings []int
+	for i := 0; i < 4; i++ {
+		c1, c2 := msLabelPerimeter[i], msLabelPerimeter[(i+1)%4]
+		mids[i] = corners[c1].Mid(corners[c2])
+		if labels[c1] != labels[c2] {
+			crossings = append(crossings, i)
+		}
+	}
+
+	// When p1->p2 is traversed, the label on the right is
+	// inside and the label on the left is outside.
+	addBoundary := func(right, left L, p1, p2 Coord) {
+		f(right, p1, p2)
+		f(left, p2, p1)
+	}
+
+	// cutCorner separates the corner at perimeter index i
+	// from the rest of the square.
+	cutCorner := func(i int) {
+		prevEdge := (i + 3) % 4
+		prevCorner := msLabelPerimeter[prevEdge]
+		corner := msLabelPerimeter[i]
+		addBoundary(labels[corner], labels[prevCorner], mids[prevEdge], mids[i])
+	}
+
+	if len(crossings) == 2 {
+		// The corners traversed counter-clockwise from the
+		// first crossing to the second are on the right.
+		e1, e2 := crossings[0], crossings[1]
+		right := labels[msLabelPerimeter[(e1+1)%4]]
+		left := labels[msLabelPerimeter[(e2+1)%4]]
+		addBoundary(right, left, mids[e1], mids[e2])
+	} else if len(crossings) == 4 && labels[0] == labels[3] {
+		// Diagonal corners share a label; connect them
+		// through the center of the square. When both
+		// diagonals match, this arbitrarily connects the
+		// first one, like the ambiguous cases of
+		// MarchingSquares.
+		cutCorner(1)
+		cutCorner(3)
+	} else if len(crossings) == 4 && labels[1] == labels[2] {
+		cutCorner(0)
+		cutCorner(2)
+	} else {
+		// Three or four labels meet at the center.
+		center := corners[0].Mid(corners[3])
+		for _, e := range crossings {
+			before := labels[msLabelPerimeter[e]]
+			after := labels[msLabelPerimeter[(e+1)%4]]
+			addBoundary(after, before, mids[e], center)
+		}
+	}
+}
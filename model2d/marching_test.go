@@ -93,3 +93,70 @@ func TestMarchingSquaresFilter(t *testing.T) {
 		}
 	})
 }
+
+func TestMarchingSquaresLabels(t *testing.T) {
+	bounds := &Rect{MinVal: XY(-10, -10), MaxVal: XY(10, 10)}
+	labelFn := func(c Coord) int {
+		if c.Norm() > 9 {
+			return 0
+		} else if c.Dist(XY(4, 4)) < 2 {
+			return 5
+		}
+		c = c.Sub(XY(0.3, 0.3))
+		if c.X < 0 {
+			if c.Y < 0 {
+				return 1
+			}
+			return 2
+		} else if c.Y < 0 {
+			return 3
+		}
+		return 4
+	}
+	const delta = 0.25
+	meshes := MarchingSquaresLabels(bounds, delta, labelFn)
+	if len(meshes) != 6 {
+		t.Fatalf("expected 6 labels but got %d", len(meshes))
+	}
+
+	segments := map[[2]Coord]int{}
+	for label, mesh := range meshes {
+		MustValidateMesh(t, mesh, label != 0)
+		mesh.Iterate(func(s *Segment) {
+			segments[[2]Coord{s[0], s[1]}] = label
+		})
+	}
+	for seg, label := range segments {
+		if other, ok := segments[[2]Coord{seg[1], seg[0]}]; !ok {
+			t.Fatalf("segment of label %d is not shared", label)
+		} else if other == label {
+			t.Fatalf("label %d borders itself", label)
+		}
+	}
+
+	colliders := map[int]Collider{}
+	for label, mesh := range meshes {
+		if label != 0 {
+			colliders[label] = MeshToCollider(mesh)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		c := NewCoordRandBounds(bounds.Min(), bounds.Max())
+		label := labelFn(c)
+		ambiguous := false
+		for _, d := range []Coord{X(2 * delta), X(-2 * delta), Y(2 * delta), Y(-2 * delta)} {
+			if labelFn(c.Add(d)) != label {
+				ambiguous = true
+			}
+		}
+		if ambiguous {
+			continue
+		}
+		for l, collider := range colliders {
+			if actual := ColliderContains(collider, c, 0); actual != (l == label) {
+				t.Fatalf("point %v with label %d: containment for %d is %v",
+					c, label, l, actual)
+			}
+		}
+	}
+}
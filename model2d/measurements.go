@@ -14,3 +14,207 @@ func (m *Mesh) Area() float64 {
 	})
 	return math.Abs(result)
 }
+
+// SignedArea computes the area inside of a manifold mesh,
+// which is positive if the normals face outward and
+// negative if they face inward.
+//
+// For a mesh with holes, the holes should have inward
+// facing normals, i.e. normals pointing away from the
+// inside of the shape, in which case they are subtracted
+// from the total.
+func (m *Mesh) SignedArea() float64 {
+	var result float64
+	m.Iterate(func(s *Segment) {
+		result += segmentCross(s)
+	})
+	return result / 2
+}
+
+// Perimeter computes the total length of all the segments
+// in the mesh.
+func (m *Mesh) Perimeter() float64 {
+	var result float64
+	m.Iterate(func(s *Segment) {
+		result += s.Length()
+	})
+	return result
+}
+
+// Centroid computes the center of mass of the area inside
+// of a manifold mesh.
+//
+// This assumes that the normals are consistent.
+func (m *Mesh) Centroid() Coord {
+	return m.Moments().Centroid
+}
+
+// Loops splits a closed, manifold mesh into its loops.
+//
+// Each loop lists its vertices in the order of the
+// segments, without repeating the first vertex at the end.
+func (m *Mesh) Loops() [][]Coord {
+	var result [][]Coord
+	visited := map[*Segment]bool{}
+	m.Iterate(func(s *Segment) {
+		if visited[s] {
+			return
+		}
+		var loop []Coord
+		for cur := s; !visited[cur]; {
+			visited[cur] = true
+			loop = append(loop, cur[0])
+			var next *Segment
+			for _, s1 := range m.Find(cur[1]) {
+				if s1[0] == cur[1] {
+					next = s1
+					break
+				}
+			}
+			if next == nil {
+				panic("mesh is not closed")
+			}
+			cur = next
+		}
+		result = append(result, loop)
+	})
+	return result
+}
+
+// LoopSignedAreas computes the signed area of each loop
+// in a closed, manifold mesh, in the order returned by
+// Loops().
+//
+// Loops with outward-facing normals have positive areas,
+// while holes (with normals facing into them) have
+// negative areas.
+func (m *Mesh) LoopSignedAreas() []float64 {
+	loops := m.Loops()
+	result := make([]float64, len(loops))
+	for i, loop := range loops {
+		for j, c := range loop {
+			result[i] += segmentCross(&Segment{c, loop[(j+1)%len(loop)]}) / 2
+		}
+	}
+	return result
+}
+
+// AreaMoments stores the mass properties of a 2D shape
+// with uniform density.
+type AreaMoments struct {
+	Area     float64
+	Centroid Coord
+
+	// Second moments of area about the centroid.
+	// Ixx is the integral of y^2, Iyy is the integral of
+	// x^2, and Ixy is the integral of x*y, where x and y
+	// are relative to the centroid.
+	Ixx float64
+	Iyy float64
+	Ixy float64
+}
+
+// PolarMoment computes the polar moment of area about the
+// centroid.
+func (a *AreaMoments) PolarMoment() float64 {
+	return a.Ixx + a.Iyy
+}
+
+// PrincipalMoments computes the maximum and minimum second
+// moments of area about any axis through the centroid.
+//
+// The returned angle is the angle of the axis for the
+// maximum moment, relative to the x-axis.
+func (a *AreaMoments) PrincipalMoments() (max, min, angle float64) {
+	mid := (a.Ixx + a.Iyy) / 2
+	radius := math.Sqrt(math.Pow((a.Ixx-a.Iyy)/2, 2) + a.Ixy*a.Ixy)
+	angle = math.Atan2(-2*a.Ixy, a.Ixx-a.Iyy) / 2
+	return mid + radius, mid - radius, angle
+}
+
+// Moments computes the area, centroid, and second moments
+// of area of a manifold mesh with outward-facing normals.
+//
+// This can be used to compute the mass properties of
+// extrusions of the mesh.
+func (m *Mesh) Moments() *AreaMoments {
+	if m.NumSegments() == 0 {
+		return &AreaMoments{}
+	}
+
+	// Compute everything relative to a vertex of the mesh
+	// for numerical stability.
+	origin := m.SegmentSlice()[0][0]
+
+	var area, cx, cy, ixx, iyy, ixy float64
+	m.Iterate(func(s *Segment) {
+		p0, p1 := s[0].Sub(origin), s[1].Sub(origin)
+		cross := p1.X*p0.Y - p0.X*p1.Y
+		area += cross
+		cx += (p0.X + p1.X) * cross
+		cy += (p0.Y + p1.Y) * cross
+		ixx += (p0.Y*p0.Y + p0.Y*p1.Y + p1.Y*p1.Y) * cross
+		iyy += (p0.X*p0.X + p0.X*p1.X + p1.X*p1.X) * cross
+		ixy += (p0.X*p1.Y + 2*p0.X*p0.Y + 2*p1.X*p1.Y + p1.X*p0.Y) * cross
+	})
+	area /= 2
+	if area == 0 {
+		return &AreaMoments{Centroid: origin}
+	}
+	centroid := XY(cx, cy).Scale(1 / (6 * area))
+	return &AreaMoments{
+		Area:     area,
+		Centroid: centroid.Add(origin),
+		Ixx:      ixx/12 - area*centroid.Y*centroid.Y,
+		Iyy:      iyy/12 - area*centroid.X*centroid.X,
+		Ixy:      ixy/24 - area*centroid.X*centroid.Y,
+	}
+}
+
+// SolidMoments approximates the area, centroid, and second
+// moments of area of a solid by sampling it on a grid with
+// the given spacing.
+func SolidMoments(s Solid, delta float64) *AreaMoments {
+	min, max := s.Min(), s.Max()
+	nx := int(math.Ceil((max.X - min.X) / delta))
+	ny := int(math.Ceil((max.Y - min.Y) / delta))
+
+	var count int
+	var sum Coord
+	var sumXX, sumYY, sumXY float64
+	for y := 0; y < ny; y++ {
+		for x := 0; x < nx; x++ {
+			c := XY(float64(x)+0.5, float64(y)+0.5).Scale(delta)
+			if s.Contains(c.Add(min)) {
+				count++
+				sum = sum.Add(c)
+				sumXX += c.X * c.X
+				sumYY += c.Y * c.Y
+				sumXY += c.X * c.Y
+			}
+		}
+	}
+	if count == 0 {
+		return &AreaMoments{Centroid: min.Mid(max)}
+	}
+
+	cellArea := delta * delta
+	mean := sum.Scale(1 / float64(count))
+	n := float64(count)
+	return &AreaMoments{
+		Area:     n * cellArea,
+		Centroid: mean.Add(min),
+
+		// Add the moment of each cell about its own center.
+		Ixx: (sumYY-n*mean.Y*mean.Y)*cellArea + n*cellArea*cellArea/12,
+		Iyy: (sumXX-n*mean.X*mean.X)*cellArea + n*cellArea*cellArea/12,
+		Ixy: (sumXY - n*mean.X*mean.Y) * cellArea,
+	}
+}
+
+// segmentCross computes twice the signed area of the
+// triangle formed by the origin and the segment, which is
+// positive for clockwise segments.
+func segmentCross(s *Segment) float64 {
+	return s[1].X*s[0].Y - s[0].X*s[1].Y
+}
@@ -30,3 +30,80 @@ func TestMeshArea(t *testing.T) {
 		}
 	})
 }
+
+func TestMeshMoments(t *testing.T) {
+	rect := NewMeshRect(XY(1, 2), XY(3, 6))
+	moments := rect.Moments()
+	expected := &AreaMoments{
+		Area:     8,
+		Centroid: XY(2, 4),
+		Ixx:      2 * 64.0 / 12,
+		Iyy:      4 * 8.0 / 12,
+	}
+	if !areaMomentsClose(moments, expected, 1e-8) {
+		t.Errorf("expected %v but got %v", expected, moments)
+	}
+	if p := rect.Perimeter(); math.Abs(p-12) > 1e-8 {
+		t.Errorf("expected perimeter 12 but got %f", p)
+	}
+	if a := rect.SignedArea(); math.Abs(a-8) > 1e-8 {
+		t.Errorf("expected signed area 8 but got %f", a)
+	}
+	if a := rect.InvertNormals().SignedArea(); math.Abs(a+8) > 1e-8 {
+		t.Errorf("expected signed area -8 but got %f", a)
+	}
+
+	// Rotating the shape should preserve the principal
+	// moments, and rotate the principal axis.
+	rotated := rect.Rotate(0.3)
+	max, min, angle := rotated.Moments().PrincipalMoments()
+	if math.Abs(max-expected.Ixx) > 1e-8 || math.Abs(min-expected.Iyy) > 1e-8 {
+		t.Errorf("unexpected principal moments: %f, %f", max, min)
+	}
+	if math.Abs(angle-0.3) > 1e-8 {
+		t.Errorf("expected angle 0.3 but got %f", angle)
+	}
+
+	// A hole should be subtracted from all of the moments.
+	holey := rect.Copy()
+	hole := NewMeshRect(XY(1.5, 3), XY(2.5, 5)).InvertNormals()
+	holey.AddMesh(hole)
+	holeMoments := holey.Moments()
+	expected = &AreaMoments{
+		Area:     6,
+		Centroid: XY(2, 4),
+		Ixx:      2*64.0/12 - 8.0/12,
+		Iyy:      4*8.0/12 - 2.0/12,
+	}
+	if !areaMomentsClose(holeMoments, expected, 1e-8) {
+		t.Errorf("expected %v but got %v", expected, holeMoments)
+	}
+
+	areas := holey.LoopSignedAreas()
+	if len(areas) != 2 {
+		t.Fatalf("expected 2 loops but got %d", len(areas))
+	}
+	if areas[0] < areas[1] {
+		areas[0], areas[1] = areas[1], areas[0]
+	}
+	if math.Abs(areas[0]-8) > 1e-8 || math.Abs(areas[1]+2) > 1e-8 {
+		t.Errorf("unexpected loop areas: %v", areas)
+	}
+}
+
+func TestSolidMoments(t *testing.T) {
+	shape := NewMeshPolar(func(theta float64) float64 {
+		return 1 + 0.3*math.Cos(3*theta)
+	}, 1000).Rotate(0.1).Translate(XY(2, -1))
+	expected := shape.Moments()
+	actual := SolidMoments(NewColliderSolid(MeshToCollider(shape)), 0.005)
+	if !areaMomentsClose(actual, expected, 1e-3) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func areaMomentsClose(a1, a2 *AreaMoments, eps float64) bool {
+	return math.Abs(a1.Area-a2.Area) < eps && a1.Centroid.Dist(a2.Centroid) < eps &&
+		math.Abs(a1.Ixx-a2.Ixx) < eps && math.Abs(a1.Iyy-a2.Iyy) < eps &&
+		math.Abs(a1.Ixy-a2.Ixy) < eps
+}
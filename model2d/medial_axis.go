@@ -1,12 +1,21 @@
 package model2d
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 const (
 	DefaultMedialAxisIters = 32
 	DefaultMedialAxisEps   = 1e-8
 )
 
+// medialAxisMinCos is the maximum cosine of the angle
+// between the boundary directions of two neighboring grid
+// points for the medial axis to be considered to pass
+// between them.
+const medialAxisMinCos = 0.7
+
 // ProjectMedialAxis projects the point c onto the medial
 // axis of the shape defined by SDF p.
 //
@@ -64,3 +73,210 @@ func ProjectMedialAxis(p PointSDF, c Coord, iters int, eps float64) Coord {
 	// crossing the medial axis.
 	return minPoint
 }
+
+// MedialAxis approximates the medial axis (also known as
+// the centerline or skeleton) of a 2D solid.
+//
+// The solid is first converted into a mesh with a grid
+// spacing of delta, and then MeshMedialAxis is used.
+//
+// See MeshMedialAxis for details on the spurLength
+// argument.
+func MedialAxis(s Solid, delta, spurLength float64) *Mesh {
+	return MeshMedialAxis(MarchingSquaresSearch(s, delta, 8), delta, spurLength)
+}
+
+// MeshMedialAxis approximates the medial axis of the
+// shape bounded by a closed mesh.
+//
+// The result is a mesh of connected segments which run
+// along the middle of the shape, which is useful for
+// deriving strokes from filled shapes such as glyphs.
+// The orientation of the resulting segments is arbitrary.
+// The radius of the shape at any point along the axis can
+// be recovered using MeshToSDF(m).
+//
+// The axis is found by sampling the closest boundary
+// point of m on a grid with spacing delta, and detecting
+// where the nearest boundary jumps between neighboring
+// grid points.
+//
+// Small bumps on the boundary create short branches on
+// the medial axis. Branches which end in a free point and
+// are shorter than spurLength are repeatedly pruned, until
+// every remaining spur is at least spurLength long.
+func MeshMedialAxis(m *Mesh, delta, spurLength float64) *Mesh {
+	sdf := MeshToSDF(m)
+	min := m.Min().Sub(XY(delta, delta))
+	max := m.Max().Add(XY(delta, delta))
+	size := max.Sub(min)
+	numX := int(math.Ceil(size.X/delta)) + 1
+	numY := int(math.Ceil(size.Y/delta)) + 1
+
+	type gridSample struct {
+		Point   Coord
+		Closest Coord
+		Inside  bool
+	}
+	samples := make([]gridSample, numX*numY)
+	for y := 0; y < numY; y++ {
+		for x := 0; x < numX; x++ {
+			c := min.Add(XY(float64(x), float64(y)).Scale(delta))
+			closest, dist := sdf.PointSDF(c)
+			samples[x+y*numX] = gridSample{Point: c, Closest: closest, Inside: dist > 0}
+		}
+	}
+
+	// Find the point where the medial axis crosses a grid
+	// edge, if it does at all.
+	edgeCrossing := func(idx1, idx2 int) (Coord, bool) {
+		s1, s2 := samples[idx1], samples[idx2]
+		if !s1.Inside || !s2.Inside {
+			return Coord{}, false
+		}
+		d1 := s1.Point.Sub(s1.Closest).Normalize()
+		d2 := s2.Point.Sub(s2.Closest).Normalize()
+		if d1.Dot(d2) > medialAxisMinCos || s1.Closest.Dist(s2.Closest) < 2*delta {
+			return Coord{}, false
+		}
+
+		// Find the point on the edge which is equidistant to
+		// both closest boundary points.
+		q1, q2 := s1.Closest, s2.Closest
+		edge := s2.Point.Sub(s1.Point)
+		featureDelta := q2.Sub(q1)
+		denom := 2 * edge.Dot(featureDelta)
+		t := 0.5
+		if denom != 0 {
+			t = (q2.Dot(q2) - q1.Dot(q1) - 2*s1.Point.Dot(featureDelta)) / denom
+			t = math.Max(0, math.Min(1, t))
+		}
+		return s1.Point.Add(edge.Scale(t)), true
+	}
+
+	// Horizontal edges are indexed by their left sample, and
+	// vertical edges by their bottom sample.
+	hCrossings := make([]*Coord, numX*numY)
+	vCrossings := make([]*Coord, numX*numY)
+	for y := 0; y < numY; y++ {
+		for x := 0; x < numX; x++ {
+			idx := x + y*numX
+			if x+1 < numX {
+				if c, ok := edgeCrossing(idx, idx+1); ok {
+					hCrossings[idx] = &c
+				}
+			}
+			if y+1 < numY {
+				if c, ok := edgeCrossing(idx, idx+numX); ok {
+					vCrossings[idx] = &c
+				}
+			}
+		}
+	}
+
+	res := NewMesh()
+	for y := 0; y+1 < numY; y++ {
+		for x := 0; x+1 < numX; x++ {
+			idx := x + y*numX
+			var points []Coord
+			for _, c := range []*Coord{
+				hCrossings[idx],
+				hCrossings[idx+numX],
+				vCrossings[idx],
+				vCrossings[idx+1],
+			} {
+				if c != nil {
+					points = append(points, *c)
+				}
+			}
+			if len(points) == 2 {
+				if points[0] != points[1] {
+					res.Add(&Segment{points[0], points[1]})
+				}
+			} else if len(points) > 2 {
+				var center Coord
+				for _, p := range points {
+					center = center.Add(p)
+				}
+				center = center.Scale(1 / float64(len(points)))
+				for _, p := range points {
+					if p != center {
+						res.Add(&Segment{p, center})
+					}
+				}
+			}
+		}
+	}
+
+	pruneMedialAxisSpurs(res, spurLength)
+	return res
+}
+
+// pruneMedialAxisSpurs repeatedly removes the shortest
+// branches of m that connect a free endpoint to a
+// junction, as long as they are shorter than spurLength.
+func pruneMedialAxisSpurs(m *Mesh, spurLength float64) {
+	if spurLength <= 0 {
+		return
+	}
+
+	type medialAxisBranch struct {
+		Segments []*Segment
+		Junction Coord
+		Length   float64
+	}
+
+	for {
+		var branches []*medialAxisBranch
+		m.IterateVertices(func(c Coord) {
+			segs := m.Find(c)
+			if len(segs) != 1 {
+				return
+			}
+			branch := &medialAxisBranch{}
+			prev := c
+			seg := segs[0]
+			for {
+				branch.Segments = append(branch.Segments, seg)
+				branch.Length += seg.Length()
+				next := seg[0]
+				if next == prev {
+					next = seg[1]
+				}
+				neighbors := m.Find(next)
+				if len(neighbors) != 2 || branch.Length >= spurLength {
+					if len(neighbors) > 2 && branch.Length < spurLength {
+						branch.Junction = next
+						branches = append(branches, branch)
+					}
+					return
+				}
+				prev = next
+				if neighbors[0] == seg {
+					seg = neighbors[1]
+				} else {
+					seg = neighbors[0]
+				}
+			}
+		})
+		if len(branches) == 0 {
+			return
+		}
+		sort.Slice(branches, func(i, j int) bool {
+			return branches[i].Length < branches[j].Length
+		})
+		numRemoved := 0
+		for _, branch := range branches {
+			if len(m.Find(branch.Junction)) <= 1 || !m.Contains(branch.Segments[0]) {
+				continue
+			}
+			for _, seg := range branch.Segments {
+				m.Remove(seg)
+			}
+			numRemoved++
+		}
+		if numRemoved == 0 {
+			return
+		}
+	}
+}
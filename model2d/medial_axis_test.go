@@ -0,0 +1,42 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMedialAxis(t *testing.T) {
+	rect := &Rect{MinVal: XY(0, 0), MaxVal: XY(4, 1)}
+
+	t.Run("Pruned", func(t *testing.T) {
+		axis := MedialAxis(rect, 0.02, 1.0)
+		var length float64
+		axis.Iterate(func(s *Segment) {
+			length += s.Length()
+			for _, c := range s {
+				if math.Abs(c.Y-0.5) > 0.03 {
+					t.Errorf("vertex too far from center line: %v", c)
+				}
+			}
+		})
+		if math.Abs(length-3) > 0.1 {
+			t.Errorf("expected length 3 but got %f", length)
+		}
+	})
+
+	t.Run("Unpruned", func(t *testing.T) {
+		axis := MedialAxis(rect, 0.02, 0)
+		var numEndpoints int
+		axis.IterateVertices(func(c Coord) {
+			if len(axis.Find(c)) == 1 {
+				numEndpoints++
+				if math.Min(c.X, 4-c.X) > 0.1 && math.Min(c.Y, 1-c.Y) > 0.1 {
+					t.Errorf("unexpected endpoint far from corners: %v", c)
+				}
+			}
+		})
+		if numEndpoints != 4 {
+			t.Errorf("expected 4 endpoints but got %d", numEndpoints)
+		}
+	})
+}
@@ -31,6 +31,10 @@ import (
 type Mesh struct {
 	faces map[*Segment]bool
 
+	// If true, iteration and slices are sorted.
+	// See SetDeterministic().
+	deterministic bool
+
 	// Stores a *CoordToSlice[*Segment]
 	vertexToFace  atomic.Value
 	v2fCreateLock sync.Mutex
@@ -118,6 +122,7 @@ func (m *Mesh) AddMesh(m1 *Mesh) {
 // segments are the same exact pointers.
 func (m *Mesh) Copy() *Mesh {
 	m1 := NewMesh()
+	m1.deterministic = m.deterministic
 	m1.AddMesh(m)
 	return m1
 }
@@ -126,6 +131,7 @@ func (m *Mesh) Copy() *Mesh {
 // segments are copied individually.
 func (m *Mesh) DeepCopy() *Mesh {
 	m1 := NewMesh()
+	m1.deterministic = m.deterministic
 	m.Iterate(func(f *Segment) {
 		f1 := new(Segment)
 		*f1 = *f
@@ -178,7 +184,8 @@ func (m *Mesh) NumSegments() int {
 }
 
 // Iterate calls f for every segment in m in an arbitrary
-// order.
+// order, or in a sorted order if the mesh is
+// deterministic (see SetDeterministic).
 //
 // If f adds or removes segments, they will not be visited.
 func (m *Mesh) Iterate(f func(*Segment)) {
@@ -202,7 +209,8 @@ func (m *Mesh) IterateSorted(f func(*Segment), cmp func(f1, f2 *Segment) bool) {
 }
 
 // IterateVertices calls f for every vertex in m in an
-// arbitrary order.
+// arbitrary order, or in a sorted order if the mesh is
+// deterministic (see SetDeterministic).
 //
 // If f adds or removes vertices, they will not be
 // visited.
@@ -331,6 +339,53 @@ func (m *Mesh) Rotate(angle float64) *Mesh {
 // MapCoords creates a new mesh by transforming all of the
 // coordinates according to the function f.
 func (m *Mesh) MapCoords(f func(Coord) Coord) *Mesh {
+	mapping := m.coordMapping(f)
+	m1 := NewMesh()
+	m1.deterministic = m.deterministic
+	m.Iterate(func(t *Segment) {
+		t1 := *t
+		for i, p := range t {
+			t1[i] = mapping.Value(p)
+		}
+		m1.Add(&t1)
+	})
+	return m1
+}
+
+// Transform applies t to the coordinates.
+func (m *Mesh) Transform(t Transform) *Mesh {
+	return m.MapCoords(t.Apply)
+}
+
+// MapCoordsInPlace is like MapCoords, but it modifies the
+// segments of m directly instead of creating a new mesh.
+//
+// This avoids duplicating every segment, which can
+// significantly reduce peak memory usage for large meshes.
+// However, any other references to the segments of m
+// will observe the new coordinates.
+func (m *Mesh) MapCoordsInPlace(f func(Coord) Coord) {
+	mapping := m.coordMapping(f)
+
+	// Release the cache before modifying the segments,
+	// since it will be invalid anyway.
+	m.clearVertexToFace()
+
+	for t := range m.faces {
+		for i, p := range t {
+			t[i] = mapping.Value(p)
+		}
+	}
+}
+
+// TransformInPlace is like Transform, but it modifies the
+// segments of m directly. See MapCoordsInPlace.
+func (m *Mesh) TransformInPlace(t Transform) {
+	m.MapCoordsInPlace(t.Apply)
+}
+
+// coordMapping evaluates f once for every vertex in m.
+func (m *Mesh) coordMapping(f func(Coord) Coord) *CoordMap[Coord] {
 	mapping := NewCoordMap[Coord]()
 	if v2f := m.getVertexToFaceOrNil(); v2f != nil {
 		v2f.KeyRange(func(c Coord) bool {
@@ -346,27 +401,14 @@ func (m *Mesh) MapCoords(f func(Coord) Coord) *Mesh {
 			}
 		}
 	}
-	m1 := NewMesh()
-	m.Iterate(func(t *Segment) {
-		t1 := *t
-		for i, p := range t {
-			t1[i] = mapping.Value(p)
-		}
-		m1.Add(&t1)
-	})
-	return m1
-}
-
-// Transform applies t to the coordinates.
-func (m *Mesh) Transform(t Transform) *Mesh {
-	return m.MapCoords(t.Apply)
+	return mapping
 }
 
 // InvertNormals returns a new mesh with every
 // segment oriented in the opposite way.
 func (m *Mesh) InvertNormals() *Mesh {
 	m1 := NewMesh()
-	m1.Iterate(func(f *Segment) {
+	m.Iterate(func(f *Segment) {
 		f1 := *f
 		f1[0], f1[1] = f1[1], f1[0]
 		m1.Add(&f1)
@@ -405,14 +447,48 @@ func (m *Mesh) SavePathSVG(path string) error {
 // SegmentSlice gets a snapshot of all the segments
 // currently in the mesh. The resulting slice is a copy,
 // and will not change as the mesh is updated.
+//
+// If the mesh is deterministic, the segments are sorted
+// by their coordinates.
 func (m *Mesh) SegmentSlice() []*Segment {
 	ts := make([]*Segment, 0, len(m.faces))
 	for t := range m.faces {
 		ts = append(ts, t)
 	}
+	if m.deterministic {
+		sort.Slice(ts, func(i, j int) bool {
+			return lessFace(ts[i], ts[j])
+		})
+	}
 	return ts
 }
 
+// SetDeterministic enables or disables deterministic
+// iteration order for the mesh.
+//
+// By default, Iterate(), SegmentSlice(), and related
+// methods visit segments in an arbitrary order which
+// may change from run to run. When deterministic mode is
+// enabled, segments and vertices are instead visited in
+// order of their coordinates, so that results such as
+// encoded files are reproducible regardless of how the
+// mesh was constructed.
+//
+// Deterministic iteration requires sorting, which costs
+// O(n*log(n)) time for every iteration.
+//
+// Meshes derived from m via Copy(), DeepCopy(), and
+// MapCoords() inherit this setting.
+func (m *Mesh) SetDeterministic(d bool) {
+	m.deterministic = d
+}
+
+// Deterministic returns true if deterministic iteration
+// order is enabled. See SetDeterministic().
+func (m *Mesh) Deterministic() bool {
+	return m.deterministic
+}
+
 // SegmentsSlice is exactly like SegmentSlice(), and is
 // only implemented for backwards-compatibility.
 func (m *Mesh) SegmentsSlice() []*Segment {
@@ -431,6 +507,11 @@ func (m *Mesh) VertexSlice() []Coord {
 		vertices = append(vertices, v)
 		return true
 	})
+	if m.deterministic {
+		sort.Slice(vertices, func(i, j int) bool {
+			return lessCoord(vertices[i], vertices[j])
+		})
+	}
 	return vertices
 }
 
@@ -524,3 +605,25 @@ func uniqueVertices(face *Segment, f func(Coord)) {
 	}
 
 }
+
+// lessFace compares segments lexicographically by
+// their coordinates.
+func lessFace(f1, f2 *Segment) bool {
+	for i, c1 := range f1 {
+		if c1 != f2[i] {
+			return lessCoord(c1, f2[i])
+		}
+	}
+	return false
+}
+
+// lessCoord compares coordinates lexicographically.
+func lessCoord(c1, c2 Coord) bool {
+	a1, a2 := c1.Array(), c2.Array()
+	for i, x := range a1 {
+		if x != a2[i] {
+			return x < a2[i]
+		}
+	}
+	return false
+}
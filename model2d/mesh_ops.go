@@ -295,6 +295,114 @@ func (m *Mesh) Decimate(maxVertices int) *Mesh {
 	return res
 }
 
+// Simplify reduces the number of vertices in the mesh
+// using the Douglas-Peucker algorithm, such that no removed
+// vertex is further than tolerance from the simplified
+// polyline.
+//
+// Vertices which are not touching exactly two segments,
+// or where the segments' orientations are inconsistent,
+// are always preserved. Closed loops which would collapse
+// to fewer than three vertices are left unchanged.
+//
+// This is useful for reducing the size of over-sampled
+// meshes, such as those produced by marching squares.
+func (m *Mesh) Simplify(tolerance float64) *Mesh {
+	isFixed := func(c Coord) bool {
+		segs := m.Find(c)
+		return len(segs) != 2 || (segs[0][0] == c) == (segs[1][0] == c)
+	}
+	nextSeg := func(s *Segment) *Segment {
+		for _, s1 := range m.Find(s[1]) {
+			if s1 != s {
+				return s1
+			}
+		}
+		return nil
+	}
+
+	res := NewMesh()
+	visited := map[*Segment]bool{}
+	addChain := func(points []Coord) {
+		for i := 1; i < len(points); i++ {
+			res.Add(&Segment{points[i-1], points[i]})
+		}
+	}
+
+	// Open chains between fixed vertices.
+	m.Iterate(func(s *Segment) {
+		if visited[s] || !isFixed(s[0]) {
+			return
+		}
+		points := []Coord{s[0], s[1]}
+		visited[s] = true
+		for cur := s; !isFixed(cur[1]); {
+			cur = nextSeg(cur)
+			visited[cur] = true
+			points = append(points, cur[1])
+		}
+		addChain(douglasPeucker(points, tolerance))
+	})
+
+	// Closed loops without any fixed vertices.
+	m.Iterate(func(s *Segment) {
+		if visited[s] {
+			return
+		}
+		points := []Coord{s[0]}
+		for cur := s; !visited[cur]; cur = nextSeg(cur) {
+			visited[cur] = true
+			points = append(points, cur[1])
+		}
+
+		// Split the loop at two extreme points, since both are
+		// likely to be kept by the simplification.
+		farthestFrom := func(c Coord) int {
+			var farthest int
+			var farthestDist float64
+			for i, p := range points {
+				if d := p.SquaredDist(c); d > farthestDist {
+					farthest, farthestDist = i, d
+				}
+			}
+			return farthest
+		}
+		start := farthestFrom(points[0])
+		points = append(append([]Coord{}, points[start:]...), points[1:start+1]...)
+		farthest := farthestFrom(points[0])
+		first := douglasPeucker(points[:farthest+1], tolerance)
+		second := douglasPeucker(points[farthest:], tolerance)
+		simplified := append(first, second[1:]...)
+		if len(simplified) < 4 {
+			addChain(points)
+		} else {
+			addChain(simplified)
+		}
+	})
+
+	return res
+}
+
+func douglasPeucker(points []Coord, tolerance float64) []Coord {
+	if len(points) < 3 {
+		return append([]Coord{}, points...)
+	}
+	seg := Segment{points[0], points[len(points)-1]}
+	var maxIdx int
+	var maxDist float64
+	for i, p := range points[1 : len(points)-1] {
+		if d := seg.Dist(p); d > maxDist {
+			maxIdx, maxDist = i+1, d
+		}
+	}
+	if maxDist <= tolerance {
+		return []Coord{points[0], points[len(points)-1]}
+	}
+	first := douglasPeucker(points[:maxIdx+1], tolerance)
+	second := douglasPeucker(points[maxIdx:], tolerance)
+	return append(first, second[1:]...)
+}
+
 func vertexArea(m *Mesh, c Coord) float64 {
 	n1, n2, ok := vertexNeighbors(m, c)
 	if !ok {
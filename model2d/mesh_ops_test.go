@@ -180,6 +180,52 @@ func TestMeshEliminateColinear(t *testing.T) {
 	}
 }
 
+func TestMeshSimplify(t *testing.T) {
+	t.Run("Rect", func(t *testing.T) {
+		mesh := NewMeshRect(XY(0, 1), XY(2, 3))
+		oldMesh := NewMeshSegments(mesh.SegmentSlice())
+		mesh.Iterate(func(s *Segment) {
+			mp := s.Mid()
+			mesh.Remove(s)
+			mesh.Add(&Segment{s[0], mp})
+			mesh.Add(&Segment{mp, s[1]})
+		})
+		if !meshesEqual(oldMesh, mesh.Simplify(1e-5)) {
+			t.Error("simplified mesh should go back to original")
+		}
+	})
+
+	t.Run("Circle", func(t *testing.T) {
+		mesh := MarchingSquaresSearch(&Circle{Radius: 0.9}, 0.01, 8)
+		for _, tol := range []float64{1e-3, 1e-2} {
+			simple := mesh.Simplify(tol)
+			MustValidateMesh(t, simple, true)
+			if n1, n2 := len(simple.VertexSlice()), len(mesh.VertexSlice()); n1 >= n2/2 {
+				t.Errorf("tol %f: expected fewer vertices but got %d (from %d)", tol, n1, n2)
+			}
+			sdf := MeshToSDF(simple)
+			mesh.IterateVertices(func(c Coord) {
+				if d := math.Abs(sdf.SDF(c)); d > tol+1e-8 {
+					t.Errorf("tol %f: vertex %v is %f from simplified mesh", tol, c, d)
+				}
+			})
+		}
+	})
+
+	t.Run("Open", func(t *testing.T) {
+		mesh := NewMesh()
+		for i := 0; i < 10; i++ {
+			mesh.Add(&Segment{XY(float64(i), 0), XY(float64(i+1), 0)})
+		}
+		mesh.Add(&Segment{XY(10, 0), XY(10, 5)})
+		simple := mesh.Simplify(1e-3)
+		expected := NewMeshSegments([]*Segment{{XY(0, 0), XY(10, 0)}, {XY(10, 0), XY(10, 5)}})
+		if !meshesEqual(simple, expected) {
+			t.Errorf("unexpected result: %v", simple.SegmentSlice())
+		}
+	})
+}
+
 func meshesEqual(m1, m2 *Mesh) bool {
 	seg1 := meshSegmentValues(m1)
 	seg2 := meshSegmentValues(m2)
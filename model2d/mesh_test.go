@@ -32,3 +32,17 @@ func TestNewMeshRect(t *testing.T) {
 	mesh := NewMeshRect(XY(0.2, 0.3), XY(0.25, 0.5))
 	MustValidateMesh(t, mesh, true)
 }
+
+func TestMeshInvertNormals(t *testing.T) {
+	mesh := NewMeshRect(XY(0.2, 0.3), XY(0.25, 0.5))
+	inverted := mesh.InvertNormals()
+	if inverted.NumSegments() != mesh.NumSegments() {
+		t.Fatalf("expected %d segments but got %d", mesh.NumSegments(), inverted.NumSegments())
+	}
+	mesh.Iterate(func(s *Segment) {
+		found := inverted.Find(s[0], s[1])
+		if len(found) != 1 || found[0][0] != s[1] || found[0][1] != s[0] {
+			t.Fatalf("missing inverted segment for %v", s)
+		}
+	})
+}
@@ -0,0 +1,434 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	DefaultOffsetterMiterLimit  = 2.0
+	DefaultOffsetterArcSegments = 32
+)
+
+// OffsetJoin determines how an Offsetter fills the gaps
+// that open up at convex corners when a mesh is offset.
+type OffsetJoin int
+
+const (
+	// OffsetJoinRound fills corners with circular arcs,
+	// producing the set of points exactly at the offset
+	// distance from the original mesh.
+	OffsetJoinRound OffsetJoin = iota
+
+	// OffsetJoinMiter extends the two offset edges until
+	// they meet, falling back to a bevel when the corner
+	// would exceed the miter limit.
+	OffsetJoinMiter
+
+	// OffsetJoinBevel connects the two offset edges with a
+	// single straight segment.
+	OffsetJoinBevel
+)
+
+// OffsetMesh offsets a closed mesh by the given distance
+// using round joins.
+//
+// Positive distances grow the shape outward, and negative
+// distances shrink it inward.
+//
+// For more fine-grained control, use Offsetter.
+func OffsetMesh(m *Mesh, distance float64) *Mesh {
+	return (&Offsetter{}).Offset(m, distance)
+}
+
+// Offsetter computes inward or outward offsets (also
+// known as buffers) of closed 2D meshes.
+//
+// This may be used for kerf compensation, or to produce
+// walls of a fixed thickness around an outline.
+type Offsetter struct {
+	// Join determines how convex corners are filled.
+	Join OffsetJoin
+
+	// MiterLimit is the maximum distance from a corner to
+	// its miter tip, measured as a multiple of the offset
+	// distance. Corners that exceed it are beveled.
+	//
+	// If 0, DefaultOffsetterMiterLimit is used.
+	MiterLimit float64
+
+	// ArcSegments is the number of segments that a full
+	// circle would be divided into for round joins.
+	//
+	// If 0, DefaultOffsetterArcSegments is used.
+	ArcSegments int
+}
+
+// Offset computes the offset of m by the given distance.
+//
+// The mesh m must be manifold and have consistent normals
+// which face outward, as produced by most routines in this
+// package.
+// Positive distances grow the shape outward, and negative
+// distances shrink it inward.
+//
+// Self-intersections created by the offset are resolved,
+// so the resulting mesh may contain more or fewer loops
+// than the original (for example, when thin features
+// vanish during an inward offset).
+func (o *Offsetter) Offset(m *Mesh, distance float64) *Mesh {
+	if distance == 0 {
+		return m.Copy()
+	}
+	if !m.Manifold() {
+		panic("mesh is non-manifold")
+	}
+
+	raw := o.rawOffset(m, distance)
+	pieces := splitOffsetSegments(raw)
+
+	sdf := MeshToSDF(m)
+	epsilon := 1e-12 * (math.Abs(distance) + m.Max().Sub(m.Min()).Norm())
+	regions := newOffsetRegions(raw, epsilon)
+	res := NewMesh()
+	for _, piece := range pieces {
+		if piece.Discard {
+			continue
+		}
+		mid := piece.Segment.Mid()
+		dist := sdf.SDF(mid)
+		if distance > 0 {
+			dist = -dist
+		}
+
+		// A piece is on the boundary of the offset shape if it
+		// is on the correct side of the original mesh, and it
+		// is not covered by the region swept out by any other
+		// part of the offset.
+		if dist > 0 && !regions.Contains(mid, piece.Index) {
+			seg := piece.Segment
+			res.Add(&seg)
+		}
+	}
+	return res
+}
+
+func (o *Offsetter) rawOffset(m *Mesh, distance float64) []offsetSegment {
+	var result []offsetSegment
+	m.Iterate(func(s *Segment) {
+		if s[0] == s[1] {
+			return
+		}
+		n1 := s.Normal()
+		seg := Segment{
+			s[0].Add(n1.Scale(distance)),
+			s[1].Add(n1.Scale(distance)),
+		}
+		result = append(result, offsetSegment{
+			Segment: seg,
+			Region:  []Coord{s[0], s[1], seg[1], seg[0]},
+		})
+
+		next := nextSegment(m, s)
+		if next == nil || next[0] == next[1] {
+			return
+		}
+		n2 := next.Normal()
+		v := s[1]
+		p1 := v.Add(n1.Scale(distance))
+		p2 := v.Add(n2.Scale(distance))
+		if p1 == p2 {
+			return
+		}
+
+		// The corner is convex with respect to the offset when
+		// the offset edges separate rather than overlap.
+		turn := s[1].Sub(s[0]).Dot(n2)
+		if distance < 0 {
+			turn = -turn
+		}
+		if turn <= 0 {
+			// The offset edges overlap; connect them through the
+			// original vertex and let the cleanup step trim them.
+			result = append(result,
+				offsetSegment{Segment: Segment{p1, v}, Discard: true},
+				offsetSegment{Segment: Segment{v, p2}, Discard: true},
+			)
+			return
+		}
+		result = append(result, o.join(v, n1, n2, distance)...)
+	})
+	return result
+}
+
+func (o *Offsetter) join(v, n1, n2 Coord, distance float64) []offsetSegment {
+	p1 := v.Add(n1.Scale(distance))
+	p2 := v.Add(n2.Scale(distance))
+	switch o.Join {
+	case OffsetJoinRound:
+		angle := math.Acos(math.Max(-1, math.Min(1, n1.Dot(n2))))
+		numSegs := int(math.Ceil(angle / (2 * math.Pi) * float64(o.arcSegments())))
+		if numSegs < 1 {
+			numSegs = 1
+		}
+		sign := 1.0
+		if n1.X*n2.Y-n1.Y*n2.X < 0 {
+			sign = -1
+		}
+		step := angle / float64(numSegs)
+		var res []offsetSegment
+		prev := p1
+		for i := 1; i <= numSegs; i++ {
+			next := p2
+			if i < numSegs {
+				theta := sign * step * float64(i)
+				dir := XY(
+					n1.X*math.Cos(theta)-n1.Y*math.Sin(theta),
+					n1.X*math.Sin(theta)+n1.Y*math.Cos(theta),
+				)
+				next = v.Add(dir.Scale(distance))
+			}
+			res = append(res, offsetSegment{
+				Segment: Segment{prev, next},
+				Region:  []Coord{v, prev, next},
+			})
+			prev = next
+		}
+		return res
+	case OffsetJoinMiter:
+		bisector := n1.Add(n2)
+		cosHalf := bisector.Norm() / 2
+		if cosHalf > 0 && 1/cosHalf <= o.miterLimit() {
+			tip := v.Add(bisector.Normalize().Scale(distance / cosHalf))
+			return []offsetSegment{
+				{Segment: Segment{p1, tip}, Region: []Coord{v, p1, tip}},
+				{Segment: Segment{tip, p2}, Region: []Coord{v, tip, p2}},
+			}
+		}
+		fallthrough
+	default:
+		return []offsetSegment{{Segment: Segment{p1, p2}, Region: []Coord{v, p1, p2}}}
+	}
+}
+
+func (o *Offsetter) miterLimit() float64 {
+	if o.MiterLimit == 0 {
+		return DefaultOffsetterMiterLimit
+	}
+	return o.MiterLimit
+}
+
+func (o *Offsetter) arcSegments() int {
+	if o.ArcSegments == 0 {
+		return DefaultOffsetterArcSegments
+	}
+	return o.ArcSegments
+}
+
+// nextSegment finds the segment that starts where s ends.
+func nextSegment(m *Mesh, s *Segment) *Segment {
+	for _, s1 := range m.Find(s[1]) {
+		if s1 != s && s1[0] == s[1] {
+			return s1
+		}
+	}
+	return nil
+}
+
+// An offsetSegment is a segment of a raw offset curve,
+// along with the region between it and the original mesh.
+type offsetSegment struct {
+	Segment Segment
+
+	// Region is a convex polygon swept out by the segment
+	// as it moves away from the original mesh.
+	Region []Coord
+
+	// Discard indicates that the segment should always be
+	// removed.
+	Discard bool
+
+	// Index is the index of the raw segment that this
+	// segment was split from.
+	Index int
+}
+
+// splitOffsetSegments splits the segments at all of their
+// mutual intersection points.
+//
+// Intersection points are computed once and shared, so
+// that the resulting pieces remain connected.
+func splitOffsetSegments(segs []offsetSegment) []offsetSegment {
+	splits := make([][]offsetSplit, len(segs))
+
+	indices := make([]int, len(segs))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return segs[indices[i]].Segment.Min().X < segs[indices[j]].Segment.Min().X
+	})
+	for i, idx1 := range indices {
+		s1 := &segs[idx1].Segment
+		max1 := s1.Max()
+		min1 := s1.Min()
+		for _, idx2 := range indices[i+1:] {
+			s2 := &segs[idx2].Segment
+			min2 := s2.Min()
+			if min2.X > max1.X {
+				break
+			}
+			max2 := s2.Max()
+			if min2.Y > max1.Y || max2.Y < min1.Y {
+				continue
+			}
+			if s1[0] == s2[0] || s1[0] == s2[1] || s1[1] == s2[0] || s1[1] == s2[1] {
+				// Segments sharing an endpoint cannot cross
+				// anywhere else, but rounding errors may say
+				// that they do.
+				continue
+			}
+			t1, t2, ok := segmentIntersection(s1, s2)
+			if !ok {
+				continue
+			}
+			p := s1[0].Add(s1[1].Sub(s1[0]).Scale(t1))
+			splits[idx1] = append(splits[idx1], offsetSplit{T: t1, Point: p})
+			splits[idx2] = append(splits[idx2], offsetSplit{T: t2, Point: p})
+		}
+	}
+
+	var res []offsetSegment
+	for i, seg := range segs {
+		points := splits[i]
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].T < points[j].T
+		})
+		prev := seg.Segment[0]
+		for _, split := range points {
+			if split.Point != prev {
+				piece := seg
+				piece.Index = i
+				piece.Segment = Segment{prev, split.Point}
+				res = append(res, piece)
+				prev = split.Point
+			}
+		}
+		if prev != seg.Segment[1] {
+			piece := seg
+			piece.Index = i
+			piece.Segment = Segment{prev, seg.Segment[1]}
+			res = append(res, piece)
+		}
+	}
+	return res
+}
+
+// offsetRegions is a collection of convex polygons from
+// the raw offset curve, sorted by their minimum x value.
+type offsetRegions struct {
+	Regions  []offsetRegion
+	MaxWidth float64
+	Epsilon  float64
+}
+
+type offsetRegion struct {
+	Index  int
+	Points []Coord
+	Min    Coord
+	Max    Coord
+}
+
+func newOffsetRegions(segs []offsetSegment, epsilon float64) *offsetRegions {
+	res := &offsetRegions{Epsilon: epsilon}
+	for i, seg := range segs {
+		if len(seg.Region) == 0 {
+			continue
+		}
+		points := seg.Region
+		if polygonSignedArea(points) < 0 {
+			points = append([]Coord{}, points...)
+			for i := 0; i < len(points)/2; i++ {
+				points[i], points[len(points)-i-1] = points[len(points)-i-1], points[i]
+			}
+		}
+		r := offsetRegion{Index: i, Points: points, Min: points[0], Max: points[0]}
+		for _, p := range points[1:] {
+			r.Min = r.Min.Min(p)
+			r.Max = r.Max.Max(p)
+		}
+		res.Regions = append(res.Regions, r)
+		res.MaxWidth = math.Max(res.MaxWidth, r.Max.X-r.Min.X)
+	}
+	sort.Slice(res.Regions, func(i, j int) bool {
+		return res.Regions[i].Min.X < res.Regions[j].Min.X
+	})
+	return res
+}
+
+// Contains checks if c is strictly inside of any region
+// except for the region of the given raw segment, which c
+// is expected to lie on the boundary of.
+func (o *offsetRegions) Contains(c Coord, exclude int) bool {
+	start := sort.Search(len(o.Regions), func(i int) bool {
+		return o.Regions[i].Min.X >= c.X-o.MaxWidth
+	})
+	for _, r := range o.Regions[start:] {
+		if r.Min.X > c.X {
+			break
+		}
+		if r.Index == exclude || c.Y <= r.Min.Y || c.Y >= r.Max.Y || c.X >= r.Max.X {
+			continue
+		}
+		inside := true
+		for i, p1 := range r.Points {
+			p2 := r.Points[(i+1)%len(r.Points)]
+			edge := p2.Sub(p1)
+			rel := c.Sub(p1)
+			if edge.X*rel.Y-edge.Y*rel.X <= o.Epsilon*edge.Norm() {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonSignedArea computes the area of a polygon, which
+// is positive if the points are counter-clockwise.
+func polygonSignedArea(points []Coord) float64 {
+	var res float64
+	for i, p1 := range points {
+		p2 := points[(i+1)%len(points)]
+		res += p1.X*p2.Y - p2.X*p1.Y
+	}
+	return res / 2
+}
+
+type offsetSplit struct {
+	T     float64
+	Point Coord
+}
+
+// segmentIntersection finds the point where two segments
+// cross strictly within their interiors, returning the
+// fraction along each segment.
+func segmentIntersection(s1, s2 *Segment) (t1, t2 float64, ok bool) {
+	v1 := s1[1].Sub(s1[0])
+	v2 := s2[1].Sub(s2[0])
+	mat := NewMatrix2Columns(v1, v2.Scale(-1))
+	det := mat.Det()
+	if math.Abs(det) < 1e-12*v1.Norm()*v2.Norm() {
+		return 0, 0, false
+	}
+	sol := mat.Inverse().MulColumn(s2[0].Sub(s1[0]))
+	t1, t2 = sol.X, sol.Y
+	const eps = 1e-10
+	if t1 <= eps || t1 >= 1-eps || t2 <= eps || t2 >= 1-eps {
+		return 0, 0, false
+	}
+	return t1, t2, true
+}
@@ -0,0 +1,114 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOffsetMeshRect(t *testing.T) {
+	mesh := NewMeshRect(XY(0, 0), XY(2, 1))
+
+	t.Run("Round", func(t *testing.T) {
+		offset := (&Offsetter{ArcSegments: 1000}).Offset(mesh, 0.5)
+		MustValidateMesh(t, offset, true)
+		expected := 2*1 + 2*(2+1)*0.5 + math.Pi*0.25
+		if a := offset.Area(); math.Abs(a-expected) > 1e-3 {
+			t.Errorf("expected area %f but got %f", expected, a)
+		}
+	})
+
+	t.Run("Miter", func(t *testing.T) {
+		offset := (&Offsetter{Join: OffsetJoinMiter}).Offset(mesh, 0.5)
+		MustValidateMesh(t, offset, true)
+		if a := offset.Area(); math.Abs(a-3*2) > 1e-8 {
+			t.Errorf("expected area %f but got %f", 6.0, a)
+		}
+		if min, max := offset.Min(), offset.Max(); min.Dist(XY(-0.5, -0.5)) > 1e-8 ||
+			max.Dist(XY(2.5, 1.5)) > 1e-8 {
+			t.Errorf("unexpected bounds: %v, %v", min, max)
+		}
+	})
+
+	t.Run("Bevel", func(t *testing.T) {
+		offset := (&Offsetter{Join: OffsetJoinBevel}).Offset(mesh, 0.5)
+		MustValidateMesh(t, offset, true)
+		expected := 6 - 4*0.5*0.5*0.5
+		if a := offset.Area(); math.Abs(a-expected) > 1e-8 {
+			t.Errorf("expected area %f but got %f", expected, a)
+		}
+	})
+
+	t.Run("Inward", func(t *testing.T) {
+		offset := OffsetMesh(mesh, -0.25)
+		MustValidateMesh(t, offset, true)
+		if a := offset.Area(); math.Abs(a-1.5*0.5) > 1e-8 {
+			t.Errorf("expected area %f but got %f", 0.75, a)
+		}
+	})
+
+	t.Run("Vanish", func(t *testing.T) {
+		offset := OffsetMesh(mesh, -0.6)
+		if n := offset.NumSegments(); n != 0 {
+			t.Errorf("expected empty mesh but got %d segments", n)
+		}
+	})
+}
+
+func TestOffsetMeshConcave(t *testing.T) {
+	// An L-shaped polygon with one concave corner.
+	points := []Coord{XY(0, 0), XY(0, 2), XY(1, 2), XY(1, 1), XY(2, 1), XY(2, 0)}
+	mesh := NewMesh()
+	for i, p := range points {
+		mesh.Add(&Segment{p, points[(i+1)%len(points)]})
+	}
+	MustValidateMesh(t, mesh, true)
+
+	for _, join := range []OffsetJoin{OffsetJoinRound, OffsetJoinMiter} {
+		for _, dist := range []float64{0.1, -0.1, 0.3} {
+			offset := (&Offsetter{Join: join}).Offset(mesh, dist)
+			MustValidateMesh(t, offset, true)
+
+			sdf := MeshToSDF(mesh)
+			offset.Iterate(func(s *Segment) {
+				d := sdf.SDF(s.Mid())
+				if join == OffsetJoinRound && math.Abs(math.Abs(d)-math.Abs(dist)) > 1e-2 {
+					t.Errorf("join %d dist %f: segment %v has distance %f", join, dist, s, d)
+				} else if math.Abs(d) < math.Abs(dist)-1e-2 {
+					t.Errorf("join %d dist %f: segment %v has distance %f", join, dist, s, d)
+				}
+			})
+		}
+	}
+}
+
+func TestOffsetMeshStars(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	for i := 0; i < 300; i++ {
+		n := 3 + rng.Intn(8)
+		radii := make([]float64, n)
+		for j := range radii {
+			radii[j] = 0.3 + rng.Float64()
+		}
+		mesh := NewMeshPolar(func(theta float64) float64 {
+			return radii[int(theta/(2*math.Pi)*float64(n))%n]
+		}, n)
+		sdf := MeshToSDF(mesh)
+		dist := (rng.Float64() - 0.5) * 0.8
+		for _, join := range []OffsetJoin{OffsetJoinRound, OffsetJoinMiter, OffsetJoinBevel} {
+			offset := (&Offsetter{Join: join}).Offset(mesh, dist)
+			if err := ValidateMesh(offset, false); err != nil {
+				t.Errorf("case %d join %d dist %f: %s", i, join, dist, err)
+				continue
+			}
+			if join != OffsetJoinRound {
+				continue
+			}
+			offset.Iterate(func(s *Segment) {
+				if d := math.Abs(sdf.SDF(s.Mid())); math.Abs(d-math.Abs(dist)) > 1e-2 {
+					t.Errorf("case %d dist %f: segment %v has distance %f", i, dist, s, d)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/unixpickle/essentials"
+)
+
+const DefaultPackerGridDivisions = 256
+
+// A Packer arranges 2D parts on rectangular sheets so that
+// they do not overlap, attempting to minimize the amount
+// of material used.
+//
+// This can be used to batch laser-cut parts, such as
+// cross sections of a 3D model.
+//
+// Parts are placed greedily, largest first, at the lowest
+// and then left-most position where they fit. Placement is
+// performed on a grid, so parts may be slightly further
+// apart than necessary.
+type Packer struct {
+	// Width and Height are the dimensions of every sheet.
+	// Each sheet spans from the origin to (Width, Height).
+	Width  float64
+	Height float64
+
+	// Spacing is the minimum distance between two parts,
+	// for example to account for the kerf of a cutter.
+	Spacing float64
+
+	// Rotations is the number of evenly spaced rotations
+	// to try for each part.
+	//
+	// If 0 or 1, parts are never rotated.
+	Rotations int
+
+	// GridSize is the resolution at which parts are placed.
+	//
+	// If 0, the larger sheet dimension is divided into
+	// DefaultPackerGridDivisions cells.
+	GridSize float64
+}
+
+// A PackedPart describes where a Packer placed one part.
+type PackedPart struct {
+	// Index is the index of the part in the list passed to
+	// Pack().
+	Index int
+
+	// Sheet is the index of the sheet the part is on.
+	Sheet int
+
+	// Rotation is the angle, in radians, that the part is
+	// rotated by about the origin.
+	Rotation float64
+
+	// Translation is added to the part after rotation.
+	Translation Coord
+}
+
+// Transform gets the transformation that moves the part
+// into its place on the sheet.
+func (p *PackedPart) Transform() Transform {
+	return JoinedTransform{Rotation(p.Rotation), &Translate{Offset: p.Translation}}
+}
+
+// Pack arranges the parts on as many sheets as needed.
+//
+// Each part must be a closed mesh with outward-facing
+// normals. Parts may be placed inside of holes in other
+// parts.
+//
+// The result contains one entry per part, in the same
+// order as the parts.
+// An error is returned if a part does not fit on an empty
+// sheet.
+func (p *Packer) Pack(parts []*Mesh) ([]*PackedPart, error) {
+	gridSize := p.gridSize()
+	sheetCols := int(math.Floor(p.Width / gridSize))
+	sheetRows := int(math.Floor(p.Height / gridSize))
+
+	rasters := make([][]*packerRaster, len(parts))
+	areas := make([]float64, len(parts))
+	for i, part := range parts {
+		areas[i] = part.Area()
+		numRotations := essentials.MaxInt(1, p.Rotations)
+		for j := 0; j < numRotations; j++ {
+			angle := 2 * math.Pi * float64(j) / float64(numRotations)
+			rasters[i] = append(rasters[i], newPackerRaster(part, angle, gridSize, p.Spacing))
+		}
+	}
+	order := make([]int, len(parts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return areas[order[i]] > areas[order[j]]
+	})
+
+	result := make([]*PackedPart, len(parts))
+	var sheets []*packerSheet
+	for _, idx := range order {
+		placed := false
+		for sheetIdx := 0; sheetIdx <= len(sheets) && !placed; sheetIdx++ {
+			newSheet := sheetIdx == len(sheets)
+			if newSheet {
+				sheets = append(sheets, newPackerSheet(sheetCols, sheetRows))
+			}
+			sheet := sheets[sheetIdx]
+			var bestRaster *packerRaster
+			var bestX, bestY int
+			for _, raster := range rasters[idx] {
+				x, y, ok := sheet.Find(raster)
+				if !ok {
+					continue
+				}
+				if bestRaster == nil || y+raster.Rows < bestY+bestRaster.Rows ||
+					(y+raster.Rows == bestY+bestRaster.Rows && x < bestX) {
+					bestRaster, bestX, bestY = raster, x, y
+				}
+			}
+			if bestRaster == nil {
+				if newSheet {
+					return nil, errors.Errorf("part %d does not fit on an empty sheet", idx)
+				}
+				continue
+			}
+			sheet.Place(bestRaster, bestX, bestY)
+			result[idx] = &PackedPart{
+				Index:    idx,
+				Sheet:    sheetIdx,
+				Rotation: bestRaster.Angle,
+				Translation: XY(float64(bestX), float64(bestY)).Scale(gridSize).
+					Sub(bestRaster.Origin),
+			}
+			placed = true
+		}
+	}
+	return result, nil
+}
+
+func (p *Packer) gridSize() float64 {
+	if p.GridSize != 0 {
+		return p.GridSize
+	}
+	return math.Max(p.Width, p.Height) / DefaultPackerGridDivisions
+}
+
+// packerRun is a horizontal run of cells [Start, End) in
+// a given row.
+type packerRun struct {
+	Row   int
+	Start int
+	End   int
+}
+
+// packerRaster stores the cells covered by a rotated part,
+// relative to the cell containing the part's minimum.
+type packerRaster struct {
+	Angle  float64
+	Origin Coord
+
+	// Rows and Cols are the dimensions of the part itself.
+	Rows int
+	Cols int
+
+	// Body contains the cells touching the part, and Halo
+	// contains the cells within Spacing of the part.
+	Body []packerRun
+	Halo []packerRun
+}
+
+func newPackerRaster(m *Mesh, angle, gridSize, spacing float64) *packerRaster {
+	rotated := m.Rotate(angle)
+	sdf := MeshToSDF(rotated)
+	min, max := rotated.Min(), rotated.Max()
+	size := max.Sub(min)
+	res := &packerRaster{
+		Angle:  angle,
+		Origin: min,
+		Rows:   essentials.MaxInt(1, int(math.Ceil(size.Y/gridSize))),
+		Cols:   essentials.MaxInt(1, int(math.Ceil(size.X/gridSize))),
+	}
+
+	// A cell touches the part if the part is within half
+	// of a diagonal from the cell's center.
+	cellRadius := gridSize * math.Sqrt2 / 2
+	pad := int(math.Ceil(spacing/gridSize)) + 1
+	for row := -pad; row < res.Rows+pad; row++ {
+		bodyStart, haloStart := -1, -1
+		for col := -pad; col <= res.Cols+pad; col++ {
+			var inBody, inHalo bool
+			if col < res.Cols+pad {
+				center := min.Add(XY(float64(col)+0.5, float64(row)+0.5).Scale(gridSize))
+				dist := sdf.SDF(center)
+				inBody = dist >= -cellRadius && row >= 0 && row < res.Rows &&
+					col >= 0 && col < res.Cols
+				inHalo = dist >= -(cellRadius + spacing)
+			}
+			res.Body, bodyStart = packerUpdateRun(res.Body, row, col, bodyStart, inBody)
+			res.Halo, haloStart = packerUpdateRun(res.Halo, row, col, haloStart, inHalo)
+		}
+	}
+	return res
+}
+
+func packerUpdateRun(runs []packerRun, row, col, start int, inside bool) ([]packerRun, int) {
+	if inside && start == -1 {
+		return runs, col
+	} else if !inside && start != -1 {
+		return append(runs, packerRun{Row: row, Start: start, End: col}), -1
+	}
+	return runs, start
+}
+
+// packerSheet tracks the occupied cells of a sheet, with
+// a prefix sum for every row for fast collision checks.
+type packerSheet struct {
+	Cols int
+	Rows int
+
+	occupied [][]bool
+	prefix   [][]int
+}
+
+func newPackerSheet(cols, rows int) *packerSheet {
+	res := &packerSheet{
+		Cols:     cols,
+		Rows:     rows,
+		occupied: make([][]bool, rows),
+		prefix:   make([][]int, rows),
+	}
+	for i := range res.occupied {
+		res.occupied[i] = make([]bool, cols)
+		res.prefix[i] = make([]int, cols+1)
+	}
+	return res
+}
+
+// Find finds the lowest, then left-most, position where
+// the part's body does not overlap any occupied cells.
+func (p *packerSheet) Find(r *packerRaster) (x, y int, ok bool) {
+	for y := 0; y+r.Rows <= p.Rows; y++ {
+		for x := 0; x+r.Cols <= p.Cols; x++ {
+			if p.fits(r, x, y) {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// Place marks the halo of a part as occupied.
+func (p *packerSheet) Place(r *packerRaster, x, y int) {
+	for _, run := range r.Halo {
+		row := run.Row + y
+		if row < 0 || row >= p.Rows {
+			continue
+		}
+		for col := essentials.MaxInt(0, run.Start+x); col < run.End+x && col < p.Cols; col++ {
+			p.occupied[row][col] = true
+		}
+		prefix := p.prefix[row]
+		for col, occ := range p.occupied[row] {
+			prefix[col+1] = prefix[col]
+			if occ {
+				prefix[col+1]++
+			}
+		}
+	}
+}
+
+func (p *packerSheet) fits(r *packerRaster, x, y int) bool {
+	for _, run := range r.Body {
+		row := run.Row + y
+		start, end := run.Start+x, run.End+x
+		if row < 0 || row >= p.Rows || start < 0 || end > p.Cols {
+			return false
+		}
+		if p.prefix[row][end] != p.prefix[row][start] {
+			return false
+		}
+	}
+	return true
+}
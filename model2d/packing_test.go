@@ -0,0 +1,89 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/essentials"
+)
+
+func TestPackerPack(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	var parts []*Mesh
+	for i := 0; i < 30; i++ {
+		if i%2 == 0 {
+			size := XY(rng.Float64()*20+5, rng.Float64()*20+5)
+			parts = append(parts, NewMeshRect(XY(-3, 7), XY(-3, 7).Add(size)))
+		} else {
+			radius := rng.Float64()*10 + 3
+			parts = append(parts, NewMeshPolar(func(theta float64) float64 {
+				return radius
+			}, 50))
+		}
+	}
+	packer := &Packer{Width: 100, Height: 80, Spacing: 1, Rotations: 4}
+	placements, err := packer.Pack(parts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placed := make([]*Mesh, len(parts))
+	numSheets := 0
+	for i, p := range placements {
+		if p.Index != i {
+			t.Fatalf("unexpected index %d at position %d", p.Index, i)
+		}
+		numSheets = essentials.MaxInt(numSheets, p.Sheet+1)
+		placed[i] = parts[i].Transform(p.Transform())
+		min, max := placed[i].Min(), placed[i].Max()
+		if min.X < -1e-8 || min.Y < -1e-8 || max.X > packer.Width+1e-8 ||
+			max.Y > packer.Height+1e-8 {
+			t.Errorf("part %d out of bounds: %v, %v", i, min, max)
+		}
+	}
+	var totalArea float64
+	for _, p := range parts {
+		totalArea += p.Area()
+	}
+	if minSheets := int(math.Ceil(totalArea / (packer.Width * packer.Height))); numSheets > minSheets+1 {
+		t.Errorf("used %d sheets but expected at most %d", numSheets, minSheets+1)
+	}
+
+	for i, p1 := range placed {
+		sdf := MeshToSDF(p1)
+		for j, p2 := range placed {
+			if i == j || placements[i].Sheet != placements[j].Sheet {
+				continue
+			}
+			for _, s := range p2.SegmentSlice() {
+				for _, c := range []Coord{s[0], s.Mid()} {
+					if d := sdf.SDF(c); d > -packer.Spacing+1e-8 {
+						t.Fatalf("parts %d and %d are too close (%f)", i, j, -d)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPackerRotation(t *testing.T) {
+	part := NewMeshRect(XY(0, 0), XY(90, 10))
+	packer := &Packer{Width: 21, Height: 100}
+	if _, err := packer.Pack([]*Mesh{part}); err == nil {
+		t.Error("expected error without rotation")
+	}
+	packer.Rotations = 4
+	placements, err := packer.Pack([]*Mesh{part, part})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range placements {
+		if p.Sheet != 0 {
+			t.Errorf("expected both parts on the first sheet")
+		}
+		if math.Abs(math.Cos(p.Rotation)) > 1e-8 {
+			t.Errorf("expected a vertical rotation but got %f", p.Rotation)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package model2d
+
+// A PathBuilder incrementally constructs a mesh out of
+// lines and curves, similar to the path commands in SVG
+// or PostScript.
+//
+// Every method returns the builder itself, so that calls
+// can be chained:
+//
+//	mesh := NewPathBuilder(0.01).
+//		MoveTo(XY(0, 0)).
+//		LineTo(XY(0, 1)).
+//		ArcTo(XY(1, 1), 0.5, false, true).
+//		LineTo(XY(1, 0)).
+//		Close().
+//		Mesh()
+//
+// Curves are flattened into segments with a maximum error
+// of the builder's tolerance.
+//
+// For closed paths to have outward-facing normals, they
+// should be traced clockwise, as in NewMeshRect.
+type PathBuilder struct {
+	tolerance float64
+	mesh      *Mesh
+
+	start     Coord
+	current   Coord
+	hasPoints bool
+}
+
+// NewPathBuilder creates an empty PathBuilder which
+// flattens curves with the given tolerance.
+func NewPathBuilder(tolerance float64) *PathBuilder {
+	return &PathBuilder{tolerance: tolerance, mesh: NewMesh()}
+}
+
+// Current gets the current point of the path.
+func (p *PathBuilder) Current() Coord {
+	return p.current
+}
+
+// MoveTo starts a new sub-path at the point c.
+func (p *PathBuilder) MoveTo(c Coord) *PathBuilder {
+	p.start = c
+	p.current = c
+	p.hasPoints = true
+	return p
+}
+
+// LineTo adds a straight line from the current point to c.
+func (p *PathBuilder) LineTo(c Coord) *PathBuilder {
+	p.checkStarted()
+	if c != p.current {
+		p.mesh.Add(&Segment{p.current, c})
+	}
+	p.current = c
+	return p
+}
+
+// QuadTo adds a quadratic Bezier curve from the current
+// point to end, using the given control point.
+func (p *PathBuilder) QuadTo(ctrl, end Coord) *PathBuilder {
+	p.checkStarted()
+	return p.polyline(BezierCurve{p.current, ctrl, end}.Flatten(p.tolerance, 0))
+}
+
+// CurveTo adds a cubic Bezier curve from the current point
+// to end, using the given control points.
+func (p *PathBuilder) CurveTo(ctrl1, ctrl2, end Coord) *PathBuilder {
+	p.checkStarted()
+	return p.polyline(BezierCurve{p.current, ctrl1, ctrl2, end}.Flatten(p.tolerance, 0))
+}
+
+// ArcTo adds a circular arc with the given radius from the
+// current point to end.
+//
+// See NewArcPoints for the meaning of the arguments.
+func (p *PathBuilder) ArcTo(end Coord, radius float64, largeArc, clockwise bool) *PathBuilder {
+	p.checkStarted()
+	arc := NewArcPoints(p.current, end, radius, largeArc, clockwise)
+	points := arc.Flatten(p.tolerance)
+	// Snap the endpoint to avoid rounding errors.
+	points[len(points)-1] = end
+	return p.polyline(points)
+}
+
+// SplineTo adds a Catmull-Rom spline from the current
+// point through all of the given points.
+//
+// See CatmullRomCurve for the meaning of alpha.
+func (p *PathBuilder) SplineTo(alpha float64, points ...Coord) *PathBuilder {
+	p.checkStarted()
+	curve := &CatmullRomCurve{
+		Points: append([]Coord{p.current}, points...),
+		Alpha:  alpha,
+	}
+	return p.polyline(curve.Flatten(p.tolerance))
+}
+
+// Close adds a straight line from the current point back
+// to the start of the current sub-path.
+func (p *PathBuilder) Close() *PathBuilder {
+	return p.LineTo(p.start)
+}
+
+// Mesh gets the mesh of all the segments added so far.
+//
+// The returned mesh is a copy, so the builder can continue
+// to be used afterwards.
+func (p *PathBuilder) Mesh() *Mesh {
+	return p.mesh.Copy()
+}
+
+func (p *PathBuilder) polyline(points []Coord) *PathBuilder {
+	for _, c := range points[1:] {
+		p.LineTo(c)
+	}
+	return p
+}
+
+func (p *PathBuilder) checkStarted() {
+	if !p.hasPoints {
+		panic("path must start with MoveTo")
+	}
+}
@@ -0,0 +1,132 @@
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestArcPoints(t *testing.T) {
+	p1 := XY(1, 2)
+	p2 := XY(2, 1)
+	for _, largeArc := range []bool{false, true} {
+		for _, clockwise := range []bool{false, true} {
+			arc := NewArcPoints(p1, p2, 1, largeArc, clockwise)
+			if arc.Eval(0).Dist(p1) > 1e-8 || arc.Eval(1).Dist(p2) > 1e-8 {
+				t.Errorf("large=%v cw=%v: bad endpoints", largeArc, clockwise)
+			}
+			expectedLength := math.Pi / 2
+			if largeArc {
+				expectedLength = 3 * math.Pi / 2
+			}
+			if l := arc.Length(); math.Abs(l-expectedLength) > 1e-8 {
+				t.Errorf("large=%v cw=%v: expected length %f but got %f", largeArc,
+					clockwise, expectedLength, l)
+			}
+			mid := arc.Eval(0.5).Sub(p1)
+			cross := (p2.Sub(p1)).X*mid.Y - (p2.Sub(p1)).Y*mid.X
+			if (cross > 0) != clockwise {
+				t.Errorf("large=%v cw=%v: wrong direction", largeArc, clockwise)
+			}
+		}
+	}
+}
+
+func TestArcFlatten(t *testing.T) {
+	arc := &Arc{Center: XY(1, 2), Radius: 3, StartAngle: 0.5, EndAngle: -2}
+	for _, tol := range []float64{1e-1, 1e-3} {
+		sdf := MeshToSDF(arc.Mesh(tol))
+		for i := 0; i <= 1000; i++ {
+			p := arc.Eval(float64(i) / 1000)
+			if d := math.Abs(sdf.SDF(p)); d > tol+1e-8 {
+				t.Errorf("tol %f: point %v has distance %f", tol, p, d)
+			}
+		}
+	}
+}
+
+func TestBSplineCurve(t *testing.T) {
+	t.Run("Bezier", func(t *testing.T) {
+		// A clamped B-spline with one span is a Bezier curve.
+		points := []Coord{XY(0, 0), XY(1, 2), XY(3, -1), XY(4, 1)}
+		spline := NewBSplineCurve(3, points...)
+		bezier := BezierCurve(points)
+		for i := 0; i <= 10; i++ {
+			x := float64(i) / 10
+			if spline.Eval(x).Dist(bezier.Eval(x)) > 1e-8 {
+				t.Errorf("mismatch at %f: %v vs %v", x, spline.Eval(x), bezier.Eval(x))
+			}
+		}
+	})
+	t.Run("Linear", func(t *testing.T) {
+		points := []Coord{XY(0, 0), XY(1, 2), XY(3, -1), XY(4, 1)}
+		spline := NewBSplineCurve(1, points...)
+		for i, p := range points {
+			x := float64(i) / 3
+			if spline.Eval(x).Dist(p) > 1e-8 {
+				t.Errorf("expected %v at %f but got %v", p, x, spline.Eval(x))
+			}
+		}
+	})
+	t.Run("Flatten", func(t *testing.T) {
+		spline := NewBSplineCurve(3, XY(0, 0), XY(1, 2), XY(3, -1), XY(4, 1), XY(5, 5),
+			XY(2, 3))
+		points := spline.Flatten(1e-3)
+		if points[0] != spline.Points[0] || points[len(points)-1].Dist(XY(2, 3)) > 1e-8 {
+			t.Error("unexpected endpoints")
+		}
+		sdf := MeshToSDF(spline.Mesh(1e-3))
+		for i := 0; i <= 1000; i++ {
+			p := spline.Eval(float64(i) / 1000)
+			if d := math.Abs(sdf.SDF(p)); d > 1e-3+1e-8 {
+				t.Errorf("point %v has distance %f", p, d)
+			}
+		}
+	})
+}
+
+func TestCatmullRomCurve(t *testing.T) {
+	for _, closed := range []bool{false, true} {
+		points := []Coord{XY(0, 0), XY(1, 2), XY(3, -1), XY(4, 1)}
+		if closed {
+			points = []Coord{XY(0, 0), XY(0, 2), XY(3, 2.5), XY(3, 0)}
+		}
+		for _, alpha := range []float64{0, 0.5, 1} {
+			curve := &CatmullRomCurve{Points: points, Alpha: alpha, Closed: closed}
+			n := float64(curve.numSpans())
+			for i, p := range points {
+				if actual := curve.Eval(float64(i) / n); actual.Dist(p) > 1e-8 {
+					t.Errorf("closed=%v alpha=%f: expected %v but got %v", closed, alpha,
+						p, actual)
+				}
+			}
+			mesh := curve.Mesh(1e-3)
+			if closed {
+				MustValidateMesh(t, mesh, false)
+			} else if mesh.NumSegments() == 0 {
+				t.Error("empty mesh")
+			}
+		}
+	}
+}
+
+func TestPathBuilder(t *testing.T) {
+	mesh := NewPathBuilder(1e-4).
+		MoveTo(XY(0, 0)).
+		LineTo(XY(0, 1)).
+		ArcTo(XY(2, 1), 1, false, true).
+		LineTo(XY(2, 0)).
+		Close().
+		Mesh()
+	MustValidateMesh(t, mesh, true)
+	expected := 2 + math.Pi/2
+	if a := mesh.Area(); math.Abs(a-expected) > 1e-3 {
+		t.Errorf("expected area %f but got %f", expected, a)
+	}
+
+	mesh = NewPathBuilder(1e-4).
+		MoveTo(XY(0, 0)).
+		CurveTo(XY(0, 1), XY(1, 1), XY(1, 0)).
+		QuadTo(XY(0.5, -1), XY(0, 0)).
+		Mesh()
+	MustValidateMesh(t, mesh, false)
+}
@@ -0,0 +1,153 @@
+// Generated from templates/poisson_disk.template
+
+package model2d
+
+import (
+	"math"
+	"math/rand"
+)
+
+const DefaultPoissonDiskSamplerAttempts = 30
+
+// A PoissonDiskSampler samples points inside of a Solid
+// such that no two points are closer than a minimum
+// spacing, but the solid is still densely covered.
+//
+// The resulting "blue noise" points are useful for
+// stippling, for seeding Voronoi structures, and for
+// evenly placing supports.
+//
+// Sampling uses Bridson's algorithm, which grows the set
+// of samples outward from seed points.
+type PoissonDiskSampler struct {
+	// Spacing is the minimum distance between samples.
+	Spacing float64
+
+	// Attempts is the number of random candidates tried
+	// around each sample before giving up on growing from
+	// it.
+	//
+	// If 0, DefaultPoissonDiskSamplerAttempts is used.
+	Attempts int
+
+	// SeedAttempts is the number of uniformly random
+	// points to try as new seeds, so that disconnected
+	// parts of the solid are also covered.
+	//
+	// If 0, this is proportional to the number of samples
+	// that could fit in the solid's bounding box.
+	SeedAttempts int
+}
+
+// Sample computes random points inside of s.
+func (p *PoissonDiskSampler) Sample(s Solid) []Coord {
+	if p.Spacing <= 0 {
+		panic("spacing must be positive")
+	}
+	attempts := p.Attempts
+	if attempts == 0 {
+		attempts = DefaultPoissonDiskSamplerAttempts
+	}
+	min, max := s.Min(), s.Max()
+	seedAttempts := p.SeedAttempts
+	if seedAttempts == 0 {
+		cells := 1.0
+		for _, x := range max.Sub(min).Array() {
+			cells *= math.Max(1, x/p.Spacing)
+		}
+		seedAttempts = 4 * int(math.Ceil(cells))
+	}
+
+	grid := newPoissonDiskGrid(p.Spacing)
+	var samples []Coord
+	var active []int
+	tryAdd := func(c Coord) bool {
+		if !InBounds(s, c) || grid.Collides(c) || !s.Contains(c) {
+			return false
+		}
+		grid.Add(c)
+		active = append(active, len(samples))
+		samples = append(samples, c)
+		return true
+	}
+
+	for i := 0; i < seedAttempts; i++ {
+		if !tryAdd(NewCoordRandBounds(min, max)) {
+			continue
+		}
+		for len(active) > 0 {
+			activeIdx := rand.Intn(len(active))
+			center := samples[active[activeIdx]]
+			var added bool
+			for j := 0; j < attempts; j++ {
+				radius := p.Spacing * (1 + rand.Float64())
+				if tryAdd(center.Add(NewCoordRandUnit().Scale(radius))) {
+					added = true
+					break
+				}
+			}
+			if !added {
+				active[activeIdx] = active[len(active)-1]
+				active = active[:len(active)-1]
+			}
+		}
+	}
+	return samples
+}
+
+// poissonDiskGrid is a background grid with cells small
+// enough that each cell contains at most one sample.
+type poissonDiskGrid struct {
+	spacing  float64
+	cellSize float64
+	cells    map[[2]int]Coord
+	offsets  [][2]int
+}
+
+func newPoissonDiskGrid(spacing float64) *poissonDiskGrid {
+	cellSize := spacing / math.Sqrt(2)
+	reach := int(math.Ceil(spacing / cellSize))
+	offsets := [][2]int{{}}
+	for axis := 0; axis < 2; axis++ {
+		var next [][2]int
+		for _, o := range offsets {
+			for i := -reach; i <= reach; i++ {
+				o[axis] = i
+				next = append(next, o)
+			}
+		}
+		offsets = next
+	}
+	return &poissonDiskGrid{
+		spacing:  spacing,
+		cellSize: cellSize,
+		cells:    map[[2]int]Coord{},
+		offsets:  offsets,
+	}
+}
+
+func (p *poissonDiskGrid) Add(c Coord) {
+	p.cells[p.key(c)] = c
+}
+
+func (p *poissonDiskGrid) Collides(c Coord) bool {
+	key := p.key(c)
+	for _, o := range p.offsets {
+		var neighbor [2]int
+		for i := range neighbor {
+			neighbor[i] = key[i] + o[i]
+		}
+		if other, ok := p.cells[neighbor]; ok && other.Dist(c) < p.spacing {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *poissonDiskGrid) key(c Coord) [2]int {
+	var res [2]int
+	for i, x := range c.Array() {
+		res[i] = int(math.Floor(x / p.cellSize))
+	}
+	return res
+}
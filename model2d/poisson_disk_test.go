@@ -0,0 +1,55 @@
+// Generated from templates/poisson_disk_test.template
+
+package model2d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPoissonDiskSampler(t *testing.T) {
+	spacing := 0.1
+	solid := JoinedSolid{
+		&Circle{Radius: 0.5},
+		&Circle{Center: X(2), Radius: 0.3},
+	}
+	sampler := &PoissonDiskSampler{Spacing: spacing}
+	samples := sampler.Sample(solid)
+
+	var numSecond int
+	for i, c := range samples {
+		if !solid.Contains(c) {
+			t.Fatalf("sample %v is not in the solid", c)
+		}
+		if c.X > 1 {
+			numSecond++
+		}
+		for _, c1 := range samples[i+1:] {
+			if d := c.Dist(c1); d < spacing {
+				t.Fatalf("samples are too close: %f", d)
+			}
+		}
+	}
+	if numSecond == 0 || numSecond == len(samples) {
+		t.Errorf("both components should be sampled (got %d of %d)", numSecond,
+			len(samples))
+	}
+
+	// The samples should cover the solid, since candidates
+	// are tried at distances up to twice the spacing.
+	tree := NewCoordTree(samples)
+	var numCovered, numTotal int
+	for i := 0; i < 1000; i++ {
+		c := NewCoordRandBounds(solid.Min(), solid.Max())
+		if !solid.Contains(c) {
+			continue
+		}
+		numTotal++
+		if tree.NearestNeighbor(c).Dist(c) < 2*spacing {
+			numCovered++
+		}
+	}
+	if frac := float64(numCovered) / float64(numTotal); math.Abs(frac-1) > 0.01 {
+		t.Errorf("only %f of the solid is covered", frac)
+	}
+}
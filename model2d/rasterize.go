@@ -169,6 +169,18 @@ type Rasterizer struct {
 	// This can be used to add padding, or have a
 	// consistent canvas when drawing a moving scene.
 	Bounds Bounder
+
+	// Width and Height, if non-zero, specify the exact
+	// size of output images in pixels, overriding Scale.
+	//
+	// The bounds of the rasterized object are stretched to
+	// fill the image, so the aspect ratio of the bounds
+	// should match the aspect ratio of the image to avoid
+	// distortion.
+	// If only one of the two is specified, the other is
+	// chosen to preserve the aspect ratio of the bounds.
+	Width  int
+	Height int
 }
 
 // Rasterize rasterizes a Solid, Mesh, or Collider.
@@ -186,11 +198,8 @@ func (r *Rasterizer) Rasterize(obj any) *image.Gray {
 
 // RasterizeSolid rasterizes a Solid into an image.
 func (r *Rasterizer) RasterizeSolid(s Solid) *image.Gray {
-	scale := r.scale()
-
 	min, max := r.bounds(s)
-	outWidth := int(math.Ceil((max.X - min.X) * scale))
-	outHeight := int(math.Ceil((max.Y - min.Y) * scale))
+	outWidth, outHeight := r.imageSize(min, max)
 	out := image.NewGray(image.Rect(0, 0, outWidth, outHeight))
 
 	pixelWidth := (max.X - min.X) / float64(outWidth)
@@ -227,11 +236,8 @@ func (r *Rasterizer) RasterizeSolid(s Solid) *image.Gray {
 // The exact pattern with which f is called will depend on
 // the image and rasterization parameters.
 func (r *Rasterizer) RasterizeSolidFilter(s Solid, f func(r *Rect) bool) *image.Gray {
-	scale := r.scale()
-
 	min, max := r.bounds(s)
-	outWidth := int(math.Ceil((max.X - min.X) * scale))
-	outHeight := int(math.Ceil((max.Y - min.Y) * scale))
+	outWidth, outHeight := r.imageSize(min, max)
 	out := image.NewGray(image.Rect(0, 0, outWidth, outHeight))
 
 	pixelWidth := (max.X - min.X) / float64(outWidth)
@@ -280,7 +286,8 @@ func (r *Rasterizer) RasterizeSolidFilter(s Solid, f func(r *Rect) bool) *image.
 // RasterizeCollider rasterizes the collider as a line
 // drawing.
 func (r *Rasterizer) RasterizeCollider(c Collider) *image.Gray {
-	extraRadius := 0.5 * r.lineWidth() / r.scale()
+	min, max := r.bounds(c)
+	extraRadius := 0.5 * r.lineWidth() / r.effectiveScale(min, max)
 	solid := NewColliderSolidHollow(c, extraRadius)
 	return r.RasterizeSolidFilter(solid, func(r *Rect) bool {
 		center := r.MinVal.Mid(r.MaxVal)
@@ -308,15 +315,44 @@ func (r *Rasterizer) bounds(b Bounder) (min, max Coord) {
 	}
 }
 
+// imageSize computes the output image dimensions for the
+// given bounds.
+func (r *Rasterizer) imageSize(min, max Coord) (width, height int) {
+	size := max.Sub(min)
+	if r.Width != 0 && r.Height != 0 {
+		return r.Width, r.Height
+	} else if r.Width != 0 {
+		return r.Width, essentials.MaxInt(1, int(math.Round(float64(r.Width)*size.Y/size.X)))
+	} else if r.Height != 0 {
+		return essentials.MaxInt(1, int(math.Round(float64(r.Height)*size.X/size.Y))), r.Height
+	}
+	scale := r.scale()
+	return int(math.Ceil(size.X * scale)), int(math.Ceil(size.Y * scale))
+}
+
+// effectiveScale computes the average number of pixels
+// per unit distance for the given bounds.
+func (r *Rasterizer) effectiveScale(min, max Coord) float64 {
+	if r.Width == 0 && r.Height == 0 {
+		return r.scale()
+	}
+	width, height := r.imageSize(min, max)
+	size := max.Sub(min)
+	return (float64(width)/size.X + float64(height)/size.Y) / 2
+}
+
 func (r *Rasterizer) rasterizePixel(s Solid, min, max Coord) float64 {
 	subsamples := r.subsamples()
-	division := max.Sub(min).Scale(1 / float64(subsamples+1))
+
+	// Sample the center of each sub-pixel, so that samples
+	// are evenly distributed throughout the pixel.
+	division := max.Sub(min).Scale(1 / float64(subsamples))
 	var result float64
 	for x := 0; x < subsamples; x++ {
 		for y := 0; y < subsamples; y++ {
 			c := min
-			c.X += division.X * float64(x)
-			c.Y += division.Y * float64(y)
+			c.X += division.X * (float64(x) + 0.5)
+			c.Y += division.Y * (float64(y) + 0.5)
 			if s.Contains(c) {
 				result += 1
 			}
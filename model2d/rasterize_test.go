@@ -1,6 +1,9 @@
 package model2d
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestRasterizeCollider(t *testing.T) {
 	shape := &Circle{Radius: 40}
@@ -49,3 +52,31 @@ func TestRasterizeColliderSolid(t *testing.T) {
 		}
 	}
 }
+
+func TestRasterizeSolidSize(t *testing.T) {
+	shape := &Circle{Radius: 1}
+	for _, size := range [][2]int{{50, 30}, {40, 0}, {0, 25}} {
+		rast := &Rasterizer{Width: size[0], Height: size[1], Subsamples: 16}
+		img := rast.RasterizeSolid(shape)
+		expectedWidth, expectedHeight := size[0], size[1]
+		if expectedWidth == 0 {
+			expectedWidth = expectedHeight
+		} else if expectedHeight == 0 {
+			expectedHeight = expectedWidth
+		}
+		if img.Bounds().Dx() != expectedWidth || img.Bounds().Dy() != expectedHeight {
+			t.Fatalf("expected size %dx%d but got %v", expectedWidth, expectedHeight,
+				img.Bounds())
+		}
+
+		// Anti-aliased coverage should sum to the area.
+		var coverage float64
+		for _, px := range img.Pix {
+			coverage += 1 - float64(px)/255
+		}
+		pixelArea := 4 / float64(expectedWidth*expectedHeight)
+		if area := coverage * pixelArea; math.Abs(area-math.Pi) > 0.01 {
+			t.Errorf("expected area %f but got %f", math.Pi, area)
+		}
+	}
+}
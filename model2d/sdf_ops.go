@@ -0,0 +1,50 @@
+package model2d
+
+import "math"
+
+// RoundSDF grows an SDF by the given radius, rounding off
+// its convex corners.
+//
+// A negative radius shrinks the SDF instead, rounding off
+// concave corners. In this case, the bounds are not
+// shrunk.
+func RoundSDF(s SDF, radius float64) SDF {
+	min, max := s.Min(), s.Max()
+	if radius > 0 {
+		min = min.Sub(XY(radius, radius))
+		max = max.Add(XY(radius, radius))
+	}
+	return FuncSDF(min, max, func(c Coord) float64 {
+		return s.SDF(c) + radius
+	})
+}
+
+// OnionSDF creates a shell of the given thickness around
+// the boundary of an SDF, centered on the boundary.
+//
+// The shell can be nested for concentric rings, like the
+// layers of an onion:
+//
+//	OnionSDF(OnionSDF(s, 0.2), 0.05)
+func OnionSDF(s SDF, thickness float64) SDF {
+	halfThickness := thickness / 2
+	min := s.Min().Sub(XY(halfThickness, halfThickness))
+	max := s.Max().Add(XY(halfThickness, halfThickness))
+	return FuncSDF(min, max, func(c Coord) float64 {
+		return halfThickness - math.Abs(s.SDF(c))
+	})
+}
+
+// SymmetricDifferenceSDF creates an SDF for the region
+// which is inside exactly one of the two SDFs.
+//
+// As with other boolean operations on SDFs, the result is
+// not necessarily an exact distance everywhere.
+func SymmetricDifferenceSDF(s1, s2 SDF) SDF {
+	min := s1.Min().Min(s2.Min())
+	max := s1.Max().Max(s2.Max())
+	return FuncSDF(min, max, func(c Coord) float64 {
+		d1, d2 := s1.SDF(c), s2.SDF(c)
+		return math.Min(math.Max(d1, d2), -math.Min(d1, d2))
+	})
+}
@@ -54,3 +54,45 @@ func sdfTestingSolid() Solid {
 		},
 	}
 }
+
+func TestSDFOperators(t *testing.T) {
+	circle := &Circle{Center: XY(1, 2), Radius: 1}
+	rect := &Rect{MinVal: XY(0, 0), MaxVal: XY(2, 1)}
+
+	t.Run("Round", func(t *testing.T) {
+		rounded := RoundSDF(rect, 0.3)
+		if rounded.Min().Dist(XY(-0.3, -0.3)) > 1e-8 || rounded.Max().Dist(XY(2.3, 1.3)) > 1e-8 {
+			t.Errorf("unexpected bounds: %v, %v", rounded.Min(), rounded.Max())
+		}
+		// The corner becomes a quarter circle.
+		corner := XY(2, 1).Add(XY(1, 1).Normalize().Scale(0.3))
+		if d := rounded.SDF(corner); math.Abs(d) > 1e-8 {
+			t.Errorf("expected zero SDF at corner but got %f", d)
+		}
+	})
+
+	t.Run("Onion", func(t *testing.T) {
+		onion := OnionSDF(circle, 0.2)
+		for _, r := range []struct {
+			Radius   float64
+			Expected float64
+		}{{1, 0.1}, {1.1, 0}, {0.9, 0}, {0.5, -0.4}, {1.5, -0.4}} {
+			c := circle.Center.Add(X(r.Radius))
+			if d := onion.SDF(c); math.Abs(d-r.Expected) > 1e-8 {
+				t.Errorf("radius %f: expected %f but got %f", r.Radius, r.Expected, d)
+			}
+		}
+	})
+
+	t.Run("SymmetricDifference", func(t *testing.T) {
+		xor := SymmetricDifferenceSDF(circle, rect)
+		for i := 0; i < 1000; i++ {
+			c := NewCoordRandBounds(xor.Min(), xor.Max())
+			expected := circle.Contains(c) != rect.Contains(c)
+			d := xor.SDF(c)
+			if math.Abs(d) > 1e-8 && (d > 0) != expected {
+				t.Fatalf("unexpected SDF %f at %v", d, c)
+			}
+		}
+	})
+}
@@ -341,6 +341,207 @@ func (r *Rect) Expand(delta float64) *Rect {
 	}
 }
 
+// A RoundedRect is a 2D primitive that fills an
+// axis-aligned rectangular space with rounded corners.
+//
+// The rounded rect is bounded by MinVal and MaxVal, and
+// its corners are rounded with the given Radius. The
+// radius should not exceed half of the size of the rect
+// along any axis.
+type RoundedRect struct {
+	MinVal Coord
+	MaxVal Coord
+	Radius float64
+}
+
+// NewRoundedRect creates a RoundedRect with a min value,
+// a max value, and a radius.
+func NewRoundedRect(min, max Coord, radius float64) *RoundedRect {
+	return &RoundedRect{MinVal: min, MaxVal: max, Radius: radius}
+}
+
+// Min yields r.MinVal.
+func (r *RoundedRect) Min() Coord {
+	return r.MinVal
+}
+
+// Max yields r.MaxVal.
+func (r *RoundedRect) Max() Coord {
+	return r.MaxVal
+}
+
+// Contains checks if c is inside of r.
+func (r *RoundedRect) Contains(c Coord) bool {
+	if c.Min(r.MinVal) != r.MinVal || c.Max(r.MaxVal) != r.MaxVal {
+		return false
+	}
+	return r.SDF(c) >= 0
+}
+
+// FirstRayCollision gets the first ray collision with the
+// surface of the rounded rect.
+func (r *RoundedRect) FirstRayCollision(ray *Ray) (RayCollision, bool) {
+	t1, t2, ok := r.rayCollisionScales(ray)
+	if !ok || t2 < 0 {
+		return RayCollision{}, false
+	}
+	t := t1
+	if t < 0 {
+		t = t2
+	}
+	normal, _ := r.NormalSDF(ray.Origin.Add(ray.Direction.Scale(t)))
+	return RayCollision{
+		Scale:  t,
+		Normal: normal,
+		Extra:  r,
+	}, true
+}
+
+// RayCollisions calls f (if non-nil) with each ray
+// collision with the surface of the rounded rect.
+// It returns the number of collisions.
+func (r *RoundedRect) RayCollisions(ray *Ray, f func(RayCollision)) int {
+	t1, t2, ok := r.rayCollisionScales(ray)
+	if !ok || t2 < 0 {
+		return 0
+	}
+
+	var count int
+	for _, t := range []float64{t1, t2} {
+		if t < 0 {
+			continue
+		}
+		count++
+		if f != nil {
+			normal, _ := r.NormalSDF(ray.Origin.Add(ray.Direction.Scale(t)))
+			f(RayCollision{
+				Scale:  t,
+				Normal: normal,
+				Extra:  r,
+			})
+		}
+	}
+	return count
+}
+
+// rayCollisionScales finds the ray scales where the ray
+// enters and exits the rounded rect.
+//
+// Since the shape is convex, the SDF is concave along the
+// ray, so we can search for a point inside the shape and
+// then bisect towards the boundary on either side of it.
+func (r *RoundedRect) rayCollisionScales(ray *Ray) (float64, float64, bool) {
+	tMin, tMax := rayCollisionWithBounds(ray, r.MinVal, r.MaxVal)
+	if tMax < tMin || tMax < 0 {
+		return 0, 0, false
+	}
+	sdf := func(t float64) float64 {
+		return r.SDF(ray.Origin.Add(ray.Direction.Scale(t)))
+	}
+
+	lo, hi := tMin, tMax
+	tInside := math.NaN()
+	for i := 0; i < 100; i++ {
+		t1 := lo + (hi-lo)/3
+		t2 := hi - (hi-lo)/3
+		s1, s2 := sdf(t1), sdf(t2)
+		if s1 >= 0 {
+			tInside = t1
+			break
+		} else if s2 >= 0 {
+			tInside = t2
+			break
+		}
+		if s1 < s2 {
+			lo = t1
+		} else {
+			hi = t2
+		}
+	}
+	if math.IsNaN(tInside) {
+		return 0, 0, false
+	}
+
+	bisect := func(outside, inside float64) float64 {
+		for i := 0; i < 64; i++ {
+			mid := (outside + inside) / 2
+			if sdf(mid) >= 0 {
+				inside = mid
+			} else {
+				outside = mid
+			}
+		}
+		return inside
+	}
+	return bisect(tMin, tInside), bisect(tMax, tInside), true
+}
+
+// CircleCollision checks if a solid circle touches any
+// part of the surface of the rounded rect.
+func (r *RoundedRect) CircleCollision(c Coord, radius float64) bool {
+	return math.Abs(r.SDF(c)) <= radius
+}
+
+// SDF gets the signed distance to the surface of the
+// rounded rect.
+func (r *RoundedRect) SDF(c Coord) float64 {
+	return r.genericSDF(c, nil, nil)
+}
+
+// PointSDF gets the nearest point on the surface of the
+// rounded rect and the corresponding SDF.
+func (r *RoundedRect) PointSDF(c Coord) (Coord, float64) {
+	var p Coord
+	res := r.genericSDF(c, nil, &p)
+	return p, res
+}
+
+// NormalSDF gets the signed distance to the rounded rect
+// and the normal at the closest point on the surface.
+func (r *RoundedRect) NormalSDF(c Coord) (Coord, float64) {
+	var n Coord
+	res := r.genericSDF(c, &n, nil)
+	return n, res
+}
+
+func (r *RoundedRect) genericSDF(c Coord, normalOut, pointOut *Coord) float64 {
+	// The rounded rect contains every point within Radius
+	// of a smaller, inset rect.
+	inner := NewRect(r.MinVal, r.MaxVal).Expand(-r.Radius)
+
+	var normal, point Coord
+	var dist float64
+	if !inner.Contains(c) {
+		point = c.Min(inner.MaxVal).Max(inner.MinVal)
+		diff := c.Sub(point)
+		dist = -diff.Norm()
+		normal = diff.Scale(-1 / dist)
+	} else {
+		dist = inner.genericSDF(c, &normal, &point)
+	}
+	if normalOut != nil {
+		*normalOut = normal
+	}
+	if pointOut != nil {
+		*pointOut = point.Add(normal.Scale(r.Radius))
+	}
+	return dist + r.Radius
+}
+
+// MetaballField returns positive values outside of the
+// surface, and these values increase linearly with
+// distance to the surface.
+func (r *RoundedRect) MetaballField(coord Coord) float64 {
+	return -r.SDF(coord)
+}
+
+// MetaballDistBound returns d always, since the metaball
+// implemented by MetaballField() is defined in terms of
+// standard Euclidean coordinates.
+func (r *RoundedRect) MetaballDistBound(d float64) float64 {
+	return d
+}
+
 // A Capsule is a shape which contains all of the points
 // within a given distance of a line segment.
 type Capsule struct {
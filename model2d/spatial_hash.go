@@ -0,0 +1,372 @@
+// Generated from templates/spatial_hash.template
+
+package model2d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A SpatialHash is a uniform grid over Coords which
+// accelerates point and radius queries.
+//
+// Grid cells are hashed into a fixed number of buckets,
+// and the points of each bucket are stored contiguously,
+// so a query only touches a handful of nearby cells.
+// For points which are roughly uniformly distributed,
+// such as the vertices of a mesh produced by marching
+// cubes, a SpatialHash can answer collision and nearest
+// neighbor queries faster than a CoordTree.
+//
+// Like a CoordTree, a SpatialHash is immutable once it
+// has been created, so it can be used safely from
+// concurrent Goroutines.
+type SpatialHash struct {
+	cellSize float64
+
+	// Points in bucket i are stored in
+	// points[starts[i]:starts[i+1]], and cells[j] is the
+	// grid cell of points[j].
+	starts []int
+	points []Coord
+	cells  [][2]int
+
+	minCell [2]int
+	maxCell [2]int
+}
+
+// NewSpatialHash creates a SpatialHash containing the
+// given points.
+//
+// The cellSize determines the side length of each grid
+// cell. For the best performance, it should be on the
+// order of the typical query radius or spacing between
+// points.
+// If cellSize is 0, it is chosen automatically so that
+// there are roughly four points per cell, which works well
+// for nearest neighbor queries.
+func NewSpatialHash(points []Coord, cellSize float64) *SpatialHash {
+	if cellSize == 0 {
+		cellSize = spatialHashCellSize(points)
+	}
+	numBuckets := 1
+	for numBuckets < len(points) {
+		numBuckets *= 2
+	}
+	res := &SpatialHash{
+		cellSize: cellSize,
+		starts:   make([]int, numBuckets+1),
+		points:   make([]Coord, len(points)),
+		cells:    make([][2]int, len(points)),
+	}
+
+	cells := make([][2]int, len(points))
+	buckets := make([]int, len(points))
+	for i, p := range points {
+		cell := res.cell(p)
+		cells[i] = cell
+		buckets[i] = res.bucket(cell)
+		res.starts[buckets[i]+1]++
+		if i == 0 {
+			res.minCell, res.maxCell = cell, cell
+		} else {
+			for j, k := range cell {
+				res.minCell[j] = essentials.MinInt(res.minCell[j], k)
+				res.maxCell[j] = essentials.MaxInt(res.maxCell[j], k)
+			}
+		}
+	}
+	for i := 1; i < len(res.starts); i++ {
+		res.starts[i] += res.starts[i-1]
+	}
+	offsets := append([]int{}, res.starts[:numBuckets]...)
+	for i, p := range points {
+		idx := offsets[buckets[i]]
+		offsets[buckets[i]]++
+		res.points[idx] = p
+		res.cells[idx] = cells[i]
+	}
+	return res
+}
+
+func spatialHashCellSize(points []Coord) float64 {
+	if len(points) < 2 {
+		return 1
+	}
+	min, max := points[0], points[0]
+	for _, p := range points[1:] {
+		min = min.Min(p)
+		max = max.Max(p)
+	}
+	size := max.Sub(min)
+	volume := 1.0
+	var nonZero int
+	for _, x := range size.Array() {
+		if x > 0 {
+			volume *= x
+			nonZero++
+		}
+	}
+	if nonZero == 0 {
+		return 1
+	}
+	return math.Pow(4*volume/float64(len(points)), 1/float64(nonZero))
+}
+
+// CellSize returns the side length of each grid cell.
+func (s *SpatialHash) CellSize() float64 {
+	return s.cellSize
+}
+
+// Len returns the number of points in the hash.
+func (s *SpatialHash) Len() int {
+	return len(s.points)
+}
+
+// Empty returns true if s contains no points.
+func (s *SpatialHash) Empty() bool {
+	return len(s.points) == 0
+}
+
+// Contains checks if any point in the hash is exactly
+// equal to p.
+func (s *SpatialHash) Contains(p Coord) bool {
+	if len(s.points) == 0 {
+		return false
+	}
+	b := s.bucket(s.cell(p))
+	for _, c := range s.points[s.starts[b]:s.starts[b+1]] {
+		if c == p {
+			return true
+		}
+	}
+	return false
+}
+
+// SphereCollision checks if the sphere centered at point
+// p with radius r contains any points in the hash.
+func (s *SpatialHash) SphereCollision(p Coord, r float64) bool {
+	var found bool
+	s.iterateRadius(p, r, func(c Coord) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// WithinRadius gets all of the points in the hash within
+// a distance r of p, in no particular order.
+func (s *SpatialHash) WithinRadius(p Coord, r float64) []Coord {
+	var res []Coord
+	s.iterateRadius(p, r, func(c Coord) bool {
+		res = append(res, c)
+		return true
+	})
+	return res
+}
+
+// Dist gets the distance from a point p to its nearest
+// neighbor in s.
+func (s *SpatialHash) Dist(p Coord) float64 {
+	return s.NearestNeighbor(p).Dist(p)
+}
+
+// NearestNeighbor gets the closest coordinate to p in the
+// hash.
+//
+// This will panic() if s is empty.
+func (s *SpatialHash) NearestNeighbor(p Coord) Coord {
+	if len(s.points) == 0 {
+		panic("cannot find nearest neighbor in empty hash")
+	}
+	center := s.cell(p)
+
+	// Rings beyond this index contain no points.
+	var maxRing int
+	for i, k := range center {
+		maxRing = essentials.MaxInt(maxRing, s.maxCell[i]-k, k-s.minCell[i])
+	}
+
+	// Find an initial candidate by searching rings of
+	// cells around p until any point is found.
+	var best Coord
+	bestDist := math.Inf(1)
+	numRings := 0
+	for numRings <= maxRing && math.IsInf(bestDist, 1) {
+		s.iterateRing(center, numRings, func(key [2]int) {
+			best, bestDist = s.nearestInBucket(p, key, best, bestDist)
+		})
+		numRings++
+	}
+
+	// Visit the remaining cells which could contain a
+	// closer point than the candidate.
+	r := math.Sqrt(bestDist)
+	minCell := s.cell(p.AddScalar(-r))
+	maxCell := s.cell(p.AddScalar(r))
+	for i := range minCell {
+		minCell[i] = essentials.MaxInt(minCell[i], s.minCell[i])
+		maxCell[i] = essentials.MinInt(maxCell[i], s.maxCell[i])
+	}
+	key := minCell
+	for {
+		if gridCellDist(key, center) >= numRings && s.cellSquaredDist(p, key) < bestDist {
+			best, bestDist = s.nearestInBucket(p, key, best, bestDist)
+		}
+		if !nextGridCell(&key, minCell, maxCell) {
+			break
+		}
+	}
+	return best
+}
+
+func (s *SpatialHash) nearestInBucket(p Coord, key [2]int, best Coord,
+	bestDist float64) (Coord, float64) {
+	b := s.bucket(key)
+	for i := s.starts[b]; i < s.starts[b+1]; i++ {
+		if s.cells[i] != key {
+			continue
+		}
+		if d := s.points[i].SquaredDist(p); d < bestDist {
+			best = s.points[i]
+			bestDist = d
+		}
+	}
+	return best, bestDist
+}
+
+// cellSquaredDist computes the squared distance from p to
+// the closest point in a grid cell.
+func (s *SpatialHash) cellSquaredDist(p Coord, key [2]int) float64 {
+	var res float64
+	for i, x := range p.Array() {
+		cellMin := float64(key[i]) * s.cellSize
+		if x < cellMin {
+			res += (cellMin - x) * (cellMin - x)
+		} else if cellMax := cellMin + s.cellSize; x > cellMax {
+			res += (x - cellMax) * (x - cellMax)
+		}
+	}
+	return res
+}
+
+// Slice gets all of the points in the hash, in no
+// particular order.
+func (s *SpatialHash) Slice() []Coord {
+	return append([]Coord{}, s.points...)
+}
+
+func (s *SpatialHash) cell(p Coord) [2]int {
+	var res [2]int
+	for i, x := range p.Array() {
+		res[i] = int(math.Floor(x / s.cellSize))
+	}
+	return res
+}
+
+func (s *SpatialHash) bucket(cell [2]int) int {
+	h := uint(cell[0])*73856093 ^ uint(cell[1])*19349663
+	return int(h & uint(len(s.starts)-2))
+}
+
+func (s *SpatialHash) iterateRadius(p Coord, r float64, f func(c Coord) bool) {
+	if len(s.points) == 0 {
+		return
+	}
+	minCell := s.cell(p.AddScalar(-r))
+	maxCell := s.cell(p.AddScalar(r))
+	for i := range minCell {
+		minCell[i] = essentials.MaxInt(minCell[i], s.minCell[i])
+		maxCell[i] = essentials.MinInt(maxCell[i], s.maxCell[i])
+		if minCell[i] > maxCell[i] {
+			return
+		}
+	}
+	rSquared := r * r
+	key := minCell
+	for {
+		b := s.bucket(key)
+		for i := s.starts[b]; i < s.starts[b+1]; i++ {
+			// Skip points from other cells in the same bucket
+			// to avoid reporting them more than once.
+			if s.cells[i] != key {
+				continue
+			}
+			if c := s.points[i]; c.SquaredDist(p) <= rSquared && !f(c) {
+				return
+			}
+		}
+		if !nextGridCell(&key, minCell, maxCell) {
+			return
+		}
+	}
+}
+
+// iterateRing calls f for every occupied part of the
+// grid whose Chebyshev distance from center is exactly
+// ring.
+func (s *SpatialHash) iterateRing(center [2]int, ring int, f func(key [2]int)) {
+	var ringMin, ringMax, lo, hi [2]int
+	for i, k := range center {
+		ringMin[i] = k - ring
+		ringMax[i] = k + ring
+		lo[i] = essentials.MaxInt(ringMin[i], s.minCell[i])
+		hi[i] = essentials.MinInt(ringMax[i], s.maxCell[i])
+		if lo[i] > hi[i] {
+			return
+		}
+	}
+	key := lo
+	for {
+		interior := true
+		for i := 1; i < len(key); i++ {
+			if key[i] == ringMin[i] || key[i] == ringMax[i] {
+				interior = false
+				break
+			}
+		}
+		if !interior || key[0] == ringMin[0] || key[0] == ringMax[0] {
+			f(key)
+		}
+		if interior && key[0] < ringMax[0]-1 {
+			// Skip over the inside of the ring.
+			if ringMax[0] <= hi[0] {
+				key[0] = ringMax[0]
+				continue
+			}
+			key[0] = hi[0]
+		}
+		if !nextGridCell(&key, lo, hi) {
+			return
+		}
+	}
+}
+
+// gridCellDist computes the Chebyshev distance between
+// two grid cells.
+func gridCellDist(c1, c2 [2]int) int {
+	var res int
+	for i, k := range c1 {
+		if d := k - c2[i]; d > res {
+			res = d
+		} else if -d > res {
+			res = -d
+		}
+	}
+	return res
+}
+
+// nextGridCell advances key to the next cell in the box
+// between min and max (inclusive), returning false once
+// every cell has been visited.
+func nextGridCell(key *[2]int, min, max [2]int) bool {
+	for i := range key {
+		if key[i] < max[i] {
+			key[i]++
+			return true
+		}
+		key[i] = min[i]
+	}
+	return false
+}
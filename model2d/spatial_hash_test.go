@@ -0,0 +1,103 @@
+// Generated from templates/spatial_hash_test.template
+
+package model2d
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestSpatialHashNearestNeighbor(t *testing.T) {
+	coords := make([]Coord, 1000)
+	for i := range coords {
+		coords[i] = NewCoordRandNorm()
+	}
+	for _, cellSize := range []float64{0, 0.1, 1} {
+		hash := NewSpatialHash(coords, cellSize)
+		if hash.Len() != len(coords) {
+			t.Fatalf("expected %d points but got %d", len(coords), hash.Len())
+		}
+		for _, c := range coords {
+			if !hash.Contains(c) {
+				t.Fatalf("missing coordinate: %v", c)
+			}
+			if hash.NearestNeighbor(c) != c {
+				t.Fatalf("bad neighbor for coordinate: %v", c)
+			}
+		}
+		for i := 0; i < 1000; i++ {
+			p := NewCoordRandNorm().Scale(3)
+			expected := math.Inf(1)
+			for _, c := range coords {
+				expected = math.Min(expected, c.Dist(p))
+			}
+			if actual := hash.Dist(p); actual != expected {
+				t.Fatalf("expected distance %f but got %f", expected, actual)
+			}
+		}
+	}
+}
+
+func TestSpatialHashRadius(t *testing.T) {
+	coords := make([]Coord, 1000)
+	for i := range coords {
+		coords[i] = NewCoordRandNorm()
+	}
+	hash := NewSpatialHash(coords, 0.1)
+	for i := 0; i < 1000; i++ {
+		p := NewCoordRandNorm()
+		r := math.Abs(NewCoordRandNorm().X) * 0.3
+		var expected []Coord
+		for _, c := range coords {
+			if c.Dist(p) <= r {
+				expected = append(expected, c)
+			}
+		}
+		if actual := hash.SphereCollision(p, r); actual != (len(expected) > 0) {
+			t.Fatalf("expected collision %v but got %v", len(expected) > 0, actual)
+		}
+		actual := hash.WithinRadius(p, r)
+		if len(actual) != len(expected) {
+			t.Fatalf("expected %d points but got %d", len(expected), len(actual))
+		}
+		sortCoords := func(cs []Coord) {
+			sort.Slice(cs, func(i, j int) bool {
+				return cs[i].X < cs[j].X
+			})
+		}
+		sortCoords(expected)
+		sortCoords(actual)
+		for i, c := range expected {
+			if actual[i] != c {
+				t.Fatalf("unexpected point %v (expected %v)", actual[i], c)
+			}
+		}
+	}
+}
+
+func BenchmarkSpatialHashNearestNeighbor(b *testing.B) {
+	coords := make([]Coord, 100000)
+	for i := range coords {
+		coords[i] = NewCoordRandUniform()
+	}
+	queries := make([]Coord, 1000)
+	for i := range queries {
+		queries[i] = NewCoordRandUniform()
+	}
+
+	b.Run("CoordTree", func(b *testing.B) {
+		tree := NewCoordTree(coords)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tree.NearestNeighbor(queries[i%len(queries)])
+		}
+	})
+	b.Run("SpatialHash", func(b *testing.B) {
+		hash := NewSpatialHash(coords, 0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			hash.NearestNeighbor(queries[i%len(queries)])
+		}
+	})
+}
@@ -0,0 +1,357 @@
+package model2d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+const (
+	DefaultSplineFitterRelDeviation = 1e-3
+	DefaultSplineFitterSmoothness   = 1e-4
+	DefaultSplineFitterMinSpans     = 4
+)
+
+// A SplineFitter fits smooth cubic B-splines to noisy
+// polylines, such as outlines traced from bitmaps.
+//
+// Curves are fit with regularized least squares, and knots
+// are inserted where the curve deviates too much from the
+// points, until every point is within the maximum
+// deviation of the curve.
+//
+// Unlike Mesh.SmoothSq(), this does not shrink shapes, and
+// the resulting curves can be flattened at any resolution.
+type SplineFitter struct {
+	// MaxDeviation is the maximum allowed distance from
+	// each point to the fit curve.
+	//
+	// If 0, DefaultSplineFitterRelDeviation times the
+	// diagonal of the bounding box of the points is used.
+	MaxDeviation float64
+
+	// Smoothness is the weight of a penalty on the bending
+	// of the control polygon, relative to the fitting
+	// error.
+	//
+	// If 0, DefaultSplineFitterSmoothness is used.
+	Smoothness float64
+
+	// MinSpans is the initial number of polynomial spans
+	// in each curve.
+	//
+	// If 0, DefaultSplineFitterMinSpans is used.
+	MinSpans int
+
+	// MaxSpans, if non-zero, limits the number of spans in
+	// each curve, even if this causes the curve to exceed
+	// MaxDeviation.
+	//
+	// If 0, the number of spans is limited to the number
+	// of points.
+	MaxSpans int
+}
+
+// Fit fits a cubic B-spline to the sequence of points.
+//
+// If closed is true, the points are treated as a loop, and
+// the resulting curve is periodic, ending where it starts
+// with continuous curvature.
+// Otherwise, the curve starts and ends exactly at the
+// first and last points.
+func (s *SplineFitter) Fit(points []Coord, closed bool) *BSplineCurve {
+	if closed && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 2 {
+		panic("need at least two points to fit a spline")
+	}
+
+	params := splineFitParams(points, closed)
+	maxDev := s.maxDeviation(points)
+	maxSpans := s.MaxSpans
+	if maxSpans == 0 {
+		maxSpans = len(points)
+	}
+
+	numSpans := s.MinSpans
+	if numSpans == 0 {
+		numSpans = DefaultSplineFitterMinSpans
+	}
+	if closed && numSpans < 3 {
+		// A periodic cubic needs at least three control
+		// points to avoid degeneracy.
+		numSpans = 3
+	}
+	breaks := make([]float64, numSpans+1)
+	for i := range breaks {
+		breaks[i] = float64(i) / float64(numSpans)
+	}
+
+	for {
+		curve := s.fitKnots(points, params, breaks, closed)
+
+		// Find spans containing points that deviate too far.
+		splitSpans := map[int]bool{}
+		for i, p := range points {
+			if curve.Eval(params[i]).Dist(p) > maxDev {
+				span := sort.SearchFloat64s(breaks, params[i]) - 1
+				if span < 0 {
+					span = 0
+				} else if span >= len(breaks)-1 {
+					span = len(breaks) - 2
+				}
+				splitSpans[span] = true
+			}
+		}
+		if len(splitSpans) == 0 || len(breaks)-1 >= maxSpans {
+			return curve
+		}
+		newBreaks := make([]float64, 0, len(breaks)+len(splitSpans))
+		for i, b := range breaks {
+			newBreaks = append(newBreaks, b)
+			if splitSpans[i] && len(newBreaks)+len(breaks)-i-1 <= maxSpans+1 {
+				newBreaks = append(newBreaks, (b+breaks[i+1])/2)
+			}
+		}
+		breaks = newBreaks
+	}
+}
+
+// FitMesh fits a spline to every loop of a closed,
+// manifold mesh, and flattens the result into a new mesh.
+//
+// The curves are flattened with a tolerance of a quarter
+// of the maximum deviation.
+//
+// The result is deterministic: each loop is fit starting
+// from its minimum vertex (ordered by x, then y), so it
+// does not depend on the mesh's internal ordering.
+func (s *SplineFitter) FitMesh(m *Mesh) *Mesh {
+	loops := m.Loops()
+	for i, loop := range loops {
+		loops[i] = rotateLoopToMin(loop)
+	}
+	sort.Slice(loops, func(i, j int) bool {
+		return coordLess(loops[i][0], loops[j][0])
+	})
+
+	res := NewMesh()
+	for _, loop := range loops {
+		if len(loop) < 3 {
+			res.AddMesh(PolylineMesh(loop, true))
+			continue
+		}
+		curve := s.Fit(loop, true)
+		points := curve.Flatten(s.maxDeviation(loop) / 4)
+		res.AddMesh(PolylineMesh(points[:len(points)-1], true))
+	}
+	return res
+}
+
+// rotateLoopToMin rotates a loop so that it starts at its
+// minimum vertex.
+func rotateLoopToMin(loop []Coord) []Coord {
+	var minIdx int
+	for i, c := range loop {
+		if coordLess(c, loop[minIdx]) {
+			minIdx = i
+		}
+	}
+	return append(append([]Coord{}, loop[minIdx:]...), loop[:minIdx]...)
+}
+
+func coordLess(c1, c2 Coord) bool {
+	return c1.X < c2.X || (c1.X == c2.X && c1.Y < c2.Y)
+}
+
+func (s *SplineFitter) maxDeviation(points []Coord) float64 {
+	if s.MaxDeviation != 0 {
+		return s.MaxDeviation
+	}
+	min, max := points[0], points[0]
+	for _, p := range points {
+		min = min.Min(p)
+		max = max.Max(p)
+	}
+	return DefaultSplineFitterRelDeviation * max.Dist(min)
+}
+
+func (s *SplineFitter) smoothness() float64 {
+	if s.Smoothness == 0 {
+		return DefaultSplineFitterSmoothness
+	}
+	return s.Smoothness
+}
+
+// fitKnots solves for the control points of a spline with
+// the given breakpoints.
+func (s *SplineFitter) fitKnots(points []Coord, params, breaks []float64,
+	closed bool) *BSplineCurve {
+	const degree = 3
+	numSpans := len(breaks) - 1
+
+	// Create the full knot vector. Closed curves wrap the
+	// first control points around to the end.
+	var knots []float64
+	var numControl, numFree int
+	if closed {
+		numFree = numSpans
+		numControl = numSpans + degree
+		for i := -degree; i <= numSpans+degree; i++ {
+			wraps := math.Floor(float64(i) / float64(numSpans))
+			idx := i - int(wraps)*numSpans
+			knots = append(knots, breaks[idx]+wraps)
+		}
+	} else {
+		numFree = numSpans + degree
+		numControl = numFree
+		for i := 0; i < degree; i++ {
+			knots = append(knots, 0)
+		}
+		knots = append(knots, breaks...)
+		for i := 0; i < degree; i++ {
+			knots = append(knots, 1)
+		}
+	}
+	controlIndex := func(i int) int {
+		return i % numFree
+	}
+
+	// Accumulate the normal equations.
+	entries := map[[2]int]float64{}
+	rhs := make([]numerical.Vec2, numFree)
+	for i, p := range points {
+		span, basis := bsplineBasis(knots, degree, numControl, params[i])
+		for j, b1 := range basis {
+			idx1 := controlIndex(span - degree + j)
+			rhs[idx1] = rhs[idx1].Add(numerical.Vec2(p.Array()).Scale(b1))
+			for k, b2 := range basis {
+				idx2 := controlIndex(span - degree + k)
+				entries[[2]int{idx1, idx2}] += b1 * b2
+			}
+		}
+	}
+
+	// Penalize second differences of control points.
+	lambda := s.smoothness() * float64(len(points)) / float64(numFree)
+	lambda = math.Max(lambda, 1e-8)
+	numDiffs := numFree - 2
+	if closed {
+		numDiffs = numFree
+	}
+	for i := 0; i < numDiffs; i++ {
+		idxs := [3]int{i, controlIndex(i + 1), controlIndex(i + 2)}
+		coeffs := [3]float64{1, -2, 1}
+		for j, idx1 := range idxs {
+			for k, idx2 := range idxs {
+				entries[[2]int{idx1, idx2}] += lambda * coeffs[j] * coeffs[k]
+			}
+		}
+	}
+
+	// Pin the endpoints of open curves.
+	fixed := map[int]Coord{}
+	if !closed {
+		fixed[0] = points[0]
+		fixed[numFree-1] = points[len(points)-1]
+	}
+
+	// Visit entries in a fixed order so that the solution
+	// does not depend on map iteration order.
+	keys := make([][2]int, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+
+	for _, key := range keys {
+		if value, ok := fixed[key[1]]; ok && key[0] != key[1] {
+			rhs[key[0]] = rhs[key[0]].Sub(numerical.Vec2(value.Array()).Scale(entries[key]))
+		}
+	}
+	for key := range entries {
+		_, fixed1 := fixed[key[0]]
+		_, fixed2 := fixed[key[1]]
+		if fixed1 || fixed2 {
+			delete(entries, key)
+		}
+	}
+	for idx, value := range fixed {
+		entries[[2]int{idx, idx}] = 1
+		rhs[idx] = numerical.Vec2(value.Array())
+	}
+
+	mat := numerical.NewSparseMatrix(numFree)
+	for _, key := range keys {
+		if x, ok := entries[key]; ok {
+			mat.Set(key[0], key[1], x)
+		}
+	}
+	solution := numerical.NewSparseCholesky(mat).ApplyInverseVec2(rhs)
+
+	control := make([]Coord, numControl)
+	for i := range control {
+		control[i] = NewCoordArray(solution[controlIndex(i)])
+	}
+	return &BSplineCurve{Degree: degree, Points: control, Knots: knots}
+}
+
+// splineFitParams assigns a parameter in [0, 1] to each
+// point using the chord length.
+func splineFitParams(points []Coord, closed bool) []float64 {
+	params := make([]float64, len(points))
+	var total float64
+	for i := 1; i < len(points); i++ {
+		total += points[i].Dist(points[i-1])
+		params[i] = total
+	}
+	if closed {
+		total += points[0].Dist(points[len(points)-1])
+	}
+	if total == 0 {
+		panic("cannot fit spline to coincident points")
+	}
+	for i := range params {
+		params[i] /= total
+	}
+	return params
+}
+
+// bsplineBasis computes the non-zero basis functions of a
+// B-spline at x, returning the index of the knot span and
+// the values of the degree+1 basis functions for the
+// control points starting at span-degree.
+func bsplineBasis(knots []float64, degree, numControl int, x float64) (int, []float64) {
+	// Find the knot span, clamping to the valid range.
+	span := sort.Search(numControl-degree, func(i int) bool {
+		return knots[degree+i+1] > x
+	}) + degree
+	if span >= numControl {
+		span = numControl - 1
+	}
+
+	basis := make([]float64, degree+1)
+	left := make([]float64, degree+1)
+	right := make([]float64, degree+1)
+	basis[0] = 1
+	for j := 1; j <= degree; j++ {
+		left[j] = x - knots[span+1-j]
+		right[j] = knots[span+j] - x
+		saved := 0.0
+		for r := 0; r < j; r++ {
+			denom := right[r+1] + left[j-r]
+			temp := 0.0
+			if denom != 0 {
+				temp = basis[r] / denom
+			}
+			basis[r] = saved + right[r+1]*temp
+			saved = left[j-r] * temp
+		}
+		basis[j] = saved
+	}
+	return span, basis
+}
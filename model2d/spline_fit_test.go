@@ -0,0 +1,84 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSplineFitterClosed(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	var points []Coord
+	for i := 0; i < 500; i++ {
+		theta := 2 * math.Pi * float64(i) / 500
+		noise := XY(rng.NormFloat64(), rng.NormFloat64()).Scale(0.005)
+		points = append(points, NewCoordPolar(theta, 1).Add(noise))
+	}
+	fitter := &SplineFitter{MaxDeviation: 0.03}
+	curve := fitter.Fit(points, true)
+	if n := len(curve.Points); n > 50 {
+		t.Errorf("too many control points: %d", n)
+	}
+	if d := curve.Eval(0).Dist(curve.Eval(1)); d > 1e-8 {
+		t.Errorf("curve is not closed: distance %f", d)
+	}
+	for _, p := range curve.Flatten(1e-3) {
+		if math.Abs(p.Norm()-1) > 0.03 {
+			t.Fatalf("point %v is too far from circle", p)
+		}
+	}
+}
+
+func TestSplineFitterOpen(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	var points []Coord
+	for i := 0; i <= 300; i++ {
+		x := 4 * float64(i) / 300
+		points = append(points, XY(x, math.Sin(x)+rng.NormFloat64()*0.002))
+	}
+	fitter := &SplineFitter{MaxDeviation: 0.01}
+	curve := fitter.Fit(points, false)
+	if curve.Eval(0).Dist(points[0]) > 1e-8 || curve.Eval(1).Dist(points[300]) > 1e-8 {
+		t.Error("curve does not start and end at the endpoints")
+	}
+	sdf := MeshToSDF(curve.Mesh(1e-4))
+	for i, p := range points {
+		if d := math.Abs(sdf.SDF(p)); d > 0.01 {
+			t.Fatalf("point %d deviates by %f", i, d)
+		}
+	}
+}
+
+func TestSplineFitterFitMesh(t *testing.T) {
+	// Avoid a radius where the circle is tangent to the grid,
+	// since the traced outline spikes at such points.
+	circle := &Circle{Radius: 20.3}
+	traced := MarchingSquares(circle, 1)
+	fitter := &SplineFitter{MaxDeviation: 0.5}
+	smoothed := fitter.FitMesh(traced)
+	MustValidateMesh(t, smoothed, true)
+
+	expectedArea := math.Pi * 20.3 * 20.3
+	if a := smoothed.Area(); math.Abs(a-expectedArea) > 0.01*expectedArea {
+		t.Errorf("expected area %f but got %f", expectedArea, a)
+	}
+	smoothed.IterateVertices(func(c Coord) {
+		if math.Abs(c.Norm()-20.3) > 0.6 {
+			t.Fatalf("vertex %v too far from circle", c)
+		}
+	})
+
+	// Map iteration order should not affect the result.
+	for i := 0; i < 5; i++ {
+		other := fitter.FitMesh(traced)
+		if other.NumSegments() != smoothed.NumSegments() {
+			t.Fatalf("expected %d segments but got %d", smoothed.NumSegments(),
+				other.NumSegments())
+		}
+		other.Iterate(func(s *Segment) {
+			if len(smoothed.Find(s[0], s[1])) != 1 {
+				t.Fatalf("segment %v not found in first result", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package model2d
+
+import "math"
+
+// A BSplineCurve is a B-spline with arbitrary degree and
+// knot vector.
+//
+// If Knots is nil, a clamped uniform knot vector is used,
+// so that the curve starts at the first control point and
+// ends at the last one.
+// Otherwise, Knots must contain len(Points)+Degree+1
+// non-decreasing values.
+//
+// The parameter t in [0, 1] is mapped linearly onto the
+// valid range of the knot vector.
+type BSplineCurve struct {
+	Degree int
+	Points []Coord
+	Knots  []float64
+}
+
+// NewBSplineCurve creates a clamped uniform B-spline with
+// the given degree and control points.
+func NewBSplineCurve(degree int, points ...Coord) *BSplineCurve {
+	if degree < 1 {
+		panic("degree must be at least 1")
+	} else if len(points) <= degree {
+		panic("need more control points than the degree")
+	}
+	return &BSplineCurve{Degree: degree, Points: points}
+}
+
+// Eval evaluates the curve at time t, where 0 <= t <= 1.
+func (b *BSplineCurve) Eval(t float64) Coord {
+	knots := b.knots()
+	p := b.Degree
+	minT := knots[p]
+	maxT := knots[len(b.Points)]
+	x := minT + math.Max(0, math.Min(1, t))*(maxT-minT)
+
+	// Find the knot span containing x.
+	span := p
+	for span < len(b.Points)-1 && knots[span+1] <= x {
+		span++
+	}
+
+	// De Boor's algorithm.
+	d := make([]Coord, p+1)
+	for j := range d {
+		d[j] = b.Points[j+span-p]
+	}
+	for r := 1; r <= p; r++ {
+		for j := p; j >= r; j-- {
+			i := j + span - p
+			denom := knots[i+1+p-r] - knots[i]
+			alpha := 0.0
+			if denom != 0 {
+				alpha = (x - knots[i]) / denom
+			}
+			d[j] = d[j-1].Scale(1 - alpha).Add(d[j].Scale(alpha))
+		}
+	}
+	return d[p]
+}
+
+// Flatten approximates the curve as a polyline whose
+// points are within tol of the true curve.
+//
+// See CurveFlatten for details.
+func (b *BSplineCurve) Flatten(tol float64) []Coord {
+	return CurveFlatten(b, tol, minSplitsForSegments(len(b.Points)-b.Degree), 0)
+}
+
+// Mesh creates a mesh approximating the curve within the
+// given tolerance, using Flatten.
+func (b *BSplineCurve) Mesh(tol float64) *Mesh {
+	return PolylineMesh(b.Flatten(tol), false)
+}
+
+func (b *BSplineCurve) knots() []float64 {
+	if b.Knots != nil {
+		if len(b.Knots) != len(b.Points)+b.Degree+1 {
+			panic("invalid number of knots")
+		}
+		return b.Knots
+	}
+	n := len(b.Points)
+	p := b.Degree
+	knots := make([]float64, n+p+1)
+	for i := range knots {
+		if i <= p {
+			knots[i] = 0
+		} else if i >= n {
+			knots[i] = float64(n - p)
+		} else {
+			knots[i] = float64(i - p)
+		}
+	}
+	return knots
+}
+
+// A CatmullRomCurve is a cubic spline which passes through
+// all of its points.
+//
+// The Alpha parameter determines the knot parameterization.
+// An Alpha of 0 gives a uniform spline, 0.5 gives a
+// centripetal spline (which avoids cusps and
+// self-intersections), and 1 gives a chordal spline.
+//
+// If Closed is false, the curve starts at the first point
+// and ends at the last point, using reflected points to
+// define the end tangents.
+// If Closed is true, the curve loops from the last point
+// back to the first.
+//
+// Each span between consecutive points consumes an equal
+// fraction of t.
+type CatmullRomCurve struct {
+	Points []Coord
+	Alpha  float64
+	Closed bool
+}
+
+// Eval evaluates the curve at time t, where 0 <= t <= 1.
+func (c *CatmullRomCurve) Eval(t float64) Coord {
+	n := c.numSpans()
+	if n < 1 {
+		panic("need at least two points")
+	}
+	spanIdx := int(t * float64(n))
+	if spanIdx >= n {
+		spanIdx = n - 1
+	} else if spanIdx < 0 {
+		spanIdx = 0
+	}
+	subT := t*float64(n) - float64(spanIdx)
+
+	p0, p1, p2, p3 := c.point(spanIdx-1), c.point(spanIdx), c.point(spanIdx+1),
+		c.point(spanIdx+2)
+
+	// Barry and Goldman's pyramidal formulation.
+	t0 := 0.0
+	t1 := t0 + c.knotDelta(p0, p1)
+	t2 := t1 + c.knotDelta(p1, p2)
+	t3 := t2 + c.knotDelta(p2, p3)
+	x := t1 + subT*(t2-t1)
+
+	lerp := func(a, b Coord, ta, tb float64) Coord {
+		if tb == ta {
+			return a
+		}
+		return a.Scale((tb - x) / (tb - ta)).Add(b.Scale((x - ta) / (tb - ta)))
+	}
+	a1 := lerp(p0, p1, t0, t1)
+	a2 := lerp(p1, p2, t1, t2)
+	a3 := lerp(p2, p3, t2, t3)
+	b1 := lerp(a1, a2, t0, t2)
+	b2 := lerp(a2, a3, t1, t3)
+	return lerp(b1, b2, t1, t2)
+}
+
+// Flatten approximates the curve as a polyline whose
+// points are within tol of the true curve.
+//
+// See CurveFlatten for details.
+func (c *CatmullRomCurve) Flatten(tol float64) []Coord {
+	return CurveFlatten(c, tol, minSplitsForSegments(c.numSpans()), 0)
+}
+
+// Mesh creates a mesh approximating the curve within the
+// given tolerance, using Flatten.
+func (c *CatmullRomCurve) Mesh(tol float64) *Mesh {
+	points := c.Flatten(tol)
+	if c.Closed {
+		points = points[:len(points)-1]
+	}
+	return PolylineMesh(points, c.Closed)
+}
+
+func (c *CatmullRomCurve) numSpans() int {
+	if c.Closed {
+		return len(c.Points)
+	}
+	return len(c.Points) - 1
+}
+
+func (c *CatmullRomCurve) point(i int) Coord {
+	n := len(c.Points)
+	if c.Closed {
+		return c.Points[((i%n)+n)%n]
+	}
+	if i < 0 {
+		return c.Points[0].Scale(2).Sub(c.Points[1])
+	} else if i >= n {
+		return c.Points[n-1].Scale(2).Sub(c.Points[n-2])
+	}
+	return c.Points[i]
+}
+
+func (c *CatmullRomCurve) knotDelta(p1, p2 Coord) float64 {
+	d := math.Pow(p1.Dist(p2), c.Alpha)
+	if d == 0 {
+		// Avoid division by zero for repeated points.
+		return 1e-8
+	}
+	return d
+}
+
+// minSplitsForSegments computes the number of binary
+// subdivisions needed so that every one of n pieces of a
+// curve is split at least once.
+func minSplitsForSegments(n int) int {
+	return int(math.Ceil(math.Log2(math.Max(1, float64(n))))) + 1
+}
@@ -51,6 +51,11 @@ type SolidSurfaceEstimator struct {
 	//
 	// Default is DefaultSurfaceEstimatorNormalNoiseEpsilon.
 	NormalNoiseEpsilon float64
+
+	// NormalSDF, if non-nil, is used by Normal() to compute
+	// exact normals instead of estimating them with search.
+	// It should describe the same surface as Solid.
+	NormalSDF NormalSDF
 }
 
 // BisectInterp returns alpha in [min, max] to minimize the
@@ -106,7 +111,10 @@ func (s *SolidSurfaceEstimator) BisectInterior(p1, p2 Coord) Coord {
 // The point must be guaranteed to be on the boundary of
 // the surface, e.g. from Bisect().
 func (s *SolidSurfaceEstimator) Normal(c Coord) Coord {
-	if s.RandomSearchNormals {
+	if s.NormalSDF != nil {
+		normal, _ := s.NormalSDF.NormalSDF(c)
+		return normal
+	} else if s.RandomSearchNormals {
 		return s.esNormal(c)
 	} else {
 		return s.bisectNormal(c)
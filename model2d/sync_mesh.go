@@ -0,0 +1,139 @@
+// Generated from templates/sync_mesh.template
+
+package model2d
+
+import "sync"
+
+// A SyncMesh wraps a Mesh so that it can be read and
+// modified from concurrent Goroutines without any
+// external synchronization.
+//
+// Reads, such as Find() and Neighbors(), may proceed in
+// parallel, while modifications are performed
+// exclusively.
+//
+// Like a Mesh, a SyncMesh identifies segments by pointer.
+type SyncMesh struct {
+	lock sync.RWMutex
+	mesh *Mesh
+}
+
+// NewSyncMesh creates an empty, concurrency-safe mesh.
+func NewSyncMesh() *SyncMesh {
+	return &SyncMesh{mesh: NewMesh()}
+}
+
+// NewSyncMeshMesh creates a concurrency-safe mesh which
+// takes ownership of m.
+//
+// The caller should not access m directly after this
+// call, except through Read() and Update().
+func NewSyncMeshMesh(m *Mesh) *SyncMesh {
+	return &SyncMesh{mesh: m}
+}
+
+// Add adds the segment f to the mesh.
+func (s *SyncMesh) Add(f *Segment) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.Add(f)
+}
+
+// AddMesh adds all the segments from m to s.
+func (s *SyncMesh) AddMesh(m *Mesh) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.AddMesh(m)
+}
+
+// Remove removes the segment f from the mesh.
+func (s *SyncMesh) Remove(f *Segment) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.Remove(f)
+}
+
+// Contains checks if f has been added to the mesh.
+func (s *SyncMesh) Contains(f *Segment) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Contains(f)
+}
+
+// NumSegments returns the number of segments in the mesh.
+func (s *SyncMesh) NumSegments() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.NumSegments()
+}
+
+// Find gets all the segments that contain all of the
+// passed points.
+//
+// See Mesh.Find() for details.
+func (s *SyncMesh) Find(ps ...Coord) []*Segment {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Find(ps...)
+}
+
+// Neighbors gets all the segments with a side touching
+// a given segment f.
+//
+// See Mesh.Neighbors() for details.
+func (s *SyncMesh) Neighbors(f *Segment) []*Segment {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Neighbors(f)
+}
+
+// SegmentSlice gets a snapshot of all the segments
+// currently in the mesh.
+func (s *SyncMesh) SegmentSlice() []*Segment {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.SegmentSlice()
+}
+
+// Iterate calls f for every segment in a snapshot of
+// the mesh.
+//
+// The mesh is not locked while f is running, so f may
+// safely modify the mesh.
+func (s *SyncMesh) Iterate(f func(*Segment)) {
+	for _, face := range s.SegmentSlice() {
+		f(face)
+	}
+}
+
+// Snapshot creates a shallow copy of the current mesh.
+//
+// The result is a regular Mesh which may be used freely
+// without affecting s.
+func (s *SyncMesh) Snapshot() *Mesh {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Copy()
+}
+
+// Read calls f with the underlying mesh while holding a
+// read lock, allowing arbitrary read-only Mesh methods
+// to be used without copying the mesh.
+//
+// The mesh must not be modified or retained by f.
+func (s *SyncMesh) Read(f func(m *Mesh)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	f(s.mesh)
+}
+
+// Update calls f with the underlying mesh while holding
+// an exclusive lock, allowing several modifications to
+// be applied atomically.
+//
+// The mesh must not be retained by f.
+func (s *SyncMesh) Update(f func(m *Mesh)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f(s.mesh)
+}
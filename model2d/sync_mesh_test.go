@@ -0,0 +1,51 @@
+// Generated from templates/sync_mesh_test.template
+
+package model2d
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncMesh(t *testing.T) {
+	base := NewMeshPolar(func(theta float64) float64 {
+		return 1
+	}, 100)
+	s := NewSyncMesh()
+
+	var wg sync.WaitGroup
+	faces := base.SegmentSlice()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := i; j < len(faces); j += 4 {
+				s.Add(faces[j])
+				for _, p := range faces[j] {
+					for _, f := range s.Find(p) {
+						if !s.Contains(f) {
+							t.Errorf("found segment is not in mesh")
+						}
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !meshesEqual(base, s.Snapshot()) {
+		t.Fatal("unexpected mesh after concurrent adds")
+	}
+	for _, f := range faces {
+		if expected, actual := len(base.Neighbors(f)), len(s.Neighbors(f)); expected != actual {
+			t.Fatalf("expected %d neighbors but got %d", expected, actual)
+		}
+	}
+
+	s.Iterate(func(f *Segment) {
+		s.Remove(f)
+	})
+	if n := s.NumSegments(); n != 0 {
+		t.Fatalf("expected empty mesh but got %d segments", n)
+	}
+}
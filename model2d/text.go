@@ -0,0 +1,120 @@
+package model2d
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// TextSolid creates a Solid from a string of text, using
+// the glyph outlines of a TrueType or OpenType font.
+//
+// The size argument is the font size, i.e. the height of
+// one em. The text starts at the origin, with the first
+// line's baseline along the x-axis. Newlines in the text
+// start new lines below the previous ones.
+//
+// Containment is determined with the even-odd rule, so
+// glyphs with overlapping contours may contain holes
+// where the contours overlap.
+func TextSolid(f *sfnt.Font, text string, size float64) (Solid, error) {
+	mesh, err := TextMesh(f, text, size, size*1e-3)
+	if err != nil {
+		return nil, err
+	}
+	if mesh.NumSegments() == 0 {
+		// Whitespace or empty text.
+		return JoinedSolid{}, nil
+	}
+	return NewColliderSolid(MeshToCollider(mesh)), nil
+}
+
+// TextMesh is like TextSolid, but creates a mesh of the
+// glyph outlines.
+//
+// Curves in the outlines are flattened to segments within
+// the given tolerance.
+// The outlines follow the TrueType convention, so the
+// normals of the resulting mesh face outward.
+//
+// Glyphs are placed using their advance widths and any
+// kerning pairs in the font's kern table.
+func TextMesh(f *sfnt.Font, text string, size, tolerance float64) (*Mesh, error) {
+	var buf sfnt.Buffer
+
+	// Load glyphs in font units for maximum precision, and
+	// scale them afterwards.
+	unitsPerEm := f.UnitsPerEm()
+	ppem := fixed.Int26_6(unitsPerEm) << 6
+	scale := size / float64(ppem)
+
+	metrics, err := f.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		return nil, errors.Wrap(err, "text mesh")
+	}
+
+	builder := NewPathBuilder(tolerance / scale)
+	convert := func(p fixed.Point26_6) Coord {
+		return XY(float64(p.X), -float64(p.Y))
+	}
+
+	var x, y fixed.Int26_6
+	var prev sfnt.GlyphIndex
+	hasPrev := false
+	for _, r := range text {
+		if r == '\n' {
+			x = 0
+			y += metrics.Height
+			hasPrev = false
+			continue
+		}
+		idx, err := f.GlyphIndex(&buf, r)
+		if err != nil {
+			return nil, errors.Wrap(err, "text mesh")
+		}
+		if hasPrev {
+			kern, err := f.Kern(&buf, prev, idx, ppem, font.HintingNone)
+			if err == nil {
+				x += kern
+			} else if err != sfnt.ErrNotFound {
+				return nil, errors.Wrap(err, "text mesh")
+			}
+		}
+		segs, err := f.LoadGlyph(&buf, idx, ppem, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "text mesh")
+		}
+		offset := fixed.Point26_6{X: x, Y: y}
+		for _, seg := range segs {
+			var args [3]Coord
+			for i, arg := range seg.Args {
+				args[i] = convert(arg.Add(offset))
+			}
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if builder.hasPoints {
+					builder.Close()
+				}
+				builder.MoveTo(args[0])
+			case sfnt.SegmentOpLineTo:
+				builder.LineTo(args[0])
+			case sfnt.SegmentOpQuadTo:
+				builder.QuadTo(args[0], args[1])
+			case sfnt.SegmentOpCubeTo:
+				builder.CurveTo(args[0], args[1], args[2])
+			}
+		}
+		advance, err := f.GlyphAdvance(&buf, idx, ppem, font.HintingNone)
+		if err != nil {
+			return nil, errors.Wrap(err, "text mesh")
+		}
+		x += advance
+		prev = idx
+		hasPrev = true
+	}
+	if builder.hasPoints {
+		builder.Close()
+	}
+	return builder.Mesh().Scale(scale), nil
+}
@@ -0,0 +1,44 @@
+package model2d
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestTextMesh(t *testing.T) {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh, err := TextMesh(f, "Ao8", 2, 1e-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	MustValidateMesh(t, mesh, true)
+
+	solid, err := TextSolid(f, "Ao8\nBob", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	min, max := solid.Min(), solid.Max()
+	if min.X < -0.1 || max.X > 6 || max.Y > 2 || min.Y > -1 {
+		t.Errorf("unexpected bounds: %v, %v", min, max)
+	}
+
+	// The hole in the "o" should not be filled, but its
+	// ring should be.
+	oMesh, err := TextMesh(f, "o", 1, 1e-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oSolid := NewColliderSolid(MeshToCollider(oMesh))
+	center := oMesh.Min().Mid(oMesh.Max())
+	if oSolid.Contains(center) {
+		t.Error("center of 'o' should be empty")
+	}
+	if !oSolid.Contains(XY(oMesh.Min().X+0.03, center.Y)) {
+		t.Error("ring of 'o' should be filled")
+	}
+}
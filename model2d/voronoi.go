@@ -0,0 +1,80 @@
+package model2d
+
+import "sort"
+
+// VoronoiCells computes the Voronoi cell of each site,
+// clipped to the region inside of a bounding mesh.
+//
+// The bounding mesh should be closed and have outward
+// facing normals. The resulting cells are closed meshes
+// with outward facing normals, and the i-th cell contains
+// the points of the bounds which are closer to sites[i]
+// than to any other site.
+//
+// Cells may be empty if a site's region does not overlap
+// the bounds. Duplicate sites produce identical cells.
+func VoronoiCells(sites []Coord, bounds *Mesh) []*Mesh {
+	polytopes := VoronoiPolytopes(sites)
+	res := make([]*Mesh, len(sites))
+	for i, p := range polytopes {
+		res[i] = ClipMesh(bounds, p)
+	}
+	return res
+}
+
+// VoronoiPolytopes computes the Voronoi cell of each site
+// as a (possibly unbounded) convex polytope.
+//
+// The constraints of each polytope are the perpendicular
+// bisectors between the site and its neighbors in the
+// Delaunay triangulation.
+func VoronoiPolytopes(sites []Coord) []ConvexPolytope {
+	neighbors := map[Coord][]Coord{}
+	addEdge := func(c1, c2 Coord) {
+		for _, n := range neighbors[c1] {
+			if n == c2 {
+				return
+			}
+		}
+		neighbors[c1] = append(neighbors[c1], c2)
+		neighbors[c2] = append(neighbors[c2], c1)
+	}
+
+	tris := Delaunay(sites)
+	if len(tris) > 0 {
+		for _, t := range tris {
+			for i := 0; i < 3; i++ {
+				addEdge(t[i], t[(i+1)%3])
+			}
+		}
+	} else {
+		// All of the sites are colinear, so each site only
+		// neighbors the sites beside it along the line.
+		sorted := append([]Coord{}, sites...)
+		sort.Slice(sorted, func(i, j int) bool {
+			if sorted[i].X == sorted[j].X {
+				return sorted[i].Y < sorted[j].Y
+			}
+			return sorted[i].X < sorted[j].X
+		})
+		for i := 1; i < len(sorted); i++ {
+			if sorted[i] != sorted[i-1] {
+				addEdge(sorted[i-1], sorted[i])
+			}
+		}
+	}
+
+	res := make([]ConvexPolytope, len(sites))
+	for i, site := range sites {
+		var p ConvexPolytope
+		for _, n := range neighbors[site] {
+			normal := n.Sub(site)
+			p = append(p, &LinearConstraint{
+				Normal: normal,
+				Max:    normal.Dot(site.Mid(n)),
+			})
+		}
+		res[i] = p
+	}
+	return res
+}
@@ -0,0 +1,60 @@
+package model2d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestVoronoiCells(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	sites := make([]Coord, 50)
+	for i := range sites {
+		sites[i] = XY(rng.Float64()*3-1, rng.Float64()*3-1)
+	}
+	bounds := MarchingSquaresSearch(&Circle{Radius: 1}, 0.02, 8)
+	cells := VoronoiCells(sites, bounds)
+
+	var totalArea float64
+	for _, cell := range cells {
+		if cell.NumSegments() > 0 {
+			MustValidateMesh(t, cell, true)
+		}
+		totalArea += cell.Area()
+	}
+	if math.Abs(totalArea-bounds.Area()) > 1e-8 {
+		t.Errorf("expected total area %f but got %f", bounds.Area(), totalArea)
+	}
+
+	boundsSolid := NewColliderSolid(MeshToCollider(bounds))
+	for i := 0; i < 1000; i++ {
+		c := XY(rng.Float64()*2-1, rng.Float64()*2-1)
+		if !boundsSolid.Contains(c) {
+			continue
+		}
+		nearest, second := -1, -1
+		for j, site := range sites {
+			if nearest == -1 || site.Dist(c) < sites[nearest].Dist(c) {
+				nearest, second = j, nearest
+			} else if second == -1 || site.Dist(c) < sites[second].Dist(c) {
+				second = j
+			}
+		}
+		if sites[second].Dist(c)-sites[nearest].Dist(c) < 1e-3 {
+			continue
+		}
+		if cells[nearest].WindingNumber(c) != 1 {
+			t.Fatalf("point %v should be in cell %d", c, nearest)
+		}
+	}
+}
+
+func TestVoronoiColinear(t *testing.T) {
+	sites := []Coord{XY(0, 0), XY(2, 0), XY(1, 0)}
+	cells := VoronoiCells(sites, NewMeshRect(XY(-1, -1), XY(3, 1)))
+	for i, expected := range []float64{3, 3, 2} {
+		if a := cells[i].Area(); math.Abs(a-expected) > 1e-8 {
+			t.Errorf("cell %d: expected area %f but got %f", i, expected, a)
+		}
+	}
+}
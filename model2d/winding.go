@@ -0,0 +1,291 @@
+package model2d
+
+// A BatchSolid is a Solid which can check containment for
+// many points at once more efficiently than calling
+// Contains for each point.
+//
+// Algorithms like marching squares use ContainsMany when
+// it is available.
+type BatchSolid interface {
+	Solid
+
+	// ContainsMany checks if each of the coordinates is
+	// in the solid, returning one result per coordinate.
+	ContainsMany(coords []Coord) []bool
+}
+
+// ContainsMany checks if each coordinate is in the solid.
+//
+// The result is equivalent to calling Contains on each
+// coordinate, but traversal of the collider's bounding
+// box hierarchy is shared between the points.
+func (c *ColliderSolid) ContainsMany(coords []Coord) []bool {
+	if c.radius != 0 {
+		res := make([]bool, len(coords))
+		for i, coord := range coords {
+			res[i] = c.Contains(coord)
+		}
+		return res
+	}
+	var inBounds []Coord
+	var indices []int
+	for i, coord := range coords {
+		if InBounds(c, coord) {
+			inBounds = append(inBounds, coord)
+			indices = append(indices, i)
+		}
+	}
+	res := make([]bool, len(coords))
+	for i, contained := range ColliderContainsMany(c.collider, inBounds, c.inset) {
+		res[indices[i]] = contained
+	}
+	return res
+}
+
+// ColliderContainsMany is like ColliderContains, but
+// checks many points at once.
+//
+// For colliders created with MeshToCollider or
+// BVHToCollider, this visits each node of the bounding box
+// hierarchy at most once, only passing along the points
+// whose rays may hit the node.
+func ColliderContainsMany(c Collider, coords []Coord, margin float64) []bool {
+	counts := make([]int, len(coords))
+	indices := make([]int, len(coords))
+	rays := make([]Ray, len(coords))
+	for i, c := range coords {
+		indices[i] = i
+		rays[i] = Ray{Origin: c, Direction: colliderContainsDirection}
+	}
+	batchRayCollisionCounts(c, rays, indices, counts)
+
+	res := make([]bool, len(coords))
+	for i, coord := range coords {
+		res[i] = colliderContainsCount(c, coord, margin, counts[i])
+	}
+	return res
+}
+
+func batchRayCollisionCounts(c Collider, rays []Ray, indices []int, counts []int) {
+	var joined *JoinedCollider
+	switch c := c.(type) {
+	case *JoinedCollider:
+		joined = c
+	case joinedMultiCollider:
+		joined = c.JoinedCollider
+	case *joinedMultiCollider:
+		joined = c.JoinedCollider
+	default:
+		for _, idx := range indices {
+			counts[idx] += c.RayCollisions(&rays[idx], nil)
+		}
+		return
+	}
+
+	if len(joined.colliders) == 0 {
+		return
+	}
+	// Move the points whose rays hit the bounds to the
+	// front, without allocating a new slice.
+	var n int
+	for i, idx := range indices {
+		minFrac, maxFrac := rayCollisionWithBounds(&rays[idx], joined.min, joined.max)
+		if minFrac <= maxFrac && maxFrac >= 0 {
+			indices[i], indices[n] = indices[n], indices[i]
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	subset := indices[:n]
+	for _, child := range joined.colliders {
+		batchRayCollisionCounts(child, rays, subset, counts)
+	}
+}
+
+// WindingNumber computes the winding number of the mesh
+// around a point.
+//
+// For a closed mesh with outward-facing normals, this is 1
+// for points inside the mesh and 0 for points outside.
+// Overlapping loops accumulate, and loops with inward
+// facing normals count negatively.
+//
+// Unlike ray parity tests, the result is exact for points
+// whose rays pass through vertices, such as the shared
+// vertex of two loops that touch at a corner.
+//
+// This takes linear time in the number of segments. For
+// repeated queries, use a WindingSolid.
+func (m *Mesh) WindingNumber(c Coord) int {
+	var res int
+	m.Iterate(func(s *Segment) {
+		res += segmentWinding(s, c)
+	})
+	return res
+}
+
+// A WindingSolid is a Solid containing the points where a
+// mesh has a non-zero winding number.
+//
+// This is more robust than a ColliderSolid for meshes
+// with self-touching or overlapping loops, since points
+// are never miscounted due to rays hitting vertices.
+type WindingSolid struct {
+	root *windingNode
+}
+
+// NewWindingSolid creates a WindingSolid from a closed
+// mesh with outward-facing normals.
+func NewWindingSolid(m *Mesh) *WindingSolid {
+	segs := m.SegmentsSlice()
+	if len(segs) == 0 {
+		return &WindingSolid{}
+	}
+	GroupSegments(segs)
+	return &WindingSolid{root: newWindingNode(segs)}
+}
+
+// Min gets the minimum of the mesh's bounding box.
+func (w *WindingSolid) Min() Coord {
+	if w.root == nil {
+		return Coord{}
+	}
+	return w.root.min
+}
+
+// Max gets the maximum of the mesh's bounding box.
+func (w *WindingSolid) Max() Coord {
+	if w.root == nil {
+		return Coord{}
+	}
+	return w.root.max
+}
+
+// Contains checks if the winding number at c is non-zero.
+func (w *WindingSolid) Contains(c Coord) bool {
+	return w.WindingNumber(c) != 0
+}
+
+// ContainsMany checks if the winding number at each of the
+// coordinates is non-zero.
+func (w *WindingSolid) ContainsMany(coords []Coord) []bool {
+	res := make([]bool, len(coords))
+	for i, n := range w.WindingNumbers(coords) {
+		res[i] = n != 0
+	}
+	return res
+}
+
+// WindingNumber computes the winding number of the mesh
+// around c.
+//
+// See Mesh.WindingNumber for details.
+func (w *WindingSolid) WindingNumber(c Coord) int {
+	if w.root == nil {
+		return 0
+	}
+	return w.root.WindingNumber(c)
+}
+
+// WindingNumbers computes the winding number of the mesh
+// around every coordinate, sharing the traversal of the
+// bounding box hierarchy between the points.
+func (w *WindingSolid) WindingNumbers(coords []Coord) []int {
+	res := make([]int, len(coords))
+	if w.root == nil {
+		return res
+	}
+	indices := make([]int, len(coords))
+	for i := range indices {
+		indices[i] = i
+	}
+	w.root.WindingNumbers(coords, indices, res)
+	return res
+}
+
+type windingNode struct {
+	min Coord
+	max Coord
+
+	// Either seg is non-nil, or both children are.
+	seg   *Segment
+	left  *windingNode
+	right *windingNode
+}
+
+func newWindingNode(segs []*Segment) *windingNode {
+	if len(segs) == 1 {
+		return &windingNode{min: segs[0].Min(), max: segs[0].Max(), seg: segs[0]}
+	}
+	mid := len(segs) / 2
+	left := newWindingNode(segs[:mid])
+	right := newWindingNode(segs[mid:])
+	return &windingNode{
+		min:   left.min.Min(right.min),
+		max:   left.max.Max(right.max),
+		left:  left,
+		right: right,
+	}
+}
+
+// mayCross checks if a ray from c in the positive x
+// direction may cross a segment within the node.
+func (w *windingNode) mayCross(c Coord) bool {
+	return c.Y >= w.min.Y && c.Y <= w.max.Y && c.X <= w.max.X
+}
+
+func (w *windingNode) WindingNumber(c Coord) int {
+	if !w.mayCross(c) {
+		return 0
+	}
+	if w.seg != nil {
+		return segmentWinding(w.seg, c)
+	}
+	return w.left.WindingNumber(c) + w.right.WindingNumber(c)
+}
+
+func (w *windingNode) WindingNumbers(coords []Coord, indices, res []int) {
+	var n int
+	for i, idx := range indices {
+		if w.mayCross(coords[idx]) {
+			indices[i], indices[n] = indices[n], indices[i]
+			n++
+		}
+	}
+	if n == 0 {
+		return
+	}
+	subset := indices[:n]
+	if w.seg != nil {
+		for _, idx := range subset {
+			res[idx] += segmentWinding(w.seg, coords[idx])
+		}
+		return
+	}
+	w.left.WindingNumbers(coords, subset, res)
+	w.right.WindingNumbers(coords, subset, res)
+}
+
+// segmentWinding computes the contribution of a segment to
+// the winding number around c, by checking if it crosses
+// a ray from c in the positive x direction.
+//
+// Crossings are half-open in y, so that a ray through a
+// vertex is counted exactly once for the two segments
+// sharing the vertex.
+//
+// Loops with outward-facing normals are clockwise, so
+// upward crossings count negatively and downward crossings
+// count positively.
+func segmentWinding(s *Segment, c Coord) int {
+	if s[0].Y <= c.Y {
+		if s[1].Y > c.Y && edgeCross(s[1].Sub(s[0]), c.Sub(s[0])) > 0 {
+			return -1
+		}
+	} else if s[1].Y <= c.Y && edgeCross(s[1].Sub(s[0]), c.Sub(s[0])) < 0 {
+		return 1
+	}
+	return 0
+}
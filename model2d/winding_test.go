@@ -0,0 +1,114 @@
+package model2d
+
+import (
+	"testing"
+)
+
+func TestMeshWindingNumber(t *testing.T) {
+	// Two squares touching at the corner (1, 1).
+	mesh := NewMeshRect(XY(0, 0), XY(1, 1))
+	mesh.AddMesh(NewMeshRect(XY(1, 1), XY(2, 2)))
+
+	solid := NewWindingSolid(mesh)
+	cases := map[Coord]int{
+		XY(0.5, 0.5): 1,
+		XY(1.5, 1.5): 1,
+		XY(0.5, 1.5): 0,
+		XY(1.5, 0.5): 0,
+		XY(-1, 1):    0,
+		XY(-1, 0):    0,
+		XY(3, 0.5):   0,
+	}
+	for c, expected := range cases {
+		if actual := mesh.WindingNumber(c); actual != expected {
+			t.Errorf("mesh winding at %v: expected %d but got %d", c, expected, actual)
+		}
+		if actual := solid.WindingNumber(c); actual != expected {
+			t.Errorf("solid winding at %v: expected %d but got %d", c, expected, actual)
+		}
+	}
+
+	// Overlapping and inverted loops.
+	mesh = NewMeshRect(XY(0, 0), XY(2, 2))
+	mesh.AddMesh(NewMeshRect(XY(1, 1), XY(3, 3)))
+	if n := mesh.WindingNumber(XY(1.5, 1.5)); n != 2 {
+		t.Errorf("expected winding number 2 but got %d", n)
+	}
+	if n := mesh.InvertNormals().WindingNumber(XY(0.5, 0.5)); n != -1 {
+		t.Errorf("expected winding number -1 but got %d", n)
+	}
+}
+
+func TestWindingSolidContainsMany(t *testing.T) {
+	mesh := MarchingSquaresSearch(&Circle{Radius: 0.8}, 0.03, 8)
+	mesh.AddMesh(NewMeshRect(XY(0.5, 0.5), XY(1, 1)).InvertNormals())
+	solid := NewWindingSolid(mesh)
+
+	coords := make([]Coord, 1000)
+	for i := range coords {
+		coords[i] = NewCoordRandBounds(XY(-1.5, -1.5), XY(1.5, 1.5))
+	}
+	numbers := solid.WindingNumbers(coords)
+	contained := solid.ContainsMany(coords)
+	for i, c := range coords {
+		if expected := mesh.WindingNumber(c); numbers[i] != expected {
+			t.Fatalf("winding number at %v: expected %d but got %d", c, expected, numbers[i])
+		}
+		if contained[i] != solid.Contains(c) {
+			t.Fatalf("containment mismatch at %v", c)
+		}
+	}
+}
+
+func TestColliderContainsMany(t *testing.T) {
+	mesh := MarchingSquaresSearch(&Circle{Radius: 0.8}, 0.03, 8)
+	coords := make([]Coord, 1000)
+	for i := range coords {
+		coords[i] = NewCoordRandBounds(XY(-1, -1), XY(1, 1))
+	}
+	for _, collider := range []Collider{
+		MeshToCollider(mesh),
+		BVHToCollider(NewBVHAreaDensity(mesh.SegmentsSlice())),
+	} {
+		for _, margin := range []float64{0, 0.05, -0.05} {
+			actual := ColliderContainsMany(collider, coords, margin)
+			for i, c := range coords {
+				if expected := ColliderContains(collider, c, margin); actual[i] != expected {
+					t.Fatalf("margin %f: mismatch at %v", margin, c)
+				}
+			}
+		}
+		for _, solid := range []*ColliderSolid{
+			NewColliderSolid(collider),
+			NewColliderSolidInset(collider, 0.05),
+			NewColliderSolidHollow(collider, 0.05),
+		} {
+			actual := solid.ContainsMany(coords)
+			for i, c := range coords {
+				if expected := solid.Contains(c); actual[i] != expected {
+					t.Fatalf("solid mismatch at %v", c)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkColliderContainsMany(b *testing.B) {
+	collider := MeshToCollider(colliderTestingMesh(10000))
+	coords := make([]Coord, 100)
+	for i := range coords {
+		coords[i] = XY(float64(i)/50-1, 0.1)
+	}
+	b.Run("Single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, c := range coords {
+				ColliderContains(collider, c, 0)
+			}
+		}
+	})
+	b.Run("Many", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ColliderContainsMany(collider, coords, 0)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package model3d
+
+// TrackAdjacency makes the mesh maintain a cache which
+// maps every edge to the triangles that contain it, in
+// addition to its vertex-to-triangle cache.
+//
+// Both caches are updated incrementally as triangles are
+// added and removed, so repeated topological queries,
+// such as Neighbors(), EdgeTriangles() and NeedsRepair(),
+// do not need to rebuild them.
+// Blur(), VertexNormals() and EliminateEdges() use the
+// caches instead of building their own adjacency maps.
+// Meshes derived from m through Copy(), DeepCopy(),
+// MapCoords() (and thus Transform() and friends), Blur()
+// and EliminateEdges() also track adjacency.
+//
+// Tracking adjacency makes Add() and Remove() slower and
+// uses more memory, so it is only worthwhile when many
+// adjacency queries are performed on the same mesh.
+//
+// Like any other modification, this must not be called
+// concurrently with other mesh operations.
+func (m *Mesh) TrackAdjacency() {
+	if m.edgeToFace != nil {
+		return
+	}
+	m.getVertexToFace()
+	m.edgeToFace = NewEdgeToSlice[*Triangle]()
+	for t := range m.faces {
+		m.addFaceEdges(t)
+	}
+}
+
+// TracksAdjacency returns true if TrackAdjacency() has
+// been called on the mesh.
+func (m *Mesh) TracksAdjacency() bool {
+	return m.edgeToFace != nil
+}
+
+// EdgeTriangles gets all of the triangles in the mesh
+// which contain the segment s as an edge.
+//
+// This is efficient if the mesh tracks adjacency, and
+// falls back on Find() otherwise.
+func (m *Mesh) EdgeTriangles(s Segment) []*Triangle {
+	if m.edgeToFace != nil {
+		return append([]*Triangle{}, m.edgeToFace.Value(NewSegment(s[0], s[1]))...)
+	}
+	return m.Find(s[0], s[1])
+}
+
+func (m *Mesh) inheritAdjacency(m1 *Mesh) {
+	if m1.edgeToFace != nil {
+		m.TrackAdjacency()
+	}
+}
+
+func (m *Mesh) addFaceEdges(t *Triangle) {
+	for _, seg := range t.Segments() {
+		m.edgeToFace.Append(seg, t)
+	}
+}
+
+func (m *Mesh) removeFaceEdges(t *Triangle) {
+	for _, seg := range t.Segments() {
+		faces := m.edgeToFace.Value(seg)
+		for i, t1 := range faces {
+			if t1 == t {
+				faces[i] = faces[len(faces)-1]
+				faces = faces[:len(faces)-1]
+				break
+			}
+		}
+		if len(faces) == 0 {
+			m.edgeToFace.Delete(seg)
+		} else {
+			m.edgeToFace.Store(seg, faces)
+		}
+	}
+}
+
+func (m *Mesh) edgeNeighbors(t *Triangle) []*Triangle {
+	var res []*Triangle
+	for _, seg := range t.Segments() {
+	NeighborLoop:
+		for _, t1 := range m.edgeToFace.Value(seg) {
+			if t1 == t {
+				continue
+			}
+			for _, t2 := range res {
+				if t2 == t1 {
+					continue NeighborLoop
+				}
+			}
+			res = append(res, t1)
+		}
+	}
+	return res
+}
+
+// edgeNeighborIndices is like faceNeighborIndices, but
+// uses the edge cache to find neighbors.
+func (m *Mesh) edgeNeighborIndices(f func(c1, c2 Coord3D) bool) (map[Coord3D]int,
+	[]Coord3D, [][]int) {
+	v2f := m.getVertexToFace()
+	coordToIdx := make(map[Coord3D]int, v2f.Len())
+	coords := make([]Coord3D, 0, v2f.Len())
+	v2f.KeyRange(func(c Coord3D) bool {
+		coordToIdx[c] = len(coords)
+		coords = append(coords, c)
+		return true
+	})
+	neighbors := make([][]int, len(coords))
+	m.edgeToFace.KeyRange(func(seg [2]Coord3D) bool {
+		if seg[0] == seg[1] {
+			return true
+		}
+		idx1, idx2 := coordToIdx[seg[0]], coordToIdx[seg[1]]
+		if f == nil || f(seg[0], seg[1]) {
+			neighbors[idx1] = append(neighbors[idx1], idx2)
+		}
+		if f == nil || f(seg[1], seg[0]) {
+			neighbors[idx2] = append(neighbors[idx2], idx1)
+		}
+		return true
+	})
+	return coordToIdx, coords, neighbors
+}
+
+// cachedVertexNormals is like VertexNormals, but sums up
+// the triangles around each vertex using the
+// vertex-to-triangle cache.
+func (m *Mesh) cachedVertexNormals() *CoordMap[Coord3D] {
+	res := NewCoordMap[Coord3D]()
+	m.getVertexToFace().Range(func(c Coord3D, faces []*Triangle) bool {
+		var sum Coord3D
+		for _, t := range faces {
+			angles := vertexAngles(t)
+			normal := t.Normal()
+			for i, p := range t {
+				if p == c {
+					sum = sum.Add(normal.Scale(angles[i]))
+				}
+			}
+		}
+		res.Store(c, sum.Normalize())
+		return true
+	})
+	return res
+}
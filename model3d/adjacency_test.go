@@ -0,0 +1,134 @@
+package model3d
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMeshTrackAdjacency(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 4)
+	mesh.TrackAdjacency()
+	if !mesh.TracksAdjacency() {
+		t.Fatal("mesh should track adjacency")
+	}
+
+	checkAdjacencyConsistent(t, mesh)
+
+	tris := mesh.TriangleSlice()
+	rand.Shuffle(len(tris), func(i, j int) {
+		tris[i], tris[j] = tris[j], tris[i]
+	})
+	for _, tri := range tris[:len(tris)/3] {
+		mesh.Remove(tri)
+	}
+	checkAdjacencyConsistent(t, mesh)
+	for _, tri := range tris[:len(tris)/6] {
+		mesh.Add(tri)
+	}
+	checkAdjacencyConsistent(t, mesh)
+
+	for _, derived := range []*Mesh{mesh.Copy(), mesh.DeepCopy(), mesh.Scale(2), mesh.Blur(0.5)} {
+		if !derived.TracksAdjacency() {
+			t.Fatal("derived mesh should track adjacency")
+		}
+		checkAdjacencyConsistent(t, derived)
+	}
+}
+
+func TestMeshTrackAdjacencyOps(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 4)
+	mesh.TrackAdjacency()
+
+	// Warm up the caches through the ops before modifying
+	// the mesh, so stale entries would show up below.
+	mesh.Blur(0.5)
+	mesh.VertexNormals()
+
+	tris := mesh.TriangleSlice()
+	rand.Shuffle(len(tris), func(i, j int) {
+		tris[i], tris[j] = tris[j], tris[i]
+	})
+	for _, tri := range tris[:len(tris)/3] {
+		mesh.Remove(tri)
+	}
+	for _, tri := range tris[:len(tris)/6] {
+		mesh.Add(tri)
+	}
+	mesh.Add(&Triangle{XYZ(2, 0, 0), XYZ(3, 0, 0), XYZ(2, 1, 0)})
+	plain := NewMeshTriangles(mesh.TriangleSlice())
+
+	t.Run("Blur", func(t *testing.T) {
+		for _, f := range []func(c1, c2 Coord3D) bool{
+			nil,
+			func(c1, c2 Coord3D) bool { return c1.Z < c2.Z },
+		} {
+			expected := plain.BlurFiltered(f, 0.5, 0.3)
+			actual := mesh.BlurFiltered(f, 0.5, 0.3)
+			if actual.NumTriangles() != expected.NumTriangles() {
+				t.Fatalf("expected %d triangles but got %d", expected.NumTriangles(),
+					actual.NumTriangles())
+			}
+			tree := NewCoordTree(expected.VertexSlice())
+			actual.IterateVertices(func(c Coord3D) {
+				if nearest := tree.NearestNeighbor(c); nearest.Dist(c) > 1e-8 {
+					t.Fatalf("unexpected blurred vertex %v (nearest %v)", c, nearest)
+				}
+			})
+			checkAdjacencyConsistent(t, actual)
+		}
+	})
+
+	t.Run("VertexNormals", func(t *testing.T) {
+		expected := plain.VertexNormals()
+		actual := mesh.VertexNormals()
+		if actual.Len() != expected.Len() {
+			t.Fatalf("expected %d normals but got %d", expected.Len(), actual.Len())
+		}
+		expected.Range(func(c, n Coord3D) bool {
+			if n1, ok := actual.Load(c); !ok || n1.Dist(n) > 1e-8 {
+				t.Fatalf("vertex %v: expected normal %v but got %v", c, n, n1)
+			}
+			return true
+		})
+	})
+
+	t.Run("EliminateEdges", func(t *testing.T) {
+		var tmpTracks bool
+		result := mesh.EliminateEdges(func(tmp *Mesh, s Segment) bool {
+			tmpTracks = tmp.TracksAdjacency()
+			return s.Length() < 0.3
+		})
+		if !tmpTracks || !result.TracksAdjacency() {
+			t.Fatal("eliminated mesh should track adjacency")
+		}
+		if result.NumTriangles() >= mesh.NumTriangles() {
+			t.Fatal("no edges were eliminated")
+		}
+		checkAdjacencyConsistent(t, result)
+		checkAdjacencyConsistent(t, result.Blur(0.5))
+	})
+}
+
+func checkAdjacencyConsistent(t *testing.T, m *Mesh) {
+	plain := NewMeshTriangles(m.TriangleSlice())
+	if plain.NeedsRepair() != m.NeedsRepair() {
+		t.Fatal("mismatched NeedsRepair()")
+	}
+	edges := map[Segment]bool{}
+	m.Iterate(func(tri *Triangle) {
+		expected := plain.Neighbors(tri)
+		actual := m.Neighbors(tri)
+		if len(expected) != len(actual) {
+			t.Fatalf("expected %d neighbors but got %d", len(expected), len(actual))
+		}
+		for _, seg := range tri.Segments() {
+			edges[seg] = true
+			if len(plain.EdgeTriangles(seg)) != len(m.EdgeTriangles(seg)) {
+				t.Fatal("mismatched edge triangles")
+			}
+		}
+	})
+	if m.edgeToFace != nil && m.edgeToFace.Len() != len(edges) {
+		t.Fatalf("expected %d cached edges but got %d", len(edges), m.edgeToFace.Len())
+	}
+}
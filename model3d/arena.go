@@ -0,0 +1,126 @@
+package model3d
+
+const DefaultTriangleArenaSlabSize = 4096
+
+// A TriangleArena allocates triangles in large slabs
+// rather than one at a time, reducing the number of
+// objects the garbage collector must track when building
+// large meshes.
+//
+// The zero value is an empty arena ready to use.
+//
+// A TriangleArena is not safe for concurrent use.
+// Concurrent routines should allocate from separate
+// arenas and combine them with Merge.
+type TriangleArena struct {
+	// SlabSize is the number of triangles allocated at
+	// once when the arena runs out of space.
+	//
+	// If 0, DefaultTriangleArenaSlabSize is used.
+	SlabSize int
+
+	slabs [][]Triangle
+	count int
+}
+
+// New allocates a triangle from the arena.
+func (t *TriangleArena) New(p1, p2, p3 Coord3D) *Triangle {
+	if len(t.slabs) == 0 {
+		t.addSlab()
+	}
+	slab := t.slabs[len(t.slabs)-1]
+	if len(slab) == cap(slab) {
+		t.addSlab()
+		slab = t.slabs[len(t.slabs)-1]
+	}
+	slab = append(slab, Triangle{p1, p2, p3})
+	t.slabs[len(t.slabs)-1] = slab
+	t.count++
+	return &slab[len(slab)-1]
+}
+
+// Len returns the number of triangles allocated from the
+// arena since it was created or last freed.
+func (t *TriangleArena) Len() int {
+	return t.count
+}
+
+// Merge moves all of the slabs from other into t, leaving
+// other empty.
+//
+// Triangles allocated from other remain valid.
+func (t *TriangleArena) Merge(other *TriangleArena) {
+	if len(other.slabs) == 0 {
+		return
+	}
+	if len(t.slabs) > 0 {
+		// Keep the partially filled slab last so that it
+		// continues to be used for new allocations.
+		last := t.slabs[len(t.slabs)-1]
+		t.slabs = append(t.slabs[:len(t.slabs)-1], other.slabs...)
+		t.slabs = append(t.slabs, last)
+	} else {
+		t.slabs = append(t.slabs, other.slabs...)
+	}
+	t.count += other.count
+	other.slabs = nil
+	other.count = 0
+}
+
+// Free releases all of the slabs held by the arena in
+// bulk.
+//
+// Triangles allocated from the arena are never reused, so
+// pointers which are still referenced elsewhere remain
+// valid; a slab is reclaimed by the garbage collector
+// once none of its triangles are referenced.
+func (t *TriangleArena) Free() {
+	t.slabs = nil
+	t.count = 0
+}
+
+func (t *TriangleArena) addSlab() {
+	size := t.SlabSize
+	if size == 0 {
+		size = DefaultTriangleArenaSlabSize
+	}
+	t.slabs = append(t.slabs, make([]Triangle, 0, size))
+}
+
+// NewMeshArena creates an empty mesh which takes
+// ownership of an arena.
+//
+// The arena is typically used to allocate the triangles
+// that are added to the mesh, and it is released when
+// the mesh is freed with Free().
+func NewMeshArena(a *TriangleArena) *Mesh {
+	m := NewMesh()
+	m.arena = a
+	return m
+}
+
+// Arena returns the arena owned by the mesh, or nil if
+// the mesh does not own an arena.
+//
+// Copies of a mesh, such as those produced by Copy() or
+// MapCoords(), never own the original mesh's arena.
+func (m *Mesh) Arena() *TriangleArena {
+	return m.arena
+}
+
+// Free removes all of the triangles from the mesh and, if
+// the mesh owns an arena, frees the arena in bulk.
+//
+// The mesh may be reused after calling Free, but it will
+// no longer own an arena.
+func (m *Mesh) Free() {
+	m.faces = map[*Triangle]bool{}
+	m.clearVertexToFace()
+	if m.edgeToFace != nil {
+		m.edgeToFace = NewEdgeToSlice[*Triangle]()
+	}
+	if m.arena != nil {
+		m.arena.Free()
+		m.arena = nil
+	}
+}
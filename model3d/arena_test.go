@@ -0,0 +1,73 @@
+package model3d
+
+import "testing"
+
+func TestTriangleArena(t *testing.T) {
+	arena := &TriangleArena{SlabSize: 7}
+	var tris []*Triangle
+	for i := 0; i < 30; i++ {
+		c := XYZ(float64(i), 0, 0)
+		tris = append(tris, arena.New(c, c.Add(X(1)), c.Add(Y(1))))
+	}
+	if arena.Len() != 30 {
+		t.Fatalf("unexpected length: %d", arena.Len())
+	}
+	for i, tri := range tris {
+		if tri[0].X != float64(i) || tri[1].X != float64(i+1) || tri[2].Y != 1 {
+			t.Fatalf("triangle %d was overwritten: %v", i, *tri)
+		}
+	}
+
+	other := &TriangleArena{}
+	other.New(X(1), Y(1), Z(1))
+	arena.Merge(other)
+	if arena.Len() != 31 || other.Len() != 0 {
+		t.Fatalf("unexpected lengths after merge: %d, %d", arena.Len(), other.Len())
+	}
+	last := arena.New(X(2), Y(2), Z(2))
+	if *last != (Triangle{X(2), Y(2), Z(2)}) || *tris[len(tris)-1] == *last {
+		t.Fatal("allocation after merge overwrote a triangle")
+	}
+
+	arena.Free()
+	if arena.Len() != 0 {
+		t.Fatal("arena not empty after free")
+	}
+	if tris[3][0].X != 3 {
+		t.Fatal("freed triangle was modified")
+	}
+}
+
+func TestMarchingCubesArena(t *testing.T) {
+	solid := &Sphere{Radius: 1}
+	for _, mesh := range []*Mesh{
+		MarchingCubesSearch(solid, 0.1, 8),
+		MarchingCubesFilter(solid, func(*Rect) bool { return true }, 0.1),
+	} {
+		if mesh.Arena() == nil {
+			t.Fatal("mesh should own an arena")
+		}
+		if mesh.Arena().Len() != len(mesh.TriangleSlice()) {
+			t.Errorf("expected %d arena triangles but got %d",
+				len(mesh.TriangleSlice()), mesh.Arena().Len())
+		}
+		MustValidateMesh(t, mesh, true)
+
+		cp := mesh.Copy()
+		if cp.Arena() != nil {
+			t.Error("copy should not own an arena")
+		}
+		mesh.Free()
+		if mesh.NumTriangles() != 0 || mesh.Arena() != nil {
+			t.Error("mesh should be empty after free")
+		}
+		MustValidateMesh(t, cp, true)
+	}
+}
+
+func BenchmarkMarchingCubesArena(b *testing.B) {
+	solid := &Sphere{Radius: 1}
+	for i := 0; i < b.N; i++ {
+		MarchingCubes(solid, 0.02).Free()
+	}
+}
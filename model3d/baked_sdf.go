@@ -0,0 +1,155 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A BakedSDF is an SDF which has been sampled on a regular
+// grid, and is evaluated with trilinear interpolation.
+//
+// Unlike NearSurfaceSDF, a BakedSDF never falls back to the
+// original SDF, so every query takes constant time. This
+// makes it useful for replacing an expensive SDF (such as
+// the result of MeshToSDF) in inner loops, at the cost of
+// some accuracy.
+//
+// If the original SDF is exact, the error of any query
+// inside the bounding box is at most sqrt(3)*Delta(), since
+// the SDF is 1-Lipschitz and the result is a weighted
+// average of the nearest grid corners. Near the surface,
+// the error is typically much smaller than this bound.
+type BakedSDF struct {
+	min   Coord3D
+	max   Coord3D
+	delta float64
+
+	numX int
+	numY int
+	numZ int
+
+	values []float64
+}
+
+// BakeSDF samples sdf on a grid with the given spacing.
+//
+// Smaller resolutions give more accurate results, but use
+// more memory and require more up-front computation.
+//
+// If resolution is 0, a spacing is chosen such that the
+// longest side of the bounding box spans roughly 64 cells.
+func BakeSDF(sdf SDF, resolution float64) *BakedSDF {
+	min, max := sdf.Min(), sdf.Max()
+	size := max.Sub(min)
+	if resolution == 0 {
+		resolution = size.MaxCoord() / 64
+	}
+	if resolution <= 0 {
+		panic("grid spacing must be positive")
+	}
+
+	res := &BakedSDF{
+		min:   min,
+		max:   max,
+		delta: resolution,
+		numX:  essentials.MaxInt(1, int(math.Ceil(size.X/resolution))) + 1,
+		numY:  essentials.MaxInt(1, int(math.Ceil(size.Y/resolution))) + 1,
+		numZ:  essentials.MaxInt(1, int(math.Ceil(size.Z/resolution))) + 1,
+	}
+	res.values = make([]float64, res.numX*res.numY*res.numZ)
+	essentials.ConcurrentMap(0, res.numZ, func(z int) {
+		idx := z * res.numX * res.numY
+		for y := 0; y < res.numY; y++ {
+			for x := 0; x < res.numX; x++ {
+				c := min.Add(XYZ(float64(x), float64(y), float64(z)).Scale(resolution))
+				res.values[idx] = sdf.SDF(c)
+				idx++
+			}
+		}
+	})
+	return res
+}
+
+// Min gets the minimum of the bounding box.
+func (b *BakedSDF) Min() Coord3D {
+	return b.min
+}
+
+// Max gets the maximum of the bounding box.
+func (b *BakedSDF) Max() Coord3D {
+	return b.max
+}
+
+// Delta gets the grid spacing.
+func (b *BakedSDF) Delta() float64 {
+	return b.delta
+}
+
+// SDF computes the interpolated SDF at c.
+//
+// Points outside of the grid are projected onto it, and
+// the distance to the grid is subtracted from the result.
+func (b *BakedSDF) SDF(c Coord3D) float64 {
+	return b.interpolate(c, 1/b.delta)
+}
+
+// BatchSDF computes the interpolated SDF for every point
+// in cs and writes the results to dst.
+//
+// This is equivalent to calling SDF on each point, but
+// avoids some per-query overhead.
+//
+// The slices must have the same length.
+func (b *BakedSDF) BatchSDF(dst []float64, cs []Coord3D) {
+	checkBatchLengths(len(dst), len(cs), len(cs))
+	cs = cs[:len(dst)]
+	invDelta := 1 / b.delta
+	for i := range dst {
+		dst[i] = b.interpolate(cs[i], invDelta)
+	}
+}
+
+func (b *BakedSDF) interpolate(c Coord3D, invDelta float64) float64 {
+	rel := c.Sub(b.min).Scale(invDelta)
+	x, fx, dx := bakedCellIndex(rel.X, b.numX)
+	y, fy, dy := bakedCellIndex(rel.Y, b.numY)
+	z, fz, dz := bakedCellIndex(rel.Z, b.numZ)
+
+	idx := x + b.numX*(y+b.numY*z)
+	strideY := b.numX
+	strideZ := b.numX * b.numY
+	v := b.values
+
+	v00 := v[idx]*(1-fx) + v[idx+1]*fx
+	v10 := v[idx+strideY]*(1-fx) + v[idx+strideY+1]*fx
+	v01 := v[idx+strideZ]*(1-fx) + v[idx+strideZ+1]*fx
+	v11 := v[idx+strideY+strideZ]*(1-fx) + v[idx+strideY+strideZ+1]*fx
+	v0 := v00*(1-fy) + v10*fy
+	v1 := v01*(1-fy) + v11*fy
+	result := v0*(1-fz) + v1*fz
+
+	if dx != 0 || dy != 0 || dz != 0 {
+		result -= math.Sqrt(dx*dx+dy*dy+dz*dz) * b.delta
+	}
+	return result
+}
+
+// bakedCellIndex gets the lower corner index and
+// fractional offset of a grid coordinate along one axis,
+// clamping the coordinate to the grid.
+//
+// The final result is the distance (in grid units) that
+// the coordinate was moved by clamping.
+func bakedCellIndex(rel float64, num int) (int, float64, float64) {
+	var outside float64
+	if rel < 0 {
+		outside = -rel
+		rel = 0
+	} else if max := float64(num - 1); rel > max {
+		outside = rel - max
+		rel = max
+	}
+	idx := essentials.MinInt(int(rel), num-2)
+	return idx, rel - float64(idx), outside
+}
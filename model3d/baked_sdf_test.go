@@ -0,0 +1,52 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBakedSDF(t *testing.T) {
+	sphere := &Sphere{Center: XYZ(0.3, -0.2, 0.1), Radius: 0.8}
+	mesh := NewMeshIcosphere(sphere.Center, sphere.Radius, 3)
+	for _, sdf := range []SDF{sphere, MeshToSDF(mesh)} {
+		baked := BakeSDF(sdf, 0.05)
+		bound := baked.Delta() * math.Sqrt(3)
+		for i := 0; i < 1000; i++ {
+			c := NewCoord3DRandBounds(sdf.Min(), sdf.Max())
+			expected := sdf.SDF(c)
+			actual := baked.SDF(c)
+			if math.Abs(actual-expected) > bound {
+				t.Fatalf("at %v: expected %f but got %f", c, expected, actual)
+			}
+		}
+
+		// Values at grid corners should be exact.
+		for _, c := range []Coord3D{sdf.Min(), sdf.Min().Add(XYZ(0.1, 0.2, 0.35))} {
+			if actual, expected := baked.SDF(c), sdf.SDF(c); math.Abs(actual-expected) > 1e-8 {
+				t.Errorf("at corner %v: expected %f but got %f", c, expected, actual)
+			}
+		}
+
+		// Points outside the grid should be outside the shape.
+		for _, c := range []Coord3D{sdf.Max().Add(XYZ(1, 0, 0)), sdf.Min().Sub(XYZ(1, 2, 3))} {
+			if actual := baked.SDF(c); actual >= 0 {
+				t.Errorf("at %v: expected negative value but got %f", c, actual)
+			}
+		}
+	}
+}
+
+func TestBakedSDFBatch(t *testing.T) {
+	baked := BakeSDF(&Sphere{Radius: 1}, 0)
+	cs := make([]Coord3D, 100)
+	for i := range cs {
+		cs[i] = NewCoord3DRandBounds(XYZ(-2, -2, -2), XYZ(2, 2, 2))
+	}
+	dst := make([]float64, len(cs))
+	baked.BatchSDF(dst, cs)
+	for i, c := range cs {
+		if expected := baked.SDF(c); dst[i] != expected {
+			t.Fatalf("at %v: expected %f but got %f", c, expected, dst[i])
+		}
+	}
+}
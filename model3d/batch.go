@@ -0,0 +1,109 @@
+package model3d
+
+// This file contains operations on slices of coordinates.
+//
+// These are portable Go loops rather than assembly, and
+// they produce the same results as the equivalent Coord3D
+// methods. The loops are written so that the compiler can
+// hoist bounds checks out of them, which makes some of
+// them (e.g. BatchDot and BatchMulColumn) modestly faster
+// than calling the methods in a loop over indices, as
+// measured by BenchmarkBatchOps.
+
+// BatchAdd computes dst[i] = a[i] + b[i].
+//
+// The slices must have the same length, and dst may alias
+// a or b.
+func BatchAdd(dst, a, b []Coord3D) {
+	checkBatchLengths(len(dst), len(a), len(b))
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		x, y := a[i], b[i]
+		dst[i] = Coord3D{X: x.X + y.X, Y: x.Y + y.Y, Z: x.Z + y.Z}
+	}
+}
+
+// BatchSub computes dst[i] = a[i] - b[i].
+//
+// The slices must have the same length, and dst may alias
+// a or b.
+func BatchSub(dst, a, b []Coord3D) {
+	checkBatchLengths(len(dst), len(a), len(b))
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		x, y := a[i], b[i]
+		dst[i] = Coord3D{X: x.X - y.X, Y: x.Y - y.Y, Z: x.Z - y.Z}
+	}
+}
+
+// BatchScale computes dst[i] = a[i] * s.
+//
+// The slices must have the same length, and dst may alias
+// a.
+func BatchScale(dst, a []Coord3D, s float64) {
+	checkBatchLengths(len(dst), len(a), len(a))
+	a = a[:len(dst)]
+	for i := range dst {
+		x := a[i]
+		dst[i] = Coord3D{X: x.X * s, Y: x.Y * s, Z: x.Z * s}
+	}
+}
+
+// BatchDot computes dst[i] = a[i].Dot(b[i]).
+//
+// The slices must have the same length.
+func BatchDot(dst []float64, a, b []Coord3D) {
+	checkBatchLengths(len(dst), len(a), len(b))
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		x, y := a[i], b[i]
+		dst[i] = x.X*y.X + x.Y*y.Y + x.Z*y.Z
+	}
+}
+
+// BatchCross computes dst[i] = a[i].Cross(b[i]).
+//
+// The slices must have the same length, and dst may alias
+// a or b.
+func BatchCross(dst, a, b []Coord3D) {
+	checkBatchLengths(len(dst), len(a), len(b))
+	a = a[:len(dst)]
+	b = b[:len(dst)]
+	for i := range dst {
+		x, y := a[i], b[i]
+		dst[i] = Coord3D{
+			X: x.Y*y.Z - x.Z*y.Y,
+			Y: x.Z*y.X - x.X*y.Z,
+			Z: x.X*y.Y - x.Y*y.X,
+		}
+	}
+}
+
+// BatchMulColumn computes dst[i] = m.MulColumn(a[i]).
+//
+// The slices must have the same length, and dst may alias
+// a.
+func (m *Matrix3) BatchMulColumn(dst, a []Coord3D) {
+	checkBatchLengths(len(dst), len(a), len(a))
+	a = a[:len(dst)]
+	m00, m01, m02 := m[0], m[1], m[2]
+	m10, m11, m12 := m[3], m[4], m[5]
+	m20, m21, m22 := m[6], m[7], m[8]
+	for i := range dst {
+		c := a[i]
+		dst[i] = Coord3D{
+			X: m00*c.X + m01*c.Y + m02*c.Z,
+			Y: m10*c.X + m11*c.Y + m12*c.Z,
+			Z: m20*c.X + m21*c.Y + m22*c.Z,
+		}
+	}
+}
+
+func checkBatchLengths(n1, n2, n3 int) {
+	if n1 != n2 || n1 != n3 {
+		panic("mismatched slice lengths")
+	}
+}
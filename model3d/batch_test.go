@@ -0,0 +1,152 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBatchOps(t *testing.T) {
+	a := make([]Coord3D, 37)
+	b := make([]Coord3D, len(a))
+	for i := range a {
+		a[i] = NewCoord3DRandNorm()
+		b[i] = NewCoord3DRandNorm()
+	}
+	matrix := NewMatrix3Rotation(XYZ(1, 2, 3).Normalize(), 0.7)
+
+	sums := make([]Coord3D, len(a))
+	diffs := make([]Coord3D, len(a))
+	scaled := make([]Coord3D, len(a))
+	crosses := make([]Coord3D, len(a))
+	products := make([]Coord3D, len(a))
+	dots := make([]float64, len(a))
+	BatchAdd(sums, a, b)
+	BatchSub(diffs, a, b)
+	BatchScale(scaled, a, 3)
+	BatchCross(crosses, a, b)
+	BatchDot(dots, a, b)
+	matrix.BatchMulColumn(products, a)
+
+	for i, x := range a {
+		y := b[i]
+		if sums[i].Dist(x.Add(y)) > 1e-8 {
+			t.Errorf("bad sum at %d", i)
+		}
+		if diffs[i].Dist(x.Sub(y)) > 1e-8 {
+			t.Errorf("bad difference at %d", i)
+		}
+		if scaled[i].Dist(x.Scale(3)) > 1e-8 {
+			t.Errorf("bad scale at %d", i)
+		}
+		if crosses[i].Dist(x.Cross(y)) > 1e-8 {
+			t.Errorf("bad cross product at %d", i)
+		}
+		if math.Abs(dots[i]-x.Dot(y)) > 1e-8 {
+			t.Errorf("bad dot product at %d", i)
+		}
+		if products[i].Dist(matrix.MulColumn(x)) > 1e-8 {
+			t.Errorf("bad matrix product at %d", i)
+		}
+	}
+
+	// Aliasing the output with an input should work.
+	aliased := append([]Coord3D{}, a...)
+	BatchCross(aliased, aliased, b)
+	for i, c := range aliased {
+		if c.Dist(crosses[i]) > 1e-8 {
+			t.Fatalf("bad aliased cross product at %d", i)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for mismatched lengths")
+		}
+	}()
+	BatchAdd(sums, a, b[1:])
+}
+
+func BenchmarkBatchOps(b *testing.B) {
+	x := make([]Coord3D, 10000)
+	y := make([]Coord3D, len(x))
+	for i := range x {
+		x[i] = NewCoord3DRandNorm()
+		y[i] = NewCoord3DRandNorm()
+	}
+	out := make([]Coord3D, len(x))
+	dots := make([]float64, len(x))
+	matrix := NewMatrix3Rotation(XYZ(1, 2, 3).Normalize(), 0.7)
+
+	b.Run("AddLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				out[j] = c.Add(y[j])
+			}
+		}
+	})
+	b.Run("AddBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchAdd(out, x, y)
+		}
+	})
+	b.Run("SubLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				out[j] = c.Sub(y[j])
+			}
+		}
+	})
+	b.Run("SubBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchSub(out, x, y)
+		}
+	})
+	b.Run("ScaleLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				out[j] = c.Scale(3)
+			}
+		}
+	})
+	b.Run("ScaleBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchScale(out, x, 3)
+		}
+	})
+	b.Run("DotLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				dots[j] = c.Dot(y[j])
+			}
+		}
+	})
+	b.Run("DotBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchDot(dots, x, y)
+		}
+	})
+	b.Run("CrossLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				out[j] = c.Cross(y[j])
+			}
+		}
+	})
+	b.Run("CrossBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchCross(out, x, y)
+		}
+	})
+	b.Run("MulColumnLoop", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, c := range x {
+				out[j] = matrix.MulColumn(c)
+			}
+		}
+	})
+	b.Run("MulColumnBatch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matrix.BatchMulColumn(out, x)
+		}
+	})
+}
@@ -0,0 +1,293 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+// BiharmonicMaxActiveSetIters is the maximum number of
+// active set iterations used by BoundedBiharmonicWeights.
+const BiharmonicMaxActiveSetIters = 20
+
+// BiharmonicWeights computes smooth handle weights for
+// every vertex of a mesh by minimizing the Laplacian
+// energy of each weight function.
+//
+// Each handle is a set of vertices, which receive weight
+// one for their own handle and zero for every other
+// handle. Weights for the remaining vertices interpolate
+// smoothly between the handles and sum to one, but may be
+// negative or greater than one.
+// See BoundedBiharmonicWeights for weights without this
+// problem.
+//
+// Every connected component of the mesh must contain at
+// least one handle vertex, and no vertex may belong to
+// more than one handle.
+func BiharmonicWeights(m *Mesh, handles [][]Coord3D) *CoordMap[[]float64] {
+	return biharmonicWeights(m, handles, false)
+}
+
+// BoundedBiharmonicWeights is like BiharmonicWeights, but
+// additionally constrains the weights to the range [0, 1].
+//
+// The bounds are enforced with a simple active set method,
+// which fixes out-of-bounds vertices to the nearest bound
+// and re-solves for the remaining vertices. The weights
+// are then renormalized to sum to one.
+// This closely approximates bounded biharmonic weights
+// (Jacobson et al., 2011), which produce more local and
+// intuitive deformations than unbounded weights.
+func BoundedBiharmonicWeights(m *Mesh, handles [][]Coord3D) *CoordMap[[]float64] {
+	return biharmonicWeights(m, handles, true)
+}
+
+// HandleDeform deforms a mesh by blending handle
+// transformations according to per-vertex handle weights,
+// such as those from BoundedBiharmonicWeights.
+//
+// Each vertex is mapped to the weighted sum of the
+// transformed vertex under every handle's transformation.
+func HandleDeform(m *Mesh, weights *CoordMap[[]float64], transforms []Transform) *Mesh {
+	return m.MapCoords(func(c Coord3D) Coord3D {
+		ws, ok := weights.Load(c)
+		if !ok {
+			panic("missing handle weights for vertex")
+		} else if len(ws) != len(transforms) {
+			panic("mismatched number of weights and transforms")
+		}
+		var res Coord3D
+		for i, w := range ws {
+			if w != 0 {
+				res = res.Add(transforms[i].Apply(c).Scale(w))
+			}
+		}
+		return res
+	})
+}
+
+func biharmonicWeights(m *Mesh, handles [][]Coord3D, bounded bool) *CoordMap[[]float64] {
+	coords := m.VertexSlice()
+	coordToIdx := NewCoordMap[int]()
+	for i, c := range coords {
+		coordToIdx.Store(c, i)
+	}
+
+	handleIdx := make([]int, len(coords))
+	for i := range handleIdx {
+		handleIdx[i] = -1
+	}
+	for i, handle := range handles {
+		for _, c := range handle {
+			idx, ok := coordToIdx.Load(c)
+			if !ok {
+				panic("handle coordinate is not a vertex of the mesh")
+			} else if handleIdx[idx] != -1 && handleIdx[idx] != i {
+				panic("vertex belongs to multiple handles")
+			}
+			handleIdx[idx] = i
+		}
+	}
+
+	q := newBilaplacian(m, coords, coordToIdx)
+
+	allWeights := make([][]float64, len(handles))
+	if !bounded {
+		solver := newBilaplacianSolver(q, handleIdx, nil)
+		for i := range handles {
+			allWeights[i] = solver.Solve(func(j int) float64 {
+				if handleIdx[j] == i {
+					return 1
+				}
+				return 0
+			})
+		}
+	} else {
+		for i := range handles {
+			allWeights[i] = boundedBiharmonicWeight(q, handleIdx, i)
+		}
+	}
+
+	res := NewCoordMap[[]float64]()
+	for j, c := range coords {
+		ws := make([]float64, len(handles))
+		var sum float64
+		for i, w := range allWeights {
+			ws[i] = w[j]
+			sum += w[j]
+		}
+		if bounded && sum > 0 {
+			for i := range ws {
+				ws[i] /= sum
+			}
+		}
+		res.Store(c, ws)
+	}
+	return res
+}
+
+func boundedBiharmonicWeight(q [][]bilaplacianEntry, handleIdx []int, handle int) []float64 {
+	fixed := map[int]float64{}
+	var weights []float64
+	for iter := 0; iter < BiharmonicMaxActiveSetIters; iter++ {
+		solver := newBilaplacianSolver(q, handleIdx, fixed)
+		weights = solver.Solve(func(j int) float64 {
+			if handleIdx[j] == handle {
+				return 1
+			} else if x, ok := fixed[j]; ok {
+				return x
+			}
+			return 0
+		})
+		var changed bool
+		for j, w := range weights {
+			if w < 0 {
+				fixed[j] = 0
+				changed = true
+			} else if w > 1 {
+				fixed[j] = 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	for j, w := range weights {
+		weights[j] = math.Max(0, math.Min(1, w))
+	}
+	return weights
+}
+
+type bilaplacianEntry struct {
+	Col   int
+	Value float64
+}
+
+// newBilaplacian computes the sparse matrix L*M^-1*L,
+// where L is the cotangent Laplacian and M is the lumped
+// mass matrix of the mesh.
+func newBilaplacian(m *Mesh, coords []Coord3D, coordToIdx *CoordMap[int]) [][]bilaplacianEntry {
+	laplacian := make([]map[int]float64, len(coords))
+	for i := range laplacian {
+		laplacian[i] = map[int]float64{}
+	}
+	mass := make([]float64, len(coords))
+	m.Iterate(func(t *Triangle) {
+		var idxs [3]int
+		for i, c := range t {
+			idxs[i] = coordToIdx.Value(c)
+		}
+		area := t.Area()
+		for i := 0; i < 3; i++ {
+			mass[idxs[i]] += area / 3
+
+			// The cotangent of the angle at vertex i
+			// weights the opposite edge.
+			c1, c2 := t[(i+1)%3], t[(i+2)%3]
+			v1 := c1.Sub(t[i])
+			v2 := c2.Sub(t[i])
+			cosTheta := v1.Normalize().Dot(v2.Normalize())
+			w := cosTheta / math.Sqrt(math.Max(1e-16, 1-cosTheta*cosTheta)) / 2
+
+			i1, i2 := idxs[(i+1)%3], idxs[(i+2)%3]
+			laplacian[i1][i2] -= w
+			laplacian[i2][i1] -= w
+			laplacian[i1][i1] += w
+			laplacian[i2][i2] += w
+		}
+	})
+
+	var maxMass float64
+	for _, x := range mass {
+		maxMass = math.Max(maxMass, x)
+	}
+
+	rows := make([]map[int]float64, len(coords))
+	for i := range rows {
+		rows[i] = map[int]float64{}
+	}
+	for j, row := range laplacian {
+		invMass := 1 / math.Max(mass[j], maxMass*1e-8)
+		for i, x1 := range row {
+			for k, x2 := range row {
+				rows[i][k] += x1 * x2 * invMass
+			}
+		}
+	}
+
+	res := make([][]bilaplacianEntry, len(coords))
+	for i, row := range rows {
+		for j, x := range row {
+			res[i] = append(res[i], bilaplacianEntry{Col: j, Value: x})
+		}
+	}
+	return res
+}
+
+type bilaplacianSolver struct {
+	q            [][]bilaplacianEntry
+	fullToFree   []int
+	freeToFull   []int
+	factorized   *numerical.SparseCholesky
+	freeVertices int
+}
+
+func newBilaplacianSolver(q [][]bilaplacianEntry, handleIdx []int,
+	fixed map[int]float64) *bilaplacianSolver {
+	res := &bilaplacianSolver{
+		q:          q,
+		fullToFree: make([]int, len(q)),
+	}
+	for i := range q {
+		_, isFixed := fixed[i]
+		if handleIdx[i] != -1 || isFixed {
+			res.fullToFree[i] = -1
+		} else {
+			res.fullToFree[i] = len(res.freeToFull)
+			res.freeToFull = append(res.freeToFull, i)
+		}
+	}
+	res.freeVertices = len(res.freeToFull)
+	if res.freeVertices == 0 {
+		return res
+	}
+	mat := numerical.NewSparseMatrix(res.freeVertices)
+	for i, full := range res.freeToFull {
+		for _, entry := range q[full] {
+			if j := res.fullToFree[entry.Col]; j != -1 {
+				mat.Set(i, j, entry.Value)
+			}
+		}
+	}
+	res.factorized = numerical.NewSparseCholesky(mat)
+	return res
+}
+
+// Solve minimizes the quadratic energy subject to the
+// fixed vertices taking the values returned by boundary.
+func (b *bilaplacianSolver) Solve(boundary func(idx int) float64) []float64 {
+	res := make([]float64, len(b.q))
+	for i, j := range b.fullToFree {
+		if j == -1 {
+			res[i] = boundary(i)
+		}
+	}
+	if b.freeVertices == 0 {
+		return res
+	}
+	rhs := make(numerical.Vec, b.freeVertices)
+	for i, full := range b.freeToFull {
+		for _, entry := range b.q[full] {
+			if b.fullToFree[entry.Col] == -1 {
+				rhs[i] -= entry.Value * res[entry.Col]
+			}
+		}
+	}
+	solution := b.factorized.ApplyInverse(rhs)
+	for i, full := range b.freeToFull {
+		res[full] = solution[i]
+	}
+	return res
+}
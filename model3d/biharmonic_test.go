@@ -0,0 +1,59 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBiharmonicWeights(t *testing.T) {
+	mesh := MarchingCubesSearch(&Rect{MinVal: XYZ(0, -0.3, -0.3), MaxVal: XYZ(4, 0.3, 0.3)}, 0.1, 8)
+	var left, right []Coord3D
+	for _, c := range mesh.VertexSlice() {
+		if c.X < 0.3 {
+			left = append(left, c)
+		} else if c.X > 3.7 {
+			right = append(right, c)
+		}
+	}
+	handles := [][]Coord3D{left, right}
+
+	for _, bounded := range []bool{false, true} {
+		var weights *CoordMap[[]float64]
+		if bounded {
+			weights = BoundedBiharmonicWeights(mesh, handles)
+		} else {
+			weights = BiharmonicWeights(mesh, handles)
+		}
+		for i, handle := range handles {
+			for _, c := range handle {
+				if w := weights.Value(c)[i]; w != 1 {
+					t.Fatalf("bounded=%v: expected handle weight 1 but got %f", bounded, w)
+				}
+			}
+		}
+		for _, c := range mesh.VertexSlice() {
+			ws := weights.Value(c)
+			if sum := ws[0] + ws[1]; math.Abs(sum-1) > 1e-5 {
+				t.Fatalf("bounded=%v: weights sum to %f", bounded, sum)
+			}
+			if bounded && (ws[0] < 0 || ws[0] > 1 || ws[1] < 0 || ws[1] > 1) {
+				t.Fatalf("out of bounds weights: %v", ws)
+			}
+			if c.X > 1.8 && c.X < 2.2 && math.Abs(ws[0]-0.5) > 0.1 {
+				t.Fatalf("bounded=%v: unexpected weights in middle: %v", bounded, ws)
+			}
+			if c.X > 3 && ws[1] < ws[0] {
+				t.Fatalf("bounded=%v: unexpected weights near right: %v", bounded, ws)
+			}
+		}
+
+		deformed := HandleDeform(mesh, weights, []Transform{
+			JoinedTransform{},
+			&Translate{Offset: Z(1)},
+		})
+		MustValidateMesh(t, deformed, true)
+		if max := deformed.Max().Z; math.Abs(max-(mesh.Max().Z+1)) > 1e-5 {
+			t.Errorf("bounded=%v: unexpected max Z: %f", bounded, max)
+		}
+	}
+}
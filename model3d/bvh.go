@@ -4,9 +4,15 @@ package model3d
 
 import (
 	"math"
+	"runtime"
 	"sort"
+	"sync"
 )
 
+// parallelBVHMinSize is the minimum number of objects in
+// a sub-tree for it to be built on its own Goroutine.
+const parallelBVHMinSize = 1 << 12
+
 // BVH represents a (possibly unbalanced) axis-aligned
 // bounding box hierarchy.
 //
@@ -21,6 +27,13 @@ type BVH[B Bounder] struct {
 
 	// Branch, if Leaf is nil, points to two children.
 	Branch []*BVH[B]
+
+	// Cached bounding box, which is computed when the BVH
+	// is built or by Refit(), and maintained by Insert()
+	// and Remove().
+	min       Coord3D
+	max       Coord3D
+	hasBounds bool
 }
 
 // NewBVHAreaDensity creates a BVH by minimizing
@@ -29,23 +42,191 @@ type BVH[B Bounder] struct {
 // each branch.
 //
 // This is good for efficient ray collision detection.
+//
+// Large hierarchies are built in parallel using up to
+// GOMAXPROCS Goroutines.
 func NewBVHAreaDensity[B Bounder](objects []B) *BVH[B] {
 	return newBVH(sortBounders(objects), make([]float64, len(objects)),
-		areaDensityBVHSplit[B])
+		areaDensityBVHSplit[B], runtime.GOMAXPROCS(0))
+}
+
+// Min gets the minimum point of the bounding box of the
+// BVH.
+//
+// BVHs created by this package store their bounds. For
+// BVHs constructed by hand, the bounds are recomputed on
+// every call until Refit() is called.
+func (b *BVH[B]) Min() Coord3D {
+	min, _ := b.bounds()
+	return min
+}
+
+// Max gets the maximum point of the bounding box of the
+// BVH.
+//
+// See Min() for details on how the bounds are computed.
+func (b *BVH[B]) Max() Coord3D {
+	_, max := b.bounds()
+	return max
+}
+
+// Refit recomputes the cached bounding boxes of every
+// node in the BVH from the current bounds of its leaves.
+//
+// This should be called after the leaves have moved, for
+// example after the triangles of a deforming mesh have
+// been modified in place. The structure of the tree is
+// not changed, so it remains correct, but it may become
+// less efficient if leaves move far from where they were
+// when the BVH was built.
+//
+// Colliders created from the BVH keep their own copies
+// of the bounds, and can be updated with
+// JoinedCollider.Refit().
+func (b *BVH[B]) Refit() {
+	if len(b.Branch) == 0 {
+		b.min, b.max = b.Leaf.Min(), b.Leaf.Max()
+		b.hasBounds = true
+	} else {
+		for _, child := range b.Branch {
+			child.Refit()
+		}
+		b.updateBranchBounds()
+	}
+}
+
+// Insert adds a new leaf to the BVH without rebuilding
+// it.
+//
+// The leaf is placed in the branch whose bounding box
+// grows the least, so the quality of the tree slowly
+// degrades as more leaves are inserted.
+func (b *BVH[B]) Insert(obj B) {
+	if !b.hasBounds {
+		b.Refit()
+	}
+	b.insert(obj, obj.Min(), obj.Max())
+}
+
+func (b *BVH[B]) insert(obj B, min, max Coord3D) {
+	if len(b.Branch) == 0 {
+		var zero B
+		b.Branch = []*BVH[B]{
+			{Leaf: b.Leaf, min: b.min, max: b.max, hasBounds: true},
+			{Leaf: obj, min: min, max: max, hasBounds: true},
+		}
+		b.Leaf = zero
+	} else {
+		var best *BVH[B]
+		var bestGrowth float64
+		for i, child := range b.Branch {
+			growth := boundsArea(child.min.Min(min), child.max.Max(max)) -
+				boundsArea(child.min, child.max)
+			if i == 0 || growth < bestGrowth {
+				best = child
+				bestGrowth = growth
+			}
+		}
+		best.insert(obj, min, max)
+	}
+	b.min = b.min.Min(min)
+	b.max = b.max.Max(max)
+}
+
+// Remove deletes a leaf from the BVH without rebuilding
+// it, and returns true if the leaf was found.
+//
+// The leaf is the first one for which equal(leaf, obj)
+// returns true. For pointer types, equal will typically
+// compare the pointers, e.g. func(x, y *T) bool { return
+// x == y }.
+//
+// Leaves are located using the cached bounds, so Refit()
+// must be called before Remove() if the leaf has moved
+// since the bounds were last computed.
+//
+// The final leaf of a BVH cannot be removed, since a BVH
+// may not be empty. In this case, false is returned.
+func (b *BVH[B]) Remove(obj B, equal func(leaf, obj B) bool) bool {
+	if len(b.Branch) == 0 {
+		return false
+	}
+	if !b.hasBounds {
+		b.Refit()
+	}
+	return b.remove(obj, equal, obj.Min(), obj.Max())
+}
+
+func (b *BVH[B]) remove(obj B, equal func(leaf, obj B) bool, min, max Coord3D) bool {
+	for i, child := range b.Branch {
+		if !boundsContain(child.min, child.max, min, max) {
+			continue
+		}
+		if len(child.Branch) == 0 {
+			if !equal(child.Leaf, obj) {
+				continue
+			}
+			b.Branch = append(b.Branch[:i:i], b.Branch[i+1:]...)
+		} else if !child.remove(obj, equal, min, max) {
+			continue
+		}
+		if len(b.Branch) == 1 {
+			*b = *b.Branch[0]
+		} else {
+			b.updateBranchBounds()
+		}
+		return true
+	}
+	return false
+}
+
+// bounds gets the cached bounds, or computes them without
+// modifying the tree if they have not been cached, so that
+// it is safe to call from multiple Goroutines.
+func (b *BVH[B]) bounds() (min, max Coord3D) {
+	if b.hasBounds {
+		return b.min, b.max
+	} else if len(b.Branch) == 0 {
+		return b.Leaf.Min(), b.Leaf.Max()
+	}
+	min, max = b.Branch[0].bounds()
+	for _, child := range b.Branch[1:] {
+		min1, max1 := child.bounds()
+		min = min.Min(min1)
+		max = max.Max(max1)
+	}
+	return
+}
+
+// updateBranchBounds sets the cached bounds of a branch
+// from the cached bounds of its children.
+func (b *BVH[B]) updateBranchBounds() {
+	b.min, b.max = b.Branch[0].min, b.Branch[0].max
+	for _, child := range b.Branch[1:] {
+		b.min = b.min.Min(child.min)
+		b.max = b.max.Max(child.max)
+	}
+	b.hasBounds = true
+}
+
+func newBVHLeaf[B Bounder](obj B) *BVH[B] {
+	return &BVH[B]{Leaf: obj, min: obj.Min(), max: obj.Max(), hasBounds: true}
 }
 
 func newBVH[B Bounder](sortedBounders [3][]*flaggedBounder[B], cache []float64,
-	splitter func([]*flaggedBounder[B], []float64) (int, float64)) *BVH[B] {
+	splitter func([]*flaggedBounder[B], []float64) (int, float64), gos int) *BVH[B] {
 	numObjs := len(sortedBounders[0])
 	if numObjs == 0 {
 		panic("empty sorted objects")
 	} else if numObjs == 1 {
-		return &BVH[B]{Leaf: sortedBounders[0][0].B}
+		return newBVHLeaf(sortedBounders[0][0].B)
 	} else if numObjs == 2 {
-		return &BVH[B]{Branch: []*BVH[B]{
-			{Leaf: sortedBounders[0][0].B},
-			{Leaf: sortedBounders[0][1].B},
+		res := &BVH[B]{Branch: []*BVH[B]{
+			newBVHLeaf(sortedBounders[0][0].B),
+			newBVHLeaf(sortedBounders[0][1].B),
 		}}
+		res.updateBranchBounds()
+		return res
 	}
 
 	xIndex, xScore := splitter(sortedBounders[0], cache)
@@ -60,12 +241,18 @@ func newBVH[B Bounder](sortedBounders [3][]*flaggedBounder[B], cache []float64,
 	} else {
 		split = splitBounders(sortedBounders, 2, zIndex)
 	}
-	return &BVH[B]{
-		Branch: []*BVH[B]{
-			newBVH(split[0], cache, splitter),
-			newBVH(split[1], cache, splitter),
-		},
-	}
+
+	// Each half gets its own part of the cache so that they
+	// may be built concurrently.
+	n0 := len(split[0][0])
+	res := &BVH[B]{Branch: make([]*BVH[B], 2)}
+	parallelSplit(numObjs, gos, func(gos int) {
+		res.Branch[0] = newBVH(split[0], cache[:n0], splitter, gos)
+	}, func(gos int) {
+		res.Branch[1] = newBVH(split[1], cache[n0:], splitter, gos)
+	})
+	res.updateBranchBounds()
+	return res
 }
 
 // areaDensityBVHSplit chooses a split index that
@@ -128,11 +315,15 @@ func GroupTriangles(faces []*Triangle) {
 // To cut a slice in half, divide the length by two, round
 // down, and use the result as the start index for the
 // second half.
+//
+// Large slices are grouped in parallel using up to
+// GOMAXPROCS Goroutines.
 func GroupBounders[B Bounder](objects []B) {
-	groupBounders(sortBounders(objects), objects)
+	groupBounders(sortBounders(objects), objects, runtime.GOMAXPROCS(0))
 }
 
-func groupBounders[B Bounder](sortedBounders [3][]*flaggedBounder[B], output []B) {
+func groupBounders[B Bounder](sortedBounders [3][]*flaggedBounder[B], output []B,
+	gos int) {
 	numObjs := len(sortedBounders[0])
 	if numObjs == 2 {
 		// The area-based splitting criterion doesn't
@@ -151,8 +342,32 @@ func groupBounders[B Bounder](sortedBounders [3][]*flaggedBounder[B], output []B
 	axis := bestSplitAxis(sortedBounders)
 
 	separated := splitBounders(sortedBounders, axis, midIdx)
-	groupBounders(separated[0], output[:midIdx])
-	groupBounders(separated[1], output[midIdx:])
+	parallelSplit(numObjs, gos, func(gos int) {
+		groupBounders(separated[0], output[:midIdx], gos)
+	}, func(gos int) {
+		groupBounders(separated[1], output[midIdx:], gos)
+	})
+}
+
+// parallelSplit runs two recursive sub-problems, dividing
+// a budget of Goroutines between them.
+//
+// If the budget allows it and the problem is large
+// enough, the sub-problems are run concurrently.
+func parallelSplit(size, gos int, f1, f2 func(gos int)) {
+	if gos < 2 || size < parallelBVHMinSize {
+		f1(1)
+		f2(1)
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f1(gos / 2)
+	}()
+	f2(gos - gos/2)
+	wg.Wait()
 }
 
 func splitBounders[B Bounder](sortedBounders [3][]*flaggedBounder[B],
@@ -231,29 +446,43 @@ func sortBounders[B Bounder](bs []B) [3][]*flaggedBounder[B] {
 	}
 
 	var result [3][]*flaggedBounder[B]
+	var wg sync.WaitGroup
 	for axis := range result {
-		bsCopy := make([]*flaggedBounder[B], len(flagged))
-		for i := range flagged {
-			bsCopy[i] = &flagged[i]
-		}
-		if axis == 0 {
-			sort.Slice(bsCopy, func(i, j int) bool {
-				return bsCopy[i].Mid.X < bsCopy[j].Mid.X
-			})
-		} else if axis == 1 {
-			sort.Slice(bsCopy, func(i, j int) bool {
-				return bsCopy[i].Mid.Y < bsCopy[j].Mid.Y
-			})
-		} else {
-			sort.Slice(bsCopy, func(i, j int) bool {
-				return bsCopy[i].Mid.Z < bsCopy[j].Mid.Z
-			})
+		if len(bs) < parallelBVHMinSize {
+			result[axis] = sortBoundersAxis(flagged, axis)
+			continue
 		}
-		result[axis] = bsCopy
+		wg.Add(1)
+		go func(axis int) {
+			defer wg.Done()
+			result[axis] = sortBoundersAxis(flagged, axis)
+		}(axis)
 	}
+	wg.Wait()
 	return result
 }
 
+func sortBoundersAxis[B Bounder](flagged []flaggedBounder[B], axis int) []*flaggedBounder[B] {
+	bsCopy := make([]*flaggedBounder[B], len(flagged))
+	for i := range flagged {
+		bsCopy[i] = &flagged[i]
+	}
+	if axis == 0 {
+		sort.Slice(bsCopy, func(i, j int) bool {
+			return bsCopy[i].Mid.X < bsCopy[j].Mid.X
+		})
+	} else if axis == 1 {
+		sort.Slice(bsCopy, func(i, j int) bool {
+			return bsCopy[i].Mid.Y < bsCopy[j].Mid.Y
+		})
+	} else {
+		sort.Slice(bsCopy, func(i, j int) bool {
+			return bsCopy[i].Mid.Z < bsCopy[j].Mid.Z
+		})
+	}
+	return bsCopy
+}
+
 func multipleBoundsArea[B Bounder](bs []*flaggedBounder[B]) float64 {
 	min, max := bs[0].Min, bs[0].Max
 	for i := 1; i < len(bs); i++ {
@@ -286,6 +515,10 @@ func multipleBoundsArea[B Bounder](bs []*flaggedBounder[B]) float64 {
 	return boundsArea(min, max)
 }
 
+func boundsContain(outerMin, outerMax, innerMin, innerMax Coord3D) bool {
+	return outerMin.Min(innerMin) == outerMin && outerMax.Max(innerMax) == outerMax
+}
+
 func boundsArea(min, max Coord3D) float64 {
 	diff := max.Sub(min)
 	return 2 * (diff.X*(diff.Y+diff.Z) + diff.Y*diff.Z)
@@ -0,0 +1,331 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// CageMethod determines how the points of a mesh are
+// expressed in terms of a deformation cage.
+type CageMethod int
+
+const (
+	// CageMeanValue uses mean value coordinates, which
+	// express every point as an affine combination of the
+	// cage vertices.
+	// This reproduces affine deformations of the cage
+	// exactly.
+	CageMeanValue CageMethod = iota
+
+	// CageGreen uses Green coordinates, which additionally
+	// depend on the normals of the cage faces.
+	// Deformations are quasi-conformal, so details of the
+	// mesh are rotated and scaled rather than sheared.
+	CageGreen
+)
+
+// A CageDeformer deforms a high-resolution mesh by moving
+// the vertices of a coarse cage mesh which encloses it.
+//
+// The cage must be a closed, manifold mesh with outward
+// facing normals, and it should contain every vertex of
+// the deformed mesh.
+type CageDeformer struct {
+	method CageMethod
+
+	cageCoords    []Coord3D
+	cageTriangles [][3]int
+
+	mesh          *Mesh
+	coordToIdx    *CoordMap[int]
+	vertexWeights [][]float64
+	faceWeights   [][]float64
+}
+
+// NewCageDeformer computes the coordinates of every vertex
+// of m with respect to the cage.
+//
+// This is the expensive step of a cage deformation, and
+// afterwards Deform() can be called cheaply for many
+// different cage poses.
+func NewCageDeformer(cage, m *Mesh, method CageMethod) *CageDeformer {
+	cageIdx := NewCoordMap[int]()
+	var cageCoords []Coord3D
+	var cageTriangles [][3]int
+	for _, t := range cage.TriangleSlice() {
+		var tri [3]int
+		for i, c := range t {
+			idx, ok := cageIdx.Load(c)
+			if !ok {
+				idx = len(cageCoords)
+				cageIdx.Store(c, idx)
+				cageCoords = append(cageCoords, c)
+			}
+			tri[i] = idx
+		}
+		cageTriangles = append(cageTriangles, tri)
+	}
+
+	res := &CageDeformer{
+		method:        method,
+		cageCoords:    cageCoords,
+		cageTriangles: cageTriangles,
+		mesh:          m,
+		coordToIdx:    NewCoordMap[int](),
+	}
+
+	coords := m.VertexSlice()
+	for i, c := range coords {
+		res.coordToIdx.Store(c, i)
+	}
+	res.vertexWeights = make([][]float64, len(coords))
+	if method == CageGreen {
+		res.faceWeights = make([][]float64, len(coords))
+	}
+	essentials.ConcurrentMap(0, len(coords), func(i int) {
+		switch method {
+		case CageMeanValue:
+			res.vertexWeights[i] = res.meanValueCoordinates(coords[i])
+		case CageGreen:
+			res.vertexWeights[i], res.faceWeights[i] = res.greenCoordinates(coords[i])
+		default:
+			panic("unknown cage method")
+		}
+	})
+	return res
+}
+
+// Deform creates a deformed version of the mesh, given a
+// function which moves each vertex of the original cage.
+//
+// The deformed cage must have the same connectivity as the
+// original, so f should only be used to move vertices.
+func (c *CageDeformer) Deform(f func(Coord3D) Coord3D) *Mesh {
+	return c.mesh.MapCoords(c.DeformFunc(f))
+}
+
+// DeformFunc is like Deform, but rather than creating a
+// mesh, it returns a function which maps each vertex of the
+// original mesh to its deformed position.
+//
+// The resulting function panics if it is called on a
+// coordinate which is not a vertex of the original mesh.
+func (c *CageDeformer) DeformFunc(f func(Coord3D) Coord3D) func(Coord3D) Coord3D {
+	newCage := make([]Coord3D, len(c.cageCoords))
+	for i, p := range c.cageCoords {
+		newCage[i] = f(p)
+	}
+
+	var faceTerms []Coord3D
+	if c.method == CageGreen {
+		faceTerms = make([]Coord3D, len(c.cageTriangles))
+		for i, t := range c.cageTriangles {
+			oldTri := Triangle{c.cageCoords[t[0]], c.cageCoords[t[1]], c.cageCoords[t[2]]}
+			newTri := Triangle{newCage[t[0]], newCage[t[1]], newCage[t[2]]}
+			faceTerms[i] = newTri.Normal().Scale(greenStretch(&oldTri, &newTri))
+		}
+	}
+
+	return func(p Coord3D) Coord3D {
+		idx, ok := c.coordToIdx.Load(p)
+		if !ok {
+			panic("coordinate is not a vertex of the mesh")
+		}
+		var res Coord3D
+		for i, w := range c.vertexWeights[idx] {
+			res = res.Add(newCage[i].Scale(w))
+		}
+		if faceTerms != nil {
+			for i, w := range c.faceWeights[idx] {
+				res = res.Add(faceTerms[i].Scale(w))
+			}
+		}
+		return res
+	}
+}
+
+// meanValueCoordinates computes the mean value coordinates
+// of a point with respect to the cage, following
+// "Mean Value Coordinates for Closed Triangular Meshes"
+// (Ju et al., 2005).
+func (c *CageDeformer) meanValueCoordinates(x Coord3D) []float64 {
+	const epsilon = 1e-8
+
+	weights := make([]float64, len(c.cageCoords))
+	dists := make([]float64, len(c.cageCoords))
+	dirs := make([]Coord3D, len(c.cageCoords))
+	for i, p := range c.cageCoords {
+		diff := p.Sub(x)
+		dists[i] = diff.Norm()
+		if dists[i] < epsilon {
+			weights[i] = 1
+			return weights
+		}
+		dirs[i] = diff.Scale(1 / dists[i])
+	}
+
+	var total float64
+	for _, t := range c.cageTriangles {
+		var theta, cs, ss [3]float64
+		var d [3]float64
+		var u [3]Coord3D
+		var h float64
+		for i, idx := range t {
+			d[i] = dists[idx]
+			u[i] = dirs[idx]
+		}
+		for i := 0; i < 3; i++ {
+			l := u[(i+1)%3].Dist(u[(i+2)%3])
+			theta[i] = 2 * math.Asin(math.Min(1, l/2))
+			h += theta[i] / 2
+		}
+		if math.Pi-h < epsilon {
+			// The point lies on the triangle, so we use
+			// barycentric coordinates within it.
+			for i := range weights {
+				weights[i] = 0
+			}
+			var sum float64
+			for i, idx := range t {
+				w := math.Sin(theta[i]) * d[(i+1)%3] * d[(i+2)%3]
+				weights[idx] += w
+				sum += w
+			}
+			for _, idx := range t {
+				weights[idx] /= sum
+			}
+			return weights
+		}
+		sign := 1.0
+		if u[0].Dot(u[1].Cross(u[2])) < 0 {
+			sign = -1
+		}
+		degenerate := false
+		for i := 0; i < 3; i++ {
+			sin1 := math.Sin(theta[(i+1)%3])
+			sin2 := math.Sin(theta[(i+2)%3])
+			cs[i] = 2*math.Sin(h)*math.Sin(h-theta[i])/(sin1*sin2) - 1
+			ss[i] = sign * math.Sqrt(math.Max(0, 1-cs[i]*cs[i]))
+			if math.Abs(ss[i]) <= epsilon {
+				degenerate = true
+			}
+		}
+		if degenerate {
+			// The point is coplanar with the triangle but
+			// outside of it, so it has no influence.
+			continue
+		}
+		for i, idx := range t {
+			i1, i2 := (i+1)%3, (i+2)%3
+			w := (theta[i] - cs[i1]*theta[i2] - cs[i2]*theta[i1]) /
+				(d[i] * math.Sin(theta[i1]) * ss[i2])
+			weights[idx] += w
+			total += w
+		}
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+	return weights
+}
+
+// greenCoordinates computes the vertex and face Green
+// coordinates of a point with respect to the cage,
+// following "Green Coordinates" (Lipman et al., 2008).
+func (c *CageDeformer) greenCoordinates(x Coord3D) ([]float64, []float64) {
+	const epsilon = 1e-8
+
+	vertexWeights := make([]float64, len(c.cageCoords))
+	faceWeights := make([]float64, len(c.cageTriangles))
+	for j, t := range c.cageTriangles {
+		var v [3]Coord3D
+		for i, idx := range t {
+			v[i] = c.cageCoords[idx].Sub(x)
+		}
+		n := (&Triangle{v[0], v[1], v[2]}).Normal()
+		p := n.Scale(v[0].Dot(n))
+
+		var integral float64
+		var edgeIntegrals [3]float64
+		var edgeNormals [3]Coord3D
+		for l := 0; l < 3; l++ {
+			v1, v2 := v[l], v[(l+1)%3]
+			s := v1.Sub(p).Cross(v2.Sub(p)).Dot(n)
+			if s < 0 {
+				integral -= greenTriangleIntegral(p, v1, v2)
+			} else {
+				integral += greenTriangleIntegral(p, v1, v2)
+			}
+			edgeIntegrals[l] = greenTriangleIntegral(Coord3D{}, v2, v1)
+			edgeNormals[l] = v2.Cross(v1).Normalize()
+		}
+		integral = -math.Abs(integral)
+		faceWeights[j] = -integral
+
+		w := n.Scale(integral)
+		for l := 0; l < 3; l++ {
+			w = w.Add(edgeNormals[l].Scale(edgeIntegrals[l]))
+		}
+		if w.Norm() > epsilon {
+			for l, idx := range t {
+				next := edgeNormals[(l+1)%3]
+				vertexWeights[idx] += next.Dot(w) / next.Dot(v[l])
+			}
+		}
+	}
+	return vertexWeights, faceWeights
+}
+
+// greenTriangleIntegral implements the GCTriInt procedure
+// from the Green coordinates paper, with the evaluation
+// point at the origin.
+func greenTriangleIntegral(p, v1, v2 Coord3D) float64 {
+	const epsilon = 1e-12
+
+	edge := v2.Sub(v1)
+	pv1 := p.Sub(v1)
+	v1p := v1.Sub(p)
+	v2p := v2.Sub(p)
+	if edge.Norm() < epsilon || pv1.Norm() < epsilon || v2p.Norm() < epsilon {
+		return 0
+	}
+	alpha := math.Acos(math.Max(-1, math.Min(1, edge.Normalize().Dot(pv1.Normalize()))))
+	beta := math.Acos(math.Max(-1, math.Min(1, v1p.Normalize().Dot(v2p.Normalize()))))
+	lambda := pv1.Dot(pv1) * math.Pow(math.Sin(alpha), 2)
+	c := p.Dot(p)
+	sqrtC := math.Sqrt(c)
+	sqrtLambda := math.Sqrt(lambda)
+
+	integral := func(theta float64) float64 {
+		s := math.Sin(theta)
+		cos := math.Cos(theta)
+		res := 2 * sqrtC * math.Atan(sqrtC*cos/math.Sqrt(lambda+s*s*c))
+		if lambda > epsilon && s != 0 {
+			logArg := 2 * sqrtLambda * s * s / math.Pow(1-cos, 2) *
+				(1 - 2*c*cos/(c*(1+cos)+lambda+math.Sqrt(lambda*lambda+lambda*c*s*s)))
+			res += sqrtLambda * math.Log(logArg)
+		}
+		if s < 0 {
+			return res / 2
+		} else if s > 0 {
+			return -res / 2
+		}
+		return 0
+	}
+
+	i1 := integral(math.Pi - alpha)
+	i2 := integral(math.Pi - alpha - beta)
+	return -math.Abs(i1-i2-sqrtC*beta) / (4 * math.Pi)
+}
+
+// greenStretch computes the factor by which a cage face
+// normal is scaled in a Green coordinates deformation.
+func greenStretch(oldTri, newTri *Triangle) float64 {
+	u := oldTri[1].Sub(oldTri[0])
+	v := oldTri[2].Sub(oldTri[0])
+	u1 := newTri[1].Sub(newTri[0])
+	v1 := newTri[2].Sub(newTri[0])
+	num := u1.Dot(u1)*v.Dot(v) - 2*u1.Dot(v1)*u.Dot(v) + v1.Dot(v1)*u.Dot(u)
+	return math.Sqrt(math.Max(0, num)) / (math.Sqrt(8) * oldTri.Area())
+}
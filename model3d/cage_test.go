@@ -0,0 +1,59 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCageDeformer(t *testing.T) {
+	cage := NewMeshIcosphere(Coord3D{}, 1.5, 1)
+	mesh := NewMeshIcosphere(XYZ(0.1, 0.2, -0.1), 0.7, 2)
+
+	similarity := JoinedTransform{
+		Rotation(XYZ(1, 2, 3).Normalize(), 0.7),
+		&Scale{Scale: 1.5},
+		&Translate{Offset: X(2)},
+	}
+	shear := &Matrix3Transform{Matrix: &Matrix3{1, 0.5, 0, 0, 1, 0, 0, 0, 2}}
+
+	testTransform := func(t *testing.T, d *CageDeformer, tr Transform) {
+		f := d.DeformFunc(tr.Apply)
+		for _, c := range mesh.VertexSlice() {
+			expected := tr.Apply(c)
+			if actual := f(c); actual.Dist(expected) > 1e-8 {
+				t.Fatalf("expected %v to map to %v but got %v", c, expected, actual)
+			}
+		}
+	}
+
+	t.Run("MeanValue", func(t *testing.T) {
+		d := NewCageDeformer(cage, mesh, CageMeanValue)
+		testTransform(t, d, JoinedTransform{})
+		testTransform(t, d, similarity)
+		testTransform(t, d, shear)
+	})
+	t.Run("Green", func(t *testing.T) {
+		d := NewCageDeformer(cage, mesh, CageGreen)
+		testTransform(t, d, JoinedTransform{})
+		testTransform(t, d, similarity)
+	})
+	t.Run("Stretch", func(t *testing.T) {
+		for _, method := range []CageMethod{CageMeanValue, CageGreen} {
+			d := NewCageDeformer(cage, mesh, method)
+			deformed := d.Deform(func(c Coord3D) Coord3D {
+				if c.Z > 0 {
+					return c.Add(Z(1))
+				}
+				return c
+			})
+			MustValidateMesh(t, deformed, true)
+			if v1, v2 := deformed.Volume(), mesh.Volume(); v1 <= v2 {
+				t.Errorf("method %d: expected volume to increase from %f but got %f",
+					method, v2, v1)
+			}
+			if max := deformed.Max().Z; math.Abs(max-(mesh.Max().Z+1)) > 0.5 {
+				t.Errorf("method %d: unexpected max z: %f", method, max)
+			}
+		}
+	})
+}
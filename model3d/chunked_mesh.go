@@ -0,0 +1,161 @@
+package model3d
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/unixpickle/essentials"
+)
+
+const DefaultChunkedMesherChunkSize = 64
+
+// A ChunkedMesher converts a Solid into a mesh one region
+// at a time, appending each region's triangles to a
+// TriangleStore so that the full mesh never needs to be
+// kept in memory.
+//
+// Every chunk is meshed with marching cubes on a single
+// global grid, so vertices on chunk boundaries are exactly
+// identical and the chunks weld together into one
+// continuous surface.
+//
+// This makes it possible to mesh very large or very
+// detailed solids, such as terrain, on modest machines.
+// The resulting store can be written out with
+// TriangleStore.WriteSTL().
+type ChunkedMesher struct {
+	// Delta is the grid spacing for marching cubes.
+	Delta float64
+
+	// Iters is the number of search iterations to
+	// perform for each vertex, as in MarchingCubesSearch.
+	Iters int
+
+	// ChunkSize is the side length of each chunk, measured
+	// in grid cells.
+	//
+	// If 0, DefaultChunkedMesherChunkSize is used.
+	ChunkSize int
+
+	// Decimator, if non-nil, is used to simplify each
+	// chunk before it is written out.
+	// Vertices on the boundary of a chunk are never
+	// removed, so decimated chunks still weld together.
+	Decimator *Decimator
+
+	// MaxGos, if specified, limits the number of Goroutines
+	// for parallel processing. If 0, GOMAXPROCS is used.
+	MaxGos int
+
+	// Progress, if non-nil, receives the fraction of
+	// chunks which have been meshed.
+	Progress Progress
+}
+
+// Mesh meshes the solid and appends the resulting
+// triangles to out.
+func (c *ChunkedMesher) Mesh(s Solid, out *TriangleStore) error {
+	return c.MeshContext(context.Background(), s, out)
+}
+
+// MeshContext is like Mesh, but stops early and returns
+// ctx.Err() if ctx is cancelled or expires.
+//
+// Cancellation is checked between chunks, so out may
+// contain the triangles of some chunks when an error is
+// returned.
+func (c *ChunkedMesher) MeshContext(ctx context.Context, s Solid, out *TriangleStore) error {
+	if !BoundsValid(s) {
+		panic("invalid bounds for solid")
+	}
+	chunkSize := c.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkedMesherChunkSize
+	}
+
+	spacer := newSquareSpacer(s, c.Delta)
+	counts := [3]int{len(spacer.Xs) - 1, len(spacer.Ys) - 1, len(spacer.Zs) - 1}
+	var numChunks [3]int
+	for i, count := range counts {
+		numChunks[i] = (count + chunkSize - 1) / chunkSize
+	}
+	totalChunks := numChunks[0] * numChunks[1] * numChunks[2]
+
+	var chunksDone int
+	for z := 0; z < numChunks[2]; z++ {
+		for y := 0; y < numChunks[1]; y++ {
+			for x := 0; x < numChunks[0]; x++ {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				var min, max [3]int
+				for i, idx := range [3]int{x, y, z} {
+					min[i] = idx * chunkSize
+					max[i] = essentials.MinInt(min[i]+chunkSize, counts[i])
+				}
+				mesh := c.meshChunk(s, spacer, min, max)
+				if mesh.NumTriangles() > 0 {
+					if c.Iters > 0 {
+						mcSearch(s, c.Delta, c.Iters, mesh, nil)
+					}
+					if c.Decimator != nil {
+						mesh = decimateOpenMesh(c.Decimator, mesh)
+					}
+					if err := out.AddMesh(mesh); err != nil {
+						return errors.Wrap(err, "chunked mesh")
+					}
+				}
+				chunksDone++
+				progressUpdate(c.Progress, float64(chunksDone)/float64(totalChunks))
+			}
+		}
+	}
+	return nil
+}
+
+// meshChunk applies marching cubes to the cells with
+// indices in [min, max) along each axis.
+func (c *ChunkedMesher) meshChunk(s Solid, spacer *squareSpacer, min, max [3]int) *Mesh {
+	nx := max[0] - min[0] + 1
+	ny := max[1] - min[1] + 1
+	nz := max[2] - min[2] + 1
+	values := make([]bool, nx*ny*nz)
+	essentials.ConcurrentMap(c.MaxGos, nz, func(z int) {
+		idx := z * nx * ny
+		for y := 0; y < ny; y++ {
+			for x := 0; x < nx; x++ {
+				values[idx] = s.Contains(spacer.CornerCoord(x+min[0], y+min[1], z+min[2]))
+				idx++
+			}
+		}
+	})
+
+	table := mcLookupTable()
+	arena := &TriangleArena{}
+	mesh := NewMeshArena(arena)
+	for z := 0; z < nz-1; z++ {
+		for y := 0; y < ny-1; y++ {
+			for x := 0; x < nx-1; x++ {
+				var bits mcIntersections
+				for i := 0; i < 8; i++ {
+					cx, cy, cz := x+(i&1), y+((i>>1)&1), z+(i>>2)
+					if values[cx+nx*(cy+ny*cz)] {
+						bits |= 1 << uint(i)
+					}
+				}
+				triangles := table[bits]
+				if len(triangles) > 0 {
+					gx, gy, gz := x+min[0], y+min[1], z+min[2]
+					corners := mcCornerCoordinates(
+						spacer.CornerCoord(gx, gy, gz),
+						spacer.CornerCoord(gx+1, gy+1, gz+1),
+					)
+					for _, t := range triangles {
+						mesh.Add(t.Triangle(corners, arena))
+					}
+				}
+			}
+		}
+	}
+	return mesh
+}
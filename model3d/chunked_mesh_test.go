@@ -0,0 +1,60 @@
+package model3d
+
+import (
+	"testing"
+)
+
+func TestChunkedMesher(t *testing.T) {
+	solid := JoinedSolid{
+		&Sphere{Radius: 0.7},
+		&Cylinder{P1: XYZ(0, 0, -1), P2: XYZ(0, 0, 1), Radius: 0.3},
+	}
+	expected := MarchingCubesSearch(solid, 0.05, 4)
+
+	t.Run("Exact", func(t *testing.T) {
+		store, err := NewTempTriangleStore("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		mesher := &ChunkedMesher{Delta: 0.05, Iters: 4, ChunkSize: 7}
+		if err := mesher.Mesh(solid, store); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := store.Mesh()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !meshesEqual(actual, expected) {
+			t.Fatal("chunked mesh does not match marching cubes")
+		}
+	})
+
+	t.Run("Decimate", func(t *testing.T) {
+		store, err := NewTempTriangleStore("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+
+		mesher := &ChunkedMesher{
+			Delta:     0.05,
+			Iters:     4,
+			ChunkSize: 10,
+			Decimator: &Decimator{PlaneDistance: 1e-3, BoundaryDistance: 1e-3},
+		}
+		if err := mesher.Mesh(solid, store); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := store.Mesh()
+		if err != nil {
+			t.Fatal(err)
+		}
+		MustValidateMesh(t, actual, true)
+		if actual.NumTriangles() >= expected.NumTriangles() {
+			t.Errorf("expected fewer than %d triangles but got %d", expected.NumTriangles(),
+				actual.NumTriangles())
+		}
+	})
+}
@@ -111,6 +111,41 @@ type MultiCollider interface {
 	RectCollider
 }
 
+// A BatchCollider is a Collider which can find the first
+// collisions of many rays at once more efficiently than
+// by checking each ray separately, for example by
+// sharing a single traversal of a bounding volume
+// hierarchy between all of the rays.
+type BatchCollider interface {
+	Collider
+
+	// FirstRayCollisions finds the first collision of
+	// every ray in rays, storing the results in the
+	// corresponding entries of collisions and collides.
+	//
+	// The collisions and collides slices must be at least
+	// as long as rays.
+	FirstRayCollisions(rays []*Ray, collisions []RayCollision, collides []bool)
+}
+
+// FirstRayCollisions finds the first collision of every
+// ray in rays with c, storing the results in the
+// corresponding entries of collisions and collides.
+//
+// If c implements BatchCollider, it is used to share work
+// between the rays. Otherwise, FirstRayCollision is
+// called for each ray.
+func FirstRayCollisions(c Collider, rays []*Ray, collisions []RayCollision,
+	collides []bool) {
+	if bc, ok := c.(BatchCollider); ok {
+		bc.FirstRayCollisions(rays, collisions, collides)
+		return
+	}
+	for i, r := range rays {
+		collisions[i], collides[i] = c.FirstRayCollision(r)
+	}
+}
+
 // ColliderContains checks if a point is within a Collider
 // and at least margin away from the border.
 //
@@ -260,6 +295,31 @@ func (j *JoinedCollider) Max() Coord3D {
 	return j.max
 }
 
+// Refit recomputes the bounding box of the collider, and
+// of any nested JoinedColliders, from the current bounds
+// of the underlying colliders.
+//
+// This can be used to update a collider after the shapes
+// it contains have been modified in place, for example
+// when animating a deforming mesh, without rebuilding
+// the entire hierarchy.
+func (j *JoinedCollider) Refit() {
+	for i, c := range j.colliders {
+		switch c := c.(type) {
+		case *JoinedCollider:
+			c.Refit()
+		case joinedMultiCollider:
+			c.Refit()
+		}
+		if i == 0 {
+			j.min, j.max = c.Min(), c.Max()
+		} else {
+			j.min = j.min.Min(c.Min())
+			j.max = j.max.Max(c.Max())
+		}
+	}
+}
+
 func (j *JoinedCollider) RayCollisions(r *Ray, f func(RayCollision)) int {
 	if !j.rayCollidesWithBounds(r) {
 		return 0
@@ -289,6 +349,67 @@ func (j *JoinedCollider) FirstRayCollision(r *Ray) (RayCollision, bool) {
 	return closest, anyCollides
 }
 
+// FirstRayCollisions finds the first collisions of many
+// rays at once, traversing the hierarchy of colliders a
+// single time for all of the rays.
+//
+// Rays are dropped from the traversal once they miss a
+// bounding box, or once they have already hit something
+// closer than the bounding box.
+func (j *JoinedCollider) FirstRayCollisions(rays []*Ray, collisions []RayCollision,
+	collides []bool) {
+	active := make([]int, len(rays), len(rays)*2)
+	for i := range rays {
+		active[i] = i
+		collides[i] = false
+	}
+	j.firstRayCollisions(rays, active, collisions, collides)
+}
+
+// firstRayCollisions updates the collisions for the rays
+// at the given indices.
+//
+// The filtered indices for sub-colliders are appended to
+// the end of active, reusing its spare capacity.
+func (j *JoinedCollider) firstRayCollisions(rays []*Ray, active []int,
+	collisions []RayCollision, collides []bool) {
+	start := len(active)
+	for _, idx := range active {
+		minFrac, maxFrac := rayCollisionWithBounds(rays[idx], j.min, j.max)
+		if maxFrac < minFrac || maxFrac < 0 {
+			continue
+		}
+		if collides[idx] && collisions[idx].Scale < minFrac {
+			continue
+		}
+		active = append(active, idx)
+	}
+	sub := active[start:]
+	if len(sub) == 0 {
+		return
+	}
+	for _, c := range j.colliders {
+		var jc *JoinedCollider
+		switch c := c.(type) {
+		case *JoinedCollider:
+			jc = c
+		case joinedMultiCollider:
+			jc = c.JoinedCollider
+		}
+		if jc != nil {
+			jc.firstRayCollisions(rays, sub, collisions, collides)
+			continue
+		}
+		for _, idx := range sub {
+			rc, ok := c.FirstRayCollision(rays[idx])
+			if ok && (!collides[idx] || rc.Scale < collisions[idx].Scale) {
+				collisions[idx] = rc
+				collides[idx] = true
+			}
+		}
+	}
+}
+
 func (j *JoinedCollider) SphereCollision(center Coord3D, r float64) bool {
 	if !sphereTouchesBounds(center, r, j.min, j.max) {
 		return false
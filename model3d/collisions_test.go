@@ -4,7 +4,9 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"runtime"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/unixpickle/model3d/model2d"
@@ -367,6 +369,267 @@ func (c *combinedSolidColliderSDF) SDF(coord Coord3D) float64 {
 	return c.InternalSDF.SDF(coord)
 }
 
+func TestBVHRefit(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 5)
+	tris := mesh.TriangleSlice()
+	bvh := NewBVHAreaDensity(tris)
+	collider := BVHToCollider(bvh)
+
+	// Deform the triangles in place.
+	for _, tri := range tris {
+		for i, c := range tri {
+			tri[i] = c.Scale(2).Add(X(3))
+		}
+	}
+	bvh.Refit()
+	if bvh.Min() != mesh.Min() || bvh.Max() != mesh.Max() {
+		t.Errorf("unexpected bounds after refit: %v, %v", bvh.Min(), bvh.Max())
+	}
+
+	collider.(interface{ Refit() }).Refit()
+	expected := MeshToCollider(mesh)
+	if collider.Min() != expected.Min() || collider.Max() != expected.Max() {
+		t.Errorf("unexpected collider bounds: %v, %v", collider.Min(), collider.Max())
+	}
+	for i := 0; i < 100; i++ {
+		ray := &Ray{Origin: X(3), Direction: NewCoord3DRandUnit()}
+		actual := collider.RayCollisions(ray, nil)
+		if n := expected.RayCollisions(ray, nil); n != actual {
+			t.Fatalf("expected %d collisions but got %d", n, actual)
+		}
+	}
+}
+
+func TestBVHInsertRemove(t *testing.T) {
+	equal := func(t1, t2 *Triangle) bool {
+		return t1 == t2
+	}
+	tris := NewMeshIcosphere(Origin, 1, 5).TriangleSlice()
+	bvh := NewBVHAreaDensity(tris[:10])
+	for _, tri := range tris[10:] {
+		bvh.Insert(tri)
+	}
+	checkLeaves := func(expected []*Triangle) {
+		leaves := map[*Triangle]bool{}
+		var gather func(b *BVH[*Triangle])
+		gather = func(b *BVH[*Triangle]) {
+			if b.Leaf != nil {
+				leaves[b.Leaf] = true
+			}
+			for _, child := range b.Branch {
+				gather(child)
+			}
+		}
+		gather(bvh)
+		if len(leaves) != len(expected) {
+			t.Fatalf("expected %d leaves but got %d", len(expected), len(leaves))
+		}
+		for _, tri := range expected {
+			if !leaves[tri] {
+				t.Fatal("missing leaf")
+			}
+		}
+		if len(expected) > 0 {
+			m := NewMeshTriangles(expected)
+			if bvh.Min() != m.Min() || bvh.Max() != m.Max() {
+				t.Fatal("incorrect bounds")
+			}
+		}
+	}
+	checkLeaves(tris)
+
+	for len(tris) > 1 {
+		idx := rand.Intn(len(tris))
+		if !bvh.Remove(tris[idx], equal) {
+			t.Fatal("failed to remove leaf")
+		}
+		tris = append(tris[:idx], tris[idx+1:]...)
+		if len(tris)%17 == 0 {
+			checkLeaves(tris)
+		}
+	}
+	checkLeaves(tris)
+	if bvh.Remove(tris[0], equal) {
+		t.Error("should not remove final leaf")
+	}
+	if bvh.Remove(&Triangle{}, equal) {
+		t.Error("removed nonexistent leaf")
+	}
+}
+
+// bvhTestLeaf is a Bounder which cannot be compared with
+// the == operator.
+type bvhTestLeaf struct {
+	Tri  *Triangle
+	Tags []int
+}
+
+func (b bvhTestLeaf) Min() Coord3D {
+	return b.Tri.Min()
+}
+
+func (b bvhTestLeaf) Max() Coord3D {
+	return b.Tri.Max()
+}
+
+func TestBVHRemoveIncomparable(t *testing.T) {
+	tris := NewMeshIcosphere(Origin, 1, 2).TriangleSlice()
+	leaves := make([]bvhTestLeaf, len(tris))
+	for i, tri := range tris {
+		leaves[i] = bvhTestLeaf{Tri: tri, Tags: []int{i}}
+	}
+	bvh := NewBVHAreaDensity(leaves)
+	equal := func(l1, l2 bvhTestLeaf) bool {
+		return l1.Tri == l2.Tri
+	}
+	for _, leaf := range leaves[1:] {
+		if !bvh.Remove(leaf, equal) {
+			t.Fatal("failed to remove leaf")
+		}
+	}
+	if bvh.Leaf.Tri != tris[0] {
+		t.Error("unexpected final leaf")
+	}
+}
+
+func TestBVHBoundsConcurrent(t *testing.T) {
+	tris := NewMeshIcosphere(Origin, 1, 3).TriangleSlice()
+	expected := NewMeshTriangles(tris)
+	built := NewBVHAreaDensity(tris)
+	byHand := &BVH[*Triangle]{Branch: []*BVH[*Triangle]{
+		{Leaf: tris[0]},
+		NewBVHAreaDensity(tris[1:]),
+	}}
+
+	// Reading the bounds should not modify the trees, so
+	// this should pass the race detector.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, bvh := range []*BVH[*Triangle]{built, byHand} {
+				if bvh.Min() != expected.Min() || bvh.Max() != expected.Max() {
+					t.Error("unexpected bounds")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBVHParallel(t *testing.T) {
+	tris := NewMeshIcosphere(Origin, 1, 30).TriangleSlice()
+	if len(tris) < 2*parallelBVHMinSize {
+		t.Fatal("mesh is too small to be built in parallel")
+	}
+	rand.Shuffle(len(tris), func(i, j int) {
+		tris[i], tris[j] = tris[j], tris[i]
+	})
+
+	build := func(gos int) *BVH[*Triangle] {
+		return newBVH(sortBounders(tris), make([]float64, len(tris)),
+			areaDensityBVHSplit[*Triangle], gos)
+	}
+	var checkEqual func(expected, actual *BVH[*Triangle])
+	checkEqual = func(expected, actual *BVH[*Triangle]) {
+		if expected.Leaf != actual.Leaf || len(expected.Branch) != len(actual.Branch) {
+			t.Fatal("tree structure differs")
+		}
+		if expected.Min() != actual.Min() || expected.Max() != actual.Max() {
+			t.Fatal("tree bounds differ")
+		}
+		for i, child := range expected.Branch {
+			checkEqual(child, actual.Branch[i])
+		}
+	}
+	checkEqual(build(1), build(8))
+
+	group := func(gos int) []*Triangle {
+		res := append([]*Triangle{}, tris...)
+		groupBounders(sortBounders(res), res, gos)
+		return res
+	}
+	expected := group(1)
+	actual := group(8)
+	for i, tri := range expected {
+		if actual[i] != tri {
+			t.Fatalf("grouping differs at index %d", i)
+		}
+	}
+}
+
+func TestFirstRayCollisions(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 5)
+	mesh.AddMesh(NewMeshRect(XYZ(2, 2, 2), XYZ(3, 3, 3)))
+	collider := MeshToCollider(mesh)
+	if _, ok := collider.(BatchCollider); !ok {
+		t.Fatal("mesh collider should support batches")
+	}
+
+	rays := make([]*Ray, 1000)
+	for i := range rays {
+		rays[i] = &Ray{
+			Origin:    NewCoord3DRandNorm().Scale(3),
+			Direction: NewCoord3DRandUnit(),
+		}
+		if i%2 == 0 {
+			// Aim at the objects to get more collisions.
+			rays[i].Direction = NewCoord3DRandNorm().Scale(0.5).Sub(rays[i].Origin)
+		}
+	}
+	collisions := make([]RayCollision, len(rays))
+	collides := make([]bool, len(rays))
+	FirstRayCollisions(collider, rays, collisions, collides)
+	var numCollisions int
+	for i, r := range rays {
+		expected, ok := collider.FirstRayCollision(r)
+		if ok != collides[i] {
+			t.Fatalf("ray %d: expected collides=%v but got %v", i, ok, collides[i])
+		}
+		if ok {
+			numCollisions++
+			if expected.Scale != collisions[i].Scale || expected.Normal != collisions[i].Normal {
+				t.Fatalf("ray %d: expected %v but got %v", i, expected, collisions[i])
+			}
+		}
+	}
+	if numCollisions == 0 {
+		t.Error("no collisions were tested")
+	}
+}
+
+func BenchmarkNewBVHAreaDensity(b *testing.B) {
+	tris := NewMeshIcosphere(Origin, 1, 50).TriangleSlice()
+	runBench := func(b *testing.B, gos int) {
+		for i := 0; i < b.N; i++ {
+			newBVH(sortBounders(tris), make([]float64, len(tris)),
+				areaDensityBVHSplit[*Triangle], gos)
+		}
+	}
+	b.Run("MaxGos1", func(b *testing.B) {
+		runBench(b, 1)
+	})
+	b.Run("MaxGos0", func(b *testing.B) {
+		runBench(b, runtime.GOMAXPROCS(0))
+	})
+}
+
+func BenchmarkGroupBounders(b *testing.B) {
+	tris := NewMeshIcosphere(Origin, 1, 50).TriangleSlice()
+	runBench := func(b *testing.B, gos int) {
+		for i := 0; i < b.N; i++ {
+			groupBounders(sortBounders(tris), tris, gos)
+		}
+	}
+	b.Run("MaxGos1", func(b *testing.B) {
+		runBench(b, 1)
+	})
+	b.Run("MaxGos0", func(b *testing.B) {
+		runBench(b, runtime.GOMAXPROCS(0))
+	})
+}
+
 func BenchmarkMeshToCollider(b *testing.B) {
 	mesh := NewMeshPolar(func(g GeoCoord) float64 {
 		return 1
@@ -0,0 +1,227 @@
+package model3d
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/pkg/errors"
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/model3d/fileformats"
+)
+
+// A Coord3D32 is a single-precision version of Coord3D,
+// for storing large numbers of points compactly.
+type Coord3D32 struct {
+	X float32
+	Y float32
+	Z float32
+}
+
+// NewCoord3D32 rounds c to single precision.
+func NewCoord3D32(c Coord3D) Coord3D32 {
+	return Coord3D32{X: float32(c.X), Y: float32(c.Y), Z: float32(c.Z)}
+}
+
+// Coord3D converts c to double precision.
+func (c Coord3D32) Coord3D() Coord3D {
+	return XYZ(float64(c.X), float64(c.Y), float64(c.Z))
+}
+
+// A CompactMesh is a memory-efficient triangle mesh, which
+// stores single-precision vertices and indexed faces.
+//
+// A CompactMesh uses a small fraction of the memory of an
+// equivalent Mesh, making it suitable for very large
+// meshes that are only going to be exported. However, it
+// does not support efficient modification or neighbor
+// lookups, so it should be converted to a Mesh for other
+// processing.
+type CompactMesh struct {
+	Vertices []Coord3D32
+
+	// Faces contains the indices of the vertices of each
+	// triangle, in the same order as a Triangle.
+	Faces [][3]uint32
+}
+
+// NewCompactMesh creates a CompactMesh from a mesh.
+//
+// Vertices which are equal after rounding to single
+// precision are merged.
+func NewCompactMesh(m *Mesh) *CompactMesh {
+	res := &CompactMesh{}
+	indices := map[Coord3D32]uint32{}
+	m.Iterate(func(t *Triangle) {
+		var face [3]uint32
+		for i, c := range t {
+			c32 := NewCoord3D32(c)
+			idx, ok := indices[c32]
+			if !ok {
+				idx = uint32(len(res.Vertices))
+				indices[c32] = idx
+				res.Vertices = append(res.Vertices, c32)
+			}
+			face[i] = idx
+		}
+		res.Faces = append(res.Faces, face)
+	})
+	return res
+}
+
+// NumTriangles gets the number of triangles in the mesh.
+func (c *CompactMesh) NumTriangles() int {
+	return len(c.Faces)
+}
+
+// Triangle creates the i-th triangle of the mesh.
+func (c *CompactMesh) Triangle(i int) *Triangle {
+	face := c.Faces[i]
+	return &Triangle{
+		c.Vertices[face[0]].Coord3D(),
+		c.Vertices[face[1]].Coord3D(),
+		c.Vertices[face[2]].Coord3D(),
+	}
+}
+
+// Mesh converts c into a regular Mesh.
+func (c *CompactMesh) Mesh() *Mesh {
+	res := NewMesh()
+	for i := range c.Faces {
+		res.Add(c.Triangle(i))
+	}
+	return res
+}
+
+// Min gets the component-wise minimum vertex.
+func (c *CompactMesh) Min() Coord3D {
+	if len(c.Vertices) == 0 {
+		return Coord3D{}
+	}
+	res := c.Vertices[0].Coord3D()
+	for _, v := range c.Vertices[1:] {
+		res = res.Min(v.Coord3D())
+	}
+	return res
+}
+
+// Max gets the component-wise maximum vertex.
+func (c *CompactMesh) Max() Coord3D {
+	if len(c.Vertices) == 0 {
+		return Coord3D{}
+	}
+	res := c.Vertices[0].Coord3D()
+	for _, v := range c.Vertices[1:] {
+		res = res.Max(v.Coord3D())
+	}
+	return res
+}
+
+// EncodeSTL encodes the mesh in the binary STL format.
+func (c *CompactMesh) EncodeSTL() []byte {
+	var buf bytes.Buffer
+	c.WriteSTL(&buf)
+	return buf.Bytes()
+}
+
+// WriteSTL writes the mesh in the binary STL format to w.
+func (c *CompactMesh) WriteSTL(w io.Writer) error {
+	if int(uint32(len(c.Faces))) != len(c.Faces) {
+		return errors.New("write STL: too many triangles for STL format")
+	}
+	bw := bufio.NewWriter(w)
+	writer, err := fileformats.NewSTLWriter(bw, uint32(len(c.Faces)))
+	if err != nil {
+		return errors.Wrap(err, "write STL")
+	}
+	for i, face := range c.Faces {
+		var verts [3][3]float32
+		for j, idx := range face {
+			v := c.Vertices[idx]
+			verts[j] = [3]float32{v.X, v.Y, v.Z}
+		}
+		normal := castVector32(c.Triangle(i).Normal())
+		if err := writer.WriteTriangle(normal, verts); err != nil {
+			return errors.Wrap(err, "write STL")
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "write STL")
+	}
+	return nil
+}
+
+// MarchingCubesCompact is like MarchingCubesSearch, but
+// produces a CompactMesh.
+//
+// The mesh is built one layer at a time, so a full Mesh
+// is never stored in memory at once.
+func MarchingCubesCompact(s Solid, delta float64, iters int) *CompactMesh {
+	if !BoundsValid(s) {
+		panic("invalid bounds for solid")
+	}
+
+	table := mcLookupTable()
+	spacer := newSquareSpacer(s, delta)
+	res := &CompactMesh{}
+
+	// Vertices are only shared between adjacent layers,
+	// so we only keep indices for two layers at a time.
+	prevIndices := map[Coord3D]uint32{}
+	curIndices := map[Coord3D]uint32{}
+	var newVertices []Coord3D
+	lookup := func(c Coord3D) uint32 {
+		if idx, ok := prevIndices[c]; ok {
+			return idx
+		} else if idx, ok := curIndices[c]; ok {
+			return idx
+		}
+		numVertices := len(res.Vertices) + len(newVertices)
+		if numVertices > math.MaxUint32 {
+			panic("too many vertices for compact mesh")
+		}
+		idx := uint32(numVertices)
+		curIndices[c] = idx
+		newVertices = append(newVertices, c)
+		return idx
+	}
+
+	spacer.Scan(s, func(z int, bottomCache, topCache *solidCache) {
+		for y := 0; y < len(spacer.Ys)-1; y++ {
+			for x := 0; x < len(spacer.Xs)-1; x++ {
+				bits := bottomCache.GetSquare(x, y) | (topCache.GetSquare(x, y) << 4)
+				triangles := table[bits]
+				if len(triangles) > 0 {
+					min := spacer.CornerCoord(x, y, z-1)
+					max := spacer.CornerCoord(x+1, y+1, z)
+					corners := mcCornerCoordinates(min, max)
+					for _, t := range triangles {
+						tri := t.Triangle(corners, nil)
+						res.Faces = append(res.Faces, [3]uint32{
+							lookup(tri[0]),
+							lookup(tri[1]),
+							lookup(tri[2]),
+						})
+					}
+				}
+			}
+		}
+
+		searched := make([]Coord3D32, len(newVertices))
+		essentials.ConcurrentMap(0, len(newVertices), func(i int) {
+			c := newVertices[i]
+			if iters > 0 {
+				c = mcSearchPoint(s, delta, iters, nil, spacer, c, nil)
+			}
+			searched[i] = NewCoord3D32(c)
+		})
+		res.Vertices = append(res.Vertices, searched...)
+		newVertices = newVertices[:0]
+		prevIndices, curIndices = curIndices, prevIndices
+		for k := range curIndices {
+			delete(curIndices, k)
+		}
+	})
+	return res
+}
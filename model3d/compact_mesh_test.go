@@ -0,0 +1,66 @@
+package model3d
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCompactMesh(t *testing.T) {
+	mesh := NewMeshIcosphere(XYZ(1, 2, 3), 2, 3)
+	compact := NewCompactMesh(mesh)
+	if compact.NumTriangles() != mesh.NumTriangles() {
+		t.Fatalf("expected %d triangles but got %d", mesh.NumTriangles(), compact.NumTriangles())
+	}
+	if len(compact.Vertices) != len(mesh.VertexSlice()) {
+		t.Errorf("expected %d vertices but got %d", len(mesh.VertexSlice()), len(compact.Vertices))
+	}
+	if compact.Min().Dist(mesh.Min()) > 1e-5 || compact.Max().Dist(mesh.Max()) > 1e-5 {
+		t.Error("unexpected bounds")
+	}
+
+	converted := compact.Mesh()
+	MustValidateMesh(t, converted, true)
+	if math.Abs(converted.Volume()-mesh.Volume()) > 1e-4 {
+		t.Errorf("unexpected volume: %f", converted.Volume())
+	}
+
+	tris, err := ReadSTL(bytes.NewReader(compact.EncodeSTL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !meshesEqual(NewMeshTriangles(tris), NewMeshTriangles(castTriangles32(mesh))) {
+		t.Error("STL does not match the original mesh")
+	}
+}
+
+func TestMarchingCubesCompact(t *testing.T) {
+	solid := JoinedSolid{
+		&Sphere{Radius: 1},
+		&Cylinder{P1: XYZ(0, 0, 0.5), P2: XYZ(1, 1, 2), Radius: 0.3},
+	}
+	for _, iters := range []int{0, 8} {
+		expected := MarchingCubesSearch(solid, 0.05, iters)
+		actual := MarchingCubesCompact(solid, 0.05, iters)
+		if actual.NumTriangles() != expected.NumTriangles() {
+			t.Fatalf("expected %d triangles but got %d", expected.NumTriangles(),
+				actual.NumTriangles())
+		}
+		if !meshesEqual(actual.Mesh(), NewMeshTriangles(castTriangles32(expected))) {
+			t.Errorf("iters %d: mesh does not match MarchingCubesSearch", iters)
+		}
+		MustValidateMesh(t, actual.Mesh(), true)
+	}
+}
+
+func castTriangles32(m *Mesh) []*Triangle {
+	var res []*Triangle
+	m.Iterate(func(t *Triangle) {
+		var t1 Triangle
+		for i, c := range t {
+			t1[i] = NewCoord3D32(c).Coord3D()
+		}
+		res = append(res, &t1)
+	})
+	return res
+}
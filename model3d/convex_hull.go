@@ -0,0 +1,329 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+// ConvexHull computes the convex hull of a set of points
+// using the quickhull algorithm.
+//
+// The result is a closed mesh with outward-facing normals.
+// Flat faces of the hull may be split into several
+// triangles, and points which lie on the faces or edges
+// of the hull are not necessarily included as vertices.
+//
+// If there are fewer than four non-coplanar points, the
+// result is empty.
+func ConvexHull(points []Coord3D) *Mesh {
+	h := newConvexHull(points)
+	if h == nil {
+		return NewMesh()
+	}
+	h.Run()
+	return h.Mesh()
+}
+
+// ConvexHull computes the convex hull of the vertices of
+// the mesh.
+//
+// See ConvexHull for details.
+func (m *Mesh) ConvexHull() *Mesh {
+	return ConvexHull(m.VertexSlice())
+}
+
+// NewConvexPolytopeHull creates the smallest convex
+// polytope which contains all of the points.
+//
+// Exactly coplanar faces of the hull are merged, so there
+// is one constraint per face of the hull.
+//
+// If there are fewer than four non-coplanar points, the
+// result is nil.
+func NewConvexPolytopeHull(points []Coord3D) ConvexPolytope {
+	h := newConvexHull(points)
+	if h == nil {
+		return nil
+	}
+	h.Run()
+	return h.Polytope()
+}
+
+type convexHullFace struct {
+	Vertices [3]int
+	Normal   Coord3D
+	Offset   float64
+	Outside  []int
+	Dead     bool
+}
+
+func (c *convexHullFace) Dist(p Coord3D) float64 {
+	return c.Normal.Dot(p) - c.Offset
+}
+
+type convexHull struct {
+	Points  []Coord3D
+	Epsilon float64
+	Faces   []*convexHullFace
+
+	// Edges maps each directed edge of a live face to the
+	// face, where faces list vertices counter-clockwise.
+	Edges map[[2]int]*convexHullFace
+}
+
+func newConvexHull(points []Coord3D) *convexHull {
+	if len(points) < 4 {
+		return nil
+	}
+	min, max := points[0], points[0]
+	for _, p := range points[1:] {
+		min = min.Min(p)
+		max = max.Max(p)
+	}
+	scale := min.Abs().Max(max.Abs())
+	h := &convexHull{
+		Points:  points,
+		Epsilon: 1e-10 * (scale.X + scale.Y + scale.Z),
+		Edges:   map[[2]int]*convexHullFace{},
+	}
+
+	// Find an initial tetrahedron by picking points which
+	// are as far apart as possible.
+	var extremes []int
+	for axis := 0; axis < 3; axis++ {
+		minIdx, maxIdx := 0, 0
+		for i, p := range points {
+			if p.Array()[axis] < points[minIdx].Array()[axis] {
+				minIdx = i
+			}
+			if p.Array()[axis] > points[maxIdx].Array()[axis] {
+				maxIdx = i
+			}
+		}
+		extremes = append(extremes, minIdx, maxIdx)
+	}
+	var i1, i2 int
+	var maxDist float64
+	for _, a := range extremes {
+		for _, b := range extremes {
+			if d := points[a].Dist(points[b]); d > maxDist {
+				maxDist = d
+				i1, i2 = a, b
+			}
+		}
+	}
+	if maxDist <= h.Epsilon {
+		return nil
+	}
+
+	p1, p2 := points[i1], points[i2]
+	direction := p2.Sub(p1).Normalize()
+	i3 := -1
+	maxDist = h.Epsilon
+	for i, p := range points {
+		diff := p.Sub(p1)
+		if d := diff.Sub(direction.Scale(diff.Dot(direction))).Norm(); d > maxDist {
+			maxDist = d
+			i3 = i
+		}
+	}
+	if i3 == -1 {
+		return nil
+	}
+
+	p3 := points[i3]
+	normal := p2.Sub(p1).Cross(p3.Sub(p1)).Normalize()
+	i4 := -1
+	maxDist = 0
+	for i, p := range points {
+		d := math.Abs(normal.Dot(p.Sub(p1)))
+		if d >= maxDist && numerical.Orient3D(p1.Array(), p2.Array(), p3.Array(), p.Array()) != 0 {
+			maxDist = d
+			i4 = i
+		}
+	}
+	if i4 == -1 {
+		return nil
+	}
+
+	if numerical.Orient3D(p1.Array(), p2.Array(), p3.Array(), points[i4].Array()) < 0 {
+		i2, i3 = i3, i2
+	}
+	faces := []*convexHullFace{
+		h.addFace(i1, i2, i3),
+		h.addFace(i1, i4, i2),
+		h.addFace(i2, i4, i3),
+		h.addFace(i3, i4, i1),
+	}
+	for i := range points {
+		h.assignOutside(i, faces)
+	}
+	return h
+}
+
+// Run adds points to the hull until every point is
+// inside of it.
+func (c *convexHull) Run() {
+	for i := 0; i < len(c.Faces); i++ {
+		face := c.Faces[i]
+		for !face.Dead && len(face.Outside) > 0 {
+			c.addPoint(face)
+		}
+	}
+}
+
+// Mesh creates a mesh from the live faces of the hull.
+func (c *convexHull) Mesh() *Mesh {
+	m := NewMesh()
+	for _, f := range c.Faces {
+		if !f.Dead {
+			m.Add(&Triangle{
+				c.Points[f.Vertices[0]],
+				c.Points[f.Vertices[1]],
+				c.Points[f.Vertices[2]],
+			})
+		}
+	}
+	return m
+}
+
+// Polytope creates a polytope with one constraint for
+// every group of adjacent, coplanar faces.
+func (c *convexHull) Polytope() ConvexPolytope {
+	groups := map[*convexHullFace]*LinearConstraint{}
+	var result ConvexPolytope
+	for _, f := range c.Faces {
+		if f.Dead || groups[f] != nil {
+			continue
+		}
+		constraint := &LinearConstraint{Normal: f.Normal, Max: f.Offset}
+		result = append(result, constraint)
+
+		// Flood fill across edges to coplanar neighbors.
+		groups[f] = constraint
+		queue := []*convexHullFace{f}
+		for len(queue) > 0 {
+			f1 := queue[0]
+			queue = queue[1:]
+			for i := 0; i < 3; i++ {
+				edge := [2]int{f1.Vertices[(i+1)%3], f1.Vertices[i]}
+				neighbor := c.Edges[edge]
+				if groups[neighbor] != nil || !c.coplanar(f, neighbor) {
+					continue
+				}
+				groups[neighbor] = constraint
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+	for f, constraint := range groups {
+		for _, v := range f.Vertices {
+			constraint.Max = math.Max(constraint.Max, constraint.Normal.Dot(c.Points[v]))
+		}
+	}
+	return result
+}
+
+func (c *convexHull) coplanar(f1, f2 *convexHullFace) bool {
+	for _, v := range f2.Vertices {
+		if c.orient(f1, c.Points[v]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// orient is positive if p is strictly outside of the
+// plane of f, negative if it is strictly inside, and zero
+// if it is exactly on the plane.
+//
+// Unlike Dist, the sign of the result is exact, so that
+// hulls of nearly coplanar points are still convex.
+func (c *convexHull) orient(f *convexHullFace, p Coord3D) float64 {
+	return -numerical.Orient3D(
+		c.Points[f.Vertices[0]].Array(),
+		c.Points[f.Vertices[1]].Array(),
+		c.Points[f.Vertices[2]].Array(),
+		p.Array(),
+	)
+}
+
+func (c *convexHull) addPoint(face *convexHullFace) {
+	// Use the furthest point from the face, which must be
+	// a vertex of the hull.
+	var pointIdx int
+	maxDist := math.Inf(-1)
+	for _, i := range face.Outside {
+		if d := face.Dist(c.Points[i]); d > maxDist {
+			maxDist = d
+			pointIdx = i
+		}
+	}
+	point := c.Points[pointIdx]
+
+	// Find all faces visible from the point, and the
+	// horizon edges which separate them from the rest.
+	visible := []*convexHullFace{face}
+	face.Dead = true
+	var horizon [][2]int
+	for i := 0; i < len(visible); i++ {
+		f := visible[i]
+		for j := 0; j < 3; j++ {
+			edge := [2]int{f.Vertices[j], f.Vertices[(j+1)%3]}
+			neighbor := c.Edges[[2]int{edge[1], edge[0]}]
+			if neighbor.Dead {
+				continue
+			}
+			if c.orient(neighbor, point) > 0 {
+				neighbor.Dead = true
+				visible = append(visible, neighbor)
+			} else {
+				horizon = append(horizon, edge)
+			}
+		}
+	}
+
+	for _, f := range visible {
+		for j := 0; j < 3; j++ {
+			delete(c.Edges, [2]int{f.Vertices[j], f.Vertices[(j+1)%3]})
+		}
+	}
+	newFaces := make([]*convexHullFace, len(horizon))
+	for i, edge := range horizon {
+		newFaces[i] = c.addFace(edge[0], edge[1], pointIdx)
+	}
+	for _, f := range visible {
+		for _, i := range f.Outside {
+			if i != pointIdx {
+				c.assignOutside(i, newFaces)
+			}
+		}
+		f.Outside = nil
+	}
+}
+
+func (c *convexHull) addFace(i1, i2, i3 int) *convexHullFace {
+	p1, p2, p3 := c.Points[i1], c.Points[i2], c.Points[i3]
+	normal := p2.Sub(p1).Cross(p3.Sub(p1)).Normalize()
+	f := &convexHullFace{
+		Vertices: [3]int{i1, i2, i3},
+		Normal:   normal,
+		Offset:   normal.Dot(p1),
+	}
+	c.Faces = append(c.Faces, f)
+	c.Edges[[2]int{i1, i2}] = f
+	c.Edges[[2]int{i2, i3}] = f
+	c.Edges[[2]int{i3, i1}] = f
+	return f
+}
+
+func (c *convexHull) assignOutside(pointIdx int, faces []*convexHullFace) {
+	p := c.Points[pointIdx]
+	for _, f := range faces {
+		if c.orient(f, p) > 0 {
+			f.Outside = append(f.Outside, pointIdx)
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package model3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/numerical"
+)
+
+func TestConvexHull(t *testing.T) {
+	for trial := 0; trial < 20; trial++ {
+		points := make([]Coord3D, 4+rand.Intn(200))
+		for i := range points {
+			points[i] = NewCoord3DRandNorm()
+		}
+		hull := ConvexHull(points)
+		MustValidateMesh(t, hull, true)
+		sdf := MeshToSDF(hull)
+		for _, p := range points {
+			if sdf.SDF(p) < -1e-8 {
+				t.Fatalf("point %v is outside of hull", p)
+			}
+		}
+		hull.Iterate(func(tri *Triangle) {
+			normal := tri.Normal()
+			for _, p := range points {
+				if normal.Dot(p.Sub(tri[0])) > 1e-8 {
+					t.Fatal("hull is not convex")
+				}
+			}
+		})
+
+		polytope := NewConvexPolytopeHull(points)
+		for _, p := range points {
+			for _, l := range polytope {
+				if p.Dot(l.Normal) > l.Max+1e-8 {
+					t.Fatalf("point %v is outside of polytope", p)
+				}
+			}
+		}
+		polyMesh := polytope.Mesh()
+		if v1, v2 := hull.Volume(), polyMesh.Volume(); math.Abs(v1-v2) > 1e-5 {
+			t.Fatalf("expected volume %f but got %f", v1, v2)
+		}
+	}
+}
+
+func TestConvexHullCube(t *testing.T) {
+	// Include points on the faces, edges, and inside of
+	// the cube, as well as duplicate corners.
+	var points []Coord3D
+	for x := 0; x <= 4; x++ {
+		for y := 0; y <= 4; y++ {
+			for z := 0; z <= 4; z++ {
+				points = append(points, XYZ(float64(x), float64(y), float64(z)))
+			}
+		}
+	}
+	points = append(points, points...)
+	rand.Shuffle(len(points), func(i, j int) {
+		points[i], points[j] = points[j], points[i]
+	})
+
+	hull := ConvexHull(points)
+	MustValidateMesh(t, hull, true)
+	if v := hull.Volume(); math.Abs(v-64) > 1e-8 {
+		t.Errorf("expected volume 64 but got %f", v)
+	}
+	polytope := NewConvexPolytopeHull(points)
+	if len(polytope) != 6 {
+		t.Errorf("expected 6 constraints but got %d", len(polytope))
+	}
+
+	// Coplanar points have no hull.
+	if m := ConvexHull([]Coord3D{XYZ(0, 0, 0), XYZ(1, 0, 0), XYZ(0, 1, 0), XYZ(1, 1, 0)}); m.NumTriangles() != 0 {
+		t.Errorf("expected empty mesh but got %d triangles", m.NumTriangles())
+	}
+}
+
+func TestConvexHullNearlyCoplanar(t *testing.T) {
+	rng := rand.New(rand.NewSource(1337))
+	for trial := 0; trial < 20; trial++ {
+		// A rotated grid of points is only coplanar up to
+		// rounding error, which tempts tolerance-based hulls
+		// into keeping slightly reflex edges.
+		rotation := NewMatrix3Rotation(
+			XYZ(rng.NormFloat64(), rng.NormFloat64(), rng.NormFloat64()).Normalize(),
+			rng.Float64()*2*math.Pi,
+		)
+		var points []Coord3D
+		for x := 0; x < 10; x++ {
+			for y := 0; y < 10; y++ {
+				p := XYZ(float64(x), float64(y), rng.NormFloat64()*1e-12)
+				points = append(points, rotation.MulColumn(p))
+			}
+		}
+		points = append(points, rotation.MulColumn(XYZ(4.5, 4.5, 3)),
+			rotation.MulColumn(XYZ(4.5, 4.5, -3)))
+		rng.Shuffle(len(points), func(i, j int) {
+			points[i], points[j] = points[j], points[i]
+		})
+
+		hull := ConvexHull(points)
+		MustValidateMesh(t, hull, true)
+		hull.Iterate(func(tri *Triangle) {
+			for _, p := range points {
+				o := numerical.Orient3D(tri[0].Array(), tri[1].Array(), tri[2].Array(), p.Array())
+				if o < 0 {
+					t.Fatalf("trial %d: point %v is outside of face %v", trial, p, tri)
+				}
+			}
+		})
+	}
+}
@@ -192,3 +192,42 @@ func TestCoordTreeSphereCollision(t *testing.T) {
 		checkCollision(p)
 	}
 }
+
+func TestCoordTreeWithinRadius(t *testing.T) {
+	coords := make([]Coord3D, 1000)
+	for i := range coords {
+		coords[i] = NewCoord3DRandNorm()
+	}
+	// Include duplicates and axis-aligned ties.
+	coords = append(coords, coords[0:100]...)
+	for i := 0; i < 100; i++ {
+		c := coords[rand.Intn(len(coords))]
+		c.X = coords[rand.Intn(len(coords))].X
+		coords = append(coords, c)
+	}
+
+	tree := NewCoordTree(coords)
+
+	for i := 0; i < 100; i++ {
+		p := NewCoord3DRandNorm()
+		radius := rand.Float64()
+		var expected int
+		for _, c := range coords {
+			if c.Dist(p) <= radius {
+				expected++
+			}
+		}
+		actual := tree.WithinRadius(p, radius)
+		if len(actual) != expected {
+			t.Fatalf("expected %d points but got %d", expected, len(actual))
+		}
+		for _, c := range actual {
+			if c.Dist(p) > radius {
+				t.Fatalf("point %v is outside of radius", c)
+			}
+		}
+		if count := tree.CountWithinRadius(p, radius); count != expected {
+			t.Fatalf("expected count %d but got %d", expected, count)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package model3d
+
+import "math"
+
+// GaussianCurvature estimates the Gaussian curvature at
+// every vertex of the mesh.
+//
+// This uses the angle defect at each vertex, divided by
+// the mixed Voronoi area around the vertex, as described
+// in "Discrete Differential-Geometry Operators for
+// Triangulated 2-Manifolds" (Meyer et al., 2003).
+//
+// For vertices on the boundary of an open mesh, the angle
+// defect is measured relative to pi rather than 2*pi.
+func (m *Mesh) GaussianCurvature() *CoordToNumber[float64] {
+	areas := m.mixedAreas()
+	angleSums := NewCoordToNumber[float64]()
+	m.Iterate(func(t *Triangle) {
+		for i, c := range t {
+			angleSums.Add(c, triangleAngle(t, i))
+		}
+	})
+	boundary := m.boundaryVertices()
+
+	res := NewCoordToNumber[float64]()
+	angleSums.Range(func(c Coord3D, angleSum float64) bool {
+		total := 2 * math.Pi
+		if _, ok := boundary.Load(c); ok {
+			total = math.Pi
+		}
+		res.Store(c, (total-angleSum)/areas.Value(c))
+		return true
+	})
+	return res
+}
+
+// MeanCurvature estimates the mean curvature at every
+// vertex of the mesh.
+//
+// This uses the cotangent Laplacian of the vertex
+// positions, divided by the mixed Voronoi area around the
+// vertex, as described in "Discrete Differential-Geometry
+// Operators for Triangulated 2-Manifolds" (Meyer et al.,
+// 2003).
+//
+// The curvature is signed, so that convex regions (such as
+// every point on a sphere) have positive curvature and
+// concave regions have negative curvature. A sphere of
+// radius r has a mean curvature of 1/r.
+func (m *Mesh) MeanCurvature() *CoordToNumber[float64] {
+	areas := m.mixedAreas()
+	laplacians := NewCoordMap[Coord3D]()
+	m.Iterate(func(t *Triangle) {
+		for i := 0; i < 3; i++ {
+			// The cotangent of the angle at vertex i
+			// weights the opposite edge.
+			c1, c2 := t[(i+1)%3], t[(i+2)%3]
+			w := triangleCotangent(t, i)
+			cur1, _ := laplacians.Load(c1)
+			cur2, _ := laplacians.Load(c2)
+			laplacians.Store(c1, cur1.Add(c1.Sub(c2).Scale(w)))
+			laplacians.Store(c2, cur2.Add(c2.Sub(c1).Scale(w)))
+		}
+	})
+	normals := m.VertexNormals()
+
+	res := NewCoordToNumber[float64]()
+	laplacians.Range(func(c, laplacian Coord3D) bool {
+		// The Laplacian is 4*A*H times the normal.
+		res.Store(c, laplacian.Dot(normals.Value(c))/(4*areas.Value(c)))
+		return true
+	})
+	return res
+}
+
+// mixedAreas computes the mixed Voronoi area around each
+// vertex, which partitions the area of the mesh without
+// overlap, even for obtuse triangles.
+func (m *Mesh) mixedAreas() *CoordToNumber[float64] {
+	res := NewCoordToNumber[float64]()
+	m.Iterate(func(t *Triangle) {
+		area := t.Area()
+		obtuse := -1
+		for i := 0; i < 3; i++ {
+			if triangleCotangent(t, i) < 0 {
+				obtuse = i
+			}
+		}
+		for i, c := range t {
+			if obtuse == i {
+				res.Add(c, area/2)
+			} else if obtuse != -1 {
+				res.Add(c, area/4)
+			} else {
+				// The Voronoi region of the vertex is bounded
+				// by the perpendicular bisectors of its edges.
+				c1, c2 := t[(i+1)%3], t[(i+2)%3]
+				res.Add(c, (c.SquaredDist(c1)*triangleCotangent(t, (i+2)%3)+
+					c.SquaredDist(c2)*triangleCotangent(t, (i+1)%3))/8)
+			}
+		}
+	})
+	return res
+}
+
+// boundaryVertices finds all of the vertices which touch an
+// edge with only one adjacent triangle.
+func (m *Mesh) boundaryVertices() *CoordToNumber[int] {
+	edgeCounts := map[Segment]int{}
+	m.Iterate(func(t *Triangle) {
+		for _, s := range t.Segments() {
+			edgeCounts[s]++
+		}
+	})
+	res := NewCoordToNumber[int]()
+	for s, count := range edgeCounts {
+		if count == 1 {
+			res.Store(s[0], 1)
+			res.Store(s[1], 1)
+		}
+	}
+	return res
+}
+
+// triangleAngle computes the interior angle of a triangle
+// at the vertex t[i].
+func triangleAngle(t *Triangle, i int) float64 {
+	v1 := t[(i+1)%3].Sub(t[i]).Normalize()
+	v2 := t[(i+2)%3].Sub(t[i]).Normalize()
+	return math.Acos(math.Max(-1, math.Min(1, v1.Dot(v2))))
+}
+
+// triangleCotangent computes the cotangent of the interior
+// angle of a triangle at the vertex t[i].
+func triangleCotangent(t *Triangle, i int) float64 {
+	v1 := t[(i+1)%3].Sub(t[i])
+	v2 := t[(i+2)%3].Sub(t[i])
+	return v1.Dot(v2) / math.Max(1e-16, v1.Cross(v2).Norm())
+}
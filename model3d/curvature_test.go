@@ -0,0 +1,102 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeshGaussianCurvature(t *testing.T) {
+	t.Run("Sphere", func(t *testing.T) {
+		mesh := NewMeshIcosphere(XYZ(1, 2, 3), 2, 10)
+		mesh.GaussianCurvature().Range(func(c Coord3D, k float64) bool {
+			if math.Abs(k-0.25) > 0.01 {
+				t.Fatalf("expected curvature 0.25 but got %f at %v", k, c)
+			}
+			return true
+		})
+	})
+	t.Run("GaussBonnet", func(t *testing.T) {
+		// The total curvature of a closed genus zero surface
+		// is always 4*pi.
+		mesh := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 2, 3))
+		mesh = SubdivideEdges(mesh, 3)
+		curvature := mesh.GaussianCurvature()
+		areas := mesh.mixedAreas()
+		var total float64
+		curvature.Range(func(c Coord3D, k float64) bool {
+			total += k * areas.Value(c)
+			return true
+		})
+		if math.Abs(total-4*math.Pi) > 1e-8 {
+			t.Errorf("expected total curvature %f but got %f", 4*math.Pi, total)
+		}
+	})
+	t.Run("Torus", func(t *testing.T) {
+		// The Gaussian curvature of a torus is negative on
+		// the inside of the hole and positive on the outside.
+		mesh := NewMeshTorus(Coord3D{}, Z(1), 0.3, 1, 30, 60)
+		curvature := mesh.GaussianCurvature()
+		var inner, outer int
+		curvature.Range(func(c Coord3D, k float64) bool {
+			r := c.XY().Norm()
+			if math.Abs(c.Z) > 0.05 {
+				return true
+			}
+			if r < 1 {
+				inner++
+				if k >= 0 {
+					t.Fatalf("expected negative curvature at %v but got %f", c, k)
+				}
+			} else {
+				outer++
+				if k <= 0 {
+					t.Fatalf("expected positive curvature at %v but got %f", c, k)
+				}
+			}
+			return true
+		})
+		if inner == 0 || outer == 0 {
+			t.Fatal("no vertices were checked")
+		}
+	})
+	t.Run("Plane", func(t *testing.T) {
+		mesh := NewMesh()
+		for i := 0; i < 4; i++ {
+			for j := 0; j < 4; j++ {
+				x, y := float64(i), float64(j)
+				mesh.AddQuad(XY(x, y), XY(x+1, y), XY(x+1, y+1), XY(x, y+1))
+			}
+		}
+		mesh.GaussianCurvature().Range(func(c Coord3D, k float64) bool {
+			if (c.X == 0 || c.X == 4) && (c.Y == 0 || c.Y == 4) {
+				// Corners of the boundary are curved.
+				return true
+			}
+			if math.Abs(k) > 1e-8 {
+				t.Fatalf("expected zero curvature but got %f at %v", k, c)
+			}
+			return true
+		})
+	})
+}
+
+func TestMeshMeanCurvature(t *testing.T) {
+	t.Run("Sphere", func(t *testing.T) {
+		mesh := NewMeshIcosphere(XYZ(1, 2, 3), 2, 10)
+		mesh.MeanCurvature().Range(func(c Coord3D, h float64) bool {
+			if math.Abs(h-0.5) > 0.01 {
+				t.Fatalf("expected curvature 0.5 but got %f at %v", h, c)
+			}
+			return true
+		})
+	})
+	t.Run("Concave", func(t *testing.T) {
+		mesh := NewMeshIcosphere(XYZ(1, 2, 3), 2, 10).InvertNormals()
+		mesh.MeanCurvature().Range(func(c Coord3D, h float64) bool {
+			if math.Abs(h+0.5) > 0.01 {
+				t.Fatalf("expected curvature -0.5 but got %f at %v", h, c)
+			}
+			return true
+		})
+	})
+}
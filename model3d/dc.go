@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"context"
 	"math"
 	"sort"
 
@@ -13,6 +14,8 @@ const (
 	DefaultDualContouringRepairEpsilon        = 0.01
 	DefaultDualContouringCubeMargin           = 0.001
 	DefaultDualContouringSingularValueEpsilon = 0.1
+	DefaultDualContouringFeatureAngle         = 0.5
+	DefaultDualContouringAdaptiveError        = 0.05
 )
 
 type DualContouringTriangleMode int
@@ -142,11 +145,63 @@ type DualContouring struct {
 
 	// TriangleMode controls how quads are triangulated.
 	TriangleMode DualContouringTriangleMode
+
+	// SharpFeatures, if true, detects sharp edges and
+	// corners from the hermite data of each cube, and
+	// places vertices exactly on these features rather
+	// than solving a damped QEF.
+	//
+	// When Clip is also true, vertices are moved along
+	// feature edges to stay within their cubes, so that
+	// edges remain sharp whenever they pass through a cube.
+	SharpFeatures bool
+
+	// FeatureAngle is the minimum angle, in radians,
+	// between two normals of a cube for SharpFeatures or
+	// Adaptive to detect a feature.
+	//
+	// Defaults to DefaultDualContouringFeatureAngle.
+	FeatureAngle float64
+
+	// Adaptive, if true, merges cubes into the larger cells
+	// of an octree wherever a single vertex approximates the
+	// surface well, so that flat regions of the surface are
+	// covered by fewer, larger triangles.
+	//
+	// Cells are only merged when doing so preserves the
+	// topology of the surface, and cells containing sharp
+	// features (see FeatureAngle) are never merged. Even so,
+	// merged cells near sharp features may occasionally
+	// produce self-intersections. Adaptive meshes are stored
+	// in memory in full while they are being created, so
+	// BufferSize only limits the memory used for sampling.
+	Adaptive bool
+
+	// AdaptiveError is the maximum root-mean-square distance
+	// from a merged cell's vertex to the tangent planes of
+	// the cell's hermite data.
+	//
+	// This size is relative to Delta.
+	//
+	// Defaults to DefaultDualContouringAdaptiveError.
+	// Only is used if Adaptive is true.
+	AdaptiveError float64
+
+	// Progress, if non-nil, receives the fraction of the
+	// volume which has been processed.
+	Progress Progress
 }
 
 // Mesh computes a mesh for the surface.
 func (d *DualContouring) Mesh() *Mesh {
-	return d.mesh(nil)
+	m, _ := d.mesh(context.Background(), nil)
+	return m
+}
+
+// MeshContext is like Mesh(), but stops early and returns
+// ctx.Err() if ctx is cancelled or expires.
+func (d *DualContouring) MeshContext(ctx context.Context) (*Mesh, error) {
+	return d.mesh(ctx, nil)
 }
 
 // MeshInterior is like Mesh(), but also returns a slice of
@@ -158,11 +213,11 @@ func (d *DualContouring) Mesh() *Mesh {
 // vertices.
 func (d *DualContouring) MeshInterior() (*Mesh, []Coord3D) {
 	var points []Coord3D
-	m := d.mesh(&points)
+	m, _ := d.mesh(context.Background(), &points)
 	return m, points
 }
 
-func (d *DualContouring) mesh(interior *[]Coord3D) *Mesh {
+func (d *DualContouring) mesh(ctx context.Context, interior *[]Coord3D) (*Mesh, error) {
 	if !BoundsValid(d.S.Solid) {
 		panic("invalid bounds for solid")
 	}
@@ -173,24 +228,41 @@ func (d *DualContouring) mesh(interior *[]Coord3D) *Mesh {
 	}
 
 	mesh := NewMesh()
+	var tree *dcOctree
+	if d.Adaptive {
+		tree = newDcOctree(d, layout)
+	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		d.populateCorners(layout)
 		d.populateEdges(layout, interior)
-		d.populateCubes(layout)
-		d.appendMesh(layout, mesh)
+		if tree != nil {
+			tree.AddCubes(layout)
+		} else {
+			d.populateCubes(layout)
+			d.appendMesh(layout, mesh)
+		}
+		progressUpdate(d.Progress, float64(layout.ZOffset+layout.BufRows)/float64(len(layout.Zs)))
 		if layout.Remaining() == 0 {
 			break
 		}
 		layout.Shift()
 	}
 
+	if tree != nil {
+		tree.Simplify()
+		tree.AppendMesh(mesh)
+	}
+
 	if d.Repair {
 		orig := d.repairSingularEdges(mesh, layout)
 		d.repairSingularVertices(mesh, layout, orig)
 		mesh.clearVertexToFace()
 	}
 
-	return mesh
+	return mesh, nil
 }
 
 func (d *DualContouring) populateCorners(layout *dcCubeLayout) {
@@ -243,50 +315,158 @@ func (d *DualContouring) populateCubes(layout *dcCubeLayout) {
 		if !layout.CubeActive(dcCubeIdx(i)) {
 			return
 		}
-		var massPoint Coord3D
-		var count float64
-		var active [12]*dcEdge
-		for i, edgeIdx := range layout.CubeEdges(dcCubeIdx(i)) {
+		var points, normals []Coord3D
+		for _, edgeIdx := range layout.CubeEdges(dcCubeIdx(i)) {
 			if edgeIdx < 0 {
 				panic("edge not available for active cube; this likely means the Solid is true outside of bounds")
 			}
 			edge := layout.Edge(edgeIdx)
 			if edge.Active {
-				active[i] = edge
-				massPoint = massPoint.Add(edge.Coord)
-				count++
+				points = append(points, edge.Coord)
+				normals = append(normals, edge.Normal)
 			}
 		}
-		if count == 0 {
+		if len(points) == 0 {
 			panic("no acive edges found")
 		}
-		massPoint = massPoint.Scale(1 / count)
-
-		var matA []numerical.Vec3
-		var matB []float64
-		for _, edge := range active {
-			if edge != nil {
-				v := edge.Coord.Sub(massPoint)
-				matA = append(matA, edge.Normal.Array())
-				matB = append(matB, v.Dot(edge.Normal))
+		minPoint, maxPoint := layout.CubeMinMax(dcCubeIdx(i))
+		cube.VertexPosition = d.solveVertex(points, normals, minPoint, maxPoint)
+	})
+}
+
+// solveVertex computes the vertex position for a cell
+// from its hermite data.
+func (d *DualContouring) solveVertex(points, normals []Coord3D, minPoint, maxPoint Coord3D) Coord3D {
+	var massPoint Coord3D
+	for _, c := range points {
+		massPoint = massPoint.Add(c)
+	}
+	massPoint = massPoint.Scale(1 / float64(len(points)))
+
+	if d.SharpFeatures {
+		if p, ok := d.solveSharpVertex(points, normals, massPoint, minPoint, maxPoint); ok {
+			return p
+		}
+	}
+
+	var matA []numerical.Vec3
+	var matB []float64
+	for i, c := range points {
+		v := c.Sub(massPoint)
+		matA = append(matA, normals[i].Array())
+		matB = append(matB, v.Dot(normals[i]))
+	}
+	solution := numerical.LeastSquaresReg3(matA, matB, d.L2Penalty, d.singularValueEpsilon())
+	p := NewCoord3DArray(solution).Add(massPoint)
+	if d.Clip {
+		minPoint, maxPoint = d.clipBounds(minPoint, maxPoint)
+		p = p.Max(minPoint).Min(maxPoint)
+	}
+	return p
+}
+
+// solveSharpVertex detects a sharp edge or corner from
+// clusters of normals, and places a vertex on it.
+//
+// If no feature is found, false is returned.
+func (d *DualContouring) solveSharpVertex(points, normals []Coord3D, massPoint, minPoint,
+	maxPoint Coord3D) (Coord3D, bool) {
+	axis, ok := d.featureAxis(normals)
+	if !ok {
+		return Coord3D{}, false
+	}
+
+	var corner bool
+	var matA, edgeMatA []numerical.Vec3
+	var matB, edgeMatB []float64
+	for i, c := range points {
+		n := normals[i]
+		if math.Abs(n.Dot(axis)) > math.Sin(d.featureAngle()) {
+			corner = true
+		}
+		v := c.Sub(massPoint)
+		matA = append(matA, n.Array())
+		matB = append(matB, v.Dot(n))
+
+		// Projecting out the feature direction leaves the
+		// position along the edge at the mass point.
+		edgeNormal := n.Sub(axis.Scale(n.Dot(axis)))
+		edgeMatA = append(edgeMatA, edgeNormal.Array())
+		edgeMatB = append(edgeMatB, v.Dot(edgeNormal))
+	}
+	epsilon := 1e-6 * float64(len(points))
+	if d.Clip {
+		minPoint, maxPoint = d.clipBounds(minPoint, maxPoint)
+	}
+	if corner {
+		p := NewCoord3DArray(numerical.LeastSquares3(matA, matB, epsilon)).Add(massPoint)
+		if !d.Clip || (p.Max(minPoint) == p && p.Min(maxPoint) == p) {
+			return p, true
+		}
+	}
+	p := NewCoord3DArray(numerical.LeastSquares3(edgeMatA, edgeMatB, epsilon)).Add(massPoint)
+	if d.Clip {
+		// Move the vertex along the edge into the cube.
+		tMin, tMax := math.Inf(-1), math.Inf(1)
+		origin, dir := p.Array(), axis.Array()
+		lo, hi := minPoint.Array(), maxPoint.Array()
+		for i := 0; i < 3; i++ {
+			if dir[i] == 0 {
+				if origin[i] < lo[i] || origin[i] > hi[i] {
+					return Coord3D{}, false
+				}
+				continue
 			}
+			t1 := (lo[i] - origin[i]) / dir[i]
+			t2 := (hi[i] - origin[i]) / dir[i]
+			tMin = math.Max(tMin, math.Min(t1, t2))
+			tMax = math.Min(tMax, math.Max(t1, t2))
 		}
-		solution := numerical.LeastSquaresReg3(matA, matB, d.L2Penalty, d.singularValueEpsilon())
-		p := NewCoord3DArray(solution).Add(massPoint)
-		if d.Clip {
-			minPoint, maxPoint := layout.CubeMinMax(dcCubeIdx(i))
-			margin := d.CubeMargin
-			if margin == 0 {
-				margin = DefaultDualContouringCubeMargin
+		if tMin > tMax {
+			return Coord3D{}, false
+		}
+		t := math.Max(tMin, math.Min(tMax, 0))
+		p = p.Add(axis.Scale(t)).Max(minPoint).Min(maxPoint)
+	}
+	return p, true
+}
+
+// featureAxis finds the direction of a sharp edge from the
+// normals of a cell, or returns false if the normals do
+// not indicate a sharp feature.
+func (d *DualContouring) featureAxis(normals []Coord3D) (Coord3D, bool) {
+	// The two most different normals span the feature.
+	minDot := math.Inf(1)
+	var n1, n2 Coord3D
+	for i, n := range normals {
+		for _, n1Other := range normals[i+1:] {
+			if dot := n.Dot(n1Other); dot < minDot {
+				minDot = dot
+				n1, n2 = n, n1Other
 			}
-			margin = margin * d.Delta
-			minPoint = minPoint.AddScalar(margin)
-			maxPoint = maxPoint.AddScalar(-margin)
-			p = p.Max(minPoint).Min(maxPoint)
 		}
+	}
+	if minDot > math.Cos(d.featureAngle()) {
+		return Coord3D{}, false
+	}
+	axis := n1.Cross(n2)
+	if axis.Norm() < 1e-8 {
+		// Opposite normals indicate a thin sheet rather than
+		// a sharp feature.
+		return Coord3D{}, false
+	}
+	return axis.Normalize(), true
+}
 
-		cube.VertexPosition = p
-	})
+// clipBounds shrinks the bounds of a cell by the cube
+// margin.
+func (d *DualContouring) clipBounds(minPoint, maxPoint Coord3D) (Coord3D, Coord3D) {
+	margin := d.CubeMargin
+	if margin == 0 {
+		margin = DefaultDualContouringCubeMargin
+	}
+	margin = margin * d.Delta
+	return minPoint.AddScalar(margin), maxPoint.AddScalar(-margin)
 }
 
 func (d *DualContouring) appendMesh(layout *dcCubeLayout, mesh *Mesh) {
@@ -438,6 +618,13 @@ func (d *DualContouring) repairEpsilon() float64 {
 	return d.RepairEpsilon * d.Delta
 }
 
+func (d *DualContouring) featureAngle() float64 {
+	if d.FeatureAngle != 0 {
+		return d.FeatureAngle
+	}
+	return DefaultDualContouringFeatureAngle
+}
+
 func (d *DualContouring) singularValueEpsilon() float64 {
 	if d.SingularValueEpsilon != 0 {
 		return d.SingularValueEpsilon
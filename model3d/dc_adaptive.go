@@ -0,0 +1,456 @@
+package model3d
+
+import (
+	"github.com/unixpickle/essentials"
+)
+
+// dcOctree merges the active cubes of a dual contouring
+// grid into larger cells for adaptive meshing.
+//
+// Cells are identified by their level, where a cell at
+// level l spans 2^l cubes along each axis, and by a key,
+// which is the minimum cube coordinate of the cell divided
+// by 2^l.
+//
+// The algorithm is based on:
+// "Dual Contouring of Hermite Data" - Tao Ju, Frank
+// Losasso, Scott Schaefer and Joe Warren.
+// https://www.cse.wustl.edu/~taoju/research/dualContour.pdf.
+type dcOctree struct {
+	DC     *DualContouring
+	Layout *dcCubeLayout
+
+	// Leaves stores the leaf cells at each level.
+	Leaves []map[[3]int]*dcOctreeCell
+
+	// Internal stores the keys of the cells at each level
+	// which contain smaller leaves.
+	Internal []map[[3]int]bool
+
+	// Signs caches the values of the solid at the corners
+	// of the grid.
+	Signs map[[3]int]bool
+}
+
+type dcOctreeCell struct {
+	Key     [3]int
+	Points  []Coord3D
+	Normals []Coord3D
+	Vertex  Coord3D
+}
+
+func newDcOctree(d *DualContouring, layout *dcCubeLayout) *dcOctree {
+	maxCubes := essentials.MaxInt(len(layout.Xs), essentials.MaxInt(len(layout.Ys), len(layout.Zs))) - 1
+	numLevels := 1
+	for 1<<(numLevels-1) < maxCubes {
+		numLevels++
+	}
+	res := &dcOctree{
+		DC:       d,
+		Layout:   layout,
+		Leaves:   make([]map[[3]int]*dcOctreeCell, numLevels),
+		Internal: make([]map[[3]int]bool, numLevels),
+		Signs:    map[[3]int]bool{},
+	}
+	for i := range res.Leaves {
+		res.Leaves[i] = map[[3]int]*dcOctreeCell{}
+		res.Internal[i] = map[[3]int]bool{}
+	}
+	return res
+}
+
+// AddCubes creates leaf cells for the active cubes in the
+// current buffer of the layout.
+func (o *dcOctree) AddCubes(layout *dcCubeLayout) {
+	for i := range layout.Cubes {
+		idx := dcCubeIdx(i)
+		cube := layout.Cube(idx)
+		if cube.Populated {
+			continue
+		}
+		cube.Populated = true
+		if !layout.CubeActive(idx) {
+			continue
+		}
+		x, y, z := layout.cubeCoord(idx)
+		z += layout.ZOffset
+		for j, c := range layout.CubeCorners(idx) {
+			o.Signs[[3]int{x + j%2, y + (j/2)%2, z + j/4}] = layout.Corner(c).Value
+		}
+		cell := &dcOctreeCell{Key: [3]int{x, y, z}}
+		for _, edgeIdx := range layout.CubeEdges(idx) {
+			if edgeIdx < 0 {
+				panic("edge not available for active cube; this likely means the Solid is true outside of bounds")
+			}
+			edge := layout.Edge(edgeIdx)
+			if edge.Active {
+				cell.Points = append(cell.Points, edge.Coord)
+				cell.Normals = append(cell.Normals, edge.Normal)
+			}
+		}
+		o.Leaves[0][cell.Key] = cell
+	}
+}
+
+// Simplify computes vertices for the leaves and merges
+// cells from the bottom up.
+func (o *dcOctree) Simplify() {
+	leaves := make([]*dcOctreeCell, 0, len(o.Leaves[0]))
+	for _, cell := range o.Leaves[0] {
+		leaves = append(leaves, cell)
+	}
+	essentials.ConcurrentMap(o.DC.MaxGos, len(leaves), func(i int) {
+		cell := leaves[i]
+		min, max := o.CellMinMax(cell.Key, 0)
+		cell.Vertex = o.DC.solveVertex(cell.Points, cell.Normals, min, max)
+	})
+
+	for level := 1; level < len(o.Leaves); level++ {
+		children := map[[3]int][]*dcOctreeCell{}
+		for key, cell := range o.Leaves[level-1] {
+			parent := [3]int{key[0] >> 1, key[1] >> 1, key[2] >> 1}
+			children[parent] = append(children[parent], cell)
+		}
+		blocked := o.Internal[level]
+		for key := range o.Internal[level-1] {
+			blocked[[3]int{key[0] >> 1, key[1] >> 1, key[2] >> 1}] = true
+		}
+		var candidates [][3]int
+		for key := range children {
+			if !blocked[key] {
+				candidates = append(candidates, key)
+			}
+		}
+
+		// Evaluate the solid ahead of time so that cells can
+		// be collapsed concurrently.
+		half := 1 << (level - 1)
+		missing := map[[3]int]bool{}
+		for _, key := range candidates {
+			for i := 0; i < 27; i++ {
+				p := [3]int{
+					(key[0]*2 + i%3) * half,
+					(key[1]*2 + (i/3)%3) * half,
+					(key[2]*2 + i/9) * half,
+				}
+				if _, ok := o.Signs[p]; !ok {
+					missing[p] = true
+				}
+			}
+		}
+		o.populateSigns(missing)
+
+		results := make([]*dcOctreeCell, len(candidates))
+		essentials.ConcurrentMap(o.DC.MaxGos, len(candidates), func(i int) {
+			results[i] = o.collapse(candidates[i], level, children[candidates[i]])
+		})
+		for i, key := range candidates {
+			if cell := results[i]; cell != nil {
+				o.Leaves[level][key] = cell
+				for _, child := range children[key] {
+					delete(o.Leaves[level-1], child.Key)
+				}
+			} else {
+				blocked[key] = true
+			}
+		}
+		if len(o.Leaves[level]) == 0 {
+			break
+		}
+	}
+}
+
+// AppendMesh adds a polygon for every minimal edge of the
+// octree which crosses the surface.
+//
+// A minimal edge is an edge of a leaf which is not
+// subdivided by any smaller neighboring leaves.
+func (o *dcOctree) AppendMesh(m *Mesh) {
+	seen := map[[5]int]bool{}
+	for level, leaves := range o.Leaves {
+		size := 1 << level
+		for key := range leaves {
+			for axis := 0; axis < 3; axis++ {
+				b, c := (axis+1)%3, (axis+2)%3
+				for i := 0; i < 4; i++ {
+					var start [3]int
+					for j := range start {
+						start[j] = key[j] * size
+					}
+					start[b] += (i % 2) * size
+					start[c] += (i / 2) * size
+					end := start
+					end[axis] += size
+					startSign, endSign := o.sign(start), o.sign(end)
+					if startSign == endSign {
+						continue
+					}
+					edgeKey := [5]int{start[0], start[1], start[2], axis, level}
+					if seen[edgeKey] {
+						continue
+					}
+					seen[edgeKey] = true
+					cells, ok := o.edgeCells(start, level, b, c)
+					if !ok {
+						continue
+					}
+					if !startSign {
+						for j := 0; j < len(cells)/2; j++ {
+							cells[j], cells[len(cells)-j-1] = cells[len(cells)-j-1], cells[j]
+						}
+					}
+					if len(cells) == 3 {
+						m.Add(&Triangle{cells[0].Vertex, cells[1].Vertex, cells[2].Vertex})
+					} else {
+						t1, t2 := o.DC.triangulateQuad([4]Coord3D{
+							cells[0].Vertex, cells[1].Vertex, cells[2].Vertex, cells[3].Vertex,
+						})
+						m.Add(t1)
+						m.Add(t2)
+					}
+				}
+			}
+		}
+	}
+}
+
+// CellMinMax gets the bounds of a cell.
+func (o *dcOctree) CellMinMax(key [3]int, level int) (min, max Coord3D) {
+	size := 1 << level
+	min = o.cornerCoord([3]int{key[0] * size, key[1] * size, key[2] * size})
+	max = o.cornerCoord([3]int{(key[0] + 1) * size, (key[1] + 1) * size, (key[2] + 1) * size})
+	return
+}
+
+// edgeCells finds the distinct leaves around an edge,
+// ordered counter-clockwise around the edge's axis.
+//
+// If the edge is not minimal, or if it does not touch at
+// least three leaves, false is returned.
+func (o *dcOctree) edgeCells(start [3]int, level, b, c int) ([]*dcOctreeCell, bool) {
+	size := 1 << level
+	var cells []*dcOctreeCell
+	for _, offset := range [4][2]int{{0, 0}, {-1, 0}, {-1, -1}, {0, -1}} {
+		region := start
+		region[b] += offset[0] * size
+		region[c] += offset[1] * size
+		if region[b] < 0 || region[c] < 0 {
+			return nil, false
+		}
+		key := [3]int{region[0] / size, region[1] / size, region[2] / size}
+		if o.Internal[level][key] {
+			return nil, false
+		}
+		cell := o.findLeaf(key, level)
+		if cell == nil {
+			return nil, false
+		}
+		if len(cells) == 0 || cells[len(cells)-1] != cell {
+			cells = append(cells, cell)
+		}
+	}
+	if len(cells) > 1 && cells[0] == cells[len(cells)-1] {
+		cells = cells[:len(cells)-1]
+	}
+	return cells, len(cells) >= 3
+}
+
+// findLeaf finds the leaf containing a cell, or returns
+// nil if the cell is empty.
+func (o *dcOctree) findLeaf(key [3]int, level int) *dcOctreeCell {
+	for l := level; l < len(o.Leaves); l++ {
+		shift := l - level
+		k := [3]int{key[0] >> shift, key[1] >> shift, key[2] >> shift}
+		if cell, ok := o.Leaves[l][k]; ok {
+			return cell
+		}
+	}
+	return nil
+}
+
+// collapse attempts to merge the children of a cell into
+// a single leaf, or returns nil if this would change the
+// topology of the surface or introduce too much error.
+func (o *dcOctree) collapse(key [3]int, level int, children []*dcOctreeCell) *dcOctreeCell {
+	half := 1 << (level - 1)
+	var signs [27]bool
+	for i := range signs {
+		signs[i] = o.sign([3]int{
+			(key[0]*2 + i%3) * half,
+			(key[1]*2 + (i/3)%3) * half,
+			(key[2]*2 + i/9) * half,
+		})
+	}
+	if !dcCollapseSafe(signs) {
+		return nil
+	}
+
+	cell := &dcOctreeCell{Key: key}
+	seen := NewCoordMap[bool]()
+	for _, child := range children {
+		for i, p := range child.Points {
+			if _, ok := seen.Load(p); !ok {
+				seen.Store(p, true)
+				cell.Points = append(cell.Points, p)
+				cell.Normals = append(cell.Normals, child.Normals[i])
+			}
+		}
+	}
+	if _, ok := o.DC.featureAxis(cell.Normals); ok {
+		// Merging across sharp features tends to produce
+		// triangles that fold over each other.
+		return nil
+	}
+	min, max := o.CellMinMax(key, level)
+	cell.Vertex = o.DC.solveVertex(cell.Points, cell.Normals, min, max)
+
+	var totalError float64
+	for i, p := range cell.Points {
+		dist := cell.Normals[i].Dot(cell.Vertex.Sub(p))
+		totalError += dist * dist
+	}
+	maxError := o.DC.AdaptiveError
+	if maxError == 0 {
+		maxError = DefaultDualContouringAdaptiveError
+	}
+	maxError *= o.DC.Delta
+	if totalError/float64(len(cell.Points)) > maxError*maxError {
+		return nil
+	}
+	return cell
+}
+
+func (o *dcOctree) sign(p [3]int) bool {
+	if value, ok := o.Signs[p]; ok {
+		return value
+	}
+	value := o.DC.S.Solid.Contains(o.cornerCoord(p))
+	o.Signs[p] = value
+	return value
+}
+
+func (o *dcOctree) populateSigns(missing map[[3]int]bool) {
+	points := make([][3]int, 0, len(missing))
+	for p := range missing {
+		points = append(points, p)
+	}
+	values := make([]bool, len(points))
+	essentials.ConcurrentMap(o.DC.MaxGos, len(points), func(i int) {
+		values[i] = o.DC.S.Solid.Contains(o.cornerCoord(points[i]))
+	})
+	for i, p := range points {
+		o.Signs[p] = values[i]
+	}
+}
+
+func (o *dcOctree) cornerCoord(p [3]int) Coord3D {
+	var res [3]float64
+	for i, arr := range [3][]float64{o.Layout.Xs, o.Layout.Ys, o.Layout.Zs} {
+		if p[i] < len(arr) {
+			res[i] = arr[p[i]]
+		} else {
+			// Cells may extend past the grid, where the solid
+			// is empty.
+			res[i] = arr[len(arr)-1] + float64(p[i]-len(arr)+1)*o.DC.Delta
+		}
+	}
+	return NewCoord3DArray(res)
+}
+
+// dcCollapseSafe checks if a cell can be merged without
+// changing the topology of the surface, given the signs
+// on a 3x3x3 grid of its children's corners.
+//
+// This implements the test from "Topology preserving and
+// controlled topology simplifying multiresolution
+// isosurface extraction" - Thomas Gerstner and Renato
+// Pajarola.
+func dcCollapseSafe(signs [27]bool) bool {
+	at := func(x, y, z int) bool {
+		return signs[x+y*3+z*9]
+	}
+
+	// Every child, and the merged cell, must contain a
+	// single sheet of the surface.
+	var corners [8]bool
+	for i := range corners {
+		corners[i] = at((i%2)*2, ((i/2)%2)*2, (i/4)*2)
+	}
+	if !dcCornersManifold(corners) {
+		return false
+	}
+	for child := 0; child < 8; child++ {
+		x, y, z := child%2, (child/2)%2, child/4
+		var childCorners [8]bool
+		for i := range childCorners {
+			childCorners[i] = at(x+i%2, y+(i/2)%2, z+i/4)
+		}
+		if !dcCornersManifold(childCorners) {
+			return false
+		}
+	}
+
+	// The sign at the middle of every edge, face, and the
+	// cell itself must agree with at least one corner of
+	// that edge, face, or cell.
+	for i := 0; i < 27; i++ {
+		x, y, z := i%3, (i/3)%3, i/9
+		if x != 1 && y != 1 && z != 1 {
+			continue
+		}
+		agrees := false
+		for j := 0; j < 8; j++ {
+			cx, cy, cz := x, y, z
+			if cx == 1 {
+				cx = (j % 2) * 2
+			}
+			if cy == 1 {
+				cy = ((j / 2) % 2) * 2
+			}
+			if cz == 1 {
+				cz = (j / 4) * 2
+			}
+			if at(cx, cy, cz) == at(x, y, z) {
+				agrees = true
+				break
+			}
+		}
+		if !agrees {
+			return false
+		}
+	}
+	return true
+}
+
+// dcCornersManifold checks that the inside corners and the
+// outside corners of a cube are each connected by the
+// edges of the cube.
+func dcCornersManifold(corners [8]bool) bool {
+	for _, value := range []bool{false, true} {
+		var visited [8]bool
+		var queue []int
+		var total int
+		for i, c := range corners {
+			if c == value {
+				total++
+				if len(queue) == 0 {
+					queue = append(queue, i)
+					visited[i] = true
+				}
+			}
+		}
+		for j := 0; j < len(queue); j++ {
+			for bit := 1; bit < 8; bit <<= 1 {
+				n := queue[j] ^ bit
+				if corners[n] == value && !visited[n] {
+					visited[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+		if len(queue) != total {
+			return false
+		}
+	}
+	return true
+}
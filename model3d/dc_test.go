@@ -148,6 +148,70 @@ func TestDualContouringSingular(t *testing.T) {
 	})
 }
 
+func TestDualContouringSharpFeatures(t *testing.T) {
+	rect := NewRect(XYZ(-1, -1, -1), XYZ(1, 1, 1))
+	rotation := Rotation(XYZ(1.0, 2.0, 3.0).Normalize(), 0.7)
+	inverse := rotation.Inverse()
+	solid := TransformSolid(rotation, rect)
+	maxError := func(sharp bool) float64 {
+		dc := &DualContouring{
+			S:             SolidSurfaceEstimator{Solid: solid},
+			Delta:         0.05,
+			SharpFeatures: sharp,
+		}
+		mesh := dc.Mesh()
+		var res float64
+		mesh.IterateVertices(func(c Coord3D) {
+			res = math.Max(res, math.Abs(rect.SDF(inverse.Apply(c))))
+		})
+		return res
+	}
+	smooth := maxError(false)
+	sharp := maxError(true)
+	if sharp > smooth/2 {
+		t.Errorf("expected sharp error (%g) to be much less than smooth error (%g)",
+			sharp, smooth)
+	}
+}
+
+func TestDualContouringAdaptive(t *testing.T) {
+	t.Run("Rect", func(t *testing.T) {
+		dc := &DualContouring{
+			S:     SolidSurfaceEstimator{Solid: NewRect(Ones(-1), Ones(1))},
+			Delta: 0.04,
+		}
+		uniform := dc.Mesh()
+		dc.Adaptive = true
+		mesh := dc.Mesh()
+		MustValidateMesh(t, mesh, false)
+		if n, m := len(mesh.TriangleSlice()), len(uniform.TriangleSlice()); n > m/4 {
+			t.Errorf("expected far fewer than %d triangles but got %d", m, n)
+		}
+		volume := mesh.Volume()
+		if math.Abs(volume-8) > 1e-2 {
+			t.Errorf("expected volume %f but got %f", 8.0, volume)
+		}
+	})
+	t.Run("Sphere", func(t *testing.T) {
+		dc := &DualContouring{
+			S:     SolidSurfaceEstimator{Solid: &Sphere{Radius: 1.0}},
+			Delta: 0.04,
+		}
+		uniform := dc.Mesh()
+		dc.Adaptive = true
+		mesh := dc.Mesh()
+		MustValidateMesh(t, mesh, false)
+		if n, m := len(mesh.TriangleSlice()), len(uniform.TriangleSlice()); n > m/4 {
+			t.Errorf("expected far fewer than %d triangles but got %d", m, n)
+		}
+		volume := mesh.Volume()
+		expected := 4.0 / 3.0 * math.Pi
+		if math.Abs(volume-expected) > 5e-2 {
+			t.Errorf("expected volume %f but got %f", expected, volume)
+		}
+	})
+}
+
 func BenchmarkDualContouring(b *testing.B) {
 	runBench := func(b *testing.B, gos int, repair bool) {
 		solid := &CylinderSolid{
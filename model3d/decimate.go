@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"context"
 	"math"
 
 	"github.com/unixpickle/essentials"
@@ -11,6 +12,8 @@ const (
 	DefaultDecimatorFeatureAngle   = 0.5
 )
 
+const decimateCheckInterval = 256
+
 // DecimateSimple decimates a mesh using a specified
 // distance epsilon combined with default parameters.
 //
@@ -74,6 +77,10 @@ type Decimator struct {
 	// If FilterFunc returns false for a coordinate, it
 	// may not be removed; otherwise it may be removed.
 	FilterFunc func(c Coord3D) bool
+
+	// Progress, if non-nil, receives the fraction of
+	// vertices which have been considered for removal.
+	Progress Progress
 }
 
 // Decimate applies the decimation algorithm to m,
@@ -82,11 +89,18 @@ func (d *Decimator) Decimate(m *Mesh) *Mesh {
 	return d.decimator().Decimate(m)
 }
 
+// DecimateContext is like Decimate, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+func (d *Decimator) DecimateContext(ctx context.Context, m *Mesh) (*Mesh, error) {
+	return d.decimator().DecimateContext(ctx, m)
+}
+
 func (d *Decimator) decimator() *decimator {
 	return &decimator{
 		FeatureAngle:       d.FeatureAngle,
 		MinimumAspectRatio: d.MinimumAspectRatio,
 		SplitAttempts:      d.SplitAttempts,
+		Progress:           d.Progress,
 		Criterion: &distanceDecCriterion{
 			PlaneDistance:      d.PlaneDistance,
 			BoundaryDistance:   d.BoundaryDistance,
@@ -155,6 +169,14 @@ type decimator struct {
 	SplitAttempts      int
 
 	Criterion decCriterion
+
+	// Fixed, if non-nil, prevents vertices from being
+	// considered for removal at all. Unlike the criterion,
+	// this may be used for vertices that are not fully
+	// surrounded by triangles.
+	Fixed func(c Coord3D) bool
+
+	Progress Progress
 }
 
 func (d *decimator) Decimate(m *Mesh) *Mesh {
@@ -163,7 +185,25 @@ func (d *decimator) Decimate(m *Mesh) *Mesh {
 	return pm.Mesh()
 }
 
+func (d *decimator) DecimateContext(ctx context.Context, m *Mesh) (*Mesh, error) {
+	pm := newPtrMeshMesh(m)
+	if _, err := d.decimatePtrMeshContext(ctx, pm); err != nil {
+		return nil, err
+	}
+	return pm.Mesh(), nil
+}
+
 func (d *decimator) decimatePtrMesh(p *ptrMesh) int {
+	eliminated, _ := d.decimatePtrMeshContext(context.Background(), p)
+	return eliminated
+}
+
+// decimatePtrMeshContext removes vertices from p, checking
+// for cancellation every decimateCheckInterval vertices.
+//
+// If ctx is cancelled, p is left in a valid but partially
+// decimated state.
+func (d *decimator) decimatePtrMeshContext(ctx context.Context, p *ptrMesh) (int, error) {
 	coords := map[*ptrCoord]struct{}{}
 	p.Iterate(func(t *ptrTriangle) {
 		for _, c := range t.Coords {
@@ -171,13 +211,25 @@ func (d *decimator) decimatePtrMesh(p *ptrMesh) int {
 		}
 	})
 	var eliminated int
+	var visited int
 	for c := range coords {
+		if visited%decimateCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return eliminated, err
+			}
+			progressUpdate(d.Progress, float64(visited)/float64(len(coords)))
+		}
+		visited++
+		if d.Fixed != nil && d.Fixed(c.Coord3D) {
+			continue
+		}
 		v := newDecVertex(c, d.FeatureAngle)
 		if d.Criterion.canRemoveVertex(v) && d.attemptRemoveVertex(p, v) {
 			eliminated++
 		}
 	}
-	return eliminated
+	progressUpdate(d.Progress, 1)
+	return eliminated, nil
 }
 
 func (d *decimator) attemptRemoveVertex(p *ptrMesh, v *decVertex) bool {
@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"context"
 	"math"
 
 	"github.com/unixpickle/model3d/numerical"
@@ -226,6 +227,31 @@ func (a *ARAP) Deform(constraints ARAPConstraints) *Mesh {
 	return a.coordsToMesh(outSlice)
 }
 
+// DeformContext is like Deform, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+func (a *ARAP) DeformContext(ctx context.Context, constraints ARAPConstraints) (*Mesh, error) {
+	return a.DeformProgress(ctx, constraints, nil)
+}
+
+// DeformProgress is like DeformContext, but reports the
+// fraction of the maximum number of iterations which have
+// been completed to p.
+//
+// Since optimization usually converges before reaching
+// MaxIterations(), the reported fraction may jump to 1 at
+// the end of the operation.
+//
+// The progress p may be nil.
+func (a *ARAP) DeformProgress(ctx context.Context, constraints ARAPConstraints,
+	p Progress) (*Mesh, error) {
+	l := newARAPOperator(a, a.indexConstraints(constraints))
+	outSlice, err := a.deformMapContext(ctx, l, nil, p)
+	if err != nil {
+		return nil, err
+	}
+	return a.coordsToMesh(outSlice), nil
+}
+
 // SeqDeformer creates a function that deforms the mesh,
 // potentially caching computations across calls.
 //
@@ -290,6 +316,12 @@ func (a *ARAP) DeformMap(constraints ARAPConstraints,
 }
 
 func (a *ARAP) deformMap(l *arapOperator, initialGuess []Coord3D) []Coord3D {
+	res, _ := a.deformMapContext(context.Background(), l, initialGuess, nil)
+	return res
+}
+
+func (a *ARAP) deformMapContext(ctx context.Context, l *arapOperator,
+	initialGuess []Coord3D, p Progress) ([]Coord3D, error) {
 	if initialGuess == nil {
 		initialGuess = a.laplace(l)
 	}
@@ -300,6 +332,9 @@ func (a *ARAP) deformMap(l *arapOperator, initialGuess []Coord3D) []Coord3D {
 	rotations := a.rotations(currentOutput)
 	lastEnergy := a.energy(currentOutput, rotations)
 	for iter := 0; iter < a.maxIters; iter++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		targets := l.Targets(rotations)
 		currentOutput = l.LinSolve(targets)
 		rotations = a.rotations(currentOutput)
@@ -308,9 +343,11 @@ func (a *ARAP) deformMap(l *arapOperator, initialGuess []Coord3D) []Coord3D {
 			break
 		}
 		lastEnergy = energy
+		progressUpdate(p, float64(iter+1)/float64(a.maxIters))
 	}
+	progressUpdate(p, 1)
 
-	return currentOutput
+	return currentOutput, nil
 }
 
 // rotations computes the rotations-of-best-fit for the
@@ -487,9 +524,7 @@ func (a *arapOperator) LinSolve(b []Coord3D) []Coord3D {
 	}
 
 	b = a.Squeeze(b)
-	for i, c := range a.SqueezeDelta() {
-		b[i] = b[i].Add(c)
-	}
+	BatchAdd(b, b, a.SqueezeDelta())
 
 	if a.chol == nil {
 		a.chol = numerical.NewSparseCholesky(a.squeezedMatrix())
@@ -0,0 +1,205 @@
+package model3d
+
+import (
+	"context"
+	"math"
+)
+
+// HierarchicalARAPNeighbors is the number of coarse
+// vertices used to transfer a deformation to each vertex
+// of the fine mesh in HierarchicalARAP.
+const HierarchicalARAPNeighbors = 4
+
+// HierarchicalARAP accelerates ARAP deformation of large
+// meshes by running ARAP on a coarse version of the mesh,
+// and then transferring the result to the full mesh.
+//
+// Each fine vertex follows a blend of the rigid motions
+// (translation and best-fit rotation) of its nearest
+// coarse vertices, so the cost of each deformation after
+// the coarse solve is linear in the size of the fine mesh.
+// This makes it possible to deform meshes with hundreds
+// of thousands of vertices at interactive rates.
+type HierarchicalARAP struct {
+	coarse *ARAP
+
+	coordToIdx map[Coord3D]int
+	coords     []Coord3D
+	triangles  [][3]int
+
+	coarseNeighbors [][]int
+	coarseWeights   [][]float64
+}
+
+// NewHierarchicalARAP creates a HierarchicalARAP for the
+// fine mesh m, using the coarse mesh to solve for the
+// deformation.
+//
+// The coarse mesh should approximate the shape of m, for
+// example by decimating m or by re-running MarchingCubes
+// at a lower resolution.
+// Since ARAP uses cotangent weights by default, the
+// coarse mesh should not contain sliver triangles.
+func NewHierarchicalARAP(m, coarse *Mesh) *HierarchicalARAP {
+	return NewHierarchicalARAPWeighted(m, coarse, ARAPWeightingCotangent,
+		ARAPWeightingCotangent)
+}
+
+// NewHierarchicalARAPWeighted is like NewHierarchicalARAP,
+// but uses the given ARAP weighting schemes for the coarse
+// mesh, as in NewARAPWeighted.
+func NewHierarchicalARAPWeighted(m, coarse *Mesh, linear,
+	rotation ARAPWeightingScheme) *HierarchicalARAP {
+	coarseARAP := NewARAPWeighted(coarse, linear, rotation)
+	coords := m.VertexSlice()
+	res := &HierarchicalARAP{
+		coarse:          coarseARAP,
+		coordToIdx:      make(map[Coord3D]int, len(coords)),
+		coords:          coords,
+		coarseNeighbors: make([][]int, len(coords)),
+		coarseWeights:   make([][]float64, len(coords)),
+	}
+	for i, c := range coords {
+		res.coordToIdx[c] = i
+	}
+	m.Iterate(func(t *Triangle) {
+		res.triangles = append(res.triangles, [3]int{
+			res.coordToIdx[t[0]],
+			res.coordToIdx[t[1]],
+			res.coordToIdx[t[2]],
+		})
+	})
+
+	tree := NewCoordTree(coarseARAP.coords)
+	for i, c := range coords {
+		if idx, ok := coarseARAP.coordToIdx[c]; ok {
+			res.coarseNeighbors[i] = []int{idx}
+			res.coarseWeights[i] = []float64{1}
+			continue
+		}
+		neighbors := tree.KNN(HierarchicalARAPNeighbors, c)
+		indices := make([]int, len(neighbors))
+		weights := make([]float64, len(neighbors))
+		var totalWeight float64
+		for j, n := range neighbors {
+			indices[j] = coarseARAP.coordToIdx[n]
+			weights[j] = 1 / math.Max(n.SquaredDist(c), 1e-16)
+			totalWeight += weights[j]
+		}
+		for j := range weights {
+			weights[j] /= totalWeight
+		}
+		res.coarseNeighbors[i] = indices
+		res.coarseWeights[i] = weights
+	}
+	return res
+}
+
+// Coarse gets the ARAP instance used for the coarse mesh.
+//
+// This can be used to configure the tolerance and
+// iteration limits of the coarse solver.
+func (h *HierarchicalARAP) Coarse() *ARAP {
+	return h.coarse
+}
+
+// Deform creates a new mesh by enforcing constraints on
+// some points of the fine mesh.
+//
+// Constraints are transferred to the nearest coarse
+// vertices for the solve, and are then enforced exactly
+// on the fine mesh.
+func (h *HierarchicalARAP) Deform(constraints ARAPConstraints) *Mesh {
+	res, _ := h.DeformContext(context.Background(), constraints)
+	return res
+}
+
+// DeformContext is like Deform, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+func (h *HierarchicalARAP) DeformContext(ctx context.Context,
+	constraints ARAPConstraints) (*Mesh, error) {
+	l := newARAPOperator(h.coarse, h.coarseConstraints(constraints))
+	coarseOut, err := h.coarse.deformMapContext(ctx, l, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.prolongate(coarseOut, constraints), nil
+}
+
+// SeqDeformer creates a function that deforms the mesh,
+// potentially caching computations across calls.
+//
+// See ARAP.SeqDeformer() for details.
+func (h *HierarchicalARAP) SeqDeformer(coldStart bool) func(ARAPConstraints) *Mesh {
+	var current []Coord3D
+	var l *arapOperator
+	return func(constraints ARAPConstraints) *Mesh {
+		coarseConstraints := h.coarseConstraints(constraints)
+		if l == nil {
+			l = newARAPOperator(h.coarse, coarseConstraints)
+		} else {
+			l.Update(coarseConstraints)
+		}
+		if coldStart {
+			current = h.coarse.deformMap(l, nil)
+		} else {
+			current = h.coarse.deformMap(l, current)
+		}
+		return h.prolongate(current, constraints)
+	}
+}
+
+// coarseConstraints moves each coarse vertex nearest to a
+// fine constraint by the average offset of the fine
+// constraints nearest to it.
+func (h *HierarchicalARAP) coarseConstraints(constraints ARAPConstraints) map[int]Coord3D {
+	sums := map[int]Coord3D{}
+	counts := map[int]float64{}
+	for in, out := range constraints {
+		idx, ok := h.coordToIdx[in]
+		if !ok {
+			panic("constraint was not in the original mesh")
+		}
+		neighbors := h.coarseNeighbors[idx]
+		weights := h.coarseWeights[idx]
+		nearest := 0
+		for i, w := range weights {
+			if w > weights[nearest] {
+				nearest = i
+			}
+		}
+		coarseIdx := neighbors[nearest]
+		sums[coarseIdx] = sums[coarseIdx].Add(out.Sub(in))
+		counts[coarseIdx]++
+	}
+	res := make(map[int]Coord3D, len(sums))
+	for idx, sum := range sums {
+		res[idx] = h.coarse.coords[idx].Add(sum.Scale(1 / counts[idx]))
+	}
+	return res
+}
+
+func (h *HierarchicalARAP) prolongate(coarseOut []Coord3D,
+	constraints ARAPConstraints) *Mesh {
+	rotations := h.coarse.rotations(coarseOut)
+	out := make([]Coord3D, len(h.coords))
+	for i, c := range h.coords {
+		if target, ok := constraints[c]; ok {
+			out[i] = target
+			continue
+		}
+		var sum Coord3D
+		for j, idx := range h.coarseNeighbors[i] {
+			offset := c.Sub(h.coarse.coords[idx])
+			p := coarseOut[idx].Add(rotations[idx].MulColumn(offset))
+			sum = sum.Add(p.Scale(h.coarseWeights[i][j]))
+		}
+		out[i] = sum
+	}
+
+	m := NewMesh()
+	for _, t := range h.triangles {
+		m.Add(&Triangle{out[t[0]], out[t[1]], out[t[2]]})
+	}
+	return m
+}
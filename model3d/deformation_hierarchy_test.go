@@ -0,0 +1,53 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHierarchicalARAP(t *testing.T) {
+	solid := &Cylinder{P1: X(0), P2: X(4), Radius: 0.4}
+	mesh := MarchingCubesSearch(solid, 0.05, 8)
+	coarse := MarchingCubesSearch(solid, 0.15, 8)
+
+	rotation := Rotation(Y(1), -math.Pi/4)
+	makeConstraints := func(m *Mesh) ARAPConstraints {
+		res := ARAPConstraints{}
+		for _, c := range m.VertexSlice() {
+			if c.X < 0.3 {
+				res[c] = c
+			} else if c.X > 3.7 {
+				res[c] = rotation.Apply(c.Sub(X(3.7))).Add(X(3.7))
+			}
+		}
+		return res
+	}
+	constraints := makeConstraints(mesh)
+
+	h := NewHierarchicalARAP(mesh, coarse)
+	deformed := h.Deform(constraints)
+	MustValidateMesh(t, deformed, true)
+	for in, out := range constraints {
+		if deformed.Find(out) == nil {
+			t.Fatalf("constraint %v -> %v was not satisfied", in, out)
+		}
+	}
+
+	if v1, v2 := deformed.Volume(), mesh.Volume(); math.Abs(v1-v2) > 0.05*v2 {
+		t.Errorf("expected volume %f but got %f", v2, v1)
+	}
+	expected := NewARAP(coarse).Deform(makeConstraints(coarse))
+	if max1, max2 := deformed.Max(), expected.Max(); max1.Dist(max2) > 0.1 {
+		t.Errorf("expected max %v but got %v", max2, max1)
+	}
+	if min1, min2 := deformed.Min(), expected.Min(); min1.Dist(min2) > 0.1 {
+		t.Errorf("expected min %v but got %v", min2, min1)
+	}
+
+	seq := h.SeqDeformer(false)
+	for i := 0; i < 2; i++ {
+		if actual := seq(constraints); math.Abs(actual.Volume()-deformed.Volume()) > 1e-2 {
+			t.Errorf("unexpected volume from SeqDeformer: %f", actual.Volume())
+		}
+	}
+}
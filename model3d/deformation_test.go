@@ -0,0 +1,39 @@
+package model3d
+
+import (
+	"context"
+	"testing"
+)
+
+func TestARAPDeformProgress(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 5)
+	arap := NewARAP(mesh)
+	constraints := ARAPConstraints{}
+	constraints.AddAround(arap, XYZ(0, 0, 1), 0.3, XYZ(0, 0, 1.5))
+	constraints.AddAround(arap, XYZ(0, 0, -1), 0.3, XYZ(0, 0, -1))
+
+	var updates []float64
+	p := ProgressFunc(func(frac float64) {
+		updates = append(updates, frac)
+	})
+	deformed, err := arap.DeformProgress(context.Background(), constraints, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := arap.Deform(constraints)
+	if deformed.NumTriangles() != expected.NumTriangles() {
+		t.Errorf("expected %d triangles but got %d", expected.NumTriangles(),
+			deformed.NumTriangles())
+	}
+	if len(updates) < 2 {
+		t.Fatalf("too few updates: %v", updates)
+	}
+	for i := 1; i < len(updates); i++ {
+		if updates[i] < updates[i-1] {
+			t.Fatalf("non-monotonic updates: %v", updates)
+		}
+	}
+	if updates[len(updates)-1] != 1 {
+		t.Errorf("final update should be 1 but got %f", updates[len(updates)-1])
+	}
+}
@@ -105,6 +105,8 @@
 // programmatically:
 //
 //   - Decimator - polygon reduction.
+//   - QEMSimplifier - polygon reduction to a target
+//     triangle count.
 //   - MeshSmoother - smoothing for reducing sharp
 //     edges or corners.
 //   - Subdivider - edge-based sub-division to add
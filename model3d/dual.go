@@ -0,0 +1,306 @@
+package model3d
+
+import "math"
+
+// A Dual is a dual number which tracks a value along with
+// its exact gradient with respect to a point in 3D space.
+//
+// Writing an SDF formula in terms of Dual and DualCoord3D
+// makes it possible to compute exact normals with
+// automatic differentiation, rather than with finite
+// differences or search.
+// See DualSDF for a convenient way to do this.
+type Dual struct {
+	Value float64
+	Grad  Coord3D
+}
+
+// DualConst creates a Dual with a zero gradient.
+func DualConst(x float64) Dual {
+	return Dual{Value: x}
+}
+
+// Add computes d+d1.
+func (d Dual) Add(d1 Dual) Dual {
+	return Dual{Value: d.Value + d1.Value, Grad: d.Grad.Add(d1.Grad)}
+}
+
+// AddScalar computes d+s.
+func (d Dual) AddScalar(s float64) Dual {
+	return Dual{Value: d.Value + s, Grad: d.Grad}
+}
+
+// Sub computes d-d1.
+func (d Dual) Sub(d1 Dual) Dual {
+	return Dual{Value: d.Value - d1.Value, Grad: d.Grad.Sub(d1.Grad)}
+}
+
+// Neg computes -d.
+func (d Dual) Neg() Dual {
+	return d.Scale(-1)
+}
+
+// Scale computes d*s.
+func (d Dual) Scale(s float64) Dual {
+	return Dual{Value: d.Value * s, Grad: d.Grad.Scale(s)}
+}
+
+// Mul computes d*d1.
+func (d Dual) Mul(d1 Dual) Dual {
+	return Dual{
+		Value: d.Value * d1.Value,
+		Grad:  d.Grad.Scale(d1.Value).Add(d1.Grad.Scale(d.Value)),
+	}
+}
+
+// Div computes d/d1.
+func (d Dual) Div(d1 Dual) Dual {
+	return Dual{
+		Value: d.Value / d1.Value,
+		Grad:  d.Grad.Scale(d1.Value).Sub(d1.Grad.Scale(d.Value)).Scale(1 / (d1.Value * d1.Value)),
+	}
+}
+
+// Abs computes |d|.
+func (d Dual) Abs() Dual {
+	if d.Value < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Sqrt computes the square root of d.
+func (d Dual) Sqrt() Dual {
+	v := math.Sqrt(d.Value)
+	return Dual{Value: v, Grad: d.Grad.Scale(0.5 / v)}
+}
+
+// Pow computes d^p.
+func (d Dual) Pow(p float64) Dual {
+	return Dual{
+		Value: math.Pow(d.Value, p),
+		Grad:  d.Grad.Scale(p * math.Pow(d.Value, p-1)),
+	}
+}
+
+// Exp computes e^d.
+func (d Dual) Exp() Dual {
+	v := math.Exp(d.Value)
+	return Dual{Value: v, Grad: d.Grad.Scale(v)}
+}
+
+// Log computes the natural logarithm of d.
+func (d Dual) Log() Dual {
+	return Dual{Value: math.Log(d.Value), Grad: d.Grad.Scale(1 / d.Value)}
+}
+
+// Sin computes sin(d).
+func (d Dual) Sin() Dual {
+	return Dual{Value: math.Sin(d.Value), Grad: d.Grad.Scale(math.Cos(d.Value))}
+}
+
+// Cos computes cos(d).
+func (d Dual) Cos() Dual {
+	return Dual{Value: math.Cos(d.Value), Grad: d.Grad.Scale(-math.Sin(d.Value))}
+}
+
+// Atan2 computes atan2(d, x), treating d as the y value.
+func (d Dual) Atan2(x Dual) Dual {
+	denom := d.Value*d.Value + x.Value*x.Value
+	return Dual{
+		Value: math.Atan2(d.Value, x.Value),
+		Grad:  d.Grad.Scale(x.Value).Sub(x.Grad.Scale(d.Value)).Scale(1 / denom),
+	}
+}
+
+// Max computes the maximum of d and d1, using the gradient
+// of whichever is larger.
+func (d Dual) Max(d1 Dual) Dual {
+	if d1.Value > d.Value {
+		return d1
+	}
+	return d
+}
+
+// Min computes the minimum of d and d1, using the gradient
+// of whichever is smaller.
+func (d Dual) Min(d1 Dual) Dual {
+	if d1.Value < d.Value {
+		return d1
+	}
+	return d
+}
+
+// A DualCoord3D is a Coord3D made of Dual components.
+type DualCoord3D struct {
+	X Dual
+	Y Dual
+	Z Dual
+}
+
+// NewDualCoord3D creates a DualCoord3D for the point c,
+// seeding the gradients so that derivatives are computed
+// with respect to c.
+func NewDualCoord3D(c Coord3D) DualCoord3D {
+	return DualCoord3D{
+		X: Dual{Value: c.X, Grad: X(1)},
+		Y: Dual{Value: c.Y, Grad: Y(1)},
+		Z: Dual{Value: c.Z, Grad: Z(1)},
+	}
+}
+
+// DualCoord3DConst creates a DualCoord3D with zero
+// gradients, representing a constant.
+func DualCoord3DConst(c Coord3D) DualCoord3D {
+	return DualCoord3D{X: DualConst(c.X), Y: DualConst(c.Y), Z: DualConst(c.Z)}
+}
+
+// Value gets the Coord3D without gradient information.
+func (d DualCoord3D) Value() Coord3D {
+	return XYZ(d.X.Value, d.Y.Value, d.Z.Value)
+}
+
+// Add computes d+d1.
+func (d DualCoord3D) Add(d1 DualCoord3D) DualCoord3D {
+	return DualCoord3D{X: d.X.Add(d1.X), Y: d.Y.Add(d1.Y), Z: d.Z.Add(d1.Z)}
+}
+
+// AddCoord computes d+c for a constant c.
+func (d DualCoord3D) AddCoord(c Coord3D) DualCoord3D {
+	return DualCoord3D{X: d.X.AddScalar(c.X), Y: d.Y.AddScalar(c.Y), Z: d.Z.AddScalar(c.Z)}
+}
+
+// Sub computes d-d1.
+func (d DualCoord3D) Sub(d1 DualCoord3D) DualCoord3D {
+	return DualCoord3D{X: d.X.Sub(d1.X), Y: d.Y.Sub(d1.Y), Z: d.Z.Sub(d1.Z)}
+}
+
+// SubCoord computes d-c for a constant c.
+func (d DualCoord3D) SubCoord(c Coord3D) DualCoord3D {
+	return d.AddCoord(c.Scale(-1))
+}
+
+// Scale computes d*s.
+func (d DualCoord3D) Scale(s float64) DualCoord3D {
+	return DualCoord3D{X: d.X.Scale(s), Y: d.Y.Scale(s), Z: d.Z.Scale(s)}
+}
+
+// Mul multiplies every component of d by the scalar s.
+func (d DualCoord3D) Mul(s Dual) DualCoord3D {
+	return DualCoord3D{X: d.X.Mul(s), Y: d.Y.Mul(s), Z: d.Z.Mul(s)}
+}
+
+// Dot computes the dot product of d and d1.
+func (d DualCoord3D) Dot(d1 DualCoord3D) Dual {
+	return d.X.Mul(d1.X).Add(d.Y.Mul(d1.Y)).Add(d.Z.Mul(d1.Z))
+}
+
+// Cross computes the cross product of d and d1.
+func (d DualCoord3D) Cross(d1 DualCoord3D) DualCoord3D {
+	return DualCoord3D{
+		X: d.Y.Mul(d1.Z).Sub(d.Z.Mul(d1.Y)),
+		Y: d.Z.Mul(d1.X).Sub(d.X.Mul(d1.Z)),
+		Z: d.X.Mul(d1.Y).Sub(d.Y.Mul(d1.X)),
+	}
+}
+
+// Norm computes the vector length of d.
+func (d DualCoord3D) Norm() Dual {
+	return d.Dot(d).Sqrt()
+}
+
+// Abs computes the component-wise absolute value of d.
+func (d DualCoord3D) Abs() DualCoord3D {
+	return DualCoord3D{X: d.X.Abs(), Y: d.Y.Abs(), Z: d.Z.Abs()}
+}
+
+// Max computes the component-wise maximum of d and d1.
+func (d DualCoord3D) Max(d1 DualCoord3D) DualCoord3D {
+	return DualCoord3D{X: d.X.Max(d1.X), Y: d.Y.Max(d1.Y), Z: d.Z.Max(d1.Z)}
+}
+
+// Min computes the component-wise minimum of d and d1.
+func (d DualCoord3D) Min(d1 DualCoord3D) DualCoord3D {
+	return DualCoord3D{X: d.X.Min(d1.X), Y: d.Y.Min(d1.Y), Z: d.Z.Min(d1.Z)}
+}
+
+// MaxCoord gets the largest component of d.
+func (d DualCoord3D) MaxCoord() Dual {
+	return d.X.Max(d.Y).Max(d.Z)
+}
+
+// MinCoord gets the smallest component of d.
+func (d DualCoord3D) MinCoord() Dual {
+	return d.X.Min(d.Y).Min(d.Z)
+}
+
+// DualGradient evaluates f at c and computes its exact
+// gradient with respect to c.
+func DualGradient(f func(c DualCoord3D) Dual, c Coord3D) (float64, Coord3D) {
+	res := f(NewDualCoord3D(c))
+	return res.Value, res.Grad
+}
+
+// A DualSDF is a NormalSDF defined by a formula written in
+// terms of dual numbers, so that normals are computed
+// exactly with automatic differentiation.
+//
+// A DualSDF can be passed to SmoothJoinV2, or used as the
+// NormalSDF of a SolidSurfaceEstimator to give exact
+// hermite data to DualContouring.
+type DualSDF struct {
+	min Coord3D
+	max Coord3D
+	f   func(c DualCoord3D) Dual
+}
+
+// NewDualSDF creates a DualSDF from a function.
+//
+// As with all SDFs in this package, f should be positive
+// inside the surface and negative outside.
+//
+// If the bounds are invalid, NewDualSDF() will panic().
+func NewDualSDF(min, max Coord3D, f func(c DualCoord3D) Dual) *DualSDF {
+	if !BoundsValid(NewRect(min, max)) {
+		panic("invalid bounds")
+	}
+	return &DualSDF{min: min, max: max, f: f}
+}
+
+// Min gets the minimum of the bounding box.
+func (d *DualSDF) Min() Coord3D {
+	return d.min
+}
+
+// Max gets the maximum of the bounding box.
+func (d *DualSDF) Max() Coord3D {
+	return d.max
+}
+
+// SDF evaluates the SDF at c.
+func (d *DualSDF) SDF(c Coord3D) float64 {
+	return d.f(DualCoord3DConst(c)).Value
+}
+
+// NormalSDF evaluates the SDF at c and computes the
+// outward-facing normal from the gradient.
+func (d *DualSDF) NormalSDF(c Coord3D) (Coord3D, float64) {
+	value, grad := DualGradient(d.f, c)
+	return grad.Scale(-1).Normalize(), value
+}
+
+// Solid creates a Solid which contains the points where
+// the SDF is positive.
+func (d *DualSDF) Solid() Solid {
+	return SDFToSolid(d, 0)
+}
+
+// SurfaceEstimator creates a SolidSurfaceEstimator which
+// uses the exact normals of d, for use with
+// DualContouring.
+func (d *DualSDF) SurfaceEstimator() SolidSurfaceEstimator {
+	return SolidSurfaceEstimator{
+		Solid:     d.Solid(),
+		NormalSDF: d,
+	}
+}
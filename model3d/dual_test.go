@@ -0,0 +1,79 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDualGradient(t *testing.T) {
+	f := func(c DualCoord3D) Dual {
+		r := c.Norm()
+		angle := c.Y.Atan2(c.X)
+		return r.Mul(angle.Sin()).Add(c.Z.Exp().Div(r.AddScalar(1))).
+			Sub(c.Abs().MaxCoord().Pow(1.5)).Add(c.Cross(DualCoord3DConst(X(1))).Dot(c).Cos())
+	}
+	floatF := func(c Coord3D) float64 {
+		return f(DualCoord3DConst(c)).Value
+	}
+	for i := 0; i < 100; i++ {
+		c := NewCoord3DRandNorm()
+		value, grad := DualGradient(f, c)
+		if math.Abs(value-floatF(c)) > 1e-8 {
+			t.Fatalf("unexpected value %f (expected %f)", value, floatF(c))
+		}
+		const eps = 1e-5
+		var approx Coord3D
+		for axis := 0; axis < 3; axis++ {
+			delta := Coord3D{}.Array()
+			delta[axis] = eps
+			d := NewCoord3DArray(delta)
+			partial := (floatF(c.Add(d)) - floatF(c.Sub(d))) / (2 * eps)
+			approx = approx.Add(d.Scale(partial / eps))
+		}
+		if grad.Dist(approx) > 1e-4*math.Max(1, approx.Norm()) {
+			t.Fatalf("expected gradient %v but got %v", approx, grad)
+		}
+	}
+}
+
+func TestDualSDF(t *testing.T) {
+	box := NewDualSDF(Ones(-1.1), Ones(1.1), func(c DualCoord3D) Dual {
+		q := c.Abs().SubCoord(Ones(1))
+		outside := q.Max(DualCoord3DConst(Coord3D{})).Norm()
+		inside := q.MaxCoord().Min(DualConst(0))
+		return outside.Add(inside).Neg()
+	})
+
+	t.Run("Normals", func(t *testing.T) {
+		rect := NewRect(Ones(-1), Ones(1))
+		for i := 0; i < 100; i++ {
+			c := NewCoord3DRandNorm()
+			expectedNormal, expectedSDF := rect.NormalSDF(c)
+			normal, sdf := box.NormalSDF(c)
+			if math.Abs(sdf-expectedSDF) > 1e-8 {
+				t.Fatalf("expected SDF %f but got %f", expectedSDF, sdf)
+			}
+			// Outside of the box, the nearest point may be on
+			// an edge where the normal is ambiguous.
+			if expectedSDF > 0 && normal.Dist(expectedNormal) > 1e-8 {
+				t.Fatalf("at %v: expected normal %v but got %v", c, expectedNormal, normal)
+			}
+		}
+	})
+
+	t.Run("DualContouring", func(t *testing.T) {
+		sphere := NewDualSDF(Ones(-1), Ones(1), func(c DualCoord3D) Dual {
+			return c.Norm().Neg().AddScalar(1)
+		})
+		dc := &DualContouring{
+			S:     sphere.SurfaceEstimator(),
+			Delta: 0.04,
+		}
+		mesh := dc.Mesh()
+		MustValidateMesh(t, mesh, false)
+		expected := 4.0 / 3.0 * math.Pi
+		if volume := mesh.Volume(); math.Abs(volume-expected) > 5e-2 {
+			t.Errorf("expected volume %f but got %f", expected, volume)
+		}
+	})
+}
@@ -9,11 +9,14 @@ import (
 	"image/png"
 	"io"
 	"math"
+	"os"
+	"sort"
 	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/unixpickle/essentials"
 	"github.com/unixpickle/model3d/fileformats"
+	"github.com/unixpickle/model3d/model2d"
 	"github.com/unixpickle/model3d/numerical"
 )
 
@@ -458,3 +461,167 @@ func VertexColorsToTriangle(f func(c Coord3D) [3]float64) func(t *Triangle) [3]f
 		return sum
 	}
 }
+
+// EncodeGLB encodes a 3D model as a binary glTF 2.0 file.
+//
+// If uvMap is non-nil, it provides texture coordinates for
+// every triangle. If texture is also non-nil, it is stored
+// in the file as the base color texture of the material,
+// using the same UV conventions as BuildUVMapMaterialOBJ.
+//
+// An error is returned if the model cannot be encoded, for
+// example if uvMap is missing one of the triangles.
+func EncodeGLB(triangles []*Triangle, uvMap MeshUVMap, texture image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteGLTF(&buf, triangles, uvMap, texture); err != nil {
+		return nil, errors.Wrap(err, "encode GLB")
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteGLTF writes a 3D model as a binary glTF 2.0 (.glb)
+// file to w.
+//
+// See EncodeGLB for details on the arguments.
+func WriteGLTF(w io.Writer, triangles []*Triangle, uvMap MeshUVMap, texture image.Image) error {
+	mesh, err := BuildGLTFMesh(triangles, uvMap, texture)
+	if err == nil {
+		err = fileformats.WriteGLB(w, []*fileformats.GLTFMesh{mesh})
+	}
+	if err != nil {
+		return errors.Wrap(err, "write glTF")
+	}
+	return nil
+}
+
+// BuildGLTFMesh creates a glTF mesh from triangles, with
+// optional texture coordinates and texture image.
+//
+// Vertices are shared between triangles when they have
+// the same coordinates and texture coordinates.
+func BuildGLTFMesh(t []*Triangle, uvMap MeshUVMap,
+	texture image.Image) (*fileformats.GLTFMesh, error) {
+	type vertexKey struct {
+		Coord Coord3D
+		UV    model2d.Coord
+	}
+	res := &fileformats.GLTFMesh{BaseColor: [4]float32{1, 1, 1, 1}}
+	if uvMap != nil {
+		res.UVs = [][2]float32{}
+	} else {
+		res.BaseColor = [4]float32{0.8, 0.8, 0.8, 1}
+	}
+	vertexToIndex := map[vertexKey]uint32{}
+	for _, tri := range t {
+		var uvs [3]model2d.Coord
+		if uvMap != nil {
+			var ok bool
+			uvs, ok = uvMap[tri]
+			if !ok {
+				return nil, errors.New("triangle is missing from UV map")
+			}
+		}
+		for i, c := range tri {
+			key := vertexKey{Coord: c, UV: uvs[i]}
+			idx, ok := vertexToIndex[key]
+			if !ok {
+				idx = uint32(len(res.Positions))
+				vertexToIndex[key] = idx
+				res.Positions = append(res.Positions, castVector32(c))
+				if uvMap != nil {
+					// glTF textures have their origin at the
+					// top-left corner.
+					res.UVs = append(res.UVs, [2]float32{float32(key.UV.X), float32(1 - key.UV.Y)})
+				}
+			}
+			res.Indices = append(res.Indices, idx)
+		}
+	}
+	if texture != nil {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, texture); err != nil {
+			return nil, err
+		}
+		res.TexturePNG = buf.Bytes()
+	}
+	return res, nil
+}
+
+// Save3MF saves named meshes to a 3MF file, which is the
+// preferred format of many slicers for multi-part and
+// multi-color prints.
+//
+// If colorFunc is non-nil, it maps every triangle of every
+// mesh to a real-valued RGB color.
+func Save3MF(path string, meshes map[string]*Mesh, colorFunc func(t *Triangle) [3]float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "save 3MF")
+	}
+	defer f.Close()
+	bw := bufio.NewWriter(f)
+	if err := Write3MF(bw, meshes, colorFunc); err != nil {
+		return errors.Wrap(err, "save 3MF")
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "save 3MF")
+	}
+	return nil
+}
+
+// Write3MF writes named meshes as a 3MF file to w.
+//
+// The objects are sorted by name, and the units are
+// millimeters.
+//
+// See Save3MF for details on colorFunc.
+func Write3MF(w io.Writer, meshes map[string]*Mesh, colorFunc func(t *Triangle) [3]float64) error {
+	if err := write3MF(w, meshes, colorFunc); err != nil {
+		return errors.Wrap(err, "write 3MF")
+	}
+	return nil
+}
+
+func write3MF(w io.Writer, meshes map[string]*Mesh, colorFunc func(t *Triangle) [3]float64) error {
+	writer, err := fileformats.NewThreeMFWriter(w, "")
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(meshes))
+	for name := range meshes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tris := meshes[name].TriangleSlice()
+		coordToIdx := NewCoordToNumber[int]()
+		var vertices [][3]float64
+		indices := make([][3]int, len(tris))
+		for i, t := range tris {
+			for j, c := range t {
+				idx, ok := coordToIdx.Load(c)
+				if !ok {
+					idx = len(vertices)
+					coordToIdx.Store(c, idx)
+					vertices = append(vertices, c.Array())
+				}
+				indices[i][j] = idx
+			}
+		}
+		var colors [][3]uint8
+		if colorFunc != nil {
+			colors = make([][3]uint8, len(tris))
+			essentials.ConcurrentMap(0, len(tris), func(i int) {
+				c := colorFunc(tris[i])
+				for j, x := range c {
+					colors[i][j] = uint8(math.Round(255 * math.Max(0, math.Min(1, x))))
+				}
+			})
+		}
+		if err := writer.AddObject(name, vertices, indices, colors); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
@@ -0,0 +1,124 @@
+package model3d
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+func TestBuildGLTFMesh(t *testing.T) {
+	mesh := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1))
+	tris := mesh.TriangleSlice()
+
+	res, err := BuildGLTFMesh(tris, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Positions) != 8 || len(res.Indices) != 3*len(tris) || res.UVs != nil {
+		t.Fatalf("unexpected mesh: %d positions, %d indices", len(res.Positions),
+			len(res.Indices))
+	}
+
+	// Give every triangle its own texture coordinates, so
+	// that no vertices can be shared.
+	uvMap := MeshUVMap{}
+	for i, tri := range tris {
+		y := float64(i) / float64(len(tris))
+		uvMap[tri] = [3]model2d.Coord{model2d.XY(0, y), model2d.XY(1, y), model2d.XY(0.5, y)}
+	}
+	res, err = BuildGLTFMesh(tris, uvMap, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Positions) != 3*len(tris) || len(res.UVs) != len(res.Positions) {
+		t.Fatalf("unexpected number of vertices: %d", len(res.Positions))
+	}
+	if res.TexturePNG == nil {
+		t.Fatal("missing texture")
+	}
+	for i, tri := range tris {
+		for j := 0; j < 3; j++ {
+			idx := res.Indices[i*3+j]
+			expected := uvMap[tri][j]
+			actual := res.UVs[idx]
+			if float64(actual[0]) != expected.X || float64(actual[1]) != float64(float32(1-expected.Y)) {
+				t.Fatalf("unexpected UV %v for %v", actual, expected)
+			}
+			if res.Positions[idx] != castVector32(tri[j]) {
+				t.Fatal("unexpected position")
+			}
+		}
+	}
+
+	delete(uvMap, tris[0])
+	if _, err := BuildGLTFMesh(tris, uvMap, nil); err == nil {
+		t.Error("expected error for missing UVs")
+	}
+	if data, err := EncodeGLB(tris, uvMap, nil); err == nil || data != nil {
+		t.Error("expected EncodeGLB to fail for missing UVs")
+	}
+	if data, err := mesh.EncodeGLB(nil, nil); err != nil {
+		t.Fatal(err)
+	} else if len(data) == 0 || string(data[:4]) != "glTF" {
+		t.Error("unexpected GLB data")
+	}
+}
+
+func TestWrite3MF(t *testing.T) {
+	meshes := map[string]*Mesh{
+		"sphere": NewMeshIcosphere(Origin, 1, 2),
+		"box":    NewMeshRect(XYZ(2, 0, 0), XYZ(3, 1, 1)),
+	}
+	var buf bytes.Buffer
+	err := Write3MF(&buf, meshes, func(t *Triangle) [3]float64 {
+		if t.Normal().Z > 0 {
+			return [3]float64{1, 0, 0}
+		}
+		return [3]float64{0, 0, 1}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model string
+	for _, f := range reader.File {
+		if f.Name == "3D/3dmodel.model" {
+			r, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var data bytes.Buffer
+			_, err = data.ReadFrom(r)
+			r.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			model = data.String()
+		}
+	}
+	if model == "" {
+		t.Fatal("missing model file")
+	}
+	if idx1, idx2 := strings.Index(model, `name="box"`),
+		strings.Index(model, `name="sphere"`); idx1 == -1 || idx2 == -1 || idx1 > idx2 {
+		t.Error("objects are missing or out of order")
+	}
+	numTris := len(meshes["sphere"].TriangleSlice()) + len(meshes["box"].TriangleSlice())
+	if count := strings.Count(model, "<triangle "); count != numTris {
+		t.Errorf("expected %d triangles but got %d", numTris, count)
+	}
+	numVerts := len(meshes["sphere"].VertexSlice()) + len(meshes["box"].VertexSlice())
+	if count := strings.Count(model, "<vertex "); count != numVerts {
+		t.Errorf("expected %d vertices but got %d", numVerts, count)
+	}
+	if count := strings.Count(model, `displaycolor="#FF0000"`); count != 2 {
+		t.Errorf("expected one red material per object but got %d", count)
+	}
+}
@@ -0,0 +1,154 @@
+package model3d
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+// HeightmapSolid creates a solid from a grayscale image,
+// where the brightness of each pixel determines the height
+// of the solid above the plane Z=0.
+//
+// The image is stretched to fill bounds in the XY plane,
+// with the top of the image towards the maximum Y value.
+// Black pixels have zero height and white pixels have a
+// height of maxHeight.
+//
+// Heights are bilinearly interpolated between pixel
+// centers, so the surface is smooth rather than blocky.
+func HeightmapSolid(img image.Image, bounds *model2d.Rect, maxHeight float64) Solid {
+	h := newImageHeightmap(img, bounds, maxHeight)
+	return CheckedFuncSolid(
+		XYZ(bounds.MinVal.X, bounds.MinVal.Y, 0),
+		XYZ(bounds.MaxVal.X, bounds.MaxVal.Y, h.MaxHeight()),
+		func(c Coord3D) bool {
+			return c.Z <= h.HeightAt(c.XY())
+		},
+	)
+}
+
+// HeightmapMesh creates a closed terrain mesh from a
+// grayscale image, approximating HeightmapSolid.
+//
+// The top surface has one vertex at every corner of every
+// pixel, and the mesh is closed by vertical sides and a
+// flat base at Z=0.
+//
+// To avoid degenerate geometry, the surface is kept a tiny
+// distance above the base, even for black pixels.
+func HeightmapMesh(img image.Image, bounds *model2d.Rect, maxHeight float64) *Mesh {
+	h := newImageHeightmap(img, bounds, maxHeight)
+	minHeight := maxHeight * 1e-5
+	top := func(i, j int) Coord3D {
+		xy := h.Corner(i, j)
+		return XYZ(xy.X, xy.Y, math.Max(minHeight, h.HeightAt(xy)))
+	}
+	bottom := func(i, j int) Coord3D {
+		xy := h.Corner(i, j)
+		return XY(xy.X, xy.Y)
+	}
+
+	m := NewMesh()
+	for i := 0; i < h.Width; i++ {
+		for j := 0; j < h.Height; j++ {
+			m.AddQuad(top(i, j), top(i+1, j), top(i+1, j+1), top(i, j+1))
+			m.AddQuad(bottom(i, j+1), bottom(i+1, j+1), bottom(i+1, j), bottom(i, j))
+		}
+	}
+	for i := 0; i < h.Width; i++ {
+		m.AddQuad(bottom(i, 0), bottom(i+1, 0), top(i+1, 0), top(i, 0))
+		m.AddQuad(bottom(i+1, h.Height), bottom(i, h.Height), top(i, h.Height),
+			top(i+1, h.Height))
+	}
+	for j := 0; j < h.Height; j++ {
+		m.AddQuad(bottom(0, j+1), bottom(0, j), top(0, j), top(0, j+1))
+		m.AddQuad(bottom(h.Width, j), bottom(h.Width, j+1), top(h.Width, j+1),
+			top(h.Width, j))
+	}
+	return m
+}
+
+type imageHeightmap struct {
+	Bounds *model2d.Rect
+	Width  int
+	Height int
+
+	// Heights stores the height of each pixel in row-major
+	// order, starting at the minimum Y value.
+	Heights []float64
+}
+
+func newImageHeightmap(img image.Image, bounds *model2d.Rect, maxHeight float64) *imageHeightmap {
+	imgBounds := img.Bounds()
+	w, h := imgBounds.Dx(), imgBounds.Dy()
+	if w == 0 || h == 0 {
+		panic("image must not be empty")
+	}
+	heights := make([]float64, 0, w*h)
+	for y := imgBounds.Max.Y - 1; y >= imgBounds.Min.Y; y-- {
+		for x := imgBounds.Min.X; x < imgBounds.Max.X; x++ {
+			gray := color.Gray16Model.Convert(img.At(x, y)).(color.Gray16)
+			heights = append(heights, maxHeight*float64(gray.Y)/0xffff)
+		}
+	}
+	return &imageHeightmap{
+		Bounds:  bounds,
+		Width:   w,
+		Height:  h,
+		Heights: heights,
+	}
+}
+
+// MaxHeight gets the maximum height of any pixel.
+func (i *imageHeightmap) MaxHeight() float64 {
+	var res float64
+	for _, h := range i.Heights {
+		res = math.Max(res, h)
+	}
+	return res
+}
+
+// Corner gets the coordinate of the bottom-left corner of
+// the pixel at the given column and row.
+func (i *imageHeightmap) Corner(x, y int) model2d.Coord {
+	size := i.Bounds.MaxVal.Sub(i.Bounds.MinVal)
+	return i.Bounds.MinVal.Add(model2d.XY(
+		size.X*float64(x)/float64(i.Width),
+		size.Y*float64(y)/float64(i.Height),
+	))
+}
+
+// HeightAt bilinearly interpolates the height between the
+// centers of the nearest pixels.
+func (i *imageHeightmap) HeightAt(c model2d.Coord) float64 {
+	if !i.Bounds.Contains(c) {
+		return math.Inf(-1)
+	}
+	size := i.Bounds.MaxVal.Sub(i.Bounds.MinVal)
+	rel := c.Sub(i.Bounds.MinVal).Div(size)
+	x := rel.X*float64(i.Width) - 0.5
+	y := rel.Y*float64(i.Height) - 0.5
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+
+	get := func(x, y int) float64 {
+		x = clampInt(x, 0, i.Width-1)
+		y = clampInt(y, 0, i.Height-1)
+		return i.Heights[y*i.Width+x]
+	}
+	ix, iy := int(x0), int(y0)
+	return (1-fy)*((1-fx)*get(ix, iy)+fx*get(ix+1, iy)) +
+		fy*((1-fx)*get(ix, iy+1)+fx*get(ix+1, iy+1))
+}
+
+func clampInt(x, min, max int) int {
+	if x < min {
+		return min
+	} else if x > max {
+		return max
+	}
+	return x
+}
@@ -0,0 +1,62 @@
+package model3d
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+func TestHeightmap(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(50 + x*40 + y*20)})
+		}
+	}
+	bounds := model2d.NewRect(model2d.XY(1, 2), model2d.XY(5, 5))
+	solid := HeightmapSolid(img, bounds, 2)
+
+	pixelHeight := func(x, y int) float64 {
+		return 2 * float64(img.GrayAt(x, y).Y) / 255
+	}
+	if max := solid.Max(); math.Abs(max.Z-pixelHeight(3, 2)) > 1e-8 {
+		t.Errorf("expected max Z %f but got %f", pixelHeight(3, 2), max.Z)
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 4; x++ {
+			// The top row of the image is at the max Y value.
+			center := model2d.XY(1.5+float64(x), 4.5-float64(y))
+			h := pixelHeight(x, y)
+			if !solid.Contains(XYZ(center.X, center.Y, h-1e-5)) {
+				t.Errorf("pixel (%d, %d) should be filled below %f", x, y, h)
+			}
+			if solid.Contains(XYZ(center.X, center.Y, h+1e-5)) {
+				t.Errorf("pixel (%d, %d) should be empty above %f", x, y, h)
+			}
+		}
+	}
+
+	// Heights are interpolated between pixel centers.
+	mid := (pixelHeight(0, 0) + pixelHeight(1, 0)) / 2
+	if !solid.Contains(XYZ(2, 4.5, mid-1e-5)) || solid.Contains(XYZ(2, 4.5, mid+1e-5)) {
+		t.Error("unexpected interpolated height")
+	}
+
+	mesh := HeightmapMesh(img, bounds, 2)
+	MustValidateMesh(t, mesh, true)
+	if n := len(mesh.TriangleSlice()); n != 4*3*4+2*(4+3)*2 {
+		t.Errorf("unexpected number of triangles: %d", n)
+	}
+	if min, max := mesh.Min(), mesh.Max(); min != XYZ(1, 2, 0) || max.XY() != bounds.MaxVal {
+		t.Errorf("unexpected bounds %v-%v", min, max)
+	}
+	heightmap := newImageHeightmap(img, bounds, 2)
+	mesh.IterateVertices(func(c Coord3D) {
+		if expected := heightmap.HeightAt(c.XY()); c.Z > 0 && math.Abs(c.Z-expected) > 1e-8 {
+			t.Errorf("vertex %v does not match solid height %f", c, expected)
+		}
+	})
+}
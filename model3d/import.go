@@ -3,9 +3,14 @@ package model3d
 import (
 	"bufio"
 	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/unixpickle/model3d/fileformats"
+	"github.com/unixpickle/model3d/model2d"
 )
 
 // ReadSTL decodes a file in the STL file format.
@@ -61,3 +66,302 @@ func ReadOFF(r io.Reader) ([]*Triangle, error) {
 	}
 	return triangles, nil
 }
+
+// An OBJAsset is a mesh decoded from a Wavefront obj file,
+// along with its texture coordinates and materials.
+//
+// The per-triangle maps are keyed by the triangles of
+// Mesh, so they remain valid if triangles are removed
+// from the mesh or if new triangles are added to the maps.
+type OBJAsset struct {
+	Mesh *Mesh
+
+	// UVMap stores texture coordinates for every triangle
+	// which specified them in the file.
+	UVMap MeshUVMap
+
+	// Normals stores vertex normals for every triangle
+	// which specified them in the file.
+	Normals map[*Triangle][3]Coord3D
+
+	// Materials stores the material name of every triangle
+	// which was preceded by a usemtl statement.
+	Materials map[*Triangle]string
+
+	// MaterialFiles lists the mtl files referenced by the
+	// obj file.
+	MaterialFiles []string
+
+	// MaterialTable maps material names to definitions from
+	// the mtl files. This is only populated by LoadOBJ, or
+	// by the caller.
+	MaterialTable map[string]*fileformats.MTLFileMaterial
+}
+
+// ReadOBJ decodes a Wavefront obj file.
+//
+// Polygonal faces are split into triangles, and the mtl
+// files are not loaded. See LoadOBJ to read materials.
+func ReadOBJ(r io.Reader) (*OBJAsset, error) {
+	obj, err := fileformats.ReadOBJFile(r)
+	if err != nil {
+		return nil, err
+	}
+	res := &OBJAsset{
+		Mesh:          NewMesh(),
+		UVMap:         MeshUVMap{},
+		Normals:       map[*Triangle][3]Coord3D{},
+		Materials:     map[*Triangle]string{},
+		MaterialFiles: obj.MaterialFiles,
+		MaterialTable: map[string]*fileformats.MTLFileMaterial{},
+	}
+	for _, group := range obj.FaceGroups {
+		for _, face := range group.Faces {
+			tri := &Triangle{}
+			var uvs [3]model2d.Coord
+			var normals [3]Coord3D
+			hasUVs, hasNormals := true, true
+			for i, v := range face {
+				tri[i] = NewCoord3DArray(obj.Vertices[v[0]-1])
+				if v[1] == 0 {
+					hasUVs = false
+				} else {
+					uvs[i] = model2d.NewCoordArray(obj.UVs[v[1]-1])
+				}
+				if v[2] == 0 {
+					hasNormals = false
+				} else {
+					normals[i] = NewCoord3DArray(obj.Normals[v[2]-1])
+				}
+			}
+			res.Mesh.Add(tri)
+			if hasUVs {
+				res.UVMap[tri] = uvs
+			}
+			if hasNormals {
+				res.Normals[tri] = normals
+			}
+			if group.Material != "" {
+				res.Materials[tri] = group.Material
+			}
+		}
+	}
+	return res, nil
+}
+
+// LoadOBJ reads a Wavefront obj file from a path, as well
+// as any mtl files it references.
+//
+// Material files are resolved relative to the directory of
+// the obj file, and missing material files are ignored.
+func LoadOBJ(path string) (*OBJAsset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "load OBJ")
+	}
+	defer f.Close()
+	res, err := ReadOBJ(bufio.NewReader(f))
+	if err != nil {
+		return nil, errors.Wrap(err, "load OBJ")
+	}
+	for _, name := range res.MaterialFiles {
+		mtlPath := name
+		if !filepath.IsAbs(mtlPath) {
+			mtlPath = filepath.Join(filepath.Dir(path), name)
+		}
+		mtl, err := loadMTL(mtlPath)
+		if os.IsNotExist(errors.Cause(err)) {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(err, "load OBJ")
+		}
+		for _, material := range mtl.Materials {
+			res.MaterialTable[material.Name] = material
+		}
+	}
+	return res, nil
+}
+
+func loadMTL(path string) (*fileformats.MTLFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return fileformats.ReadMTLFile(bufio.NewReader(f))
+}
+
+// Build encodes the asset as obj and mtl files.
+//
+// The obj file will reference materialFile, which should
+// be the name of the saved mtl file. The mtl file contains
+// every entry of MaterialTable.
+func (o *OBJAsset) Build(materialFile string) (*fileformats.OBJFile, *fileformats.MTLFile) {
+	obj := &fileformats.OBJFile{}
+	mtl := &fileformats.MTLFile{}
+	if len(o.MaterialTable) > 0 {
+		obj.MaterialFiles = []string{materialFile}
+		names := make([]string, 0, len(o.MaterialTable))
+		for name := range o.MaterialTable {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			mtl.Materials = append(mtl.Materials, o.MaterialTable[name])
+		}
+	}
+
+	coordToIndex := map[[3]float64]int{}
+	uvToIndex := map[[2]float64]int{}
+	normalToIndex := map[[3]float64]int{}
+	index3D := func(m map[[3]float64]int, list *[][3]float64, c [3]float64) int {
+		if idx, ok := m[c]; ok {
+			return idx
+		}
+		*list = append(*list, c)
+		m[c] = len(*list)
+		return len(*list)
+	}
+
+	groups := map[string]*fileformats.OBJFileFaceGroup{}
+	var groupNames []string
+	for _, t := range o.Mesh.TriangleSlice() {
+		material := o.Materials[t]
+		group, ok := groups[material]
+		if !ok {
+			group = &fileformats.OBJFileFaceGroup{Material: material}
+			groups[material] = group
+			groupNames = append(groupNames, material)
+		}
+		uvs, hasUVs := o.UVMap[t]
+		normals, hasNormals := o.Normals[t]
+		var face [3][3]int
+		for i, c := range t {
+			face[i][0] = index3D(coordToIndex, &obj.Vertices, c.Array())
+			if hasUVs {
+				uv := uvs[i].Array()
+				idx, ok := uvToIndex[uv]
+				if !ok {
+					obj.UVs = append(obj.UVs, uv)
+					idx = len(obj.UVs)
+					uvToIndex[uv] = idx
+				}
+				face[i][1] = idx
+			}
+			if hasNormals {
+				face[i][2] = index3D(normalToIndex, &obj.Normals, normals[i].Array())
+			}
+		}
+		group.Faces = append(group.Faces, face)
+	}
+	sort.Strings(groupNames)
+	for _, name := range groupNames {
+		obj.FaceGroups = append(obj.FaceGroups, groups[name])
+	}
+	return obj, mtl
+}
+
+// Save writes the asset to an obj file at path, and its
+// materials to an mtl file with the same name but a .mtl
+// extension.
+//
+// If MaterialTable is empty, no mtl file is written.
+func (o *OBJAsset) Save(path string) error {
+	mtlPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".mtl"
+	obj, mtl := o.Build(filepath.Base(mtlPath))
+	if err := saveWavefront(path, obj.Write); err != nil {
+		return errors.Wrap(err, "save OBJ")
+	}
+	if len(mtl.Materials) > 0 {
+		if err := saveWavefront(mtlPath, mtl.Write); err != nil {
+			return errors.Wrap(err, "save OBJ")
+		}
+	}
+	return nil
+}
+
+func saveWavefront(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return write(f)
+}
+
+// A PLYAsset is a mesh decoded from a PLY file, along with
+// its vertex attributes.
+type PLYAsset struct {
+	Mesh *Mesh
+
+	// Colors maps vertices to sRGB colors, or is nil if the
+	// file has no vertex colors.
+	Colors *CoordMap[[3]uint8]
+
+	// Normals maps vertices to normals, or is nil if the
+	// file has no vertex normals.
+	Normals *CoordMap[Coord3D]
+}
+
+// ReadPLY decodes an ASCII or binary PLY file.
+//
+// Polygonal faces are split into triangles.
+func ReadPLY(r io.Reader) (*PLYAsset, error) {
+	ply, err := fileformats.ReadPLYMesh(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read PLY")
+	}
+	coords := make([]Coord3D, len(ply.Vertices))
+	for i, v := range ply.Vertices {
+		coords[i] = NewCoord3DArray(v)
+	}
+	res := &PLYAsset{Mesh: NewMesh()}
+	if ply.Colors != nil {
+		res.Colors = NewCoordMap[[3]uint8]()
+		for i, c := range ply.Colors {
+			res.Colors.Store(coords[i], c)
+		}
+	}
+	if ply.Normals != nil {
+		res.Normals = NewCoordMap[Coord3D]()
+		for i, n := range ply.Normals {
+			res.Normals.Store(coords[i], NewCoord3DArray(n))
+		}
+	}
+	for _, face := range ply.Faces {
+		poly := make([]Coord3D, len(face))
+		for i, idx := range face {
+			poly[i] = coords[idx]
+		}
+		for _, t := range TriangulateFace(poly) {
+			res.Mesh.Add(t)
+		}
+	}
+	return res, nil
+}
+
+// ColorFunc creates a function which returns the color of
+// the nearest vertex in Colors.
+//
+// This can be used to preserve vertex colors after the
+// mesh is modified, e.g. by a Decimator. The resulting
+// function is safe to call from multiple Goroutines.
+//
+// If Colors is nil, ColorFunc returns nil.
+func (p *PLYAsset) ColorFunc() func(c Coord3D) [3]uint8 {
+	if p.Colors == nil {
+		return nil
+	}
+	keys := make([]Coord3D, 0, p.Colors.Len())
+	p.Colors.KeyRange(func(c Coord3D) bool {
+		keys = append(keys, c)
+		return true
+	})
+	tree := NewCoordTree(keys)
+	return func(c Coord3D) [3]uint8 {
+		if color, ok := p.Colors.Load(c); ok {
+			return color
+		}
+		return p.Colors.Value(tree.NearestNeighbor(c))
+	}
+}
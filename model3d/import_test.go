@@ -5,7 +5,12 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/unixpickle/model3d/fileformats"
+	"github.com/unixpickle/model3d/model2d"
 )
 
 func TestImportSTL(t *testing.T) {
@@ -58,3 +63,127 @@ func TestImportOFF(t *testing.T) {
 		t.Errorf("incorrect area: %f", area)
 	}
 }
+
+func TestOBJAssetRoundTrip(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 1)
+	asset := &OBJAsset{
+		Mesh:      mesh,
+		UVMap:     MeshUVMap{},
+		Normals:   map[*Triangle][3]Coord3D{},
+		Materials: map[*Triangle]string{},
+		MaterialTable: map[string]*fileformats.MTLFileMaterial{
+			"top":    {Name: "top", Diffuse: [3]float32{1, 0, 0}},
+			"bottom": {Name: "bottom", Diffuse: [3]float32{0, 0, 1}},
+		},
+	}
+	mesh.Iterate(func(t *Triangle) {
+		center := t.Min().Mid(t.Max())
+		if center.Z > 0 {
+			asset.Materials[t] = "top"
+			var uvs [3]model2d.Coord
+			for i, c := range t {
+				uvs[i] = c.XY().Scale(0.5).Add(model2d.XY(0.5, 0.5))
+			}
+			asset.UVMap[t] = uvs
+		} else {
+			asset.Materials[t] = "bottom"
+			asset.Normals[t] = [3]Coord3D{t[0], t[1], t[2]}
+		}
+	})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mesh.obj")
+	if err := asset.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decoded.MaterialFiles, []string{"mesh.mtl"}) {
+		t.Errorf("unexpected material files: %v", decoded.MaterialFiles)
+	}
+	if !reflect.DeepEqual(decoded.MaterialTable, asset.MaterialTable) {
+		t.Errorf("unexpected material table: %v", decoded.MaterialTable)
+	}
+	if n1, n2 := len(decoded.Mesh.TriangleSlice()), len(mesh.TriangleSlice()); n1 != n2 {
+		t.Fatalf("expected %d triangles but got %d", n2, n1)
+	}
+	if len(decoded.UVMap) != len(asset.UVMap) || len(decoded.Normals) != len(asset.Normals) {
+		t.Fatalf("unexpected attribute counts")
+	}
+
+	// Match decoded triangles to the originals by their
+	// centroids, since coordinates are written as float32.
+	centroid := func(t *Triangle) Coord3D {
+		return t[0].Add(t[1]).Add(t[2]).Scale(1.0 / 3)
+	}
+	decoded.Mesh.Iterate(func(t1 *Triangle) {
+		var t2 *Triangle
+		mesh.Iterate(func(t *Triangle) {
+			if centroid(t).Dist(centroid(t1)) < 1e-5 {
+				t2 = t
+			}
+		})
+		if t2 == nil {
+			t.Fatal("no matching triangle")
+		}
+		if decoded.Materials[t1] != asset.Materials[t2] {
+			t.Errorf("expected material %s but got %s", asset.Materials[t2], decoded.Materials[t1])
+		}
+		if uvs, ok := asset.UVMap[t2]; ok {
+			for i, uv := range decoded.UVMap[t1] {
+				if uv.Dist(uvs[i]) > 1e-5 {
+					t.Errorf("unexpected UV: %v (expected %v)", uv, uvs[i])
+				}
+			}
+		}
+		if normals, ok := asset.Normals[t2]; ok {
+			for i, n := range decoded.Normals[t1] {
+				if n.Dist(normals[i]) > 1e-5 {
+					t.Errorf("unexpected normal: %v (expected %v)", n, normals[i])
+				}
+			}
+		}
+	})
+}
+
+func TestReadPLY(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 3)
+	colorFunc := func(c Coord3D) [3]uint8 {
+		if c.Z > 0 {
+			return [3]uint8{255, 0, 0}
+		}
+		return [3]uint8{0, 0, 255}
+	}
+	decoded, err := ReadPLY(bytes.NewReader(mesh.EncodePLY(colorFunc)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Normals != nil {
+		t.Error("unexpected normals")
+	}
+	if n1, n2 := len(decoded.Mesh.TriangleSlice()), len(mesh.TriangleSlice()); n1 != n2 {
+		t.Fatalf("expected %d triangles but got %d", n2, n1)
+	}
+	if math.Abs(decoded.Mesh.Volume()-mesh.Volume()) > 1e-4 {
+		t.Errorf("unexpected volume: %f", decoded.Mesh.Volume())
+	}
+	if decoded.Colors.Len() != len(mesh.VertexSlice()) {
+		t.Fatalf("unexpected number of colors: %d", decoded.Colors.Len())
+	}
+
+	// Colors should be preserved by nearest-neighbor lookup
+	// after vertices are moved or removed.
+	decimated := (&Decimator{PlaneDistance: 0.01}).Decimate(decoded.Mesh)
+	decimated = decimated.Scale(1.01)
+	decodedColors := decoded.ColorFunc()
+	for _, c := range decimated.VertexSlice() {
+		if math.Abs(c.Z) > 0.2 {
+			if actual, expected := decodedColors(c), colorFunc(c); actual != expected {
+				t.Errorf("vertex %v: expected color %v but got %v", c, expected, actual)
+			}
+		}
+	}
+}
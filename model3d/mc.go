@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"context"
 	"math"
 	"runtime"
 	"sort"
@@ -12,14 +13,28 @@ import (
 // MarchingCubes turns a Solid into a surface mesh using a
 // corrected marching cubes algorithm.
 func MarchingCubes(s Solid, delta float64) *Mesh {
+	mesh, _ := MarchingCubesContext(context.Background(), s, delta)
+	return mesh
+}
+
+// MarchingCubesContext is like MarchingCubes, but stops
+// early and returns ctx.Err() if ctx is cancelled or
+// expires.
+func MarchingCubesContext(ctx context.Context, s Solid, delta float64) (*Mesh, error) {
+	return marchingCubes(ctx, s, delta, nil)
+}
+
+func marchingCubes(ctx context.Context, s Solid, delta float64, p Progress) (*Mesh, error) {
 	if !BoundsValid(s) {
 		panic("invalid bounds for solid")
 	}
 
 	table := mcLookupTable()
 	spacer := newSquareSpacer(s, delta)
-	mesh := NewMesh()
-	spacer.Scan(s, func(z int, bottomCache, topCache *solidCache) {
+	arena := &TriangleArena{}
+	mesh := NewMeshArena(arena)
+	err := spacer.ScanContext(ctx, s, func(z int, bottomCache, topCache *solidCache) {
+		progressUpdate(p, float64(z-1)/float64(len(spacer.Zs)-1))
 		for y := 0; y < len(spacer.Ys)-1; y++ {
 			for x := 0; x < len(spacer.Xs)-1; x++ {
 				bits := bottomCache.GetSquare(x, y) | (topCache.GetSquare(x, y) << 4)
@@ -29,13 +44,16 @@ func MarchingCubes(s Solid, delta float64) *Mesh {
 					max := spacer.CornerCoord(x+1, y+1, z)
 					corners := mcCornerCoordinates(min, max)
 					for _, t := range triangles {
-						mesh.Add(t.Triangle(corners))
+						mesh.Add(t.Triangle(corners, arena))
 					}
 				}
 			}
 		}
 	})
-	return mesh
+	if err != nil {
+		return nil, err
+	}
+	return mesh, nil
 }
 
 // MarchingCubesSearch is like MarchingCubes, but applies
@@ -50,6 +68,36 @@ func MarchingCubesSearch(s Solid, delta float64, iters int) *Mesh {
 	return mesh
 }
 
+// MarchingCubesSearchContext is like MarchingCubesSearch,
+// but stops early and returns ctx.Err() if ctx is
+// cancelled or expires.
+func MarchingCubesSearchContext(ctx context.Context, s Solid, delta float64,
+	iters int) (*Mesh, error) {
+	return MarchingCubesSearchProgress(ctx, s, delta, iters, nil)
+}
+
+// MarchingCubesSearchProgress is like
+// MarchingCubesSearchContext, but reports the fraction of
+// the volume which has been scanned to p, followed by a
+// status message once the search step begins.
+//
+// The progress p may be nil.
+func MarchingCubesSearchProgress(ctx context.Context, s Solid, delta float64, iters int,
+	p Progress) (*Mesh, error) {
+	mesh, err := marchingCubes(ctx, s, delta, p)
+	if err != nil {
+		return nil, err
+	}
+	progressUpdate(p, 1)
+	if iters > 0 {
+		progressLogf(p, "refining vertices with %d search iterations", iters)
+	}
+	if err := mcSearchContext(ctx, s, delta, iters, mesh, nil); err != nil {
+		return nil, err
+	}
+	return mesh, nil
+}
+
 // MarchingCubesInterior is like MarchingCubesSearch, but
 // in addition to a mesh, it returns a mapping from each
 // vertex to a nearby point which is known to be contained
@@ -105,7 +153,8 @@ func MarchingCubesFilter(s Solid, f func(*Rect) bool, delta float64) *Mesh {
 	subDivideVolume := 64
 	for i := 0; i < numGos; i++ {
 		go func() {
-			result := NewMesh()
+			arena := &TriangleArena{}
+			result := NewMeshArena(arena)
 			cache := newMcBlockCache()
 			for block := range blockQueue {
 				block.Pieces(subDivideVolume, blockFilter, func(block *mcBlock) {
@@ -120,7 +169,7 @@ func MarchingCubesFilter(s Solid, f func(*Rect) bool, delta float64) *Mesh {
 									max := spacer.CornerCoord(x+1, y+1, z+1)
 									corners := mcCornerCoordinates(min, max)
 									for _, t := range triangles {
-										result.Add(t.Triangle(corners))
+										result.Add(t.Triangle(corners, arena))
 									}
 								}
 							}
@@ -143,9 +192,11 @@ func MarchingCubesFilter(s Solid, f func(*Rect) bool, delta float64) *Mesh {
 	})
 	close(blockQueue)
 
-	mesh := NewMesh()
+	mesh := NewMeshArena(&TriangleArena{})
 	for i := 0; i < numGos; i++ {
-		mesh.AddMesh(<-outputs)
+		output := <-outputs
+		mesh.AddMesh(output)
+		mesh.arena.Merge(output.arena)
 	}
 	return mesh
 }
@@ -184,8 +235,15 @@ func MarchingCubesC2F(s Solid, bigDelta, smallDelta, extraSpace float64, iters i
 }
 
 func mcSearch(s Solid, delta float64, iters int, mesh *Mesh, interior *CoordMap[Coord3D]) {
+	mcSearchContext(context.Background(), s, delta, iters, mesh, interior)
+}
+
+// mcSearchContext is like mcSearch, but aborts before
+// modifying the mesh if ctx is cancelled.
+func mcSearchContext(ctx context.Context, s Solid, delta float64, iters int, mesh *Mesh,
+	interior *CoordMap[Coord3D]) error {
 	if iters == 0 && interior == nil {
-		return
+		return nil
 	}
 
 	spacer := newSquareSpacer(s, delta)
@@ -195,16 +253,28 @@ func mcSearch(s Solid, delta float64, iters int, mesh *Mesh, interior *CoordMap[
 	if interior != nil {
 		interiorVertices := make([]Coord3D, len(inVertices))
 		essentials.ConcurrentMap(0, len(inVertices), func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
 			outVertices[i] = mcSearchPoint(s, delta, iters, mesh, spacer, inVertices[i],
 				&interiorVertices[i])
 		})
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		for i, c := range outVertices {
 			interior.Store(c, interiorVertices[i])
 		}
 	} else {
 		essentials.ConcurrentMap(0, len(inVertices), func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
 			outVertices[i] = mcSearchPoint(s, delta, iters, mesh, spacer, inVertices[i], nil)
 		})
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 	}
 
 	v2t := mesh.getVertexToFace()
@@ -223,6 +293,8 @@ func mcSearch(s Solid, delta float64, iters int, mesh *Mesh, interior *CoordMap[
 	// We just invalidated the entire v2t cache by
 	// replacing the vertices in the triangles.
 	mesh.vertexToFace = atomic.Value{}
+
+	return nil
 }
 
 func mcSearchPoint(s Solid, delta float64, iters int, m *Mesh, spacer *squareSpacer,
@@ -398,12 +470,16 @@ type mcTriangle [6]mcCorner
 
 // Triangle creates a real triangle out of the mcTriangle,
 // given the corner coordinates.
-func (m mcTriangle) Triangle(corners [8]Coord3D) *Triangle {
-	return &Triangle{
-		corners[m[0]].Mid(corners[m[1]]),
-		corners[m[2]].Mid(corners[m[3]]),
-		corners[m[4]].Mid(corners[m[5]]),
+//
+// If arena is non-nil, the triangle is allocated from it.
+func (m mcTriangle) Triangle(corners [8]Coord3D, arena *TriangleArena) *Triangle {
+	p1 := corners[m[0]].Mid(corners[m[1]])
+	p2 := corners[m[2]].Mid(corners[m[3]])
+	p3 := corners[m[4]].Mid(corners[m[5]])
+	if arena != nil {
+		return arena.New(p1, p2, p3)
 	}
+	return &Triangle{p1, p2, p3}
 }
 
 // mcIntersections represents which corners on a cube are
@@ -612,6 +688,13 @@ func (s *squareSpacer) CornerCoord(x, y, z int) Coord3D {
 }
 
 func (s *squareSpacer) Scan(solid Solid, f func(z int, bottom, top *solidCache)) {
+	s.ScanContext(context.Background(), solid, f)
+}
+
+// ScanContext is like Scan, but stops between z layers if
+// ctx is cancelled, returning ctx.Err().
+func (s *squareSpacer) ScanContext(ctx context.Context, solid Solid,
+	f func(z int, bottom, top *solidCache)) error {
 	numGos := runtime.GOMAXPROCS(0)
 
 	// Prevent edge case where we are making a very
@@ -631,6 +714,12 @@ func (s *squareSpacer) Scan(solid Solid, f func(z int, bottom, top *solidCache))
 
 	<-caches[0].Done
 	for nextZ := 1; nextZ < len(s.Zs); nextZ++ {
+		if err := ctx.Err(); err != nil {
+			// Outstanding fetches write to buffered channels,
+			// so they will not leak Goroutines.
+			return err
+		}
+
 		prevIdx := (nextZ - 1) % len(caches)
 		curIdx := nextZ % len(caches)
 
@@ -642,6 +731,7 @@ func (s *squareSpacer) Scan(solid Solid, f func(z int, bottom, top *solidCache))
 			caches[prevIdx].FetchZ(nextZ + len(caches) - 1)
 		}
 	}
+	return nil
 }
 
 func (s *squareSpacer) LookupEdgePoint(c Coord3D) (axis int, min, max float64) {
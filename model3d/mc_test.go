@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 )
@@ -39,6 +40,66 @@ func TestMarchingCubesRandom(t *testing.T) {
 	}
 }
 
+func TestMarchingCubesContext(t *testing.T) {
+	solid := &Sphere{Radius: 1}
+
+	mesh, err := MarchingCubesSearchContext(context.Background(), solid, 0.1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := MarchingCubesSearch(solid, 0.1, 2)
+	if mesh.NumTriangles() != expected.NumTriangles() {
+		t.Errorf("expected %d triangles but got %d", expected.NumTriangles(),
+			mesh.NumTriangles())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := MarchingCubesSearchContext(ctx, solid, 0.1, 2); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	dc := &DualContouring{S: SolidSurfaceEstimator{Solid: solid}, Delta: 0.1}
+	if _, err := dc.MeshContext(ctx); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	dec := &Decimator{PlaneDistance: 0.01}
+	if _, err := dec.DecimateContext(ctx, expected); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	qem := &QEMSimplifier{}
+	if _, err := qem.SimplifyContext(ctx, expected, 100); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+	arap := NewARAP(expected)
+	constraints := ARAPConstraints{}
+	constraints.AddAround(arap, XYZ(0, 0, 1), 0.3, XYZ(0, 0, 1.5))
+	if _, err := arap.DeformContext(ctx, constraints); err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMarchingCubesProgress(t *testing.T) {
+	var updates []float64
+	p := ProgressFunc(func(frac float64) {
+		updates = append(updates, frac)
+	})
+	_, err := MarchingCubesSearchProgress(context.Background(), &Sphere{Radius: 1}, 0.1, 2, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) < 2 {
+		t.Fatalf("too few updates: %v", updates)
+	}
+	for i := 1; i < len(updates); i++ {
+		if updates[i] < updates[i-1] {
+			t.Fatalf("non-monotonic updates: %v", updates)
+		}
+	}
+	if updates[len(updates)-1] != 1 {
+		t.Errorf("final update should be 1 but got %f", updates[len(updates)-1])
+	}
+}
+
 func TestMarchingCubesFilter(t *testing.T) {
 	t.Run("Sphere", func(t *testing.T) {
 		mesh := NewMeshIcosphere(XYZ(0.1, 0.3, -0.2), 1.0, 20)
@@ -1,6 +1,9 @@
 package model3d
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 // Area computes the total surface area of the mesh.
 func (m *Mesh) Area() float64 {
@@ -27,3 +30,133 @@ func (m *Mesh) Volume() float64 {
 	})
 	return math.Abs(result)
 }
+
+// SignedVolume measures the volume of a manifold mesh,
+// which is positive if the normals face outward and
+// negative if they face inward.
+func (m *Mesh) SignedVolume() float64 {
+	var result float64
+	m.Iterate(func(t *Triangle) {
+		result += t[0].Dot(t[1].Cross(t[2])) / 6
+	})
+	return result
+}
+
+// Centroid computes the center of mass of the volume
+// inside of a manifold mesh.
+//
+// This assumes that the normals are consistent.
+func (m *Mesh) Centroid() Coord3D {
+	return m.Moments().Centroid
+}
+
+// InertiaTensor computes the inertia tensor of the volume
+// inside of a manifold mesh about its centroid, assuming
+// a uniform density of 1.
+//
+// This assumes that the normals are consistent.
+func (m *Mesh) InertiaTensor() *Matrix3 {
+	return &m.Moments().Inertia
+}
+
+// VolumeMoments stores the mass properties of a 3D shape
+// with uniform density.
+type VolumeMoments struct {
+	Volume   float64
+	Centroid Coord3D
+
+	// Inertia is the inertia tensor about the centroid for
+	// a density of 1. For other densities, the tensor
+	// should be scaled by the density.
+	Inertia Matrix3
+}
+
+// PrincipalMoments computes the eigenvalues of the inertia
+// tensor, which are the moments of inertia about the
+// principal axes, sorted from largest to smallest.
+func (v *VolumeMoments) PrincipalMoments() [3]float64 {
+	eigs := v.Inertia.Eigenvalues()
+	var res [3]float64
+	for i, x := range eigs {
+		res[i] = real(x)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(res[:])))
+	return res
+}
+
+// Moments computes the volume, centroid, and inertia
+// tensor of a manifold mesh by integrating over signed
+// tetrahedra.
+//
+// This assumes that the normals are consistent. If the
+// normals face inward, the result is the same as if they
+// faced outward.
+func (m *Mesh) Moments() *VolumeMoments {
+	if m.NumTriangles() == 0 {
+		return &VolumeMoments{}
+	}
+
+	// Compute everything relative to a vertex of the mesh
+	// for numerical stability.
+	origin := m.TriangleSlice()[0][0]
+
+	var volume float64
+	var first Coord3D
+	var second Matrix3
+	m.Iterate(func(t *Triangle) {
+		p0, p1, p2 := t[0].Sub(origin), t[1].Sub(origin), t[2].Sub(origin)
+		det := p0.Dot(p1.Cross(p2))
+		sum := p0.Add(p1).Add(p2)
+		volume += det
+		first = first.Add(sum.Scale(det))
+
+		// The integral of x_i*x_j over a tetrahedron with one
+		// vertex at the origin is det/120 times the sum of
+		// p_i*p_j over the vertices plus sum_i*sum_j.
+		ps := [3][3]float64{p0.Array(), p1.Array(), p2.Array()}
+		sumArr := sum.Array()
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				x := sumArr[i] * sumArr[j]
+				for _, p := range ps {
+					x += p[i] * p[j]
+				}
+				second[i*3+j] += x * det
+			}
+		}
+	})
+	if volume < 0 {
+		volume = -volume
+		first = first.Scale(-1)
+		second.Scale(-1)
+	}
+	volume /= 6
+	if volume == 0 {
+		return &VolumeMoments{Centroid: origin}
+	}
+	centroid := first.Scale(1 / (24 * volume))
+	second.Scale(1.0 / 120)
+
+	// Move the second moments to the centroid, and then
+	// convert them into an inertia tensor.
+	c := centroid.Array()
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			second[i*3+j] -= volume * c[i] * c[j]
+		}
+	}
+	trace := second[0] + second[4] + second[8]
+	var inertia Matrix3
+	for i := 0; i < 9; i++ {
+		inertia[i] = -second[i]
+	}
+	for i := 0; i < 3; i++ {
+		inertia[i*4] += trace
+	}
+
+	return &VolumeMoments{
+		Volume:   volume,
+		Centroid: centroid.Add(origin),
+		Inertia:  inertia,
+	}
+}
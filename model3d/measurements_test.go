@@ -20,3 +20,59 @@ func TestMeshVolume(t *testing.T) {
 		}
 	}
 }
+
+func TestMeshMoments(t *testing.T) {
+	box := NewMeshRect(XYZ(1, 2, 0), XYZ(3, 6, 1))
+	expectedInertia := Matrix3{
+		8.0 / 12 * (16 + 1), 0, 0,
+		0, 8.0 / 12 * (4 + 1), 0,
+		0, 0, 8.0 / 12 * (4 + 16),
+	}
+	for i, mesh := range []*Mesh{box, box.InvertNormals()} {
+		moments := mesh.Moments()
+		if math.Abs(moments.Volume-8) > 1e-8 {
+			t.Errorf("case %d: expected volume 8 but got %f", i, moments.Volume)
+		}
+		if moments.Centroid.Dist(XYZ(2, 4, 0.5)) > 1e-8 {
+			t.Errorf("case %d: unexpected centroid: %v", i, moments.Centroid)
+		}
+		for j, x := range expectedInertia {
+			if math.Abs(moments.Inertia[j]-x) > 1e-8 {
+				t.Errorf("case %d: expected inertia %v but got %v", i, expectedInertia,
+					moments.Inertia)
+				break
+			}
+		}
+	}
+	if v := box.SignedVolume(); math.Abs(v-8) > 1e-8 {
+		t.Errorf("expected signed volume 8 but got %f", v)
+	}
+	if v := box.InvertNormals().SignedVolume(); math.Abs(v+8) > 1e-8 {
+		t.Errorf("expected signed volume -8 but got %f", v)
+	}
+
+	// Rotating the shape should preserve the principal
+	// moments.
+	rotated := box.Transform(Rotation(XYZ(1, 2, 3).Normalize(), 0.7))
+	principal := rotated.Moments().PrincipalMoments()
+	expected := [3]float64{expectedInertia[8], expectedInertia[0], expectedInertia[4]}
+	for i, x := range expected {
+		if math.Abs(principal[i]-x) > 1e-6 {
+			t.Errorf("expected principal moments %v but got %v", expected, principal)
+			break
+		}
+	}
+
+	sphere := NewMeshIcosphere(XYZ(1, -2, 3), 2, 30)
+	moments := sphere.Moments()
+	if moments.Centroid.Dist(XYZ(1, -2, 3)) > 1e-8 {
+		t.Errorf("unexpected sphere centroid: %v", moments.Centroid)
+	}
+	expectedMoment := 2.0 / 5.0 * moments.Volume * 4
+	inertia := sphere.InertiaTensor()
+	for i := 0; i < 3; i++ {
+		if math.Abs(inertia[i*4]-expectedMoment) > 1e-2*expectedMoment {
+			t.Errorf("expected sphere moment %f but got %f", expectedMoment, inertia[i*4])
+		}
+	}
+}
@@ -4,6 +4,7 @@ package model3d
 
 import (
 	"bufio"
+	"image"
 	"math"
 	"os"
 	"sort"
@@ -33,9 +34,22 @@ import (
 type Mesh struct {
 	faces map[*Triangle]bool
 
+	// If true, iteration and slices are sorted.
+	// See SetDeterministic().
+	deterministic bool
+
 	// Stores a *CoordToSlice[*Triangle]
 	vertexToFace  atomic.Value
 	v2fCreateLock sync.Mutex
+
+	// If non-nil, the mesh owns this arena and may
+	// release it in Free().
+	arena *TriangleArena
+
+	// If non-nil, an edge-to-face cache which is kept
+	// up-to-date by Add() and Remove().
+	// See TrackAdjacency().
+	edgeToFace *EdgeToSlice[*Triangle]
 }
 
 // NewMesh creates an empty mesh.
@@ -298,8 +312,14 @@ func NewMeshIcosahedron() *Mesh {
 // the Z axis.
 //
 // The 2D mesh must be manifold, closed, and oriented.
+//
+// The resulting mesh is built directly from the 2D mesh,
+// so every 2D vertex appears exactly (at both minZ and
+// maxZ), and every 2D segment becomes one side quad.
+// The caps are filled with a constrained Delaunay
+// triangulation to avoid needlessly thin triangles.
 func ProfileMesh(m2d *model2d.Mesh, minZ, maxZ float64) *Mesh {
-	tris := model2d.TriangulateMesh(m2d)
+	tris := model2d.TriangulateMeshDelaunay(m2d)
 	m := NewMesh()
 	for _, t := range tris {
 		m.Add(&Triangle{
@@ -313,28 +333,84 @@ func ProfileMesh(m2d *model2d.Mesh, minZ, maxZ float64) *Mesh {
 			XYZ(t[2].X, t[2].Y, maxZ),
 		})
 	}
+	m2d.Iterate(func(s *model2d.Segment) {
+		m.AddQuad(
+			XYZ(s[1].X, s[1].Y, minZ),
+			XYZ(s[0].X, s[0].Y, minZ),
+			XYZ(s[0].X, s[0].Y, maxZ),
+			XYZ(s[1].X, s[1].Y, maxZ),
+		)
+	})
+	return m
+}
 
-	// Add sides to triangle edges with no neighbors.
-	m.Iterate(func(t *Triangle) {
-		if t[0].Z != minZ {
+// RevolveMesh creates a 3D mesh by rotating a 2D mesh
+// around an axis, like a lathe.
+//
+// As with RevolveSolid, the y-axis of the 2D mesh is
+// extended along the axis of revolution, while the x-axis
+// is used as a radius. The 2D mesh should be manifold,
+// closed, oriented, and have no negative x values.
+//
+// The mesh is swept through the given angle (in radians)
+// in the given number of segments, starting and turning in
+// the same direction as Revolve. If the angle is less
+// than 2*pi, the ends of the sweep are capped to produce a
+// closed mesh.
+func RevolveMesh(m2d *model2d.Mesh, axis Coord3D, angle float64, segments int) *Mesh {
+	if segments < 1 {
+		panic("must have at least one segment")
+	}
+	axis = axis.Normalize()
+	b1, b2 := revolveBasis(axis)
+	full := angle >= 2*math.Pi
+	if full {
+		angle = 2 * math.Pi
+	}
+	point := func(c model2d.Coord, i int) Coord3D {
+		if full {
+			// Make sure the last segment meets the first.
+			i %= segments
+		}
+		if c.X == 0 {
+			return axis.Scale(c.Y)
+		}
+		theta := angle * float64(i) / float64(segments)
+		radial := b1.Scale(math.Cos(theta)).Add(b2.Scale(math.Sin(theta)))
+		return radial.Scale(c.X).Add(axis.Scale(c.Y))
+	}
+
+	m := NewMesh()
+	m2d.Iterate(func(s *model2d.Segment) {
+		if s[0].X == 0 && s[1].X == 0 {
 			return
 		}
-		for i := 0; i < 3; i++ {
-			seg := [2]Coord3D{t[(i+1)%3], t[i]}
-			if len(m.Find(seg[0], seg[1])) == 1 {
-				// This needs to be connected from minZ to maxZ.
-				p3, p4 := seg[1], seg[0]
-				p3.Z = maxZ
-				p4.Z = maxZ
-				m.AddQuad(seg[0], seg[1], p3, p4)
+		for i := 0; i < segments; i++ {
+			p1, p2 := point(s[0], i), point(s[1], i)
+			p3, p4 := point(s[1], i+1), point(s[0], i+1)
+			if s[0].X == 0 {
+				m.Add(&Triangle{p1, p2, p3})
+			} else if s[1].X == 0 {
+				m.Add(&Triangle{p1, p2, p4})
+			} else {
+				m.AddQuad(p1, p2, p3, p4)
 			}
 		}
 	})
+	if !full {
+		for _, t := range model2d.TriangulateMesh(m2d) {
+			m.Add(&Triangle{point(t[1], 0), point(t[0], 0), point(t[2], 0)})
+			m.Add(&Triangle{point(t[0], segments), point(t[1], segments), point(t[2], segments)})
+		}
+	}
 	return m
 }
 
 // Add adds the triangle f to the mesh.
 func (m *Mesh) Add(f *Triangle) {
+	if m.edgeToFace != nil && !m.faces[f] {
+		m.addFaceEdges(f)
+	}
 	v2f := m.getVertexToFaceOrNil()
 	if v2f == nil {
 		m.faces[f] = true
@@ -372,6 +448,8 @@ func (m *Mesh) AddMesh(m1 *Mesh) {
 // triangles are the same exact pointers.
 func (m *Mesh) Copy() *Mesh {
 	m1 := NewMesh()
+	m1.deterministic = m.deterministic
+	m1.inheritAdjacency(m)
 	m1.AddMesh(m)
 	return m1
 }
@@ -380,6 +458,8 @@ func (m *Mesh) Copy() *Mesh {
 // triangles are copied individually.
 func (m *Mesh) DeepCopy() *Mesh {
 	m1 := NewMesh()
+	m1.deterministic = m.deterministic
+	m1.inheritAdjacency(m)
 	m.Iterate(func(f *Triangle) {
 		f1 := new(Triangle)
 		*f1 = *f
@@ -397,6 +477,9 @@ func (m *Mesh) Remove(f *Triangle) {
 		return
 	}
 	delete(m.faces, f)
+	if m.edgeToFace != nil {
+		m.removeFaceEdges(f)
+	}
 	v2f := m.getVertexToFaceOrNil()
 	if v2f != nil {
 		uniqueVertices(f, func(p Coord3D) {
@@ -432,7 +515,8 @@ func (m *Mesh) NumTriangles() int {
 }
 
 // Iterate calls f for every triangle in m in an arbitrary
-// order.
+// order, or in a sorted order if the mesh is
+// deterministic (see SetDeterministic).
 //
 // If f adds or removes triangles, they will not be visited.
 func (m *Mesh) Iterate(f func(*Triangle)) {
@@ -456,7 +540,8 @@ func (m *Mesh) IterateSorted(f func(*Triangle), cmp func(f1, f2 *Triangle) bool)
 }
 
 // IterateVertices calls f for every vertex in m in an
-// arbitrary order.
+// arbitrary order, or in a sorted order if the mesh is
+// deterministic (see SetDeterministic).
 //
 // If f adds or removes vertices, they will not be
 // visited.
@@ -478,6 +563,9 @@ func (m *Mesh) IterateVertices(f func(c Coord3D)) {
 // not in the mesh, but an equivalent triangle is, then said
 // equivalent triangle will be in the results.
 func (m *Mesh) Neighbors(f *Triangle) []*Triangle {
+	if m.edgeToFace != nil {
+		return m.edgeNeighbors(f)
+	}
 	counts := m.neighborsWithCounts(f)
 	res := make([]*Triangle, 0, len(counts))
 	for t1, count := range counts {
@@ -580,6 +668,59 @@ func (m *Mesh) Rotate(axis Coord3D, angle float64) *Mesh {
 // MapCoords creates a new mesh by transforming all of the
 // coordinates according to the function f.
 func (m *Mesh) MapCoords(f func(Coord3D) Coord3D) *Mesh {
+	mapping := m.coordMapping(f)
+	m1 := NewMesh()
+	m1.deterministic = m.deterministic
+	m1.inheritAdjacency(m)
+	m.Iterate(func(t *Triangle) {
+		t1 := *t
+		for i, p := range t {
+			t1[i] = mapping.Value(p)
+		}
+		m1.Add(&t1)
+	})
+	return m1
+}
+
+// Transform applies t to the coordinates.
+func (m *Mesh) Transform(t Transform) *Mesh {
+	return m.MapCoords(t.Apply)
+}
+
+// MapCoordsInPlace is like MapCoords, but it modifies the
+// triangles of m directly instead of creating a new mesh.
+//
+// This avoids duplicating every triangle, which can
+// significantly reduce peak memory usage for large meshes.
+// However, any other references to the triangles of m
+// will observe the new coordinates.
+func (m *Mesh) MapCoordsInPlace(f func(Coord3D) Coord3D) {
+	mapping := m.coordMapping(f)
+
+	// Release the cache before modifying the triangles,
+	// since it will be invalid anyway.
+	m.clearVertexToFace()
+
+	for t := range m.faces {
+		for i, p := range t {
+			t[i] = mapping.Value(p)
+		}
+	}
+
+	if m.edgeToFace != nil {
+		m.edgeToFace = nil
+		m.TrackAdjacency()
+	}
+}
+
+// TransformInPlace is like Transform, but it modifies the
+// triangles of m directly. See MapCoordsInPlace.
+func (m *Mesh) TransformInPlace(t Transform) {
+	m.MapCoordsInPlace(t.Apply)
+}
+
+// coordMapping evaluates f once for every vertex in m.
+func (m *Mesh) coordMapping(f func(Coord3D) Coord3D) *CoordMap[Coord3D] {
 	mapping := NewCoordMap[Coord3D]()
 	if v2f := m.getVertexToFaceOrNil(); v2f != nil {
 		v2f.KeyRange(func(c Coord3D) bool {
@@ -595,27 +736,14 @@ func (m *Mesh) MapCoords(f func(Coord3D) Coord3D) *Mesh {
 			}
 		}
 	}
-	m1 := NewMesh()
-	m.Iterate(func(t *Triangle) {
-		t1 := *t
-		for i, p := range t {
-			t1[i] = mapping.Value(p)
-		}
-		m1.Add(&t1)
-	})
-	return m1
-}
-
-// Transform applies t to the coordinates.
-func (m *Mesh) Transform(t Transform) *Mesh {
-	return m.MapCoords(t.Apply)
+	return mapping
 }
 
 // InvertNormals returns a new mesh with every
 // triangle oriented in the opposite way.
 func (m *Mesh) InvertNormals() *Mesh {
 	m1 := NewMesh()
-	m1.Iterate(func(f *Triangle) {
+	m.Iterate(func(f *Triangle) {
 		f1 := *f
 		f1[0], f1[1] = f1[1], f1[0]
 		m1.Add(&f1)
@@ -670,6 +798,30 @@ func (m *Mesh) SaveQuantizedMaterialOBJ(path string, textureSize int,
 	return nil
 }
 
+// EncodeGLB encodes the mesh as a binary glTF file.
+//
+// See EncodeGLB for details on uvMap and texture, either of
+// which may be nil.
+func (m *Mesh) EncodeGLB(uvMap MeshUVMap, texture image.Image) ([]byte, error) {
+	return EncodeGLB(m.TriangleSlice(), uvMap, texture)
+}
+
+// SaveGLB saves the mesh to a binary glTF file.
+//
+// See EncodeGLB for details on uvMap and texture, either of
+// which may be nil.
+func (m *Mesh) SaveGLB(path string, uvMap MeshUVMap, texture image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "save GLB")
+	}
+	defer f.Close()
+	if err := WriteGLTF(f, m.TriangleSlice(), uvMap, texture); err != nil {
+		return errors.Wrap(err, "save GLB")
+	}
+	return nil
+}
+
 // SaveGroupedSTL writes the mesh to an STL file with the
 // triangles grouped in such a way that the file can be
 // compressed efficiently.
@@ -696,14 +848,48 @@ func (m *Mesh) SaveGroupedSTL(path string) error {
 // TriangleSlice gets a snapshot of all the triangles
 // currently in the mesh. The resulting slice is a copy,
 // and will not change as the mesh is updated.
+//
+// If the mesh is deterministic, the triangles are sorted
+// by their coordinates.
 func (m *Mesh) TriangleSlice() []*Triangle {
 	ts := make([]*Triangle, 0, len(m.faces))
 	for t := range m.faces {
 		ts = append(ts, t)
 	}
+	if m.deterministic {
+		sort.Slice(ts, func(i, j int) bool {
+			return lessFace(ts[i], ts[j])
+		})
+	}
 	return ts
 }
 
+// SetDeterministic enables or disables deterministic
+// iteration order for the mesh.
+//
+// By default, Iterate(), TriangleSlice(), and related
+// methods visit triangles in an arbitrary order which
+// may change from run to run. When deterministic mode is
+// enabled, triangles and vertices are instead visited in
+// order of their coordinates, so that results such as
+// encoded files are reproducible regardless of how the
+// mesh was constructed.
+//
+// Deterministic iteration requires sorting, which costs
+// O(n*log(n)) time for every iteration.
+//
+// Meshes derived from m via Copy(), DeepCopy(), and
+// MapCoords() inherit this setting.
+func (m *Mesh) SetDeterministic(d bool) {
+	m.deterministic = d
+}
+
+// Deterministic returns true if deterministic iteration
+// order is enabled. See SetDeterministic().
+func (m *Mesh) Deterministic() bool {
+	return m.deterministic
+}
+
 // VertexSlice gets a snapshot of all the vertices
 // currently in the mesh.
 //
@@ -716,6 +902,11 @@ func (m *Mesh) VertexSlice() []Coord3D {
 		vertices = append(vertices, v)
 		return true
 	})
+	if m.deterministic {
+		sort.Slice(vertices, func(i, j int) bool {
+			return lessCoord(vertices[i], vertices[j])
+		})
+	}
 	return vertices
 }
 
@@ -813,3 +1004,25 @@ func uniqueVertices(face *Triangle, f func(Coord3D)) {
 	}
 
 }
+
+// lessFace compares triangles lexicographically by
+// their coordinates.
+func lessFace(f1, f2 *Triangle) bool {
+	for i, c1 := range f1 {
+		if c1 != f2[i] {
+			return lessCoord(c1, f2[i])
+		}
+	}
+	return false
+}
+
+// lessCoord compares coordinates lexicographically.
+func lessCoord(c1, c2 Coord3D) bool {
+	a1, a2 := c1.Array(), c2.Array()
+	for i, x := range a1 {
+		if x != a2[i] {
+			return x < a2[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,833 @@
+package model3d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/numerical"
+)
+
+// MeshUnion computes the union of two meshes directly on
+// their triangles.
+//
+// Unlike converting the meshes to solids and running
+// marching cubes, the result preserves the original
+// vertices and sharp features of both meshes, and only
+// triangles which cross the other mesh are split.
+//
+// Both meshes should be closed, manifold, and free of
+// self-intersections, with normals facing outward.
+// Intersections are classified using exact geometric
+// predicates, so coplanar and touching faces are handled
+// robustly. However, new intersection points are rounded
+// to floating point, so features which nearly coincide
+// without coinciding exactly may produce slivers or
+// non-manifold edges.
+func MeshUnion(m1, m2 *Mesh) *Mesh {
+	return meshBoolean(m1, m2, booleanUnion)
+}
+
+// MeshIntersect computes the intersection of two meshes.
+//
+// See MeshUnion for details and requirements.
+func MeshIntersect(m1, m2 *Mesh) *Mesh {
+	return meshBoolean(m1, m2, booleanIntersect)
+}
+
+// MeshSubtract computes the part of m1 which is not
+// contained in m2.
+//
+// See MeshUnion for details and requirements.
+func MeshSubtract(m1, m2 *Mesh) *Mesh {
+	return meshBoolean(m1, m2, booleanSubtract)
+}
+
+type booleanOp int
+
+const (
+	booleanUnion booleanOp = iota
+	booleanIntersect
+	booleanSubtract
+)
+
+type booleanClass int
+
+const (
+	booleanOutside booleanClass = iota
+	booleanInside
+	booleanCoplanarSame
+	booleanCoplanarOpposite
+)
+
+// keep determines if a piece of the first or second mesh
+// with a given classification belongs in the result, and
+// whether it should be flipped.
+func (b booleanOp) keep(second bool, class booleanClass) (keep, flip bool) {
+	if second {
+		switch b {
+		case booleanUnion:
+			return class == booleanOutside, false
+		case booleanIntersect:
+			return class == booleanInside, false
+		default:
+			return class == booleanInside, true
+		}
+	}
+	switch b {
+	case booleanUnion:
+		return class == booleanOutside || class == booleanCoplanarSame, false
+	case booleanIntersect:
+		return class == booleanInside || class == booleanCoplanarSame, false
+	default:
+		return class == booleanOutside || class == booleanCoplanarOpposite, false
+	}
+}
+
+func meshBoolean(m1, m2 *Mesh, op booleanOp) *Mesh {
+	tris1 := m1.TriangleSlice()
+	tris2 := m2.TriangleSlice()
+	splits1 := make([]*booleanSplit, len(tris1))
+	splits2 := make([]*booleanSplit, len(tris2))
+	for i := range splits1 {
+		splits1[i] = &booleanSplit{}
+	}
+	for i := range splits2 {
+		splits2[i] = &booleanSplit{}
+	}
+
+	if len(tris1) > 0 && len(tris2) > 0 {
+		bvh := NewBVHAreaDensity(booleanIndexedTris(tris2))
+
+		results := make([][]booleanPairResult, len(tris1))
+		essentials.ConcurrentMap(0, len(tris1), func(i int) {
+			t1 := tris1[i]
+			min, max := t1.Min(), t1.Max()
+			booleanBoxQuery(bvh, min, max, func(t2 booleanIndexedTri) {
+				points, coplanar := booleanIntersectTriangles(t1, t2.Triangle)
+				if len(points) > 0 || coplanar {
+					results[i] = append(results[i], booleanPairResult{
+						Index:    t2.Index,
+						Points:   points,
+						Coplanar: coplanar,
+					})
+				}
+			})
+		})
+		for i, pairs := range results {
+			for _, pair := range pairs {
+				splits1[i].AddPair(pair.Points, false, pair.Coplanar, tris2[pair.Index])
+				splits2[pair.Index].AddPair(pair.Points, true, pair.Coplanar, tris1[i])
+			}
+		}
+	}
+
+	res := NewMesh()
+	addPieces := func(tris []*Triangle, splits []*booleanSplit, other *Mesh, second bool) {
+		pieces := make([][]*Triangle, len(tris))
+		essentials.ConcurrentMap(0, len(tris), func(i int) {
+			pieces[i] = splits[i].Retriangulate(tris[i])
+		})
+		var allPieces []*Triangle
+		var pieceSplits []*booleanSplit
+		for i, ps := range pieces {
+			allPieces = append(allPieces, ps...)
+			for range ps {
+				pieceSplits = append(pieceSplits, splits[i])
+			}
+		}
+		collider := MeshToCollider(other)
+		patches := booleanPatches(allPieces, splits)
+		patchClasses := make([]booleanClass, len(patches))
+		essentials.ConcurrentMap(0, len(patches), func(i int) {
+			// Classifying the largest piece of each patch avoids
+			// unreliable ray casts from tiny slivers.
+			var rep int
+			maxArea := -1.0
+			for _, j := range patches[i] {
+				if a := allPieces[j].Area(); a > maxArea {
+					rep = j
+					maxArea = a
+				}
+			}
+			patchClasses[i] = pieceSplits[rep].Classify(allPieces[rep], collider)
+		})
+		classes := make([]booleanClass, len(allPieces))
+		for i, patch := range patches {
+			for _, j := range patch {
+				classes[j] = patchClasses[i]
+			}
+		}
+		for i, t := range allPieces {
+			if keep, flip := op.keep(second, classes[i]); keep {
+				if flip {
+					t[0], t[1] = t[1], t[0]
+				}
+				res.Add(t)
+			}
+		}
+	}
+	addPieces(tris1, splits1, m2, false)
+	addPieces(tris2, splits2, m1, true)
+	return res
+}
+
+// booleanPatches groups pieces into connected patches
+// which do not cross any intersection segments, since all
+// the pieces in a patch must have the same classification.
+func booleanPatches(pieces []*Triangle, splits []*booleanSplit) [][]int {
+	boundary := map[[2]Coord3D]bool{}
+	for _, s := range splits {
+		for _, seg := range s.Segments {
+			boundary[booleanCanonicalEdge(seg)] = true
+		}
+	}
+	edgeToPieces := map[[2]Coord3D][]int{}
+	for i, t := range pieces {
+		for j := 0; j < 3; j++ {
+			e := booleanCanonicalEdge([2]Coord3D{t[j], t[(j+1)%3]})
+			if !boundary[e] {
+				edgeToPieces[e] = append(edgeToPieces[e], i)
+			}
+		}
+	}
+
+	var patches [][]int
+	visited := make([]bool, len(pieces))
+	for i := range pieces {
+		if visited[i] {
+			continue
+		}
+		visited[i] = true
+		patch := []int{i}
+		for j := 0; j < len(patch); j++ {
+			t := pieces[patch[j]]
+			for k := 0; k < 3; k++ {
+				e := booleanCanonicalEdge([2]Coord3D{t[k], t[(k+1)%3]})
+				for _, neighbor := range edgeToPieces[e] {
+					if !visited[neighbor] {
+						visited[neighbor] = true
+						patch = append(patch, neighbor)
+					}
+				}
+			}
+		}
+		patches = append(patches, patch)
+	}
+	return patches
+}
+
+type booleanIndexedTri struct {
+	*Triangle
+	Index int
+}
+
+func booleanIndexedTris(tris []*Triangle) []booleanIndexedTri {
+	res := make([]booleanIndexedTri, len(tris))
+	for i, t := range tris {
+		res[i] = booleanIndexedTri{Triangle: t, Index: i}
+	}
+	return res
+}
+
+// booleanBoxQuery calls f for every leaf of a BVH whose
+// bounding box touches the box from min to max.
+//
+// The BVH must already have cached bounds, so that
+// concurrent queries do not modify it.
+func booleanBoxQuery(b *BVH[booleanIndexedTri], min, max Coord3D,
+	f func(t booleanIndexedTri)) {
+	bMin, bMax := b.bounds()
+	if bMin.X > max.X || bMin.Y > max.Y || bMin.Z > max.Z ||
+		bMax.X < min.X || bMax.Y < min.Y || bMax.Z < min.Z {
+		return
+	}
+	if len(b.Branch) == 0 {
+		f(b.Leaf)
+		return
+	}
+	for _, child := range b.Branch {
+		booleanBoxQuery(child, min, max, f)
+	}
+}
+
+type booleanPairResult struct {
+	Index    int
+	Points   []booleanPoint
+	Coplanar bool
+}
+
+type booleanLocationKind int
+
+const (
+	booleanLocationFace booleanLocationKind = iota
+	booleanLocationEdge
+	booleanLocationVertex
+)
+
+// A booleanLocation identifies the feature of a triangle
+// on which a point lies.
+//
+// For edges, Index i refers to the edge from vertex i to
+// vertex (i+1)%3.
+type booleanLocation struct {
+	Kind  booleanLocationKind
+	Index int
+}
+
+// A booleanPoint is a point where two triangles meet,
+// along with its location on each triangle.
+type booleanPoint struct {
+	Coord Coord3D
+	Loc1  booleanLocation
+	Loc2  booleanLocation
+}
+
+// booleanIntersectTriangles computes the points which
+// define the intersection of two triangles.
+//
+// For non-coplanar triangles, the result contains at most
+// two points, which are the endpoints of the intersection
+// segment. For coplanar triangles, the result contains the
+// vertices of the overlapping polygon, in order.
+//
+// Every point is computed as a deterministic function of
+// the features it lies on, so that adjacent triangles
+// produce bitwise-identical coordinates for shared points.
+func booleanIntersectTriangles(t1, t2 *Triangle) ([]booleanPoint, bool) {
+	if t1.crossProduct().Norm() == 0 || t2.crossProduct().Norm() == 0 {
+		return nil, false
+	}
+	var signs1, signs2 [3]int
+	for i := 0; i < 3; i++ {
+		signs1[i] = booleanOrient(t2[0], t2[1], t2[2], t1[i])
+		signs2[i] = booleanOrient(t1[0], t1[1], t1[2], t2[i])
+	}
+	if booleanOneSide(signs1) || booleanOneSide(signs2) {
+		return nil, false
+	}
+	if signs2 == [3]int{} {
+		return booleanCoplanarPoints(t1, t2), true
+	}
+
+	var points []booleanPoint
+	addPoint := func(p booleanPoint) {
+		for _, p1 := range points {
+			if p1.Loc1 == p.Loc1 && p1.Loc2 == p.Loc2 {
+				return
+			}
+		}
+		points = append(points, p)
+	}
+
+	// Vertices lying in the other triangle's plane.
+	proj1 := booleanProjection(t1)
+	proj2 := booleanProjection(t2)
+	for i, c := range t1 {
+		if signs1[i] == 0 {
+			if loc, ok := booleanLocate(t2, proj2, c); ok {
+				addPoint(booleanPoint{
+					Coord: c,
+					Loc1:  booleanLocation{Kind: booleanLocationVertex, Index: i},
+					Loc2:  loc,
+				})
+			}
+		}
+	}
+	for i, c := range t2 {
+		if signs2[i] == 0 {
+			if loc, ok := booleanLocate(t1, proj1, c); ok {
+				addPoint(booleanPoint{
+					Coord: c,
+					Loc1:  loc,
+					Loc2:  booleanLocation{Kind: booleanLocationVertex, Index: i},
+				})
+			}
+		}
+	}
+
+	// Edges strictly crossing the other triangle's plane.
+	for i := 0; i < 3; i++ {
+		if signs1[i]*signs1[(i+1)%3] < 0 {
+			e := [2]Coord3D{t1[i], t1[(i+1)%3]}
+			if loc, ok := booleanEdgeCrossing(e, t2); ok {
+				edgeLoc := booleanLocation{Kind: booleanLocationEdge, Index: i}
+				addPoint(booleanPoint{
+					Coord: booleanCrossingPoint(e, edgeLoc, t2, loc, false),
+					Loc1:  edgeLoc,
+					Loc2:  loc,
+				})
+			}
+		}
+		if signs2[i]*signs2[(i+1)%3] < 0 {
+			e := [2]Coord3D{t2[i], t2[(i+1)%3]}
+			if loc, ok := booleanEdgeCrossing(e, t1); ok {
+				edgeLoc := booleanLocation{Kind: booleanLocationEdge, Index: i}
+				addPoint(booleanPoint{
+					Coord: booleanCrossingPoint(e, edgeLoc, t1, loc, true),
+					Loc1:  loc,
+					Loc2:  edgeLoc,
+				})
+			}
+		}
+	}
+
+	if len(points) > 2 {
+		// This can only happen for degenerate inputs, in
+		// which case we keep the longest segment.
+		var best [2]booleanPoint
+		bestDist := -1.0
+		for i, p1 := range points {
+			for _, p2 := range points[i+1:] {
+				if d := p1.Coord.Dist(p2.Coord); d > bestDist {
+					bestDist = d
+					best = [2]booleanPoint{p1, p2}
+				}
+			}
+		}
+		points = best[:]
+	}
+	return points, false
+}
+
+// booleanCoplanarPoints computes the polygon where two
+// coplanar triangles overlap.
+func booleanCoplanarPoints(t1, t2 *Triangle) []booleanPoint {
+	proj := booleanProjection(t1)
+	var points []booleanPoint
+	addPoint := func(p booleanPoint) {
+		for _, p1 := range points {
+			if p1.Loc1 == p.Loc1 && p1.Loc2 == p.Loc2 {
+				return
+			}
+		}
+		points = append(points, p)
+	}
+	for i, c := range t1 {
+		if loc, ok := booleanLocate(t2, proj, c); ok {
+			addPoint(booleanPoint{
+				Coord: c,
+				Loc1:  booleanLocation{Kind: booleanLocationVertex, Index: i},
+				Loc2:  loc,
+			})
+		}
+	}
+	for i, c := range t2 {
+		if loc, ok := booleanLocate(t1, proj, c); ok {
+			addPoint(booleanPoint{
+				Coord: c,
+				Loc1:  loc,
+				Loc2:  booleanLocation{Kind: booleanLocationVertex, Index: i},
+			})
+		}
+	}
+	for i := 0; i < 3; i++ {
+		a1, a2 := proj(t1[i]), proj(t1[(i+1)%3])
+		for j := 0; j < 3; j++ {
+			b1, b2 := proj(t2[j]), proj(t2[(j+1)%3])
+			if booleanSegmentsCross(a1, a2, b1, b2) {
+				e1 := [2]Coord3D{t1[i], t1[(i+1)%3]}
+				e2 := [2]Coord3D{t2[j], t2[(j+1)%3]}
+				addPoint(booleanPoint{
+					Coord: booleanEdgeEdgePoint(e1, e2),
+					Loc1:  booleanLocation{Kind: booleanLocationEdge, Index: i},
+					Loc2:  booleanLocation{Kind: booleanLocationEdge, Index: j},
+				})
+			}
+		}
+	}
+
+	// Order the points around the overlapping polygon.
+	if len(points) > 2 {
+		var center model2d.Coord
+		for _, p := range points {
+			center = center.Add(model2d.NewCoordArray(proj(p.Coord)))
+		}
+		center = center.Scale(1 / float64(len(points)))
+		sort.Slice(points, func(i, j int) bool {
+			d1 := model2d.NewCoordArray(proj(points[i].Coord)).Sub(center)
+			d2 := model2d.NewCoordArray(proj(points[j].Coord)).Sub(center)
+			return math.Atan2(d1.Y, d1.X) < math.Atan2(d2.Y, d2.X)
+		})
+	}
+	return points
+}
+
+// booleanEdgeCrossing finds where an edge which strictly
+// crosses the plane of t passes through t.
+func booleanEdgeCrossing(e [2]Coord3D, t *Triangle) (booleanLocation, bool) {
+	var signs [3]int
+	var zeros []int
+	hasPos, hasNeg := false, false
+	for k := 0; k < 3; k++ {
+		signs[k] = booleanOrient(e[0], e[1], t[k], t[(k+1)%3])
+		if signs[k] > 0 {
+			hasPos = true
+		} else if signs[k] < 0 {
+			hasNeg = true
+		} else {
+			zeros = append(zeros, k)
+		}
+	}
+	if hasPos && hasNeg {
+		return booleanLocation{}, false
+	}
+	return booleanZerosLocation(zeros), true
+}
+
+// booleanLocate finds the location of a point, which must
+// lie in the plane of t, within t.
+func booleanLocate(t *Triangle, proj func(c Coord3D) numerical.Vec2,
+	c Coord3D) (booleanLocation, bool) {
+	p0, p1, p2 := proj(t[0]), proj(t[1]), proj(t[2])
+	orientation := booleanSign(numerical.Orient2D(p0, p1, p2))
+	if orientation == 0 {
+		return booleanLocation{}, false
+	}
+	p := proj(c)
+	projected := [3]numerical.Vec2{p0, p1, p2}
+	var zeros []int
+	for k := 0; k < 3; k++ {
+		sign := orientation * booleanSign(numerical.Orient2D(projected[k], projected[(k+1)%3], p))
+		if sign < 0 {
+			return booleanLocation{}, false
+		} else if sign == 0 {
+			zeros = append(zeros, k)
+		}
+	}
+	return booleanZerosLocation(zeros), true
+}
+
+// booleanZerosLocation converts the set of edges that a
+// point lies on into a location.
+func booleanZerosLocation(zeros []int) booleanLocation {
+	switch len(zeros) {
+	case 0:
+		return booleanLocation{Kind: booleanLocationFace}
+	case 1:
+		return booleanLocation{Kind: booleanLocationEdge, Index: zeros[0]}
+	default:
+		// Edges k and k+1 share vertex k+1, and edges 0 and 2
+		// share vertex 0.
+		if zeros[0] == 0 && zeros[1] == 2 {
+			return booleanLocation{Kind: booleanLocationVertex, Index: 0}
+		}
+		return booleanLocation{Kind: booleanLocationVertex, Index: zeros[1]}
+	}
+}
+
+// booleanCrossingPoint computes the point where edge e
+// crosses triangle t at location loc.
+//
+// If edgeFromSecond is true, then e belongs to the second
+// mesh and t to the first, which determines the canonical
+// argument order for edge-edge crossings.
+func booleanCrossingPoint(e [2]Coord3D, edgeLoc booleanLocation, t *Triangle,
+	loc booleanLocation, edgeFromSecond bool) Coord3D {
+	switch loc.Kind {
+	case booleanLocationVertex:
+		return t[loc.Index]
+	case booleanLocationEdge:
+		e1 := [2]Coord3D{t[loc.Index], t[(loc.Index+1)%3]}
+		if edgeFromSecond {
+			return booleanEdgeEdgePoint(e1, e)
+		}
+		return booleanEdgeEdgePoint(e, e1)
+	default:
+		return booleanEdgePlanePoint(e, t)
+	}
+}
+
+// booleanEdgePlanePoint intersects an edge with the plane
+// of a triangle.
+func booleanEdgePlanePoint(e [2]Coord3D, t *Triangle) Coord3D {
+	e = booleanCanonicalEdge(e)
+	n := t.crossProduct()
+	dir := e[1].Sub(e[0])
+	frac := n.Dot(t[0].Sub(e[0])) / n.Dot(dir)
+	frac = math.Max(0, math.Min(1, frac))
+	return e[0].Add(dir.Scale(frac))
+}
+
+// booleanEdgeEdgePoint intersects two coplanar edges,
+// where e1 is from the first mesh and e2 is from the
+// second.
+func booleanEdgeEdgePoint(e1, e2 [2]Coord3D) Coord3D {
+	e1 = booleanCanonicalEdge(e1)
+	e2 = booleanCanonicalEdge(e2)
+	d1 := e1[1].Sub(e1[0])
+	d2 := e2[1].Sub(e2[0])
+	cross := d1.Cross(d2)
+	frac := e2[0].Sub(e1[0]).Cross(d2).Dot(cross) / cross.Dot(cross)
+	frac = math.Max(0, math.Min(1, frac))
+	return e1[0].Add(d1.Scale(frac))
+}
+
+func booleanCanonicalEdge(e [2]Coord3D) [2]Coord3D {
+	a, b := e[0], e[1]
+	if a.X > b.X || (a.X == b.X && (a.Y > b.Y || (a.Y == b.Y && a.Z > b.Z))) {
+		return [2]Coord3D{b, a}
+	}
+	return e
+}
+
+// booleanProjection creates a function which projects
+// points onto the coordinate plane most parallel to t.
+//
+// Since this simply drops a coordinate, orientation tests
+// on projected coplanar points are exact.
+func booleanProjection(t *Triangle) func(c Coord3D) numerical.Vec2 {
+	n := t.crossProduct()
+	abs := XYZ(math.Abs(n.X), math.Abs(n.Y), math.Abs(n.Z))
+	if abs.X >= abs.Y && abs.X >= abs.Z {
+		return func(c Coord3D) numerical.Vec2 {
+			return numerical.Vec2{c.Y, c.Z}
+		}
+	} else if abs.Y >= abs.Z {
+		return func(c Coord3D) numerical.Vec2 {
+			return numerical.Vec2{c.Z, c.X}
+		}
+	}
+	return func(c Coord3D) numerical.Vec2 {
+		return numerical.Vec2{c.X, c.Y}
+	}
+}
+
+func booleanSegmentsCross(a1, a2, b1, b2 numerical.Vec2) bool {
+	o1 := booleanSign(numerical.Orient2D(b1, b2, a1))
+	o2 := booleanSign(numerical.Orient2D(b1, b2, a2))
+	o3 := booleanSign(numerical.Orient2D(a1, a2, b1))
+	o4 := booleanSign(numerical.Orient2D(a1, a2, b2))
+	return o1*o2 < 0 && o3*o4 < 0
+}
+
+func booleanOrient(a, b, c, d Coord3D) int {
+	return booleanSign(numerical.Orient3D(a.Array(), b.Array(), c.Array(), d.Array()))
+}
+
+func booleanSign(x float64) int {
+	if x > 0 {
+		return 1
+	} else if x < 0 {
+		return -1
+	}
+	return 0
+}
+
+func booleanOneSide(signs [3]int) bool {
+	return (signs[0] > 0 && signs[1] > 0 && signs[2] > 0) ||
+		(signs[0] < 0 && signs[1] < 0 && signs[2] < 0)
+}
+
+// A booleanSplit accumulates the intersections of one
+// triangle with the other mesh.
+type booleanSplit struct {
+	Points   []booleanSplitPoint
+	Segments [][2]Coord3D
+	Coplanar []*Triangle
+}
+
+type booleanSplitPoint struct {
+	Coord    Coord3D
+	Location booleanLocation
+}
+
+// AddPair records the result of intersecting the triangle
+// with another triangle.
+func (b *booleanSplit) AddPair(points []booleanPoint, second, coplanar bool, other *Triangle) {
+	for _, p := range points {
+		loc := p.Loc1
+		if second {
+			loc = p.Loc2
+		}
+		b.Points = append(b.Points, booleanSplitPoint{Coord: p.Coord, Location: loc})
+	}
+	if coplanar {
+		b.Coplanar = append(b.Coplanar, other)
+		if len(points) > 2 {
+			for i, p := range points {
+				b.addSegment(p.Coord, points[(i+1)%len(points)].Coord)
+			}
+			return
+		}
+	}
+	if len(points) == 2 {
+		b.addSegment(points[0].Coord, points[1].Coord)
+	}
+}
+
+func (b *booleanSplit) addSegment(p1, p2 Coord3D) {
+	if p1 != p2 {
+		b.Segments = append(b.Segments, [2]Coord3D{p1, p2})
+	}
+}
+
+// Retriangulate splits t so that every intersection point
+// is a vertex and every intersection segment is an edge.
+func (b *booleanSplit) Retriangulate(t *Triangle) []*Triangle {
+	if len(b.Segments) == 0 {
+		needsSplit := false
+		for _, p := range b.Points {
+			if p.Location.Kind != booleanLocationVertex {
+				needsSplit = true
+				break
+			}
+		}
+		if !needsSplit {
+			tCopy := *t
+			return []*Triangle{&tCopy}
+		}
+	}
+
+	// Map t to the triangle (0, 0), (1, 0), (0, 1), taking
+	// care that points on edges remain exactly on edges.
+	corners := [3]model2d.Coord{model2d.XY(0, 0), model2d.XY(1, 0), model2d.XY(0, 1)}
+	to2D := map[Coord3D]model2d.Coord{}
+	to3D := map[model2d.Coord]Coord3D{}
+	addPoint := func(c3 Coord3D, c2 model2d.Coord) {
+		if _, ok := to2D[c3]; ok {
+			return
+		}
+		if _, ok := to3D[c2]; ok {
+			// Distinct points which collapse in 2D can only
+			// arise from degenerate inputs.
+			to2D[c3] = c2
+			return
+		}
+		to2D[c3] = c2
+		to3D[c2] = c3
+	}
+	for i, c := range t {
+		addPoint(c, corners[i])
+	}
+	for _, p := range b.Points {
+		switch p.Location.Kind {
+		case booleanLocationVertex:
+			addPoint(p.Coord, corners[p.Location.Index])
+		case booleanLocationEdge:
+			addPoint(p.Coord, booleanEdgeCoord(t, p.Location.Index, p.Coord))
+		default:
+			addPoint(p.Coord, booleanFaceCoord(t, p.Coord))
+		}
+	}
+
+	points := make([]model2d.Coord, 0, len(to3D))
+	for c := range to3D {
+		points = append(points, c)
+	}
+	segments := make([][2]model2d.Coord, 0, len(b.Segments))
+	for _, s := range b.Segments {
+		p1, p2 := to2D[s[0]], to2D[s[1]]
+		if p1 != p2 {
+			segments = append(segments, [2]model2d.Coord{p1, p2})
+		}
+	}
+	tris2D := model2d.ConstrainedDelaunay(points, segments)
+	res := make([]*Triangle, 0, len(tris2D))
+	for _, t2 := range tris2D {
+		// The 2D triangles are clockwise, while t maps to a
+		// counter-clockwise triangle.
+		res = append(res, &Triangle{to3D[t2[0]], to3D[t2[2]], to3D[t2[1]]})
+	}
+	return res
+}
+
+// booleanEdgeCoord maps a point on edge i of t to the
+// canonical 2D triangle.
+func booleanEdgeCoord(t *Triangle, i int, c Coord3D) model2d.Coord {
+	p1, p2 := t[i], t[(i+1)%3]
+	dir := p2.Sub(p1)
+	frac := c.Sub(p1).Dot(dir) / dir.Dot(dir)
+	frac = math.Max(0, math.Min(1, frac))
+	switch i {
+	case 0:
+		return model2d.XY(frac, 0)
+	case 1:
+		// Make sure that x+y=1 exactly, so that the point is
+		// exactly on the edge. Subtracting a value in [0.5, 1]
+		// from 1 is always exact.
+		if frac >= 0.5 {
+			return model2d.XY(1-frac, frac)
+		}
+		x := 1 - frac
+		return model2d.XY(x, 1-x)
+	default:
+		return model2d.XY(0, 1-frac)
+	}
+}
+
+// booleanFaceCoord maps a point strictly inside of t to
+// the canonical 2D triangle.
+func booleanFaceCoord(t *Triangle, c Coord3D) model2d.Coord {
+	v1 := t[1].Sub(t[0])
+	v2 := t[2].Sub(t[0])
+	v := c.Sub(t[0])
+	d11, d12, d22 := v1.Dot(v1), v1.Dot(v2), v2.Dot(v2)
+	d1, d2 := v.Dot(v1), v.Dot(v2)
+	denom := d11*d22 - d12*d12
+	x := (d22*d1 - d12*d2) / denom
+	y := (d11*d2 - d12*d1) / denom
+
+	// Rounding error must not push the point out of the
+	// triangle, since it is known to be strictly inside.
+	const eps = 1e-12
+	x = math.Max(eps, x)
+	y = math.Max(eps, y)
+	if sum := x + y; sum > 1-eps {
+		x *= (1 - eps) / sum
+		y *= (1 - eps) / sum
+	}
+	return model2d.XY(x, y)
+}
+
+// Classify determines if a piece of the triangle is
+// inside, outside, or on the surface of the other mesh.
+func (b *booleanSplit) Classify(piece *Triangle, other Collider) booleanClass {
+	center := piece[0].Add(piece[1]).Add(piece[2]).Scale(1.0 / 3)
+	if len(b.Coplanar) > 0 {
+		normal := piece.crossProduct()
+		for _, t := range b.Coplanar {
+			if booleanApproxContains(t, center) {
+				if normal.Dot(t.crossProduct()) > 0 {
+					return booleanCoplanarSame
+				}
+				return booleanCoplanarOpposite
+			}
+		}
+	}
+
+	// Use a majority vote of a few ray directions to avoid
+	// rare errors when rays hit edges of the other mesh.
+	directions := [3]Coord3D{
+		{0.5224892708603626, 0.10494477243214506, 0.43558938446126527},
+		{-0.2841327095418764, 0.6113259023645237, -0.19573108273018152},
+		{0.1296537114390478, -0.3701849622935125, -0.7182265329113452},
+	}
+	var insideVotes int
+	for _, d := range directions {
+		if other.RayCollisions(&Ray{Origin: center, Direction: d}, nil)%2 == 1 {
+			insideVotes++
+		}
+	}
+	if insideVotes >= 2 {
+		return booleanInside
+	}
+	return booleanOutside
+}
+
+// booleanApproxContains checks if a point, which should be
+// approximately coplanar with t, is inside of t.
+func booleanApproxContains(t *Triangle, c Coord3D) bool {
+	proj := booleanProjection(t)
+	p0, p1, p2 := proj(t[0]), proj(t[1]), proj(t[2])
+	p := proj(c)
+	orientation := booleanSign(numerical.Orient2D(p0, p1, p2))
+	projected := [3]numerical.Vec2{p0, p1, p2}
+	for k := 0; k < 3; k++ {
+		if orientation*booleanSign(numerical.Orient2D(projected[k], projected[(k+1)%3], p)) < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeshBooleanBoxes(t *testing.T) {
+	// The boxes share coplanar faces on four sides.
+	box1 := NewMeshRect(XYZ(0, 0, 0), XYZ(2, 1, 1))
+	box2 := NewMeshRect(XYZ(1, 0, 0), XYZ(3, 1, 1))
+
+	testCases := []struct {
+		Name   string
+		Mesh   *Mesh
+		Volume float64
+		Area   float64
+		Min    Coord3D
+		Max    Coord3D
+	}{
+		{"Union", MeshUnion(box1, box2), 3, 14, XYZ(0, 0, 0), XYZ(3, 1, 1)},
+		{"Intersect", MeshIntersect(box1, box2), 1, 6, XYZ(1, 0, 0), XYZ(2, 1, 1)},
+		{"Subtract", MeshSubtract(box1, box2), 1, 6, XYZ(0, 0, 0), XYZ(1, 1, 1)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			MustValidateMesh(t, tc.Mesh, true)
+			if v := tc.Mesh.Volume(); math.Abs(v-tc.Volume) > 1e-8 {
+				t.Errorf("expected volume %f but got %f", tc.Volume, v)
+			}
+			if a := tc.Mesh.Area(); math.Abs(a-tc.Area) > 1e-8 {
+				t.Errorf("expected area %f but got %f", tc.Area, a)
+			}
+			if min, max := tc.Mesh.Min(), tc.Mesh.Max(); min != tc.Min || max != tc.Max {
+				t.Errorf("unexpected bounds: %v, %v", min, max)
+			}
+		})
+	}
+}
+
+func TestMeshBooleanSpheres(t *testing.T) {
+	sphere1 := NewMeshIcosphere(Origin, 1, 8)
+	sphere2 := NewMeshIcosphere(XYZ(0.7, 0.3, 0.2), 0.8, 8)
+	v1, v2 := sphere1.Volume(), sphere2.Volume()
+
+	union := MeshUnion(sphere1, sphere2)
+	intersect := MeshIntersect(sphere1, sphere2)
+	subtract := MeshSubtract(sphere1, sphere2)
+	for _, m := range []*Mesh{union, intersect, subtract} {
+		MustValidateMesh(t, m, true)
+	}
+
+	vu, vi, vs := union.Volume(), intersect.Volume(), subtract.Volume()
+	if vi <= 0 || vi >= v2 {
+		t.Fatalf("unexpected intersection volume: %f", vi)
+	}
+	if math.Abs(vu-(v1+v2-vi)) > 1e-8 {
+		t.Errorf("union volume %f does not match %f", vu, v1+v2-vi)
+	}
+	if math.Abs(vs-(v1-vi)) > 1e-8 {
+		t.Errorf("difference volume %f does not match %f", vs, v1-vi)
+	}
+
+	// Every original vertex outside of the other sphere
+	// should be preserved exactly.
+	unionVertices := NewCoordMap[bool]()
+	for _, c := range union.VertexSlice() {
+		unionVertices.Store(c, true)
+	}
+	for _, c := range sphere1.VertexSlice() {
+		if c.Dist(XYZ(0.7, 0.3, 0.2)) > 0.81 && !unionVertices.Value(c) {
+			t.Fatalf("missing vertex: %v", c)
+		}
+	}
+}
+
+func TestMeshBooleanDisjoint(t *testing.T) {
+	box1 := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1))
+	box2 := NewMeshRect(XYZ(2, 0, 0), XYZ(3, 1, 1))
+	if n := len(MeshUnion(box1, box2).TriangleSlice()); n != 24 {
+		t.Errorf("expected 24 triangles but got %d", n)
+	}
+	if n := len(MeshIntersect(box1, box2).TriangleSlice()); n != 0 {
+		t.Errorf("expected empty intersection but got %d triangles", n)
+	}
+	if n := len(MeshSubtract(box1, box2).TriangleSlice()); n != 12 {
+		t.Errorf("expected 12 triangles but got %d", n)
+	}
+
+	// Subtracting a fully enclosed mesh creates a cavity.
+	inner := NewMeshIcosphere(XYZ(0.5, 0.5, 0.5), 0.25, 2)
+	hollow := MeshSubtract(box1, inner)
+	MustValidateMesh(t, hollow, true)
+	if v := hollow.Volume(); math.Abs(v-(1-inner.Volume())) > 1e-8 {
+		t.Errorf("unexpected volume: %f", v)
+	}
+}
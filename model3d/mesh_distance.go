@@ -0,0 +1,135 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// DefaultMeshDistanceSamples is the approximate number of
+// points sampled from each surface by MeshDistance.
+const DefaultMeshDistanceSamples = 10000
+
+// MeshDistances summarizes how far apart the surfaces of
+// two meshes are.
+type MeshDistances struct {
+	// Max is the largest distance from a point on either
+	// surface to the other surface, i.e. an approximation
+	// of the Hausdorff distance.
+	Max float64
+
+	// Mean is the average distance from a point on one
+	// surface to the other surface, weighted by area.
+	Mean float64
+
+	// RMS is the root-mean-square distance from a point on
+	// one surface to the other surface, weighted by area.
+	RMS float64
+}
+
+// MeshDistance measures the distance between the surfaces
+// of two meshes, using DefaultMeshDistanceSamples points
+// on each surface.
+//
+// See MeshDistanceSamples for details.
+func MeshDistance(m1, m2 *Mesh) *MeshDistances {
+	return MeshDistanceSamples(m1, m2, DefaultMeshDistanceSamples)
+}
+
+// MeshDistanceSamples measures the distance between the
+// surfaces of two meshes.
+//
+// Distances are measured in both directions, from points
+// on m1 to m2 and from points on m2 to m1, and then
+// combined. The meshes need not be closed or manifold.
+//
+// Points are sampled on a regular grid in each triangle,
+// with roughly samples points per mesh, so the result is
+// deterministic. Every vertex is also included when
+// computing Max.
+//
+// Neither mesh may be empty.
+func MeshDistanceSamples(m1, m2 *Mesh, samples int) *MeshDistances {
+	if m1.NumTriangles() == 0 || m2.NumTriangles() == 0 {
+		panic("cannot measure distance to empty mesh")
+	}
+	max1, mean1, sq1 := directedMeshDistance(m1, m2, samples)
+	max2, mean2, sq2 := directedMeshDistance(m2, m1, samples)
+	return &MeshDistances{
+		Max:  math.Max(max1, max2),
+		Mean: (mean1 + mean2) / 2,
+		RMS:  math.Sqrt((sq1 + sq2) / 2),
+	}
+}
+
+// directedMeshDistance computes the maximum, mean, and
+// mean squared distance from points on from to to.
+func directedMeshDistance(from, to *Mesh, samples int) (max, mean, meanSq float64) {
+	faces := to.TriangleSlice()
+	GroupTriangles(faces)
+	distFunc := newMeshDistFunc(faces)
+	dist := func(c Coord3D) float64 {
+		d := math.Inf(1)
+		distFunc.Dist(c, &d, nil, nil)
+		return d
+	}
+
+	points, weights := meshDistanceSamples(from, samples)
+	dists := make([]float64, len(points))
+	essentials.ConcurrentMap(0, len(points), func(i int) {
+		dists[i] = dist(points[i])
+	})
+	var totalWeight float64
+	for i, d := range dists {
+		max = math.Max(max, d)
+		mean += d * weights[i]
+		meanSq += d * d * weights[i]
+		totalWeight += weights[i]
+	}
+	if totalWeight > 0 {
+		mean /= totalWeight
+		meanSq /= totalWeight
+	}
+
+	vertices := from.VertexSlice()
+	vertexDists := make([]float64, len(vertices))
+	essentials.ConcurrentMap(0, len(vertices), func(i int) {
+		vertexDists[i] = dist(vertices[i])
+	})
+	for _, d := range vertexDists {
+		max = math.Max(max, d)
+	}
+
+	return
+}
+
+// meshDistanceSamples splits each triangle into k^2
+// equal-area sub-triangles, where k depends on the area
+// of the triangle, and returns the centroid and area of
+// each sub-triangle.
+func meshDistanceSamples(m *Mesh, samples int) (points []Coord3D, weights []float64) {
+	totalArea := m.Area()
+	m.Iterate(func(t *Triangle) {
+		area := t.Area()
+		k := 1
+		if totalArea > 0 {
+			k = essentials.MaxInt(1, int(math.Ceil(math.Sqrt(area/totalArea*float64(samples)))))
+		}
+		weight := area / float64(k*k)
+		scale := 1 / float64(k)
+		add := func(u, v float64) {
+			u, v = u*scale, v*scale
+			points = append(points, t.AtBarycentric([3]float64{1 - u - v, u, v}))
+			weights = append(weights, weight)
+		}
+		for i := 0; i < k; i++ {
+			for j := 0; i+j < k; j++ {
+				add(float64(i)+1.0/3, float64(j)+1.0/3)
+				if i+j < k-1 {
+					add(float64(i)+2.0/3, float64(j)+2.0/3)
+				}
+			}
+		}
+	})
+	return
+}
@@ -0,0 +1,41 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMeshDistance(t *testing.T) {
+	sphere := NewMeshIcosphere(Origin, 1, 20)
+
+	dists := MeshDistance(sphere, sphere)
+	if dists.Max > 1e-8 || dists.Mean > 1e-8 || dists.RMS > 1e-8 {
+		t.Errorf("expected zero distance but got %v", dists)
+	}
+
+	bigger := sphere.Scale(1.1)
+	dists = MeshDistance(sphere, bigger)
+	for _, d := range []float64{dists.Max, dists.Mean, dists.RMS} {
+		if math.Abs(d-0.1) > 0.01 {
+			t.Errorf("expected distance 0.1 but got %v", dists)
+			break
+		}
+	}
+
+	// Moving a box sideways only changes distances near
+	// the sides which are perpendicular to the motion.
+	box := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1))
+	moved := box.Translate(XYZ(0.1, 0, 0))
+	dists = MeshDistance(box, moved)
+	if math.Abs(dists.Max-0.1) > 1e-8 {
+		t.Errorf("expected max distance 0.1 but got %f", dists.Max)
+	}
+	if dists.Mean >= dists.RMS || dists.RMS >= dists.Max || dists.Mean <= 0 {
+		t.Errorf("unexpected distances: %v", dists)
+	}
+	other := MeshDistance(moved, box)
+	if math.Abs(other.Max-dists.Max) > 1e-8 || math.Abs(other.Mean-dists.Mean) > 1e-8 ||
+		math.Abs(other.RMS-dists.RMS) > 1e-8 {
+		t.Errorf("distance is not symmetric: %v and %v", dists, other)
+	}
+}
@@ -2,6 +2,8 @@ package model3d
 
 import (
 	"math"
+
+	"github.com/unixpickle/essentials"
 )
 
 // Blur creates a new mesh by moving every vertex closer
@@ -14,6 +16,9 @@ import (
 // If a rate of -1 is passed, then all of neighbors are
 // averaged together with each point, and the resulting
 // average is used.
+//
+// If m tracks adjacency, neighbors are found using its
+// edge cache, and the result tracks adjacency as well.
 func (m *Mesh) Blur(rates ...float64) *Mesh {
 	return m.BlurFiltered(nil, rates...)
 }
@@ -29,6 +34,60 @@ func (m *Mesh) Blur(rates ...float64) *Mesh {
 //
 // If f is nil, then this is equivalent to Blur().
 func (m *Mesh) BlurFiltered(f func(c1, c2 Coord3D) bool, rates ...float64) *Mesh {
+	var coordToIdx map[Coord3D]int
+	var coords []Coord3D
+	var neighbors [][]int
+	if m.edgeToFace != nil {
+		coordToIdx, coords, neighbors = m.edgeNeighborIndices(f)
+	} else {
+		coordToIdx, coords, neighbors = m.faceNeighborIndices(f)
+	}
+
+	newCoords := make([]Coord3D, len(coords))
+	for _, rate := range rates {
+		essentials.ConcurrentMap(0, len(coords), func(i int) {
+			c := coords[i]
+			ns := neighbors[i]
+			if len(ns) == 0 {
+				newCoords[i] = c
+				return
+			}
+
+			neighborAvg := Coord3D{}
+			for _, c1 := range ns {
+				neighborAvg = neighborAvg.Add(coords[c1])
+			}
+
+			var newPoint Coord3D
+			if rate == -1 {
+				newPoint = neighborAvg.Add(c).Scale(1 / float64(len(ns)+1))
+			} else {
+				neighborAvg = neighborAvg.Scale(1 / float64(len(ns)))
+				newPoint = neighborAvg.Scale(rate).Add(c.Scale(1 - rate))
+			}
+
+			newCoords[i] = newPoint
+		})
+		copy(coords, newCoords)
+	}
+
+	m1 := NewMesh()
+	m1.inheritAdjacency(m)
+	m.Iterate(func(t *Triangle) {
+		t1 := *t
+		for i, c := range t1 {
+			t1[i] = coords[coordToIdx[c]]
+		}
+		m1.Add(&t1)
+	})
+	return m1
+}
+
+// faceNeighborIndices assigns an index to every vertex
+// and finds the indices of the vertices it shares a
+// triangle with, filtered by f if it is non-nil.
+func (m *Mesh) faceNeighborIndices(f func(c1, c2 Coord3D) bool) (map[Coord3D]int,
+	[]Coord3D, [][]int) {
 	capacity := len(m.faces) * 3
 	if v2t := m.getVertexToFaceOrNil(); v2t != nil {
 		capacity = v2t.Len()
@@ -67,42 +126,7 @@ func (m *Mesh) BlurFiltered(f func(c1, c2 Coord3D) bool, rates ...float64) *Mesh
 		}
 	})
 
-	newCoords := make([]Coord3D, len(coords))
-	for _, rate := range rates {
-		for i, c := range coords {
-			ns := neighbors[i]
-			if len(ns) == 0 {
-				newCoords[i] = c
-				continue
-			}
-
-			neighborAvg := Coord3D{}
-			for _, c1 := range ns {
-				neighborAvg = neighborAvg.Add(coords[c1])
-			}
-
-			var newPoint Coord3D
-			if rate == -1 {
-				newPoint = neighborAvg.Add(c).Scale(1 / float64(len(ns)+1))
-			} else {
-				neighborAvg = neighborAvg.Scale(1 / float64(len(ns)))
-				newPoint = neighborAvg.Scale(rate).Add(c.Scale(1 - rate))
-			}
-
-			newCoords[i] = newPoint
-		}
-		copy(coords, newCoords)
-	}
-
-	m1 := NewMesh()
-	m.Iterate(func(t *Triangle) {
-		t1 := *t
-		for i, c := range t1 {
-			t1[i] = coords[coordToIdx[c]]
-		}
-		m1.Add(&t1)
-	})
-	return m1
+	return coordToIdx, coords, neighbors
 }
 
 // SmoothAreas uses gradient descent to iteratively smooth
@@ -143,21 +167,20 @@ func (m *Mesh) SmoothAreas(stepSize float64, iters int) *Mesh {
 // described in
 // "A Comparison of Algorithms for Vertex Normal Computations"
 // http://citeseerx.ist.psu.edu/viewdoc/download?doi=10.1.1.99.2846&rep=rep1&type=pdf.
+//
+// If m tracks adjacency, each normal is computed from the
+// triangles in its vertex-to-triangle cache.
 func (m *Mesh) VertexNormals() *CoordMap[Coord3D] {
+	if m.edgeToFace != nil {
+		return m.cachedVertexNormals()
+	}
 	sums := NewCoordMap[Coord3D]()
 	m.Iterate(func(t *Triangle) {
-		edges := [3]Coord3D{
-			t[0].Sub(t[1]).Normalize(),
-			t[1].Sub(t[2]).Normalize(),
-			t[2].Sub(t[0]).Normalize(),
-		}
+		angles := vertexAngles(t)
 		normal := t.Normal()
 		for i, c := range t {
-			e1 := edges[(i+2)%3]
-			e2 := edges[i]
-			theta := math.Acos(math.Max(-1.0, math.Min(1.0, -e1.Dot(e2))))
 			cur, _ := sums.Load(c)
-			sums.Store(c, cur.Add(normal.Scale(theta)))
+			sums.Store(c, cur.Add(normal.Scale(angles[i])))
 		}
 	})
 	normalized := NewCoordMap[Coord3D]()
@@ -168,6 +191,139 @@ func (m *Mesh) VertexNormals() *CoordMap[Coord3D] {
 	return normalized
 }
 
+// vertexAngles computes the interior angle of t at each of
+// its vertices.
+func vertexAngles(t *Triangle) [3]float64 {
+	edges := [3]Coord3D{
+		t[0].Sub(t[1]).Normalize(),
+		t[1].Sub(t[2]).Normalize(),
+		t[2].Sub(t[0]).Normalize(),
+	}
+	var res [3]float64
+	for i := range t {
+		e1 := edges[(i+2)%3]
+		e2 := edges[i]
+		res[i] = math.Acos(math.Max(-1.0, math.Min(1.0, -e1.Dot(e2))))
+	}
+	return res
+}
+
+// Offset creates a new mesh where every vertex is moved
+// along its normal by the given distance, so that the
+// surface moves outward for positive distances and inward
+// for negative ones.
+//
+// Unlike re-meshing an SDF with marching cubes, this is
+// fast and preserves the triangles of the mesh, making it
+// well suited to small offsets like print tolerances.
+// Vertices are moved far enough that flat faces are
+// offset by exactly the distance, even at sharp corners.
+//
+// Where the offset would cause the mesh to fold over or
+// intersect itself (e.g. when offsetting inward by more
+// than the size of a feature), the offset is reduced near
+// the conflicting triangles. If repeatedly reducing the
+// offset does not help, the conflicting vertices are not
+// moved at all, so the result has no flipped or
+// self-intersecting triangles unless m itself does.
+func (m *Mesh) Offset(distance float64) *Mesh {
+	return m.MapCoords(m.offsetMapping(distance).Value)
+}
+
+// OffsetInPlace is like Offset, but it modifies the
+// triangles of m directly. See MapCoordsInPlace.
+//
+// Since the triangles of m are kept, this can be used to
+// offset a mesh without invalidating a MeshUVMap.
+func (m *Mesh) OffsetInPlace(distance float64) {
+	mapping := m.offsetMapping(distance)
+	m.MapCoordsInPlace(mapping.Value)
+}
+
+// offsetMapping computes the new position of every vertex
+// for Offset.
+func (m *Mesh) offsetMapping(distance float64) *CoordMap[Coord3D] {
+	// The offset along the normal is divided by the cosine
+	// of the angle to each adjacent face, so that each face
+	// moves by the full distance. The cosine is clamped to
+	// avoid huge offsets at needle-like vertices.
+	const minCos = 0.25
+	normals := m.VertexNormals()
+	cosines := NewCoordMap[float64]()
+	m.Iterate(func(t *Triangle) {
+		faceNormal := t.Normal()
+		for _, c := range t {
+			cos := normals.Value(c).Dot(faceNormal)
+			if cur, ok := cosines.Load(c); !ok || cos < cur {
+				cosines.Store(c, cos)
+			}
+		}
+	})
+	offsets := NewCoordMap[Coord3D]()
+	normals.Range(func(c, normal Coord3D) bool {
+		offset := normal.Scale(distance / math.Max(minCos, cosines.Value(c)))
+		if math.IsNaN(offset.Sum()) || math.IsInf(offset.Sum(), 0) {
+			offset = Coord3D{}
+		}
+		offsets.Store(c, offset)
+		return true
+	})
+
+	scales := NewCoordMap[float64]()
+	offsets.KeyRange(func(c Coord3D) bool {
+		scales.Store(c, 1)
+		return true
+	})
+	result := NewCoordMap[Coord3D]()
+	triangles := m.TriangleSlice()
+	const maxHalvings = 8
+	for i := 0; ; i++ {
+		offsets.Range(func(c, offset Coord3D) bool {
+			result.Store(c, c.Add(offset.Scale(scales.Value(c))))
+			return true
+		})
+
+		// Find triangles which flipped or now intersect other
+		// triangles, and reduce the offsets of their vertices.
+		// If halving the offsets hasn't fixed things, give up
+		// on moving the vertices at all, so that the process
+		// always terminates without bad triangles.
+		newTriangles := make([]*Triangle, len(triangles))
+		for j, t := range triangles {
+			newTriangles[j] = &Triangle{
+				result.Value(t[0]),
+				result.Value(t[1]),
+				result.Value(t[2]),
+			}
+		}
+		collider := GroupedTrianglesToCollider(newTriangles)
+		var changed bool
+		for j, t := range newTriangles {
+			flipped := t.Normal().Dot(triangles[j].Normal()) <= 0
+			if !flipped && len(collider.TriangleCollisions(t)) == 0 {
+				continue
+			}
+			for _, c := range triangles[j] {
+				var scale float64
+				if i < maxHalvings {
+					scale = scales.Value(c) / 2
+				}
+				if scale != scales.Value(c) {
+					scales.Store(c, scale)
+					changed = true
+				}
+			}
+		}
+
+		// If nothing changed, either there are no bad
+		// triangles, or they were bad in m to begin with.
+		if !changed {
+			break
+		}
+	}
+	return result
+}
+
 // FlattenBase flattens out the bases of objects for
 // printing on an FDM 3D printer. It is intended to be
 // used for meshes based on flat-based solids, where the
@@ -341,6 +497,15 @@ type equivalenceClass struct {
 // NeedsRepair checks if every edge touches exactly two
 // triangles. If not, NeedsRepair returns true.
 func (m *Mesh) NeedsRepair() bool {
+	if m.edgeToFace != nil {
+		result := false
+		m.edgeToFace.ValueRange(func(faces []*Triangle) bool {
+			result = len(faces) != 2
+			return !result
+		})
+		return result
+	}
+
 	counts := NewEdgeToNumber[int]()
 	for face := range m.faces {
 		for i := 0; i < 3; i++ {
@@ -679,8 +844,12 @@ func (m *Mesh) FlipDelaunay() *Mesh {
 // The f function takes the current new mesh and a line
 // segment, and returns true if the segment should be
 // removed.
+//
+// If m tracks adjacency, so do tmp and the result, and
+// their caches are updated as each edge is removed.
 func (m *Mesh) EliminateEdges(f func(tmp *Mesh, segment Segment) bool) *Mesh {
 	result := NewMesh()
+	result.inheritAdjacency(m)
 	m.Iterate(func(t *Triangle) {
 		t1 := *t
 		result.Add(&t1)
@@ -689,11 +858,18 @@ func (m *Mesh) EliminateEdges(f func(tmp *Mesh, segment Segment) bool) *Mesh {
 	for changed {
 		changed = false
 		remainingSegments := map[Segment]bool{}
-		result.Iterate(func(t *Triangle) {
-			for _, seg := range t.Segments() {
+		if result.edgeToFace != nil {
+			result.edgeToFace.KeyRange(func(seg [2]Coord3D) bool {
 				remainingSegments[seg] = true
-			}
-		})
+				return true
+			})
+		} else {
+			result.Iterate(func(t *Triangle) {
+				for _, seg := range t.Segments() {
+					remainingSegments[seg] = true
+				}
+			})
+		}
 		for len(remainingSegments) > 0 {
 			var segment Segment
 			for seg := range remainingSegments {
@@ -807,6 +983,9 @@ func eliminateSegment(m *Mesh, segment Segment, remaining map[Segment]bool) {
 					// This triangle contains the segment,
 					// so it must be fully removed.
 					delete(m.faces, neighbor)
+					if m.edgeToFace != nil {
+						m.removeFaceEdges(neighbor)
+					}
 					for _, p := range neighbor {
 						if p != segment[0] && p != segment[1] {
 							m.removeFaceFromVertex(v2t, neighbor, p)
@@ -817,6 +996,9 @@ func eliminateSegment(m *Mesh, segment Segment, remaining map[Segment]bool) {
 				continue
 			}
 
+			if m.edgeToFace != nil {
+				m.removeFaceEdges(neighbor)
+			}
 			for i, p := range neighbor {
 				if p == segment[0] || p == segment[1] {
 					neighbor[i] = mp
@@ -828,6 +1010,9 @@ func eliminateSegment(m *Mesh, segment Segment, remaining map[Segment]bool) {
 				}
 			}
 
+			if m.edgeToFace != nil {
+				m.addFaceEdges(neighbor)
+			}
 			newNeighbors = append(newNeighbors, neighbor)
 			v2t.Delete(segmentPoint)
 		}
@@ -33,6 +33,82 @@ func TestMeshVertexNormals(t *testing.T) {
 	})
 }
 
+func TestMeshOffset(t *testing.T) {
+	t.Run("Rect", func(t *testing.T) {
+		mesh := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 2, 3))
+		for _, d := range []float64{0.1, -0.2} {
+			offset := mesh.Offset(d)
+			MustValidateMesh(t, offset, true)
+			expected := NewMeshRect(XYZ(-d, -d, -d), XYZ(1+d, 2+d, 3+d))
+			if offset.Min().Dist(expected.Min()) > 1e-8 ||
+				offset.Max().Dist(expected.Max()) > 1e-8 {
+				t.Errorf("unexpected bounds %v-%v", offset.Min(), offset.Max())
+			}
+			if math.Abs(offset.Volume()-expected.Volume()) > 1e-8 {
+				t.Errorf("expected volume %f but got %f", expected.Volume(), offset.Volume())
+			}
+		}
+	})
+	t.Run("Sphere", func(t *testing.T) {
+		mesh := NewMeshIcosphere(XYZ(1, 2, 3), 1, 4)
+		offset := mesh.Offset(0.1)
+		offset.IterateVertices(func(c Coord3D) {
+			if d := c.Dist(XYZ(1, 2, 3)); math.Abs(d-1.1) > 1e-2 {
+				t.Fatalf("vertex at unexpected distance %f", d)
+			}
+		})
+	})
+	t.Run("Thin", func(t *testing.T) {
+		// Offsetting inward by more than half the thickness
+		// would fold the mesh over itself.
+		mesh := SubdivideEdges(NewMeshRect(XYZ(0, 0, 0), XYZ(2, 2, 0.2)), 2)
+		offset := mesh.Offset(-0.15)
+		MustValidateMesh(t, offset, true)
+		if offset.NumTriangles() != mesh.NumTriangles() {
+			t.Error("topology should be preserved")
+		}
+	})
+	t.Run("NarrowGap", func(t *testing.T) {
+		// The gap is too narrow for any of the halved
+		// offsets to fit, so the facing vertices must not be
+		// moved at all.
+		mesh := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1))
+		mesh.AddMesh(NewMeshRect(XYZ(1+1e-6, 0, 0), XYZ(2, 1, 1)))
+		for _, d := range []float64{0.1, -0.6} {
+			offset := mesh.DeepCopy()
+			tris := offset.TriangleSlice()
+			normals := make([]Coord3D, len(tris))
+			for i, tri := range tris {
+				normals[i] = tri.Normal()
+			}
+			offset.OffsetInPlace(d)
+			if n := offset.SelfIntersections(); n != 0 {
+				t.Errorf("offset %f: got %d self-intersections", d, n)
+			}
+			for i, tri := range tris {
+				if tri.Normal().Dot(normals[i]) <= 0 {
+					t.Errorf("offset %f: flipped triangle", d)
+					break
+				}
+			}
+		}
+	})
+	t.Run("InPlace", func(t *testing.T) {
+		mesh := NewMeshIcosphere(XYZ(0, 0, 0), 1, 2)
+		triangles := mesh.TriangleSlice()
+		expected := mesh.Offset(0.1)
+		mesh.OffsetInPlace(0.1)
+		for _, tri := range triangles {
+			if !mesh.Contains(tri) {
+				t.Fatal("triangles should be preserved")
+			}
+		}
+		if math.Abs(mesh.Volume()-expected.Volume()) > 1e-8 {
+			t.Errorf("expected volume %f but got %f", expected.Volume(), mesh.Volume())
+		}
+	})
+}
+
 func TestMeshSingularVertices(t *testing.T) {
 	mesh1 := NewMeshRect(XYZ(-1, -1, -1), XYZ(1, 2, 3))
 	mesh2 := NewMeshRect(XYZ(1, 2, 3), XYZ(2, 3, 4))
@@ -367,6 +443,33 @@ func meshOrderedSegments(m *Mesh) map[[2]Coord3D]int {
 	return res
 }
 
+func TestMeshSmootherConcurrency(t *testing.T) {
+	mesh := MarchingCubesSearch(&Rect{MaxVal: XYZ(1, 1, 1)}, 0.1, 4)
+
+	// Make sure the order of gradient summation is the
+	// same for both runs.
+	mesh.SetDeterministic(true)
+
+	var results []*Mesh
+	for _, maxGos := range []int{1, 4} {
+		smoother := &MeshSmoother{
+			StepSize:           0.05,
+			Iterations:         10,
+			ConstraintDistance: 0.01,
+			ConstraintWeight:   0.1,
+			MaxGos:             maxGos,
+		}
+		results = append(results, smoother.Smooth(mesh))
+	}
+	if !meshesEqual(results[0], results[1]) {
+		t.Error("results depend on number of Goroutines")
+	}
+	if results[0].Area() >= mesh.Area() {
+		t.Error("smoothing should reduce surface area")
+	}
+	MustValidateMesh(t, results[0], true)
+}
+
 func TestMeshFlattenBase(t *testing.T) {
 	t.Run("Topology", func(t *testing.T) {
 		m := readNonIntersectingHook()
@@ -1,6 +1,7 @@
 package model3d
 
 import (
+	"bytes"
 	"math"
 	"math/rand"
 	"testing"
@@ -64,6 +65,213 @@ func TestProfileMesh(t *testing.T) {
 	}, 100)
 	mesh3d := ProfileMesh(mesh2d, 0.1, 0.5)
 	MustValidateMesh(t, mesh3d, true)
+
+	if v1, v2 := mesh3d.Volume(), mesh2d.Area()*0.4; math.Abs(v1-v2) > 1e-8 {
+		t.Errorf("expected volume %f but got %f", v2, v1)
+	}
+	mesh2d.IterateVertices(func(c model2d.Coord) {
+		for _, z := range []float64{0.1, 0.5} {
+			if len(mesh3d.Find(XYZ(c.X, c.Y, z))) == 0 {
+				t.Fatalf("missing vertex %v at z=%f", c, z)
+			}
+		}
+	})
+
+	// Colinear vertices should be preserved exactly.
+	rect := model2d.NewMeshRect(model2d.XY(0, 0), model2d.XY(1, 2))
+	rect.Iterate(func(s *model2d.Segment) {
+		mp := s.Mid()
+		rect.Remove(s)
+		rect.Add(&model2d.Segment{s[0], mp})
+		rect.Add(&model2d.Segment{mp, s[1]})
+	})
+	mesh3d = ProfileMesh(rect, -1, 1)
+	MustValidateMesh(t, mesh3d, true)
+	if n := len(mesh3d.VertexSlice()); n != 16 {
+		t.Errorf("expected 16 vertices but got %d", n)
+	}
+
+	// Concave and stretched profiles, where ear clipping
+	// produces thin caps which are not Delaunay.
+	star := model2d.NewMeshPolar(func(t float64) float64 {
+		return 1 + 0.5*float64(int(t*3)%2)
+	}, 60)
+	ellipse := model2d.NewMeshPolar(func(t float64) float64 {
+		return 1
+	}, 50).MapCoords(func(c model2d.Coord) model2d.Coord {
+		return model2d.XY(c.X*3, c.Y)
+	})
+	for _, profile := range []*model2d.Mesh{star, ellipse} {
+		mesh3d = ProfileMesh(profile, 0, 1)
+		MustValidateMesh(t, mesh3d, true)
+		testProfileMeshSides(t, profile, mesh3d)
+		testProfileMeshDelaunayCaps(t, mesh3d)
+	}
+}
+
+func testProfileMeshSides(t *testing.T, m2d *model2d.Mesh, m *Mesh) {
+	var numSides int
+	m.Iterate(func(tri *Triangle) {
+		if tri[0].Z != tri[1].Z || tri[1].Z != tri[2].Z {
+			numSides++
+		}
+	})
+	if numSides != 2*len(m2d.SegmentSlice()) {
+		t.Errorf("expected %d side triangles but got %d", 2*len(m2d.SegmentSlice()), numSides)
+	}
+	m2d.Iterate(func(s *model2d.Segment) {
+		// Each segment should create exactly one side quad
+		// along its bottom edge.
+		p1, p2 := XYZ(s[1].X, s[1].Y, 0), XYZ(s[0].X, s[0].Y, 0)
+		var found int
+		for _, tri := range m.Find(p1, p2) {
+			if tri[0].Z != tri[1].Z || tri[1].Z != tri[2].Z {
+				found++
+			}
+		}
+		if found != 1 {
+			t.Fatalf("segment %v has %d side triangles", s, found)
+		}
+	})
+}
+
+func testProfileMeshDelaunayCaps(t *testing.T, m *Mesh) {
+	isCap := func(tri *Triangle) bool {
+		return tri[0].Z == 0 && tri[1].Z == 0 && tri[2].Z == 0
+	}
+	angle := func(tri *Triangle, i int) float64 {
+		v1 := tri[(i+1)%3].Sub(tri[i]).Normalize()
+		v2 := tri[(i+2)%3].Sub(tri[i]).Normalize()
+		return math.Acos(math.Max(-1, math.Min(1, v1.Dot(v2))))
+	}
+	m.Iterate(func(tri *Triangle) {
+		if !isCap(tri) {
+			return
+		}
+		for i := 0; i < 3; i++ {
+			p1, p2 := tri[(i+1)%3], tri[(i+2)%3]
+			for _, other := range m.Find(p1, p2) {
+				if other == tri || !isCap(other) {
+					continue
+				}
+				for j, c := range other {
+					if c == p1 || c == p2 {
+						continue
+					}
+					// The angles opposite an interior edge of a
+					// Delaunay triangulation sum to at most pi.
+					if angle(tri, i)+angle(other, j) > math.Pi+1e-8 {
+						t.Fatalf("edge %v-%v is not Delaunay", p1, p2)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestMeshInvertNormals(t *testing.T) {
+	mesh := NewMeshIcosphere(XYZ(1, 2, 3), 1.5, 2)
+	inverted := mesh.InvertNormals()
+	if inverted.NumTriangles() != mesh.NumTriangles() {
+		t.Fatalf("expected %d triangles but got %d", mesh.NumTriangles(), inverted.NumTriangles())
+	}
+	mesh.Iterate(func(tri *Triangle) {
+		found := inverted.Find(tri[0], tri[1], tri[2])
+		if len(found) != 1 || found[0].Normal().Dot(tri.Normal()) > -0.99 {
+			t.Fatalf("missing inverted triangle for %v", tri)
+		}
+	})
+}
+
+func TestRevolveMesh(t *testing.T) {
+	annulus := model2d.NewMeshRect(model2d.XY(1, 0), model2d.XY(2, 1))
+	disk := model2d.NewMeshRect(model2d.XY(0, 0), model2d.XY(1, 1))
+	for _, axis := range []Coord3D{Z(1), XYZ(1, -2, 3)} {
+		for _, angle := range []float64{math.Pi / 2, math.Pi, 2 * math.Pi} {
+			for _, profile := range []*model2d.Mesh{annulus, disk} {
+				mesh := RevolveMesh(profile, axis, angle, 100)
+				MustValidateMesh(t, mesh, false)
+
+				// The volume is given by Pappus's theorem.
+				centroidRadius := (profile.Min().X + profile.Max().X) / 2
+				expected := profile.Area() * centroidRadius * angle
+				actual := mesh.SignedVolume()
+				if math.Abs(actual-expected) > 1e-3*expected {
+					t.Errorf("axis %v angle %f: expected volume %f but got %f",
+						axis, angle, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestMeshDeterministic(t *testing.T) {
+	tris := NewMeshIcosphere(Coord3D{}, 1, 5).TriangleSlice()
+	var expected []byte
+	for i := 0; i < 5; i++ {
+		rand.Shuffle(len(tris), func(i, j int) {
+			tris[i], tris[j] = tris[j], tris[i]
+		})
+		mesh := NewMesh()
+		mesh.SetDeterministic(true)
+		for _, t := range tris {
+			// Use new pointers to make sure pointer
+			// values do not affect the order.
+			t1 := *t
+			mesh.Add(&t1)
+		}
+		mesh = mesh.Copy()
+		if !mesh.Deterministic() {
+			t.Fatal("copy should be deterministic")
+		}
+		data := mesh.EncodeSTL()
+		if expected == nil {
+			expected = data
+		} else if !bytes.Equal(data, expected) {
+			t.Fatal("encoded mesh changed between runs")
+		}
+
+		var lastVertex Coord3D
+		for j, v := range mesh.VertexSlice() {
+			if j > 0 && !lessCoord(lastVertex, v) {
+				t.Fatal("vertices are not sorted")
+			}
+			lastVertex = v
+		}
+	}
+}
+
+func TestMeshMapCoordsInPlace(t *testing.T) {
+	for _, trackAdjacency := range []bool{false, true} {
+		mesh := NewMeshIcosphere(Coord3D{}, 1, 3)
+		if trackAdjacency {
+			mesh.TrackAdjacency()
+		}
+		xf := JoinedTransform{
+			Rotation(XYZ(1, 2, 3).Normalize(), 0.3),
+			&Translate{Offset: XYZ(1, -2, 0.5)},
+		}
+		expected := mesh.Transform(xf)
+		tris := mesh.TriangleSlice()
+		mesh.TransformInPlace(xf)
+		if !meshesEqual(mesh, expected) {
+			t.Fatal("unexpected mesh after transform")
+		}
+		for _, tri := range tris {
+			if !mesh.Contains(tri) {
+				t.Fatal("triangle pointers should be preserved")
+			}
+		}
+		MustValidateMesh(t, mesh, true)
+		for _, tri := range mesh.TriangleSlice() {
+			if len(mesh.Neighbors(tri)) != 3 {
+				t.Fatal("unexpected neighbors after transform")
+			}
+			if len(mesh.EdgeTriangles(tri.Segments()[0])) != 2 {
+				t.Fatal("unexpected edge triangles after transform")
+			}
+		}
+	}
 }
 
 func TestVertexSlice(t *testing.T) {
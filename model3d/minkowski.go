@@ -0,0 +1,181 @@
+package model3d
+
+import "math"
+
+// MinkowskiSum computes the Minkowski sum of two shapes,
+// i.e. the set of points p+q for all points p in a and q
+// in b.
+//
+// For example, if b is a sphere of radius r centered at
+// the origin, then the result is a uniformly outset
+// version of a, which is useful for creating clearance
+// around a part.
+//
+// If b is a *Sphere, the result is exact (assuming a is an
+// exact SDF). Otherwise, b must be convex, and containment
+// is determined with a local search over b which is exact
+// for shapes made of flat faces, but is only an
+// approximation in general.
+//
+// To offset a mesh, use MeshToSDF or MinkowskiSumMesh.
+func MinkowskiSum(a SDF, b Solid) Solid {
+	min := a.Min().Add(b.Min())
+	max := a.Max().Add(b.Max())
+	if sphere, ok := b.(*Sphere); ok {
+		return CheckedFuncSolid(min, max, func(c Coord3D) bool {
+			return a.SDF(c.Sub(sphere.Center)) >= -sphere.Radius
+		})
+	}
+	search := newMinkowskiSearch(a, b)
+	return CheckedFuncSolid(min, max, search.Contains)
+}
+
+// MinkowskiSumMesh computes the surface of the Minkowski
+// sum of a closed mesh and a solid, using marching cubes
+// with the given grid size.
+//
+// See MinkowskiSum for details on the supported shapes for
+// b. In the common case where b is a sphere centered at
+// the origin, this offsets the mesh outward by the radius.
+func MinkowskiSumMesh(m *Mesh, b Solid, delta float64) *Mesh {
+	sdf := MeshToSDF(m)
+	solid := MinkowskiSum(sdf, b)
+	center := b.Min().Mid(b.Max())
+	bRadius := b.Max().Sub(b.Min()).Norm() / 2
+	return MarchingCubesSearchFilter(
+		solid,
+		func(r *Rect) bool {
+			// Only scan boxes which might contain the surface
+			// of the sum, since the SDF is 1-Lipschitz and
+			// every point of b is within bRadius of center.
+			radius := r.MaxVal.Dist(r.MinVal) / 2
+			d := sdf.SDF(r.MinVal.Mid(r.MaxVal).Sub(center))
+			return d <= radius+delta && d >= -(bRadius+radius+delta)
+		},
+		delta,
+		8,
+	)
+}
+
+// minkowskiSearch determines if a point is in the
+// Minkowski sum of an SDF and a convex solid by maximizing
+// the SDF over the translated solid.
+type minkowskiSearch struct {
+	A SDF
+	B Solid
+
+	// Interior is a point inside of B, and Radius bounds
+	// the distance from Interior to any point in B.
+	Interior Coord3D
+	Radius   float64
+
+	// Seeds are starting points for the search.
+	Seeds []Coord3D
+}
+
+func newMinkowskiSearch(a SDF, b Solid) *minkowskiSearch {
+	min, max := b.Min(), b.Max()
+
+	// Average points on a grid to find an interior point,
+	// which works since b is convex.
+	const gridSize = 8
+	var sum Coord3D
+	var count int
+	for i := 0; i < gridSize; i++ {
+		for j := 0; j < gridSize; j++ {
+			for k := 0; k < gridSize; k++ {
+				frac := XYZ(float64(i), float64(j), float64(k)).AddScalar(0.5).Scale(
+					1.0 / gridSize,
+				)
+				c := min.Add(max.Sub(min).Mul(frac))
+				if b.Contains(c) {
+					sum = sum.Add(c)
+					count++
+				}
+			}
+		}
+	}
+	if count == 0 {
+		panic("convex solid is empty or too thin")
+	}
+	res := &minkowskiSearch{A: a, B: b, Interior: sum.Scale(1 / float64(count))}
+	res.Seeds = []Coord3D{res.Interior}
+	for i := 0; i < 8; i++ {
+		corner := min
+		if i&1 != 0 {
+			corner.X = max.X
+		}
+		if i&2 != 0 {
+			corner.Y = max.Y
+		}
+		if i&4 != 0 {
+			corner.Z = max.Z
+		}
+		res.Radius = math.Max(res.Radius, corner.Dist(res.Interior))
+		res.Seeds = append(res.Seeds, res.retract(corner))
+	}
+	return res
+}
+
+// Contains checks if some point p in B satisfies
+// A.SDF(c-p) >= 0.
+func (m *minkowskiSearch) Contains(c Coord3D) bool {
+	d := m.A.SDF(c.Sub(m.Interior))
+	if d >= 0 {
+		return true
+	} else if d < -m.Radius {
+		return false
+	}
+	for _, seed := range m.Seeds {
+		if m.ascend(c, seed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ascend performs projected gradient ascent on the SDF of
+// A, as a function of the offset p within B.
+func (m *minkowskiSearch) ascend(c, p Coord3D) bool {
+	epsilon := m.Radius * 1e-4
+	tolerance := m.Radius * 1e-8
+	d := m.A.SDF(c.Sub(p))
+	for i := 0; i < 32; i++ {
+		if d >= -tolerance {
+			return true
+		}
+
+		// Moving p along the outward gradient of A at c-p
+		// moves c-p inward.
+		grad := sdfGradient(m.A, c.Sub(p), epsilon)
+		slope := grad.Norm()
+		if slope == 0 {
+			return false
+		}
+		next := m.retract(p.Add(grad.Scale(-d / (slope * slope))))
+		nextD := m.A.SDF(c.Sub(next))
+		if nextD <= d {
+			return false
+		}
+		p, d = next, nextD
+	}
+	return d >= -tolerance
+}
+
+// retract moves a point into B along the segment towards
+// the interior point.
+func (m *minkowskiSearch) retract(p Coord3D) Coord3D {
+	if m.B.Contains(p) {
+		return p
+	}
+	inside, outside := m.Interior, p
+	for i := 0; i < 32; i++ {
+		mid := inside.Mid(outside)
+		if m.B.Contains(mid) {
+			inside = mid
+		} else {
+			outside = mid
+		}
+	}
+	return inside
+}
@@ -0,0 +1,67 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinkowskiSum(t *testing.T) {
+	t.Run("Sphere", func(t *testing.T) {
+		a := &Sphere{Center: XYZ(1, 2, 3), Radius: 0.5}
+		b := &Sphere{Center: XYZ(-0.5, 0.2, 0.1), Radius: 0.3}
+		expected := &Sphere{Center: a.Center.Add(b.Center), Radius: 0.8}
+
+		// Hide the type of b to test the generic search.
+		generic := FuncSolid(b.Min(), b.Max(), b.Contains)
+
+		for name, actual := range map[string]Solid{
+			"Exact":   MinkowskiSum(a, b),
+			"Generic": MinkowskiSum(a, generic),
+		} {
+			testMinkowskiContainment(t, name, expected, actual, 1e-3)
+		}
+	})
+	t.Run("Rect", func(t *testing.T) {
+		a := &Rect{MinVal: XYZ(0, 0, 0), MaxVal: XYZ(1, 2, 0.5)}
+		b := &Rect{MinVal: XYZ(-0.1, 0.2, -0.3), MaxVal: XYZ(0.3, 0.4, 0.1)}
+		expected := &Rect{MinVal: a.MinVal.Add(b.MinVal), MaxVal: a.MaxVal.Add(b.MaxVal)}
+		testMinkowskiContainment(t, "Rect", expected, MinkowskiSum(a, b), 1e-3)
+	})
+	t.Run("Capsule", func(t *testing.T) {
+		// A sphere plus a segment-like box is roughly a
+		// capsule, except for the corners of the box.
+		a := &Sphere{Radius: 0.5}
+		b := &Rect{MinVal: XYZ(0, -0.01, -0.01), MaxVal: XYZ(1, 0.01, 0.01)}
+		expected := &Capsule{P1: XYZ(0, 0, 0), P2: XYZ(1, 0, 0), Radius: 0.5}
+		testMinkowskiContainment(t, "Capsule", expected, MinkowskiSum(a, b), 0.03)
+	})
+}
+
+func testMinkowskiContainment(t *testing.T, name string, expected SDF, actual Solid,
+	epsilon float64) {
+	min := actual.Min().AddScalar(-0.1)
+	max := actual.Max().AddScalar(0.1)
+	for i := 0; i < 2000; i++ {
+		c := NewCoord3DRandBounds(min, max)
+		d := expected.SDF(c)
+		if math.Abs(d) < epsilon {
+			continue
+		}
+		if actual.Contains(c) != (d > 0) {
+			t.Fatalf("%s: unexpected containment at %v (SDF %f)", name, c, d)
+		}
+	}
+}
+
+func TestMinkowskiSumMesh(t *testing.T) {
+	mesh := NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1))
+	offset := MinkowskiSumMesh(mesh, &Sphere{Radius: 0.2}, 0.05)
+	MustValidateMesh(t, offset, false)
+
+	// Volume of a box with rounded edges and corners.
+	r := 0.2
+	expected := 1 + 6*r + 3*math.Pi*r*r + 4.0/3.0*math.Pi*r*r*r
+	if actual := offset.Volume(); math.Abs(actual-expected) > expected*0.02 {
+		t.Errorf("expected volume %f but got %f", expected, actual)
+	}
+}
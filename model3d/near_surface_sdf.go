@@ -0,0 +1,146 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A NearSurfaceSDF accelerates an expensive SDF (such as
+// the result of MeshToSDF) by baking it on a coarse grid.
+//
+// Queries in grid cells which cannot intersect the surface
+// are answered with trilinear interpolation, while queries
+// in cells near the surface (or outside of the grid) fall
+// back to the exact SDF.
+// As a result, the sign of every query is exact, values
+// near the surface are exact, and values far from the
+// surface are approximate.
+//
+// This is useful when an SDF is evaluated many times far
+// away from the surface, for example in color functions or
+// smooth joins of several shapes.
+type NearSurfaceSDF struct {
+	exact SDF
+
+	min   Coord3D
+	max   Coord3D
+	delta float64
+
+	numX int
+	numY int
+	numZ int
+
+	values []float64
+}
+
+// NewNearSurfaceSDF bakes sdf on a grid with the given
+// spacing.
+//
+// Smaller values of delta make the interpolated values
+// more accurate, but require more memory and up-front
+// computation.
+//
+// If delta is 0, a spacing is chosen such that the
+// longest side of the bounding box spans roughly 64
+// cells.
+func NewNearSurfaceSDF(sdf SDF, delta float64) *NearSurfaceSDF {
+	min, max := sdf.Min(), sdf.Max()
+	size := max.Sub(min)
+	if delta == 0 {
+		delta = size.MaxCoord() / 64
+	}
+	if delta <= 0 {
+		panic("grid spacing must be positive")
+	}
+
+	res := &NearSurfaceSDF{
+		exact: sdf,
+		min:   min,
+		max:   max,
+		delta: delta,
+		numX:  essentials.MaxInt(1, int(math.Ceil(size.X/delta))) + 1,
+		numY:  essentials.MaxInt(1, int(math.Ceil(size.Y/delta))) + 1,
+		numZ:  essentials.MaxInt(1, int(math.Ceil(size.Z/delta))) + 1,
+	}
+	res.values = make([]float64, res.numX*res.numY*res.numZ)
+	essentials.ConcurrentMap(0, res.numZ, func(z int) {
+		idx := z * res.numX * res.numY
+		for y := 0; y < res.numY; y++ {
+			for x := 0; x < res.numX; x++ {
+				res.values[idx] = sdf.SDF(res.corner(x, y, z))
+				idx++
+			}
+		}
+	})
+	return res
+}
+
+// Min gets the minimum of the bounding box.
+func (n *NearSurfaceSDF) Min() Coord3D {
+	return n.min
+}
+
+// Max gets the maximum of the bounding box.
+func (n *NearSurfaceSDF) Max() Coord3D {
+	return n.max
+}
+
+// Exact gets the SDF which was baked to create n.
+func (n *NearSurfaceSDF) Exact() SDF {
+	return n.exact
+}
+
+// Delta gets the grid spacing.
+func (n *NearSurfaceSDF) Delta() float64 {
+	return n.delta
+}
+
+// SDF computes the (possibly approximate) SDF at c.
+func (n *NearSurfaceSDF) SDF(c Coord3D) float64 {
+	rel := c.Sub(n.min).Scale(1 / n.delta)
+	x, fx, ok1 := n.cellIndex(rel.X, n.numX)
+	y, fy, ok2 := n.cellIndex(rel.Y, n.numY)
+	z, fz, ok3 := n.cellIndex(rel.Z, n.numZ)
+	if !(ok1 && ok2 && ok3) {
+		return n.exact.SDF(c)
+	}
+
+	// Since an SDF is 1-Lipschitz, the surface can only
+	// pass through the cell if some corner is within one
+	// cell diagonal of it.
+	threshold := n.delta * math.Sqrt(3)
+
+	var corners [8]float64
+	for i := range corners {
+		cx, cy, cz := x+(i&1), y+((i>>1)&1), z+(i>>2)
+		v := n.values[cx+n.numX*(cy+n.numY*cz)]
+		if math.Abs(v) <= threshold {
+			return n.exact.SDF(c)
+		}
+		corners[i] = v
+	}
+
+	for i := 0; i < 4; i++ {
+		corners[i] = corners[i]*(1-fz) + corners[i+4]*fz
+	}
+	for i := 0; i < 2; i++ {
+		corners[i] = corners[i]*(1-fy) + corners[i+2]*fy
+	}
+	return corners[0]*(1-fx) + corners[1]*fx
+}
+
+func (n *NearSurfaceSDF) corner(x, y, z int) Coord3D {
+	return n.min.Add(XYZ(float64(x), float64(y), float64(z)).Scale(n.delta))
+}
+
+// cellIndex gets the lower corner index and fractional
+// offset of a grid coordinate along one axis, or false if
+// the coordinate is outside of the grid.
+func (n *NearSurfaceSDF) cellIndex(rel float64, num int) (int, float64, bool) {
+	if rel < 0 || rel > float64(num-1) {
+		return 0, 0, false
+	}
+	idx := essentials.MinInt(int(rel), num-2)
+	return idx, rel - float64(idx), true
+}
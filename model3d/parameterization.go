@@ -1,8 +1,8 @@
 package model3d
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"math"
 	"sort"
 
@@ -40,6 +40,28 @@ const (
 // underlying algorithm and exact results are subject to
 // change.
 func BuildAutomaticUVMap(m *Mesh, resolution int, verbose bool) MeshUVMap {
+	res, _ := BuildAutomaticUVMapProgress(context.Background(), m, resolution,
+		verboseProgress(verbose))
+	return res
+}
+
+// BuildAutomaticUVMapContext is like BuildAutomaticUVMap,
+// but stops early and returns ctx.Err() if ctx is
+// cancelled or expires.
+//
+// Cancellation is checked between local parameterizations.
+func BuildAutomaticUVMapContext(ctx context.Context, m *Mesh, resolution int,
+	verbose bool) (MeshUVMap, error) {
+	return BuildAutomaticUVMapProgress(ctx, m, resolution, verboseProgress(verbose))
+}
+
+// BuildAutomaticUVMapProgress is like
+// BuildAutomaticUVMapContext, but reports status messages
+// and the fraction of parameterized surface area to p.
+//
+// The progress p may be nil.
+func BuildAutomaticUVMapProgress(ctx context.Context, m *Mesh, resolution int,
+	p Progress) (MeshUVMap, error) {
 	foundPower := false
 	for i := 0; i < 32; i++ {
 		if 1<<uint(i) == resolution {
@@ -53,28 +75,25 @@ func BuildAutomaticUVMap(m *Mesh, resolution int, verbose bool) MeshUVMap {
 
 	totalArea := m.Area()
 	minSplitArea := totalArea / automaticUVMapMaxAreaDivide
-	if verbose {
-		log.Printf("- processing mesh with total area %f", totalArea)
-	}
+	progressLogf(p, "processing mesh with total area %f", totalArea)
 
 	var params []MeshUVMap
 	var completedArea float64
 
 	var handleDisc func(disc *Mesh, depth int)
 	handleDisc = func(disc *Mesh, depth int) {
+		if ctx.Err() != nil {
+			return
+		}
 		area := disc.Area()
 		canSplit := depth < automaticUVMapMaxRecursion && disc.NumTriangles() > 1 &&
 			area > minSplitArea
-		if verbose {
-			log.Printf("- parameterizing plane graph of area %f", area)
-		}
+		progressLogf(p, "parameterizing plane graph of area %f", area)
 
 		splitRecursively := func(stretch float64) {
 			separated := SplitPlaneGraph(disc, nil)
-			if verbose {
-				log.Printf("- split plane graph of area %f and normalized stretch %f into %d pieces",
-					area, stretch, len(separated))
-			}
+			progressLogf(p, "split plane graph of area %f and normalized stretch %f into %d pieces",
+				area, stretch, len(separated))
 			for _, subMesh := range separated {
 				handleDisc(subMesh, depth+1)
 			}
@@ -93,14 +112,14 @@ func BuildAutomaticUVMap(m *Mesh, resolution int, verbose bool) MeshUVMap {
 			}
 		}
 
-		parameterization := StretchMinimizingParameterization(
+		parameterization := stretchMinimizingParameterization(
 			disc,
 			boundary,
 			Floater97ShapePreservingWeights(disc),
 			nil,
 			automaticUVMapParamIters,
 			automaticUVMapParamEta,
-			verbose,
+			p,
 		)
 		ExtendBoundaryUVs(disc, parameterization, 0.1)
 		stretch := normalizedStretch(disc, parameterization)
@@ -110,27 +129,24 @@ func BuildAutomaticUVMap(m *Mesh, resolution int, verbose bool) MeshUVMap {
 			return
 		}
 
-		if verbose {
-			log.Printf("- parameterized with normalized stretch %f", stretch)
-		}
+		progressLogf(p, "parameterized with normalized stretch %f", stretch)
 		params = append(params, NewMeshUVMapForCoords(disc, parameterization))
 		completedArea += area
-		if verbose {
-			log.Printf("- completed %.2f%% of surface area", 100*completedArea/totalArea)
-		}
+		progressUpdate(p, completedArea/totalArea)
 	}
 	for _, disc := range MeshToPlaneGraphsLimited(m, automaticUVMapMaxTris, 0) {
 		handleDisc(disc, 0)
 	}
-	if verbose {
-		log.Printf("- created a total of %d local parameterizations", len(params))
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	progressLogf(p, "created a total of %d local parameterizations", len(params))
 	return PackMeshUVMaps(
 		model2d.XY(0, 0),
 		model2d.XY(1, 1),
 		1.0/float64(resolution),
 		params,
-	)
+	), nil
 }
 
 // CircleBoundary computes a mapping of the boundary of a
@@ -376,7 +392,9 @@ func Floater97DefaultSolver() *numerical.BiCGSTABSolver {
 //
 // The solver argument should be able to solve the sparse
 // linear system produced by the algorithm efficiently.
-// If nil is provided, Floater97DefaultSolver() is used.
+// If nil is provided, the system is solved directly using
+// a sparse LU decomposition, which is typically faster
+// and more accurate than Floater97DefaultSolver().
 //
 // The returned mapping assigns a 2D coordinate to every
 // vertex in the original mesh, including the fixed
@@ -457,7 +475,10 @@ func floater97(m *Mesh, boundary *CoordMap[model2d.Coord],
 	}
 
 	if solver == nil {
-		solver = Floater97DefaultSolver()
+		// The system is at least weakly diagonally dominant,
+		// and pivoting guards against tiny pivots otherwise.
+		solution := numerical.NewSparseLU(matrix).ApplyInverseVec2(bias)
+		return floater97Result(boundary, nonBoundary, solution)
 	}
 	solution := make([]numerical.Vec2, len(bias))
 	for i := 0; i < 2; i++ {
@@ -476,7 +497,11 @@ func floater97(m *Mesh, boundary *CoordMap[model2d.Coord],
 			solution[j][i] = x
 		}
 	}
+	return floater97Result(boundary, nonBoundary, solution)
+}
 
+func floater97Result(boundary *CoordMap[model2d.Coord], nonBoundary []Coord3D,
+	solution []numerical.Vec2) *CoordMap[model2d.Coord] {
 	result := NewCoordMap[model2d.Coord]()
 	boundary.Range(func(k Coord3D, v model2d.Coord) bool {
 		result.Store(k, v)
@@ -507,6 +532,13 @@ func floater97(m *Mesh, boundary *CoordMap[model2d.Coord],
 func StretchMinimizingParameterization(m *Mesh, boundary *CoordMap[model2d.Coord],
 	edgeWeights *EdgeMap[float64], solver numerical.LargeLinearSolver, nIters int,
 	eta float64, verbose bool) *CoordMap[model2d.Coord] {
+	return stretchMinimizingParameterization(m, boundary, edgeWeights, solver, nIters, eta,
+		verboseProgress(verbose))
+}
+
+func stretchMinimizingParameterization(m *Mesh, boundary *CoordMap[model2d.Coord],
+	edgeWeights *EdgeMap[float64], solver numerical.LargeLinearSolver, nIters int,
+	eta float64, p Progress) *CoordMap[model2d.Coord] {
 	solution := Floater97(m, boundary, edgeWeights, solver)
 
 	// Don't count stretch of triangles completely
@@ -527,9 +559,7 @@ func StretchMinimizingParameterization(m *Mesh, boundary *CoordMap[model2d.Coord
 	prevTotalStretch := math.Inf(1)
 	for i := 0; i < nIters || nIters == -1; i++ {
 		stretches, totalStretch := vertexStretches(m, boundaryTris, solution, eta)
-		if verbose {
-			log.Printf("- iter %d: stretch=%f", i, totalStretch)
-		}
+		progressLogf(p, "iter %d: stretch=%f", i, totalStretch)
 		if totalStretch >= prevTotalStretch {
 			return prevSolution
 		}
@@ -561,6 +591,133 @@ func StretchMinimizingParameterization(m *Mesh, boundary *CoordMap[model2d.Coord
 	return solution
 }
 
+// LSCM computes a least squares conformal map of a mesh
+// into 2D, which preserves angles as well as possible.
+//
+// Unlike Floater97, the boundary needn't be fixed.
+// Instead, at least two vertices must be pinned to 2D
+// coordinates, and every other vertex, including the rest
+// of the boundary, is solved for. Pinning exactly two
+// vertices removes the ambiguity of rotation, scale, and
+// translation without distorting the result.
+//
+// The mesh m should be mappable to a disc, and its
+// triangles should be oriented consistently. The result is
+// oriented such that counter-clockwise triangles (when
+// viewed from outside of the mesh) are counter-clockwise
+// in 2D.
+//
+// The linear system is solved directly using a sparse LU
+// decomposition.
+//
+// This is based on the paper:
+// "Least Squares Conformal Maps for Automatic Texture Atlas Generation"
+// (Levy et al., 2002). https://members.loria.fr/Bruno.Levy/papers/LSCM_SIGGRAPH_2002.pdf
+func LSCM(m *Mesh, pinned *CoordMap[model2d.Coord]) *CoordMap[model2d.Coord] {
+	if pinned.Len() < 2 {
+		panic("at least two vertices must be pinned")
+	}
+
+	// Each free vertex has two variables, u and v.
+	free := []Coord3D{}
+	freeToIndex := NewCoordMap[int]()
+	for _, v := range m.VertexSlice() {
+		if _, ok := pinned.Load(v); !ok {
+			freeToIndex.Store(v, len(free))
+			free = append(free, v)
+		}
+	}
+
+	// Accumulate the quadratic form of the conformal energy,
+	// moving the terms of pinned variables into the bias.
+	entries := map[[2]int]float64{}
+	bias := make(numerical.Vec, len(free)*2)
+	type lscmTerm struct {
+		Index  int
+		Coeff  float64
+		Pinned float64
+	}
+	m.Iterate(func(t *Triangle) {
+		area := t.Area()
+		if area == 0 {
+			return
+		}
+		local := lscmLocalCoords(t)
+		scale := 1 / (4 * area)
+
+		// The residuals of the Cauchy-Riemann equations are
+		// linear in the (u, v) coordinates of the vertices.
+		var rows [2][6]lscmTerm
+		for k, c := range t {
+			e := local[(k+2)%3].Sub(local[(k+1)%3])
+			coeffs := [2][2]float64{{-e.Y, -e.X}, {e.X, -e.Y}}
+			for i, rowCoeffs := range coeffs {
+				for j, coeff := range rowCoeffs {
+					term := lscmTerm{Index: -1, Coeff: coeff}
+					if idx, ok := freeToIndex.Load(c); ok {
+						term.Index = idx*2 + j
+					} else {
+						term.Pinned = pinned.Value(c).Array()[j]
+					}
+					rows[i][k*2+j] = term
+				}
+			}
+		}
+		for _, row := range rows {
+			for _, t1 := range row {
+				if t1.Index == -1 {
+					continue
+				}
+				for _, t2 := range row {
+					x := scale * t1.Coeff * t2.Coeff
+					if t2.Index == -1 {
+						bias[t1.Index] -= x * t2.Pinned
+					} else {
+						entries[[2]int{t1.Index, t2.Index}] += x
+					}
+				}
+			}
+		}
+	})
+
+	keys := make([][2]int, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		k1, k2 := keys[i], keys[j]
+		return k1[0] < k2[0] || (k1[0] == k2[0] && k1[1] < k2[1])
+	})
+	matrix := numerical.NewSparseMatrix(len(bias))
+	for _, key := range keys {
+		matrix.Set(key[0], key[1], entries[key])
+	}
+	solution := numerical.NewSparseLU(matrix).ApplyInverse(bias)
+
+	res := NewCoordMap[model2d.Coord]()
+	pinned.Range(func(k Coord3D, v model2d.Coord) bool {
+		res.Store(k, v)
+		return true
+	})
+	for i, v := range free {
+		res.Store(v, model2d.XY(solution[i*2], solution[i*2+1]))
+	}
+	return res
+}
+
+// lscmLocalCoords projects a triangle into an orthonormal
+// basis of its plane, preserving its orientation.
+func lscmLocalCoords(t *Triangle) [3]model2d.Coord {
+	xAxis := t[1].Sub(t[0]).Normalize()
+	yAxis := t.Normal().Cross(xAxis)
+	var res [3]model2d.Coord
+	for i, c := range t {
+		d := c.Sub(t[0])
+		res[i] = model2d.XY(d.Dot(xAxis), d.Dot(yAxis))
+	}
+	return res
+}
+
 func localParameterizationWeights(m *Mesh, center Coord3D) ([]Coord3D, []float64) {
 	ps := orderedNeighbors(m, center)
 
@@ -2,6 +2,7 @@ package model3d
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/unixpickle/model3d/model2d"
@@ -92,6 +93,104 @@ func mustHaveSingleBoundary(t *testing.T, m *Mesh) {
 	}
 }
 
+func TestFloater97LU(t *testing.T) {
+	sphere := NewMeshIcosphere(Origin, 5.0, 8)
+	sphere.Iterate(func(t *Triangle) {
+		if t.Min().Z < 0 {
+			sphere.Remove(t)
+		}
+	})
+	boundary := SquareBoundary(sphere)
+	weights := Floater97ShapePreservingWeights(sphere)
+
+	direct := Floater97(sphere, boundary, weights, nil)
+	iterative := Floater97(sphere, boundary, weights, Floater97DefaultSolver())
+	direct.Range(func(c Coord3D, p model2d.Coord) bool {
+		if p1 := iterative.Value(c); p1.Dist(p) > 1e-4 {
+			t.Fatalf("direct solution %v does not match iterative solution %v", p, p1)
+		}
+		return true
+	})
+}
+
+func TestLSCM(t *testing.T) {
+	t.Run("Planar", func(t *testing.T) {
+		// A planar mesh has an exactly conformal map, namely
+		// the identity, which should be recovered from two
+		// pinned vertices.
+		grid := make([][]Coord3D, 10)
+		for i := range grid {
+			grid[i] = make([]Coord3D, 10)
+			for j := range grid[i] {
+				grid[i][j] = XYZ(float64(i)+rand.Float64()*0.5, float64(j)+rand.Float64()*0.5, 0)
+			}
+		}
+		m := NewMesh()
+		for i := 0; i < 9; i++ {
+			for j := 0; j < 9; j++ {
+				m.Add(&Triangle{grid[i][j], grid[i+1][j], grid[i+1][j+1]})
+				m.Add(&Triangle{grid[i][j], grid[i+1][j+1], grid[i][j+1]})
+			}
+		}
+		pinned := NewCoordMap[model2d.Coord]()
+		for _, c := range []Coord3D{grid[0][0], grid[9][5]} {
+			pinned.Store(c, c.XY())
+		}
+		LSCM(m, pinned).Range(func(c Coord3D, p model2d.Coord) bool {
+			if p.Dist(c.XY()) > 1e-8 {
+				t.Fatalf("expected %v but got %v", c.XY(), p)
+			}
+			return true
+		})
+	})
+
+	t.Run("Hemisphere", func(t *testing.T) {
+		sphere := NewMeshIcosphere(Origin, 5.0, 8)
+		sphere.Iterate(func(t *Triangle) {
+			if t.Min().Z < 0 {
+				sphere.Remove(t)
+			}
+		})
+		var min, max Coord3D
+		for _, v := range sphere.VertexSlice() {
+			if v.X < min.X {
+				min = v
+			}
+			if v.X > max.X {
+				max = v
+			}
+		}
+		pinned := NewCoordMap[model2d.Coord]()
+		pinned.Store(min, model2d.XY(-1, 0))
+		pinned.Store(max, model2d.XY(1, 0))
+
+		param := LSCM(sphere, pinned)
+		if p := param.Value(min); p != model2d.XY(-1, 0) {
+			t.Errorf("pinned vertex moved to %v", p)
+		}
+		var angleError, totalAngle float64
+		sphere.Iterate(func(t3d *Triangle) {
+			var t2d [3]model2d.Coord
+			for i, c := range t3d {
+				t2d[i] = param.Value(c)
+			}
+			d1, d2 := t2d[1].Sub(t2d[0]), t2d[2].Sub(t2d[0])
+			if d1.X*d2.Y-d1.Y*d2.X <= 0 {
+				t.Fatalf("triangle is flipped or degenerate: %v", t2d)
+			}
+			for i := 0; i < 3; i++ {
+				a3 := t3d[(i+1)%3].Sub(t3d[i]).Normalize().Dot(t3d[(i+2)%3].Sub(t3d[i]).Normalize())
+				a2 := t2d[(i+1)%3].Sub(t2d[i]).Normalize().Dot(t2d[(i+2)%3].Sub(t2d[i]).Normalize())
+				angleError += math.Abs(math.Acos(a3) - math.Acos(a2))
+				totalAngle += math.Acos(a3)
+			}
+		})
+		if rel := angleError / totalAngle; rel > 0.05 {
+			t.Errorf("relative angle error too high: %f", rel)
+		}
+	})
+}
+
 func TestBoundaryNonDegenerate(t *testing.T) {
 	testFn := func(t *testing.T, fn func(*Mesh) *CoordMap[model2d.Coord]) {
 		// Try a few times since MeshToPlaneGraphs is non-deterministic.
@@ -2,6 +2,8 @@ package model3d
 
 import (
 	"math"
+
+	"github.com/unixpickle/model3d/numerical"
 )
 
 // A Triangle is a triangle in 3D Euclidean space.
@@ -290,6 +292,14 @@ func (t *Triangle) TriangleCollisions(t1 *Triangle) []Segment {
 		return nil
 	}
 
+	// Exactly rule out triangles which lie on one side of
+	// each other's planes, so that rounding error cannot
+	// produce spurious collisions for triangles which
+	// merely touch.
+	if t.separatesTriangle(t1) || t1.separatesTriangle(t) {
+		return nil
+	}
+
 	// Check if the triangles are (nearly) co-planar.
 	n1 := t.Normal()
 	n2 := t1.Normal()
@@ -436,6 +446,22 @@ func (t *Triangle) TriangleCollisions(t1 *Triangle) []Segment {
 	return []Segment{NewSegment(p1, p2)}
 }
 
+// separatesTriangle checks if the plane of t has all of
+// the vertices of t1 on one side of it, except for at
+// most one vertex which may lie exactly on the plane.
+func (t *Triangle) separatesTriangle(t1 *Triangle) bool {
+	var numPos, numNeg int
+	for _, c := range t1 {
+		o := numerical.Orient3D(t[0].Array(), t[1].Array(), t[2].Array(), c.Array())
+		if o > 0 {
+			numPos++
+		} else if o < 0 {
+			numNeg++
+		}
+	}
+	return (numPos == 0 || numNeg == 0) && numPos+numNeg >= 2
+}
+
 // SegmentCollision checks if the segment collides with
 // the triangle.
 func (t *Triangle) SegmentCollision(s Segment) bool {
@@ -183,6 +183,23 @@ func TestTriangleCollisions(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("TouchingVertex", func(t *testing.T) {
+		// A vertex of t2 lies exactly on the plane of t1,
+		// inside of t1, which is not an intersection.
+		for i := 0; i < 1000; i++ {
+			t1 := &Triangle{XYZ(0, 0, 0.1), XYZ(1, 0, 0.1), XYZ(0, 1, 0.1)}
+			touch := XYZ(rand.Float64()*0.4+0.05, rand.Float64()*0.4+0.05, 0.1)
+			t2 := &Triangle{
+				touch,
+				touch.Add(XYZ(rand.NormFloat64(), rand.NormFloat64(), rand.Float64()+0.1)),
+				touch.Add(XYZ(rand.NormFloat64(), rand.NormFloat64(), rand.Float64()+0.1)),
+			}
+			if len(t1.TriangleCollisions(t2)) != 0 || len(t2.TriangleCollisions(t1)) != 0 {
+				t.Fatal("unexpected collision for touching triangles")
+			}
+		}
+	})
 }
 
 func TestTriangleCollisionMismatch(t *testing.T) {
@@ -0,0 +1,99 @@
+package model3d
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// A Progress receives status updates from a long-running
+// operation, such as meshing, decimation, parameterization,
+// or rendering.
+//
+// Operations which accept a Progress allow it to be nil,
+// in which case no updates are reported.
+//
+// Methods may be called concurrently from multiple
+// Goroutines.
+type Progress interface {
+	// Update reports the fraction of the operation which
+	// has been completed, between 0 and 1.
+	Update(frac float64)
+
+	// Log reports a human-readable status message.
+	Log(msg string)
+}
+
+// ProgressFunc is a Progress which calls a function for
+// every update and ignores status messages.
+type ProgressFunc func(frac float64)
+
+// Update calls p(frac).
+func (p ProgressFunc) Update(frac float64) {
+	p(frac)
+}
+
+// Log does nothing.
+func (p ProgressFunc) Log(msg string) {
+}
+
+// LogProgress is a Progress which writes messages and
+// completion percentages using the standard logger.
+//
+// Completion percentages are only logged when they change
+// by at least one percent.
+type LogProgress struct {
+	lock    sync.Mutex
+	lastPct float64
+	logged  bool
+}
+
+// NewLogProgress creates a LogProgress.
+func NewLogProgress() *LogProgress {
+	return &LogProgress{}
+}
+
+// Update logs frac as a percentage, unless it is too close
+// to the last logged percentage.
+func (l *LogProgress) Update(frac float64) {
+	pct := math.Floor(frac * 100)
+	l.lock.Lock()
+	if l.logged && pct == l.lastPct {
+		l.lock.Unlock()
+		return
+	}
+	l.logged = true
+	l.lastPct = pct
+	l.lock.Unlock()
+	log.Printf("- completed %.0f%%", pct)
+}
+
+// Log logs msg with a "- " prefix.
+func (l *LogProgress) Log(msg string) {
+	log.Printf("- %s", msg)
+}
+
+// verboseProgress gets a LogProgress if verbose is true,
+// or nil otherwise.
+//
+// This is used to implement legacy APIs which accept a
+// verbose flag in terms of Progress.
+func verboseProgress(verbose bool) Progress {
+	if verbose {
+		return NewLogProgress()
+	}
+	return nil
+}
+
+func progressUpdate(p Progress, frac float64) {
+	if p != nil {
+		p.Update(frac)
+	}
+}
+
+func progressLogf(p Progress, format string, args ...any) {
+	if p != nil {
+		p.Log(fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,596 @@
+package model3d
+
+import (
+	"container/heap"
+	"context"
+	"math"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+const (
+	// qemBoundaryWeight scales the quadrics which keep
+	// boundary vertices near their original boundary.
+	qemBoundaryWeight = 100.0
+
+	qemProgressInterval = 256
+)
+
+// SimplifyQEM simplifies a mesh to at most targetTris
+// triangles using default parameters.
+//
+// For more fine-grained control, use QEMSimplifier.
+func SimplifyQEM(m *Mesh, targetTris int) *Mesh {
+	return (&QEMSimplifier{}).Simplify(m, targetTris)
+}
+
+// QEMSimplifier implements quadric error metric (QEM)
+// simplification for triangle meshes.
+//
+// Edges are collapsed in order of increasing error, and
+// the merged vertex is placed where it best fits the
+// planes of the triangles it replaces. Unlike Decimator,
+// this moves vertices as the mesh is simplified, which
+// works much better for curved surfaces, and it can reach
+// a specific triangle count.
+//
+// The mesh should be manifold, but it may have a
+// boundary. Vertices touching non-manifold edges are never
+// moved or removed.
+//
+// The algorithm is described in:
+// "Surface Simplification Using Quadric Error Metrics" -
+// Michael Garland and Paul S. Heckbert.
+// https://www.cs.cmu.edu/~garland/Papers/quadrics.pdf.
+type QEMSimplifier struct {
+	// PreserveBoundary, if true, prevents vertices on the
+	// boundary of an open mesh from being moved or removed.
+	//
+	// Otherwise, boundary vertices may be collapsed, but
+	// extra quadrics penalize moving them away from the
+	// original boundary.
+	PreserveBoundary bool
+
+	// FilterFunc, if specified, can be used to prevent
+	// certain vertices from being moved or removed.
+	// If FilterFunc returns false for a coordinate, it
+	// is kept in place; otherwise it may be collapsed.
+	FilterFunc func(c Coord3D) bool
+
+	// Progress, if non-nil, receives the fraction of the
+	// required triangles which have been removed.
+	Progress Progress
+}
+
+// Simplify collapses edges of m until it has at most
+// targetTris triangles, producing a new mesh.
+//
+// Each collapse removes two triangles (or one triangle on
+// a boundary), so the result may have one fewer triangle
+// than requested. If no more edges can be collapsed
+// without changing the topology of the mesh or flipping
+// triangles, the result may have more triangles than
+// requested.
+func (q *QEMSimplifier) Simplify(m *Mesh, targetTris int) *Mesh {
+	res, _ := q.SimplifyContext(context.Background(), m, targetTris)
+	return res
+}
+
+// SimplifyContext is like Simplify, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+func (q *QEMSimplifier) SimplifyContext(ctx context.Context, m *Mesh,
+	targetTris int) (*Mesh, error) {
+	s := newQEMState(q, m, nil)
+	if err := s.Run(ctx, targetTris); err != nil {
+		return nil, err
+	}
+	res, _ := s.Result()
+	return res, nil
+}
+
+// SimplifyUV is like Simplify, but also computes texture
+// coordinates for the simplified mesh.
+//
+// The uvMap must contain every triangle of m. Vertices on
+// UV seams, where adjacent triangles disagree on texture
+// coordinates, are never moved or removed, so that the
+// texture layout is preserved. Texture coordinates of
+// other vertices are interpolated along collapsed edges.
+func (q *QEMSimplifier) SimplifyUV(m *Mesh, uvMap MeshUVMap,
+	targetTris int) (*Mesh, MeshUVMap) {
+	res, resUV, _ := q.SimplifyUVContext(context.Background(), m, uvMap, targetTris)
+	return res, resUV
+}
+
+// SimplifyUVContext is like SimplifyUV, but stops early
+// and returns ctx.Err() if ctx is cancelled or expires.
+func (q *QEMSimplifier) SimplifyUVContext(ctx context.Context, m *Mesh, uvMap MeshUVMap,
+	targetTris int) (*Mesh, MeshUVMap, error) {
+	s := newQEMState(q, m, uvMap)
+	if err := s.Run(ctx, targetTris); err != nil {
+		return nil, nil, err
+	}
+	res, resUV := s.Result()
+	return res, resUV, nil
+}
+
+type qemState struct {
+	Progress Progress
+
+	Coords   []Coord3D
+	Quadrics []qemQuadric
+	Fixed    []bool
+	Versions []int
+	VertTris [][]int
+
+	Tris     [][3]int
+	TriUVs   [][3]model2d.Coord
+	TriAlive []bool
+	NumTris  int
+
+	Queue qemQueue
+}
+
+func newQEMState(q *QEMSimplifier, m *Mesh, uvMap MeshUVMap) *qemState {
+	s := &qemState{Progress: q.Progress}
+
+	coordToIdx := NewCoordMap[int]()
+	m.Iterate(func(t *Triangle) {
+		var tri [3]int
+		for i, c := range t {
+			idx, ok := coordToIdx.Load(c)
+			if !ok {
+				idx = len(s.Coords)
+				coordToIdx.Store(c, idx)
+				s.Coords = append(s.Coords, c)
+				s.VertTris = append(s.VertTris, nil)
+			}
+			tri[i] = idx
+			s.VertTris[idx] = append(s.VertTris[idx], len(s.Tris))
+		}
+		s.Tris = append(s.Tris, tri)
+		s.TriAlive = append(s.TriAlive, true)
+		if uvMap != nil {
+			s.TriUVs = append(s.TriUVs, uvMap[t])
+		}
+	})
+	s.NumTris = len(s.Tris)
+	s.Quadrics = make([]qemQuadric, len(s.Coords))
+	s.Fixed = make([]bool, len(s.Coords))
+	s.Versions = make([]int, len(s.Coords))
+
+	edgeTris := map[[2]int][]int{}
+	for i, t := range s.Tris {
+		tri := &Triangle{s.Coords[t[0]], s.Coords[t[1]], s.Coords[t[2]]}
+		cross := tri.crossProduct()
+		area := cross.Norm() / 2
+		if area == 0 {
+			continue
+		}
+		normal := cross.Normalize()
+		quadric := newQEMQuadricPlane(normal, tri[0], area)
+		for _, idx := range t {
+			s.Quadrics[idx].Add(&quadric)
+		}
+		for j := 0; j < 3; j++ {
+			e := qemEdge(t[j], t[(j+1)%3])
+			edgeTris[e] = append(edgeTris[e], i)
+		}
+	}
+
+	for e, tris := range edgeTris {
+		if len(tris) > 2 {
+			s.Fixed[e[0]] = true
+			s.Fixed[e[1]] = true
+		} else if len(tris) == 1 {
+			if q.PreserveBoundary {
+				s.Fixed[e[0]] = true
+				s.Fixed[e[1]] = true
+			}
+			// Penalize moving away from a plane which contains
+			// the boundary edge and is perpendicular to the
+			// triangle.
+			t := s.Tris[tris[0]]
+			tri := &Triangle{s.Coords[t[0]], s.Coords[t[1]], s.Coords[t[2]]}
+			p1, p2 := s.Coords[e[0]], s.Coords[e[1]]
+			edgeDir := p2.Sub(p1)
+			normal := edgeDir.Cross(tri.Normal()).Normalize()
+			weight := qemBoundaryWeight * edgeDir.Dot(edgeDir)
+			quadric := newQEMQuadricPlane(normal, p1, weight)
+			s.Quadrics[e[0]].Add(&quadric)
+			s.Quadrics[e[1]].Add(&quadric)
+		} else if s.TriUVs != nil {
+			if s.triUV(tris[0], e[0]) != s.triUV(tris[1], e[0]) ||
+				s.triUV(tris[0], e[1]) != s.triUV(tris[1], e[1]) {
+				s.Fixed[e[0]] = true
+				s.Fixed[e[1]] = true
+			}
+		}
+	}
+	for i, c := range s.Coords {
+		if q.FilterFunc != nil && !q.FilterFunc(c) {
+			s.Fixed[i] = true
+		}
+		if s.TriUVs != nil {
+			tris := s.VertTris[i]
+			for _, t := range tris[1:] {
+				if s.triUV(t, i) != s.triUV(tris[0], i) {
+					s.Fixed[i] = true
+					break
+				}
+			}
+		}
+	}
+
+	for e := range edgeTris {
+		s.pushCollapse(e[0], e[1])
+	}
+	return s
+}
+
+// Run collapses edges until the mesh has at most
+// targetTris triangles, or no more edges can be
+// collapsed.
+func (s *qemState) Run(ctx context.Context, targetTris int) error {
+	initTris := s.NumTris
+	var iter int
+	for s.NumTris > targetTris && s.Queue.Len() > 0 {
+		if iter%qemProgressInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			progressUpdate(s.Progress, float64(initTris-s.NumTris)/float64(initTris-targetTris))
+		}
+		iter++
+		c := heap.Pop(&s.Queue).(*qemCollapse)
+		if s.Versions[c.I] != c.VersionI || s.Versions[c.J] != c.VersionJ {
+			continue
+		}
+		s.collapse(c)
+	}
+	progressUpdate(s.Progress, 1)
+	return nil
+}
+
+// Result creates a mesh, and optionally a UV map, from
+// the remaining triangles.
+func (s *qemState) Result() (*Mesh, MeshUVMap) {
+	m := NewMesh()
+	var uvMap MeshUVMap
+	if s.TriUVs != nil {
+		uvMap = MeshUVMap{}
+	}
+	for i, t := range s.Tris {
+		if !s.TriAlive[i] {
+			continue
+		}
+		tri := &Triangle{s.Coords[t[0]], s.Coords[t[1]], s.Coords[t[2]]}
+		m.Add(tri)
+		if uvMap != nil {
+			uvMap[tri] = s.TriUVs[i]
+		}
+	}
+	return m, uvMap
+}
+
+func (s *qemState) pushCollapse(i, j int) {
+	if s.Fixed[i] && s.Fixed[j] {
+		return
+	}
+	// The removed vertex j is never fixed.
+	if s.Fixed[j] {
+		i, j = j, i
+	}
+	quadric := s.Quadrics[i]
+	quadric.Add(&s.Quadrics[j])
+
+	p1, p2 := s.Coords[i], s.Coords[j]
+	var pos Coord3D
+	if s.Fixed[i] {
+		pos = p1
+	} else {
+		var ok bool
+		pos, ok = quadric.Minimize()
+		// Reject solutions which are poorly conditioned and
+		// wander far from the edge.
+		mid := p1.Mid(p2)
+		if !ok || pos.Dist(mid) > p1.Dist(p2) {
+			pos = p1
+			for _, c := range []Coord3D{p2, mid} {
+				if quadric.Eval(c) < quadric.Eval(pos) {
+					pos = c
+				}
+			}
+		}
+	}
+	heap.Push(&s.Queue, &qemCollapse{
+		I:        i,
+		J:        j,
+		Pos:      pos,
+		Cost:     quadric.Eval(pos),
+		VersionI: s.Versions[i],
+		VersionJ: s.Versions[j],
+	})
+}
+
+func (s *qemState) collapse(c *qemCollapse) {
+	i, j := c.I, c.J
+	var shared, others []int
+	for _, t := range s.VertTris[j] {
+		if qemTriContains(s.Tris[t], i) {
+			shared = append(shared, t)
+		} else {
+			others = append(others, t)
+		}
+	}
+	if !s.canCollapse(i, j, c.Pos, shared) {
+		return
+	}
+
+	if s.TriUVs != nil {
+		uv1, uv2 := s.triUV(shared[0], i), s.triUV(shared[0], j)
+		newUV := uv1
+		if !s.Fixed[i] {
+			p1, p2 := s.Coords[i], s.Coords[j]
+			dir := p2.Sub(p1)
+			frac := math.Max(0, math.Min(1, c.Pos.Sub(p1).Dot(dir)/dir.Dot(dir)))
+			newUV = uv1.Add(uv2.Sub(uv1).Scale(frac))
+			for _, t := range s.VertTris[i] {
+				s.setTriUV(t, i, newUV)
+			}
+		}
+		for _, t := range others {
+			s.setTriUV(t, j, newUV)
+		}
+	}
+
+	for _, t := range shared {
+		s.TriAlive[t] = false
+		s.NumTris--
+		for _, v := range s.Tris[t] {
+			if v != j {
+				s.VertTris[v] = qemRemoveIndex(s.VertTris[v], t)
+			}
+		}
+	}
+	for _, t := range others {
+		for k, v := range s.Tris[t] {
+			if v == j {
+				s.Tris[t][k] = i
+			}
+		}
+		s.VertTris[i] = append(s.VertTris[i], t)
+	}
+	s.VertTris[j] = nil
+	s.Coords[i] = c.Pos
+	s.Quadrics[i].Add(&s.Quadrics[j])
+	s.Versions[i]++
+	s.Versions[j]++
+
+	for _, n := range s.neighbors(i) {
+		s.pushCollapse(i, n)
+	}
+}
+
+func (s *qemState) canCollapse(i, j int, pos Coord3D, shared []int) bool {
+	if len(shared) == 0 {
+		return false
+	}
+
+	// The link condition ensures that the collapse keeps
+	// the mesh manifold.
+	opposite := map[int]bool{}
+	for _, t := range shared {
+		for _, v := range s.Tris[t] {
+			if v != i && v != j {
+				opposite[v] = true
+			}
+		}
+	}
+	neighbors := map[int]bool{}
+	for _, n := range s.neighbors(i) {
+		neighbors[n] = true
+	}
+	for _, n := range s.neighbors(j) {
+		if neighbors[n] && !opposite[n] {
+			return false
+		}
+	}
+
+	// Prevent triangles from flipping or degenerating, and
+	// prevent duplicate triangles, which arise when
+	// collapsing a tetrahedron.
+	seen := map[[3]int]bool{}
+	for _, v := range [2]int{i, j} {
+		for _, t := range s.VertTris[v] {
+			tri := s.Tris[t]
+			if qemTriContains(tri, i) && qemTriContains(tri, j) {
+				continue
+			}
+			old := &Triangle{s.Coords[tri[0]], s.Coords[tri[1]], s.Coords[tri[2]]}
+			newTri := *old
+			key := tri
+			for k, idx := range tri {
+				if idx == i || idx == j {
+					newTri[k] = pos
+					key[k] = i
+				}
+			}
+			oldNormal := old.crossProduct()
+			newNormal := newTri.crossProduct()
+			if newNormal.Norm() == 0 || oldNormal.Dot(newNormal) <= 0 {
+				return false
+			}
+			key = qemSortTri(key)
+			if seen[key] {
+				return false
+			}
+			seen[key] = true
+		}
+	}
+	return true
+}
+
+func (s *qemState) neighbors(v int) []int {
+	var res []int
+	for _, t := range s.VertTris[v] {
+		for _, n := range s.Tris[t] {
+			if n == v {
+				continue
+			}
+			found := false
+			for _, n1 := range res {
+				if n1 == n {
+					found = true
+					break
+				}
+			}
+			if !found {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+func (s *qemState) triUV(t, v int) model2d.Coord {
+	for k, idx := range s.Tris[t] {
+		if idx == v {
+			return s.TriUVs[t][k]
+		}
+	}
+	panic("vertex not in triangle")
+}
+
+func (s *qemState) setTriUV(t, v int, uv model2d.Coord) {
+	for k, idx := range s.Tris[t] {
+		if idx == v {
+			s.TriUVs[t][k] = uv
+		}
+	}
+}
+
+type qemCollapse struct {
+	I, J     int
+	Pos      Coord3D
+	Cost     float64
+	VersionI int
+	VersionJ int
+}
+
+type qemQueue []*qemCollapse
+
+func (q qemQueue) Len() int {
+	return len(q)
+}
+
+func (q qemQueue) Less(i, j int) bool {
+	return q[i].Cost < q[j].Cost
+}
+
+func (q qemQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *qemQueue) Push(x any) {
+	*q = append(*q, x.(*qemCollapse))
+}
+
+func (q *qemQueue) Pop() any {
+	old := *q
+	res := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return res
+}
+
+// qemQuadric is a symmetric 4x4 matrix Q which measures
+// the squared error v^T*Q*v of a point v=(x, y, z, 1).
+//
+// The upper triangle is stored in row-major order.
+type qemQuadric [10]float64
+
+// newQEMQuadricPlane creates a quadric measuring the
+// weighted squared distance to a plane, given a unit
+// normal and a point on the plane.
+func newQEMQuadricPlane(normal, point Coord3D, weight float64) qemQuadric {
+	a, b, c := normal.X, normal.Y, normal.Z
+	d := -normal.Dot(point)
+	return qemQuadric{
+		a * a, a * b, a * c, a * d,
+		b * b, b * c, b * d,
+		c * c, c * d,
+		d * d,
+	}.Scale(weight)
+}
+
+func (q qemQuadric) Scale(s float64) qemQuadric {
+	for i := range q {
+		q[i] *= s
+	}
+	return q
+}
+
+func (q *qemQuadric) Add(q1 *qemQuadric) {
+	for i, x := range q1 {
+		q[i] += x
+	}
+}
+
+func (q *qemQuadric) Eval(c Coord3D) float64 {
+	x, y, z := c.X, c.Y, c.Z
+	return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+		q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+		q[7]*z*z + 2*q[8]*z +
+		q[9]
+}
+
+// Minimize finds the point with the smallest error, or
+// returns false if the system is poorly conditioned.
+func (q *qemQuadric) Minimize() (Coord3D, bool) {
+	mat := &Matrix3{
+		q[0], q[1], q[2],
+		q[1], q[4], q[5],
+		q[2], q[5], q[7],
+	}
+	trace := q[0] + q[4] + q[7]
+	det := mat.Det()
+	if trace == 0 || math.Abs(det) < 1e-8*trace*trace*trace {
+		return Coord3D{}, false
+	}
+	return mat.MulColumnInv(XYZ(-q[3], -q[6], -q[8]), det), true
+}
+
+func qemEdge(i, j int) [2]int {
+	if i > j {
+		return [2]int{j, i}
+	}
+	return [2]int{i, j}
+}
+
+func qemTriContains(t [3]int, v int) bool {
+	return t[0] == v || t[1] == v || t[2] == v
+}
+
+func qemSortTri(t [3]int) [3]int {
+	if t[0] > t[1] {
+		t[0], t[1] = t[1], t[0]
+	}
+	if t[1] > t[2] {
+		t[1], t[2] = t[2], t[1]
+	}
+	if t[0] > t[1] {
+		t[0], t[1] = t[1], t[0]
+	}
+	return t
+}
+
+func qemRemoveIndex(indices []int, x int) []int {
+	for i, y := range indices {
+		if y == x {
+			indices[i] = indices[len(indices)-1]
+			return indices[:len(indices)-1]
+		}
+	}
+	return indices
+}
@@ -0,0 +1,113 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+func TestSimplifyQEMSphere(t *testing.T) {
+	m := NewMeshIcosphere(Origin, 1, 10)
+	for _, target := range []int{1000, 200, 50} {
+		simple := SimplifyQEM(m, target)
+		MustValidateMesh(t, simple, true)
+		n := len(simple.TriangleSlice())
+		if n > target || n < target-1 {
+			t.Errorf("target %d: expected %d triangles but got %d", target, target, n)
+		}
+		expectedVolume := m.Volume()
+		if v := simple.Volume(); math.Abs(v-expectedVolume) > 0.1*expectedVolume {
+			t.Errorf("target %d: expected volume %f but got %f", target, expectedVolume, v)
+		}
+		simple.IterateVertices(func(c Coord3D) {
+			if math.Abs(c.Norm()-1) > 0.1 {
+				t.Errorf("target %d: vertex %v is too far from surface", target, c)
+			}
+		})
+	}
+}
+
+func TestSimplifyQEMBoundary(t *testing.T) {
+	m := NewMesh()
+	heightmap := func(x, y int) Coord3D {
+		fx, fy := float64(x)/10, float64(y)/10
+		return XYZ(fx, fy, 0.2*math.Sin(fx*3)*math.Cos(fy*2))
+	}
+	for x := 0; x < 20; x++ {
+		for y := 0; y < 20; y++ {
+			m.AddQuad(heightmap(x, y), heightmap(x+1, y), heightmap(x+1, y+1), heightmap(x, y+1))
+		}
+	}
+	boundary := func(m *Mesh) map[Coord3D]bool {
+		res := map[Coord3D]bool{}
+		m.Iterate(func(t *Triangle) {
+			for _, seg := range t.Segments() {
+				if len(m.Find(seg[0], seg[1])) == 1 {
+					res[seg[0]] = true
+					res[seg[1]] = true
+				}
+			}
+		})
+		return res
+	}
+	expected := boundary(m)
+
+	q := &QEMSimplifier{PreserveBoundary: true}
+	simple := q.Simplify(m, 200)
+	if n := len(simple.TriangleSlice()); n > 200 {
+		t.Errorf("expected at most 200 triangles but got %d", n)
+	}
+	if len(simple.SingularVertices()) != 0 {
+		t.Error("singular vertices")
+	}
+	actual := boundary(simple)
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %d boundary vertices but got %d", len(expected), len(actual))
+	}
+	for c := range expected {
+		if !actual[c] {
+			t.Fatalf("missing boundary vertex %v", c)
+		}
+	}
+}
+
+func TestSimplifyQEMUV(t *testing.T) {
+	// Each face of the cube is mapped to its own chart,
+	// so UVs are a linear function of position per face.
+	faceUV := func(normal, c Coord3D) model2d.Coord {
+		if math.Abs(normal.X) > 0.5 {
+			return model2d.XY(c.Y+normal.X*2, c.Z)
+		} else if math.Abs(normal.Y) > 0.5 {
+			return model2d.XY(c.X+normal.Y*4, c.Z)
+		}
+		return model2d.XY(c.X+normal.Z*6, c.Y)
+	}
+	m := SubdivideEdges(NewMeshRect(XYZ(0, 0, 0), XYZ(1, 1, 1)), 8)
+	uvMap := MeshUVMap{}
+	m.Iterate(func(t *Triangle) {
+		n := t.Normal()
+		uvMap[t] = [3]model2d.Coord{faceUV(n, t[0]), faceUV(n, t[1]), faceUV(n, t[2])}
+	})
+
+	simple, simpleUV := (&QEMSimplifier{}).SimplifyUV(m, uvMap, 100)
+	MustValidateMesh(t, simple, true)
+	if n, orig := len(simple.TriangleSlice()), len(m.TriangleSlice()); n >= orig {
+		t.Fatalf("expected fewer than %d triangles but got %d", orig, n)
+	}
+	if len(simpleUV) != len(simple.TriangleSlice()) {
+		t.Fatalf("expected %d UV entries but got %d", len(simple.TriangleSlice()), len(simpleUV))
+	}
+	simple.Iterate(func(t1 *Triangle) {
+		uvs, ok := simpleUV[t1]
+		if !ok {
+			t.Fatal("missing UVs for triangle")
+		}
+		n := t1.Normal()
+		for i, c := range t1 {
+			if expected := faceUV(n, c); expected.Dist(uvs[i]) > 1e-8 {
+				t.Fatalf("expected UV %v but got %v", expected, uvs[i])
+			}
+		}
+	})
+}
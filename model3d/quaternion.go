@@ -0,0 +1,339 @@
+package model3d
+
+import "math"
+
+// A Quaternion is a number of the form W+X*i+Y*j+Z*k.
+//
+// Unit quaternions represent 3D rotations, and can be
+// composed and interpolated more cleanly than rotation
+// matrices.
+type Quaternion struct {
+	W float64
+	X float64
+	Y float64
+	Z float64
+}
+
+// IdentityQuaternion gets the unit quaternion for the
+// identity rotation.
+func IdentityQuaternion() Quaternion {
+	return Quaternion{W: 1}
+}
+
+// NewQuaternionRotation creates a unit quaternion which
+// rotates points around the given axis in a right-handed
+// direction, like NewMatrix3Rotation.
+//
+// The axis is assumed to be normalized.
+// The angle is measured in radians.
+func NewQuaternionRotation(axis Coord3D, angle float64) Quaternion {
+	s := math.Sin(angle / 2)
+	return Quaternion{
+		W: math.Cos(angle / 2),
+		X: axis.X * s,
+		Y: axis.Y * s,
+		Z: axis.Z * s,
+	}
+}
+
+// NewQuaternionMatrix3 creates a unit quaternion from a
+// rotation matrix.
+//
+// The matrix is assumed to be orthonormal with a positive
+// determinant.
+func NewQuaternionMatrix3(m *Matrix3) Quaternion {
+	// Shepperd's method: pick the largest diagonal term of
+	// the quaternion outer product to avoid cancellation.
+	trace := m[0] + m[4] + m[8]
+	var q Quaternion
+	if trace > 0 {
+		s := 2 * math.Sqrt(trace+1)
+		q = Quaternion{
+			W: s / 4,
+			X: (m[7] - m[5]) / s,
+			Y: (m[2] - m[6]) / s,
+			Z: (m[3] - m[1]) / s,
+		}
+	} else if m[0] > m[4] && m[0] > m[8] {
+		s := 2 * math.Sqrt(1+m[0]-m[4]-m[8])
+		q = Quaternion{
+			W: (m[7] - m[5]) / s,
+			X: s / 4,
+			Y: (m[1] + m[3]) / s,
+			Z: (m[2] + m[6]) / s,
+		}
+	} else if m[4] > m[8] {
+		s := 2 * math.Sqrt(1+m[4]-m[0]-m[8])
+		q = Quaternion{
+			W: (m[2] - m[6]) / s,
+			X: (m[1] + m[3]) / s,
+			Y: s / 4,
+			Z: (m[5] + m[7]) / s,
+		}
+	} else {
+		s := 2 * math.Sqrt(1+m[8]-m[0]-m[4])
+		q = Quaternion{
+			W: (m[3] - m[1]) / s,
+			X: (m[2] + m[6]) / s,
+			Y: (m[5] + m[7]) / s,
+			Z: s / 4,
+		}
+	}
+	return q.Normalize()
+}
+
+// Vec gets the vector (imaginary) part of q.
+func (q Quaternion) Vec() Coord3D {
+	return XYZ(q.X, q.Y, q.Z)
+}
+
+// Add computes q+q1.
+func (q Quaternion) Add(q1 Quaternion) Quaternion {
+	return Quaternion{W: q.W + q1.W, X: q.X + q1.X, Y: q.Y + q1.Y, Z: q.Z + q1.Z}
+}
+
+// Sub computes q-q1.
+func (q Quaternion) Sub(q1 Quaternion) Quaternion {
+	return Quaternion{W: q.W - q1.W, X: q.X - q1.X, Y: q.Y - q1.Y, Z: q.Z - q1.Z}
+}
+
+// Scale multiplies every component of q by s.
+func (q Quaternion) Scale(s float64) Quaternion {
+	return Quaternion{W: q.W * s, X: q.X * s, Y: q.Y * s, Z: q.Z * s}
+}
+
+// Mul computes the Hamilton product q*q1.
+//
+// For unit quaternions, the result rotates by q1 and then
+// by q.
+func (q Quaternion) Mul(q1 Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*q1.W - q.X*q1.X - q.Y*q1.Y - q.Z*q1.Z,
+		X: q.W*q1.X + q.X*q1.W + q.Y*q1.Z - q.Z*q1.Y,
+		Y: q.W*q1.Y - q.X*q1.Z + q.Y*q1.W + q.Z*q1.X,
+		Z: q.W*q1.Z + q.X*q1.Y - q.Y*q1.X + q.Z*q1.W,
+	}
+}
+
+// Dot computes the 4D dot product of q and q1.
+func (q Quaternion) Dot(q1 Quaternion) float64 {
+	return q.W*q1.W + q.X*q1.X + q.Y*q1.Y + q.Z*q1.Z
+}
+
+// Norm computes the magnitude of q.
+func (q Quaternion) Norm() float64 {
+	return math.Sqrt(q.Dot(q))
+}
+
+// Normalize gets a unit quaternion in the direction of q.
+func (q Quaternion) Normalize() Quaternion {
+	return q.Scale(1 / q.Norm())
+}
+
+// Conj computes the conjugate of q, which is the inverse
+// rotation for unit quaternions.
+func (q Quaternion) Conj() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Inverse computes the multiplicative inverse of q.
+func (q Quaternion) Inverse() Quaternion {
+	return q.Conj().Scale(1 / q.Dot(q))
+}
+
+// Apply rotates c by the unit quaternion q.
+func (q Quaternion) Apply(c Coord3D) Coord3D {
+	// Equivalent to q*c*conj(q), but with fewer operations.
+	v := q.Vec()
+	t := v.Cross(c).Scale(2)
+	return c.Add(t.Scale(q.W)).Add(v.Cross(t))
+}
+
+// Matrix3 gets the rotation matrix for the unit
+// quaternion q.
+func (q Quaternion) Matrix3() *Matrix3 {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+	return &Matrix3{
+		1 - 2*(yy+zz), 2 * (xy - wz), 2 * (xz + wy),
+		2 * (xy + wz), 1 - 2*(xx+zz), 2 * (yz - wx),
+		2 * (xz - wy), 2 * (yz + wx), 1 - 2*(xx+yy),
+	}
+}
+
+// Transform gets a DistTransform which rotates by the unit
+// quaternion q, like Rotation().
+func (q Quaternion) Transform() DistTransform {
+	return &orthoMatrix3Transform{
+		Matrix3Transform{
+			Matrix: q.Matrix3(),
+		},
+	}
+}
+
+// AxisAngle gets the rotation axis and angle (in radians)
+// for the unit quaternion q.
+//
+// The angle is in the range [0, pi], and the axis is
+// arbitrary for the identity rotation.
+func (q Quaternion) AxisAngle() (Coord3D, float64) {
+	if q.W < 0 {
+		q = q.Scale(-1)
+	}
+	v := q.Vec()
+	s := v.Norm()
+	if s == 0 {
+		return X(1), 0
+	}
+	return v.Scale(1 / s), 2 * math.Atan2(s, q.W)
+}
+
+// Slerp performs spherical linear interpolation between
+// unit quaternions q and q1.
+//
+// The result is q when t=0 and q1 (or -q1, which is the
+// same rotation) when t=1.
+// The shortest path between the two rotations is used.
+func (q Quaternion) Slerp(q1 Quaternion, t float64) Quaternion {
+	dot := q.Dot(q1)
+	if dot < 0 {
+		q1 = q1.Scale(-1)
+		dot = -dot
+	}
+	if dot > 1-1e-8 {
+		// Avoid division by zero for nearly identical
+		// rotations, where linear interpolation suffices.
+		return q.Scale(1 - t).Add(q1.Scale(t)).Normalize()
+	}
+	theta := math.Acos(dot)
+	sin := math.Sin(theta)
+	return q.Scale(math.Sin((1-t)*theta) / sin).Add(q1.Scale(math.Sin(t*theta) / sin))
+}
+
+// A DualQuaternion represents a rigid transformation
+// (rotation followed by translation) as Real+eps*Dual,
+// where eps^2 = 0.
+//
+// Unlike matrices, unit dual quaternions can be blended
+// without introducing scaling or shearing, which makes
+// them well-suited for skinning and rigid interpolation.
+type DualQuaternion struct {
+	Real Quaternion
+	Dual Quaternion
+}
+
+// IdentityDualQuaternion gets the unit dual quaternion for
+// the identity transformation.
+func IdentityDualQuaternion() DualQuaternion {
+	return DualQuaternion{Real: IdentityQuaternion()}
+}
+
+// NewDualQuaternion creates a unit dual quaternion which
+// first rotates by a unit quaternion and then translates
+// by a vector.
+func NewDualQuaternion(rotation Quaternion, translation Coord3D) DualQuaternion {
+	t := Quaternion{X: translation.X, Y: translation.Y, Z: translation.Z}
+	return DualQuaternion{
+		Real: rotation,
+		Dual: t.Mul(rotation).Scale(0.5),
+	}
+}
+
+// Mul computes the product d*d1.
+//
+// For unit dual quaternions, the result applies d1 and
+// then d.
+func (d DualQuaternion) Mul(d1 DualQuaternion) DualQuaternion {
+	return DualQuaternion{
+		Real: d.Real.Mul(d1.Real),
+		Dual: d.Real.Mul(d1.Dual).Add(d.Dual.Mul(d1.Real)),
+	}
+}
+
+// Add computes d+d1.
+func (d DualQuaternion) Add(d1 DualQuaternion) DualQuaternion {
+	return DualQuaternion{Real: d.Real.Add(d1.Real), Dual: d.Dual.Add(d1.Dual)}
+}
+
+// Scale multiplies both parts of d by s.
+func (d DualQuaternion) Scale(s float64) DualQuaternion {
+	return DualQuaternion{Real: d.Real.Scale(s), Dual: d.Dual.Scale(s)}
+}
+
+// Normalize gets the unit dual quaternion nearest to d.
+func (d DualQuaternion) Normalize() DualQuaternion {
+	norm := d.Real.Norm()
+	real := d.Real.Scale(1 / norm)
+	dual := d.Dual.Scale(1 / norm)
+
+	// Remove the component of the dual part which is not
+	// orthogonal to the real part.
+	dual = dual.Sub(real.Scale(real.Dot(dual)))
+	return DualQuaternion{Real: real, Dual: dual}
+}
+
+// Inverse computes the inverse of a unit dual quaternion.
+func (d DualQuaternion) Inverse() DualQuaternion {
+	return DualQuaternion{Real: d.Real.Conj(), Dual: d.Dual.Conj()}
+}
+
+// Rotation gets the rotation of a unit dual quaternion.
+func (d DualQuaternion) Rotation() Quaternion {
+	return d.Real
+}
+
+// Translation gets the translation of a unit dual
+// quaternion.
+func (d DualQuaternion) Translation() Coord3D {
+	return d.Dual.Scale(2).Mul(d.Real.Conj()).Vec()
+}
+
+// Apply applies the rigid transformation of a unit dual
+// quaternion to c.
+func (d DualQuaternion) Apply(c Coord3D) Coord3D {
+	return d.Real.Apply(c).Add(d.Translation())
+}
+
+// Transform gets a DistTransform equivalent to the unit
+// dual quaternion.
+func (d DualQuaternion) Transform() DistTransform {
+	return JoinedTransform{
+		d.Real.Transform(),
+		&Translate{Offset: d.Translation()},
+	}
+}
+
+// Interp interpolates between unit dual quaternions d and
+// d1 by blending them with weights 1-t and t.
+//
+// The result is always a rigid transformation.
+func (d DualQuaternion) Interp(d1 DualQuaternion, t float64) DualQuaternion {
+	return BlendDualQuaternions([]DualQuaternion{d, d1}, []float64{1 - t, t})
+}
+
+// BlendDualQuaternions computes a normalized weighted
+// combination of unit dual quaternions, as used in dual
+// quaternion skinning.
+//
+// Since d and -d represent the same transformation, every
+// input is first flipped to lie in the same hemisphere as
+// the first input, so that blending follows the shortest
+// path.
+func BlendDualQuaternions(ds []DualQuaternion, weights []float64) DualQuaternion {
+	if len(ds) != len(weights) {
+		panic("mismatched number of dual quaternions and weights")
+	}
+	if len(ds) == 0 {
+		return IdentityDualQuaternion()
+	}
+	var sum DualQuaternion
+	for i, d := range ds {
+		w := weights[i]
+		if d.Real.Dot(ds[0].Real) < 0 {
+			w = -w
+		}
+		sum = sum.Add(d.Scale(w))
+	}
+	return sum.Normalize()
+}
@@ -0,0 +1,111 @@
+package model3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestQuaternionRotation(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		axis := NewCoord3DRandUnit()
+		angle := rand.Float64()*4*math.Pi - 2*math.Pi
+		q := NewQuaternionRotation(axis, angle)
+		expected := NewMatrix3Rotation(axis, angle)
+
+		for j, x := range q.Matrix3() {
+			if math.Abs(x-expected[j]) > 1e-8 {
+				t.Fatalf("expected matrix %v but got %v", expected, q.Matrix3())
+			}
+		}
+
+		c := NewCoord3DRandNorm()
+		if actual, exp := q.Apply(c), expected.MulColumn(c); actual.Dist(exp) > 1e-8 {
+			t.Errorf("expected %v but got %v", exp, actual)
+		}
+
+		q1 := NewQuaternionMatrix3(expected)
+		if math.Abs(math.Abs(q1.Dot(q))-1) > 1e-8 {
+			t.Errorf("expected %v (up to sign) but got %v", q, q1)
+		}
+
+		axis1, angle1 := q.AxisAngle()
+		q2 := NewQuaternionRotation(axis1, angle1)
+		if math.Abs(math.Abs(q2.Dot(q))-1) > 1e-8 {
+			t.Errorf("axis-angle round trip: expected %v but got %v", q, q2)
+		}
+	}
+}
+
+func TestQuaternionMul(t *testing.T) {
+	q1 := NewQuaternionRotation(NewCoord3DRandUnit(), rand.NormFloat64())
+	q2 := NewQuaternionRotation(NewCoord3DRandUnit(), rand.NormFloat64())
+	c := NewCoord3DRandNorm()
+	expected := q1.Apply(q2.Apply(c))
+	if actual := q1.Mul(q2).Apply(c); actual.Dist(expected) > 1e-8 {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+	if actual := q1.Inverse().Apply(q1.Apply(c)); actual.Dist(c) > 1e-8 {
+		t.Errorf("expected %v but got %v", c, actual)
+	}
+}
+
+func TestQuaternionSlerp(t *testing.T) {
+	axis := NewCoord3DRandUnit()
+	q1 := NewQuaternionRotation(axis, 0.3)
+	q2 := NewQuaternionRotation(axis, 1.5)
+	for _, frac := range []float64{0, 0.25, 0.5, 1} {
+		expected := NewQuaternionRotation(axis, 0.3+1.2*frac)
+		actual := q1.Slerp(q2, frac)
+		if math.Abs(actual.Dot(expected)-1) > 1e-8 {
+			t.Errorf("frac %f: expected %v but got %v", frac, expected, actual)
+		}
+		// Negating the endpoint should not change the path.
+		actual = q1.Slerp(q2.Scale(-1), frac)
+		if math.Abs(actual.Dot(expected)-1) > 1e-8 {
+			t.Errorf("frac %f (negated): expected %v but got %v", frac, expected, actual)
+		}
+	}
+}
+
+func TestDualQuaternion(t *testing.T) {
+	rot1 := NewQuaternionRotation(NewCoord3DRandUnit(), rand.NormFloat64())
+	rot2 := NewQuaternionRotation(NewCoord3DRandUnit(), rand.NormFloat64())
+	trans1 := NewCoord3DRandNorm()
+	trans2 := NewCoord3DRandNorm()
+	d1 := NewDualQuaternion(rot1, trans1)
+	d2 := NewDualQuaternion(rot2, trans2)
+	c := NewCoord3DRandNorm()
+
+	if actual, expected := d1.Apply(c), rot1.Apply(c).Add(trans1); actual.Dist(expected) > 1e-8 {
+		t.Errorf("apply: expected %v but got %v", expected, actual)
+	}
+	if actual := d1.Translation(); actual.Dist(trans1) > 1e-8 {
+		t.Errorf("translation: expected %v but got %v", trans1, actual)
+	}
+	if actual, expected := d1.Transform().Apply(c), d1.Apply(c); actual.Dist(expected) > 1e-8 {
+		t.Errorf("transform: expected %v but got %v", expected, actual)
+	}
+
+	expected := d1.Apply(d2.Apply(c))
+	if actual := d1.Mul(d2).Apply(c); actual.Dist(expected) > 1e-8 {
+		t.Errorf("mul: expected %v but got %v", expected, actual)
+	}
+	if actual := d1.Inverse().Apply(d1.Apply(c)); actual.Dist(c) > 1e-8 {
+		t.Errorf("inverse: expected %v but got %v", c, actual)
+	}
+
+	if actual := d1.Interp(d2, 0).Apply(c); actual.Dist(d1.Apply(c)) > 1e-8 {
+		t.Errorf("interp start: expected %v but got %v", d1.Apply(c), actual)
+	}
+	if actual := d1.Interp(d2.Scale(-1), 1).Apply(c); actual.Dist(d2.Apply(c)) > 1e-8 {
+		t.Errorf("interp end: expected %v but got %v", d2.Apply(c), actual)
+	}
+
+	// Blends should remain rigid transformations.
+	mid := d1.Interp(d2, 0.4)
+	c1 := NewCoord3DRandNorm()
+	if d0, d := c.Dist(c1), mid.Apply(c).Dist(mid.Apply(c1)); math.Abs(d0-d) > 1e-8 {
+		t.Errorf("blend is not rigid: distance %f became %f", d0, d)
+	}
+}
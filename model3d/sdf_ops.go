@@ -0,0 +1,75 @@
+package model3d
+
+// Round creates a Solid which rounds off the convex corners
+// and edges of an SDF with the given radius.
+//
+// The result contains every point within radius of the
+// SDF inset by radius. In other words, the SDF is inset
+// and then outset again, filleting any corners which are
+// sharper than the radius while leaving other parts of the
+// surface in place. Concave corners are not affected.
+//
+// Since the SDF of the inset shape is not known exactly,
+// points near rounded corners are found with a local
+// search along the gradient of the SDF. This is exact for
+// shapes made of flat faces, like boxes, but is only an
+// approximation for other shapes.
+//
+// For best results, s should be an exact SDF.
+func Round(s SDF, radius float64) Solid {
+	if radius <= 0 {
+		return SDFToSolid(s, 0)
+	}
+	return CheckedFuncSolid(
+		s.Min(),
+		s.Max(),
+		func(c Coord3D) bool {
+			d := s.SDF(c)
+			if d >= radius {
+				return true
+			} else if d < 0 {
+				return false
+			}
+			return roundSearch(s, c, d, radius)
+		},
+	)
+}
+
+// roundSearch checks if there is a point within radius of
+// c which is at least radius units inside the SDF.
+//
+// This performs gradient ascent on the SDF, projecting
+// each step back into the sphere around c.
+func roundSearch(s SDF, c Coord3D, d, radius float64) bool {
+	epsilon := radius * 1e-4
+	tolerance := radius * 1e-8
+	p := c
+	for i := 0; i < 32; i++ {
+		grad := XYZ(
+			s.SDF(p.Add(X(epsilon)))-s.SDF(p.Sub(X(epsilon))),
+			s.SDF(p.Add(Y(epsilon)))-s.SDF(p.Sub(Y(epsilon))),
+			s.SDF(p.Add(Z(epsilon)))-s.SDF(p.Sub(Z(epsilon))),
+		).Scale(1 / (2 * epsilon))
+		slope := grad.Norm()
+		if slope == 0 {
+			return false
+		}
+
+		// Step far enough to reach the target distance if
+		// the SDF were linear along the gradient.
+		step := (radius - d) / slope
+		next := p.Add(grad.Scale(step / slope))
+		if offset := next.Sub(c); offset.Norm() > radius {
+			next = c.Add(offset.Scale(radius / offset.Norm()))
+		}
+
+		nextD := s.SDF(next)
+		if nextD >= radius-tolerance {
+			return true
+		} else if nextD <= d {
+			return false
+		}
+		p, d = next, nextD
+	}
+	return false
+}
@@ -36,6 +36,30 @@ func TestMeshSDFVertices(t *testing.T) {
 	})
 }
 
+func TestNearSurfaceSDF(t *testing.T) {
+	solid := sdfTestingSolid()
+	mesh := MarchingCubesSearch(solid, 0.02, 8)
+	exactSDF := MeshToSDF(mesh)
+	sdf := NewNearSurfaceSDF(exactSDF, 0.05)
+	maxErr := sdf.Delta() * math.Sqrt(3)
+
+	for i := 0; i < 1000; i++ {
+		c := NewCoord3DRandNorm()
+		actual := sdf.SDF(c)
+		expected := exactSDF.SDF(c)
+		if (actual > 0) != (expected > 0) {
+			t.Fatalf("bad sign at %v: expected %f but got %f", c, expected, actual)
+		}
+		if math.Abs(expected) < maxErr && actual != expected {
+			t.Fatalf("near-surface value at %v should be exact: expected %f but got %f",
+				c, expected, actual)
+		}
+		if math.Abs(actual-expected) > maxErr {
+			t.Fatalf("value at %v too far off: expected %f but got %f", c, expected, actual)
+		}
+	}
+}
+
 func TestMeshPointSDF(t *testing.T) {
 	solid := sdfTestingSolid()
 	mesh := MarchingCubesSearch(solid, 0.02, 8)
@@ -56,6 +80,57 @@ func TestMeshPointSDF(t *testing.T) {
 	}
 }
 
+func TestRound(t *testing.T) {
+	t.Run("Rect", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			expected := randomRoundedRect()
+			actual := Round(NewRect(expected.MinVal, expected.MaxVal), expected.Radius)
+			testRoundContainment(t, expected, actual)
+		}
+	})
+	t.Run("Sphere", func(t *testing.T) {
+		sphere := &Sphere{Center: XYZ(0.1, 0.2, 0.3), Radius: 0.7}
+		testRoundContainment(t, sphere, Round(sphere, 0.3))
+	})
+	t.Run("Concave", func(t *testing.T) {
+		// An L shape has one concave edge, which should
+		// remain sharp, while its other edges are rounded.
+		solid := JoinedSolid{
+			NewRect(XYZ(0, 0, 0), XYZ(2, 1, 1)),
+			NewRect(XYZ(0, 0, 0), XYZ(1, 2, 1)),
+		}
+		mesh := MarchingCubesSearch(solid, 0.02, 8)
+		rounded := Round(MeshToSDF(mesh), 0.2)
+		for _, c := range []Coord3D{XYZ(0.99, 0.99, 0.5), XYZ(1.03, 0.93, 0.5)} {
+			if !rounded.Contains(c) {
+				t.Errorf("concave edge should be preserved at %v", c)
+			}
+		}
+		for _, c := range []Coord3D{XYZ(1.99, 0.01, 0.5), XYZ(0.01, 1.99, 0.99)} {
+			if rounded.Contains(c) {
+				t.Errorf("convex corner should be rounded at %v", c)
+			}
+		}
+		if !rounded.Contains(XYZ(1.5, 0.5, 0.5)) {
+			t.Error("interior should be preserved")
+		}
+	})
+}
+
+func testRoundContainment(t *testing.T, expected SDF, actual Solid) {
+	min, max := expected.Min(), expected.Max()
+	for i := 0; i < 10000; i++ {
+		c := NewCoord3DRandBounds(min, max)
+		sdf := expected.SDF(c)
+		if math.Abs(sdf) < 1e-5 {
+			continue
+		}
+		if actual.Contains(c) != (sdf > 0) {
+			t.Fatalf("unexpected containment at %v (expected SDF %f)", c, sdf)
+		}
+	}
+}
+
 func TestProfileSDF(t *testing.T) {
 	profileSolid := model2d.JoinedSolid{
 		&model2d.Circle{
@@ -127,6 +202,7 @@ func BenchmarkMeshSDFs(b *testing.B) {
 
 	approxSDF := ColliderToSDF(MeshToCollider(mesh), 64)
 	exactSDF := MeshToSDF(mesh)
+	nearSurfaceSDF := NewNearSurfaceSDF(exactSDF, 0)
 
 	runTests := func(b *testing.B, c Coord3D) {
 		b.Run("Approx", func(b *testing.B) {
@@ -146,6 +222,12 @@ func BenchmarkMeshSDFs(b *testing.B) {
 				exactSDF.PointSDF(c)
 			}
 		})
+
+		b.Run("NearSurface", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				nearSurfaceSDF.SDF(c)
+			}
+		})
 	}
 
 	b.Run("Center", func(b *testing.B) {
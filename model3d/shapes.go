@@ -343,6 +343,208 @@ func (r *Rect) Expand(delta float64) *Rect {
 	}
 }
 
+// A RoundedRect is a 3D primitive that fills an
+// axis-aligned rectangular space with rounded corners
+// and edges.
+//
+// The rounded rect is bounded by MinVal and MaxVal, and
+// its corners are rounded with the given Radius. The
+// radius should not exceed half of the size of the rect
+// along any axis.
+type RoundedRect struct {
+	MinVal Coord3D
+	MaxVal Coord3D
+	Radius float64
+}
+
+// NewRoundedRect creates a RoundedRect with a min value,
+// a max value, and a radius.
+func NewRoundedRect(min, max Coord3D, radius float64) *RoundedRect {
+	return &RoundedRect{MinVal: min, MaxVal: max, Radius: radius}
+}
+
+// Min yields r.MinVal.
+func (r *RoundedRect) Min() Coord3D {
+	return r.MinVal
+}
+
+// Max yields r.MaxVal.
+func (r *RoundedRect) Max() Coord3D {
+	return r.MaxVal
+}
+
+// Contains checks if c is inside of r.
+func (r *RoundedRect) Contains(c Coord3D) bool {
+	if c.Min(r.MinVal) != r.MinVal || c.Max(r.MaxVal) != r.MaxVal {
+		return false
+	}
+	return r.SDF(c) >= 0
+}
+
+// FirstRayCollision gets the first ray collision with the
+// surface of the rounded rect.
+func (r *RoundedRect) FirstRayCollision(ray *Ray) (RayCollision, bool) {
+	t1, t2, ok := r.rayCollisionScales(ray)
+	if !ok || t2 < 0 {
+		return RayCollision{}, false
+	}
+	t := t1
+	if t < 0 {
+		t = t2
+	}
+	normal, _ := r.NormalSDF(ray.Origin.Add(ray.Direction.Scale(t)))
+	return RayCollision{
+		Scale:  t,
+		Normal: normal,
+		Extra:  r,
+	}, true
+}
+
+// RayCollisions calls f (if non-nil) with each ray
+// collision with the surface of the rounded rect.
+// It returns the number of collisions.
+func (r *RoundedRect) RayCollisions(ray *Ray, f func(RayCollision)) int {
+	t1, t2, ok := r.rayCollisionScales(ray)
+	if !ok || t2 < 0 {
+		return 0
+	}
+
+	var count int
+	for _, t := range []float64{t1, t2} {
+		if t < 0 {
+			continue
+		}
+		count++
+		if f != nil {
+			normal, _ := r.NormalSDF(ray.Origin.Add(ray.Direction.Scale(t)))
+			f(RayCollision{
+				Scale:  t,
+				Normal: normal,
+				Extra:  r,
+			})
+		}
+	}
+	return count
+}
+
+// rayCollisionScales finds the ray scales where the ray
+// enters and exits the rounded rect.
+//
+// Since the shape is convex, the SDF is concave along the
+// ray, so we can search for a point inside the shape and
+// then bisect towards the boundary on either side of it.
+func (r *RoundedRect) rayCollisionScales(ray *Ray) (float64, float64, bool) {
+	tMin, tMax := rayCollisionWithBounds(ray, r.MinVal, r.MaxVal)
+	if tMax < tMin || tMax < 0 {
+		return 0, 0, false
+	}
+	sdf := func(t float64) float64 {
+		return r.SDF(ray.Origin.Add(ray.Direction.Scale(t)))
+	}
+
+	lo, hi := tMin, tMax
+	tInside := math.NaN()
+	for i := 0; i < 100; i++ {
+		t1 := lo + (hi-lo)/3
+		t2 := hi - (hi-lo)/3
+		s1, s2 := sdf(t1), sdf(t2)
+		if s1 >= 0 {
+			tInside = t1
+			break
+		} else if s2 >= 0 {
+			tInside = t2
+			break
+		}
+		if s1 < s2 {
+			lo = t1
+		} else {
+			hi = t2
+		}
+	}
+	if math.IsNaN(tInside) {
+		return 0, 0, false
+	}
+
+	bisect := func(outside, inside float64) float64 {
+		for i := 0; i < 64; i++ {
+			mid := (outside + inside) / 2
+			if sdf(mid) >= 0 {
+				inside = mid
+			} else {
+				outside = mid
+			}
+		}
+		return inside
+	}
+	return bisect(tMin, tInside), bisect(tMax, tInside), true
+}
+
+// SphereCollision checks if a solid sphere touches any
+// part of the surface of the rounded rect.
+func (r *RoundedRect) SphereCollision(c Coord3D, radius float64) bool {
+	return math.Abs(r.SDF(c)) <= radius
+}
+
+// SDF gets the signed distance to the surface of the
+// rounded rect.
+func (r *RoundedRect) SDF(c Coord3D) float64 {
+	return r.genericSDF(c, nil, nil)
+}
+
+// PointSDF gets the nearest point on the surface of the
+// rounded rect and the corresponding SDF.
+func (r *RoundedRect) PointSDF(c Coord3D) (Coord3D, float64) {
+	var p Coord3D
+	res := r.genericSDF(c, nil, &p)
+	return p, res
+}
+
+// NormalSDF gets the signed distance to the rounded rect
+// and the normal at the closest point on the surface.
+func (r *RoundedRect) NormalSDF(c Coord3D) (Coord3D, float64) {
+	var n Coord3D
+	res := r.genericSDF(c, &n, nil)
+	return n, res
+}
+
+func (r *RoundedRect) genericSDF(c Coord3D, normalOut, pointOut *Coord3D) float64 {
+	// The rounded rect contains every point within Radius
+	// of a smaller, inset rect.
+	inner := NewRect(r.MinVal, r.MaxVal).Expand(-r.Radius)
+
+	var normal, point Coord3D
+	var dist float64
+	if !inner.Contains(c) {
+		point = c.Min(inner.MaxVal).Max(inner.MinVal)
+		diff := c.Sub(point)
+		dist = -diff.Norm()
+		normal = diff.Scale(-1 / dist)
+	} else {
+		dist = inner.genericSDF(c, &normal, &point)
+	}
+	if normalOut != nil {
+		*normalOut = normal
+	}
+	if pointOut != nil {
+		*pointOut = point.Add(normal.Scale(r.Radius))
+	}
+	return dist + r.Radius
+}
+
+// MetaballField returns positive values outside of the
+// surface, and these values increase linearly with
+// distance to the surface.
+func (r *RoundedRect) MetaballField(coord Coord3D) float64 {
+	return -r.SDF(coord)
+}
+
+// MetaballDistBound returns d always, since the metaball
+// implemented by MetaballField() is defined in terms of
+// standard Euclidean coordinates.
+func (r *RoundedRect) MetaballDistBound(d float64) float64 {
+	return d
+}
+
 // A Capsule is a shape which contains all of the points
 // within a given distance of a line segment.
 type Capsule struct {
@@ -938,7 +1140,8 @@ func (c *Cone) RayCollisions(r *Ray, f func(RayCollision)) int {
 			if dot := axis.Dot(p); dot >= 0 && dot <= norm {
 				if f != nil {
 					baseAxis := safeNormal(p.Add(c.Tip).Sub(c.Base), b1, axis)
-					normal := baseAxis.Scale(c.Radius).Add(c.Tip.Sub(c.Base)).Normalize()
+					normal := baseAxis.Scale(norm * norm).Add(c.Tip.Sub(c.Base).Scale(c.Radius))
+					normal = normal.Normalize()
 					f(RayCollision{
 						Scale:  t,
 						Normal: normal,
@@ -1007,7 +1210,8 @@ func (c *Cone) genericSDF(p Coord3D, normalOut, pointOut *Coord3D) float64 {
 	if edgeDist < dist {
 		dist = edgeDist
 		if normalOut != nil {
-			*normalOut = axis.Scale(c.Radius).Add(c.Tip.Sub(c.Base)).Normalize()
+			normal := axis.Scale(centerLine.Dot(centerLine)).Add(centerLine.Scale(c.Radius))
+			*normalOut = normal.Normalize()
 		}
 		if pointOut != nil {
 			*pointOut = edgeSegment.Closest(p)
@@ -1034,6 +1238,227 @@ func (c *Cone) MetaballDistBound(d float64) float64 {
 	return d
 }
 
+// A TruncatedCone is a cone with its tip cut off, bounded
+// by two parallel circles centered at P1 and P2 with radii
+// R1 and R2, respectively.
+//
+// Either radius may be zero, in which case the shape is a
+// regular cone.
+type TruncatedCone struct {
+	P1 Coord3D
+	P2 Coord3D
+	R1 float64
+	R2 float64
+}
+
+// Min gets the minimum point of the bounding box.
+func (t *TruncatedCone) Min() Coord3D {
+	axis := t.P2.Sub(t.P1)
+	minOffsets := Coord3D{
+		circleAxisBound(0, axis, -1),
+		circleAxisBound(1, axis, -1),
+		circleAxisBound(2, axis, -1),
+	}
+	return t.P1.Add(minOffsets.Scale(t.R1)).Min(t.P2.Add(minOffsets.Scale(t.R2)))
+}
+
+// Max gets the maximum point of the bounding box.
+func (t *TruncatedCone) Max() Coord3D {
+	axis := t.P2.Sub(t.P1)
+	maxOffsets := Coord3D{
+		circleAxisBound(0, axis, 1),
+		circleAxisBound(1, axis, 1),
+		circleAxisBound(2, axis, 1),
+	}
+	return t.P1.Add(maxOffsets.Scale(t.R1)).Max(t.P2.Add(maxOffsets.Scale(t.R2)))
+}
+
+// Contains checks if p is inside the truncated cone.
+func (t *TruncatedCone) Contains(p Coord3D) bool {
+	diff := t.P2.Sub(t.P1)
+	norm := diff.Norm()
+	direction := diff.Scale(1 / norm)
+	frac := p.Sub(t.P1).Dot(direction)
+	if frac < 0 || frac > norm {
+		return false
+	}
+	projection := t.P1.Add(direction.Scale(frac))
+	return projection.Dist(p) <= t.radius(frac/norm)
+}
+
+// FirstRayCollision gets the first ray collision with the
+// truncated cone, if one occurs.
+func (t *TruncatedCone) FirstRayCollision(r *Ray) (RayCollision, bool) {
+	var res RayCollision
+	var ok bool
+	t.RayCollisions(r, func(rc RayCollision) {
+		if !ok || rc.Scale < res.Scale {
+			res = rc
+			ok = true
+		}
+	})
+	return res, ok
+}
+
+// RayCollisions calls f (if non-nil) with every ray
+// collision.
+//
+// It returns the total number of collisions.
+func (t *TruncatedCone) RayCollisions(r *Ray, f func(RayCollision)) int {
+	n := 0
+
+	axis := t.P2.Sub(t.P1)
+	norm := axis.Norm()
+	axis = axis.Scale(1 / norm)
+	b1, b2 := axis.OrthoBasis()
+
+	// The radius changes linearly along the axis, so the
+	// squared distance from the surface along the ray is a
+	// quadratic polynomial.
+	o := r.Origin.Sub(t.P1)
+	d := r.Direction
+	slope := (t.R2 - t.R1) / norm
+	dist1 := numerical.Polynomial{b1.Dot(o), b1.Dot(d)}
+	dist2 := numerical.Polynomial{b2.Dot(o), b2.Dot(d)}
+	distSq := dist1.Mul(dist1).Add(dist2.Mul(dist2))
+	radius := numerical.Polynomial{t.R1 + o.Dot(axis)*slope, d.Dot(axis) * slope}
+	radiusSq := radius.Mul(radius)
+
+	sqSurfaceDist := distSq.Add(radiusSq.Scale(-1))
+	sqSurfaceDist.IterRealRoots(func(scale float64) bool {
+		if scale >= 0 {
+			p := o.Add(d.Scale(scale))
+			if dot := axis.Dot(p); dot >= 0 && dot <= norm {
+				if f != nil {
+					radial := safeNormal(p.Sub(axis.Scale(dot)), b1, axis)
+					f(RayCollision{
+						Scale:  scale,
+						Normal: t.sideNormal(radial, axis, norm),
+						Extra:  t,
+					})
+				}
+				n++
+			}
+		}
+		return true
+	})
+
+	for i, center := range []Coord3D{t.P1, t.P2} {
+		normal := axis
+		radius := t.R2
+		if i == 0 {
+			normal = normal.Scale(-1)
+			radius = t.R1
+		}
+		if radius == 0 {
+			continue
+		}
+		coll, ok := castCircle(normal, center, radius, r)
+		if ok {
+			n++
+			if f != nil {
+				coll.Extra = t
+				f(coll)
+			}
+		}
+	}
+
+	return n
+}
+
+// SphereCollision checks if the surface of t collides
+// with a solid sphere centered at c with radius r.
+func (t *TruncatedCone) SphereCollision(center Coord3D, r float64) bool {
+	return math.Abs(t.SDF(center)) <= r
+}
+
+// SDF determines the minimum distance from a point to the
+// surface of the truncated cone.
+func (t *TruncatedCone) SDF(coord Coord3D) float64 {
+	return t.genericSDF(coord, nil, nil)
+}
+
+// PointSDF is like SDF, but also returns the closest point
+// on the surface of the truncated cone.
+func (t *TruncatedCone) PointSDF(coord Coord3D) (Coord3D, float64) {
+	var point Coord3D
+	dist := t.genericSDF(coord, nil, &point)
+	return point, dist
+}
+
+// NormalSDF is like SDF, but also returns the normal on
+// the surface of the truncated cone at the closest point
+// to coord.
+func (t *TruncatedCone) NormalSDF(coord Coord3D) (Coord3D, float64) {
+	var normal Coord3D
+	dist := t.genericSDF(coord, &normal, nil)
+	return normal, dist
+}
+
+func (t *TruncatedCone) genericSDF(p Coord3D, normalOut, pointOut *Coord3D) float64 {
+	axis := t.P2.Sub(t.P1)
+	norm := axis.Norm()
+	axis = axis.Scale(1 / norm)
+
+	// A zero radius is the tip of a cone, which is handled
+	// as part of the slanted side.
+	dist := math.Inf(1)
+	if t.R1 != 0 {
+		filledCircleDist(p, t.P1, axis.Scale(-1), t.R1, &dist, normalOut, pointOut)
+	}
+	if t.R2 != 0 {
+		filledCircleDist(p, t.P2, axis, t.R2, &dist, normalOut, pointOut)
+	}
+
+	offset := p.Sub(t.P1)
+	fallback, _ := axis.OrthoBasis()
+	radial := safeNormal(offset.Sub(axis.Scale(offset.Dot(axis))), fallback, axis)
+	edgeSegment := NewSegment(t.P1.Add(radial.Scale(t.R1)), t.P2.Add(radial.Scale(t.R2)))
+	edgeDist := edgeSegment.Dist(p)
+
+	if edgeDist < dist {
+		dist = edgeDist
+		if normalOut != nil {
+			*normalOut = t.sideNormal(radial, axis, norm)
+		}
+		if pointOut != nil {
+			*pointOut = edgeSegment.Closest(p)
+		}
+	}
+	if t.Contains(p) {
+		return dist
+	} else {
+		return -dist
+	}
+}
+
+// radius gets the radius at a fraction of the way from P1
+// to P2.
+func (t *TruncatedCone) radius(frac float64) float64 {
+	return t.R1 + (t.R2-t.R1)*frac
+}
+
+// sideNormal computes the normal of the slanted side in
+// the direction of the unit vector radial, given the unit
+// axis from P1 to P2 and the distance between P1 and P2.
+func (t *TruncatedCone) sideNormal(radial, axis Coord3D, norm float64) Coord3D {
+	return radial.Scale(norm).Add(axis.Scale(t.R1 - t.R2)).Normalize()
+}
+
+// MetaballField returns positive values outside of the
+// surface, and these values increase linearly with
+// distance to the surface.
+func (t *TruncatedCone) MetaballField(coord Coord3D) float64 {
+	return -t.SDF(coord)
+}
+
+// MetaballDistBound returns d always, since the metaball
+// implemented by MetaballField() is defined in terms of
+// standard Euclidean coordinates.
+func (t *TruncatedCone) MetaballDistBound(d float64) float64 {
+	return d
+}
+
 // A Torus is a 3D primitive that represents a torus.
 //
 // The torus is defined by revolving a sphere of radius
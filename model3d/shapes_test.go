@@ -73,6 +73,12 @@ func TestTorusBounds(t *testing.T) {
 	}
 }
 
+func TestTruncatedConeBounds(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		testSolidBounds(t, randomTruncatedCone())
+	}
+}
+
 func testSolidBounds(t *testing.T, solid Solid) {
 	min := solid.Min()
 	max := solid.Max()
@@ -259,6 +265,76 @@ func TestConeSDF(t *testing.T) {
 			cone.Tip.Mid(cone.Base).Add(b2.Scale(cone.Radius*0.2)),
 			cone.Base.Add(cone.Base.Sub(cone.Tip)),
 		)
+		testNormalSDFGradient(
+			t,
+			cone,
+			cone.Tip.Mid(cone.Base).Add(b1.Scale(cone.Radius*0.5+1e-2)),
+			cone.Tip.Mid(cone.Base).Add(b2.Scale(-cone.Radius*0.5-1e-2)),
+		)
+	}
+}
+
+func TestTruncatedConeSDF(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		tc := randomTruncatedCone()
+		testSolidSDF(t, tc)
+		testPointSDFConsistency(t, tc, tc.P1.Add(tc.P1.Sub(tc.P2)))
+
+		axis := tc.P2.Sub(tc.P1).Normalize()
+		b1, b2 := axis.OrthoBasis()
+		mid := tc.P1.Mid(tc.P2)
+		midRadius := (tc.R1 + tc.R2) / 2
+		testNormalSDFConsistency(
+			t,
+			tc,
+			false,
+			mid.Add(b1.Scale(midRadius*1.01)),
+			mid.Add(b2.Scale(midRadius*0.99)),
+			mid.Add(b1.Scale(midRadius*0.2)),
+			tc.P1.Add(tc.P1.Sub(tc.P2)),
+			tc.P2.Add(tc.P2.Sub(tc.P1)),
+		)
+
+		// Points just outside of the slanted side have a
+		// smooth SDF whose gradient is the normal.
+		testNormalSDFGradient(
+			t,
+			tc,
+			mid.Add(b1.Scale(midRadius+1e-2)),
+			mid.Add(b2.Scale(-midRadius-1e-2)),
+		)
+
+		// A truncated cone with one zero radius is a cone.
+		cone := &Cone{Base: tc.P1, Tip: tc.P2, Radius: tc.R1}
+		tc.R2 = 0
+		for j := 0; j < 100; j++ {
+			c := NewCoord3DRandBounds(cone.Min(), cone.Max())
+			p1, sdf1 := cone.PointSDF(c)
+			p2, sdf2 := tc.PointSDF(c)
+			if math.Abs(sdf1-sdf2) > 1e-8 {
+				t.Errorf("expected SDF %f but got %f", sdf1, sdf2)
+			}
+			if p1.Dist(p2) > 1e-8 {
+				t.Errorf("expected closest point %v but got %v", p1, p2)
+			}
+		}
+	}
+}
+
+func TestRoundedRectSDF(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		r := randomRoundedRect()
+		testSolidSDF(t, r)
+		testPointSDFConsistency(t, r)
+		testNormalSDFConsistency(t, r, true)
+		testNormalSDFGradient(
+			t,
+			r,
+			r.MinVal.Mid(r.MaxVal).Add(X(0.01)),
+			r.MinVal.Add(XYZ(0.01, 0.02, 0.03)),
+			r.MaxVal.Sub(XYZ(0.01, 0.02, 0.03)),
+			r.MaxVal.Add(XYZ(0.1, 0.2, 0.3)),
+		)
 	}
 }
 
@@ -365,6 +441,24 @@ func testNormalSDFConsistency(t *testing.T, p pointNormalSDF, checkRandom bool,
 	}
 }
 
+func testNormalSDFGradient(t *testing.T, n NormalSDF, checkPoints ...Coord3D) {
+	const epsilon = 1e-5
+	for _, c := range checkPoints {
+		normal, _ := n.NormalSDF(c)
+		var grad [3]float64
+		for axis := 0; axis < 3; axis++ {
+			var delta [3]float64
+			delta[axis] = epsilon
+			d := NewCoord3DArray(delta)
+			grad[axis] = (n.SDF(c.Sub(d)) - n.SDF(c.Add(d))) / (2 * epsilon)
+		}
+		expected := NewCoord3DArray(grad).Normalize()
+		if normal.Dot(expected) < 1-1e-4 {
+			t.Errorf("expected normal %v at %v but got %v", expected, c, normal)
+		}
+	}
+}
+
 func TestRectCollider(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		c1 := NewCoord3DRandNorm()
@@ -446,6 +540,18 @@ func TestConeColliderSDF(t *testing.T) {
 	}
 }
 
+func TestTruncatedConeColliderSDF(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		testSolidColliderSDF(t, randomTruncatedCone())
+	}
+}
+
+func TestRoundedRectColliderSDF(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		testSolidColliderSDF(t, randomRoundedRect())
+	}
+}
+
 func TestTorusColliderSDF(t *testing.T) {
 	for i := 0; i < 10; i++ {
 		testSolidColliderSDF(t, randomTorus())
@@ -539,3 +645,26 @@ func randomTorus() *Torus {
 		InnerRadius: inner,
 	}
 }
+
+func randomTruncatedCone() *TruncatedCone {
+	return &TruncatedCone{
+		P1: NewCoord3DRandNorm(),
+		P2: NewCoord3DRandNorm(),
+		R1: math.Abs(rand.NormFloat64()) + 0.1,
+		R2: math.Abs(rand.NormFloat64()) + 0.1,
+	}
+}
+
+func randomRoundedRect() *RoundedRect {
+	c1 := NewCoord3DRandNorm()
+	c2 := NewCoord3DRandNorm()
+	min := c1.Min(c2)
+	max := c1.Max(c2).Add(XYZ(0.1, 0.1, 0.1))
+	size := max.Sub(min)
+	minSize := math.Min(size.X, math.Min(size.Y, size.Z))
+	return &RoundedRect{
+		MinVal: min,
+		MaxVal: max,
+		Radius: rand.Float64() * minSize / 2,
+	}
+}
@@ -0,0 +1,93 @@
+package model3d
+
+import "math"
+
+// A DrainHole is a cylindrical hole through the wall of a
+// shell, which lets material (e.g. resin or powder) drain
+// out of the hollow interior after printing.
+type DrainHole struct {
+	// Point is a point on the outer surface where the
+	// hole should be placed.
+	Point Coord3D
+
+	// Radius is the radius of the hole.
+	Radius float64
+}
+
+// Shell hollows out the inside of an SDF, leaving a wall
+// of the given thickness just inside the surface.
+//
+// Unlike subtracting a scaled copy of a shape, the wall
+// has a uniform thickness for arbitrary shapes, as long
+// as the SDF is exact. To hollow a mesh, use MeshToSDF or
+// Mesh.Shell.
+//
+// Drain holes may optionally be drilled through the wall,
+// perpendicular to the surface at each hole's Point.
+func Shell(s SDF, thickness float64, holes ...DrainHole) Solid {
+	drills := make([]*Cylinder, len(holes))
+	for i, h := range holes {
+		normal := sdfGradient(s, h.Point, thickness*1e-3).Normalize()
+		drills[i] = &Cylinder{
+			P1:     h.Point.Add(normal.Scale(thickness)),
+			P2:     h.Point.Sub(normal.Scale(thickness * 2)),
+			Radius: h.Radius,
+		}
+	}
+	return CheckedFuncSolid(
+		s.Min(),
+		s.Max(),
+		func(c Coord3D) bool {
+			d := s.SDF(c)
+			if d < 0 || d > thickness {
+				return false
+			}
+			for _, drill := range drills {
+				if drill.Contains(c) {
+					return false
+				}
+			}
+			return true
+		},
+	)
+}
+
+// Shell creates a hollow version of a closed mesh, with a
+// wall of the given thickness.
+//
+// The result contains the original mesh along with an
+// inner surface facing into the hollow interior. The
+// inner surface is created with marching cubes, using a
+// grid size proportional to the thickness.
+//
+// If the mesh is too thin to hollow out, a copy of the
+// original mesh is returned.
+func (m *Mesh) Shell(thickness float64) *Mesh {
+	sdf := MeshToSDF(m)
+	delta := thickness / 4
+	inner := MarchingCubesSearchFilter(
+		SDFToSolid(sdf, -thickness),
+		func(r *Rect) bool {
+			// Only scan boxes which might contain the inner
+			// surface, since the SDF is 1-Lipschitz.
+			radius := r.MaxVal.Dist(r.MinVal) / 2
+			d := sdf.SDF(r.MinVal.Mid(r.MaxVal))
+			return math.Abs(d-thickness) <= radius+delta
+		},
+		delta,
+		8,
+	)
+	result := m.Copy()
+	result.AddMesh(inner.InvertNormals())
+	return result
+}
+
+// sdfGradient approximates the gradient of an SDF using
+// central differences, pointing towards the outside.
+func sdfGradient(s SDF, c Coord3D, epsilon float64) Coord3D {
+	return XYZ(
+		s.SDF(c.Sub(X(epsilon)))-s.SDF(c.Add(X(epsilon))),
+		s.SDF(c.Sub(Y(epsilon)))-s.SDF(c.Add(Y(epsilon))),
+		s.SDF(c.Sub(Z(epsilon)))-s.SDF(c.Add(Z(epsilon))),
+	).Scale(1 / (2 * epsilon))
+}
@@ -0,0 +1,48 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShell(t *testing.T) {
+	sphere := &Sphere{Center: XYZ(0.1, 0.2, 0.3), Radius: 1}
+	hole := DrainHole{Point: sphere.Center.Add(Z(1)), Radius: 0.2}
+	shell := Shell(sphere, 0.1, hole)
+
+	testPoints := map[Coord3D]bool{
+		sphere.Center:                     false,
+		sphere.Center.Add(X(0.95)):        true,
+		sphere.Center.Add(X(-0.95)):       true,
+		sphere.Center.Add(X(0.85)):        false,
+		sphere.Center.Add(X(1.05)):        false,
+		sphere.Center.Add(Z(-0.95)):       true,
+		sphere.Center.Add(Z(0.95)):        false,
+		sphere.Center.Add(XZ(0.15, 0.95)): false,
+		sphere.Center.Add(XZ(0.3, 0.9)):   true,
+	}
+	for c, expected := range testPoints {
+		if actual := shell.Contains(c); actual != expected {
+			t.Errorf("coord %v: expected %v but got %v", c, expected, actual)
+		}
+	}
+}
+
+func TestMeshShell(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 20)
+	shell := mesh.Shell(0.2)
+	MustValidateMesh(t, shell, false)
+
+	expected := mesh.Volume() - 4.0/3.0*math.Pi*math.Pow(0.8, 3)
+	if actual := shell.Volume(); math.Abs(actual-expected) > 0.02*expected {
+		t.Errorf("expected volume %f but got %f", expected, actual)
+	}
+	if n := len(shell.TriangleSlice()); n <= len(mesh.TriangleSlice()) {
+		t.Errorf("expected inner surface, but got %d triangles", n)
+	}
+
+	// The interior should be hollow.
+	if NewColliderSolid(MeshToCollider(shell)).Contains(Origin) {
+		t.Error("interior of shell should be empty")
+	}
+}
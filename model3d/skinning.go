@@ -0,0 +1,265 @@
+package model3d
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultSkeletonMaxInfluences is the default number of
+// joints which may influence each vertex in
+// Skeleton.AutoWeights().
+const DefaultSkeletonMaxInfluences = 4
+
+// A Joint is a single joint in a Skeleton.
+type Joint struct {
+	Name string
+
+	// Parent is the index of the parent joint, or -1 if
+	// this is a root joint.
+	Parent int
+
+	// Origin is the center of rotation for the joint in
+	// the rest pose.
+	Origin Coord3D
+}
+
+// A Skeleton is a hierarchy of joints which can be used to
+// pose a mesh, as in skeletal animation.
+//
+// Joints are stored such that every joint comes after its
+// parent.
+type Skeleton struct {
+	Joints []*Joint
+}
+
+// NewSkeleton creates an empty skeleton.
+func NewSkeleton() *Skeleton {
+	return &Skeleton{}
+}
+
+// AddJoint adds a joint to the skeleton and returns its
+// index.
+//
+// The parent must be -1 or the index of a joint which was
+// already added.
+func (s *Skeleton) AddJoint(name string, parent int, origin Coord3D) int {
+	if parent < -1 || parent >= len(s.Joints) {
+		panic("invalid parent joint index")
+	}
+	s.Joints = append(s.Joints, &Joint{Name: name, Parent: parent, Origin: origin})
+	return len(s.Joints) - 1
+}
+
+// Joint finds the index of the first joint with the given
+// name, or -1 if no such joint exists.
+func (s *Skeleton) Joint(name string) int {
+	for i, j := range s.Joints {
+		if j.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewPose creates a rest pose for the skeleton, in which
+// every joint has the identity rotation.
+func (s *Skeleton) NewPose() *Pose {
+	rotations := make([]Quaternion, len(s.Joints))
+	for i := range rotations {
+		rotations[i] = IdentityQuaternion()
+	}
+	return &Pose{Rotations: rotations}
+}
+
+// Transforms computes, for every joint, the rigid
+// transformation from the rest pose to the given pose.
+//
+// Each joint rotates about its origin, and its children
+// inherit the resulting transformation.
+func (s *Skeleton) Transforms(p *Pose) []DualQuaternion {
+	if len(p.Rotations) != len(s.Joints) {
+		panic("pose does not match skeleton")
+	}
+	res := make([]DualQuaternion, len(s.Joints))
+	for i, j := range s.Joints {
+		rot := p.Rotations[i]
+		local := NewDualQuaternion(rot, j.Origin.Sub(rot.Apply(j.Origin)))
+		if j.Parent == -1 {
+			res[i] = NewDualQuaternion(IdentityQuaternion(), p.Translation).Mul(local)
+		} else {
+			if j.Parent >= i {
+				panic("joint must come after its parent")
+			}
+			res[i] = res[j.Parent].Mul(local)
+		}
+	}
+	return res
+}
+
+// AutoWeights computes skinning weights for every vertex
+// of a mesh in the rest pose, based on the distance from
+// each vertex to each joint's bones.
+//
+// The bones of a joint are the segments from its origin to
+// the origins of its children; a joint without children
+// is treated as a single point.
+// Each vertex is influenced by at most maxInfluences
+// joints, which defaults to DefaultSkeletonMaxInfluences
+// if 0 is passed.
+//
+// This is a simple heuristic, and more accurate weights
+// may need to be painted or computed by other means.
+func (s *Skeleton) AutoWeights(m *Mesh, maxInfluences int) *CoordMap[[]JointWeight] {
+	if maxInfluences == 0 {
+		maxInfluences = DefaultSkeletonMaxInfluences
+	}
+	children := make([][]int, len(s.Joints))
+	for i, j := range s.Joints {
+		if j.Parent != -1 {
+			children[j.Parent] = append(children[j.Parent], i)
+		}
+	}
+
+	res := NewCoordMap[[]JointWeight]()
+	for _, c := range m.VertexSlice() {
+		weights := make([]JointWeight, len(s.Joints))
+		for i, j := range s.Joints {
+			dist := j.Origin.Dist(c)
+			for _, child := range children[i] {
+				seg := NewSegment(j.Origin, s.Joints[child].Origin)
+				dist = math.Min(dist, seg.Dist(c))
+			}
+			weights[i] = JointWeight{Joint: i, Weight: 1 / math.Max(1e-8, math.Pow(dist, 4))}
+		}
+		sort.Slice(weights, func(i, j int) bool {
+			return weights[i].Weight > weights[j].Weight
+		})
+		if len(weights) > maxInfluences {
+			weights = weights[:maxInfluences]
+		}
+		normalizeJointWeights(weights)
+		res.Store(c, weights)
+	}
+	return res
+}
+
+// A Pose specifies the rotation of every joint in a
+// Skeleton, along with a global translation.
+type Pose struct {
+	// Rotations stores a unit quaternion for each joint,
+	// which rotates the joint about its origin relative to
+	// its parent.
+	Rotations []Quaternion
+
+	// Translation is applied to every root joint.
+	Translation Coord3D
+}
+
+// A JointWeight specifies how much a joint influences a
+// vertex during skinning.
+type JointWeight struct {
+	Joint  int
+	Weight float64
+}
+
+// SkinningMethod determines how joint transformations are
+// blended for vertices influenced by multiple joints.
+type SkinningMethod int
+
+const (
+	// SkinningLinearBlend averages the transformed vertex
+	// positions. This is fast and standard, but can cause
+	// joints to collapse when they twist or bend sharply.
+	SkinningLinearBlend SkinningMethod = iota
+
+	// SkinningDualQuaternion blends the joint
+	// transformations as dual quaternions, which avoids
+	// the collapsing artifacts of linear blending.
+	SkinningDualQuaternion
+)
+
+// A Skin binds a rest-pose mesh to a Skeleton so that it
+// can be deformed into new poses.
+type Skin struct {
+	Skeleton *Skeleton
+
+	// Weights maps every vertex of the rest-pose mesh to
+	// the joints which influence it.
+	// Weights for each vertex should sum to 1.
+	Weights *CoordMap[[]JointWeight]
+
+	// Method determines how joint transformations are
+	// combined.
+	Method SkinningMethod
+}
+
+// NewSkin creates a Skin using the weights from
+// Skeleton.AutoWeights().
+func NewSkin(s *Skeleton, restMesh *Mesh, method SkinningMethod) *Skin {
+	return &Skin{
+		Skeleton: s,
+		Weights:  s.AutoWeights(restMesh, 0),
+		Method:   method,
+	}
+}
+
+// Apply deforms the rest-pose mesh into the given pose.
+//
+// Every vertex of the mesh must have weights.
+func (s *Skin) Apply(m *Mesh, p *Pose) *Mesh {
+	return m.MapCoords(s.PoseFunc(p))
+}
+
+// PoseFunc gets a function which maps rest-pose vertices
+// to their positions in the given pose.
+//
+// The function panics if it is called on a coordinate
+// without weights.
+func (s *Skin) PoseFunc(p *Pose) func(c Coord3D) Coord3D {
+	transforms := s.Skeleton.Transforms(p)
+	var matrices []*Matrix3
+	var translations []Coord3D
+	if s.Method == SkinningLinearBlend {
+		matrices = make([]*Matrix3, len(transforms))
+		translations = make([]Coord3D, len(transforms))
+		for i, t := range transforms {
+			matrices[i] = t.Rotation().Matrix3()
+			translations[i] = t.Translation()
+		}
+	}
+	return func(c Coord3D) Coord3D {
+		weights, ok := s.Weights.Load(c)
+		if !ok {
+			panic("missing skinning weights for vertex")
+		}
+		switch s.Method {
+		case SkinningLinearBlend:
+			var res Coord3D
+			for _, w := range weights {
+				p := matrices[w.Joint].MulColumn(c).Add(translations[w.Joint])
+				res = res.Add(p.Scale(w.Weight))
+			}
+			return res
+		case SkinningDualQuaternion:
+			dqs := make([]DualQuaternion, len(weights))
+			ws := make([]float64, len(weights))
+			for i, w := range weights {
+				dqs[i] = transforms[w.Joint]
+				ws[i] = w.Weight
+			}
+			return BlendDualQuaternions(dqs, ws).Apply(c)
+		default:
+			panic("unknown skinning method")
+		}
+	}
+}
+
+func normalizeJointWeights(weights []JointWeight) {
+	var sum float64
+	for _, w := range weights {
+		sum += w.Weight
+	}
+	for i := range weights {
+		weights[i].Weight /= sum
+	}
+}
@@ -0,0 +1,74 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSkinning(t *testing.T) {
+	skeleton := NewSkeleton()
+	shoulder := skeleton.AddJoint("shoulder", -1, X(0))
+	elbow := skeleton.AddJoint("elbow", shoulder, X(1))
+	skeleton.AddJoint("hand", elbow, X(2))
+	if skeleton.Joint("elbow") != elbow {
+		t.Fatal("unexpected joint index")
+	}
+
+	arm := MarchingCubesSearch(&Cylinder{P1: X(0), P2: X(2), Radius: 0.2}, 0.05, 8)
+	methods := map[string]SkinningMethod{
+		"LinearBlend":    SkinningLinearBlend,
+		"DualQuaternion": SkinningDualQuaternion,
+	}
+	for name, method := range methods {
+		t.Run(name, func(t *testing.T) {
+			skin := NewSkin(skeleton, arm, method)
+
+			restFn := skin.PoseFunc(skeleton.NewPose())
+			for _, c := range arm.VertexSlice() {
+				if c1 := restFn(c); c1.Dist(c) > 1e-8 {
+					t.Fatalf("rest pose moved vertex %v to %v", c, c1)
+				}
+			}
+
+			pose := skeleton.NewPose()
+			pose.Rotations[elbow] = NewQuaternionRotation(Z(1), math.Pi/2)
+			pose.Translation = Z(3)
+			poseFn := skin.PoseFunc(pose)
+			for _, c := range arm.VertexSlice() {
+				var expected Coord3D
+				if c.X > 1.5 {
+					expected = XYZ(1-c.Y, c.X-1, c.Z+3)
+				} else if c.X < 0.5 {
+					expected = c.Add(Z(3))
+				} else {
+					continue
+				}
+				if actual := poseFn(c); actual.Dist(expected) > 0.02 {
+					t.Fatalf("vertex %v: expected %v but got %v", c, expected, actual)
+				}
+			}
+
+			MustValidateMesh(t, skin.Apply(arm, pose), true)
+		})
+	}
+
+	t.Run("Twist", func(t *testing.T) {
+		// Linear blending famously collapses twisted
+		// joints, while dual quaternions preserve volume.
+		pose := skeleton.NewPose()
+		pose.Rotations[elbow] = NewQuaternionRotation(X(1), math.Pi*0.9)
+
+		volumes := map[SkinningMethod]float64{}
+		for _, method := range methods {
+			volumes[method] = NewSkin(skeleton, arm, method).Apply(arm, pose).Volume()
+		}
+		restVolume := arm.Volume()
+		if v := volumes[SkinningDualQuaternion]; math.Abs(v-restVolume) > 0.05*restVolume {
+			t.Errorf("expected DQS volume near %f but got %f", restVolume, v)
+		}
+		if volumes[SkinningLinearBlend] >= volumes[SkinningDualQuaternion] {
+			t.Errorf("expected LBS volume %f to be less than DQS volume %f",
+				volumes[SkinningLinearBlend], volumes[SkinningDualQuaternion])
+		}
+	})
+}
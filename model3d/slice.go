@@ -0,0 +1,153 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+	"github.com/unixpickle/model3d/model2d"
+)
+
+// SliceMesh computes the cross-section of a mesh at a
+// plane perpendicular to an axis, as a 2D mesh.
+//
+// The axis is 0, 1, or 2 for X, Y, or Z respectively, and
+// the axisValue is the value for the axis at which the
+// plane is constructed. As with CrossSectionSolid, the
+// remaining two axes are used as the 2D coordinates, in
+// order.
+//
+// See SliceMeshPlane for details.
+func SliceMesh(m *Mesh, axis int, axisValue float64) *model2d.Mesh {
+	switch axis {
+	case 0:
+		return SliceMeshPlane(m, X(axisValue), Y(1), Z(1))
+	case 1:
+		return SliceMeshPlane(m, Y(axisValue), X(1), Z(1))
+	case 2:
+		return SliceMeshPlane(m, Z(axisValue), X(1), Y(1))
+	default:
+		panic("invalid axis")
+	}
+}
+
+// SliceMeshPlane computes the cross-section of a mesh at
+// an arbitrary plane, as a 2D mesh.
+//
+// The plane passes through origin and is spanned by the
+// orthonormal vectors xAxis and yAxis, which are used as
+// the axes of the 2D coordinate system.
+//
+// If m is closed and manifold with outward-facing normals,
+// the result is closed and its normals face outward from
+// the cross-section, so holes in the cross-section have
+// inward-facing normals. Triangles which lie in the plane
+// do not contribute to the cross-section.
+func SliceMeshPlane(m *Mesh, origin, xAxis, yAxis Coord3D) *model2d.Mesh {
+	normal := xAxis.Cross(yAxis)
+	result := model2d.NewMesh()
+	m.Iterate(func(t *Triangle) {
+		if seg, ok := sliceTriangle(t, origin, normal, xAxis, yAxis); ok {
+			result.Add(seg)
+		}
+	})
+	return result
+}
+
+// SliceStack slices a mesh along the Z axis into layers of
+// the given height, as is done to 3D print a model.
+//
+// Layer i covers the range of Z values starting at
+// m.Min().Z+i*layerHeight, and is sliced at the middle of
+// this range. Enough layers are produced to cover the
+// entire mesh.
+//
+// See SliceMeshPlane for details about each layer.
+func SliceStack(m *Mesh, layerHeight float64) []*model2d.Mesh {
+	if layerHeight <= 0 {
+		panic("layer height must be positive")
+	}
+	if m.NumTriangles() == 0 {
+		return nil
+	}
+	minZ, maxZ := m.Min().Z, m.Max().Z
+	numLayers := essentials.MaxInt(1, int(math.Ceil((maxZ-minZ)/layerHeight)))
+	result := make([]*model2d.Mesh, numLayers)
+	layerZ := func(i int) float64 {
+		return minZ + (float64(i)+0.5)*layerHeight
+	}
+	for i := range result {
+		result[i] = model2d.NewMesh()
+	}
+	m.Iterate(func(t *Triangle) {
+		tMin, tMax := t.Min().Z, t.Max().Z
+		first := essentials.MaxInt(0, int(math.Floor((tMin-minZ)/layerHeight-0.5)))
+		last := essentials.MinInt(numLayers-1, int(math.Ceil((tMax-minZ)/layerHeight-0.5)))
+		for i := first; i <= last; i++ {
+			z := layerZ(i)
+			if z < tMin || z > tMax {
+				continue
+			}
+			if seg, ok := sliceTriangle(t, Z(z), Z(1), X(1), Y(1)); ok {
+				result[i].Add(seg)
+			}
+		}
+	})
+	return result
+}
+
+// sliceTriangle intersects a triangle with a plane and
+// projects the resulting segment onto the plane.
+func sliceTriangle(t *Triangle, origin, normal, xAxis, yAxis Coord3D) (*model2d.Segment, bool) {
+	var dists [3]float64
+	var numPositive int
+	for i, c := range t {
+		dists[i] = normal.Dot(c.Sub(origin))
+		// Points on the plane are treated as positive so
+		// that every edge is split at most once.
+		if dists[i] >= 0 {
+			numPositive++
+		}
+	}
+	if numPositive == 0 || numPositive == 3 {
+		return nil, false
+	}
+
+	// For a counter-clockwise triangle, the segment starts
+	// where an edge crosses from the negative side to the
+	// positive side, so that its normal agrees with the
+	// triangle's normal.
+	var start, end Coord3D
+	for i := 0; i < 3; i++ {
+		j := (i + 1) % 3
+		p1, p2 := t[i], t[j]
+		d1, d2 := dists[i], dists[j]
+		if (d1 >= 0) == (d2 >= 0) {
+			continue
+		}
+		isStart := d1 < 0
+
+		// Always interpolate from the negative endpoint so
+		// that triangles sharing an edge produce exactly
+		// the same point.
+		if d1 >= 0 {
+			p1, p2 = p2, p1
+			d1, d2 = d2, d1
+		}
+		point := p1.Add(p2.Sub(p1).Scale(d1 / (d1 - d2)))
+		if isStart {
+			start = point
+		} else {
+			end = point
+		}
+	}
+
+	project := func(c Coord3D) model2d.Coord {
+		diff := c.Sub(origin)
+		return model2d.XY(xAxis.Dot(diff), yAxis.Dot(diff))
+	}
+	seg := &model2d.Segment{project(start), project(end)}
+	if seg[0] == seg[1] {
+		return nil, false
+	}
+	return seg, true
+}
@@ -0,0 +1,62 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+)
+
+func TestSliceMesh(t *testing.T) {
+	sphere := NewMeshIcosphere(Origin, 1, 30)
+	checkSlice := func(t *testing.T, slice *model2d.Mesh, expectedArea float64) {
+		if !slice.Manifold() || len(slice.InconsistentVertices()) > 0 {
+			t.Fatal("slice is not manifold")
+		}
+		if area := slice.SignedArea(); math.Abs(area-expectedArea) > 0.01*expectedArea {
+			t.Errorf("expected area %f but got %f", expectedArea, area)
+		}
+	}
+	for axis := 0; axis < 3; axis++ {
+		checkSlice(t, SliceMesh(sphere, axis, 0.5), math.Pi*0.75)
+	}
+	rotation := Rotation(XYZ(1, 2, 3).Normalize(), 0.7)
+	checkSlice(t, SliceMeshPlane(sphere, XYZ(0.1, 0, 0), rotation.Apply(X(1)),
+		rotation.Apply(Y(1))), math.Pi*(1-0.01*math.Pow(rotation.Apply(Z(1)).X, 2)))
+
+	// Slicing through a vertex of the mesh should still
+	// produce a closed loop.
+	checkSlice(t, SliceMesh(NewMeshRect(Origin, XYZ(1, 2, 3)), 2, 0.5), 2)
+
+	// A torus has a hole in its cross-section.
+	torus := MarchingCubesSearch(&Torus{Axis: Z(1), OuterRadius: 1, InnerRadius: 0.3}, 0.02, 8)
+	slice := SliceMesh(torus, 2, 0)
+	checkSlice(t, slice, math.Pi*(1.3*1.3-0.7*0.7))
+	if loops := slice.Loops(); len(loops) != 2 {
+		t.Errorf("expected 2 loops but got %d", len(loops))
+	}
+
+	if slice := SliceMesh(sphere, 2, 2); slice.NumSegments() != 0 {
+		t.Errorf("expected empty slice but got %d segments", slice.NumSegments())
+	}
+}
+
+func TestSliceStack(t *testing.T) {
+	sphere := NewMeshIcosphere(Origin, 1, 30)
+	layers := SliceStack(sphere, 0.1)
+	if len(layers) != 20 {
+		t.Fatalf("expected 20 layers but got %d", len(layers))
+	}
+	minZ := sphere.Min().Z
+	for i, layer := range layers {
+		z := minZ + (float64(i)+0.5)*0.1
+		expected := SliceMesh(sphere, 2, z)
+		if layer.NumSegments() != expected.NumSegments() {
+			t.Errorf("layer %d: expected %d segments but got %d", i, expected.NumSegments(),
+				layer.NumSegments())
+		}
+		if a1, a2 := layer.SignedArea(), expected.SignedArea(); math.Abs(a1-a2) > 1e-8 {
+			t.Errorf("layer %d: expected area %f but got %f", i, a2, a1)
+		}
+	}
+}
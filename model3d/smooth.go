@@ -1,5 +1,7 @@
 package model3d
 
+import "github.com/unixpickle/essentials"
+
 // A MeshSmoother uses gradient descent to smooth out the
 // surface of a mesh by minimizing surface area.
 //
@@ -43,12 +45,19 @@ type MeshSmoother struct {
 	//
 	// This is independent of ConstraintDistance and
 	// ConstraintWeight, which can be used simultaneously.
+	//
+	// This may be called concurrently from multiple
+	// Goroutines.
 	ConstraintFunc func(origin, newCoord Coord3D) Coord3D
 
 	// HardConstraintFunc, if non-nil, is a function that
 	// returns true for all of the initial points that
 	// should not be modified at all.
 	HardConstraintFunc func(origin Coord3D) bool
+
+	// MaxGos, if specified, limits the number of Goroutines
+	// for parallel processing. If 0, GOMAXPROCS is used.
+	MaxGos int
 }
 
 // Smooth applies gradient descent to smooth the mesh.
@@ -81,34 +90,27 @@ func (m *MeshSmoother) smoothInternal(mesh *Mesh) (*indexMesh, []Coord3D) {
 		}
 	}
 
+	grads := newIndexMeshAreaGradients(im)
 	for step := 0; step < m.Iterations; step++ {
-		if m.ConstraintWeight != 0 {
-			for i, c := range newCoords {
+		grads.Compute(m.MaxGos)
+		essentials.ConcurrentMap(m.MaxGos, len(newCoords), func(i int) {
+			c := newCoords[i]
+			if m.ConstraintWeight != 0 {
 				d := origins[i].Sub(c)
-				if m.ConstraintDistance > 0 {
-					norm := d.Norm()
-					if norm <= m.ConstraintDistance {
-						continue
+				norm := d.Norm()
+				if m.ConstraintDistance <= 0 || norm > m.ConstraintDistance {
+					if m.ConstraintDistance > 0 {
+						d = d.Scale((norm - m.ConstraintDistance) / norm)
 					}
-					d = d.Scale((norm - m.ConstraintDistance) / norm)
+					c = c.Add(d.Scale(2 * m.ConstraintWeight * m.StepSize))
 				}
-				newCoords[i] = c.Add(d.Scale(2 * m.ConstraintWeight * m.StepSize))
 			}
-		}
-		if m.ConstraintFunc != nil {
-			for i, c := range newCoords {
+			if m.ConstraintFunc != nil {
 				grad := m.ConstraintFunc(origins[i], c)
-				newCoords[i] = c.Add(grad.Scale(m.StepSize))
+				c = c.Add(grad.Scale(m.StepSize))
 			}
-		}
-		for i := range im.Triangles {
-			indexTri := im.Triangles[i]
-			t := im.Triangle(i)
-			for i, grad := range t.AreaGradient() {
-				j := indexTri[i]
-				newCoords[j] = newCoords[j].Add(grad.Scale(-m.StepSize))
-			}
-		}
+			newCoords[i] = c.Add(grads.Vertex[i].Scale(-m.StepSize))
+		})
 		if hardConstraints != nil {
 			for _, i := range hardConstraints {
 				newCoords[i] = im.Coords[i]
@@ -153,25 +155,17 @@ func (v *VoxelSmoother) SmoothMapping(mesh *Mesh) *CoordMap[Coord3D] {
 func (v *VoxelSmoother) smoothInternal(mesh *Mesh) (*indexMesh, []Coord3D) {
 	im := newIndexMesh(mesh)
 	origins := append([]Coord3D{}, im.Coords...)
-	newCoords := append([]Coord3D{}, im.Coords...)
+	constraint := XYZ(v.MaxDistance, v.MaxDistance, v.MaxDistance)
+	grads := newIndexMeshAreaGradients(im)
 	for step := 0; step < v.Iterations; step++ {
-		for i := range im.Triangles {
-			indexTri := im.Triangles[i]
-			t := im.Triangle(i)
-			for i, grad := range t.AreaGradient() {
-				j := indexTri[i]
-				newCoords[j] = newCoords[j].Add(grad.Scale(-v.StepSize))
-			}
-		}
-		for i, c := range newCoords {
+		grads.Compute(0)
+		essentials.ConcurrentMap(0, len(im.Coords), func(i int) {
 			o := origins[i]
-			constraint := XYZ(v.MaxDistance, v.MaxDistance, v.MaxDistance)
+			c := im.Coords[i].Add(grads.Vertex[i].Scale(-v.StepSize))
 			c = c.Max(o.Sub(constraint))
 			c = c.Min(o.Add(constraint))
-			newCoords[i] = c
 			im.Coords[i] = c
-		}
-		copy(im.Coords, newCoords)
+		})
 	}
 	return im, origins
 }
@@ -227,6 +221,55 @@ func (i *indexMesh) Mesh() *Mesh {
 	return m
 }
 
+// indexMeshAreaGradients computes the gradient of the
+// surface area of an indexMesh with respect to each of its
+// coordinates.
+//
+// Gradients are computed per triangle, and then gathered
+// per vertex, so that both steps can be done in parallel
+// without any synchronization.
+type indexMeshAreaGradients struct {
+	Mesh *indexMesh
+
+	// Vertex stores the result of Compute().
+	Vertex []Coord3D
+
+	// Maps each vertex to the triangles containing it,
+	// stored as (triangle index, corner index) pairs.
+	vertexTris [][][2]int
+	triGrads   []Triangle
+}
+
+func newIndexMeshAreaGradients(im *indexMesh) *indexMeshAreaGradients {
+	vertexTris := make([][][2]int, len(im.Coords))
+	for i, t := range im.Triangles {
+		for j, c := range t {
+			vertexTris[c] = append(vertexTris[c], [2]int{i, j})
+		}
+	}
+	return &indexMeshAreaGradients{
+		Mesh:       im,
+		Vertex:     make([]Coord3D, len(im.Coords)),
+		vertexTris: vertexTris,
+		triGrads:   make([]Triangle, len(im.Triangles)),
+	}
+}
+
+// Compute updates i.Vertex for the current coordinates.
+func (i *indexMeshAreaGradients) Compute(maxGos int) {
+	essentials.ConcurrentMap(maxGos, len(i.triGrads), func(j int) {
+		t := i.Mesh.Triangle(j)
+		i.triGrads[j] = *t.AreaGradient()
+	})
+	essentials.ConcurrentMap(maxGos, len(i.Vertex), func(j int) {
+		var sum Coord3D
+		for _, tc := range i.vertexTris[j] {
+			sum = sum.Add(i.triGrads[tc[0]][tc[1]])
+		}
+		i.Vertex[j] = sum
+	})
+}
+
 func (i *indexMesh) Mapping(sources []Coord3D) *CoordMap[Coord3D] {
 	res := NewCoordMap[Coord3D]()
 	for j, s := range sources {
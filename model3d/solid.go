@@ -433,6 +433,67 @@ func ProfileSolid(solid2d model2d.Solid, minZ, maxZ float64) Solid {
 	})
 }
 
+// ExtrudeOptions configures Extrude.
+//
+// The zero value extrudes a profile straight up, just like
+// ProfileSolid.
+type ExtrudeOptions struct {
+	// Twist is the number of degrees that the profile is
+	// rotated per unit of height, counter-clockwise around
+	// the Z axis.
+	Twist float64
+
+	// Taper is the fraction by which the profile shrinks
+	// from the bottom to the top of the extrusion. A value
+	// of 1 shrinks the profile to a point, and a negative
+	// value makes the profile grow instead.
+	//
+	// The profile is scaled around the origin.
+	Taper float64
+}
+
+// Extrude turns a 2D solid into a 3D solid by extruding it
+// along the Z axis, from Z=0 to Z=height.
+//
+// The profile can be rotated and scaled along the length
+// of the extrusion using opts, which may be nil.
+func Extrude(profile model2d.Solid, height float64, opts *ExtrudeOptions) Solid {
+	if opts == nil {
+		opts = &ExtrudeOptions{}
+	}
+	twist := opts.Twist * math.Pi / 180
+	taper := opts.Taper
+
+	min, max := profile.Min(), profile.Max()
+	topScale := 1 - taper
+	if twist != 0 {
+		radius := math.Max(min.Norm(), max.Norm())
+		radius = math.Max(radius, model2d.XY(min.X, max.Y).Norm())
+		radius = math.Max(radius, model2d.XY(max.X, min.Y).Norm())
+		radius *= math.Max(1, topScale)
+		min, max = model2d.XY(-radius, -radius), model2d.XY(radius, radius)
+	} else {
+		s := math.Max(topScale, 0)
+		min, max = min.Min(min.Scale(s)), max.Max(max.Scale(s))
+	}
+
+	return CheckedFuncSolid(
+		XYZ(min.X, min.Y, 0),
+		XYZ(max.X, max.Y, height),
+		func(c Coord3D) bool {
+			scale := 1 - taper*c.Z/height
+			if scale <= 0 {
+				return false
+			}
+			p := c.XY()
+			if twist != 0 {
+				p = model2d.NewMatrix2Rotation(-twist * c.Z).MulColumn(p)
+			}
+			return profile.Contains(p.Scale(1 / scale))
+		},
+	)
+}
+
 // CrossSectionSolid creates a 2D cross-section of a 3D
 // solid as a 2D solid.
 //
@@ -502,6 +563,46 @@ func RevolveSolid(solid model2d.Solid, axis Coord3D) Solid {
 	)
 }
 
+// Revolve is like RevolveSolid, but only sweeps the 2D
+// solid through the given angle (in radians) around the
+// axis, rather than a full revolution.
+//
+// The sweep starts with the x-axis of the 2D solid
+// pointing in the direction of the first vector returned
+// by axis.OrthoBasis(), and proceeds counter-clockwise
+// around the axis. If angle is at least 2*pi, this is
+// equivalent to RevolveSolid.
+func Revolve(solid model2d.Solid, axis Coord3D, angle float64) Solid {
+	full := RevolveSolid(solid, axis)
+	if angle >= 2*math.Pi {
+		return full
+	}
+	b1, b2 := revolveBasis(axis)
+	return CheckedFuncSolid(
+		full.Min(),
+		full.Max(),
+		func(c Coord3D) bool {
+			theta := math.Atan2(b2.Dot(c), b1.Dot(c))
+			if theta < 0 {
+				theta += 2 * math.Pi
+			}
+			return theta <= angle && full.Contains(c)
+		},
+	)
+}
+
+// revolveBasis creates an orthonormal basis for the plane
+// perpendicular to an axis, such that rotating from the
+// first vector to the second is counter-clockwise around
+// the axis.
+func revolveBasis(axis Coord3D) (Coord3D, Coord3D) {
+	axis = axis.Normalize()
+	b1, b2 := axis.OrthoBasis()
+	b1 = b1.Normalize()
+	b2 = axis.Cross(b1).Normalize()
+	return b1, b2
+}
+
 // A SolidMux computes many solid values in parallel and
 // returns a bitmap of containment for each solid.
 //
@@ -1,6 +1,12 @@
 package model3d
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+)
 
 func TestJoinedSolidOptimize(t *testing.T) {
 	js := JoinedSolid{}
@@ -99,3 +105,88 @@ func BenchmarkSolidMux(b *testing.B) {
 		}
 	})
 }
+
+func TestExtrude(t *testing.T) {
+	profile := model2d.NewRect(model2d.XY(0.5, -0.1), model2d.XY(1, 0.1))
+
+	t.Run("Straight", func(t *testing.T) {
+		expected := ProfileSolid(profile, 0, 2)
+		actual := Extrude(profile, 2, nil)
+		if actual.Min() != expected.Min() || actual.Max() != expected.Max() {
+			t.Errorf("expected bounds %v-%v but got %v-%v", expected.Min(), expected.Max(),
+				actual.Min(), actual.Max())
+		}
+		for i := 0; i < 10000; i++ {
+			c := NewCoord3DRandBounds(XYZ(-1, -1, -1), XYZ(2, 2, 3))
+			if actual.Contains(c) != expected.Contains(c) {
+				t.Fatalf("unexpected containment at %v", c)
+			}
+		}
+	})
+
+	t.Run("Twist", func(t *testing.T) {
+		solid := Extrude(profile, 2, &ExtrudeOptions{Twist: 45})
+		testSolidBounds(t, solid)
+		testPoints := map[Coord3D]bool{
+			XYZ(0.75, 0, 0.01): true,
+			XYZ(0, 0.75, 0.01): false,
+			XYZ(0.75, 0, 1.99): false,
+			XYZ(0, 0.75, 1.99): true,
+			XYZ(0.5, 0.5, 1):   true,
+			XYZ(0.5, -0.5, 1):  false,
+		}
+		for c, expected := range testPoints {
+			if actual := solid.Contains(c); actual != expected {
+				t.Errorf("coord %v: expected %v but got %v", c, expected, actual)
+			}
+		}
+	})
+
+	t.Run("Taper", func(t *testing.T) {
+		solid := Extrude(profile, 2, &ExtrudeOptions{Taper: 0.5})
+		testSolidBounds(t, solid)
+		testPoints := map[Coord3D]bool{
+			XYZ(0.55, 0, 0.01): true,
+			XYZ(0.55, 0, 1.99): false,
+			XYZ(0.3, 0, 1.99):  true,
+			XYZ(0.45, 0.07, 1): true,
+			XYZ(0.45, 0.08, 1): false,
+			XYZ(0.45, 0.07, 0): false,
+			XYZ(0.8, 0.07, 1):  false,
+		}
+		for c, expected := range testPoints {
+			if actual := solid.Contains(c); actual != expected {
+				t.Errorf("coord %v: expected %v but got %v", c, expected, actual)
+			}
+		}
+	})
+
+	t.Run("TwistTaper", func(t *testing.T) {
+		solid := Extrude(profile, 1, &ExtrudeOptions{Twist: 360, Taper: -1})
+		testSolidBounds(t, solid)
+	})
+}
+
+func TestRevolve(t *testing.T) {
+	profile := model2d.NewRect(model2d.XY(0.5, -0.5), model2d.XY(1, 0.5))
+	axis := XYZ(1, 2, -1).Normalize()
+	full := RevolveSolid(profile, axis)
+	for _, angle := range []float64{math.Pi / 3, math.Pi, 3 * math.Pi / 2, 2 * math.Pi} {
+		solid := Revolve(profile, axis, angle)
+		b1, b2 := axis.OrthoBasis()
+		b1 = b1.Normalize()
+		b2 = axis.Cross(b1)
+		for i := 0; i < 1000; i++ {
+			theta := rand.Float64() * 2 * math.Pi
+			radius := rand.Float64() * 1.5
+			height := rand.Float64()*1.5 - 0.75
+			c := b1.Scale(radius * math.Cos(theta)).Add(b2.Scale(radius * math.Sin(theta))).
+				Add(axis.Scale(height))
+			expected := full.Contains(c) && theta <= angle
+			if actual := solid.Contains(c); actual != expected {
+				t.Fatalf("angle %f: point at theta=%f radius=%f height=%f: expected %v but got %v",
+					angle, theta, radius, height, expected, actual)
+			}
+		}
+	}
+}
@@ -53,6 +53,11 @@ type SolidSurfaceEstimator struct {
 	//
 	// Default is DefaultSurfaceEstimatorNormalNoiseEpsilon.
 	NormalNoiseEpsilon float64
+
+	// NormalSDF, if non-nil, is used by Normal() to compute
+	// exact normals instead of estimating them with search.
+	// It should describe the same surface as Solid.
+	NormalSDF NormalSDF
 }
 
 // BisectInterp returns alpha in [min, max] to minimize the
@@ -108,7 +113,10 @@ func (s *SolidSurfaceEstimator) BisectInterior(p1, p2 Coord3D) Coord3D {
 // The point must be guaranteed to be on the boundary of
 // the surface, e.g. from Bisect().
 func (s *SolidSurfaceEstimator) Normal(c Coord3D) Coord3D {
-	if s.RandomSearchNormals {
+	if s.NormalSDF != nil {
+		normal, _ := s.NormalSDF.NormalSDF(c)
+		return normal
+	} else if s.RandomSearchNormals {
 		return s.esNormal(c)
 	} else {
 		return s.bisectNormal(c)
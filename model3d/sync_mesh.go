@@ -0,0 +1,139 @@
+// Generated from templates/sync_mesh.template
+
+package model3d
+
+import "sync"
+
+// A SyncMesh wraps a Mesh so that it can be read and
+// modified from concurrent Goroutines without any
+// external synchronization.
+//
+// Reads, such as Find() and Neighbors(), may proceed in
+// parallel, while modifications are performed
+// exclusively.
+//
+// Like a Mesh, a SyncMesh identifies triangles by pointer.
+type SyncMesh struct {
+	lock sync.RWMutex
+	mesh *Mesh
+}
+
+// NewSyncMesh creates an empty, concurrency-safe mesh.
+func NewSyncMesh() *SyncMesh {
+	return &SyncMesh{mesh: NewMesh()}
+}
+
+// NewSyncMeshMesh creates a concurrency-safe mesh which
+// takes ownership of m.
+//
+// The caller should not access m directly after this
+// call, except through Read() and Update().
+func NewSyncMeshMesh(m *Mesh) *SyncMesh {
+	return &SyncMesh{mesh: m}
+}
+
+// Add adds the triangle f to the mesh.
+func (s *SyncMesh) Add(f *Triangle) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.Add(f)
+}
+
+// AddMesh adds all the triangles from m to s.
+func (s *SyncMesh) AddMesh(m *Mesh) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.AddMesh(m)
+}
+
+// Remove removes the triangle f from the mesh.
+func (s *SyncMesh) Remove(f *Triangle) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.mesh.Remove(f)
+}
+
+// Contains checks if f has been added to the mesh.
+func (s *SyncMesh) Contains(f *Triangle) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Contains(f)
+}
+
+// NumTriangles returns the number of triangles in the mesh.
+func (s *SyncMesh) NumTriangles() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.NumTriangles()
+}
+
+// Find gets all the triangles that contain all of the
+// passed points.
+//
+// See Mesh.Find() for details.
+func (s *SyncMesh) Find(ps ...Coord3D) []*Triangle {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Find(ps...)
+}
+
+// Neighbors gets all the triangles with a side touching
+// a given triangle f.
+//
+// See Mesh.Neighbors() for details.
+func (s *SyncMesh) Neighbors(f *Triangle) []*Triangle {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Neighbors(f)
+}
+
+// TriangleSlice gets a snapshot of all the triangles
+// currently in the mesh.
+func (s *SyncMesh) TriangleSlice() []*Triangle {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.TriangleSlice()
+}
+
+// Iterate calls f for every triangle in a snapshot of
+// the mesh.
+//
+// The mesh is not locked while f is running, so f may
+// safely modify the mesh.
+func (s *SyncMesh) Iterate(f func(*Triangle)) {
+	for _, face := range s.TriangleSlice() {
+		f(face)
+	}
+}
+
+// Snapshot creates a shallow copy of the current mesh.
+//
+// The result is a regular Mesh which may be used freely
+// without affecting s.
+func (s *SyncMesh) Snapshot() *Mesh {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.mesh.Copy()
+}
+
+// Read calls f with the underlying mesh while holding a
+// read lock, allowing arbitrary read-only Mesh methods
+// to be used without copying the mesh.
+//
+// The mesh must not be modified or retained by f.
+func (s *SyncMesh) Read(f func(m *Mesh)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	f(s.mesh)
+}
+
+// Update calls f with the underlying mesh while holding
+// an exclusive lock, allowing several modifications to
+// be applied atomically.
+//
+// The mesh must not be retained by f.
+func (s *SyncMesh) Update(f func(m *Mesh)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f(s.mesh)
+}
@@ -0,0 +1,49 @@
+// Generated from templates/sync_mesh_test.template
+
+package model3d
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSyncMesh(t *testing.T) {
+	base := NewMeshIcosphere(Origin, 1, 5)
+	s := NewSyncMesh()
+
+	var wg sync.WaitGroup
+	faces := base.TriangleSlice()
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := i; j < len(faces); j += 4 {
+				s.Add(faces[j])
+				for _, p := range faces[j] {
+					for _, f := range s.Find(p) {
+						if !s.Contains(f) {
+							t.Errorf("found triangle is not in mesh")
+						}
+					}
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !meshesEqual(base, s.Snapshot()) {
+		t.Fatal("unexpected mesh after concurrent adds")
+	}
+	for _, f := range faces {
+		if expected, actual := len(base.Neighbors(f)), len(s.Neighbors(f)); expected != actual {
+			t.Fatalf("expected %d neighbors but got %d", expected, actual)
+		}
+	}
+
+	s.Iterate(func(f *Triangle) {
+		s.Remove(f)
+	})
+	if n := s.NumTriangles(); n != 0 {
+		t.Fatalf("expected empty mesh but got %d triangles", n)
+	}
+}
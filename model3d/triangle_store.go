@@ -0,0 +1,521 @@
+package model3d
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/unixpickle/model3d/fileformats"
+)
+
+const DefaultTriangleStoreChunkSize = 1 << 16
+
+// triangleStoreRecordSize is the number of bytes used to
+// store a single triangle on disk.
+const triangleStoreRecordSize = 9 * 8
+
+// A TriangleStore is an append-only list of triangles
+// which is kept in a file on disk rather than in memory.
+//
+// Triangle stores make it possible to work with meshes
+// that are too large to fit in RAM, such as raw 3D scan
+// data, by streaming through the triangles one chunk at a
+// time.
+//
+// Triangles are stored as raw little-endian float64
+// values, so no precision is lost between Add() and
+// Chunks().
+//
+// A TriangleStore is not safe for concurrent use.
+type TriangleStore struct {
+	file   *os.File
+	writer *bufio.Writer
+	count  int
+	temp   bool
+}
+
+// CreateTriangleStore creates an empty triangle store at
+// the given path, truncating any existing file.
+func CreateTriangleStore(path string) (*TriangleStore, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "create triangle store")
+	}
+	return newTriangleStore(f, 0, false), nil
+}
+
+// OpenTriangleStore opens an existing triangle store
+// which was previously created with CreateTriangleStore.
+//
+// New triangles may be appended to the opened store.
+func OpenTriangleStore(path string) (*TriangleStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, errors.Wrap(err, "open triangle store")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "open triangle store")
+	}
+	if info.Size()%triangleStoreRecordSize != 0 {
+		f.Close()
+		return nil, errors.New("open triangle store: unexpected file size")
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return nil, errors.Wrap(err, "open triangle store")
+	}
+	return newTriangleStore(f, int(info.Size()/triangleStoreRecordSize), false), nil
+}
+
+// NewTempTriangleStore creates an empty triangle store in
+// a new temporary file inside of dir.
+//
+// If dir is "", the default temporary directory is used.
+//
+// The file is deleted when the store is closed.
+func NewTempTriangleStore(dir string) (*TriangleStore, error) {
+	f, err := os.CreateTemp(dir, "triangles-*.bin")
+	if err != nil {
+		return nil, errors.Wrap(err, "create temporary triangle store")
+	}
+	return newTriangleStore(f, 0, true), nil
+}
+
+func newTriangleStore(f *os.File, count int, temp bool) *TriangleStore {
+	return &TriangleStore{
+		file:   f,
+		writer: bufio.NewWriter(f),
+		count:  count,
+		temp:   temp,
+	}
+}
+
+// Len returns the number of triangles in the store.
+func (t *TriangleStore) Len() int {
+	return t.count
+}
+
+// Add appends a triangle to the store.
+func (t *TriangleStore) Add(tri *Triangle) error {
+	var buf [triangleStoreRecordSize]byte
+	for i, c := range tri {
+		for j, x := range c.Array() {
+			binary.LittleEndian.PutUint64(buf[(i*3+j)*8:], math.Float64bits(x))
+		}
+	}
+	if _, err := t.writer.Write(buf[:]); err != nil {
+		return errors.Wrap(err, "add to triangle store")
+	}
+	t.count++
+	return nil
+}
+
+// AddTriangles appends a slice of triangles to the store.
+func (t *TriangleStore) AddTriangles(tris []*Triangle) error {
+	for _, tri := range tris {
+		if err := t.Add(tri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddMesh appends all of the triangles from m to the
+// store.
+func (t *TriangleStore) AddMesh(m *Mesh) error {
+	return t.AddTriangles(m.TriangleSlice())
+}
+
+// Chunks calls f with consecutive chunks of triangles
+// from the store, in the order they were added.
+//
+// Each chunk contains at most chunkSize triangles.
+// If chunkSize is 0, DefaultTriangleStoreChunkSize is
+// used.
+//
+// Every chunk is freshly allocated, so f may retain the
+// triangles it is passed. If f returns an error,
+// iteration stops and the error is returned.
+func (t *TriangleStore) Chunks(chunkSize int, f func(tris []*Triangle) error) error {
+	if chunkSize == 0 {
+		chunkSize = DefaultTriangleStoreChunkSize
+	}
+	if err := t.writer.Flush(); err != nil {
+		return errors.Wrap(err, "read triangle store")
+	}
+	buf := make([]byte, chunkSize*triangleStoreRecordSize)
+	for start := 0; start < t.count; start += chunkSize {
+		n := t.count - start
+		if n > chunkSize {
+			n = chunkSize
+		}
+		data := buf[:n*triangleStoreRecordSize]
+		if _, err := t.file.ReadAt(data, int64(start)*triangleStoreRecordSize); err != nil {
+			return errors.Wrap(err, "read triangle store")
+		}
+		slab := make([]Triangle, n)
+		chunk := make([]*Triangle, n)
+		for i := range slab {
+			record := data[i*triangleStoreRecordSize:]
+			for j := 0; j < 3; j++ {
+				var arr [3]float64
+				for k := range arr {
+					bits := binary.LittleEndian.Uint64(record[(j*3+k)*8:])
+					arr[k] = math.Float64frombits(bits)
+				}
+				slab[i][j] = NewCoord3DArray(arr)
+			}
+			chunk[i] = &slab[i]
+		}
+		if err := f(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bounds computes the bounding box of all the triangles
+// in the store.
+//
+// If the store is empty, the bounds are both zero.
+func (t *TriangleStore) Bounds() (min, max Coord3D, err error) {
+	first := true
+	err = t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			for _, c := range tri {
+				if first {
+					min, max = c, c
+					first = false
+				} else {
+					min = min.Min(c)
+					max = max.Max(c)
+				}
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// Mesh loads all of the triangles into an in-memory mesh.
+func (t *TriangleStore) Mesh() (*Mesh, error) {
+	m := NewMesh()
+	err := t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			m.Add(tri)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// WriteSTL writes the triangles in the store to w in the
+// binary STL format, streaming one chunk at a time.
+func (t *TriangleStore) WriteSTL(w io.Writer) error {
+	if int(uint32(t.count)) != t.count {
+		return errors.New("write STL: too many triangles for STL format")
+	}
+	bw := bufio.NewWriter(w)
+	writer, err := fileformats.NewSTLWriter(bw, uint32(t.count))
+	if err != nil {
+		return errors.Wrap(err, "write STL")
+	}
+	err = t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			verts := [3][3]float32{
+				castVector32(tri[0]),
+				castVector32(tri[1]),
+				castVector32(tri[2]),
+			}
+			if err := writer.WriteTriangle(castVector32(tri.Normal()), verts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "write STL")
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "write STL")
+	}
+	return nil
+}
+
+// Repair combines vertices which are within epsilon of
+// each other, writing the resulting triangles to out.
+//
+// The store is read twice. The first pass selects a set of
+// representative vertices, such that every vertex is
+// within epsilon of some representative and no two
+// representatives are within epsilon of each other. The
+// second pass moves every vertex to its nearest
+// representative. Thus, unlike Mesh.Repair, memory usage
+// grows with the number of distinct vertices after
+// merging, but not with the number of triangles.
+//
+// Triangles which become degenerate after merging are
+// dropped.
+//
+// The out argument must not be t itself.
+func (t *TriangleStore) Repair(out *TriangleStore, epsilon float64) error {
+	if out == t {
+		return errors.New("repair triangle store: output must differ from input")
+	}
+	reps := newVertexWelder(epsilon)
+	err := t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			for _, c := range tri {
+				reps.Insert(c)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "repair triangle store")
+	}
+	return t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			welded := &Triangle{reps.Weld(tri[0]), reps.Weld(tri[1]), reps.Weld(tri[2])}
+			if welded[0] == welded[1] || welded[1] == welded[2] ||
+				welded[0] == welded[2] {
+				continue
+			}
+			if err := out.Add(welded); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// vertexWelder stores representative vertices in a
+// spatial hash with a cell size of epsilon, so that all
+// representatives within epsilon of a point can be found
+// in the point's own cell and the 26 surrounding ones.
+type vertexWelder struct {
+	epsilon float64
+	cells   map[Coord3D][]Coord3D
+}
+
+func newVertexWelder(epsilon float64) *vertexWelder {
+	return &vertexWelder{epsilon: epsilon, cells: map[Coord3D][]Coord3D{}}
+}
+
+// Insert adds c as a representative unless there is
+// already a representative within epsilon of it.
+func (v *vertexWelder) Insert(c Coord3D) {
+	if _, ok := v.nearest(c); ok {
+		return
+	}
+	cell := v.cell(c)
+	v.cells[cell] = append(v.cells[cell], c)
+}
+
+// Weld returns the nearest representative to c, or c
+// itself if no representative is within epsilon.
+func (v *vertexWelder) Weld(c Coord3D) Coord3D {
+	if rep, ok := v.nearest(c); ok {
+		return rep
+	}
+	return c
+}
+
+func (v *vertexWelder) nearest(c Coord3D) (Coord3D, bool) {
+	center := v.cell(c)
+	var best Coord3D
+	bestDist := math.Inf(1)
+	for x := -1.0; x <= 1.0; x++ {
+		for y := -1.0; y <= 1.0; y++ {
+			for z := -1.0; z <= 1.0; z++ {
+				for _, rep := range v.cells[center.Add(XYZ(x, y, z))] {
+					if d := rep.Dist(c); d <= v.epsilon && d < bestDist {
+						best, bestDist = rep, d
+					}
+				}
+			}
+		}
+	}
+	return best, !math.IsInf(bestDist, 1)
+}
+
+func (v *vertexWelder) cell(c Coord3D) Coord3D {
+	return XYZ(
+		math.Floor(c.X/v.epsilon),
+		math.Floor(c.Y/v.epsilon),
+		math.Floor(c.Z/v.epsilon),
+	)
+}
+
+// Decimate applies d to the triangles in the store,
+// writing the simplified triangles to out.
+//
+// The store is split into slabs along its longest axis,
+// each of which contains roughly chunkSize triangles on
+// average, and each slab is decimated in memory.
+// Vertices on the boundary between slabs are never
+// removed, so the decimated slabs join up exactly.
+//
+// If chunkSize is 0, DefaultTriangleStoreChunkSize is
+// used. Temporary files for the slabs are created in
+// tmpDir, or in the default temporary directory if tmpDir
+// is "".
+func (t *TriangleStore) Decimate(out *TriangleStore, d *Decimator, chunkSize int,
+	tmpDir string) error {
+	if chunkSize == 0 {
+		chunkSize = DefaultTriangleStoreChunkSize
+	}
+	slabs, err := t.Partition(chunkSize, tmpDir)
+	if err != nil {
+		return errors.Wrap(err, "decimate triangle store")
+	}
+	defer func() {
+		for _, slab := range slabs {
+			slab.Close()
+		}
+	}()
+
+	for _, slab := range slabs {
+		m, err := slab.Mesh()
+		if err != nil {
+			return errors.Wrap(err, "decimate triangle store")
+		}
+		if err := out.AddMesh(decimateOpenMesh(d, m)); err != nil {
+			return errors.Wrap(err, "decimate triangle store")
+		}
+		slab.Close()
+	}
+	return nil
+}
+
+// decimateOpenMesh decimates a mesh which may have a
+// boundary, never removing vertices on the boundary so that
+// the result still joins up with neighboring meshes.
+func decimateOpenMesh(d *Decimator, m *Mesh) *Mesh {
+	boundary := NewCoordMap[bool]()
+	counts := NewEdgeToNumber[int]()
+	m.Iterate(func(tri *Triangle) {
+		for _, seg := range tri.Segments() {
+			counts.Add(seg, 1)
+		}
+	})
+	counts.Range(func(seg [2]Coord3D, count int) bool {
+		if count != 2 {
+			boundary.Store(seg[0], true)
+			boundary.Store(seg[1], true)
+		}
+		return true
+	})
+	dec := d.decimator()
+	dec.Fixed = func(c Coord3D) bool {
+		return boundary.Value(c)
+	}
+	return dec.Decimate(m)
+}
+
+// Partition splits the store into temporary stores, each
+// containing the triangles whose centers fall into one
+// slab along the longest axis of the bounding box.
+//
+// The number of slabs is chosen so that there are roughly
+// maxTriangles triangles per slab on average.
+// Empty slabs are omitted from the result.
+//
+// The caller is responsible for closing the resulting
+// stores, which deletes their files.
+func (t *TriangleStore) Partition(maxTriangles int, tmpDir string) ([]*TriangleStore, error) {
+	min, max, err := t.Bounds()
+	if err != nil {
+		return nil, err
+	}
+	size := max.Sub(min)
+	axis := 0
+	for i, x := range size.Array() {
+		if x > size.Array()[axis] {
+			axis = i
+		}
+	}
+	axisMin := min.Array()[axis]
+	axisSize := size.Array()[axis]
+
+	numSlabs := (t.count + maxTriangles - 1) / maxTriangles
+	if numSlabs < 1 || axisSize == 0 {
+		numSlabs = 1
+	}
+
+	slabs := make([]*TriangleStore, numSlabs)
+	closeAll := func() {
+		for _, s := range slabs {
+			if s != nil {
+				s.Close()
+			}
+		}
+	}
+	err = t.Chunks(0, func(tris []*Triangle) error {
+		for _, tri := range tris {
+			center := tri[0].Add(tri[1]).Add(tri[2]).Scale(1.0 / 3).Array()[axis]
+			idx := int(float64(numSlabs) * (center - axisMin) / axisSize)
+			if idx >= numSlabs {
+				idx = numSlabs - 1
+			} else if idx < 0 {
+				idx = 0
+			}
+			if slabs[idx] == nil {
+				s, err := NewTempTriangleStore(tmpDir)
+				if err != nil {
+					return err
+				}
+				slabs[idx] = s
+			}
+			if err := slabs[idx].Add(tri); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		closeAll()
+		return nil, errors.Wrap(err, "partition triangle store")
+	}
+
+	var res []*TriangleStore
+	for _, s := range slabs {
+		if s != nil {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+// Close flushes any pending writes and closes the
+// underlying file.
+//
+// If the store is temporary, its file is also deleted.
+//
+// It is safe to call Close more than once.
+func (t *TriangleStore) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	f := t.file
+	t.file = nil
+	flushErr := t.writer.Flush()
+	closeErr := f.Close()
+	if t.temp {
+		os.Remove(f.Name())
+	}
+	if flushErr != nil {
+		return errors.Wrap(flushErr, "close triangle store")
+	} else if closeErr != nil {
+		return errors.Wrap(closeErr, "close triangle store")
+	}
+	return nil
+}
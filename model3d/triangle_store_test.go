@@ -0,0 +1,197 @@
+package model3d
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestTriangleStoreRoundTrip(t *testing.T) {
+	mesh := NewMeshIcosphere(XYZ(1, 2, 3), 2, 5)
+	path := filepath.Join(t.TempDir(), "store.bin")
+	store, err := CreateTriangleStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddMesh(mesh); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = OpenTriangleStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if store.Len() != mesh.NumTriangles() {
+		t.Fatalf("expected %d triangles but got %d", mesh.NumTriangles(), store.Len())
+	}
+
+	var numChunks int
+	loaded := NewMesh()
+	err = store.Chunks(7, func(tris []*Triangle) error {
+		numChunks++
+		if len(tris) > 7 {
+			t.Fatalf("chunk too large: %d", len(tris))
+		}
+		for _, tri := range tris {
+			loaded.Add(tri)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (mesh.NumTriangles() + 6) / 7; numChunks != expected {
+		t.Errorf("expected %d chunks but got %d", expected, numChunks)
+	}
+	if !meshesEqual(mesh, loaded) {
+		t.Error("loaded mesh does not match original")
+	}
+
+	min, max, err := store.Bounds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min != mesh.Min() || max != mesh.Max() {
+		t.Errorf("unexpected bounds %v, %v", min, max)
+	}
+
+	var buf bytes.Buffer
+	if err := store.WriteSTL(&buf); err != nil {
+		t.Fatal(err)
+	}
+	stlMesh, err := ReadSTL(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stlMesh) != mesh.NumTriangles() {
+		t.Errorf("expected %d STL triangles but got %d", mesh.NumTriangles(), len(stlMesh))
+	}
+}
+
+func TestTriangleStoreRepair(t *testing.T) {
+	mesh := NewMeshIcosphere(Origin, 1, 4)
+	store, err := NewTempTriangleStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for _, tri := range mesh.TriangleSlice() {
+		perturbed := *tri
+		for i := range perturbed {
+			perturbed[i] = perturbed[i].Add(NewCoord3DRandUniform().Scale(1e-8))
+		}
+		store.Add(&perturbed)
+	}
+
+	out, err := NewTempTriangleStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	if err := store.Repair(out, 1e-4); err != nil {
+		t.Fatal(err)
+	}
+	repaired, err := out.Mesh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repaired.NeedsRepair() {
+		t.Error("repaired mesh still needs repair")
+	}
+}
+
+func TestTriangleStoreRepairCellBoundary(t *testing.T) {
+	const epsilon = 1e-4
+	repairedVertices := func(x1, x2 float64) []Coord3D {
+		store, err := NewTempTriangleStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		store.Add(&Triangle{XYZ(x1, 0, 0), XYZ(1, 0, 0), XYZ(0, 1, 0)})
+		store.Add(&Triangle{XYZ(x2, 0, 0), XYZ(0, 1, 0), XYZ(0, 0, 1)})
+
+		out, err := NewTempTriangleStore(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer out.Close()
+		if err := store.Repair(out, epsilon); err != nil {
+			t.Fatal(err)
+		}
+		mesh, err := out.Mesh()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []Coord3D
+		for _, v := range mesh.VertexSlice() {
+			if v.Y == 0 && v.Z == 0 && v.X < 0.5 {
+				res = append(res, v)
+			}
+		}
+		return res
+	}
+
+	for _, xs := range [][2]float64{
+		{0.1 * epsilon, 0.3 * epsilon},
+		// Either side of a cell boundary, for both
+		// flooring and rounding to a grid.
+		{epsilon - 1e-9, epsilon + 1e-9},
+		{0.5*epsilon - 1e-9, 0.5*epsilon + 1e-9},
+	} {
+		if vs := repairedVertices(xs[0], xs[1]); len(vs) != 1 {
+			t.Errorf("%v: expected one merged vertex but got %v", xs, vs)
+		}
+	}
+	if vs := repairedVertices(0, 1.5*epsilon); len(vs) != 2 {
+		t.Errorf("expected two separate vertices but got %v", vs)
+	}
+}
+
+func TestTriangleStoreRepairSelf(t *testing.T) {
+	store, err := NewTempTriangleStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	store.AddMesh(NewMeshIcosphere(Origin, 1, 2))
+	if err := store.Repair(store, 1e-4); err == nil {
+		t.Error("expected an error when repairing into the same store")
+	}
+}
+
+func TestTriangleStoreDecimate(t *testing.T) {
+	mesh := MarchingCubesSearch(&Sphere{Radius: 1}, 0.05, 8)
+	store, err := NewTempTriangleStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.AddMesh(mesh); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := NewTempTriangleStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	d := &Decimator{PlaneDistance: 1e-2, BoundaryDistance: 1e-2}
+	if err := store.Decimate(out, d, store.Len()/5, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	decimated, err := out.Mesh()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n1, n2 := decimated.NumTriangles(), mesh.NumTriangles(); n1 >= n2 {
+		t.Errorf("expected fewer than %d triangles but got %d", n2, n1)
+	}
+	if decimated.NeedsRepair() {
+		t.Error("decimated mesh has seams")
+	}
+}
@@ -16,6 +16,28 @@ func Triangulate(polygon []Coord2D) [][3]Coord2D {
 	return model2d.Triangulate(polygon)
 }
 
+// TriangulateMesh2D creates a flat mesh at the given z
+// value which covers the region enclosed by a 2D mesh.
+//
+// The triangulation is a constrained Delaunay
+// triangulation, as produced by
+// model2d.TriangulateMeshDelaunay, so the 2D mesh must
+// meet the same requirements.
+//
+// The normals of the resulting triangles face in the
+// positive z direction.
+func TriangulateMesh2D(m *model2d.Mesh, z float64) *Mesh {
+	res := NewMesh()
+	for _, t := range model2d.TriangulateMeshDelaunay(m) {
+		res.Add(&Triangle{
+			XYZ(t[1].X, t[1].Y, z),
+			XYZ(t[0].X, t[0].Y, z),
+			XYZ(t[2].X, t[2].Y, z),
+		})
+	}
+	return res
+}
+
 // TriangulateFace turns any simple polygon face into a
 // set of triangles.
 //
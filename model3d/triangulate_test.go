@@ -1,7 +1,10 @@
 package model3d
 
 import (
+	"math"
 	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
 )
 
 func TestTriangulateFace(t *testing.T) {
@@ -49,3 +52,23 @@ func TestTriangulateFaceColinear(t *testing.T) {
 		t.Fatalf("unexpected triangle count: %d", len(triangles))
 	}
 }
+
+func TestTriangulateMesh2D(t *testing.T) {
+	m2d := model2d.NewMeshPolar(func(theta float64) float64 {
+		return math.Cos(theta) + 1.5
+	}, 30)
+	mesh := TriangulateMesh2D(m2d, 0.5)
+	if a1, a2 := mesh.Area(), m2d.Area(); math.Abs(a1-a2) > 1e-8 {
+		t.Errorf("expected area %f but got %f", a2, a1)
+	}
+	mesh.Iterate(func(tri *Triangle) {
+		if n := tri.Normal(); n.Dist(Z(1)) > 1e-8 {
+			t.Fatalf("unexpected normal: %v", n)
+		}
+		for _, c := range tri {
+			if c.Z != 0.5 {
+				t.Fatalf("unexpected z value: %f", c.Z)
+			}
+		}
+	})
+}
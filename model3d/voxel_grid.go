@@ -0,0 +1,649 @@
+package model3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/essentials"
+)
+
+// A VoxelIndex is the integer coordinate of a voxel in a
+// VoxelSpace.
+type VoxelIndex [3]int
+
+// Add computes the element-wise sum of two indices.
+func (v VoxelIndex) Add(v1 VoxelIndex) VoxelIndex {
+	return VoxelIndex{v[0] + v1[0], v[1] + v1[1], v[2] + v1[2]}
+}
+
+// Min computes the element-wise minimum of two indices.
+func (v VoxelIndex) Min(v1 VoxelIndex) VoxelIndex {
+	return VoxelIndex{
+		essentials.MinInt(v[0], v1[0]),
+		essentials.MinInt(v[1], v1[1]),
+		essentials.MinInt(v[2], v1[2]),
+	}
+}
+
+// Max computes the element-wise maximum of two indices.
+func (v VoxelIndex) Max(v1 VoxelIndex) VoxelIndex {
+	return VoxelIndex{
+		essentials.MaxInt(v[0], v1[0]),
+		essentials.MaxInt(v[1], v1[1]),
+		essentials.MaxInt(v[2], v1[2]),
+	}
+}
+
+// A VoxelSpace determines the position and size of every
+// voxel in 3D space.
+//
+// The voxel at index (x, y, z) is a cube whose minimum
+// corner is Origin+Delta*(x, y, z), and whose side length
+// is Delta.
+//
+// Voxel grids can only be combined if they use the same
+// VoxelSpace.
+type VoxelSpace struct {
+	Origin Coord3D
+	Delta  float64
+}
+
+// Index gets the index of the voxel containing c.
+func (v VoxelSpace) Index(c Coord3D) VoxelIndex {
+	rel := c.Sub(v.Origin).Scale(1 / v.Delta)
+	return VoxelIndex{
+		int(math.Floor(rel.X)),
+		int(math.Floor(rel.Y)),
+		int(math.Floor(rel.Z)),
+	}
+}
+
+// Min gets the minimum corner of the voxel.
+func (v VoxelSpace) Min(idx VoxelIndex) Coord3D {
+	return v.Origin.Add(XYZ(float64(idx[0]), float64(idx[1]), float64(idx[2])).Scale(v.Delta))
+}
+
+// Center gets the center of the voxel.
+func (v VoxelSpace) Center(idx VoxelIndex) Coord3D {
+	return v.Min(idx).AddScalar(v.Delta / 2)
+}
+
+// A VoxelGrid is a 3D grid of boolean voxels, like a 3D
+// version of model2d.Bitmap.
+//
+// A VoxelGrid is also a Solid which contains the points
+// inside of its true voxels, so it can be combined with
+// other solids or meshed with any of the usual algorithms.
+// The Mesh() methods of grids are often more convenient,
+// since they align the mesh with the voxels.
+type VoxelGrid interface {
+	Solid
+
+	// Space gets the VoxelSpace for the grid.
+	Space() VoxelSpace
+
+	// Get gets the value of a voxel.
+	//
+	// Voxels outside of the grid are always false.
+	Get(idx VoxelIndex) bool
+
+	// Set sets the value of a voxel.
+	Set(idx VoxelIndex, value bool)
+
+	// IndexBounds gets a minimum (inclusive) and maximum
+	// (exclusive) index which bound every true voxel.
+	IndexBounds() (min, max VoxelIndex)
+
+	// Iterate calls f for every true voxel.
+	Iterate(f func(idx VoxelIndex))
+
+	// Count gets the number of true voxels.
+	Count() int
+}
+
+// A DenseVoxelGrid is a VoxelGrid which stores every voxel
+// in a fixed rectangular region of a VoxelSpace.
+type DenseVoxelGrid struct {
+	VoxelSpace
+
+	// MinIndex is the index of the first voxel in Data.
+	MinIndex VoxelIndex
+
+	// Size is the number of voxels along each axis.
+	Size [3]int
+
+	// Data stores every voxel, ordered by x, then y, then
+	// z, so that x changes most rapidly.
+	Data []bool
+}
+
+// NewDenseVoxelGrid creates an empty grid containing the
+// voxels from min (inclusive) to max (exclusive).
+func NewDenseVoxelGrid(space VoxelSpace, min, max VoxelIndex) *DenseVoxelGrid {
+	var size [3]int
+	for i := range size {
+		size[i] = essentials.MaxInt(0, max[i]-min[i])
+	}
+	return &DenseVoxelGrid{
+		VoxelSpace: space,
+		MinIndex:   min,
+		Size:       size,
+		Data:       make([]bool, size[0]*size[1]*size[2]),
+	}
+}
+
+// NewDenseVoxelGridSolid rasterizes a solid into a grid.
+//
+// Each voxel is set if its center is inside the solid, and
+// the grid covers the bounds of the solid.
+func NewDenseVoxelGridSolid(space VoxelSpace, s Solid) *DenseVoxelGrid {
+	min, max := voxelSolidBounds(space, s)
+	res := NewDenseVoxelGrid(space, min, max)
+	essentials.ConcurrentMap(0, res.Size[2], func(z int) {
+		for y := 0; y < res.Size[1]; y++ {
+			for x := 0; x < res.Size[0]; x++ {
+				idx := res.MinIndex.Add(VoxelIndex{x, y, z})
+				if s.Contains(space.Center(idx)) {
+					res.Data[res.offset(idx)] = true
+				}
+			}
+		}
+	})
+	return res
+}
+
+// Space gets d.VoxelSpace.
+func (d *DenseVoxelGrid) Space() VoxelSpace {
+	return d.VoxelSpace
+}
+
+// Min gets the minimum corner of the grid's region.
+func (d *DenseVoxelGrid) Min() Coord3D {
+	return d.VoxelSpace.Min(d.MinIndex)
+}
+
+// Max gets the maximum corner of the grid's region.
+func (d *DenseVoxelGrid) Max() Coord3D {
+	return d.VoxelSpace.Min(d.maxIndex())
+}
+
+// Contains checks if c is inside a true voxel.
+func (d *DenseVoxelGrid) Contains(c Coord3D) bool {
+	return d.Get(d.Index(c))
+}
+
+// Get gets the value of a voxel.
+func (d *DenseVoxelGrid) Get(idx VoxelIndex) bool {
+	if !d.inBounds(idx) {
+		return false
+	}
+	return d.Data[d.offset(idx)]
+}
+
+// Set sets the value of a voxel.
+//
+// The voxel must be within the grid's region.
+func (d *DenseVoxelGrid) Set(idx VoxelIndex, value bool) {
+	if !d.inBounds(idx) {
+		panic("voxel index out of bounds")
+	}
+	d.Data[d.offset(idx)] = value
+}
+
+// IndexBounds gets the region covered by the grid.
+func (d *DenseVoxelGrid) IndexBounds() (min, max VoxelIndex) {
+	return d.MinIndex, d.maxIndex()
+}
+
+// Iterate calls f for every true voxel.
+func (d *DenseVoxelGrid) Iterate(f func(idx VoxelIndex)) {
+	var i int
+	for z := 0; z < d.Size[2]; z++ {
+		for y := 0; y < d.Size[1]; y++ {
+			for x := 0; x < d.Size[0]; x++ {
+				if d.Data[i] {
+					f(d.MinIndex.Add(VoxelIndex{x, y, z}))
+				}
+				i++
+			}
+		}
+	}
+}
+
+// Count gets the number of true voxels.
+func (d *DenseVoxelGrid) Count() int {
+	var res int
+	for _, v := range d.Data {
+		if v {
+			res++
+		}
+	}
+	return res
+}
+
+// Copy creates a copy of the grid.
+func (d *DenseVoxelGrid) Copy() *DenseVoxelGrid {
+	return d.resized(d.MinIndex, d.maxIndex())
+}
+
+// Sparse creates a sparse copy of the grid.
+func (d *DenseVoxelGrid) Sparse() *SparseVoxelGrid {
+	res := NewSparseVoxelGrid(d.VoxelSpace)
+	d.Iterate(func(idx VoxelIndex) {
+		res.Set(idx, true)
+	})
+	return res
+}
+
+// Mesh creates a closed mesh around the true voxels.
+//
+// See VoxelGridMesh for details.
+func (d *DenseVoxelGrid) Mesh() *Mesh {
+	return VoxelGridMesh(d)
+}
+
+// Union creates a grid where each voxel is true if it is
+// true in either d or g.
+// The result covers the regions of both grids.
+func (d *DenseVoxelGrid) Union(g VoxelGrid) *DenseVoxelGrid {
+	min, max := g.IndexBounds()
+	res := d.resized(d.MinIndex.Min(min), d.maxIndex().Max(max))
+	voxelUnion(res, g)
+	return res
+}
+
+// Intersect creates a grid where each voxel is true if it
+// is true in both d and g.
+func (d *DenseVoxelGrid) Intersect(g VoxelGrid) *DenseVoxelGrid {
+	res := d.Copy()
+	voxelIntersect(res, g)
+	return res
+}
+
+// Subtract creates a grid where each voxel is true if it
+// is true in d but not in g.
+func (d *DenseVoxelGrid) Subtract(g VoxelGrid) *DenseVoxelGrid {
+	res := d.Copy()
+	voxelSubtract(res, g)
+	return res
+}
+
+// Dilate creates a grid where every voxel is true if any
+// voxel within the given radius is true in d.
+//
+// The neighborhood of each voxel is a ball, so that
+// dilation grows shapes uniformly in every direction.
+// The result covers a larger region than d, so that no
+// voxels are lost.
+func (d *DenseVoxelGrid) Dilate(radius int) *DenseVoxelGrid {
+	r := VoxelIndex{radius, radius, radius}
+	res := NewDenseVoxelGrid(
+		d.VoxelSpace,
+		d.MinIndex.Add(VoxelIndex{-radius, -radius, -radius}),
+		d.maxIndex().Add(r),
+	)
+	voxelDilate(res, d, radius)
+	return res
+}
+
+// Erode creates a grid where every voxel is true only if
+// every voxel within the given radius is true in d.
+//
+// Voxels outside of the grid are considered false, so
+// shapes are also eroded away from the edges of the grid.
+func (d *DenseVoxelGrid) Erode(radius int) *DenseVoxelGrid {
+	res := NewDenseVoxelGrid(d.VoxelSpace, d.MinIndex, d.maxIndex())
+	voxelErode(res, d, radius)
+	return res
+}
+
+// Open erodes and then dilates the grid, removing features
+// (such as noise and thin protrusions) which are smaller
+// than the radius.
+func (d *DenseVoxelGrid) Open(radius int) *DenseVoxelGrid {
+	return d.Erode(radius).Dilate(radius)
+}
+
+// Close dilates and then erodes the grid, filling gaps and
+// holes which are smaller than the radius.
+func (d *DenseVoxelGrid) Close(radius int) *DenseVoxelGrid {
+	return d.Dilate(radius).Erode(radius)
+}
+
+func (d *DenseVoxelGrid) maxIndex() VoxelIndex {
+	return d.MinIndex.Add(VoxelIndex(d.Size))
+}
+
+func (d *DenseVoxelGrid) inBounds(idx VoxelIndex) bool {
+	for i, x := range idx {
+		if x < d.MinIndex[i] || x >= d.MinIndex[i]+d.Size[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *DenseVoxelGrid) offset(idx VoxelIndex) int {
+	x := idx[0] - d.MinIndex[0]
+	y := idx[1] - d.MinIndex[1]
+	z := idx[2] - d.MinIndex[2]
+	return x + d.Size[0]*(y+d.Size[1]*z)
+}
+
+// resized copies the grid into a new region.
+func (d *DenseVoxelGrid) resized(min, max VoxelIndex) *DenseVoxelGrid {
+	res := NewDenseVoxelGrid(d.VoxelSpace, min, max)
+	d.Iterate(func(idx VoxelIndex) {
+		if res.inBounds(idx) {
+			res.Set(idx, true)
+		}
+	})
+	return res
+}
+
+// A SparseVoxelGrid is a VoxelGrid which only stores the
+// true voxels, and is unbounded.
+//
+// This is more efficient than a DenseVoxelGrid when most
+// voxels are false, e.g. for thin surfaces.
+type SparseVoxelGrid struct {
+	VoxelSpace
+
+	voxels map[VoxelIndex]struct{}
+}
+
+// NewSparseVoxelGrid creates an empty sparse grid.
+func NewSparseVoxelGrid(space VoxelSpace) *SparseVoxelGrid {
+	return &SparseVoxelGrid{
+		VoxelSpace: space,
+		voxels:     map[VoxelIndex]struct{}{},
+	}
+}
+
+// NewSparseVoxelGridSolid rasterizes a solid into a
+// sparse grid.
+//
+// Each voxel is set if its center is inside the solid.
+func NewSparseVoxelGridSolid(space VoxelSpace, s Solid) *SparseVoxelGrid {
+	min, max := voxelSolidBounds(space, s)
+	numZ := essentials.MaxInt(0, max[2]-min[2])
+	layers := make([][]VoxelIndex, numZ)
+	essentials.ConcurrentMap(0, numZ, func(i int) {
+		z := min[2] + i
+		for y := min[1]; y < max[1]; y++ {
+			for x := min[0]; x < max[0]; x++ {
+				idx := VoxelIndex{x, y, z}
+				if s.Contains(space.Center(idx)) {
+					layers[i] = append(layers[i], idx)
+				}
+			}
+		}
+	})
+	res := NewSparseVoxelGrid(space)
+	for _, layer := range layers {
+		for _, idx := range layer {
+			res.voxels[idx] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Space gets s.VoxelSpace.
+func (s *SparseVoxelGrid) Space() VoxelSpace {
+	return s.VoxelSpace
+}
+
+// Min gets the minimum corner of the true voxels.
+func (s *SparseVoxelGrid) Min() Coord3D {
+	min, _ := s.IndexBounds()
+	return s.VoxelSpace.Min(min)
+}
+
+// Max gets the maximum corner of the true voxels.
+func (s *SparseVoxelGrid) Max() Coord3D {
+	_, max := s.IndexBounds()
+	return s.VoxelSpace.Min(max)
+}
+
+// Contains checks if c is inside a true voxel.
+func (s *SparseVoxelGrid) Contains(c Coord3D) bool {
+	return s.Get(s.Index(c))
+}
+
+// Get gets the value of a voxel.
+func (s *SparseVoxelGrid) Get(idx VoxelIndex) bool {
+	_, ok := s.voxels[idx]
+	return ok
+}
+
+// Set sets the value of a voxel.
+func (s *SparseVoxelGrid) Set(idx VoxelIndex, value bool) {
+	if value {
+		s.voxels[idx] = struct{}{}
+	} else {
+		delete(s.voxels, idx)
+	}
+}
+
+// IndexBounds computes the bounds of the true voxels.
+//
+// If there are no true voxels, min and max are both zero.
+func (s *SparseVoxelGrid) IndexBounds() (min, max VoxelIndex) {
+	first := true
+	for idx := range s.voxels {
+		if first {
+			min, max = idx, idx
+			first = false
+		} else {
+			min = min.Min(idx)
+			max = max.Max(idx)
+		}
+	}
+	if !first {
+		max = max.Add(VoxelIndex{1, 1, 1})
+	}
+	return
+}
+
+// Iterate calls f for every true voxel, in an arbitrary
+// order.
+func (s *SparseVoxelGrid) Iterate(f func(idx VoxelIndex)) {
+	for idx := range s.voxels {
+		f(idx)
+	}
+}
+
+// Count gets the number of true voxels.
+func (s *SparseVoxelGrid) Count() int {
+	return len(s.voxels)
+}
+
+// Dense creates a dense copy of the grid, covering the
+// bounds of the true voxels.
+func (s *SparseVoxelGrid) Dense() *DenseVoxelGrid {
+	min, max := s.IndexBounds()
+	res := NewDenseVoxelGrid(s.VoxelSpace, min, max)
+	s.Iterate(func(idx VoxelIndex) {
+		res.Set(idx, true)
+	})
+	return res
+}
+
+// Mesh creates a closed mesh around the true voxels.
+//
+// See VoxelGridMesh for details.
+func (s *SparseVoxelGrid) Mesh() *Mesh {
+	return VoxelGridMesh(s)
+}
+
+// Union creates a grid where each voxel is true if it is
+// true in either s or g.
+func (s *SparseVoxelGrid) Union(g VoxelGrid) *SparseVoxelGrid {
+	res := s.Copy()
+	voxelUnion(res, g)
+	return res
+}
+
+// Intersect creates a grid where each voxel is true if it
+// is true in both s and g.
+func (s *SparseVoxelGrid) Intersect(g VoxelGrid) *SparseVoxelGrid {
+	res := s.Copy()
+	voxelIntersect(res, g)
+	return res
+}
+
+// Subtract creates a grid where each voxel is true if it
+// is true in s but not in g.
+func (s *SparseVoxelGrid) Subtract(g VoxelGrid) *SparseVoxelGrid {
+	res := s.Copy()
+	voxelSubtract(res, g)
+	return res
+}
+
+// Dilate creates a grid where every voxel is true if any
+// voxel within the given radius is true in s.
+//
+// The neighborhood of each voxel is a ball, so that
+// dilation grows shapes uniformly in every direction.
+func (s *SparseVoxelGrid) Dilate(radius int) *SparseVoxelGrid {
+	res := NewSparseVoxelGrid(s.VoxelSpace)
+	voxelDilate(res, s, radius)
+	return res
+}
+
+// Erode creates a grid where every voxel is true only if
+// every voxel within the given radius is true in s.
+func (s *SparseVoxelGrid) Erode(radius int) *SparseVoxelGrid {
+	res := NewSparseVoxelGrid(s.VoxelSpace)
+	voxelErode(res, s, radius)
+	return res
+}
+
+// Open erodes and then dilates the grid, removing features
+// (such as noise and thin protrusions) which are smaller
+// than the radius.
+func (s *SparseVoxelGrid) Open(radius int) *SparseVoxelGrid {
+	return s.Erode(radius).Dilate(radius)
+}
+
+// Close dilates and then erodes the grid, filling gaps and
+// holes which are smaller than the radius.
+func (s *SparseVoxelGrid) Close(radius int) *SparseVoxelGrid {
+	return s.Dilate(radius).Erode(radius)
+}
+
+// Copy creates a copy of the grid.
+func (s *SparseVoxelGrid) Copy() *SparseVoxelGrid {
+	res := NewSparseVoxelGrid(s.VoxelSpace)
+	for idx := range s.voxels {
+		res.voxels[idx] = struct{}{}
+	}
+	return res
+}
+
+// VoxelGridMesh creates a closed, manifold mesh around the
+// true voxels of a grid using marching cubes.
+//
+// The marching cubes lattice is aligned with the centers
+// of the voxels, so the surface passes through the middle
+// of the faces between true and false voxels. As a
+// result, corners of the voxels are beveled rather than
+// sharp.
+func VoxelGridMesh(g VoxelGrid) *Mesh {
+	if g.Count() == 0 {
+		return NewMesh()
+	}
+	space := g.Space()
+	min, max := g.IndexBounds()
+
+	// The maximum is padded slightly past the last voxel
+	// center so that rounding errors cannot drop the final
+	// layer of the lattice.
+	solid := FuncSolid(
+		space.Center(min),
+		space.Center(max.Add(VoxelIndex{-1, -1, -1})).AddScalar(space.Delta/4),
+		func(c Coord3D) bool {
+			return g.Get(space.Index(c))
+		},
+	)
+	return MarchingCubes(solid, space.Delta)
+}
+
+func voxelSolidBounds(space VoxelSpace, s Solid) (min, max VoxelIndex) {
+	min = space.Index(s.Min())
+	max = space.Index(s.Max()).Add(VoxelIndex{1, 1, 1})
+	return
+}
+
+func voxelCheckSpaces(g1, g2 VoxelGrid) {
+	if g1.Space() != g2.Space() {
+		panic("voxel grids must have the same VoxelSpace")
+	}
+}
+
+func voxelUnion(dst, g VoxelGrid) {
+	voxelCheckSpaces(dst, g)
+	g.Iterate(func(idx VoxelIndex) {
+		dst.Set(idx, true)
+	})
+}
+
+func voxelIntersect(dst, g VoxelGrid) {
+	voxelCheckSpaces(dst, g)
+	var remove []VoxelIndex
+	dst.Iterate(func(idx VoxelIndex) {
+		if !g.Get(idx) {
+			remove = append(remove, idx)
+		}
+	})
+	for _, idx := range remove {
+		dst.Set(idx, false)
+	}
+}
+
+func voxelSubtract(dst, g VoxelGrid) {
+	voxelCheckSpaces(dst, g)
+	var remove []VoxelIndex
+	dst.Iterate(func(idx VoxelIndex) {
+		if g.Get(idx) {
+			remove = append(remove, idx)
+		}
+	})
+	for _, idx := range remove {
+		dst.Set(idx, false)
+	}
+}
+
+func voxelBallOffsets(radius int) []VoxelIndex {
+	var res []VoxelIndex
+	for z := -radius; z <= radius; z++ {
+		for y := -radius; y <= radius; y++ {
+			for x := -radius; x <= radius; x++ {
+				if x*x+y*y+z*z <= radius*radius {
+					res = append(res, VoxelIndex{x, y, z})
+				}
+			}
+		}
+	}
+	return res
+}
+
+func voxelDilate(dst, src VoxelGrid, radius int) {
+	offsets := voxelBallOffsets(radius)
+	src.Iterate(func(idx VoxelIndex) {
+		for _, o := range offsets {
+			dst.Set(idx.Add(o), true)
+		}
+	})
+}
+
+func voxelErode(dst, src VoxelGrid, radius int) {
+	offsets := voxelBallOffsets(radius)
+	src.Iterate(func(idx VoxelIndex) {
+		for _, o := range offsets {
+			if !src.Get(idx.Add(o)) {
+				return
+			}
+		}
+		dst.Set(idx, true)
+	})
+}
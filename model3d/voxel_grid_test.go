@@ -0,0 +1,161 @@
+package model3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVoxelGridSolid(t *testing.T) {
+	space := VoxelSpace{Origin: XYZ(0.1, -0.2, 0.3), Delta: 0.05}
+	sphere := &Sphere{Center: XYZ(0.5, 0.3, -0.2), Radius: 0.7}
+	grids := map[string]VoxelGrid{
+		"Dense":  NewDenseVoxelGridSolid(space, sphere),
+		"Sparse": NewSparseVoxelGridSolid(space, sphere),
+	}
+	for name, grid := range grids {
+		t.Run(name, func(t *testing.T) {
+			volume := float64(grid.Count()) * math.Pow(space.Delta, 3)
+			expected := 4.0 / 3.0 * math.Pi * math.Pow(sphere.Radius, 3)
+			if math.Abs(volume-expected) > expected*0.02 {
+				t.Errorf("expected volume %f but got %f", expected, volume)
+			}
+			grid.Iterate(func(idx VoxelIndex) {
+				if !sphere.Contains(space.Center(idx)) {
+					t.Fatalf("voxel %v should not be set", idx)
+				}
+			})
+			for i := 0; i < 1000; i++ {
+				c := NewCoord3DRandBounds(sphere.Min(), sphere.Max())
+				idx := space.Index(c)
+				if grid.Contains(c) != sphere.Contains(space.Center(idx)) {
+					t.Fatalf("unexpected containment at %v", c)
+				}
+			}
+		})
+	}
+}
+
+func TestVoxelGridMesh(t *testing.T) {
+	space := VoxelSpace{Delta: 0.1}
+	solid := JoinedSolid{
+		&Sphere{Center: XYZ(0, 0, 0), Radius: 0.5},
+		&Rect{MinVal: XYZ(0.2, -0.2, -0.2), MaxVal: XYZ(1.0, 0.2, 0.2)},
+	}
+	grids := map[string]VoxelGrid{
+		"Dense":  NewDenseVoxelGridSolid(space, solid),
+		"Sparse": NewSparseVoxelGridSolid(space, solid),
+	}
+	for name, grid := range grids {
+		t.Run(name, func(t *testing.T) {
+			mesh := VoxelGridMesh(grid)
+			MustValidateMesh(t, mesh, true)
+
+			// The mesh bevels the corners of the voxels, so it
+			// should be slightly smaller than the voxels.
+			volume := mesh.Volume()
+			voxelVolume := float64(grid.Count()) * math.Pow(space.Delta, 3)
+			if volume > voxelVolume || volume < voxelVolume*0.85 {
+				t.Errorf("unexpected volume %f (voxels have volume %f)", volume, voxelVolume)
+			}
+		})
+	}
+
+	if NewSparseVoxelGrid(space).Mesh().NumTriangles() != 0 {
+		t.Error("empty grid should produce empty mesh")
+	}
+}
+
+func TestVoxelGridBooleans(t *testing.T) {
+	space := VoxelSpace{Delta: 1}
+	g1 := NewDenseVoxelGrid(space, VoxelIndex{0, 0, 0}, VoxelIndex{4, 4, 4})
+	g2 := NewSparseVoxelGrid(space)
+	for _, idx := range []VoxelIndex{{1, 1, 1}, {2, 2, 2}, {1, 2, 3}} {
+		g1.Set(idx, true)
+		g2.Set(idx.Add(VoxelIndex{0, 0, 1}), true)
+	}
+	g1.Set(VoxelIndex{0, 0, 0}, true)
+
+	expectVoxels := func(t *testing.T, g VoxelGrid, expected ...VoxelIndex) {
+		if g.Count() != len(expected) {
+			t.Fatalf("expected %d voxels but got %d", len(expected), g.Count())
+		}
+		for _, idx := range expected {
+			if !g.Get(idx) {
+				t.Errorf("missing voxel %v", idx)
+			}
+		}
+	}
+
+	t.Run("Union", func(t *testing.T) {
+		expected := []VoxelIndex{
+			{0, 0, 0}, {1, 1, 1}, {2, 2, 2}, {1, 2, 3}, {1, 1, 2}, {2, 2, 3}, {1, 2, 4},
+		}
+		expectVoxels(t, g1.Union(g2), expected...)
+		expectVoxels(t, g2.Union(g1), expected...)
+	})
+	t.Run("Intersect", func(t *testing.T) {
+		g := g2.Copy()
+		g.Set(VoxelIndex{1, 1, 1}, true)
+		expectVoxels(t, g1.Intersect(g), VoxelIndex{1, 1, 1})
+		expectVoxels(t, g.Intersect(g1), VoxelIndex{1, 1, 1})
+	})
+	t.Run("Subtract", func(t *testing.T) {
+		g := g2.Copy()
+		g.Set(VoxelIndex{1, 1, 1}, true)
+		expectVoxels(t, g1.Subtract(g), VoxelIndex{0, 0, 0}, VoxelIndex{2, 2, 2},
+			VoxelIndex{1, 2, 3})
+		expectVoxels(t, g.Subtract(g1), VoxelIndex{1, 1, 2}, VoxelIndex{2, 2, 3},
+			VoxelIndex{1, 2, 4})
+	})
+}
+
+func TestVoxelGridMorphology(t *testing.T) {
+	space := VoxelSpace{Delta: 1}
+
+	t.Run("DilateVoxel", func(t *testing.T) {
+		g := NewDenseVoxelGrid(space, VoxelIndex{}, VoxelIndex{1, 1, 1})
+		g.Set(VoxelIndex{}, true)
+		for _, dilated := range []VoxelGrid{g.Dilate(1), g.Sparse().Dilate(1)} {
+			// A radius 1 ball has the center and 6 neighbors.
+			if dilated.Count() != 7 {
+				t.Errorf("expected 7 voxels but got %d", dilated.Count())
+			}
+			if !dilated.Get(VoxelIndex{0, 0, -1}) || dilated.Get(VoxelIndex{1, 1, 0}) {
+				t.Error("unexpected dilated voxels")
+			}
+		}
+	})
+
+	t.Run("ErodeBox", func(t *testing.T) {
+		g := NewDenseVoxelGrid(space, VoxelIndex{}, VoxelIndex{5, 6, 7})
+		for i := range g.Data {
+			g.Data[i] = true
+		}
+		for _, eroded := range []VoxelGrid{g.Erode(1), g.Sparse().Erode(1)} {
+			if eroded.Count() != 3*4*5 {
+				t.Errorf("expected %d voxels but got %d", 3*4*5, eroded.Count())
+			}
+			if eroded.Get(VoxelIndex{0, 1, 1}) || !eroded.Get(VoxelIndex{1, 1, 1}) {
+				t.Error("unexpected eroded voxels")
+			}
+		}
+	})
+
+	t.Run("OpenClose", func(t *testing.T) {
+		box := &Rect{MinVal: XYZ(0, 0, 0), MaxVal: XYZ(10, 10, 10)}
+		g := NewSparseVoxelGridSolid(space, box)
+
+		noisy := g.Copy()
+		noisy.Set(VoxelIndex{-5, -5, -5}, true)
+		noisy.Set(VoxelIndex{5, 5, 5}, false)
+		if opened := noisy.Open(1); opened.Get(VoxelIndex{-5, -5, -5}) {
+			t.Error("opening should remove isolated voxel")
+		}
+		if closed := noisy.Close(1); !closed.Get(VoxelIndex{5, 5, 5}) {
+			t.Error("closing should fill hole")
+		}
+		if closed := noisy.Dense().Close(1); !closed.Get(VoxelIndex{5, 5, 5}) {
+			t.Error("closing should fill hole")
+		}
+	})
+}
@@ -147,3 +147,149 @@ func (b *BiCGSTAB) Iter() Vec {
 
 	return b.x
 }
+
+// CGSolver implements LargeLinearSolver using the
+// (optionally preconditioned) conjugate gradient method
+// with a specified stopping condition.
+//
+// Unlike BiCGSTABSolver, this requires that the linear
+// operator is symmetric and positive definite, as is the
+// case for Laplacian systems with boundary constraints.
+// For such systems, CG typically converges in far fewer
+// operator applications than BiCGSTAB.
+//
+// The stopping criteria are the same as those of
+// BiCGSTABSolver, and at least one must be provided.
+type CGSolver struct {
+	MaxIters int
+
+	// If either MSE or MAE of the residual go below these
+	// values, the optimization will terminate early.
+	MSETolerance float64
+	MAETolerance float64
+
+	// Preconditioner, if non-nil, approximates the inverse
+	// of the operator to speed up convergence.
+	// It should be symmetric and positive definite, such as
+	// a JacobiPreconditioner or an IncompleteCholesky.
+	Preconditioner Preconditioner
+}
+
+// SolveLinearSystem iteratively runs CG until a stopping
+// criterion is met.
+func (c *CGSolver) SolveLinearSystem(op func(v Vec) Vec, x, initGuess Vec) Vec {
+	if len(x) == 0 {
+		return x.Zeros()
+	}
+	if c.MaxIters == 0 && c.MAETolerance <= 0 && c.MSETolerance <= 0 {
+		panic("no stopping criteria provided")
+	}
+	solver := NewCG(op, c.Preconditioner, x, initGuess)
+	var solution Vec
+	for i := 0; c.MaxIters == 0 || i < c.MaxIters; i++ {
+		solution = solver.Iter()
+		if c.MSETolerance != 0 || c.MAETolerance != 0 {
+			sqErr := 0.0
+			absErr := 0.0
+			for _, x := range solver.Residual() {
+				sqErr += x * x
+				absErr += math.Abs(x)
+			}
+			if math.IsNaN(absErr) {
+				panic("NaN detected during solving")
+			}
+			if sqErr < c.MSETolerance*float64(len(x)) || absErr < c.MAETolerance*float64(len(x)) {
+				break
+			}
+		}
+	}
+	return solution
+}
+
+// CG implements the preconditioned conjugate gradient
+// method for inverting a specific large symmetric positive
+// definite matrix.
+//
+// This can be instantiated with NewCG() and then iterated
+// via the Iter() method, which returns the current
+// solution.
+type CG struct {
+	Op             func(v Vec) Vec
+	Preconditioner Preconditioner
+	B              Vec
+
+	x  Vec
+	r  Vec
+	p  Vec
+	rz float64
+
+	// flag to disable more updates, since they might
+	// cause NaN.
+	terminate bool
+}
+
+// NewCG initializes the CG solver for the given linear
+// system, as implemented by op.
+//
+// If precond is nil, no preconditioning is used.
+// If initGuess is non-nil, it is the initial solution.
+func NewCG(op func(v Vec) Vec, precond Preconditioner, b, initGuess Vec) *CG {
+	if initGuess == nil {
+		initGuess = b.Zeros()
+	}
+	res := &CG{
+		Op:             op,
+		Preconditioner: precond,
+		B:              b,
+
+		x: initGuess,
+		r: b.Sub(op(initGuess)),
+	}
+	z := res.precondition(res.r)
+	res.p = z
+	res.rz = res.r.Dot(z)
+	return res
+}
+
+// Iter performs an iteration of the method and returns the
+// current estimated solution.
+func (c *CG) Iter() Vec {
+	if c.terminate {
+		return c.x
+	}
+	ap := c.Op(c.p)
+	pap := c.p.Dot(ap)
+	if c.rz == 0 || pap == 0 {
+		// Prevent NaN due to division-by-zero, since
+		// either the solution is exact or the search
+		// direction has degenerated.
+		c.terminate = true
+		return c.x
+	}
+	alpha := c.rz / pap
+	c.x = c.x.Add(c.p.Scale(alpha))
+	c.r = c.r.Sub(ap.Scale(alpha))
+
+	z := c.precondition(c.r)
+	rz := c.r.Dot(z)
+	beta := rz / c.rz
+	c.p = z.Add(c.p.Scale(beta))
+	c.rz = rz
+
+	return c.x
+}
+
+// Residual gets b-A*x for the current solution x.
+//
+// This is tracked incrementally, so it does not require an
+// extra application of the operator.
+func (c *CG) Residual() Vec {
+	return c.r
+}
+
+func (c *CG) precondition(r Vec) Vec {
+	if c.Preconditioner == nil {
+		return r
+	}
+	return c.Preconditioner.ApplyInverse(r)
+}
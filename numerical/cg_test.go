@@ -1,6 +1,10 @@
 package numerical
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
 
 func TestBiCGSTAB(t *testing.T) {
 	matrix := NewSparseMatrix(5)
@@ -30,3 +34,94 @@ func TestBiCGSTAB(t *testing.T) {
 		t.Errorf("expected %v but got %v", groundTruth, solution)
 	}
 }
+
+func TestCGSolver(t *testing.T) {
+	// Create a grid Laplacian with a few Dirichlet rows
+	// and irregular edge weights, which is SPD.
+	const side = 20
+	const size = side * side
+	matrix := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		x, y := i%side, i/side
+		diag := 0.0
+		if i%37 == 0 {
+			diag = 1
+		}
+		for _, n := range [][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+			if n[0] < 0 || n[1] < 0 || n[0] >= side || n[1] >= side {
+				continue
+			}
+			j := n[0] + n[1]*side
+			w := 1 + float64((i+j)%7)
+			matrix.Set(i, j, -w)
+			diag += w
+		}
+		matrix.Set(i, i, diag)
+	}
+
+	groundTruth := make(Vec, size)
+	for i := range groundTruth {
+		groundTruth[i] = rand.NormFloat64()
+	}
+	b := matrix.Apply(groundTruth)
+
+	var prevIters int
+	for _, precond := range []Preconditioner{
+		nil,
+		NewJacobiPreconditioner(matrix),
+		NewIncompleteCholesky(matrix),
+	} {
+		solver := NewCG(matrix.Apply, precond, b, nil)
+		var iters int
+		for iters = 0; iters < size*2; iters++ {
+			solver.Iter()
+			if solver.Residual().Norm() < 1e-8 {
+				break
+			}
+		}
+		solution := solver.Iter()
+		if d := solution.Dist(groundTruth); d > 1e-5 {
+			t.Errorf("preconditioner %T: solution off by %f", precond, d)
+		}
+		if prevIters != 0 && iters > prevIters {
+			t.Errorf("preconditioner %T: took %d iters but previous took %d",
+				precond, iters, prevIters)
+		}
+		prevIters = iters
+
+		cgSolver := &CGSolver{
+			MaxIters:       size * 2,
+			MSETolerance:   1e-20,
+			Preconditioner: precond,
+		}
+		solution = cgSolver.SolveLinearSystem(matrix.Apply, b, nil)
+		if d := solution.Dist(groundTruth); d > 1e-5 {
+			t.Errorf("preconditioner %T: CGSolver solution off by %f", precond, d)
+		}
+	}
+}
+
+func TestIncompleteCholesky(t *testing.T) {
+	// For a tridiagonal matrix, there is no fill-in, so the
+	// incomplete decomposition is exact.
+	const size = 50
+	matrix := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			matrix.Set(i, i-1, -1)
+		}
+		matrix.Set(i, i, 2.5+rand.Float64())
+		if i+1 < size {
+			matrix.Set(i, i+1, -1)
+		}
+	}
+	ic := NewIncompleteCholesky(matrix)
+	inVec := make(Vec, size)
+	for i := range inVec {
+		inVec[i] = rand.NormFloat64()
+	}
+	inverted := matrix.Apply(ic.ApplyInverse(inVec))
+	if d := inverted.Dist(inVec); d > 1e-8 || math.IsNaN(d) {
+		t.Errorf("unexpected distance from target: %f", d)
+	}
+}
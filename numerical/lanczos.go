@@ -0,0 +1,235 @@
+package numerical
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/unixpickle/essentials"
+)
+
+// Lanczos approximates eigenpairs of a symmetric linear
+// operator using the Lanczos method with full
+// reorthogonalization.
+//
+// The operator acts on vectors of the given size, and
+// iters is the dimension of the Krylov subspace to build.
+// The extreme eigenvalues (largest and smallest) of the
+// operator converge first, so iters can usually be much
+// smaller than size when only a few extreme eigenpairs
+// are needed.
+//
+// The resulting Ritz values are sorted in ascending order,
+// and each Ritz vector has unit norm.
+// Fewer than iters pairs may be returned if an invariant
+// subspace is found early.
+func Lanczos(op func(v Vec) Vec, size, iters int) ([]float64, []Vec) {
+	if iters > size {
+		iters = size
+	}
+	if iters <= 0 {
+		return nil, nil
+	}
+
+	q := make(Vec, size)
+	for i := range q {
+		q[i] = rand.NormFloat64()
+	}
+	basis := []Vec{q.Normalize()}
+
+	var alphas, betas []float64
+	for {
+		cur := basis[len(basis)-1]
+		w := op(cur)
+		alphas = append(alphas, w.Dot(cur))
+		if len(basis) == iters {
+			break
+		}
+		// Two passes of Gram-Schmidt keep the basis
+		// orthogonal to working precision.
+		for pass := 0; pass < 2; pass++ {
+			for _, b := range basis {
+				w = w.Sub(b.Scale(w.Dot(b)))
+			}
+		}
+		beta := w.Norm()
+		if beta <= 1e-12*math.Abs(alphas[len(alphas)-1]) || beta == 0 {
+			break
+		}
+		betas = append(betas, beta)
+		basis = append(basis, w.Scale(1/beta))
+	}
+
+	values, tVectors := symmetricTridiagonalEigen(alphas, betas)
+	vectors := make([]Vec, len(values))
+	for i, tVec := range tVectors {
+		v := make(Vec, size)
+		for j, b := range basis {
+			v = v.Add(b.Scale(tVec[j]))
+		}
+		vectors[i] = v.Normalize()
+	}
+	return values, vectors
+}
+
+// SmallestEigenpairs computes the k smallest eigenvalues
+// and corresponding unit eigenvectors of a sparse,
+// symmetric, positive semi-definite matrix, such as a mesh
+// Laplacian or stiffness matrix.
+//
+// This uses the shift-and-invert Lanczos method, so the
+// matrix is factorized once with a SparseCholesky.
+//
+// The eigenvalues are sorted in ascending order.
+func SmallestEigenpairs(mat *SparseMatrix, k int) ([]float64, []Vec) {
+	size := len(mat.rows)
+	if k > size {
+		k = size
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+
+	// Add a small multiple of the identity so that the
+	// matrix is strictly positive definite even if it is
+	// singular, as is the case for Laplacians.
+	var maxDiag float64
+	for i := 0; i < size; i++ {
+		mat.Iterate(i, func(col int, x float64) {
+			if col == i {
+				maxDiag = math.Max(maxDiag, math.Abs(x))
+			}
+		})
+	}
+	shift := 1e-8 * math.Max(maxDiag, 1e-8)
+	shifted := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		hasDiag := false
+		mat.Iterate(i, func(col int, x float64) {
+			if col == i {
+				x += shift
+				hasDiag = true
+			}
+			shifted.Set(i, col, x)
+		})
+		if !hasDiag {
+			shifted.Set(i, i, shift)
+		}
+	}
+	chol := NewSparseCholesky(shifted)
+
+	iters := essentials.MinInt(size, 2*k+40)
+	values, vectors := Lanczos(chol.ApplyInverse, size, iters)
+
+	// The largest eigenvalues of the inverse correspond to
+	// the smallest eigenvalues of the original matrix.
+	k = essentials.MinInt(k, len(values))
+	resValues := make([]float64, k)
+	resVectors := make([]Vec, k)
+	for i := 0; i < k; i++ {
+		j := len(values) - (i + 1)
+		resValues[i] = 1/values[j] - shift
+		resVectors[i] = vectors[j]
+	}
+	return resValues, resVectors
+}
+
+// symmetricTridiagonalEigen computes the eigenvalues and
+// eigenvectors of a symmetric tridiagonal matrix with the
+// given diagonal and off-diagonal entries, using the
+// implicit QL method.
+//
+// The eigenvalues are sorted in ascending order.
+func symmetricTridiagonalEigen(diag, offDiag []float64) ([]float64, [][]float64) {
+	n := len(diag)
+	d := append([]float64{}, diag...)
+	e := make([]float64, n)
+	copy(e, offDiag)
+
+	// Columns of v are the eigenvectors.
+	v := make([][]float64, n)
+	for i := range v {
+		v[i] = make([]float64, n)
+		v[i][i] = 1
+	}
+
+	// Adapted from the tql2 routine in EISPACK, by way of
+	// the public-domain JAMA library.
+	var f, tst1 float64
+	eps := math.Pow(2, -52)
+	for l := 0; l < n; l++ {
+		tst1 = math.Max(tst1, math.Abs(d[l])+math.Abs(e[l]))
+		m := l
+		for m < n-1 && math.Abs(e[m]) > eps*tst1 {
+			m++
+		}
+		if m > l {
+			for {
+				g := d[l]
+				p := (d[l+1] - g) / (2 * e[l])
+				r := math.Hypot(p, 1)
+				if p < 0 {
+					r = -r
+				}
+				d[l] = e[l] / (p + r)
+				d[l+1] = e[l] * (p + r)
+				dl1 := d[l+1]
+				h := g - d[l]
+				for i := l + 2; i < n; i++ {
+					d[i] -= h
+				}
+				f += h
+
+				p = d[m]
+				c, c2, c3 := 1.0, 1.0, 1.0
+				el1 := e[l+1]
+				var s, s2 float64
+				for i := m - 1; i >= l; i-- {
+					c3 = c2
+					c2 = c
+					s2 = s
+					g = c * e[i]
+					h = c * p
+					r = math.Hypot(p, e[i])
+					e[i+1] = s * r
+					s = e[i] / r
+					c = p / r
+					p = c*d[i] - s*g
+					d[i+1] = h + s*(c*g+s*d[i])
+					for k := 0; k < n; k++ {
+						h = v[k][i+1]
+						v[k][i+1] = s*v[k][i] + c*h
+						v[k][i] = c*v[k][i] - s*h
+					}
+				}
+				p = -s * s2 * c3 * el1 * e[l] / dl1
+				e[l] = s * p
+				d[l] = c * p
+				if math.Abs(e[l]) <= eps*tst1 {
+					break
+				}
+			}
+		}
+		d[l] += f
+		e[l] = 0
+	}
+
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		return d[indices[i]] < d[indices[j]]
+	})
+	values := make([]float64, n)
+	vectors := make([][]float64, n)
+	for i, idx := range indices {
+		values[i] = d[idx]
+		vec := make([]float64, n)
+		for j := range vec {
+			vec[j] = v[j][idx]
+		}
+		vectors[i] = vec
+	}
+	return values, vectors
+}
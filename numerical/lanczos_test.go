@@ -0,0 +1,65 @@
+package numerical
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSmallestEigenpairs(t *testing.T) {
+	// The Laplacian of a path graph has known eigenvalues
+	// 2-2*cos(pi*j/n) for j=0...n-1.
+	const size = 200
+	matrix := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		var diag float64
+		for _, j := range []int{i - 1, i + 1} {
+			if j >= 0 && j < size {
+				matrix.Set(i, j, -1)
+				diag++
+			}
+		}
+		matrix.Set(i, i, diag)
+	}
+
+	values, vectors := SmallestEigenpairs(matrix, 5)
+	if len(values) != 5 || len(vectors) != 5 {
+		t.Fatalf("unexpected result lengths: %d, %d", len(values), len(vectors))
+	}
+	for j, value := range values {
+		expected := 2 - 2*math.Cos(math.Pi*float64(j)/size)
+		if math.Abs(value-expected) > 1e-8 {
+			t.Errorf("eigenvalue %d: expected %f but got %f", j, expected, value)
+		}
+		vec := vectors[j]
+		if math.Abs(vec.Norm()-1) > 1e-8 {
+			t.Errorf("eigenvector %d: unexpected norm %f", j, vec.Norm())
+		}
+		residual := matrix.Apply(vec).Sub(vec.Scale(value)).Norm()
+		if residual > 1e-6 {
+			t.Errorf("eigenvector %d: residual %f", j, residual)
+		}
+	}
+}
+
+func TestLanczos(t *testing.T) {
+	// With a full Krylov subspace, Lanczos recovers the
+	// entire spectrum of a diagonal operator.
+	diag := Vec{3, -1, 2.5, 7, 0.5, 4}
+	op := func(v Vec) Vec {
+		res := make(Vec, len(v))
+		for i, x := range v {
+			res[i] = x * diag[i]
+		}
+		return res
+	}
+	values, _ := Lanczos(op, len(diag), len(diag))
+	expected := []float64{-1, 0.5, 2.5, 3, 4, 7}
+	if len(values) != len(expected) {
+		t.Fatalf("expected %d values but got %d", len(expected), len(values))
+	}
+	for i, x := range expected {
+		if math.Abs(values[i]-x) > 1e-8 {
+			t.Errorf("value %d: expected %f but got %f", i, x, values[i])
+		}
+	}
+}
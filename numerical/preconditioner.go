@@ -0,0 +1,153 @@
+package numerical
+
+import "math"
+
+// A Preconditioner approximates the inverse of a linear
+// operator, and is used to speed up iterative solvers.
+//
+// SparseLU and SparseCholesky also implement this
+// interface, in which case they are exact (but expensive)
+// preconditioners.
+type Preconditioner interface {
+	// ApplyInverse computes an approximation of A^-1*x.
+	ApplyInverse(x Vec) Vec
+}
+
+// JacobiPreconditioner is a Preconditioner which scales
+// each component by the inverse of the matrix diagonal.
+//
+// This is very cheap to create and apply, and is effective
+// for matrices with widely varying diagonal entries, such
+// as cotangent Laplacians of irregular meshes.
+type JacobiPreconditioner struct {
+	invDiag Vec
+}
+
+// NewJacobiPreconditioner creates a JacobiPreconditioner
+// for the matrix.
+//
+// Zero diagonal entries are treated as 1.
+func NewJacobiPreconditioner(mat *SparseMatrix) *JacobiPreconditioner {
+	invDiag := make(Vec, len(mat.rows))
+	for i := range invDiag {
+		var diag float64
+		mat.Iterate(i, func(col int, x float64) {
+			if col == i {
+				diag += x
+			}
+		})
+		if diag == 0 {
+			invDiag[i] = 1
+		} else {
+			invDiag[i] = 1 / diag
+		}
+	}
+	return &JacobiPreconditioner{invDiag: invDiag}
+}
+
+// ApplyInverse computes D^-1*x, where D is the diagonal of
+// the matrix.
+func (j *JacobiPreconditioner) ApplyInverse(x Vec) Vec {
+	res := make(Vec, len(x))
+	for i, d := range j.invDiag {
+		res[i] = x[i] * d
+	}
+	return res
+}
+
+// IncompleteCholesky is a Preconditioner based on the
+// zero fill-in incomplete Cholesky decomposition, IC(0),
+// of a symmetric positive definite matrix.
+//
+// The factor L has the same sparsity pattern as the lower
+// triangle of the matrix, so it uses no more memory than
+// the matrix itself, unlike SparseCholesky.
+type IncompleteCholesky struct {
+	// Strictly lower-triangular entries, with columns
+	// sorted in each row.
+	lower *SparseMatrix
+	diag  []float64
+}
+
+// NewIncompleteCholesky computes the IC(0) decomposition
+// of a symmetric positive definite matrix.
+//
+// Only the lower triangle of the matrix is read.
+//
+// If the decomposition breaks down at some pivot, which
+// may happen even for positive definite matrices, the
+// corresponding diagonal entry of the original matrix is
+// used instead.
+// This panics if the matrix has a non-positive diagonal
+// entry.
+func NewIncompleteCholesky(mat *SparseMatrix) *IncompleteCholesky {
+	size := len(mat.rows)
+	lower := NewSparseMatrix(size)
+	diag := make([]float64, size)
+
+	row := newSparseMatrixMap(size)
+	for i := 0; i < size; i++ {
+		row.Clear()
+		var rowDiag float64
+		mat.Iterate(i, func(j int, x float64) {
+			if j < i {
+				row.Add(j, x)
+			} else if j == i {
+				rowDiag += x
+			}
+		})
+		if rowDiag <= 0 {
+			panic("matrix is not positive definite")
+		}
+
+		// Entries are finalized in order of column, so
+		// every entry used by the dot product below has
+		// already been computed.
+		pivot := rowDiag
+		row.IterateSorted(func(k int, x float64) {
+			lower.Iterate(k, func(j int, l float64) {
+				if row.contained[j] {
+					x -= row.data[j] * l
+				}
+			})
+			x /= diag[k]
+			row.data[k] = x
+			pivot -= x * x
+		})
+		if pivot <= 0 {
+			pivot = rowDiag
+		}
+		diag[i] = math.Sqrt(pivot)
+
+		row.IterateSorted(func(k int, x float64) {
+			lower.Set(i, k, x)
+		})
+	}
+
+	return &IncompleteCholesky{lower: lower, diag: diag}
+}
+
+// ApplyInverse computes (L*L^T)^-1*x.
+func (i *IncompleteCholesky) ApplyInverse(x Vec) Vec {
+	res := make(Vec, len(x))
+
+	// Solve L*y = x.
+	for row, b := range x {
+		i.lower.Iterate(row, func(col int, l float64) {
+			b -= l * res[col]
+		})
+		res[row] = b / i.diag[row]
+	}
+
+	// Solve L^T*z = y, scattering each solved entry into
+	// the remaining rows.
+	for row := len(res) - 1; row >= 0; row-- {
+		z := res[row] / i.diag[row]
+		res[row] = z
+		i.lower.Iterate(row, func(col int, l float64) {
+			res[col] -= l * z
+		})
+	}
+
+	return res
+}
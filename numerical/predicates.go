@@ -0,0 +1,307 @@
+package numerical
+
+import (
+	"math"
+	"math/big"
+)
+
+// Error bounds for the floating-point filters, from
+// "Adaptive Precision Floating-Point Arithmetic and Fast
+// Robust Geometric Predicates" (Shewchuk, 1997).
+var (
+	predicateEpsilon = math.Pow(2, -53)
+	ccwErrBoundA     = (3 + 16*predicateEpsilon) * predicateEpsilon
+	o3dErrBoundA     = (7 + 56*predicateEpsilon) * predicateEpsilon
+	iccErrBoundA     = (10 + 96*predicateEpsilon) * predicateEpsilon
+	ispErrBoundA     = (16 + 224*predicateEpsilon) * predicateEpsilon
+)
+
+// Orient2D computes a value which is positive if a, b, and
+// c are in counter-clockwise order, negative if they are
+// in clockwise order, and zero if they are colinear.
+//
+// The sign of the result is always exact. When the
+// floating-point approximation cannot be trusted, the
+// result is recomputed using exact arithmetic, in which
+// case its magnitude is only approximate.
+func Orient2D(a, b, c Vec2) float64 {
+	detLeft := (a[0] - c[0]) * (b[1] - c[1])
+	detRight := (a[1] - c[1]) * (b[0] - c[0])
+	det := detLeft - detRight
+
+	var detSum float64
+	if detLeft > 0 {
+		if detRight <= 0 {
+			return det
+		}
+		detSum = detLeft + detRight
+	} else if detLeft < 0 {
+		if detRight >= 0 {
+			return det
+		}
+		detSum = -detLeft - detRight
+	} else {
+		return det
+	}
+	if math.Abs(det) >= ccwErrBoundA*detSum || !finitePredicateInput(det) {
+		return det
+	}
+
+	return orient2DExact(a, b, c)
+}
+
+func orient2DExact(a, b, c Vec2) float64 {
+	e := exactPredicate{}
+	return e.Result(e.Sub(
+		e.Mul(e.Diff(a[0], c[0]), e.Diff(b[1], c[1])),
+		e.Mul(e.Diff(a[1], c[1]), e.Diff(b[0], c[0])),
+	))
+}
+
+// InCircle computes a value which is positive if d lies
+// strictly inside the circle passing through a, b, and c,
+// negative if it lies strictly outside, and zero if the
+// four points are cocircular.
+//
+// The points a, b, and c must be in counter-clockwise
+// order, or else the sign of the result is reversed.
+//
+// As with Orient2D, the sign of the result is always
+// exact.
+func InCircle(a, b, c, d Vec2) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	aLift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	bLift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	cLift := cdx*cdx + cdy*cdy
+
+	det := aLift*(bdxcdy-cdxbdy) + bLift*(cdxady-adxcdy) + cLift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*aLift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*bLift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*cLift
+	if math.Abs(det) > iccErrBoundA*permanent || !finitePredicateInput(det) {
+		return det
+	}
+	return inCircleExact(a, b, c, d)
+}
+
+func inCircleExact(a, b, c, d Vec2) float64 {
+	e := exactPredicate{}
+	eadx, eady := e.Diff(a[0], d[0]), e.Diff(a[1], d[1])
+	ebdx, ebdy := e.Diff(b[0], d[0]), e.Diff(b[1], d[1])
+	ecdx, ecdy := e.Diff(c[0], d[0]), e.Diff(c[1], d[1])
+	return e.Result(e.Sum(
+		e.Mul(e.Lift(eadx, eady), e.Cross(ebdx, ebdy, ecdx, ecdy)),
+		e.Mul(e.Lift(ebdx, ebdy), e.Cross(ecdx, ecdy, eadx, eady)),
+		e.Mul(e.Lift(ecdx, ecdy), e.Cross(eadx, eady, ebdx, ebdy)),
+	))
+}
+
+// Orient3D computes a value which is positive if d lies
+// below the plane through a, b, and c, negative if it lies
+// above the plane, and zero if the points are coplanar.
+// Here, "below" is defined such that a, b, and c appear in
+// counter-clockwise order when viewed from above.
+//
+// Equivalently, this is the determinant of the matrix with
+// rows a-d, b-d, and c-d.
+//
+// As with Orient2D, the sign of the result is always
+// exact.
+func Orient3D(a, b, c, d Vec3) float64 {
+	adx, ady, adz := a[0]-d[0], a[1]-d[1], a[2]-d[2]
+	bdx, bdy, bdz := b[0]-d[0], b[1]-d[1], b[2]-d[2]
+	cdx, cdy, cdz := c[0]-d[0], c[1]-d[1], c[2]-d[2]
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+
+	det := adz*(bdxcdy-cdxbdy) + bdz*(cdxady-adxcdy) + cdz*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*math.Abs(adz) +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*math.Abs(bdz) +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*math.Abs(cdz)
+	if math.Abs(det) > o3dErrBoundA*permanent || !finitePredicateInput(det) {
+		return det
+	}
+	return orient3DExact(a, b, c, d)
+}
+
+func orient3DExact(a, b, c, d Vec3) float64 {
+	e := exactPredicate{}
+	var rows [3][3]*big.Rat
+	for i, p := range [3]Vec3{a, b, c} {
+		for j := 0; j < 3; j++ {
+			rows[i][j] = e.Diff(p[j], d[j])
+		}
+	}
+	return e.Result(e.Det3(rows))
+}
+
+// InSphere computes a value which is positive if e lies
+// strictly inside the sphere passing through a, b, c, and
+// d, negative if it lies strictly outside, and zero if the
+// five points are cospherical.
+//
+// The points a, b, c, and d must be ordered such that
+// Orient3D(a, b, c, d) is positive, or else the sign of
+// the result is reversed.
+//
+// As with Orient2D, the sign of the result is always
+// exact.
+func InSphere(a, b, c, d, e Vec3) float64 {
+	aex, aey, aez := a[0]-e[0], a[1]-e[1], a[2]-e[2]
+	bex, bey, bez := b[0]-e[0], b[1]-e[1], b[2]-e[2]
+	cex, cey, cez := c[0]-e[0], c[1]-e[1], c[2]-e[2]
+	dex, dey, dez := d[0]-e[0], d[1]-e[1], d[2]-e[2]
+
+	aexbey, bexaey := aex*bey, bex*aey
+	bexcey, cexbey := bex*cey, cex*bey
+	cexdey, dexcey := cex*dey, dex*cey
+	dexaey, aexdey := dex*aey, aex*dey
+	aexcey, cexaey := aex*cey, cex*aey
+	bexdey, dexbey := bex*dey, dex*bey
+
+	ab := aexbey - bexaey
+	bc := bexcey - cexbey
+	cd := cexdey - dexcey
+	da := dexaey - aexdey
+	ac := aexcey - cexaey
+	bd := bexdey - dexbey
+
+	abc := aez*bc - bez*ac + cez*ab
+	bcd := bez*cd - cez*bd + dez*bc
+	cda := cez*da + dez*ac + aez*cd
+	dab := dez*ab + aez*bd + bez*da
+
+	aLift := aex*aex + aey*aey + aez*aez
+	bLift := bex*bex + bey*bey + bez*bez
+	cLift := cex*cex + cey*cey + cez*cez
+	dLift := dex*dex + dey*dey + dez*dez
+
+	det := (dLift*abc - cLift*dab) + (bLift*cda - aLift*bcd)
+
+	abs := math.Abs
+	aezPlus, bezPlus, cezPlus, dezPlus := abs(aez), abs(bez), abs(cez), abs(dez)
+	permanent := ((abs(cexdey)+abs(dexcey))*bezPlus+
+		(abs(dexbey)+abs(bexdey))*cezPlus+
+		(abs(bexcey)+abs(cexbey))*dezPlus)*aLift +
+		((abs(dexaey)+abs(aexdey))*cezPlus+
+			(abs(aexcey)+abs(cexaey))*dezPlus+
+			(abs(cexdey)+abs(dexcey))*aezPlus)*bLift +
+		((abs(aexbey)+abs(bexaey))*dezPlus+
+			(abs(bexdey)+abs(dexbey))*aezPlus+
+			(abs(dexaey)+abs(aexdey))*bezPlus)*cLift +
+		((abs(bexcey)+abs(cexbey))*aezPlus+
+			(abs(cexaey)+abs(aexcey))*bezPlus+
+			(abs(aexbey)+abs(bexaey))*cezPlus)*dLift
+	if abs(det) > ispErrBoundA*permanent || !finitePredicateInput(det) {
+		return det
+	}
+	return inSphereExact(a, b, c, d, e)
+}
+
+func inSphereExact(a, b, c, d, e Vec3) float64 {
+	x := exactPredicate{}
+	var rows [4][3]*big.Rat
+	var lifts [4]*big.Rat
+	for i, p := range [4]Vec3{a, b, c, d} {
+		for j := 0; j < 3; j++ {
+			rows[i][j] = x.Diff(p[j], e[j])
+		}
+		lifts[i] = x.Sum(
+			x.Mul(rows[i][0], rows[i][0]),
+			x.Mul(rows[i][1], rows[i][1]),
+			x.Mul(rows[i][2], rows[i][2]),
+		)
+	}
+
+	// Expand the 4x4 determinant along the lift column.
+	minor := func(skip int) *big.Rat {
+		var m [3][3]*big.Rat
+		k := 0
+		for i := 0; i < 4; i++ {
+			if i != skip {
+				m[k] = rows[i]
+				k++
+			}
+		}
+		return x.Det3(m)
+	}
+	result := new(big.Rat)
+	for i := 0; i < 4; i++ {
+		term := x.Mul(lifts[i], minor(i))
+		if i%2 == 0 {
+			result = x.Sub(result, term)
+		} else {
+			result = x.Sum(result, term)
+		}
+	}
+	return x.Result(result)
+}
+
+func finitePredicateInput(det float64) bool {
+	return !math.IsNaN(det) && !math.IsInf(det, 0)
+}
+
+// exactPredicate implements the exact arithmetic fallback
+// for geometric predicates.
+//
+// Since every input is a float64, all of the intermediate
+// values are rational, so they can be represented exactly
+// with big.Rat.
+type exactPredicate struct{}
+
+func (e exactPredicate) Diff(a, b float64) *big.Rat {
+	x := new(big.Rat).SetFloat64(a)
+	return x.Sub(x, new(big.Rat).SetFloat64(b))
+}
+
+func (e exactPredicate) Mul(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(a, b)
+}
+
+func (e exactPredicate) Sub(a, b *big.Rat) *big.Rat {
+	return new(big.Rat).Sub(a, b)
+}
+
+func (e exactPredicate) Sum(xs ...*big.Rat) *big.Rat {
+	res := new(big.Rat)
+	for _, x := range xs {
+		res.Add(res, x)
+	}
+	return res
+}
+
+// Lift computes x^2+y^2.
+func (e exactPredicate) Lift(x, y *big.Rat) *big.Rat {
+	return e.Sum(e.Mul(x, x), e.Mul(y, y))
+}
+
+// Cross computes x1*y2-x2*y1.
+func (e exactPredicate) Cross(x1, y1, x2, y2 *big.Rat) *big.Rat {
+	return e.Sub(e.Mul(x1, y2), e.Mul(x2, y1))
+}
+
+// Det3 computes the determinant of a 3x3 matrix of rows.
+func (e exactPredicate) Det3(m [3][3]*big.Rat) *big.Rat {
+	return e.Sum(
+		e.Mul(m[0][2], e.Cross(m[1][0], m[1][1], m[2][0], m[2][1])),
+		e.Mul(m[1][2], e.Cross(m[2][0], m[2][1], m[0][0], m[0][1])),
+		e.Mul(m[2][2], e.Cross(m[0][0], m[0][1], m[1][0], m[1][1])),
+	)
+}
+
+func (e exactPredicate) Result(x *big.Rat) float64 {
+	res, _ := x.Float64()
+	if res == 0 && x.Sign() != 0 {
+		// Preserve the sign when the result underflows.
+		return float64(x.Sign()) * math.SmallestNonzeroFloat64
+	}
+	return res
+}
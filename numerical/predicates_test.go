@@ -0,0 +1,95 @@
+package numerical
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPredicatesExactMatchesFast(t *testing.T) {
+	// On random inputs, the floating-point filter should
+	// almost always suffice, and the exact fallback should
+	// agree with it.
+	sameSign := func(x, y float64) bool {
+		return (x > 0) == (y > 0) && (x < 0) == (y < 0)
+	}
+	for i := 0; i < 1000; i++ {
+		a, b, c, d := NewVec2RandomNormal(), NewVec2RandomNormal(), NewVec2RandomNormal(),
+			NewVec2RandomNormal()
+		if x, y := Orient2D(a, b, c), orient2DExact(a, b, c); !sameSign(x, y) ||
+			math.Abs(x-y) > 1e-8 {
+			t.Fatalf("Orient2D: fast=%f exact=%f", x, y)
+		}
+		if x, y := InCircle(a, b, c, d), inCircleExact(a, b, c, d); !sameSign(x, y) ||
+			math.Abs(x-y) > 1e-8 {
+			t.Fatalf("InCircle: fast=%f exact=%f", x, y)
+		}
+
+		a3, b3, c3, d3, e3 := NewVec3RandomNormal(), NewVec3RandomNormal(),
+			NewVec3RandomNormal(), NewVec3RandomNormal(), NewVec3RandomNormal()
+		if x, y := Orient3D(a3, b3, c3, d3), orient3DExact(a3, b3, c3, d3); !sameSign(x, y) ||
+			math.Abs(x-y) > 1e-8 {
+			t.Fatalf("Orient3D: fast=%f exact=%f", x, y)
+		}
+		if x, y := InSphere(a3, b3, c3, d3, e3), inSphereExact(a3, b3, c3, d3, e3); !sameSign(x, y) ||
+			math.Abs(x-y) > 1e-8 {
+			t.Fatalf("InSphere: fast=%f exact=%f", x, y)
+		}
+	}
+}
+
+func TestPredicatesOrientation(t *testing.T) {
+	if Orient2D(Vec2{0, 0}, Vec2{1, 0}, Vec2{0, 1}) <= 0 {
+		t.Error("expected counter-clockwise triangle to be positive")
+	}
+	if InCircle(Vec2{1, 0}, Vec2{0, 1}, Vec2{-1, 0}, Vec2{0.1, 0.2}) <= 0 {
+		t.Error("expected point inside circle to be positive")
+	}
+	a, b, c := Vec3{0, 0, 0}, Vec3{1, 0, 0}, Vec3{0, 1, 0}
+	if Orient3D(a, b, c, Vec3{0, 0, -1}) <= 0 {
+		t.Error("expected point below plane to be positive")
+	}
+	d := Vec3{0, 0, -1}
+	if InSphere(a, b, c, d, Vec3{0.2, 0.2, -0.2}) <= 0 {
+		t.Error("expected point inside sphere to be positive")
+	}
+	if InSphere(a, b, c, d, Vec3{5, 5, 5}) >= 0 {
+		t.Error("expected point outside sphere to be negative")
+	}
+}
+
+func TestPredicatesDegenerate(t *testing.T) {
+	// Nearly colinear points must produce signs which are
+	// consistent under permutation, even though the naive
+	// determinant is dominated by rounding error.
+	for i := 0; i < 1000; i++ {
+		x := 0.5 + rand.Float64()
+		p := Vec2{x, math.Nextafter(x, x+float64(rand.Intn(3)-1))}
+		q := Vec2{12, 12}
+		r := Vec2{24, 24}
+		o := Orient2D(p, q, r)
+		expected := p[1] - p[0]
+		if (o > 0) != (expected > 0) || (o < 0) != (expected < 0) {
+			t.Fatalf("expected sign of %g but got %g", expected, o)
+		}
+		if o1 := Orient2D(q, r, p); (o > 0) != (o1 > 0) || (o < 0) != (o1 < 0) {
+			t.Fatalf("sign changed under rotation: %g vs %g", o, o1)
+		}
+		if o1 := Orient2D(q, p, r); (o > 0) != (o1 < 0) || (o < 0) != (o1 > 0) {
+			t.Fatalf("sign did not flip under swap: %g vs %g", o, o1)
+		}
+	}
+
+	// Cospherical points on an axis-aligned cube.
+	cube := []Vec3{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 1}}
+	a, b, c, d := cube[0], cube[1], cube[2], cube[3]
+	if Orient3D(a, b, c, d) < 0 {
+		a, b = b, a
+	}
+	if x := InSphere(a, b, c, d, cube[4]); x != 0 {
+		t.Errorf("expected cospherical points to give 0 but got %g", x)
+	}
+	if x := Orient3D(a, b, c, Vec3{0.3, 0.7, 0}); x != 0 {
+		t.Errorf("expected coplanar points to give 0 but got %g", x)
+	}
+}
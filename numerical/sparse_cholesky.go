@@ -117,6 +117,19 @@ func sparseCholeskyApply[T Vector[T]](s *SparseCholesky, x []T) []T {
 	return permuteVectorsInv(out, s.perm)
 }
 
+// ApplyInverse computes A^-1*x.
+func (s *SparseCholesky) ApplyInverse(x Vec) Vec {
+	vecs := make([]Vec2, len(x))
+	for i, v := range x {
+		vecs[i][0] = v
+	}
+	res := make(Vec, len(x))
+	for i, v := range s.ApplyInverseVec2(vecs) {
+		res[i] = v[0]
+	}
+	return res
+}
+
 // ApplyInverseVec2 computes (A^-1*x, A^-1*y).
 func (s *SparseCholesky) ApplyInverseVec2(x []Vec2) []Vec2 {
 	return sparseCholeskyApplyInverse(s, x)
@@ -0,0 +1,167 @@
+package numerical
+
+import "math"
+
+// SparseLU is a sparse LU decomposition of a square,
+// possibly non-symmetric matrix.
+//
+// Unlike SparseCholesky, the matrix needn't be symmetric
+// or positive definite, making this suitable for systems
+// such as those produced by asymmetric Floater97 weights
+// or LSCM parameterizations.
+//
+// Rows and columns are reordered to reduce fill-in, and
+// then columns are further exchanged during the
+// decomposition using threshold partial pivoting.
+// The column suggested by the fill-reducing ordering is
+// kept as the pivot unless its magnitude is below
+// SparseLUPivotThreshold times the largest candidate in
+// the row, in which case the largest candidate is used.
+// This avoids zero and tiny pivots while mostly
+// preserving sparsity.
+//
+// Once instantiated, this object can be used to quickly
+// apply the inverse of a matrix to vectors.
+type SparseLU struct {
+	lower *SparseMatrix
+	upper *SparseMatrix
+	perm  []int
+
+	// pivotCols[i] is the (permuted) column of the i-th
+	// pivot.
+	pivotCols []int
+}
+
+// SparseLUPivotThreshold is the relative magnitude below
+// which SparseLU will not use the default pivot.
+const SparseLUPivotThreshold = 0.1
+
+// NewSparseLU computes the LU decomposition of a matrix.
+//
+// This panics if the matrix is singular, in which case no
+// non-zero pivot can be found for some row.
+func NewSparseLU(mat *SparseMatrix) *SparseLU {
+	perm := mat.symmetricPattern().RCM()
+	mat = mat.Permute(perm)
+	size := len(mat.rows)
+
+	// Map pivots to columns and vice versa. Columns which
+	// have not been chosen as pivots yet map to -1.
+	pivotCols := make([]int, size)
+	colPivots := make([]int, size)
+	for i := range colPivots {
+		colPivots[i] = -1
+	}
+
+	lower := NewSparseMatrix(size)
+	upperCols := NewSparseMatrix(size)
+	upperDiag := make([]float64, size)
+
+	row := newSparseMatrixMap(size)
+	for i := 0; i < size; i++ {
+		row.Clear()
+		minPivot := i
+		mat.Iterate(i, func(j int, x float64) {
+			row.Add(j, x)
+			if k := colPivots[j]; k != -1 && k < minPivot {
+				minPivot = k
+			}
+		})
+
+		// Eliminate the columns of previous pivots in order.
+		// Fill-in only ever occurs in columns which were not
+		// pivots yet when a row of U was computed, so a
+		// linear scan over the previous pivots visits every
+		// entry.
+		for k := minPivot; k < i; k++ {
+			col := pivotCols[k]
+			if !row.contained[col] || row.data[col] == 0 {
+				continue
+			}
+			l := row.data[col] / upperDiag[k]
+			lower.Set(i, k, l)
+			upperCols.Iterate(k, func(j int, u float64) {
+				if j != col {
+					row.Add(j, -l*u)
+				}
+			})
+		}
+		lower.Set(i, i, 1)
+
+		pivot := -1
+		var maxAbs float64
+		for _, j := range row.indices {
+			if colPivots[j] == -1 && math.Abs(row.data[j]) > maxAbs {
+				pivot = j
+				maxAbs = math.Abs(row.data[j])
+			}
+		}
+		if pivot == -1 {
+			panic("singular matrix in sparse LU decomposition")
+		}
+		if colPivots[i] == -1 && row.contained[i] &&
+			math.Abs(row.data[i]) >= SparseLUPivotThreshold*maxAbs {
+			pivot = i
+		}
+		pivotCols[i] = pivot
+		colPivots[pivot] = i
+		upperDiag[i] = row.data[pivot]
+
+		// Intentionally adding the entries in order.
+		row.IterateSorted(func(j int, x float64) {
+			if x != 0 && (colPivots[j] == -1 || colPivots[j] == i) {
+				upperCols.Set(i, j, x)
+			}
+		})
+	}
+
+	// Renumber the columns of U by their pivots, making it
+	// upper-triangular.
+	upper := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		upperCols.Iterate(i, func(j int, x float64) {
+			upper.Set(i, colPivots[j], x)
+		})
+	}
+
+	return &SparseLU{
+		lower:     lower,
+		upper:     upper,
+		perm:      perm,
+		pivotCols: pivotCols,
+	}
+}
+
+// ApplyInverse computes A^-1*x.
+func (s *SparseLU) ApplyInverse(x Vec) Vec {
+	vecs := make([]Vec2, len(x))
+	for i, v := range x {
+		vecs[i][0] = v
+	}
+	res := make(Vec, len(x))
+	for i, v := range s.ApplyInverseVec2(vecs) {
+		res[i] = v[0]
+	}
+	return res
+}
+
+// ApplyInverseVec2 computes (A^-1*x, A^-1*y).
+func (s *SparseLU) ApplyInverseVec2(x []Vec2) []Vec2 {
+	return sparseLUApplyInverse(s, x)
+}
+
+// ApplyInverseVec3 computes (A^-1*x, A^-1*y, A^-1*z).
+func (s *SparseLU) ApplyInverseVec3(x []Vec3) []Vec3 {
+	return sparseLUApplyInverse(s, x)
+}
+
+func sparseLUApplyInverse[T Vector[T]](s *SparseLU, x []T) []T {
+	if len(x) == 0 {
+		return nil
+	}
+	b := permuteVectors(x, s.perm)
+	out := make([]T, len(x))
+	sparseMatrixBacksubLower(s.lower, out, b)
+	sparseMatrixBacksubUpper(s.upper, out, out)
+	return permuteVectorsInv(permuteVectorsInv(out, s.pivotCols), s.perm)
+}
@@ -0,0 +1,114 @@
+package numerical
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSparseLU(t *testing.T) {
+	// Create a random, sparse, non-symmetric but diagonally
+	// dominant matrix.
+	const size = 100
+	matrix := NewSparseMatrix(size)
+	dense := make([][]float64, size)
+	for i := range dense {
+		dense[i] = make([]float64, size)
+		var rowSum float64
+		for j := 0; j < size; j++ {
+			if j != i && rand.Intn(10) == 0 {
+				x := rand.NormFloat64()
+				dense[i][j] = x
+				rowSum += math.Abs(x)
+				matrix.Set(i, j, x)
+			}
+		}
+		dense[i][i] = rowSum + 1
+		matrix.Set(i, i, dense[i][i])
+	}
+
+	lu := NewSparseLU(matrix)
+
+	t.Run("ApplyInverseVec3", func(t *testing.T) {
+		inVec := make([]Vec3, size)
+		for i := range inVec {
+			inVec[i] = NewVec3RandomNormal()
+		}
+		inverted := matrix.ApplyVec3(lu.ApplyInverseVec3(inVec))
+		for i, x := range inVec {
+			a := inverted[i]
+			if a.Dist(x) > 1e-8 || math.IsNaN(a.Sum()) {
+				t.Errorf("expected %v but got %v", x, a)
+				return
+			}
+		}
+	})
+
+	t.Run("ApplyInverse", func(t *testing.T) {
+		inVec := make(Vec, size)
+		for i := range inVec {
+			inVec[i] = rand.NormFloat64()
+		}
+		inverted := matrix.Apply(lu.ApplyInverse(inVec))
+		if d := inverted.Dist(inVec); d > 1e-8 || math.IsNaN(d) {
+			t.Errorf("unexpected distance from target: %f", d)
+		}
+	})
+}
+
+func TestSparseLUPivoting(t *testing.T) {
+	// Shuffle the rows of a diagonally dominant matrix, so
+	// that most diagonal entries are zero and pivoting is
+	// required.
+	const size = 100
+	rowPerm := rand.Perm(size)
+	matrix := NewSparseMatrix(size)
+	for i := 0; i < size; i++ {
+		var rowSum float64
+		for j := 0; j < size; j++ {
+			if j != i && rand.Intn(10) == 0 {
+				x := rand.NormFloat64()
+				rowSum += math.Abs(x)
+				matrix.Set(rowPerm[i], j, x)
+			}
+		}
+		matrix.Set(rowPerm[i], i, rowSum+1)
+	}
+
+	lu := NewSparseLU(matrix)
+	inVec := make(Vec, size)
+	for i := range inVec {
+		inVec[i] = rand.NormFloat64()
+	}
+	inverted := matrix.Apply(lu.ApplyInverse(inVec))
+	if d := inverted.Dist(inVec); d > 1e-8 || math.IsNaN(d) {
+		t.Errorf("unexpected distance from target: %f", d)
+	}
+
+	// A tiny leading pivot should be avoided rather than
+	// amplifying rounding errors.
+	matrix = NewSparseMatrix(2)
+	matrix.Set(0, 0, 1e-20)
+	matrix.Set(0, 1, 1)
+	matrix.Set(1, 0, 1)
+	matrix.Set(1, 1, 1)
+	solution := NewSparseLU(matrix).ApplyInverse(Vec{1, 2})
+	if solution.Dist(Vec{1, 1}) > 1e-8 {
+		t.Errorf("unexpected solution: %v", solution)
+	}
+}
+
+func TestSparseLUSingular(t *testing.T) {
+	matrix := NewSparseMatrix(3)
+	matrix.Set(0, 0, 1)
+	matrix.Set(0, 1, 2)
+	matrix.Set(1, 0, 2)
+	matrix.Set(1, 1, 4)
+	matrix.Set(2, 2, 1)
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for singular matrix")
+		}
+	}()
+	NewSparseLU(matrix)
+}
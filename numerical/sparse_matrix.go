@@ -90,7 +90,12 @@ func (s *SparseMatrix) RCM() []int {
 	drawBestStart := func() int {
 		result := -1
 		var resultNeighbors int
-		for i := range remaining {
+		// Iterate in order (rather than over the map) so
+		// that ties are broken deterministically.
+		for i := range s.indices {
+			if !remaining[i] {
+				continue
+			}
 			n := remainingNeighbors(i)
 			if n < resultNeighbors || result == -1 {
 				result = i
@@ -137,6 +142,28 @@ func (s *SparseMatrix) RCM() []int {
 	return permutation
 }
 
+// symmetricPattern creates a matrix with the non-zero
+// pattern of A+A^T, which is useful for computing
+// orderings of non-symmetric matrices.
+func (s *SparseMatrix) symmetricPattern() *SparseMatrix {
+	res := NewSparseMatrix(len(s.rows))
+	seen := newSparseMatrixMap(len(s.rows))
+	transpose := s.Transpose()
+	for i := range s.rows {
+		seen.Clear()
+		s.Iterate(i, func(j int, x float64) {
+			seen.Add(j, 1)
+		})
+		transpose.Iterate(i, func(j int, x float64) {
+			seen.Add(j, 1)
+		})
+		seen.IterateSorted(func(j int, x float64) {
+			res.Set(i, j, 1)
+		})
+	}
+	return res
+}
+
 // Apply computes A*x.
 func (s *SparseMatrix) Apply(x Vec) Vec {
 	res := make(Vec, len(x))
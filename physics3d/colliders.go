@@ -0,0 +1,57 @@
+package physics3d
+
+import "github.com/unixpickle/model3d/model3d"
+
+// A Collider prevents particles from entering an obstacle.
+type Collider interface {
+	// Collide moves a particle at position p out of the
+	// obstacle, if necessary.
+	//
+	// The prev argument is the position of the particle at
+	// the start of the current substep, which may be used
+	// to apply friction.
+	Collide(p, prev model3d.Coord3D) model3d.Coord3D
+}
+
+// An SDFCollider is a Collider for a static obstacle
+// described by an SDF.
+type SDFCollider struct {
+	SDF model3d.PointSDF
+
+	// Thickness is the minimum distance that particles
+	// must keep from the surface, which prevents cloth
+	// from visibly intersecting the obstacle.
+	Thickness float64
+
+	// Friction is the fraction of tangential motion which
+	// is removed from colliding particles, in [0, 1].
+	Friction float64
+}
+
+// Collide projects p to the outside of the obstacle.
+func (s *SDFCollider) Collide(p, prev model3d.Coord3D) model3d.Coord3D {
+	closest, sdf := s.SDF.PointSDF(p)
+	if sdf < -s.Thickness {
+		return p
+	}
+	normal := p.Sub(closest)
+	if sdf > 0 {
+		normal = normal.Scale(-1)
+	}
+	if norm := normal.Norm(); norm == 0 {
+		// We cannot determine a direction exactly on the
+		// surface, so we fall back to the direction the
+		// particle came from.
+		normal = prev.Sub(p).Normalize()
+	} else {
+		normal = normal.Scale(1 / norm)
+	}
+	res := closest.Add(normal.Scale(s.Thickness))
+
+	if s.Friction != 0 {
+		motion := res.Sub(prev)
+		tangent := motion.Sub(normal.Scale(motion.Dot(normal)))
+		res = res.Sub(tangent.Scale(s.Friction))
+	}
+	return res
+}
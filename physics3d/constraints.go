@@ -0,0 +1,137 @@
+package physics3d
+
+import "github.com/unixpickle/model3d/model3d"
+
+// A Constraint restricts the positions of particles.
+//
+// Constraints use extended position-based dynamics (XPBD),
+// in which a compliance parameter determines how soft the
+// constraint is, independent of the time step.
+type Constraint interface {
+	// Reset is called at the beginning of every substep.
+	Reset()
+
+	// Project moves the particles of the system to better
+	// satisfy the constraint, given the substep size.
+	Project(p *ParticleSystem, dt float64)
+}
+
+// A DistanceConstraint keeps two particles at a fixed
+// distance from each other, like a spring.
+type DistanceConstraint struct {
+	I, J int
+
+	RestLength float64
+
+	// Compliance is the inverse stiffness of the
+	// constraint. A compliance of zero is perfectly rigid.
+	Compliance float64
+
+	lambda float64
+}
+
+// NewDistanceConstraint creates a DistanceConstraint which
+// preserves the current distance between two particles.
+func NewDistanceConstraint(p *ParticleSystem, i, j int, compliance float64) *DistanceConstraint {
+	return &DistanceConstraint{
+		I:          i,
+		J:          j,
+		RestLength: p.Positions[i].Dist(p.Positions[j]),
+		Compliance: compliance,
+	}
+}
+
+// Reset resets the accumulated Lagrange multiplier.
+func (d *DistanceConstraint) Reset() {
+	d.lambda = 0
+}
+
+// Project moves the two particles towards the rest length.
+func (d *DistanceConstraint) Project(p *ParticleSystem, dt float64) {
+	w1, w2 := p.InvMasses[d.I], p.InvMasses[d.J]
+	if w1+w2 == 0 {
+		return
+	}
+	diff := p.Positions[d.I].Sub(p.Positions[d.J])
+	dist := diff.Norm()
+	if dist == 0 {
+		return
+	}
+	n := diff.Scale(1 / dist)
+	alpha := d.Compliance / (dt * dt)
+	c := dist - d.RestLength
+	deltaLambda := (-c - alpha*d.lambda) / (w1 + w2 + alpha)
+	d.lambda += deltaLambda
+	p.Positions[d.I] = p.Positions[d.I].Add(n.Scale(w1 * deltaLambda))
+	p.Positions[d.J] = p.Positions[d.J].Sub(n.Scale(w2 * deltaLambda))
+}
+
+// A VolumeConstraint keeps the volume enclosed by a closed
+// triangle mesh of particles near a target, which makes a
+// soft body behave like an inflated balloon.
+type VolumeConstraint struct {
+	// Triangles indexes the particles of each triangle,
+	// with normals facing outward.
+	Triangles [][3]int
+
+	// TargetVolume is the volume the mesh tries to
+	// maintain. This is typically the rest volume, scaled
+	// up or down to inflate or deflate the body.
+	TargetVolume float64
+
+	// Compliance is the inverse stiffness of the
+	// constraint.
+	Compliance float64
+
+	lambda float64
+}
+
+// NewVolumeConstraint creates a VolumeConstraint which
+// preserves the current volume of the triangles, scaled
+// by pressure.
+func NewVolumeConstraint(p *ParticleSystem, triangles [][3]int, pressure,
+	compliance float64) *VolumeConstraint {
+	return &VolumeConstraint{
+		Triangles:    triangles,
+		TargetVolume: pressure * triangleVolume(p.Positions, triangles),
+		Compliance:   compliance,
+	}
+}
+
+// Reset resets the accumulated Lagrange multiplier.
+func (v *VolumeConstraint) Reset() {
+	v.lambda = 0
+}
+
+// Project moves the particles towards the target volume.
+func (v *VolumeConstraint) Project(p *ParticleSystem, dt float64) {
+	grads := map[int]model3d.Coord3D{}
+	for _, t := range v.Triangles {
+		p1, p2, p3 := p.Positions[t[0]], p.Positions[t[1]], p.Positions[t[2]]
+		grads[t[0]] = grads[t[0]].Add(p2.Cross(p3).Scale(1.0 / 6))
+		grads[t[1]] = grads[t[1]].Add(p3.Cross(p1).Scale(1.0 / 6))
+		grads[t[2]] = grads[t[2]].Add(p1.Cross(p2).Scale(1.0 / 6))
+	}
+	var denom float64
+	for i, g := range grads {
+		denom += p.InvMasses[i] * g.Dot(g)
+	}
+	alpha := v.Compliance / (dt * dt)
+	if denom+alpha == 0 {
+		return
+	}
+	c := triangleVolume(p.Positions, v.Triangles) - v.TargetVolume
+	deltaLambda := (-c - alpha*v.lambda) / (denom + alpha)
+	v.lambda += deltaLambda
+	for i, g := range grads {
+		p.Positions[i] = p.Positions[i].Add(g.Scale(p.InvMasses[i] * deltaLambda))
+	}
+}
+
+func triangleVolume(positions []model3d.Coord3D, triangles [][3]int) float64 {
+	var res float64
+	for _, t := range triangles {
+		res += positions[t[0]].Cross(positions[t[1]]).Dot(positions[t[2]])
+	}
+	return res / 6
+}
@@ -0,0 +1,10 @@
+// Package physics3d provides a small physics simulator for
+// cloth and soft bodies, which can be used to produce
+// draped or squished versions of models for renderings.
+//
+// Simulations are built from particles, which are moved
+// by forces and then corrected by constraints, following
+// extended position-based dynamics (XPBD).
+// Particles collide with obstacles expressed as SDFs from
+// the model3d package.
+package physics3d
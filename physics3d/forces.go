@@ -0,0 +1,63 @@
+package physics3d
+
+import "github.com/unixpickle/model3d/model3d"
+
+// A ForceField determines the external forces applied to
+// the particles of a ParticleSystem.
+type ForceField interface {
+	Forces(p *ParticleSystem) []model3d.Coord3D
+}
+
+// A JoinedField is a ForceField that adds ForceFields.
+type JoinedField []ForceField
+
+// Forces computes the combined forces on the system.
+func (j JoinedField) Forces(p *ParticleSystem) []model3d.Coord3D {
+	res := make([]model3d.Coord3D, len(p.Positions))
+	for _, f := range j {
+		for i, x := range f.Forces(p) {
+			res[i] = res[i].Add(x)
+		}
+	}
+	return res
+}
+
+// A GravityField applies a constant acceleration to every
+// particle, regardless of its mass.
+type GravityField struct {
+	Acceleration model3d.Coord3D
+}
+
+// Forces computes the gravitational force on each
+// particle.
+func (g *GravityField) Forces(p *ParticleSystem) []model3d.Coord3D {
+	res := make([]model3d.Coord3D, len(p.Positions))
+	for i, w := range p.InvMasses {
+		if w != 0 {
+			res[i] = g.Acceleration.Scale(1 / w)
+		}
+	}
+	return res
+}
+
+// A DragField applies a force opposite to the velocity of
+// each particle, proportional to its mass.
+//
+// This damps the motion of the system, as if it were
+// moving through air.
+type DragField struct {
+	// Coefficient is the fraction of velocity lost per
+	// second.
+	Coefficient float64
+}
+
+// Forces computes the drag force on each particle.
+func (d *DragField) Forces(p *ParticleSystem) []model3d.Coord3D {
+	res := make([]model3d.Coord3D, len(p.Positions))
+	for i, w := range p.InvMasses {
+		if w != 0 {
+			res[i] = p.Velocities[i].Scale(-d.Coefficient / w)
+		}
+	}
+	return res
+}
@@ -0,0 +1,146 @@
+package physics3d
+
+import (
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// A MeshBody is a ParticleSystem whose particles are the
+// vertices of a triangle mesh, such as a piece of cloth or
+// a soft body.
+type MeshBody struct {
+	System *ParticleSystem
+
+	restCoords []model3d.Coord3D
+	coordToIdx *model3d.CoordMap[int]
+	triangles  [][3]int
+}
+
+// NewMeshBody creates a MeshBody for the mesh, with the
+// given total mass distributed between the vertices in
+// proportion to their surrounding area.
+//
+// The body has no constraints by default, so it will not
+// hold its shape. Use methods like AddStretchConstraints()
+// to add constraints to the body.
+func NewMeshBody(m *model3d.Mesh, mass float64) *MeshBody {
+	coords := m.VertexSlice()
+	coordToIdx := model3d.NewCoordMap[int]()
+	for i, c := range coords {
+		coordToIdx.Store(c, i)
+	}
+	var triangles [][3]int
+	areas := make([]float64, len(coords))
+	var totalArea float64
+	m.Iterate(func(t *model3d.Triangle) {
+		var tri [3]int
+		area := t.Area()
+		totalArea += area
+		for i, c := range t {
+			tri[i] = coordToIdx.Value(c)
+			areas[tri[i]] += area / 3
+		}
+		triangles = append(triangles, tri)
+	})
+	masses := make([]float64, len(coords))
+	for i, a := range areas {
+		if totalArea == 0 {
+			masses[i] = mass / float64(len(coords))
+		} else {
+			masses[i] = mass * a / totalArea
+		}
+	}
+	return &MeshBody{
+		System:     NewParticleSystem(coords, masses),
+		restCoords: coords,
+		coordToIdx: coordToIdx,
+		triangles:  triangles,
+	}
+}
+
+// Index gets the particle index of a vertex of the
+// original mesh, or -1 if c is not a vertex.
+func (m *MeshBody) Index(c model3d.Coord3D) int {
+	if idx, ok := m.coordToIdx.Load(c); ok {
+		return idx
+	}
+	return -1
+}
+
+// Pin pins every particle for which f returns true for the
+// original vertex position, so that it does not move.
+func (m *MeshBody) Pin(f func(c model3d.Coord3D) bool) {
+	for i, c := range m.restCoords {
+		if f(c) {
+			m.System.InvMasses[i] = 0
+			m.System.Velocities[i] = model3d.Coord3D{}
+		}
+	}
+}
+
+// AddStretchConstraints adds a DistanceConstraint for
+// every edge of the mesh, which prevents the surface from
+// stretching.
+func (m *MeshBody) AddStretchConstraints(compliance float64) {
+	m.forEachEdge(func(i1, i2 int, _ []int) {
+		c := NewDistanceConstraint(m.System, i1, i2, compliance)
+		m.System.Constraints = append(m.System.Constraints, c)
+	})
+}
+
+// AddBendConstraints adds a DistanceConstraint between the
+// opposite vertices of every pair of adjacent triangles,
+// which prevents the surface from folding freely.
+//
+// For cloth, the bending compliance is typically much
+// larger than the stretch compliance.
+func (m *MeshBody) AddBendConstraints(compliance float64) {
+	m.forEachEdge(func(i1, i2 int, opposite []int) {
+		if len(opposite) != 2 {
+			return
+		}
+		c := NewDistanceConstraint(m.System, opposite[0], opposite[1], compliance)
+		m.System.Constraints = append(m.System.Constraints, c)
+	})
+}
+
+// AddVolumeConstraint adds a VolumeConstraint for the
+// entire mesh, which must be closed.
+//
+// The pressure scales the rest volume of the mesh, so
+// values greater than 1 inflate the body.
+func (m *MeshBody) AddVolumeConstraint(pressure, compliance float64) {
+	c := NewVolumeConstraint(m.System, m.triangles, pressure, compliance)
+	m.System.Constraints = append(m.System.Constraints, c)
+}
+
+// Mesh creates a mesh from the current particle
+// positions.
+func (m *MeshBody) Mesh() *model3d.Mesh {
+	res := model3d.NewMesh()
+	pos := m.System.Positions
+	for _, t := range m.triangles {
+		res.Add(&model3d.Triangle{pos[t[0]], pos[t[1]], pos[t[2]]})
+	}
+	return res
+}
+
+func (m *MeshBody) forEachEdge(f func(i1, i2 int, opposite []int)) {
+	type edge [2]int
+	var edges []edge
+	opposite := map[edge][]int{}
+	for _, t := range m.triangles {
+		for i := 0; i < 3; i++ {
+			e := edge{t[i], t[(i+1)%3]}
+			if e[0] > e[1] {
+				e[0], e[1] = e[1], e[0]
+			}
+			if _, ok := opposite[e]; !ok {
+				edges = append(edges, e)
+			}
+			opposite[e] = append(opposite[e], t[(i+2)%3])
+		}
+	}
+	for _, e := range edges {
+		f(e[0], e[1], opposite[e])
+	}
+}
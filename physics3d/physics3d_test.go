@@ -0,0 +1,117 @@
+package physics3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestClothDrape(t *testing.T) {
+	cloth := testClothMesh(20, 2)
+	body := NewMeshBody(cloth, 1)
+	body.AddStretchConstraints(0)
+	body.AddBendConstraints(1e-3)
+
+	sphere := &model3d.Sphere{Center: model3d.Z(-0.6), Radius: 0.5}
+	floor := &model3d.Rect{
+		MinVal: model3d.XYZ(-5, -5, -3),
+		MaxVal: model3d.XYZ(5, 5, -1),
+	}
+	body.System.Forces = JoinedField{
+		&GravityField{Acceleration: model3d.Z(-9.8)},
+		&DragField{Coefficient: 0.5},
+	}
+	body.System.Colliders = []Collider{
+		&SDFCollider{SDF: sphere, Thickness: 0.01, Friction: 0.5},
+		&SDFCollider{SDF: floor, Thickness: 0.01},
+	}
+	for i := 0; i < 200; i++ {
+		body.System.Step(1.0 / 60)
+	}
+
+	draped := body.Mesh()
+	if min := draped.Min().Z; min < -1-1e-5 {
+		t.Errorf("cloth passed through floor: %f", min)
+	}
+	draped.IterateVertices(func(c model3d.Coord3D) {
+		if sphere.SDF(c) > 1e-5 {
+			t.Fatalf("cloth passed through sphere at %v", c)
+		}
+	})
+	if max := draped.Max().Z; max > 0 || max < -0.2 {
+		t.Errorf("unexpected cloth top: %f", max)
+	}
+	if draped.Min().Z > -0.9 {
+		t.Errorf("cloth did not drape to the floor")
+	}
+	for _, c := range body.System.Constraints[:10] {
+		d := c.(*DistanceConstraint)
+		actual := body.System.Positions[d.I].Dist(body.System.Positions[d.J])
+		if math.Abs(actual-d.RestLength) > 0.05*d.RestLength {
+			t.Errorf("edge stretched from %f to %f", d.RestLength, actual)
+		}
+	}
+}
+
+func TestSoftBodyPinned(t *testing.T) {
+	mesh := model3d.NewMeshIcosphere(model3d.Coord3D{}, 1, 3)
+	body := NewMeshBody(mesh, 1)
+	body.AddStretchConstraints(1e-4)
+	body.AddVolumeConstraint(1, 0)
+	body.Pin(func(c model3d.Coord3D) bool {
+		return c.Z > 0.9
+	})
+	body.System.Forces = &GravityField{Acceleration: model3d.Z(-9.8)}
+	for i := 0; i < 60; i++ {
+		body.System.Step(1.0 / 60)
+	}
+
+	posed := body.Mesh()
+	if v1, v2 := posed.Volume(), mesh.Volume(); math.Abs(v1-v2) > 1e-3*v2 {
+		t.Errorf("expected volume %f but got %f", v2, v1)
+	}
+	if posed.Min().Z >= mesh.Min().Z {
+		t.Errorf("body did not sag under gravity")
+	}
+	for _, c := range mesh.VertexSlice() {
+		if c.Z > 0.9 {
+			if p := body.System.Positions[body.Index(c)]; p != c {
+				t.Fatalf("pinned vertex moved from %v to %v", c, p)
+			}
+		}
+	}
+}
+
+func TestGravityFall(t *testing.T) {
+	system := NewParticleSystem([]model3d.Coord3D{{}, model3d.X(1)}, []float64{1, 2})
+	system.Forces = &GravityField{Acceleration: model3d.Z(-2)}
+	system.Step(1)
+	for i, p := range system.Positions {
+		// Symplectic Euler slightly overshoots the exact
+		// solution z = -t^2.
+		if math.Abs(p.Z+1) > 0.15 || p.Z > -1 {
+			t.Errorf("particle %d: unexpected position %v", i, p)
+		}
+	}
+	if e := system.KineticEnergy(); math.Abs(e-3*4/2) > 1e-5 {
+		t.Errorf("unexpected kinetic energy: %f", e)
+	}
+}
+
+func testClothMesh(n int, size float64) *model3d.Mesh {
+	res := model3d.NewMesh()
+	point := func(i, j int) model3d.Coord3D {
+		return model3d.XY(
+			size*(float64(i)/float64(n)-0.5),
+			size*(float64(j)/float64(n)-0.5),
+		)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			res.Add(&model3d.Triangle{point(i, j), point(i+1, j), point(i+1, j+1)})
+			res.Add(&model3d.Triangle{point(i, j), point(i+1, j+1), point(i, j+1)})
+		}
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+package physics3d
+
+import (
+	"github.com/unixpickle/model3d/model3d"
+)
+
+const (
+	// DefaultSubsteps is the default number of substeps
+	// per ParticleSystem.Step() call.
+	DefaultSubsteps = 10
+
+	// DefaultIterations is the default number of
+	// constraint solver iterations per substep.
+	DefaultIterations = 1
+)
+
+// A ParticleSystem simulates a collection of particles
+// which are moved by forces and constrained by
+// constraints and colliders.
+type ParticleSystem struct {
+	Positions  []model3d.Coord3D
+	Velocities []model3d.Coord3D
+
+	// InvMasses stores the inverse mass of every particle.
+	// A particle with an inverse mass of zero is pinned in
+	// place, and is unaffected by forces and constraints.
+	InvMasses []float64
+
+	// Forces, if non-nil, computes the external forces
+	// acting on the particles, such as gravity.
+	Forces ForceField
+
+	Constraints []Constraint
+	Colliders   []Collider
+
+	// Substeps is the number of substeps per call to
+	// Step(). Using many small substeps is typically more
+	// effective than using many solver iterations.
+	//
+	// If 0, DefaultSubsteps is used.
+	Substeps int
+
+	// Iterations is the number of times constraints are
+	// enforced per substep.
+	//
+	// If 0, DefaultIterations is used.
+	Iterations int
+}
+
+// NewParticleSystem creates a stationary particle system
+// with the given particle positions and masses.
+//
+// A mass of zero pins the corresponding particle.
+func NewParticleSystem(positions []model3d.Coord3D, masses []float64) *ParticleSystem {
+	if len(positions) != len(masses) {
+		panic("mismatched number of positions and masses")
+	}
+	invMasses := make([]float64, len(masses))
+	for i, m := range masses {
+		if m != 0 {
+			invMasses[i] = 1 / m
+		}
+	}
+	return &ParticleSystem{
+		Positions:  append([]model3d.Coord3D{}, positions...),
+		Velocities: make([]model3d.Coord3D, len(positions)),
+		InvMasses:  invMasses,
+	}
+}
+
+// Step advances the simulation by dt seconds.
+func (p *ParticleSystem) Step(dt float64) {
+	substeps := p.Substeps
+	if substeps == 0 {
+		substeps = DefaultSubsteps
+	}
+	iters := p.Iterations
+	if iters == 0 {
+		iters = DefaultIterations
+	}
+	h := dt / float64(substeps)
+	prev := make([]model3d.Coord3D, len(p.Positions))
+	for i := 0; i < substeps; i++ {
+		var forces []model3d.Coord3D
+		if p.Forces != nil {
+			forces = p.Forces.Forces(p)
+		}
+		copy(prev, p.Positions)
+		for j, w := range p.InvMasses {
+			if w == 0 {
+				continue
+			}
+			if forces != nil {
+				p.Velocities[j] = p.Velocities[j].Add(forces[j].Scale(h * w))
+			}
+			p.Positions[j] = p.Positions[j].Add(p.Velocities[j].Scale(h))
+		}
+
+		for _, c := range p.Constraints {
+			c.Reset()
+		}
+		for j := 0; j < iters; j++ {
+			for _, c := range p.Constraints {
+				c.Project(p, h)
+			}
+			p.collide(prev)
+		}
+
+		for j, w := range p.InvMasses {
+			if w == 0 {
+				p.Velocities[j] = model3d.Coord3D{}
+			} else {
+				p.Velocities[j] = p.Positions[j].Sub(prev[j]).Scale(1 / h)
+			}
+		}
+	}
+}
+
+func (p *ParticleSystem) collide(prev []model3d.Coord3D) {
+	if len(p.Colliders) == 0 {
+		return
+	}
+	for i, w := range p.InvMasses {
+		if w == 0 {
+			continue
+		}
+		for _, c := range p.Colliders {
+			p.Positions[i] = c.Collide(p.Positions[i], prev[i])
+		}
+	}
+}
+
+// KineticEnergy computes the total kinetic energy of the
+// moving particles.
+func (p *ParticleSystem) KineticEnergy() float64 {
+	var res float64
+	for i, w := range p.InvMasses {
+		if w != 0 {
+			v := p.Velocities[i]
+			res += v.Dot(v) / (2 * w)
+		}
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+package render3d
+
+import (
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// BakeAmbientOcclusion estimates how exposed each vertex
+// of a mesh is to ambient light, for baking into vertex
+// colors or textures.
+//
+// For each vertex, numRays cosine-weighted rays are cast
+// into the hemisphere around the vertex normal, and the
+// result is the fraction of rays which do not hit c within
+// a distance of maxDist. Thus, 1 means fully exposed and 0
+// means fully occluded.
+//
+// The rays of each vertex are cast together using
+// model3d.FirstRayCollisions, so colliders which support
+// batched queries can share work between them.
+//
+// Typically, c is a collider for m itself, possibly
+// joined with other objects in the scene.
+func BakeAmbientOcclusion(m *model3d.Mesh, c model3d.Collider, numRays int,
+	maxDist float64) *model3d.CoordMap[float64] {
+	normals := m.VertexNormals()
+	vertices := m.VertexSlice()
+	epsilon := m.Max().Dist(m.Min()) * 1e-8
+
+	values := make([]float64, len(vertices))
+	mapCoordinates(len(vertices), 1, func(g *goInfo, _, _, idx int) {
+		if g.Extra == nil {
+			g.Extra = newAOBatch(numRays)
+		}
+		batch := g.Extra.(*aoBatch)
+
+		v := vertices[idx]
+		normal := normals.Value(v)
+		origin := v.Add(normal.Scale(epsilon))
+		for _, ray := range batch.Rays {
+			ray.Origin = origin
+			ray.Direction = sampleAngularDest(g.Gen, normal)
+		}
+		model3d.FirstRayCollisions(c, batch.Rays, batch.Collisions, batch.Collides)
+		var exposed int
+		for i, collides := range batch.Collides {
+			if !collides || batch.Collisions[i].Scale >= maxDist {
+				exposed++
+			}
+		}
+		values[idx] = float64(exposed) / float64(numRays)
+	})
+
+	res := model3d.NewCoordMap[float64]()
+	for i, v := range vertices {
+		res.Store(v, values[i])
+	}
+	return res
+}
+
+type aoBatch struct {
+	Rays       []*model3d.Ray
+	Collisions []model3d.RayCollision
+	Collides   []bool
+}
+
+func newAOBatch(numRays int) *aoBatch {
+	rays := make([]*model3d.Ray, numRays)
+	for i := range rays {
+		rays[i] = &model3d.Ray{}
+	}
+	return &aoBatch{
+		Rays:       rays,
+		Collisions: make([]model3d.RayCollision, numRays),
+		Collides:   make([]bool, numRays),
+	}
+}
@@ -0,0 +1,73 @@
+package render3d
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestBakeAmbientOcclusion(t *testing.T) {
+	mesh := model3d.NewMeshIcosphere(model3d.Origin, 1, 5)
+
+	t.Run("Exposed", func(t *testing.T) {
+		ao := BakeAmbientOcclusion(mesh, model3d.MeshToCollider(mesh), 50, 10)
+		if ao.Len() != len(mesh.VertexSlice()) {
+			t.Fatalf("expected %d values but got %d", len(mesh.VertexSlice()), ao.Len())
+		}
+		ao.Range(func(v model3d.Coord3D, value float64) bool {
+			if value != 1 {
+				t.Errorf("vertex %v: expected exposure 1 but got %f", v, value)
+				return false
+			}
+			return true
+		})
+	})
+
+	inverted := mesh.InvertNormals()
+	collider := &countingBatchCollider{Collider: model3d.MeshToCollider(inverted)}
+
+	t.Run("Occluded", func(t *testing.T) {
+		ao := BakeAmbientOcclusion(inverted, collider, 50, 10)
+		ao.Range(func(v model3d.Coord3D, value float64) bool {
+			if value > 0.05 {
+				t.Errorf("vertex %v: expected exposure near 0 but got %f", v, value)
+				return false
+			}
+			return true
+		})
+		if n, expected := collider.Calls(), int64(ao.Len()); n != expected {
+			t.Errorf("expected %d batched calls but got %d", expected, n)
+		}
+	})
+
+	t.Run("MaxDist", func(t *testing.T) {
+		// Every ray travels at least partway across the
+		// sphere, so a tiny maxDist should leave the inside
+		// mostly exposed.
+		ao := BakeAmbientOcclusion(inverted, collider, 50, 0.1)
+		var total float64
+		ao.Range(func(_ model3d.Coord3D, value float64) bool {
+			total += value
+			return true
+		})
+		if mean := total / float64(ao.Len()); mean < 0.9 {
+			t.Errorf("expected mean exposure near 1 but got %f", mean)
+		}
+	})
+}
+
+type countingBatchCollider struct {
+	model3d.Collider
+	calls int64
+}
+
+func (c *countingBatchCollider) FirstRayCollisions(rays []*model3d.Ray,
+	collisions []model3d.RayCollision, collides []bool) {
+	atomic.AddInt64(&c.calls, 1)
+	model3d.FirstRayCollisions(c.Collider, rays, collisions, collides)
+}
+
+func (c *countingBatchCollider) Calls() int64 {
+	return atomic.LoadInt64(&c.calls)
+}
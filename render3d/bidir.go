@@ -1,6 +1,7 @@
 package render3d
 
 import (
+	"context"
 	"math"
 	"math/rand"
 
@@ -60,6 +61,7 @@ type BidirPathTracer struct {
 	Antialias float64
 	Epsilon   float64
 	LogFunc   func(frac float64, sampleRate float64)
+	Progress  model3d.Progress
 }
 
 // Render renders the object to an image.
@@ -67,6 +69,13 @@ func (b *BidirPathTracer) Render(img *Image, obj Object) {
 	b.rayRenderer().Render(img, obj)
 }
 
+// RenderContext is like Render, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+// In this case, the image may be partially rendered.
+func (b *BidirPathTracer) RenderContext(ctx context.Context, img *Image, obj Object) error {
+	return b.rayRenderer().RenderContext(ctx, img, obj)
+}
+
 // RenderVariance computes the variance per pixel using a
 // fixed number of rays per pixel, and writes the results
 // as pixels in an image.
@@ -95,6 +104,7 @@ func (b *BidirPathTracer) rayRenderer() *rayRenderer {
 		Convergence:          b.Convergence,
 		Antialias:            b.Antialias,
 		LogFunc:              b.LogFunc,
+		Progress:             b.Progress,
 	}
 }
 
@@ -1,9 +1,11 @@
 package render3d
 
 import (
+	"context"
 	"math/rand"
 	"runtime"
 	"sync"
+	"sync/atomic"
 )
 
 type goInfo struct {
@@ -15,6 +17,18 @@ type goInfo struct {
 // image, along with a per-goroutine random number
 // generator and the pixel index.
 func mapCoordinates(width, height int, f func(g *goInfo, x, y, idx int)) {
+	mapCoordinatesContext(context.Background(), width, height, f)
+}
+
+// mapCoordinatesContext is like mapCoordinates, but stops
+// calling f once ctx is done.
+//
+// If any coordinates were skipped as a result, ctx.Err()
+// is returned. Otherwise, the result is nil, even if ctx
+// was cancelled after the last call to f.
+func mapCoordinatesContext(ctx context.Context, width, height int,
+	f func(g *goInfo, x, y, idx int)) error {
+	done := ctx.Done()
 	coords := make(chan [3]int, width*height)
 	var idx int
 	for y := 0; y < height; y++ {
@@ -25,6 +39,7 @@ func mapCoordinates(width, height int, f func(g *goInfo, x, y, idx int)) {
 	}
 	close(coords)
 
+	var stopped int32
 	var wg sync.WaitGroup
 	for i := 0; i < runtime.NumCPU(); i++ {
 		wg.Add(1)
@@ -34,10 +49,69 @@ func mapCoordinates(width, height int, f func(g *goInfo, x, y, idx int)) {
 				Gen: rand.New(rand.NewSource(rand.Int63())),
 			}
 			for c := range coords {
+				select {
+				case <-done:
+					atomic.StoreInt32(&stopped, 1)
+					return
+				default:
+				}
 				f(g, c[0], c[1], c[2])
 			}
 		}()
 	}
 
 	wg.Wait()
+	if atomic.LoadInt32(&stopped) != 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// mapRows is like mapCoordinates, but calls f once per
+// row of the image, along with the index of the first
+// pixel in the row.
+func mapRows(width, height int, f func(g *goInfo, y, idx int)) {
+	mapRowsContext(context.Background(), width, height, f)
+}
+
+// mapRowsContext is like mapRows, but stops calling f once
+// ctx is done.
+//
+// As with mapCoordinatesContext, ctx.Err() is only
+// returned if some rows were skipped.
+func mapRowsContext(ctx context.Context, width, height int,
+	f func(g *goInfo, y, idx int)) error {
+	done := ctx.Done()
+	rows := make(chan int, height)
+	for y := 0; y < height; y++ {
+		rows <- y
+	}
+	close(rows)
+
+	var stopped int32
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g := &goInfo{
+				Gen: rand.New(rand.NewSource(rand.Int63())),
+			}
+			for y := range rows {
+				select {
+				case <-done:
+					atomic.StoreInt32(&stopped, 1)
+					return
+				default:
+				}
+				f(g, y, y*width)
+			}
+		}()
+	}
+
+	wg.Wait()
+	if atomic.LoadInt32(&stopped) != 0 {
+		return ctx.Err()
+	}
+	return nil
 }
@@ -0,0 +1,60 @@
+package render3d
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMapCoordinatesContext(t *testing.T) {
+	const width, height = 30, 20
+
+	testCancel := func(t *testing.T, mapFn func(ctx context.Context, f func()) error,
+		total int) {
+		t.Run("AfterLast", func(t *testing.T) {
+			// Cancelling once all work is done should not
+			// be reported as a failure.
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var count int64
+			err := mapFn(ctx, func() {
+				if atomic.AddInt64(&count, 1) == int64(total) {
+					cancel()
+				}
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+		t.Run("Early", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var count int64
+			err := mapFn(ctx, func() {
+				atomic.AddInt64(&count, 1)
+				cancel()
+			})
+			if err != context.Canceled {
+				t.Errorf("expected context.Canceled but got %v", err)
+			}
+			if count == int64(total) {
+				t.Error("expected some work to be skipped")
+			}
+		})
+	}
+
+	t.Run("Coordinates", func(t *testing.T) {
+		testCancel(t, func(ctx context.Context, f func()) error {
+			return mapCoordinatesContext(ctx, width, height, func(*goInfo, int, int, int) {
+				f()
+			})
+		}, width*height)
+	})
+	t.Run("Rows", func(t *testing.T) {
+		testCancel(t, func(ctx context.Context, f func()) error {
+			return mapRowsContext(ctx, width, height, func(*goInfo, int, int) {
+				f()
+			})
+		}, height)
+	})
+}
@@ -22,6 +22,19 @@ type Object interface {
 	Cast(r *model3d.Ray) (model3d.RayCollision, Material, bool)
 }
 
+// A BatchObject is an Object which can cast many rays at
+// once more efficiently than casting them one at a time.
+type BatchObject interface {
+	Object
+
+	// CastBatch is like Cast, but for many rays at once.
+	// The results are stored in the corresponding entries
+	// of collisions, materials, and ok, which must be at
+	// least as long as rays.
+	CastBatch(rays []*model3d.Ray, collisions []model3d.RayCollision, materials []Material,
+		ok []bool)
+}
+
 // A ColliderObject wraps a model3d.Collider in the Object
 // interface, using a constant material.
 type ColliderObject struct {
@@ -45,6 +58,16 @@ func (c *ColliderObject) Cast(r *model3d.Ray) (model3d.RayCollision, Material, b
 	return coll, c.Material, ok
 }
 
+// CastBatch returns the first collisions of many rays,
+// using a batched traversal if the collider supports it.
+func (c *ColliderObject) CastBatch(rays []*model3d.Ray, collisions []model3d.RayCollision,
+	materials []Material, ok []bool) {
+	model3d.FirstRayCollisions(c.Collider, rays, collisions, ok)
+	for i := range rays {
+		materials[i] = c.Material
+	}
+}
+
 // ParticipatingMedium is a volume in which a ray has a
 // probability of hitting a particle, in which the
 // collision probability increases with distance.
@@ -1,6 +1,7 @@
 package render3d
 
 import (
+	"context"
 	"math"
 
 	"github.com/unixpickle/essentials"
@@ -20,9 +21,14 @@ type rayRenderer struct {
 	Convergence          func(mean, stddev Color) bool
 	Antialias            float64
 	LogFunc              func(frac float64, sampleRate float64)
+	Progress             model3d.Progress
 }
 
 func (r *rayRenderer) Render(img *Image, obj Object) {
+	r.RenderContext(context.Background(), img, obj)
+}
+
+func (r *rayRenderer) RenderContext(ctx context.Context, img *Image, obj Object) error {
 	if r.NumSamples == 0 {
 		panic("must set NumSamples to non-zero for rayRenderer")
 	}
@@ -31,12 +37,14 @@ func (r *rayRenderer) Render(img *Image, obj Object) {
 	caster := r.Camera.Caster(maxX, maxY)
 
 	progressCh := make(chan int, 1)
+	var err error
 	go func() {
-		mapCoordinates(img.Width, img.Height, func(g *goInfo, x, y, idx int) {
-			color, numSamples := r.estimateColor(g, obj, float64(x), float64(y), caster)
-			img.Data[idx] = color
-			progressCh <- numSamples
-		})
+		err = mapCoordinatesContext(ctx, img.Width, img.Height,
+			func(g *goInfo, x, y, idx int) {
+				color, numSamples := r.estimateColor(g, obj, float64(x), float64(y), caster)
+				img.Data[idx] = color
+				progressCh <- numSamples
+			})
 		close(progressCh)
 	}()
 
@@ -44,15 +52,19 @@ func (r *rayRenderer) Render(img *Image, obj Object) {
 	var pixelsComplete int
 	var samplesTaken int
 	for n := range progressCh {
-		if r.LogFunc != nil {
-			pixelsComplete++
-			samplesTaken += n
-			if pixelsComplete%updateInterval == 0 {
-				r.LogFunc(float64(pixelsComplete)/float64(img.Width*img.Height),
-					float64(samplesTaken)/float64(pixelsComplete))
+		pixelsComplete++
+		samplesTaken += n
+		if pixelsComplete%updateInterval == 0 {
+			frac := float64(pixelsComplete) / float64(img.Width*img.Height)
+			if r.LogFunc != nil {
+				r.LogFunc(frac, float64(samplesTaken)/float64(pixelsComplete))
+			}
+			if r.Progress != nil {
+				r.Progress.Update(frac)
 			}
 		}
 	}
+	return err
 }
 
 func (r *rayRenderer) RenderVariance(img *Image, obj Object, numSamples int) {
@@ -1,6 +1,9 @@
 package render3d
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/unixpickle/model3d/model3d"
 )
 
@@ -9,31 +12,85 @@ import (
 type RayCaster struct {
 	Camera *Camera
 	Lights []*PointLight
+
+	// Progress, if specified, receives the fraction of
+	// pixels which have been rendered, roughly once per
+	// row of the image.
+	Progress model3d.Progress
 }
 
 // Render renders the object to an image.
+//
+// If obj is a BatchObject, an entire row of rays is cast
+// at once.
 func (r *RayCaster) Render(img *Image, obj Object) {
+	r.RenderContext(context.Background(), img, obj)
+}
+
+// RenderContext is like Render, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+// In this case, the image may be partially rendered.
+func (r *RayCaster) RenderContext(ctx context.Context, img *Image, obj Object) error {
 	maxX := float64(img.Width) - 1
 	maxY := float64(img.Height) - 1
 	caster := r.Camera.Caster(maxX, maxY)
 
-	mapCoordinates(img.Width, img.Height, func(g *goInfo, x, y, idx int) {
+	var pixelsComplete int64
+	numPixels := img.Width * img.Height
+	pixelsDone := func(n int) {
+		if r.Progress == nil {
+			return
+		}
+		total := atomic.AddInt64(&pixelsComplete, int64(n))
+		if total%int64(img.Width) == 0 {
+			r.Progress.Update(float64(total) / float64(numPixels))
+		}
+	}
+
+	if batchObj, ok := obj.(BatchObject); ok {
+		return mapRowsContext(ctx, img.Width, img.Height, func(g *goInfo, y, idx int) {
+			rays := make([]*model3d.Ray, img.Width)
+			for x := range rays {
+				rays[x] = &model3d.Ray{
+					Origin:    r.Camera.Origin,
+					Direction: caster(float64(x), float64(y)),
+				}
+			}
+			collisions := make([]model3d.RayCollision, img.Width)
+			materials := make([]Material, img.Width)
+			collides := make([]bool, img.Width)
+			batchObj.CastBatch(rays, collisions, materials, collides)
+			for x, ok := range collides {
+				if ok {
+					img.Data[idx+x] = r.shade(rays[x], collisions[x], materials[x])
+				}
+			}
+			pixelsDone(img.Width)
+		})
+	}
+
+	return mapCoordinatesContext(ctx, img.Width, img.Height, func(g *goInfo, x, y, idx int) {
 		ray := model3d.Ray{
 			Origin:    r.Camera.Origin,
 			Direction: caster(float64(x), float64(y)),
 		}
 		collision, material, ok := obj.Cast(&ray)
-		if !ok {
-			return
+		if ok {
+			img.Data[idx] = r.shade(&ray, collision, material)
 		}
-		point := ray.Origin.Add(ray.Direction.Scale(collision.Scale))
-		color := material.Ambient().Add(material.Emission())
-		for _, l := range r.Lights {
-			brdf := material.BSDF(collision.Normal, point.Sub(l.Origin).Normalize(),
-				ray.Origin.Sub(point).Normalize())
-			p2l := l.Origin.Sub(point)
-			color = color.Add(l.ShadeCollision(collision.Normal, p2l).Mul(brdf))
-		}
-		img.Data[idx] = color
+		pixelsDone(1)
 	})
 }
+
+func (r *RayCaster) shade(ray *model3d.Ray, collision model3d.RayCollision,
+	material Material) Color {
+	point := ray.Origin.Add(ray.Direction.Scale(collision.Scale))
+	color := material.Ambient().Add(material.Emission())
+	for _, l := range r.Lights {
+		brdf := material.BSDF(collision.Normal, point.Sub(l.Origin).Normalize(),
+			ray.Origin.Sub(point).Normalize())
+		p2l := l.Origin.Sub(point)
+		color = color.Add(l.ShadeCollision(collision.Normal, p2l).Mul(brdf))
+	}
+	return color
+}
@@ -1,6 +1,7 @@
 package render3d
 
 import (
+	"context"
 	"math"
 	"math/rand"
 
@@ -92,6 +93,10 @@ type RecursiveRayTracer struct {
 	// The sampleRate argument specifies the mean number
 	// of rays traced per pixel.
 	LogFunc func(frac float64, sampleRate float64)
+
+	// Progress, if specified, receives the fraction of
+	// pixels which have been colored.
+	Progress model3d.Progress
 }
 
 // Render renders the object to an image.
@@ -99,6 +104,13 @@ func (r *RecursiveRayTracer) Render(img *Image, obj Object) {
 	r.rayRenderer().Render(img, obj)
 }
 
+// RenderContext is like Render, but stops early and
+// returns ctx.Err() if ctx is cancelled or expires.
+// In this case, the image may be partially rendered.
+func (r *RecursiveRayTracer) RenderContext(ctx context.Context, img *Image, obj Object) error {
+	return r.rayRenderer().RenderContext(ctx, img, obj)
+}
+
 // RenderVariance computes the variance per pixel using a
 // fixed number of rays per pixel, and writes the results
 // as pixels in an image.
@@ -132,6 +144,7 @@ func (r *RecursiveRayTracer) rayRenderer() *rayRenderer {
 		Convergence:          r.Convergence,
 		Antialias:            r.Antialias,
 		LogFunc:              r.LogFunc,
+		Progress:             r.Progress,
 	}
 }
 
@@ -0,0 +1,139 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// A Bearing describes the dimensions of a radial ball
+// bearing, in millimeters.
+type Bearing struct {
+	// InnerDiameter is the diameter of the bore.
+	InnerDiameter float64
+
+	// OuterDiameter is the diameter of the outer race.
+	OuterDiameter float64
+
+	// Width is the axial width of the bearing.
+	Width float64
+}
+
+// Bearings maps names like "608" to the dimensions of
+// common deep groove ball bearings.
+var Bearings = map[string]*Bearing{
+	"603":  {InnerDiameter: 3, OuterDiameter: 9, Width: 5},
+	"623":  {InnerDiameter: 3, OuterDiameter: 10, Width: 4},
+	"624":  {InnerDiameter: 4, OuterDiameter: 13, Width: 5},
+	"625":  {InnerDiameter: 5, OuterDiameter: 16, Width: 5},
+	"626":  {InnerDiameter: 6, OuterDiameter: 19, Width: 6},
+	"608":  {InnerDiameter: 8, OuterDiameter: 22, Width: 7},
+	"688":  {InnerDiameter: 8, OuterDiameter: 16, Width: 5},
+	"6000": {InnerDiameter: 10, OuterDiameter: 26, Width: 8},
+	"6001": {InnerDiameter: 12, OuterDiameter: 28, Width: 8},
+	"6002": {InnerDiameter: 15, OuterDiameter: 32, Width: 9},
+	"6003": {InnerDiameter: 17, OuterDiameter: 35, Width: 10},
+	"6004": {InnerDiameter: 20, OuterDiameter: 42, Width: 12},
+	"6005": {InnerDiameter: 25, OuterDiameter: 47, Width: 12},
+}
+
+// Bushing creates a printed tube with the same outer
+// dimensions as the bearing, which can be used in place
+// of the bearing for slow or lightly loaded shafts.
+//
+// The tube extends from p1 to p2, and the clearance is
+// added to the radius of its bore.
+func (b *Bearing) Bushing(p1, p2 model3d.Coord3D, clearance float64) model3d.Solid {
+	return &model3d.SubtractedSolid{
+		Positive: &model3d.Cylinder{P1: p1, P2: p2, Radius: b.OuterDiameter / 2},
+		Negative: &model3d.Cylinder{P1: p1, P2: p2, Radius: b.InnerDiameter/2 + clearance},
+	}
+}
+
+// A BearingSeat is a model3d.Solid to subtract from a
+// part to create a pocket for a bearing.
+//
+// The pocket starts at the mouth P1 and extends towards
+// P2. Past the bearing, a narrower hole continues to P2,
+// leaving a shoulder which keeps the bearing from being
+// pushed through while giving the inner race room to
+// spin. For a blind pocket, P2 can be placed at the
+// bottom of the pocket.
+type BearingSeat struct {
+	Bearing *Bearing
+
+	// P1 is the center of the mouth of the pocket.
+	P1 model3d.Coord3D
+
+	// P2 is the end of the hole through the shoulder.
+	P2 model3d.Coord3D
+
+	// Clearance is added to the radius of the pocket.
+	// Negative values make a press fit.
+	Clearance float64
+
+	// Shoulder is the radial width of the shoulder at the
+	// bottom of the pocket.
+	// If 0, a quarter of the difference between the outer
+	// and inner diameters is used, so that the shoulder
+	// only touches the outer race.
+	Shoulder float64
+
+	// SnapLip, if non-zero, is the radial height of a lip
+	// at the mouth of the pocket which the bearing snaps
+	// past, retaining it without glue.
+	// The lip has a 45 degree lead-in to make insertion
+	// easier.
+	SnapLip float64
+}
+
+func (b *BearingSeat) Min() model3d.Coord3D {
+	return b.bounds().Min()
+}
+
+func (b *BearingSeat) Max() model3d.Coord3D {
+	return b.bounds().Max()
+}
+
+func (b *BearingSeat) Contains(c model3d.Coord3D) bool {
+	axis := b.P2.Sub(b.P1)
+	length := axis.Norm()
+	axis = axis.Scale(1 / length)
+	v := c.Sub(b.P1)
+	t := v.Dot(axis)
+	if t < 0 || t > length {
+		return false
+	}
+	r := v.Sub(axis.Scale(t)).Norm()
+	return r <= b.radiusAt(t)
+}
+
+// Depth gets the depth of the pocket, including the snap
+// lip if there is one.
+func (b *BearingSeat) Depth() float64 {
+	return b.Bearing.Width + 2*b.SnapLip
+}
+
+func (b *BearingSeat) radiusAt(t float64) float64 {
+	radius := b.Bearing.OuterDiameter/2 + b.Clearance
+	lip := b.SnapLip
+	if t < 2*lip {
+		// Lead-in from the mouth, followed by the lip.
+		return radius - math.Min(t, lip)
+	} else if t <= b.Depth() {
+		return radius
+	}
+	shoulder := b.Shoulder
+	if shoulder == 0 {
+		shoulder = (b.Bearing.OuterDiameter - b.Bearing.InnerDiameter) / 4
+	}
+	return radius - shoulder
+}
+
+func (b *BearingSeat) bounds() *model3d.Cylinder {
+	return &model3d.Cylinder{
+		P1:     b.P1,
+		P2:     b.P2,
+		Radius: b.Bearing.OuterDiameter/2 + math.Max(0, b.Clearance),
+	}
+}
@@ -0,0 +1,57 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestBearingSeat(t *testing.T) {
+	seat := &BearingSeat{
+		Bearing:   Bearings["608"],
+		P1:        model3d.Z(10),
+		P2:        model3d.Z(0),
+		Clearance: 0.1,
+		SnapLip:   0.3,
+	}
+	if seat.Depth() != 7.6 {
+		t.Errorf("unexpected depth: %f", seat.Depth())
+	}
+	expected := map[model3d.Coord3D]bool{
+		// Lead-in and lip.
+		model3d.XYZ(11.05, 0, 9.99): true,
+		model3d.XYZ(11.05, 0, 9.7):  false,
+		model3d.XYZ(10.75, 0, 9.5):  true,
+		model3d.XYZ(10.85, 0, 9.5):  false,
+		// The pocket itself.
+		model3d.XYZ(11.05, 0, 9):   true,
+		model3d.XYZ(0, 11.05, 2.5): true,
+		model3d.XYZ(11.15, 0, 5):   false,
+		// The shoulder and through hole.
+		model3d.XYZ(11.05, 0, 2.3): false,
+		model3d.XYZ(7.5, 0, 1):     true,
+		model3d.XYZ(7.7, 0, 1):     false,
+		model3d.XYZ(0, 0, -0.1):    false,
+	}
+	for c, exp := range expected {
+		if seat.Contains(c) != exp {
+			t.Errorf("point %v: expected %v", c, exp)
+		}
+	}
+	if !model3d.BoundsValid(seat) {
+		t.Error("invalid bounds")
+	}
+}
+
+func TestBearingBushing(t *testing.T) {
+	bushing := Bearings["623"].Bushing(model3d.Origin, model3d.Z(4), 0.1)
+	if bushing.Contains(model3d.XYZ(1.55, 0, 2)) {
+		t.Error("bore is too small")
+	}
+	if !bushing.Contains(model3d.XYZ(1.65, 0, 2)) || !bushing.Contains(model3d.XYZ(4.95, 0, 2)) {
+		t.Error("expected point in bushing")
+	}
+	if bushing.Contains(model3d.XYZ(5.05, 0, 2)) {
+		t.Error("bushing is too large")
+	}
+}
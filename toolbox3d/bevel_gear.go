@@ -0,0 +1,203 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// A BevelGear is a model3d.Solid for a straight bevel
+// gear, whose teeth lie on a cone rather than a cylinder.
+//
+// The teeth are shaped using Tredgold's approximation,
+// where the cross section of the teeth on the back cone
+// matches a spur gear with Teeth/cos(PitchAngle) teeth.
+// The teeth shrink towards the apex of the cone, and the
+// gear is bounded by spheres around the apex.
+//
+// Meshing pairs of gears can be created with a
+// BevelGearPair.
+type BevelGear struct {
+	// Apex is the tip of the gear's pitch cone.
+	Apex model3d.Coord3D
+
+	// Axis is the direction from the apex towards the
+	// gear along the gear's axis of rotation.
+	Axis model3d.Coord3D
+
+	// Reference is a direction perpendicular to Axis
+	// at which a tooth is centered (before applying
+	// Phase). If zero, an arbitrary direction is used.
+	Reference model3d.Coord3D
+
+	// Phase is the angle in radians by which the gear is
+	// rotated about its axis.
+	Phase float64
+
+	// Module is the pitch diameter at the large end of the
+	// teeth divided by the number of teeth.
+	Module float64
+
+	// Teeth is the number of teeth on the gear.
+	Teeth int
+
+	// PitchAngle is the angle in radians between the axis
+	// and the pitch cone.
+	PitchAngle float64
+
+	// PressureAngle is the pressure angle in radians.
+	// If 0, DefaultGearPressureAngle is used.
+	PressureAngle float64
+
+	// FaceWidth is the length of the teeth along the
+	// pitch cone.
+	FaceWidth float64
+
+	// BoreRadius, if non-zero, is the radius of a hole
+	// through the center of the gear for an axle.
+	BoreRadius float64
+}
+
+// ConeDistance gets the distance from the apex to the
+// pitch circle at the large end of the teeth.
+func (b *BevelGear) ConeDistance() float64 {
+	return b.Module * float64(b.Teeth) / (2 * math.Sin(b.PitchAngle))
+}
+
+func (b *BevelGear) Min() model3d.Coord3D {
+	return b.Apex.Sub(model3d.XYZ(1, 1, 1).Scale(b.ConeDistance()))
+}
+
+func (b *BevelGear) Max() model3d.Coord3D {
+	return b.Apex.Add(model3d.XYZ(1, 1, 1).Scale(b.ConeDistance()))
+}
+
+func (b *BevelGear) Contains(c model3d.Coord3D) bool {
+	coneDist := b.ConeDistance()
+	v := c.Sub(b.Apex)
+	dist := v.Norm()
+	if dist > coneDist || dist < coneDist-b.FaceWidth {
+		return false
+	}
+	axis, b1, b2 := b.basis()
+	height := v.Dot(axis)
+	if height <= 0 {
+		return false
+	}
+	radial := v.Sub(axis.Scale(height))
+	if radial.Norm() < b.BoreRadius {
+		return false
+	}
+
+	// Project the point onto the back cone of the gear,
+	// and look it up in the virtual spur gear.
+	cos := math.Cos(b.PitchAngle)
+	profile := b.virtualProfile()
+	coneAngle := math.Atan2(radial.Norm(), height)
+	virtualRadius := profile.PitchRadius() + coneDist*(coneAngle-b.PitchAngle)
+	if virtualRadius < profile.rootRadius {
+		return true
+	}
+	toothAngle := 2 * math.Pi / float64(b.Teeth)
+	theta := math.Atan2(v.Dot(b2), v.Dot(b1)) - b.Phase
+	theta -= math.Floor(theta/toothAngle) * toothAngle
+	virtualTheta := theta*cos + profile.reflectTheta/2
+	return profile.Contains(model2d.XY(math.Cos(virtualTheta),
+		math.Sin(virtualTheta)).Scale(virtualRadius))
+}
+
+func (b *BevelGear) basis() (axis, b1, b2 model3d.Coord3D) {
+	axis = b.Axis.Normalize()
+	b1 = b.Reference.Sub(axis.Scale(axis.Dot(b.Reference)))
+	if b1.Norm() == 0 {
+		b1, _ = axis.OrthoBasis()
+	}
+	b1 = b1.Normalize()
+	b2 = axis.Cross(b1)
+	return
+}
+
+func (b *BevelGear) virtualProfile() *involuteGearProfile {
+	pressureAngle := b.PressureAngle
+	if pressureAngle == 0 {
+		pressureAngle = DefaultGearPressureAngle
+	}
+	virtualTeeth := float64(b.Teeth) / math.Cos(b.PitchAngle)
+	return newInvoluteGearProfile(pressureAngle, b.Module, b.Module, 1.25*b.Module,
+		virtualTeeth)
+}
+
+// A BevelGearPair describes two straight bevel gears which
+// mesh with each other on intersecting shafts.
+type BevelGearPair struct {
+	// Module is the module at the large end of the teeth,
+	// shared by both gears.
+	Module float64
+
+	// Teeth1 and Teeth2 are the number of teeth on each
+	// gear.
+	Teeth1 int
+	Teeth2 int
+
+	// ShaftAngle is the angle in radians between the axes
+	// of the two gears.
+	// If 0, the shafts are at a right angle.
+	ShaftAngle float64
+
+	// PressureAngle is the pressure angle in radians.
+	// If 0, DefaultGearPressureAngle is used.
+	PressureAngle float64
+
+	// FaceWidth is the length of the teeth along the pitch
+	// cone.
+	// If 0, a third of the cone distance is used, which is
+	// the typical maximum.
+	FaceWidth float64
+}
+
+// Gears creates the two gears of the pair.
+//
+// The apexes of both pitch cones are at the origin. The
+// first gear's axis is the positive z-axis, and the second
+// gear's axis is rotated from the first by the shaft angle
+// towards the positive x-axis.
+func (b *BevelGearPair) Gears() (*BevelGear, *BevelGear) {
+	shaftAngle := b.ShaftAngle
+	if shaftAngle == 0 {
+		shaftAngle = math.Pi / 2
+	}
+	ratio := float64(b.Teeth2) / float64(b.Teeth1)
+	pitch1 := math.Atan2(math.Sin(shaftAngle), ratio+math.Cos(shaftAngle))
+	pitch2 := shaftAngle - pitch1
+
+	axis1 := model3d.Z(1)
+	axis2 := model3d.XZ(math.Sin(shaftAngle), math.Cos(shaftAngle))
+	contact := model3d.XZ(math.Sin(pitch1), math.Cos(pitch1))
+
+	g1 := &BevelGear{
+		Axis:          axis1,
+		Reference:     contact,
+		Module:        b.Module,
+		Teeth:         b.Teeth1,
+		PitchAngle:    pitch1,
+		PressureAngle: b.PressureAngle,
+	}
+	g2 := &BevelGear{
+		Axis:      axis2,
+		Reference: contact,
+		// Center a gap between teeth at the contact line.
+		Phase:         math.Pi / float64(b.Teeth2),
+		Module:        b.Module,
+		Teeth:         b.Teeth2,
+		PitchAngle:    pitch2,
+		PressureAngle: b.PressureAngle,
+	}
+	faceWidth := b.FaceWidth
+	if faceWidth == 0 {
+		faceWidth = g1.ConeDistance() / 3
+	}
+	g1.FaceWidth = faceWidth
+	g2.FaceWidth = faceWidth
+	return g1, g2
+}
@@ -0,0 +1,55 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestBevelGearPair(t *testing.T) {
+	for _, shaftAngle := range []float64{0, math.Pi / 3} {
+		pair := &BevelGearPair{Module: 1, Teeth1: 15, Teeth2: 25, ShaftAngle: shaftAngle}
+		g1, g2 := pair.Gears()
+		if d1, d2 := g1.ConeDistance(), g2.ConeDistance(); math.Abs(d1-d2) > 1e-8 {
+			t.Fatalf("cone distances should match: %f, %f", d1, d2)
+		}
+
+		min := g1.Min().Min(g2.Min())
+		max := g1.Max().Max(g2.Max())
+		var count1, count2, overlap int
+		for i := 0; i < 200000; i++ {
+			c := model3d.NewCoord3DRandBounds(min, max)
+			in1, in2 := g1.Contains(c), g2.Contains(c)
+			if in1 {
+				count1++
+			}
+			if in2 {
+				count2++
+			}
+			if in1 && in2 {
+				overlap++
+			}
+		}
+		if count1 == 0 || count2 == 0 {
+			t.Fatal("gears should not be empty")
+		}
+		if frac := float64(overlap) / float64(count1+count2); frac > 1e-3 {
+			t.Errorf("shaft angle %f: gears overlap in %f of their volume", shaftAngle, frac)
+		}
+
+		// Rotating the second gear by half a tooth should
+		// cause the teeth to collide.
+		g2.Phase = 0
+		overlap = 0
+		for i := 0; i < 200000; i++ {
+			c := model3d.NewCoord3DRandBounds(min, max)
+			if g1.Contains(c) && g2.Contains(c) {
+				overlap++
+			}
+		}
+		if overlap == 0 {
+			t.Error("misaligned gears should overlap")
+		}
+	}
+}
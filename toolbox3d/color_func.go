@@ -1,8 +1,9 @@
 package toolbox3d
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"image"
 	"math"
 	"os"
 	"sync"
@@ -115,6 +116,19 @@ func (c CoordColorFunc) Transform(t model3d.Transform) CoordColorFunc {
 // default to DefaultTextureImageAntialias.
 func (c CoordColorFunc) ToTexture(out *render3d.Image, mapping model3d.MeshUVMap, antialias int,
 	verbose bool) {
+	var p model3d.Progress
+	if verbose {
+		p = model3d.NewLogProgress()
+	}
+	c.ToTextureProgress(out, mapping, antialias, p)
+}
+
+// ToTextureProgress is like ToTexture, but reports the
+// fraction of filled pixels to p rather than logging.
+//
+// The progress p may be nil.
+func (c CoordColorFunc) ToTextureProgress(out *render3d.Image, mapping model3d.MeshUVMap,
+	antialias int, p model3d.Progress) {
 	if antialias == 0 {
 		antialias = DefaultTextureImageAntialias
 	}
@@ -123,12 +137,12 @@ func (c CoordColorFunc) ToTexture(out *render3d.Image, mapping model3d.MeshUVMap
 	dx := 1 / float64(out.Width*antialias)
 	dy := 1 / float64(out.Height*antialias)
 	numPixels := out.Width * out.Height
-	logInterval := numPixels / 10
+	logInterval := essentials.MaxInt(1, numPixels/10)
 	essentials.ConcurrentMap(0, numPixels, func(i int) {
 		x := i % out.Width
 		y := i / out.Width
-		if verbose && i%logInterval == 0 {
-			log.Printf("- filled %.02f%% of texture", 100*(float64(i)/float64(numPixels)))
+		if p != nil && i%logInterval == 0 {
+			p.Update(float64(i) / float64(numPixels))
 		}
 		minY := float64(y) / float64(out.Height)
 		minX := float64(x) / float64(out.Width)
@@ -147,8 +161,9 @@ func (c CoordColorFunc) ToTexture(out *render3d.Image, mapping model3d.MeshUVMap
 			out.Set(x, out.Height-(y+1), sum.Scale(1/count))
 		}
 	})
-	if verbose {
-		log.Println("- filled texture")
+	if p != nil {
+		p.Update(1)
+		p.Log("filled texture")
 	}
 }
 
@@ -167,16 +182,25 @@ func (c CoordColorFunc) ToTexture(out *render3d.Image, mapping model3d.MeshUVMap
 // automatically.
 func (c CoordColorFunc) SaveTexturedMaterialOBJ(path string, mesh *model3d.Mesh,
 	uvMap model3d.MeshUVMap, resolution int, verbose bool) error {
+	var p model3d.Progress
+	if verbose {
+		p = model3d.NewLogProgress()
+	}
 	if uvMap == nil {
-		uvMap = model3d.BuildAutomaticUVMap(mesh, resolution, verbose)
+		var err error
+		uvMap, err = model3d.BuildAutomaticUVMapProgress(context.Background(), mesh,
+			resolution, p)
+		if err != nil {
+			return errors.Wrap(err, "save textured material OBJ")
+		}
 	}
 	tris := mesh.TriangleSlice()
 	obj, mtl := model3d.BuildUVMapMaterialOBJ(tris, uvMap)
 	img := render3d.NewImage(resolution, resolution)
-	if verbose {
-		log.Printf("- constructing texture...")
+	if p != nil {
+		p.Log("constructing texture...")
 	}
-	c.ToTexture(img, uvMap, 0, verbose)
+	c.ToTextureProgress(img, uvMap, 0, p)
 	f, err := os.Create(path)
 	if err != nil {
 		return errors.Wrap(err, "save textured material OBJ")
@@ -191,6 +215,90 @@ func (c CoordColorFunc) SaveTexturedMaterialOBJ(path string, mesh *model3d.Mesh,
 	return nil
 }
 
+// TextureColorFunc creates a CoordColorFunc that colors
+// points on a mesh by looking up their texture
+// coordinates in an image.
+//
+// This is the inverse of ToTexture, so the UV map should
+// be confined to the unit square, with (0, 0) at the
+// bottom-left corner of the image. This is the case for
+// maps created by model3d.BuildAutomaticUVMap.
+//
+// Points that are not on the mesh are mapped to the
+// nearest point on the mesh. Colors are bilinearly
+// interpolated between pixels.
+func TextureColorFunc(uvMap model3d.MeshUVMap, img image.Image) CoordColorFunc {
+	tris := make([]*model3d.Triangle, 0, len(uvMap))
+	for t := range uvMap {
+		tris = append(tris, t)
+	}
+	model3d.GroupTriangles(tris)
+	sdf := model3d.GroupedTrianglesToSDF(tris)
+	texture := textureImage(img)
+
+	return func(c model3d.Coord3D) render3d.Color {
+		tri, closest, _ := sdf.FaceSDF(c)
+		weights := triangleBarycentric(tri, closest)
+		uvs := uvMap[tri]
+		var uv model2d.Coord
+		for i, w := range weights {
+			uv = uv.Add(uvs[i].Scale(w))
+		}
+		return textureBilinear(texture, uv)
+	}
+}
+
+func textureImage(img image.Image) *render3d.Image {
+	bounds := img.Bounds()
+	res := render3d.NewImage(bounds.Dx(), bounds.Dy())
+	for y := 0; y < res.Height; y++ {
+		for x := 0; x < res.Width; x++ {
+			r, g, b, _ := img.At(x+bounds.Min.X, y+bounds.Min.Y).RGBA()
+			res.Set(x, y, render3d.NewColorRGB(
+				float64(r)/0xffff,
+				float64(g)/0xffff,
+				float64(b)/0xffff,
+			))
+		}
+	}
+	return res
+}
+
+func textureBilinear(img *render3d.Image, uv model2d.Coord) render3d.Color {
+	// Pixel centers are at half-integer texture coordinates,
+	// and the Y axis is flipped, matching ToTexture.
+	x := uv.X*float64(img.Width) - 0.5
+	y := (1-uv.Y)*float64(img.Height) - 0.5
+	x = math.Max(0, math.Min(float64(img.Width-1), x))
+	y = math.Max(0, math.Min(float64(img.Height-1), y))
+
+	x0, y0 := int(x), int(y)
+	x1, y1 := essentials.MinInt(x0+1, img.Width-1), essentials.MinInt(y0+1, img.Height-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	top := img.At(x0, y0).Scale(1 - fx).Add(img.At(x1, y0).Scale(fx))
+	bottom := img.At(x0, y1).Scale(1 - fx).Add(img.At(x1, y1).Scale(fx))
+	return top.Scale(1 - fy).Add(bottom.Scale(fy))
+}
+
+func triangleBarycentric(t *model3d.Triangle, c model3d.Coord3D) [3]float64 {
+	v0 := t[1].Sub(t[0])
+	v1 := t[2].Sub(t[0])
+	v2 := c.Sub(t[0])
+	d00 := v0.Dot(v0)
+	d01 := v0.Dot(v1)
+	d11 := v1.Dot(v1)
+	d20 := v2.Dot(v0)
+	d21 := v2.Dot(v1)
+	denom := d00*d11 - d01*d01
+	if denom == 0 {
+		return [3]float64{1, 0, 0}
+	}
+	w1 := (d11*d20 - d01*d21) / denom
+	w2 := (d00*d21 - d01*d20) / denom
+	return [3]float64{1 - w1 - w2, w1, w2}
+}
+
 // ChangeFilterFunc creates a filter for mesh decimation
 // that avoids decimating vertices near color changes.
 //
@@ -380,3 +488,17 @@ func colorFuncFromObj(obj any) CoordColorFunc {
 		panic(fmt.Sprintf("unknown type for color: %T", colorFn))
 	}
 }
+
+// ByteColorFunc creates a CoordColorFunc from a function
+// that returns 8-bit sRGB colors, such as the color
+// function of a model3d.PLYAsset.
+func ByteColorFunc(f func(c model3d.Coord3D) [3]uint8) CoordColorFunc {
+	return func(c model3d.Coord3D) render3d.Color {
+		color := f(c)
+		return render3d.NewColorRGB(
+			float64(color[0])/255,
+			float64(color[1])/255,
+			float64(color[2])/255,
+		)
+	}
+}
@@ -0,0 +1,79 @@
+package toolbox3d
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+func TestTextureColorFunc(t *testing.T) {
+	// A unit square in the XY plane, mapped directly to
+	// the unit square in UV space.
+	t1 := &model3d.Triangle{model3d.XYZ(0, 0, 0), model3d.XYZ(1, 0, 0), model3d.XYZ(1, 1, 0)}
+	t2 := &model3d.Triangle{model3d.XYZ(0, 0, 0), model3d.XYZ(1, 1, 0), model3d.XYZ(0, 1, 0)}
+	uvMap := model3d.MeshUVMap{}
+	for _, tri := range []*model3d.Triangle{t1, t2} {
+		var uv [3]model2d.Coord
+		for i, c := range tri {
+			uv[i] = c.XY()
+		}
+		uvMap[tri] = uv
+	}
+
+	// Red on the top half of the image, and blue on the
+	// bottom half.
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if y < 2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	colorFn := TextureColorFunc(uvMap, img)
+	red := render3d.NewColorRGB(1, 0, 0)
+	blue := render3d.NewColorRGB(0, 0, 1)
+	expected := map[model3d.Coord3D]render3d.Color{
+		model3d.XYZ(0.1, 0.9, 0):   red,
+		model3d.XYZ(0.8, 0.7, 0.5): red,
+		model3d.XYZ(0.3, 0.1, 0):   blue,
+		model3d.XYZ(0.9, 0.2, -1):  blue,
+		model3d.XYZ(0.5, 0.5, 0):   red.Add(blue).Scale(0.5),
+	}
+	for c, exp := range expected {
+		actual := colorFn(c)
+		if actual.Dist(exp) > 1e-5 {
+			t.Errorf("point %v: expected color %v but got %v", c, exp, actual)
+		}
+	}
+
+	// Round-trip through ToTexture.
+	out := render3d.NewImage(4, 4)
+	colorFn.ToTexture(out, uvMap, 1, false)
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			r, _, b := render3d.RGB(out.At(x, y))
+			if math.Abs(r-float64(1-y/2)) > 1e-3 || math.Abs(b-float64(y/2)) > 1e-3 {
+				t.Errorf("unexpected round-trip color at %d,%d: %f,%f", x, y, r, b)
+			}
+		}
+	}
+}
+
+func TestByteColorFunc(t *testing.T) {
+	f := ByteColorFunc(func(c model3d.Coord3D) [3]uint8 {
+		return [3]uint8{255, 128, 0}
+	})
+	r, g, b := render3d.RGB(f(model3d.Origin))
+	if math.Abs(r-1) > 1e-5 || math.Abs(g-128.0/255) > 1e-5 || math.Abs(b) > 1e-5 {
+		t.Errorf("unexpected color: %f, %f, %f", r, g, b)
+	}
+}
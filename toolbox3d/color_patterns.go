@@ -0,0 +1,183 @@
+package toolbox3d
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+const DefaultNoiseOctaves = 4
+
+// LinearGradientColorFunc creates a CoordColorFunc that
+// blends from c1 to c2 along the segment from p1 to p2.
+//
+// Points beyond either end of the segment get the color
+// of that end.
+func LinearGradientColorFunc(p1, p2 model3d.Coord3D, c1, c2 render3d.Color) CoordColorFunc {
+	axis := p2.Sub(p1)
+	scale := 1 / axis.Dot(axis)
+	return func(c model3d.Coord3D) render3d.Color {
+		t := math.Max(0, math.Min(1, c.Sub(p1).Dot(axis)*scale))
+		return blendColors(c1, c2, t)
+	}
+}
+
+// RadialGradientColorFunc creates a CoordColorFunc that
+// blends from inner at center to outer at the given
+// radius from center.
+func RadialGradientColorFunc(center model3d.Coord3D, radius float64,
+	inner, outer render3d.Color) CoordColorFunc {
+	return func(c model3d.Coord3D) render3d.Color {
+		t := math.Min(1, c.Dist(center)/radius)
+		return blendColors(inner, outer, t)
+	}
+}
+
+// CheckerboardColorFunc creates a CoordColorFunc that
+// alternates between two colors in a 3D grid of cubes
+// with the given side length.
+func CheckerboardColorFunc(size float64, c1, c2 render3d.Color) CoordColorFunc {
+	return func(c model3d.Coord3D) render3d.Color {
+		idx := math.Floor(c.X/size) + math.Floor(c.Y/size) + math.Floor(c.Z/size)
+		if math.Mod(idx, 2) == 0 {
+			return c1
+		}
+		return c2
+	}
+}
+
+// MarbleColorFunc creates a CoordColorFunc for a marble
+// pattern of veins perpendicular to the X axis.
+//
+// The period is the distance between veins, and the
+// turbulence controls how far the veins are distorted by
+// noise.
+//
+// Use CoordColorFunc.Transform to orient the pattern.
+func MarbleColorFunc(noise *PerlinNoise, period, turbulence float64,
+	base, vein render3d.Color) CoordColorFunc {
+	return func(c model3d.Coord3D) render3d.Color {
+		offset := turbulence * noise.FBM(c.Scale(1/period), DefaultNoiseOctaves)
+		t := 0.5 + 0.5*math.Sin((c.X/period+offset)*2*math.Pi)
+		return blendColors(base, vein, math.Pow(1-t, 4))
+	}
+}
+
+// WoodColorFunc creates a CoordColorFunc for a wood grain
+// pattern of rings around the Z axis.
+//
+// The period is the distance between rings, and the
+// turbulence controls how far the rings are distorted by
+// noise.
+//
+// Use CoordColorFunc.Transform to orient the pattern.
+func WoodColorFunc(noise *PerlinNoise, period, turbulence float64,
+	light, dark render3d.Color) CoordColorFunc {
+	return func(c model3d.Coord3D) render3d.Color {
+		offset := turbulence * noise.FBM(c.Scale(1/period), DefaultNoiseOctaves)
+		r := c.XY().Norm()/period + offset
+		t := r - math.Floor(r)
+		return blendColors(light, dark, t*t)
+	}
+}
+
+// PerlinNoise implements 3D gradient noise, which varies
+// smoothly and randomly in space.
+type PerlinNoise struct {
+	perm [512]int
+}
+
+// NewPerlinNoise creates a noise function from a random
+// seed.
+func NewPerlinNoise(seed int64) *PerlinNoise {
+	res := &PerlinNoise{}
+	for i, x := range rand.New(rand.NewSource(seed)).Perm(256) {
+		res.perm[i] = x
+		res.perm[i+256] = x
+	}
+	return res
+}
+
+// Noise evaluates the noise function at a point.
+//
+// The result is roughly in the range [-1, 1], and is zero
+// at every integer coordinate.
+func (p *PerlinNoise) Noise(c model3d.Coord3D) float64 {
+	fx, fy, fz := math.Floor(c.X), math.Floor(c.Y), math.Floor(c.Z)
+	x, y, z := c.X-fx, c.Y-fy, c.Z-fz
+	xi, yi, zi := int(fx)&255, int(fy)&255, int(fz)&255
+	u, v, w := perlinFade(x), perlinFade(y), perlinFade(z)
+
+	a := p.perm[xi] + yi
+	aa := p.perm[a] + zi
+	ab := p.perm[a+1] + zi
+	b := p.perm[xi+1] + yi
+	ba := p.perm[b] + zi
+	bb := p.perm[b+1] + zi
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, perlinGrad(p.perm[aa], x, y, z), perlinGrad(p.perm[ba], x-1, y, z)),
+			lerp(u, perlinGrad(p.perm[ab], x, y-1, z), perlinGrad(p.perm[bb], x-1, y-1, z)),
+		),
+		lerp(v,
+			lerp(u, perlinGrad(p.perm[aa+1], x, y, z-1), perlinGrad(p.perm[ba+1], x-1, y, z-1)),
+			lerp(u, perlinGrad(p.perm[ab+1], x, y-1, z-1), perlinGrad(p.perm[bb+1], x-1, y-1, z-1)),
+		),
+	)
+}
+
+// FBM evaluates fractal Brownian motion, a sum of noise
+// at increasing frequencies and decreasing amplitudes.
+//
+// Each octave doubles the frequency and halves the
+// amplitude. The result is normalized to roughly the
+// range [-1, 1].
+func (p *PerlinNoise) FBM(c model3d.Coord3D, octaves int) float64 {
+	var sum, totalAmp float64
+	amp := 1.0
+	for i := 0; i < octaves; i++ {
+		sum += amp * p.Noise(c)
+		totalAmp += amp
+		amp /= 2
+		c = c.Scale(2)
+	}
+	return sum / totalAmp
+}
+
+func perlinFade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func perlinGrad(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u := x
+	if h >= 8 {
+		u = y
+	}
+	var v float64
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	} else {
+		v = z
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+func blendColors(c1, c2 render3d.Color, t float64) render3d.Color {
+	return c1.Scale(1 - t).Add(c2.Scale(t))
+}
@@ -0,0 +1,115 @@
+package toolbox3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+func TestGradientColorFuncs(t *testing.T) {
+	black, white := render3d.NewColor(0), render3d.NewColor(1)
+
+	linear := LinearGradientColorFunc(model3d.X(1), model3d.X(3), black, white)
+	expected := map[model3d.Coord3D]float64{
+		model3d.XYZ(0, 5, 3):   0,
+		model3d.XYZ(1, 0, 0):   0,
+		model3d.XYZ(2, 1, 0):   0.5,
+		model3d.XYZ(2.5, 0, 1): 0.75,
+		model3d.XYZ(4, 0, 0):   1,
+	}
+	for c, exp := range expected {
+		if actual := linear(c); actual.Dist(render3d.NewColor(exp)) > 1e-8 {
+			t.Errorf("linear %v: expected %f but got %v", c, exp, actual)
+		}
+	}
+
+	radial := RadialGradientColorFunc(model3d.Z(1), 2, black, white)
+	expected = map[model3d.Coord3D]float64{
+		model3d.XYZ(0, 0, 1): 0,
+		model3d.XYZ(0, 1, 1): 0.5,
+		model3d.XYZ(0, 0, 4): 1,
+	}
+	for c, exp := range expected {
+		if actual := radial(c); actual.Dist(render3d.NewColor(exp)) > 1e-8 {
+			t.Errorf("radial %v: expected %f but got %v", c, exp, actual)
+		}
+	}
+}
+
+func TestCheckerboardColorFunc(t *testing.T) {
+	black, white := render3d.NewColor(0), render3d.NewColor(1)
+	checker := CheckerboardColorFunc(2, black, white)
+	expected := map[model3d.Coord3D]render3d.Color{
+		model3d.XYZ(0.5, 0.5, 0.5):   black,
+		model3d.XYZ(2.5, 0.5, 0.5):   white,
+		model3d.XYZ(2.5, 2.5, 0.5):   black,
+		model3d.XYZ(-0.5, 0.5, 0.5):  white,
+		model3d.XYZ(-0.5, -0.5, 0.5): black,
+	}
+	for c, exp := range expected {
+		if checker(c) != exp {
+			t.Errorf("point %v: expected %v", c, exp)
+		}
+	}
+}
+
+func TestPerlinNoise(t *testing.T) {
+	noise := NewPerlinNoise(1337)
+	var minValue, maxValue float64
+	for i := 0; i < 10000; i++ {
+		c := model3d.NewCoord3DRandNorm().Scale(10)
+		value := noise.Noise(c)
+		minValue = math.Min(minValue, value)
+		maxValue = math.Max(maxValue, value)
+
+		// The noise should be continuous.
+		delta := model3d.NewCoord3DRandUnit().Scale(1e-5)
+		if math.Abs(noise.Noise(c.Add(delta))-value) > 1e-3 {
+			t.Fatalf("noise is not continuous at %v", c)
+		}
+
+		if fbm := noise.FBM(c, 4); math.Abs(fbm) > 1.1 {
+			t.Fatalf("unexpected FBM value: %f", fbm)
+		}
+
+		lattice := model3d.XYZ(
+			float64(rand.Intn(100)-50),
+			float64(rand.Intn(100)-50),
+			float64(rand.Intn(100)-50),
+		)
+		if noise.Noise(lattice) != 0 {
+			t.Fatalf("expected zero at lattice point %v", lattice)
+		}
+	}
+	if minValue > -0.5 || maxValue < 0.5 || minValue < -1.1 || maxValue > 1.1 {
+		t.Errorf("unexpected range: %f to %f", minValue, maxValue)
+	}
+
+	other := NewPerlinNoise(1338)
+	c := model3d.XYZ(0.3, 0.7, 0.1)
+	if noise.Noise(c) == other.Noise(c) {
+		t.Error("different seeds should give different noise")
+	}
+}
+
+func TestProceduralColorFuncs(t *testing.T) {
+	noise := NewPerlinNoise(1)
+	black, white := render3d.NewColor(0), render3d.NewColor(1)
+	for _, fn := range []CoordColorFunc{
+		MarbleColorFunc(noise, 1, 0.5, white, black),
+		WoodColorFunc(noise, 0.3, 0.2, white, black),
+	} {
+		var minValue, maxValue = math.Inf(1), math.Inf(-1)
+		for i := 0; i < 1000; i++ {
+			value := fn(model3d.NewCoord3DRandUniform().Scale(3)).X
+			minValue = math.Min(minValue, value)
+			maxValue = math.Max(maxValue, value)
+		}
+		if minValue < 0 || maxValue > 1 || maxValue-minValue < 0.5 {
+			t.Errorf("unexpected color range: %f to %f", minValue, maxValue)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package toolbox3d
+
+import (
+	"image"
+	"math"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+// EquirectColorFunc creates a CoordColorFunc that wraps
+// an equirectangular image around a center point.
+//
+// Each point is colored according to its direction from
+// the center, using the conventions of model3d.GeoCoord
+// and NewEquirect: the top of the image is the positive Y
+// direction, and the center of the image faces the
+// positive Z direction.
+func EquirectColorFunc(img image.Image, center model3d.Coord3D) CoordColorFunc {
+	texture := textureImage(img)
+	return func(c model3d.Coord3D) render3d.Color {
+		g := c.Sub(center).Geo()
+		uv := model2d.XY((g.Lon+math.Pi)/(2*math.Pi), (g.Lat+math.Pi/2)/math.Pi)
+		return textureBilinear(texture, uv)
+	}
+}
+
+// CylindricalColorFunc creates a CoordColorFunc that
+// wraps an image around the axis from p1 to p2, like a
+// label on a can.
+//
+// The bottom of the image is at p1 and the top is at p2.
+// The horizontal center of the image faces the positive Z
+// direction, or the negative Y direction if the axis is
+// parallel to Z. Points beyond either end of the axis get
+// the color at the nearest edge of the image.
+func CylindricalColorFunc(img image.Image, p1, p2 model3d.Coord3D) CoordColorFunc {
+	texture := textureImage(img)
+	axis := p2.Sub(p1)
+	invNormSquared := 1 / axis.Dot(axis)
+	front, side := cylindricalBasis(axis.Normalize())
+	return func(c model3d.Coord3D) render3d.Color {
+		v := c.Sub(p1)
+		theta := math.Atan2(v.Dot(side), v.Dot(front))
+		uv := model2d.XY((theta+math.Pi)/(2*math.Pi), v.Dot(axis)*invNormSquared)
+		return textureBilinear(texture, uv)
+	}
+}
+
+// PlanarColorFunc creates a CoordColorFunc that projects
+// an image onto a model along the normal of a plane.
+//
+// The bottom-left corner of the image is at origin, and
+// the bottom and left edges of the image extend along
+// xAxis and yAxis, respectively. Points outside of the
+// image get the color at the nearest edge of the image.
+func PlanarColorFunc(img image.Image, origin, xAxis, yAxis model3d.Coord3D) CoordColorFunc {
+	texture := textureImage(img)
+
+	// Solve for coordinates in the (possibly skewed) basis
+	// of the two axes.
+	matrix := model2d.Matrix2{
+		xAxis.Dot(xAxis), xAxis.Dot(yAxis),
+		yAxis.Dot(xAxis), yAxis.Dot(yAxis),
+	}
+	inv := matrix.Inverse()
+	return func(c model3d.Coord3D) render3d.Color {
+		v := c.Sub(origin)
+		uv := inv.MulColumn(model2d.XY(v.Dot(xAxis), v.Dot(yAxis)))
+		return textureBilinear(texture, uv)
+	}
+}
+
+func cylindricalBasis(axis model3d.Coord3D) (front, side model3d.Coord3D) {
+	front = model3d.Z(1).ProjectOut(axis)
+	if front.Norm() < 1e-5 {
+		front = model3d.Y(-1).ProjectOut(axis)
+	}
+	front = front.Normalize()
+	side = axis.Cross(front)
+	return
+}
@@ -0,0 +1,76 @@
+package toolbox3d
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+	"github.com/unixpickle/model3d/render3d"
+)
+
+func TestProjectionColorFuncs(t *testing.T) {
+	// Each quadrant of the image has a different color.
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	quadrantColors := [2][2]color.RGBA{
+		{{R: 255, A: 255}, {G: 255, A: 255}},
+		{{B: 255, A: 255}, {R: 255, G: 255, A: 255}},
+	}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, quadrantColors[y/4][x/4])
+		}
+	}
+	topLeft := render3d.NewColorRGB(1, 0, 0)
+	topRight := render3d.NewColorRGB(0, 1, 0)
+	bottomLeft := render3d.NewColorRGB(0, 0, 1)
+	bottomRight := render3d.NewColorRGB(1, 1, 0)
+
+	testCases := []struct {
+		Name     string
+		Func     CoordColorFunc
+		Expected map[model3d.Coord3D]render3d.Color
+	}{
+		{
+			Name: "Equirect",
+			Func: EquirectColorFunc(img, model3d.X(1)),
+			Expected: map[model3d.Coord3D]render3d.Color{
+				model3d.XYZ(0.5, 1, 1):   topLeft,
+				model3d.XYZ(1.5, 1, 1):   topRight,
+				model3d.XYZ(0.5, -1, 1):  bottomLeft,
+				model3d.XYZ(1.5, -1, 1):  bottomRight,
+				model3d.XYZ(0.5, 1, -1):  topLeft,
+				model3d.XYZ(1.5, -1, -1): bottomRight,
+			},
+		},
+		{
+			Name: "Cylindrical",
+			Func: CylindricalColorFunc(img, model3d.Y(-1), model3d.Y(1)),
+			Expected: map[model3d.Coord3D]render3d.Color{
+				model3d.XYZ(-1, 0.5, 1):  topLeft,
+				model3d.XYZ(1, 0.5, 1):   topRight,
+				model3d.XYZ(-1, -0.5, 1): bottomLeft,
+				model3d.XYZ(1, -0.5, 1):  bottomRight,
+				model3d.XYZ(1, -5, 1):    bottomRight,
+			},
+		},
+		{
+			Name: "Planar",
+			Func: PlanarColorFunc(img, model3d.XYZ(0, 0, 1), model3d.X(2), model3d.Z(-2)),
+			Expected: map[model3d.Coord3D]render3d.Color{
+				model3d.XYZ(0.5, 3, -0.5): topLeft,
+				model3d.XYZ(1.5, 0, -0.5): topRight,
+				model3d.XYZ(0.5, 1, 0.5):  bottomLeft,
+				model3d.XYZ(1.5, 2, 0.5):  bottomRight,
+				model3d.XYZ(5, 2, 5):      bottomRight,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		for c, expected := range tc.Expected {
+			if actual := tc.Func(c); actual.Dist(expected) > 1e-5 {
+				t.Errorf("%s: point %v should have color %v but got %v", tc.Name, c, expected, actual)
+			}
+		}
+	}
+}
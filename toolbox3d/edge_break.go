@@ -0,0 +1,188 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// DefaultEdgeBreakerMinAngle is the default minimum angle
+// between the normals of two faces for their shared edge
+// to be broken by an EdgeBreaker.
+const DefaultEdgeBreakerMinAngle = math.Pi / 6
+
+// An EdgeBreakMode determines how an EdgeBreaker replaces
+// sharp edges.
+type EdgeBreakMode int
+
+const (
+	// EdgeFillet replaces edges with rounded fillets.
+	EdgeFillet EdgeBreakMode = iota
+
+	// EdgeChamfer replaces edges with flat chamfers.
+	EdgeChamfer
+)
+
+// An EdgeBreaker detects the sharp convex edges of a mesh
+// and replaces them with fillets or chamfers.
+//
+// Edges are broken independently, so the radius should be
+// small compared to the faces adjacent to each edge.
+type EdgeBreaker struct {
+	// Mode determines the shape of broken edges.
+	Mode EdgeBreakMode
+
+	// Radius is the radius of fillets. Chamfers connect
+	// the same two points that a fillet would.
+	Radius float64
+
+	// MinAngle is the minimum angle between the normals of
+	// two faces for their shared edge to be broken.
+	// If 0, DefaultEdgeBreakerMinAngle is used.
+	MinAngle float64
+}
+
+// FeatureEdges finds the convex edges of a mesh which
+// should be broken.
+func (e *EdgeBreaker) FeatureEdges(m *model3d.Mesh) []model3d.Segment {
+	var res []model3d.Segment
+	seen := map[model3d.Segment]bool{}
+	m.Iterate(func(t *model3d.Triangle) {
+		for _, seg := range t.Segments() {
+			if seen[seg] {
+				continue
+			}
+			seen[seg] = true
+			if _, _, ok := e.edgeNormals(m, seg); ok {
+				res = append(res, seg)
+			}
+		}
+	})
+	return res
+}
+
+// Solid creates a solid for the mesh with its feature
+// edges broken.
+func (e *EdgeBreaker) Solid(m *model3d.Mesh) model3d.Solid {
+	solid := model3d.NewColliderSolid(model3d.MeshToCollider(m))
+
+	var cutters model3d.JoinedSolid
+	for _, seg := range e.FeatureEdges(m) {
+		n1, n2, _ := e.edgeNormals(m, seg)
+		cutters = append(cutters, newEdgeCutter(seg, n1, n2, e.Radius, e.Mode))
+	}
+	if len(cutters) == 0 {
+		return solid
+	}
+	return &model3d.SubtractedSolid{
+		Positive: solid,
+		Negative: cutters.Optimize(),
+	}
+}
+
+// Mesh creates a mesh with the feature edges broken, by
+// applying marching cubes to Solid() with the given grid
+// spacing.
+func (e *EdgeBreaker) Mesh(m *model3d.Mesh, delta float64) *model3d.Mesh {
+	return model3d.MarchingCubesSearch(e.Solid(m), delta, 8)
+}
+
+func (e *EdgeBreaker) edgeNormals(m *model3d.Mesh,
+	seg model3d.Segment) (model3d.Coord3D, model3d.Coord3D, bool) {
+	tris := m.Find(seg[0], seg[1])
+	if len(tris) != 2 {
+		return model3d.Coord3D{}, model3d.Coord3D{}, false
+	}
+	minAngle := e.MinAngle
+	if minAngle == 0 {
+		minAngle = DefaultEdgeBreakerMinAngle
+	}
+	n1, n2 := tris[0].Normal(), tris[1].Normal()
+	if n1.Dot(n2) > math.Cos(minAngle) {
+		return n1, n2, false
+	}
+
+	// For a convex edge, the second triangle is behind the
+	// plane of the first.
+	other := seg.Other(tris[1])
+	if other.Sub(seg[0]).Dot(n1) >= 0 {
+		return n1, n2, false
+	}
+	return n1, n2, true
+}
+
+// edgeCutter is the region removed from a solid to break
+// a single convex edge.
+type edgeCutter struct {
+	seg    model3d.Segment
+	dir    model3d.Coord3D
+	length float64
+	n1     model3d.Coord3D
+	n2     model3d.Coord3D
+	mid    model3d.Coord3D
+
+	// axis is the offset from the edge to the axis of the
+	// fillet in the plane perpendicular to the edge.
+	axis   model3d.Coord3D
+	radius float64
+	mode   EdgeBreakMode
+
+	min model3d.Coord3D
+	max model3d.Coord3D
+}
+
+func newEdgeCutter(seg model3d.Segment, n1, n2 model3d.Coord3D, radius float64,
+	mode EdgeBreakMode) *edgeCutter {
+	dir := seg[1].Sub(seg[0])
+	length := dir.Norm()
+	dir = dir.Scale(1 / length)
+
+	// The axis of the fillet is a distance of radius from
+	// both planes, i.e. (axis·n1) = (axis·n2) = -radius.
+	axis := n1.Add(n2).Scale(-radius / (1 + n1.Dot(n2)))
+
+	expand := model3d.XYZ(1, 1, 1).Scale(axis.Norm() + radius)
+	return &edgeCutter{
+		seg:    seg,
+		dir:    dir,
+		length: length,
+		n1:     n1,
+		n2:     n2,
+		mid:    n1.Add(n2).Normalize(),
+		axis:   axis,
+		radius: radius,
+		mode:   mode,
+		min:    seg[0].Min(seg[1]).Sub(expand),
+		max:    seg[0].Max(seg[1]).Add(expand),
+	}
+}
+
+func (e *edgeCutter) Min() model3d.Coord3D {
+	return e.min
+}
+
+func (e *edgeCutter) Max() model3d.Coord3D {
+	return e.max
+}
+
+func (e *edgeCutter) Contains(c model3d.Coord3D) bool {
+	if !model3d.InBounds(e, c) {
+		return false
+	}
+	v := c.Sub(e.seg[0])
+	t := v.Dot(e.dir)
+	if t < 0 || t > e.length {
+		return false
+	}
+	rel := v.Sub(e.dir.Scale(t)).Sub(e.axis)
+
+	// Only the region between the two tangent points,
+	// closer to the edge than the axis, is removed.
+	if rel.Dot(e.n1) <= 0 || rel.Dot(e.n2) <= 0 {
+		return false
+	}
+	if e.mode == EdgeChamfer {
+		return rel.Dot(e.mid) > e.radius*e.mid.Dot(e.n1)
+	}
+	return rel.Norm() > e.radius
+}
@@ -0,0 +1,65 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestEdgeBreaker(t *testing.T) {
+	mesh := model3d.NewMeshRect(model3d.XYZ(0, 0, 0), model3d.XYZ(2, 2, 2))
+
+	breaker := &EdgeBreaker{Radius: 0.3, MinAngle: math.Pi * 0.6}
+	if n := len(breaker.FeatureEdges(mesh)); n != 0 {
+		t.Errorf("expected no feature edges but got %d", n)
+	}
+	breaker.MinAngle = 0
+	if n := len(breaker.FeatureEdges(mesh)); n != 12 {
+		t.Errorf("expected 12 feature edges but got %d", n)
+	}
+
+	fillet := breaker.Solid(mesh)
+	breaker.Mode = EdgeChamfer
+	chamfer := breaker.Solid(mesh)
+
+	// Distance along the diagonal of the cross section
+	// from the edge to the surface of the fillet.
+	filletDepth := 0.3 * (math.Sqrt2 - 1)
+	chamferDepth := 0.3 / math.Sqrt2
+
+	edgeDiag := model3d.XYZ(2, 1, 2)
+	inward := model3d.XYZ(-1, 0, -1).Normalize()
+	for _, tc := range []struct {
+		Solid model3d.Solid
+		Depth float64
+	}{{fillet, filletDepth}, {chamfer, chamferDepth}} {
+		if tc.Solid.Contains(edgeDiag.Add(inward.Scale(tc.Depth - 0.01))) {
+			t.Errorf("expected point outside of broken edge (depth %f)", tc.Depth)
+		}
+		if !tc.Solid.Contains(edgeDiag.Add(inward.Scale(tc.Depth + 0.01))) {
+			t.Errorf("expected point inside of broken edge (depth %f)", tc.Depth)
+		}
+		for _, c := range []model3d.Coord3D{
+			model3d.XYZ(1.99, 1, 1.69),
+			model3d.XYZ(1.69, 1, 1.99),
+			model3d.XYZ(1, 1, 1.99),
+			model3d.XYZ(1, 1, 1),
+		} {
+			if !tc.Solid.Contains(c) {
+				t.Errorf("expected point %v to be unaffected", c)
+			}
+		}
+	}
+
+	m := breaker.Mesh(mesh, 0.05)
+	if m.NeedsRepair() {
+		t.Error("mesh needs repair")
+	}
+	// Each edge removes a triangular prism, but the prisms
+	// overlap near the corners.
+	maxRemoved := 12 * 2 * 0.3 * 0.3 / 2
+	if v := m.Volume(); v < 8-maxRemoved-0.05 || v > 8-maxRemoved/2 {
+		t.Errorf("unexpected volume: %f", v)
+	}
+}
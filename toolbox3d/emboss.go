@@ -0,0 +1,114 @@
+package toolbox3d
+
+import (
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// An Emboss wraps an existing solid and raises or
+// recesses a 2D shape, such as text or a logo, on its
+// surface.
+//
+// The 2D shape is projected onto the surface along
+// Direction. By default, the depth of the embossing is
+// also measured along Direction, which works well for
+// surfaces facing against Direction. For curved
+// surfaces, an SDF can be provided so that the depth is
+// measured along the surface normals instead.
+//
+// Text can be created with model2d.TextSolid, and images
+// can be converted to solids with model2d.Bitmap.
+type Emboss struct {
+	model3d.Solid
+
+	// SDF, if non-nil, is the SDF of Solid. When set, the
+	// shape is offset from the surface along its normals.
+	SDF model3d.SDF
+
+	// Shape is the 2D shape to emboss.
+	Shape model2d.Solid
+
+	// Origin is the point in 3D space where the origin of
+	// Shape is projected from.
+	Origin model3d.Coord3D
+
+	// Direction is the direction of the projection, which
+	// points into the surface.
+	Direction model3d.Coord3D
+
+	// Up, if non-zero, is the direction in 3D space of the
+	// 2D Y axis of Shape. It should be perpendicular to
+	// Direction.
+	Up model3d.Coord3D
+
+	// Depth is the distance by which the shape is raised
+	// out of the surface, or recessed into the surface if
+	// negative.
+	Depth float64
+}
+
+func (e *Emboss) Min() model3d.Coord3D {
+	if e.Depth < 0 {
+		return e.Solid.Min()
+	}
+	return e.Solid.Min().Sub(model3d.XYZ(1, 1, 1).Scale(e.Depth))
+}
+
+func (e *Emboss) Max() model3d.Coord3D {
+	if e.Depth < 0 {
+		return e.Solid.Max()
+	}
+	return e.Solid.Max().Add(model3d.XYZ(1, 1, 1).Scale(e.Depth))
+}
+
+func (e *Emboss) Contains(c model3d.Coord3D) bool {
+	if e.Depth < 0 {
+		return e.recessContains(c)
+	}
+	if e.Solid.Contains(c) {
+		return true
+	}
+	if !e.Shape.Contains(e.project(c)) {
+		return false
+	}
+	if e.SDF != nil {
+		return e.SDF.SDF(c) >= -e.Depth
+	}
+	return e.Solid.Contains(c.Add(e.direction().Scale(e.Depth)))
+}
+
+func (e *Emboss) recessContains(c model3d.Coord3D) bool {
+	if !e.Solid.Contains(c) {
+		return false
+	}
+	if !e.Shape.Contains(e.project(c)) {
+		return true
+	}
+	if e.SDF != nil {
+		return e.SDF.SDF(c) > -e.Depth
+	}
+	return e.Solid.Contains(c.Add(e.direction().Scale(e.Depth)))
+}
+
+func (e *Emboss) project(c model3d.Coord3D) model2d.Coord {
+	xAxis, yAxis := e.axes()
+	v := c.Sub(e.Origin)
+	return model2d.XY(v.Dot(xAxis), v.Dot(yAxis))
+}
+
+func (e *Emboss) direction() model3d.Coord3D {
+	return e.Direction.Normalize()
+}
+
+func (e *Emboss) axes() (model3d.Coord3D, model3d.Coord3D) {
+	dir := e.direction()
+	if e.Up.Norm() == 0 {
+		return dir.OrthoBasis()
+	}
+	yAxis := e.Up.ProjectOut(dir).Normalize()
+
+	// Looking along the direction of projection, X should
+	// be to the right of Y so that the shape isn't mirrored.
+	xAxis := dir.Cross(yAxis)
+	return xAxis, yAxis
+}
@@ -0,0 +1,55 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestEmboss(t *testing.T) {
+	box := model3d.NewRect(model3d.XYZ(-5, -5, -3), model3d.XYZ(5, 5, 0))
+
+	// An L shape, to make sure the projection isn't
+	// mirrored or rotated.
+	shape := model2d.JoinedSolid{
+		model2d.NewRect(model2d.XY(0, 0), model2d.XY(1, 3)),
+		model2d.NewRect(model2d.XY(0, 0), model2d.XY(2, 1)),
+	}
+
+	raised := &Emboss{
+		Solid:     box,
+		Shape:     shape,
+		Origin:    model3d.XYZ(1, 1, 0),
+		Direction: model3d.Z(-1),
+		Up:        model3d.Y(1),
+		Depth:     0.5,
+	}
+	recessed := *raised
+	recessed.Depth = -0.5
+	curved := recessed
+	curved.SDF = box
+
+	expected := map[model3d.Coord3D][3]bool{
+		model3d.XYZ(1.5, 3.5, 0.4):    {true, false, false},
+		model3d.XYZ(2.5, 1.5, 0.4):    {true, false, false},
+		model3d.XYZ(1.5, 3.5, -0.4):   {true, false, false},
+		model3d.XYZ(2.5, 1.5, -0.4):   {true, false, false},
+		model3d.XYZ(2.5, 1.5, -0.6):   {true, true, true},
+		model3d.XYZ(2.5, 3.5, 0.4):    {false, false, false},
+		model3d.XYZ(2.5, 3.5, -0.4):   {true, true, true},
+		model3d.XYZ(0.5, 1.5, 0.4):    {false, false, false},
+		model3d.XYZ(1.5, 3.5, 0.6):    {false, false, false},
+		model3d.XYZ(-1.5, -3.5, -0.9): {true, true, true},
+	}
+	for c, exp := range expected {
+		for i, solid := range []model3d.Solid{raised, &recessed, &curved} {
+			if solid.Contains(c) != exp[i] {
+				t.Errorf("solid %d: point %v should have containment %v", i, c, exp[i])
+			}
+		}
+	}
+	if !model3d.BoundsValid(raised) || raised.Max().Z < 0.5 {
+		t.Error("invalid bounds")
+	}
+}
@@ -0,0 +1,304 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+const (
+	DefaultEnclosureWallThickness = 2.0
+	DefaultEnclosureClearance     = 0.2
+)
+
+// An EnclosureLidStyle determines how the lid of an
+// Enclosure fits onto its body.
+type EnclosureLidStyle int
+
+const (
+	// EnclosureLidFlat is a flat plate resting on top of
+	// the walls.
+	EnclosureLidFlat EnclosureLidStyle = iota
+
+	// EnclosureLidInset is a flat plate with a lip that
+	// fits inside the walls, keeping the lid aligned.
+	EnclosureLidInset
+)
+
+// An EnclosureFace is one of the outer faces of an
+// Enclosure.
+type EnclosureFace int
+
+const (
+	EnclosureFront EnclosureFace = iota
+	EnclosureBack
+	EnclosureLeft
+	EnclosureRight
+	EnclosureBottom
+)
+
+// An EnclosurePort is a cutout through a wall of an
+// Enclosure, for connectors, buttons, or displays.
+type EnclosurePort struct {
+	// Face is the face of the enclosure to cut through.
+	Face EnclosureFace
+
+	// Profile is the shape of the cutout.
+	//
+	// For the sides, the profile's coordinates are as
+	// seen from outside of the enclosure, with the origin
+	// at the bottom-left corner of the face and the Y
+	// axis pointing up.
+	// For the bottom, the profile's coordinates are the X
+	// and Y coordinates of the enclosure.
+	Profile model2d.Solid
+}
+
+// An EnclosureStandoff is a post on the floor of an
+// Enclosure for mounting a circuit board.
+type EnclosureStandoff struct {
+	// Position is the X and Y coordinates of the center
+	// of the standoff.
+	Position model2d.Coord
+
+	// Height is the height of the standoff above the
+	// floor.
+	Height float64
+
+	// Diameter is the outer diameter of the standoff.
+	Diameter float64
+
+	// Screw, if non-nil, is the thread of a hole through
+	// the center of the standoff.
+	Screw *ThreadProfile
+}
+
+// An Enclosure is a parametric project box, consisting
+// of an open-topped body and a lid.
+//
+// The enclosure spans from the origin to Size, and the
+// lid is positioned as it is when the box is assembled.
+// All dimensions are typically in millimeters.
+type Enclosure struct {
+	// Size is the outer size of the enclosure, including
+	// the lid.
+	Size model3d.Coord3D
+
+	// WallThickness is the thickness of the walls and
+	// floor.
+	// If 0, DefaultEnclosureWallThickness is used.
+	WallThickness float64
+
+	// LidThickness is the thickness of the lid.
+	// If 0, WallThickness is used.
+	LidThickness float64
+
+	// LidStyle determines how the lid fits on the body.
+	LidStyle EnclosureLidStyle
+
+	// Clearance is the gap between mating parts, such as
+	// the lid's lip and the walls.
+	// If 0, DefaultEnclosureClearance is used.
+	Clearance float64
+
+	// Screw, if non-nil, adds a post in each corner of
+	// the body, so that the lid can be attached with
+	// countersunk screws of this thread.
+	Screw *ThreadProfile
+
+	// Standoffs are posts on the floor of the body.
+	Standoffs []*EnclosureStandoff
+
+	// Ports are cutouts in the walls of the body.
+	Ports []*EnclosurePort
+}
+
+// Body creates the body of the enclosure.
+func (e *Enclosure) Body() model3d.Solid {
+	w := e.wallThickness()
+	bodyHeight := e.bodyHeight()
+
+	positive := model3d.JoinedSolid{
+		&model3d.SubtractedSolid{
+			Positive: model3d.NewRect(model3d.Origin, model3d.XYZ(e.Size.X, e.Size.Y, bodyHeight)),
+			Negative: model3d.NewRect(
+				model3d.XYZ(w, w, w),
+				model3d.XYZ(e.Size.X-w, e.Size.Y-w, bodyHeight+1),
+			),
+		},
+	}
+	var negative model3d.JoinedSolid
+
+	if e.Screw != nil {
+		postRadius := e.postRadius()
+		holeDepth := math.Min(bodyHeight-w, 4*e.Screw.MajorDiameter)
+		for _, c := range e.postCenters() {
+			top := model3d.XYZ(c.X, c.Y, bodyHeight)
+			positive = append(positive, &model3d.Cylinder{
+				P1:     model3d.XYZ(c.X, c.Y, 0),
+				P2:     top,
+				Radius: postRadius,
+			})
+			negative = append(negative, e.Screw.Hole(top, top.Sub(model3d.Z(holeDepth)), 0))
+		}
+	}
+
+	for _, s := range e.Standoffs {
+		p1 := model3d.XYZ(s.Position.X, s.Position.Y, w/2)
+		p2 := model3d.XYZ(s.Position.X, s.Position.Y, w+s.Height)
+		positive = append(positive, &model3d.Cylinder{P1: p1, P2: p2, Radius: s.Diameter / 2})
+		if s.Screw != nil {
+			negative = append(negative, s.Screw.Hole(p2, model3d.XYZ(p2.X, p2.Y, w), 0))
+		}
+	}
+
+	for _, p := range e.Ports {
+		negative = append(negative, &enclosurePortSolid{Enclosure: e, Port: p})
+	}
+
+	if len(negative) == 0 {
+		return positive
+	}
+	return &model3d.SubtractedSolid{
+		Positive: positive,
+		Negative: negative,
+	}
+}
+
+// Lid creates the lid of the enclosure.
+func (e *Enclosure) Lid() model3d.Solid {
+	w := e.wallThickness()
+	c := e.clearance()
+	bodyHeight := e.bodyHeight()
+
+	positive := model3d.JoinedSolid{
+		model3d.NewRect(model3d.XYZ(0, 0, bodyHeight), e.Size),
+	}
+	var negative model3d.JoinedSolid
+
+	bottom := bodyHeight
+	if e.LidStyle == EnclosureLidInset {
+		bottom = bodyHeight - 2*w
+		lipMin := model3d.XYZ(w+c, w+c, bottom)
+		lipMax := model3d.XYZ(e.Size.X-(w+c), e.Size.Y-(w+c), bodyHeight)
+		lip := &model3d.SubtractedSolid{
+			Positive: model3d.NewRect(lipMin, lipMax),
+			Negative: model3d.NewRect(
+				lipMin.Add(model3d.XYZ(w, w, -1)),
+				lipMax.Sub(model3d.XYZ(w, w, 0)),
+			),
+		}
+		positive = append(positive, lip)
+		if e.Screw != nil {
+			// Make room for the screw posts.
+			for _, center := range e.postCenters() {
+				negative = append(negative, &model3d.Cylinder{
+					P1:     model3d.XYZ(center.X, center.Y, bottom-1),
+					P2:     model3d.XYZ(center.X, center.Y, bodyHeight),
+					Radius: e.postRadius() + c,
+				})
+			}
+		}
+	}
+
+	if e.Screw != nil {
+		for _, center := range e.postCenters() {
+			negative = append(negative, e.Screw.CountersinkHole(
+				model3d.XYZ(center.X, center.Y, e.Size.Z),
+				model3d.XYZ(center.X, center.Y, bottom-1),
+				c,
+			))
+		}
+	}
+
+	if len(negative) == 0 {
+		return positive
+	}
+	return &model3d.SubtractedSolid{
+		Positive: positive,
+		Negative: negative,
+	}
+}
+
+// Meshes creates meshes for the body and lid using
+// marching cubes with the given grid spacing.
+func (e *Enclosure) Meshes(delta float64) (body, lid *model3d.Mesh) {
+	body = model3d.MarchingCubesSearch(e.Body(), delta, 8)
+	lid = model3d.MarchingCubesSearch(e.Lid(), delta, 8)
+	return
+}
+
+func (e *Enclosure) wallThickness() float64 {
+	if e.WallThickness == 0 {
+		return DefaultEnclosureWallThickness
+	}
+	return e.WallThickness
+}
+
+func (e *Enclosure) lidThickness() float64 {
+	if e.LidThickness == 0 {
+		return e.wallThickness()
+	}
+	return e.LidThickness
+}
+
+func (e *Enclosure) clearance() float64 {
+	if e.Clearance == 0 {
+		return DefaultEnclosureClearance
+	}
+	return e.Clearance
+}
+
+func (e *Enclosure) bodyHeight() float64 {
+	return e.Size.Z - e.lidThickness()
+}
+
+func (e *Enclosure) postRadius() float64 {
+	return e.Screw.MajorDiameter
+}
+
+func (e *Enclosure) postCenters() []model2d.Coord {
+	inset := e.wallThickness() + e.postRadius()
+	return []model2d.Coord{
+		model2d.XY(inset, inset),
+		model2d.XY(e.Size.X-inset, inset),
+		model2d.XY(inset, e.Size.Y-inset),
+		model2d.XY(e.Size.X-inset, e.Size.Y-inset),
+	}
+}
+
+// enclosurePortSolid cuts a port through a wall, and
+// anything within one wall thickness inside of it.
+type enclosurePortSolid struct {
+	Enclosure *Enclosure
+	Port      *EnclosurePort
+}
+
+func (e *enclosurePortSolid) Min() model3d.Coord3D {
+	return model3d.XYZ(-1, -1, -1).Scale(e.Enclosure.wallThickness())
+}
+
+func (e *enclosurePortSolid) Max() model3d.Coord3D {
+	return e.Enclosure.Size.Add(model3d.XYZ(1, 1, 1).Scale(e.Enclosure.wallThickness()))
+}
+
+func (e *enclosurePortSolid) Contains(c model3d.Coord3D) bool {
+	size := e.Enclosure.Size
+	var p model2d.Coord
+	var depth float64
+	switch e.Port.Face {
+	case EnclosureFront:
+		p, depth = model2d.XY(c.X, c.Z), c.Y
+	case EnclosureBack:
+		p, depth = model2d.XY(size.X-c.X, c.Z), size.Y-c.Y
+	case EnclosureLeft:
+		p, depth = model2d.XY(size.Y-c.Y, c.Z), c.X
+	case EnclosureRight:
+		p, depth = model2d.XY(c.Y, c.Z), size.X-c.X
+	case EnclosureBottom:
+		p, depth = model2d.XY(c.X, c.Y), c.Z
+	}
+	w := e.Enclosure.wallThickness()
+	return depth >= -w && depth <= 2*w && e.Port.Profile.Contains(p)
+}
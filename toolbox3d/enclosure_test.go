@@ -0,0 +1,72 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestEnclosure(t *testing.T) {
+	for _, style := range []EnclosureLidStyle{EnclosureLidFlat, EnclosureLidInset} {
+		enclosure := &Enclosure{
+			Size:     model3d.XYZ(60, 40, 30),
+			LidStyle: style,
+			Screw:    ISOMetricThreads["M3"],
+			Standoffs: []*EnclosureStandoff{
+				{Position: model2d.XY(30, 20), Height: 5, Diameter: 6, Screw: ISOMetricThreads["M2.5"]},
+			},
+			Ports: []*EnclosurePort{
+				{
+					Face:    EnclosureFront,
+					Profile: model2d.NewRect(model2d.XY(10, 10), model2d.XY(20, 15)),
+				},
+				{
+					Face:    EnclosureRight,
+					Profile: &model2d.Circle{Center: model2d.XY(20, 15), Radius: 4},
+				},
+			},
+		}
+		body, lid := enclosure.Body(), enclosure.Lid()
+
+		for i := 0; i < 100000; i++ {
+			c := model3d.NewCoord3DRandBounds(model3d.Origin, enclosure.Size)
+			if body.Contains(c) && lid.Contains(c) {
+				t.Fatalf("style %d: body and lid overlap at %v", style, c)
+			}
+		}
+
+		expected := map[model3d.Coord3D][2]bool{
+			// Walls, floor and lid.
+			model3d.XYZ(1, 20, 10):  {true, false},
+			model3d.XYZ(30, 20, 1):  {true, false},
+			model3d.XYZ(30, 10, 29): {false, true},
+			model3d.XYZ(30, 10, 15): {false, false},
+			// Ports.
+			model3d.XYZ(15, 1, 12):  {false, false},
+			model3d.XYZ(15, 1, 16):  {true, false},
+			model3d.XYZ(59, 20, 15): {false, false},
+			model3d.XYZ(59, 25, 15): {true, false},
+			// Screw posts and their holes.
+			model3d.XYZ(3.2, 5, 20):   {true, false},
+			model3d.XYZ(5, 5, 20):     {false, false},
+			model3d.XYZ(5, 5, 29):     {false, false},
+			model3d.XYZ(5, 9.5, 29.5): {false, true},
+			// Standoff.
+			model3d.XYZ(32.5, 20, 6): {true, false},
+			model3d.XYZ(30, 20, 6):   {false, false},
+			model3d.XYZ(30, 20, 1):   {true, false},
+		}
+		for c, exp := range expected {
+			if body.Contains(c) != exp[0] || lid.Contains(c) != exp[1] {
+				t.Errorf("style %d: point %v should have containment %v", style, c, exp)
+			}
+		}
+
+		if style == EnclosureLidInset {
+			if !lid.Contains(model3d.XYZ(30, 2.5, 27)) {
+				t.Error("expected lip inside walls")
+			}
+		}
+	}
+}
@@ -7,10 +7,47 @@ import (
 	"github.com/unixpickle/model3d/model3d"
 )
 
+// DefaultGearPressureAngle is the standard pressure angle
+// of 20 degrees, in radians.
+const DefaultGearPressureAngle = 20 * math.Pi / 180
+
+// A SpurGear is a model3d.Solid for a gear with straight
+// teeth, extruded from P1 to P2.
+//
+// The cross section is either given explicitly by Profile,
+// or is a standard involute profile determined by Module,
+// Teeth, and PressureAngle.
 type SpurGear struct {
-	P1      model3d.Coord3D
-	P2      model3d.Coord3D
+	// P1 is the center of the bottom face of the gear.
+	P1 model3d.Coord3D
+
+	// P2 is the center of the top face of the gear.
+	P2 model3d.Coord3D
+
+	// Profile is the cross section of the gear.
+	// If nil, a StandardGearProfile is used.
 	Profile GearProfile
+
+	// Module is the pitch diameter divided by the number
+	// of teeth. Gears must have the same module to mesh.
+	Module float64
+
+	// Teeth is the number of teeth on the gear.
+	Teeth int
+
+	// PressureAngle is the angle, in radians, between the
+	// line of action and the tangent to the pitch circle.
+	// If 0, DefaultGearPressureAngle is used.
+	PressureAngle float64
+
+	// Thickness, if non-zero, is used instead of P2, so
+	// that the gear extends Thickness along the z-axis
+	// from P1.
+	Thickness float64
+
+	// BoreRadius, if non-zero, is the radius of a hole
+	// through the center of the gear for an axle.
+	BoreRadius float64
 }
 
 func (s *SpurGear) Min() model3d.Coord3D {
@@ -25,26 +62,111 @@ func (s *SpurGear) Contains(c model3d.Coord3D) bool {
 	if !model3d.InBounds(s, c) {
 		return false
 	}
-	v1, v2 := s.P2.Sub(s.P1).OrthoBasis()
-	return s.Profile.Contains(model2d.Coord{
-		X: v1.Dot(c),
-		Y: v2.Dot(c),
-	})
+	c2, _, ok := gearCoords(s.P1, s.p2(), c)
+	if !ok || c2.Norm() < s.BoreRadius {
+		return false
+	}
+	return s.profile().Contains(c2)
 }
 
 func (s *SpurGear) boundingCylinder() *model3d.CylinderSolid {
 	return &model3d.CylinderSolid{
 		P1:     s.P1,
-		P2:     s.P2,
-		Radius: s.Profile.Max().X,
+		P2:     s.p2(),
+		Radius: s.profile().Max().X,
+	}
+}
+
+func (s *SpurGear) p2() model3d.Coord3D {
+	if s.Thickness != 0 {
+		return s.P1.Add(model3d.Z(s.Thickness))
+	}
+	return s.P2
+}
+
+func (s *SpurGear) profile() GearProfile {
+	if s.Profile != nil {
+		return s.Profile
+	}
+	pressureAngle := s.PressureAngle
+	if pressureAngle == 0 {
+		pressureAngle = DefaultGearPressureAngle
+	}
+	return StandardGearProfile(pressureAngle, s.Module, s.Teeth)
+}
+
+// gearCoords projects c onto the plane of a gear's cross
+// section, also returning the distance along the gear's
+// axis.
+//
+// If c is past either end of the gear, false is returned.
+func gearCoords(p1, p2, c model3d.Coord3D) (model2d.Coord, float64, bool) {
+	axis := p2.Sub(p1)
+	height := axis.Norm()
+	diff := c.Sub(p1)
+	z := axis.Dot(diff) / height
+	if z < 0 || z > height {
+		return model2d.Coord{}, 0, false
 	}
+	v1, v2 := axis.OrthoBasis()
+	return model2d.XY(v1.Dot(diff), v2.Dot(diff)), z, true
 }
 
+// A HelicalGear is a model3d.Solid for a gear whose teeth
+// twist along the axis of the gear, extruded from P1 to P2.
+//
+// Helical gears run more quietly than spur gears, since
+// teeth engage gradually. Two helical gears on parallel
+// axes mesh when their helix angles are equal and
+// opposite.
+//
+// Like a SpurGear, the cross section is either given by
+// Profile, or is a standard involute profile determined by
+// the other fields.
 type HelicalGear struct {
-	P1      model3d.Coord3D
-	P2      model3d.Coord3D
+	// P1 is the center of the bottom face of the gear.
+	P1 model3d.Coord3D
+
+	// P2 is the center of the top face of the gear.
+	P2 model3d.Coord3D
+
+	// Profile is the cross section of the gear, which is
+	// rotated as it is extruded.
+	// If nil, a StandardGearProfile is used, with the
+	// transverse module and pressure angle corresponding
+	// to Module and PressureAngle.
 	Profile GearProfile
-	Angle   float64
+
+	// Angle is the helix angle in radians, measured at the
+	// pitch radius.
+	Angle float64
+
+	// Module is the normal module of the teeth, measured
+	// perpendicular to the teeth. Gears with the same
+	// normal module and opposite helix angles mesh.
+	Module float64
+
+	// Teeth is the number of teeth on the gear.
+	Teeth int
+
+	// PressureAngle is the normal pressure angle in
+	// radians.
+	// If 0, DefaultGearPressureAngle is used.
+	PressureAngle float64
+
+	// Thickness, if non-zero, is used instead of P2, so
+	// that the gear extends Thickness along the z-axis
+	// from P1.
+	Thickness float64
+
+	// BoreRadius, if non-zero, is the radius of a hole
+	// through the center of the gear for an axle.
+	BoreRadius float64
+
+	// Herringbone, if true, reverses the direction of the
+	// twist halfway up the gear, creating a double helical
+	// gear with no net axial thrust.
+	Herringbone bool
 }
 
 func (h *HelicalGear) Min() model3d.Coord3D {
@@ -59,29 +181,53 @@ func (h *HelicalGear) Contains(c model3d.Coord3D) bool {
 	if !model3d.InBounds(h, c) {
 		return false
 	}
-	axis := h.P2.Sub(h.P1)
-	v1, v2 := axis.OrthoBasis()
-	c2 := model2d.Coord{
-		X: v1.Dot(c),
-		Y: v2.Dot(c),
+	p2 := h.p2()
+	c2, z, ok := gearCoords(h.P1, p2, c)
+	if !ok || c2.Norm() < h.BoreRadius {
+		return false
+	}
+	if h.Herringbone {
+		z = math.Min(z, p2.Dist(h.P1)-z)
 	}
 
-	distUp := axis.Normalize().Dot(c.Sub(h.P1))
-	theta := math.Tan(h.Angle) * distUp / h.Profile.PitchRadius()
-
+	// A helix with the given angle at the pitch radius
+	// rotates by tan(angle)/radius per unit of height.
+	profile := h.profile()
+	theta := math.Tan(h.Angle) * z / profile.PitchRadius()
 	c2 = model2d.NewMatrix2Rotation(theta).MulColumn(c2)
 
-	return h.Profile.Contains(c2)
+	return profile.Contains(c2)
 }
 
 func (h *HelicalGear) boundingCylinder() *model3d.CylinderSolid {
 	return &model3d.CylinderSolid{
 		P1:     h.P1,
-		P2:     h.P2,
-		Radius: h.Profile.Max().X,
+		P2:     h.p2(),
+		Radius: h.profile().Max().X,
 	}
 }
 
+func (h *HelicalGear) p2() model3d.Coord3D {
+	if h.Thickness != 0 {
+		return h.P1.Add(model3d.Z(h.Thickness))
+	}
+	return h.P2
+}
+
+func (h *HelicalGear) profile() GearProfile {
+	if h.Profile != nil {
+		return h.Profile
+	}
+	pressureAngle := h.PressureAngle
+	if pressureAngle == 0 {
+		pressureAngle = DefaultGearPressureAngle
+	}
+	cos := math.Cos(h.Angle)
+	transverseAngle := math.Atan(math.Tan(pressureAngle) / cos)
+	return InvoluteGearProfileSizes(transverseAngle, h.Module/cos, h.Module,
+		1.25*h.Module, h.Teeth)
+}
+
 type GearProfile interface {
 	model2d.Solid
 	PitchRadius() float64
@@ -123,13 +269,22 @@ func InvoluteGearProfile(pressureAngle, module, clearance float64, numTeeth int)
 // InvoluteGearProfile.
 func InvoluteGearProfileSizes(pressureAngle, module, addendum, dedendum float64,
 	numTeeth int) GearProfile {
-	radius := module * float64(numTeeth) / 2
+	return newInvoluteGearProfile(pressureAngle, module, addendum, dedendum,
+		float64(numTeeth))
+}
+
+// newInvoluteGearProfile is like InvoluteGearProfileSizes,
+// but allows a fractional number of teeth for the virtual
+// gears used to shape bevel gear teeth.
+func newInvoluteGearProfile(pressureAngle, module, addendum, dedendum,
+	numTeeth float64) *involuteGearProfile {
+	radius := module * numTeeth / 2
 	baseRadius := math.Cos(pressureAngle) * radius
 
 	tForR := math.Sqrt(math.Pow(radius/baseRadius, 2) - 1)
 	x, y := involuteCoords(tForR)
 
-	toothTheta := math.Pi * 2 / float64(numTeeth)
+	toothTheta := math.Pi * 2 / numTeeth
 	reflectTheta := toothTheta/2 + 2*math.Atan2(y, x)
 
 	return &involuteGearProfile{
@@ -142,6 +297,27 @@ func InvoluteGearProfileSizes(pressureAngle, module, addendum, dedendum float64,
 	}
 }
 
+// StandardGearProfile creates an involute GearProfile
+// with the standard addendum of one module and dedendum of
+// 1.25 modules, leaving clearance between meshing gears.
+func StandardGearProfile(pressureAngle, module float64, numTeeth int) GearProfile {
+	return InvoluteGearProfileSizes(pressureAngle, module, module, 1.25*module, numTeeth)
+}
+
+// GearProfileMesh creates a closed mesh of the outline of
+// a gear profile, with outward-facing normals.
+//
+// For involute profiles, the mesh traces the exact shape
+// of the teeth, flattening the curves within the given
+// tolerance. Other profiles are approximated with
+// marching squares.
+func GearProfileMesh(p GearProfile, tolerance float64) *model2d.Mesh {
+	if i, ok := p.(*involuteGearProfile); ok {
+		return i.mesh(tolerance)
+	}
+	return model2d.MarchingSquaresSearch(p, tolerance, 8)
+}
+
 func (i *involuteGearProfile) PitchRadius() float64 {
 	return i.pitchRadius
 }
@@ -190,3 +366,108 @@ func (i *involuteGearProfile) Contains(c model2d.Coord) bool {
 func involuteCoords(t float64) (float64, float64) {
 	return math.Cos(t) + t*math.Sin(t), math.Sin(t) - t*math.Cos(t)
 }
+
+func (i *involuteGearProfile) mesh(tolerance float64) *model2d.Mesh {
+	// The involute of the base circle at parameter t is at
+	// an angle of t-atan(t) from the start of the curve.
+	involuteAngle := func(t float64) float64 {
+		return t - math.Atan(t)
+	}
+	involuteT := func(r float64) float64 {
+		return math.Sqrt(math.Max(0, math.Pow(r/i.baseRadius, 2)-1))
+	}
+
+	startT := involuteT(math.Max(i.rootRadius, i.baseRadius))
+	tipT := involuteT(i.outerRadius)
+	pointed := involuteAngle(tipT) > i.reflectTheta/2
+	if pointed {
+		// The flanks meet before the outer radius.
+		minT, maxT := startT, tipT
+		for iter := 0; iter < 64; iter++ {
+			tipT = (minT + maxT) / 2
+			if involuteAngle(tipT) > i.reflectTheta/2 {
+				maxT = tipT
+			} else {
+				minT = tipT
+			}
+		}
+	}
+
+	var points []model2d.Coord
+	addPoints := func(ps []model2d.Coord) {
+		for _, p := range ps {
+			if len(points) == 0 || p.Dist(points[len(points)-1]) > tolerance*1e-3 {
+				points = append(points, p)
+			}
+		}
+	}
+	polar := func(r, theta float64) model2d.Coord {
+		return model2d.XY(math.Cos(theta), math.Sin(theta)).Scale(r)
+	}
+	numTeeth := int(math.Round(2 * math.Pi / i.toothTheta))
+	for tooth := 0; tooth < numTeeth; tooth++ {
+		offset := float64(tooth) * i.toothTheta
+		if i.rootRadius < i.baseRadius {
+			addPoints([]model2d.Coord{polar(i.rootRadius, offset)})
+		}
+		addPoints(model2d.CurveFlatten(&involuteFlank{
+			BaseRadius: i.baseRadius,
+			StartT:     startT,
+			EndT:       tipT,
+			Offset:     offset,
+		}, tolerance, 3, 0))
+		if !pointed {
+			arc := &model2d.Arc{
+				Radius:     i.outerRadius,
+				StartAngle: offset + involuteAngle(tipT),
+				EndAngle:   offset + i.reflectTheta - involuteAngle(tipT),
+			}
+			addPoints(arc.Flatten(tolerance))
+		}
+		addPoints(model2d.CurveFlatten(&involuteFlank{
+			BaseRadius: i.baseRadius,
+			StartT:     tipT,
+			EndT:       startT,
+			Offset:     offset + i.reflectTheta,
+			Mirror:     true,
+		}, tolerance, 3, 0))
+		if i.rootRadius < i.baseRadius {
+			addPoints([]model2d.Coord{polar(i.rootRadius, offset+i.reflectTheta)})
+		}
+		arc := &model2d.Arc{
+			Radius:     i.rootRadius,
+			StartAngle: offset + i.reflectTheta - involuteAngle(startT),
+			EndAngle:   offset + i.toothTheta + involuteAngle(startT),
+		}
+		addPoints(arc.Flatten(tolerance))
+	}
+	for len(points) > 1 && points[0].Dist(points[len(points)-1]) <= tolerance*1e-3 {
+		points = points[:len(points)-1]
+	}
+
+	// Points were generated counter-clockwise.
+	for j := 0; j < len(points)/2; j++ {
+		points[j], points[len(points)-1-j] = points[len(points)-1-j], points[j]
+	}
+	return model2d.PolylineMesh(points, true)
+}
+
+// involuteFlank is a model2d.Curve tracing the involute
+// of a base circle from parameter StartT to EndT, rotated
+// by Offset and optionally mirrored across the x-axis.
+type involuteFlank struct {
+	BaseRadius float64
+	StartT     float64
+	EndT       float64
+	Offset     float64
+	Mirror     bool
+}
+
+func (i *involuteFlank) Eval(t float64) model2d.Coord {
+	x, y := involuteCoords(i.StartT + t*(i.EndT-i.StartT))
+	if i.Mirror {
+		y = -y
+	}
+	c := model2d.XY(x, y).Scale(i.BaseRadius)
+	return model2d.NewMatrix2Rotation(i.Offset).MulColumn(c)
+}
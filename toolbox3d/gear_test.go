@@ -0,0 +1,114 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestGearProfileMesh(t *testing.T) {
+	profiles := map[string]GearProfile{
+		"Standard": StandardGearProfile(DefaultGearPressureAngle, 2, 17),
+		"Small":    StandardGearProfile(DefaultGearPressureAngle, 1, 8),
+		"Legacy":   InvoluteGearProfile(DefaultGearPressureAngle, 1, 0.1, 30),
+		"Pointed":  InvoluteGearProfileSizes(DefaultGearPressureAngle, 1, 2, 1, 12),
+	}
+	for name, profile := range profiles {
+		t.Run(name, func(t *testing.T) {
+			mesh := GearProfileMesh(profile, 1e-3)
+			if !mesh.Manifold() {
+				t.Fatal("mesh is not manifold")
+			}
+			if _, n := mesh.RepairNormals(1e-8); n != 0 {
+				t.Fatalf("mesh has %d incorrect normals", n)
+			}
+			sdf := model2d.MeshToSDF(mesh)
+			for i := 0; i < 10000; i++ {
+				c := model2d.NewCoordRandBounds(profile.Min(), profile.Max())
+				dist := sdf.SDF(c)
+				if math.Abs(dist) < 1e-2 {
+					continue
+				}
+				if (dist > 0) != profile.Contains(c) {
+					t.Fatalf("containment mismatch at %v (sdf %f)", c, dist)
+				}
+			}
+		})
+	}
+}
+
+func TestSpurGear(t *testing.T) {
+	gear := &SpurGear{
+		P1:         model3d.XYZ(1, 2, 3),
+		Module:     2,
+		Teeth:      15,
+		Thickness:  5,
+		BoreRadius: 3,
+	}
+	pitchRadius := 15.0
+	if max := gear.Max(); math.Abs(max.X-(1+pitchRadius+2)) > 1e-5 ||
+		math.Abs(max.Z-8) > 1e-5 {
+		t.Errorf("unexpected max: %v", max)
+	}
+	if gear.Contains(model3d.XYZ(1, 2, 5)) {
+		t.Error("bore should be empty")
+	}
+	if !gear.Contains(model3d.XYZ(1, 2+5, 5)) {
+		t.Error("gear body should be solid")
+	}
+	if gear.Contains(model3d.XYZ(1, 2+5, 9)) {
+		t.Error("gear should end at its thickness")
+	}
+
+	// Count teeth around the pitch circle.
+	var transitions int
+	prev := gear.Contains(model3d.XYZ(1+pitchRadius, 2, 5))
+	for i := 1; i <= 1000; i++ {
+		theta := float64(i) / 1000 * 2 * math.Pi
+		c := model3d.XYZ(1+pitchRadius*math.Cos(theta), 2+pitchRadius*math.Sin(theta), 5)
+		cur := gear.Contains(c)
+		if cur != prev {
+			transitions++
+		}
+		prev = cur
+	}
+	if transitions != 2*gear.Teeth {
+		t.Errorf("expected %d transitions but got %d", 2*gear.Teeth, transitions)
+	}
+}
+
+func TestHelicalGear(t *testing.T) {
+	gear := &HelicalGear{
+		Angle:     math.Pi / 6,
+		Module:    1,
+		Teeth:     20,
+		Thickness: 10,
+	}
+	pitchRadius := 10 / math.Cos(math.Pi/6)
+	if r := gear.profile().PitchRadius(); math.Abs(r-pitchRadius) > 1e-8 {
+		t.Errorf("expected pitch radius %f but got %f", pitchRadius, r)
+	}
+
+	// Each slice should be a rotated copy of the profile.
+	profile := gear.profile()
+	for i := 0; i < 1000; i++ {
+		c := model3d.NewCoord3DRandBounds(gear.Min(), gear.Max())
+		c2, z, _ := gearCoords(gear.P1, gear.p2(), c)
+		theta := math.Tan(gear.Angle) * z / pitchRadius
+		c2 = model2d.NewMatrix2Rotation(theta).MulColumn(c2)
+		if gear.Contains(c) != profile.Contains(c2) {
+			t.Fatalf("unexpected containment at %v", c)
+		}
+	}
+
+	gear.Herringbone = true
+	for i := 0; i < 1000; i++ {
+		c := model3d.NewCoord3DRandBounds(gear.Min(), gear.Max())
+		mirrored := model3d.XYZ(c.X, c.Y, 10-c.Z)
+		if gear.Contains(c) != gear.Contains(mirrored) {
+			t.Fatalf("herringbone gear should be symmetric at %v", c)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// An InfillPattern determines the structure of an Infill.
+type InfillPattern int
+
+const (
+	// InfillGyroid creates walls along a gyroid, a triply
+	// periodic minimal surface which is strong in every
+	// direction.
+	InfillGyroid InfillPattern = iota
+
+	// InfillSchwarzP creates walls along a Schwarz P
+	// surface, which has large spherical chambers
+	// connected along each axis.
+	InfillSchwarzP
+
+	// InfillDiamond creates walls along a Schwarz D
+	// (diamond) surface.
+	InfillDiamond
+
+	// InfillCubic creates a grid of cylindrical struts
+	// along the edges of cubic cells.
+	InfillCubic
+)
+
+// An Infill wraps an existing solid and replaces its
+// interior with a periodic lattice, such as a gyroid.
+//
+// This is useful for making 3D printed objects lighter
+// while keeping them strong. Typically, the Infill is
+// joined with a hollow shell of the original solid.
+//
+// For the surface patterns (gyroid, Schwarz P, and
+// diamond), the wall thickness is computed with a first
+// order approximation of the distance to the surface, so
+// it is only approximately uniform.
+type Infill struct {
+	model3d.Solid
+
+	// Origin is a point the pattern is aligned to.
+	Origin model3d.Coord3D
+
+	// Pattern is the structure of the lattice.
+	Pattern InfillPattern
+
+	// CellSize is the period of the lattice along each
+	// axis.
+	CellSize float64
+
+	// WallThickness is the thickness of the walls of the
+	// surface patterns, or the diameter of the struts of
+	// the cubic pattern.
+	WallThickness float64
+}
+
+func (i *Infill) Contains(c model3d.Coord3D) bool {
+	if !i.Solid.Contains(c) {
+		return false
+	}
+	p := c.Sub(i.Origin)
+	if i.Pattern == InfillCubic {
+		return i.cubicStrut(p)
+	}
+
+	// Scale the pattern so that each function has a period
+	// of CellSize along each axis.
+	scale := 2 * math.Pi / i.CellSize
+	value, grad := i.surfaceFunc(p.Scale(scale))
+	gradNorm := grad.Norm() * scale
+	if gradNorm == 0 {
+		return value == 0
+	}
+	return math.Abs(value)/gradNorm < i.WallThickness/2
+}
+
+// surfaceFunc evaluates the implicit function of a surface
+// pattern and its gradient, with a period of 2*pi.
+func (i *Infill) surfaceFunc(p model3d.Coord3D) (float64, model3d.Coord3D) {
+	sx, cx := math.Sincos(p.X)
+	sy, cy := math.Sincos(p.Y)
+	sz, cz := math.Sincos(p.Z)
+	switch i.Pattern {
+	case InfillGyroid:
+		value := sx*cy + sy*cz + sz*cx
+		grad := model3d.XYZ(
+			cx*cy-sz*sx,
+			cy*cz-sx*sy,
+			cz*cx-sy*sz,
+		)
+		return value, grad
+	case InfillSchwarzP:
+		return cx + cy + cz, model3d.XYZ(-sx, -sy, -sz)
+	case InfillDiamond:
+		value := sx*sy*sz + sx*cy*cz + cx*sy*cz + cx*cy*sz
+		grad := model3d.XYZ(
+			cx*sy*sz+cx*cy*cz-sx*sy*cz-sx*cy*sz,
+			sx*cy*sz-sx*sy*cz+cx*cy*cz-cx*sy*sz,
+			sx*sy*cz-sx*cy*sz-cx*sy*sz+cx*cy*cz,
+		)
+		return value, grad
+	default:
+		panic("unknown infill pattern")
+	}
+}
+
+func (i *Infill) cubicStrut(p model3d.Coord3D) bool {
+	// Struts run along every axis through every lattice
+	// point, so we check the distance to the nearest strut
+	// parallel to each axis.
+	s := i.CellSize
+	r := i.WallThickness / 2
+	offset := func(x float64) float64 {
+		return x - math.Round(x/s)*s
+	}
+	dx, dy, dz := offset(p.X), offset(p.Y), offset(p.Z)
+	return dy*dy+dz*dz < r*r || dx*dx+dz*dz < r*r || dx*dx+dy*dy < r*r
+}
@@ -0,0 +1,73 @@
+package toolbox3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestInfill(t *testing.T) {
+	solid := model3d.NewRect(model3d.XYZ(-10, -10, -10), model3d.XYZ(10, 10, 10))
+
+	// For thin walls, the fraction of the volume which is
+	// kept is the thickness times the area per unit volume
+	// of the surface.
+	const cellSize = 2.0
+	const thickness = 0.05
+	expectedFractions := map[InfillPattern]float64{
+		InfillGyroid:   3.0915 / cellSize * thickness,
+		InfillSchwarzP: 2.3451 / cellSize * thickness,
+		InfillDiamond:  3.8377 / cellSize * thickness,
+		InfillCubic:    3 * math.Pi * thickness * thickness / 4 / (cellSize * cellSize),
+	}
+	onSurface := map[InfillPattern]model3d.Coord3D{
+		InfillGyroid:   model3d.XYZ(0, 0, 0),
+		InfillSchwarzP: model3d.XYZ(0.5, 0.5, 0.5),
+		InfillDiamond:  model3d.XYZ(0, 0, 0),
+		InfillCubic:    model3d.XYZ(2, 0.01, 4),
+	}
+	offSurface := map[InfillPattern]model3d.Coord3D{
+		InfillGyroid:   model3d.XYZ(0.5, 0, 0),
+		InfillSchwarzP: model3d.XYZ(0, 0, 0),
+		InfillDiamond:  model3d.XYZ(0.5, 0.5, 0.5),
+		InfillCubic:    model3d.XYZ(1, 1, 1),
+	}
+	for pattern, expected := range expectedFractions {
+		infill := &Infill{
+			Solid:         solid,
+			Pattern:       pattern,
+			CellSize:      cellSize,
+			WallThickness: thickness,
+		}
+
+		// The cubic lattice keeps a small fraction of the
+		// volume, so many samples are needed for a stable
+		// estimate.
+		var count int
+		const n = 1000000
+		gen := rand.New(rand.NewSource(int64(pattern)))
+		size := solid.Max().Sub(solid.Min())
+		for i := 0; i < n; i++ {
+			p := model3d.XYZ(gen.Float64(), gen.Float64(), gen.Float64())
+			if infill.Contains(solid.Min().Add(p.Mul(size))) {
+				count++
+			}
+		}
+		actual := float64(count) / n
+		if math.Abs(actual-expected) > 0.1*expected {
+			t.Errorf("pattern %d: expected fraction %f but got %f", pattern, expected, actual)
+		}
+
+		if !infill.Contains(onSurface[pattern]) {
+			t.Errorf("pattern %d: expected %v to be contained", pattern, onSurface[pattern])
+		}
+		if infill.Contains(offSurface[pattern]) {
+			t.Errorf("pattern %d: expected %v not to be contained", pattern, offSurface[pattern])
+		}
+		if infill.Contains(model3d.XYZ(0, 0, 10.1)) {
+			t.Errorf("pattern %d: point outside of solid is contained", pattern)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+const (
+	DefaultInsertBossWallThickness = 2.0
+	DefaultInsertBossChamfer       = 0.5
+	DefaultInsertPocketExtraDepth  = 1.0
+)
+
+// A HeatSetInsert describes the recommended pocket for a
+// threaded heat-set insert.
+//
+// All dimensions are in millimeters.
+type HeatSetInsert struct {
+	// HoleDiameter is the diameter of the pocket that the
+	// insert is melted into.
+	HoleDiameter float64
+
+	// Length is the length of the insert.
+	Length float64
+}
+
+// HeatSetInserts maps metric thread names like "M3" to
+// common sizes of heat-set inserts.
+var HeatSetInserts = map[string]*HeatSetInsert{
+	"M2":   {HoleDiameter: 3.2, Length: 4},
+	"M2.5": {HoleDiameter: 3.6, Length: 4},
+	"M3":   {HoleDiameter: 4, Length: 5.7},
+	"M4":   {HoleDiameter: 5.6, Length: 8.1},
+	"M5":   {HoleDiameter: 6.4, Length: 9.5},
+	"M6":   {HoleDiameter: 8, Length: 12.7},
+}
+
+// An InsertBoss describes a cylindrical boss with a
+// pocket for a heat-set insert, optionally braced by
+// triangular gussets around its base.
+type InsertBoss struct {
+	// Base is the center of the bottom of the boss, which
+	// is typically on or inside of a wall.
+	Base model3d.Coord3D
+
+	// Top is the center of the top of the boss, where the
+	// insert is pushed in.
+	Top model3d.Coord3D
+
+	// Insert is the insert that the pocket is made for.
+	Insert *HeatSetInsert
+
+	// WallThickness is the thickness of the boss around
+	// the pocket.
+	// If 0, DefaultInsertBossWallThickness is used.
+	WallThickness float64
+
+	// Chamfer is the depth of the 45 degree chamfer at the
+	// entry of the pocket, which helps to center the
+	// insert.
+	// If 0, DefaultInsertBossChamfer is used.
+	// If negative, no chamfer is created.
+	Chamfer float64
+
+	// PocketDepth is the depth of the pocket.
+	// If 0, the insert's length plus
+	// DefaultInsertPocketExtraDepth is used, leaving room
+	// for displaced plastic.
+	PocketDepth float64
+
+	// Gussets is the number of gussets around the boss.
+	Gussets int
+
+	// GussetWidth is the distance that each gusset
+	// extends from the side of the boss at its base.
+	// If 0, the radius of the boss is used.
+	GussetWidth float64
+
+	// GussetHeight is the height of each gusset along the
+	// side of the boss.
+	// If 0, three quarters of the boss height is used.
+	GussetHeight float64
+
+	// GussetThickness is the thickness of each gusset.
+	// If 0, WallThickness is used.
+	GussetThickness float64
+}
+
+// Solid creates the positive solid for the boss,
+// including its gussets but not its pocket.
+func (i *InsertBoss) Solid() model3d.Solid {
+	cylinder := &model3d.Cylinder{
+		P1:     i.Base,
+		P2:     i.Top,
+		Radius: i.outerRadius(),
+	}
+	if i.Gussets == 0 {
+		return cylinder
+	}
+	return &insertBossSolid{
+		Cylinder: cylinder,
+		Boss:     i,
+	}
+}
+
+// Pocket creates the negative solid for the insert's
+// pocket, including the chamfer at its entry.
+func (i *InsertBoss) Pocket() model3d.Solid {
+	axis := i.Top.Sub(i.Base).Normalize()
+	depth := i.PocketDepth
+	if depth == 0 {
+		depth = i.Insert.Length + DefaultInsertPocketExtraDepth
+	}
+	radius := i.Insert.HoleDiameter / 2
+	pocket := model3d.JoinedSolid{
+		&model3d.Cylinder{
+			P1:     i.Top.Sub(axis.Scale(depth)),
+			P2:     i.Top,
+			Radius: radius,
+		},
+	}
+	chamfer := i.Chamfer
+	if chamfer == 0 {
+		chamfer = DefaultInsertBossChamfer
+	}
+	if chamfer > 0 {
+		pocket = append(pocket, &model3d.Cone{
+			Tip:    i.Top.Sub(axis.Scale(radius + chamfer)),
+			Base:   i.Top,
+			Radius: radius + chamfer,
+		})
+	}
+	return pocket
+}
+
+func (i *InsertBoss) outerRadius() float64 {
+	wall := i.WallThickness
+	if wall == 0 {
+		wall = DefaultInsertBossWallThickness
+	}
+	return i.Insert.HoleDiameter/2 + wall
+}
+
+// AttachInsertBosses adds bosses to a solid and cuts
+// their pockets.
+//
+// Pockets are cut after all of the bosses are added, so
+// they also cut through the wall if they are deeper than
+// their bosses.
+func AttachInsertBosses(wall model3d.Solid, bosses ...*InsertBoss) model3d.Solid {
+	if len(bosses) == 0 {
+		return wall
+	}
+	positive := model3d.JoinedSolid{wall}
+	negative := model3d.JoinedSolid{}
+	for _, b := range bosses {
+		positive = append(positive, b.Solid())
+		negative = append(negative, b.Pocket())
+	}
+	return &model3d.SubtractedSolid{
+		Positive: positive.Optimize(),
+		Negative: negative.Optimize(),
+	}
+}
+
+type insertBossSolid struct {
+	*model3d.Cylinder
+	Boss *InsertBoss
+}
+
+func (i *insertBossSolid) Min() model3d.Coord3D {
+	return i.bounds().Min()
+}
+
+func (i *insertBossSolid) Max() model3d.Coord3D {
+	return i.bounds().Max()
+}
+
+func (i *insertBossSolid) Contains(c model3d.Coord3D) bool {
+	if i.Cylinder.Contains(c) {
+		return true
+	}
+	b := i.Boss
+	axis := b.Top.Sub(b.Base)
+	bossHeight := axis.Norm()
+	axis = axis.Scale(1 / bossHeight)
+
+	v := c.Sub(b.Base)
+	h := v.Dot(axis)
+	gussetHeight := b.GussetHeight
+	if gussetHeight == 0 {
+		gussetHeight = bossHeight * 0.75
+	}
+	if h < 0 || h > gussetHeight {
+		return false
+	}
+	gussetWidth := b.GussetWidth
+	if gussetWidth == 0 {
+		gussetWidth = i.Radius
+	}
+	thickness := b.GussetThickness
+	if thickness == 0 {
+		thickness = i.Radius - b.Insert.HoleDiameter/2
+	}
+	maxRadial := i.Radius + gussetWidth*(1-h/gussetHeight)
+
+	b1, b2 := axis.OrthoBasis()
+	for j := 0; j < b.Gussets; j++ {
+		theta := 2 * math.Pi * float64(j) / float64(b.Gussets)
+		radial := b1.Scale(math.Cos(theta)).Add(b2.Scale(math.Sin(theta)))
+		tangent := axis.Cross(radial)
+		r := v.Dot(radial)
+		if r >= 0 && r <= maxRadial && math.Abs(v.Dot(tangent)) <= thickness/2 {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *insertBossSolid) bounds() *model3d.Cylinder {
+	gussetWidth := i.Boss.GussetWidth
+	if gussetWidth == 0 {
+		gussetWidth = i.Radius
+	}
+	return &model3d.Cylinder{
+		P1:     i.P1,
+		P2:     i.P2,
+		Radius: i.Radius + gussetWidth,
+	}
+}
@@ -0,0 +1,52 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestAttachInsertBosses(t *testing.T) {
+	wall := model3d.NewRect(model3d.XYZ(-20, -20, -2), model3d.XYZ(20, 20, 0))
+	boss := &InsertBoss{
+		Base:    model3d.XYZ(5, 5, -1),
+		Top:     model3d.XYZ(5, 5, 10),
+		Insert:  HeatSetInserts["M3"],
+		Gussets: 4,
+	}
+	solid := AttachInsertBosses(wall, boss)
+
+	expected := map[model3d.Coord3D]bool{
+		// Inside the pocket and its chamfer.
+		model3d.XYZ(5, 5, 9):      false,
+		model3d.XYZ(6.9, 5, 5):    false,
+		model3d.XYZ(7.4, 5, 9.95): false,
+		model3d.XYZ(5, 5, 10-6.6): false,
+		model3d.XYZ(5, 5, 10-6.8): true,
+		// In the walls of the boss.
+		model3d.XYZ(7.5, 5, 9):  true,
+		model3d.XYZ(5, 2.5, 9):  true,
+		model3d.XYZ(5, 5, 10.1): false,
+		model3d.XYZ(9.1, 5, 9):  false,
+		// In the wall.
+		model3d.XYZ(-10, -10, -1): true,
+		// Near the base of the gussets.
+		model3d.XYZ(5+6.5, 5, 0.5):   true,
+		model3d.XYZ(5+6.5, 5.5, 0.5): true,
+		model3d.XYZ(5+6.5, 6.5, 0.5): false,
+		model3d.XYZ(5, 5-6.5, 0.5):   true,
+	}
+	for c, exp := range expected {
+		if solid.Contains(c) != exp {
+			t.Errorf("point %v: expected containment %v", c, exp)
+		}
+	}
+
+	if !model3d.BoundsValid(solid) {
+		t.Error("invalid bounds")
+	}
+	max := boss.Solid().Max()
+	if max.Z < 10 || max.X < 5+8 {
+		t.Errorf("unexpected boss max: %v", max)
+	}
+}
@@ -0,0 +1,133 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// DefaultJointDovetailAngle is the default angle between
+// the sides of each dovetail and the joint's normal, in
+// radians.
+const DefaultJointDovetailAngle = math.Pi / 12
+
+// A JointPattern determines the shape of the interlocking
+// fingers of a Joint.
+type JointPattern int
+
+const (
+	// JointBox creates rectangular fingers, which can be
+	// pushed together along the joint's normal.
+	JointBox JointPattern = iota
+
+	// JointDovetail creates fingers which widen away from
+	// the plane, so the parts must be slid together along
+	// the fingers and cannot be pulled apart along the
+	// normal.
+	JointDovetail
+)
+
+// A Joint splits a solid into two parts along a plane,
+// with interlocking fingers cut into the interface.
+//
+// This makes it possible to print objects larger than
+// the build plate in multiple parts, which can then be
+// glued or slid together.
+type Joint struct {
+	// Origin is a point on the plane of the joint.
+	Origin model3d.Coord3D
+
+	// Normal is the normal of the plane, pointing from
+	// the first part towards the second part.
+	Normal model3d.Coord3D
+
+	// Direction is the direction in the plane along which
+	// the fingers alternate. For dovetails, the parts are
+	// slid together perpendicular to this and Normal.
+	Direction model3d.Coord3D
+
+	// Pattern is the shape of the fingers.
+	Pattern JointPattern
+
+	// FingerWidth is the width of each finger at the
+	// plane. Fingers from the two parts alternate, so the
+	// pattern repeats every 2*FingerWidth.
+	FingerWidth float64
+
+	// Depth is the length of the fingers along the normal.
+	// The fingers are centered around the plane.
+	// If 0, FingerWidth is used.
+	Depth float64
+
+	// Clearance is the gap left between the two parts on
+	// every mating face.
+	Clearance float64
+
+	// Angle is the angle in radians between the sides of
+	// each dovetail and the normal.
+	// If 0, DefaultJointDovetailAngle is used.
+	Angle float64
+}
+
+// Split cuts the solid into two parts: one on the
+// negative side of the plane and one on the positive
+// side, with matching fingers.
+func (j *Joint) Split(s model3d.Solid) (model3d.Solid, model3d.Solid) {
+	return &jointPart{Solid: s, joint: j, positive: false},
+		&jointPart{Solid: s, joint: j, positive: true}
+}
+
+// PartContains checks if a point is on the first or
+// second side of the joint, ignoring the bounds of any
+// solid being split.
+//
+// Points in the clearance gap are on neither side.
+func (j *Joint) PartContains(c model3d.Coord3D, positive bool) bool {
+	normal := j.Normal.Normalize()
+	dir := j.Direction.Sub(normal.Scale(normal.Dot(j.Direction))).Normalize()
+	v := c.Sub(j.Origin).Dot(normal)
+	u := c.Sub(j.Origin).Dot(dir)
+
+	if positive {
+		// The second part's fingers fill the gaps between
+		// the first part's fingers, so it is the same
+		// shape mirrored and shifted by one finger.
+		v = -v
+		u += j.FingerWidth
+	}
+
+	depth := j.Depth
+	if depth == 0 {
+		depth = j.FingerWidth
+	}
+	halfGap := j.Clearance / 2
+	if v < -depth/2 {
+		return true
+	} else if v > depth/2-halfGap {
+		return false
+	}
+
+	pitch := j.FingerWidth * 2
+	u -= pitch * math.Round(u/pitch)
+	halfWidth := j.FingerWidth / 2
+	if j.Pattern == JointDovetail {
+		angle := j.Angle
+		if angle == 0 {
+			angle = DefaultJointDovetailAngle
+		}
+		halfWidth += v*math.Tan(angle) - halfGap/math.Cos(angle)
+	} else {
+		halfWidth -= halfGap
+	}
+	return math.Abs(u) < halfWidth
+}
+
+type jointPart struct {
+	model3d.Solid
+	joint    *Joint
+	positive bool
+}
+
+func (j *jointPart) Contains(c model3d.Coord3D) bool {
+	return j.Solid.Contains(c) && j.joint.PartContains(c, j.positive)
+}
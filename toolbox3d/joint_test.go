@@ -0,0 +1,87 @@
+package toolbox3d
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestJoint(t *testing.T) {
+	solid := model3d.NewRect(model3d.XYZ(-5, -5, -5), model3d.XYZ(5, 5, 5))
+	for _, pattern := range []JointPattern{JointBox, JointDovetail} {
+		joint := &Joint{
+			Origin:      model3d.XYZ(0.3, 0, 0),
+			Normal:      model3d.X(1),
+			Direction:   model3d.XYZ(0.1, 1, 0),
+			Pattern:     pattern,
+			FingerWidth: 1,
+			Clearance:   0.1,
+		}
+		p1, p2 := joint.Split(solid)
+
+		var count1, count2 int
+		for i := 0; i < 100000; i++ {
+			c := model3d.NewCoord3DRandBounds(solid.Min(), solid.Max())
+			in1, in2 := p1.Contains(c), p2.Contains(c)
+			if in1 && in2 {
+				t.Fatalf("pattern %d: parts overlap at %v", pattern, c)
+			}
+			if in1 {
+				count1++
+			}
+			if in2 {
+				count2++
+			}
+
+			// Points far from the plane should be in
+			// exactly one of the parts.
+			if c.X < -0.3 && !in1 {
+				t.Fatalf("pattern %d: expected %v in first part", pattern, c)
+			} else if c.X > 0.9 && !in2 {
+				t.Fatalf("pattern %d: expected %v in second part", pattern, c)
+			}
+		}
+
+		// The parts should be roughly the same size, with a
+		// small fraction of the volume lost to clearance.
+		frac1 := float64(count1) / 100000
+		frac2 := float64(count2) / 100000
+		if frac1 < 0.5 || frac1 > 0.55 || frac2 < 0.43 || frac2 > 0.48 {
+			t.Errorf("pattern %d: unexpected volume fractions %f, %f", pattern, frac1, frac2)
+		}
+
+		// The fingers should interlock across the plane.
+		var crossings int
+		prev := p1.Contains(model3d.XYZ(0.3, -4, 0))
+		for y := -4.0; y < 4; y += 0.01 {
+			cur := p1.Contains(model3d.XYZ(0.3, y, 0))
+			if cur != prev {
+				crossings++
+			}
+			prev = cur
+		}
+		if crossings < 7 || crossings > 9 {
+			t.Errorf("pattern %d: unexpected number of crossings: %d", pattern, crossings)
+		}
+	}
+}
+
+func TestJointDovetailLocks(t *testing.T) {
+	joint := &Joint{
+		Normal:      model3d.Z(1),
+		Direction:   model3d.X(1),
+		Pattern:     JointDovetail,
+		FingerWidth: 2,
+		Clearance:   0.1,
+	}
+	// Near the tip of the first part's finger, it should
+	// be wider than at the plane, so points beside the
+	// finger at the plane are in the finger further out.
+	if joint.PartContains(model3d.XYZ(0.97, rand.Float64(), 0), false) {
+		t.Error("expected point at plane to be outside of finger")
+	}
+	if !joint.PartContains(model3d.XYZ(0.97, rand.Float64(), 0.85), false) {
+		t.Error("expected point near tip to be inside finger")
+	}
+}
@@ -0,0 +1,104 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// DefaultKnurlAngle is the default helix angle of the
+// ridges of a diamond knurl, in radians.
+const DefaultKnurlAngle = math.Pi / 6
+
+// A KnurlPattern determines the shape of the ridges of a
+// Knurl.
+type KnurlPattern int
+
+const (
+	// KnurlStraight creates ridges parallel to the axis.
+	KnurlStraight KnurlPattern = iota
+
+	// KnurlDiamond creates two crossing sets of helical
+	// ridges, forming a grid of small pyramids.
+	KnurlDiamond
+)
+
+// A Knurl wraps an existing solid and cuts V-shaped
+// grooves into a cylindrical region of its surface, giving
+// it a grippy texture for knobs and handles.
+//
+// The grooves only affect points between Radius-Depth and
+// Radius from the axis, so the wrapped solid's surface in
+// the region should be a cylinder of the given Radius.
+type Knurl struct {
+	model3d.Solid
+
+	// P1 and P2 are the endpoints of the axis of the
+	// knurled region.
+	P1 model3d.Coord3D
+	P2 model3d.Coord3D
+
+	// Radius is the outer radius of the knurled surface.
+	Radius float64
+
+	// Pitch is the approximate distance between ridges
+	// around the circumference at Radius. It is adjusted
+	// so that a whole number of ridges fit.
+	Pitch float64
+
+	// Depth is the depth of the grooves.
+	Depth float64
+
+	// Pattern is the arrangement of ridges.
+	Pattern KnurlPattern
+
+	// Angle is the angle in radians between the axis and
+	// each set of ridges of a diamond knurl.
+	// If 0, DefaultKnurlAngle is used.
+	Angle float64
+}
+
+func (k *Knurl) Contains(c model3d.Coord3D) bool {
+	if !k.Solid.Contains(c) {
+		return false
+	}
+	axis := k.P2.Sub(k.P1)
+	height := axis.Norm()
+	axis = axis.Scale(1 / height)
+	v := c.Sub(k.P1)
+	z := v.Dot(axis)
+	if z < 0 || z > height {
+		return true
+	}
+	radial := v.Sub(axis.Scale(z))
+	r := radial.Norm()
+	if r < k.Radius-k.Depth || r > k.Radius {
+		return true
+	}
+
+	b1, b2 := axis.OrthoBasis()
+	theta := math.Atan2(radial.Dot(b2), radial.Dot(b1))
+	numRidges := math.Max(1, math.Round(2*math.Pi*k.Radius/k.Pitch))
+	phase := theta * numRidges / (2 * math.Pi)
+
+	var grooveDepth float64
+	if k.Pattern == KnurlDiamond {
+		angle := k.Angle
+		if angle == 0 {
+			angle = DefaultKnurlAngle
+		}
+		twist := z * math.Tan(angle) * numRidges / (2 * math.Pi * k.Radius)
+		grooveDepth = math.Max(knurlGroove(phase+twist), knurlGroove(phase-twist))
+	} else {
+		grooveDepth = knurlGroove(phase)
+	}
+	return r <= k.Radius-k.Depth*grooveDepth
+}
+
+// knurlGroove computes the relative depth of a V-shaped
+// groove, which is 0 at the peaks of the ridges (integer
+// phases) and 1 at the bottom of the grooves.
+func knurlGroove(phase float64) float64 {
+	frac := phase - math.Floor(phase)
+	return 1 - math.Abs(1-2*frac)
+}
@@ -0,0 +1,68 @@
+package toolbox3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestKnurl(t *testing.T) {
+	cylinder := &model3d.Cylinder{P2: model3d.Z(10), Radius: 5}
+	for _, pattern := range []KnurlPattern{KnurlStraight, KnurlDiamond} {
+		knurl := &Knurl{
+			Solid:   cylinder,
+			P1:      model3d.Z(2),
+			P2:      model3d.Z(8),
+			Radius:  5,
+			Pitch:   1,
+			Depth:   0.5,
+			Pattern: pattern,
+		}
+		var transitions int
+		var numInside int
+		prev := knurl.Contains(model3d.XYZ(4.9, 0, 5))
+		for i := 1; i <= 10000; i++ {
+			theta := float64(i) / 10000 * 2 * math.Pi
+			cur := knurl.Contains(model3d.XYZ(4.9*math.Cos(theta), 4.9*math.Sin(theta), 5))
+			if cur != prev {
+				transitions++
+			}
+			if cur {
+				numInside++
+			}
+			prev = cur
+
+			if !knurl.Contains(model3d.XYZ(4.4*math.Cos(theta), 4.4*math.Sin(theta), 5)) {
+				t.Fatal("points below the grooves should be contained")
+			}
+			if !knurl.Contains(model3d.XYZ(4.9*math.Cos(theta), 4.9*math.Sin(theta), 1)) {
+				t.Fatal("points outside the knurled region should be contained")
+			}
+		}
+		if pattern == KnurlStraight {
+			if transitions != 2*31 {
+				t.Errorf("expected %d transitions but got %d", 2*31, transitions)
+			}
+			if frac := float64(numInside) / 10000; math.Abs(frac-0.2) > 0.01 {
+				t.Errorf("expected 0.2 of ridge to remain, but got %f", frac)
+			}
+		} else {
+			// Both sets of grooves must be shallow for a
+			// point to remain, which happens for about 0.2^2
+			// of the surface.
+			var numKept int
+			for i := 0; i < 10000; i++ {
+				theta := rand.Float64() * 2 * math.Pi
+				z := 3 + rand.Float64()*4
+				if knurl.Contains(model3d.XYZ(4.9*math.Cos(theta), 4.9*math.Sin(theta), z)) {
+					numKept++
+				}
+			}
+			if frac := float64(numKept) / 10000; math.Abs(frac-0.04) > 0.01 {
+				t.Errorf("expected 0.04 of surface to remain, but got %f", frac)
+			}
+		}
+	}
+}
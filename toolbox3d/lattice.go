@@ -0,0 +1,157 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+const latticeBorderSamples = 32
+
+// A LatticePattern determines the shape of the cells of a
+// LatticePanel.
+type LatticePattern int
+
+const (
+	// LatticeHexagonal creates a honeycomb of hexagonal
+	// cells.
+	LatticeHexagonal LatticePattern = iota
+
+	// LatticeTriangular creates a grid of equilateral
+	// triangular cells.
+	LatticeTriangular
+)
+
+// A LatticePanel wraps an existing solid and cuts a
+// through-hole lattice pattern into it, leaving a solid
+// border around its edge.
+//
+// This is useful for ventilation panels and for making
+// flat structures lighter.
+//
+// The pattern is a 2D lattice extruded along Normal, so
+// the wrapped solid is typically a flat panel
+// perpendicular to Normal.
+type LatticePanel struct {
+	model3d.Solid
+
+	// Normal is the direction along which the lattice is
+	// extruded.
+	// If zero, the Z axis is used.
+	Normal model3d.Coord3D
+
+	// Direction, if non-zero, is a direction in the plane
+	// of the panel along which rows of cells are aligned.
+	Direction model3d.Coord3D
+
+	// Origin is a point the pattern is aligned to: the
+	// center of a hexagon, or a vertex of the triangles.
+	Origin model3d.Coord3D
+
+	// Pattern is the shape of the cells.
+	Pattern LatticePattern
+
+	// CellSize is the size of each cell: the distance
+	// across the flats of each hexagon, or the side length
+	// of each triangle.
+	CellSize float64
+
+	// WallThickness is the thickness of the walls between
+	// cells.
+	WallThickness float64
+
+	// BorderThickness is the width of the solid border
+	// kept around the edge of the panel, measured in the
+	// plane.
+	//
+	// The border is found by sampling points around each
+	// point, so features of the boundary much smaller than
+	// the border may be missed.
+	BorderThickness float64
+}
+
+func (l *LatticePanel) Contains(c model3d.Coord3D) bool {
+	if !l.Solid.Contains(c) {
+		return false
+	}
+	b1, b2 := l.basis()
+	if l.inBorder(c, b1, b2) {
+		return true
+	}
+	v := c.Sub(l.Origin)
+	p := [2]float64{v.Dot(b1), v.Dot(b2)}
+	if l.Pattern == LatticeTriangular {
+		return l.triangularWall(p)
+	}
+	return l.hexagonalWall(p)
+}
+
+func (l *LatticePanel) basis() (model3d.Coord3D, model3d.Coord3D) {
+	normal := l.Normal
+	if normal.Norm() == 0 {
+		normal = model3d.Z(1)
+	}
+	normal = normal.Normalize()
+	if l.Direction.Norm() == 0 {
+		return normal.OrthoBasis()
+	}
+	b1 := l.Direction.Sub(normal.Scale(normal.Dot(l.Direction))).Normalize()
+	return b1, normal.Cross(b1)
+}
+
+func (l *LatticePanel) inBorder(c, b1, b2 model3d.Coord3D) bool {
+	if l.BorderThickness == 0 {
+		return false
+	}
+	for i := 0; i < latticeBorderSamples; i++ {
+		theta := 2 * math.Pi * float64(i) / latticeBorderSamples
+		offset := b1.Scale(math.Cos(theta)).Add(b2.Scale(math.Sin(theta)))
+		if !l.Solid.Contains(c.Add(offset.Scale(l.BorderThickness))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *LatticePanel) hexagonalWall(p [2]float64) bool {
+	// Cell centers form a triangular lattice with basis
+	// vectors s*(1, 0) and s*(1/2, sqrt(3)/2).
+	s := l.CellSize
+	rowHeight := s * math.Sqrt(3) / 2
+	j := p[1] / rowHeight
+	i := p[0]/s - j/2
+	i0, j0 := math.Floor(i), math.Floor(j)
+
+	// The nearest center is a corner of the containing
+	// parallelogram, and the distance to the edge of its
+	// hexagon is the apothem minus the hexagonal norm.
+	minNorm := math.Inf(1)
+	for _, di := range []float64{0, 1} {
+		for _, dj := range []float64{0, 1} {
+			ci, cj := i0+di, j0+dj
+			x := p[0] - s*(ci+cj/2)
+			y := p[1] - rowHeight*cj
+			norm := math.Max(
+				math.Abs(x),
+				math.Max(math.Abs(x/2+y*math.Sqrt(3)/2), math.Abs(x/2-y*math.Sqrt(3)/2)),
+			)
+			minNorm = math.Min(minNorm, norm)
+		}
+	}
+	return s/2-minNorm < l.WallThickness/2
+}
+
+func (l *LatticePanel) triangularWall(p [2]float64) bool {
+	// Edges of the triangles lie on three families of
+	// parallel lines, each spaced one triangle height
+	// apart.
+	height := l.CellSize * math.Sqrt(3) / 2
+	for i := 0; i < 3; i++ {
+		theta := math.Pi/2 + float64(i)*2*math.Pi/3
+		d := (p[0]*math.Cos(theta) + p[1]*math.Sin(theta)) / height
+		if math.Abs(d-math.Round(d))*height < l.WallThickness/2 {
+			return true
+		}
+	}
+	return false
+}
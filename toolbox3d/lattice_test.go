@@ -0,0 +1,63 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestLatticePanel(t *testing.T) {
+	panel := model3d.NewRect(model3d.XYZ(-20, -20, 0), model3d.XYZ(20, 20, 1))
+	expectedFractions := map[LatticePattern]float64{
+		LatticeHexagonal:  1 - math.Pow(1-0.2/2, 2),
+		LatticeTriangular: 1 - math.Pow(1-0.1/(2/(2*math.Sqrt(3))), 2),
+	}
+	for pattern, expected := range expectedFractions {
+		lattice := &LatticePanel{
+			Solid:           panel,
+			Direction:       model3d.XYZ(1, 1, 0),
+			Pattern:         pattern,
+			CellSize:        2,
+			WallThickness:   0.2,
+			BorderThickness: 1.5,
+		}
+
+		// Check the fraction of the interior which is kept.
+		var count int
+		const n = 50000
+		inner := model3d.XYZ(18.5, 18.5, 0.5)
+		for i := 0; i < n; i++ {
+			c := model3d.NewCoord3DRandBounds(inner.Scale(-1), inner)
+			c.Z = 0.5
+			if lattice.Contains(c) {
+				count++
+			}
+			// The pattern should go all the way through.
+			c.Z = 0.01
+			c1 := lattice.Contains(c)
+			c.Z = 0.99
+			if c1 != lattice.Contains(c) {
+				t.Fatalf("pattern %d: inconsistent along normal at %v", pattern, c)
+			}
+		}
+		actual := float64(count) / n
+		if math.Abs(actual-expected) > 0.02 {
+			t.Errorf("pattern %d: expected fraction %f but got %f", pattern, expected, actual)
+		}
+
+		// The border should be solid.
+		for _, c := range []model3d.Coord3D{
+			model3d.XYZ(-19, 3.3, 0.5),
+			model3d.XYZ(7.1, 19.1, 0.5),
+			model3d.XYZ(19.9, -19.9, 0.5),
+		} {
+			if !lattice.Contains(c) {
+				t.Errorf("pattern %d: expected %v in border", pattern, c)
+			}
+		}
+		if lattice.Contains(model3d.XYZ(0, 0, 1.1)) {
+			t.Errorf("pattern %d: point outside of solid is contained", pattern)
+		}
+	}
+}
@@ -27,6 +27,15 @@ type ScrewSolid struct {
 	// This may not exceed Radius.
 	GrooveSize float64
 
+	// Pitch, if non-zero, is the axial distance between
+	// threads. In this case, GrooveSize is ignored, and
+	// the threads follow the standard 60 degree form used
+	// by ISO metric and Unified threads, with a depth
+	// determined by the pitch.
+	//
+	// See ThreadProfile for standard thread sizes.
+	Pitch float64
+
 	// Pointed can be set to true to indicate that the tip
 	// at the P2 end should be cut off at a 45 degree
 	// angle (in the shape of a cone).
@@ -75,7 +84,25 @@ func (s *ScrewSolid) Contains(c model3d.Coord3D) bool {
 	maxDistance := s.Radius - offset.XY().Norm()
 	if maxDistance < 0 {
 		return false
-	} else if maxDistance > s.GrooveSize {
+	}
+
+	if s.Pitch != 0 {
+		depth := isoThreadDepth(s.Pitch)
+		if maxDistance > depth {
+			return true
+		}
+		zOffset := math.Atan2(offset.Y, offset.X) * s.Pitch / (2 * math.Pi)
+		offZ := offset.Z - zOffset
+		crestDist := math.Abs(offZ - math.Round(offZ/s.Pitch)*s.Pitch)
+
+		// The crest is flat for a width of Pitch/8, after
+		// which the flanks descend at 30 degrees from the
+		// radial direction until reaching the flat root.
+		flankDepth := (crestDist - s.Pitch/16) * math.Sqrt(3)
+		return maxDistance >= math.Min(depth, flankDepth)
+	}
+
+	if maxDistance > s.GrooveSize {
 		return true
 	}
 
@@ -89,6 +116,13 @@ func (s *ScrewSolid) Contains(c model3d.Coord3D) bool {
 	return false
 }
 
+// isoThreadDepth computes the radial depth of the basic
+// profile of a 60 degree thread, which is 5/8 of the
+// height of the fundamental triangle.
+func isoThreadDepth(pitch float64) float64 {
+	return pitch * math.Sqrt(3) / 2 * 5 / 8
+}
+
 func (s *ScrewSolid) boundingCylinder() *model3d.CylinderSolid {
 	return &model3d.CylinderSolid{
 		P1:     s.P1,
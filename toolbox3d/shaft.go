@@ -0,0 +1,270 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// DefaultShaftFlatDepth is the default depth of the flat
+// of a D-shaft, relative to the shaft's diameter.
+const DefaultShaftFlatDepth = 0.1
+
+// A ShaftProfile determines the cross section of a Shaft.
+type ShaftProfile int
+
+const (
+	// ShaftRound is a plain cylindrical shaft.
+	ShaftRound ShaftProfile = iota
+
+	// ShaftD is a shaft with one flat side, as found on
+	// many small motors.
+	ShaftD
+
+	// ShaftKeyed is a shaft with a keyway for a parallel
+	// key.
+	ShaftKeyed
+)
+
+// A ParallelKey describes the dimensions of a parallel
+// key and its keyways, in millimeters.
+type ParallelKey struct {
+	// Width is the width of the key.
+	Width float64
+
+	// Height is the height of the key.
+	Height float64
+
+	// ShaftDepth is the depth of the keyway in the shaft.
+	ShaftDepth float64
+
+	// HubDepth is the depth of the keyway in the hub,
+	// measured from the surface of the shaft.
+	HubDepth float64
+}
+
+var standardParallelKeys = []struct {
+	MaxDiameter float64
+	Key         ParallelKey
+}{
+	{8, ParallelKey{Width: 2, Height: 2, ShaftDepth: 1.2, HubDepth: 1}},
+	{10, ParallelKey{Width: 3, Height: 3, ShaftDepth: 1.8, HubDepth: 1.4}},
+	{12, ParallelKey{Width: 4, Height: 4, ShaftDepth: 2.5, HubDepth: 1.8}},
+	{17, ParallelKey{Width: 5, Height: 5, ShaftDepth: 3, HubDepth: 2.3}},
+	{22, ParallelKey{Width: 6, Height: 6, ShaftDepth: 3.5, HubDepth: 2.8}},
+	{30, ParallelKey{Width: 8, Height: 7, ShaftDepth: 4, HubDepth: 3.3}},
+	{38, ParallelKey{Width: 10, Height: 8, ShaftDepth: 5, HubDepth: 3.3}},
+	{44, ParallelKey{Width: 12, Height: 8, ShaftDepth: 5, HubDepth: 3.3}},
+	{50, ParallelKey{Width: 14, Height: 9, ShaftDepth: 5.5, HubDepth: 3.8}},
+	{58, ParallelKey{Width: 16, Height: 10, ShaftDepth: 6, HubDepth: 4.3}},
+}
+
+// StandardParallelKey gets the DIN 6885 parallel key for
+// a shaft diameter in millimeters.
+//
+// The second return value is false if the diameter is
+// outside of the supported range of 6mm to 58mm.
+func StandardParallelKey(diameter float64) (*ParallelKey, bool) {
+	if diameter < 6 {
+		return nil, false
+	}
+	for _, entry := range standardParallelKeys {
+		if diameter <= entry.MaxDiameter {
+			key := entry.Key
+			return &key, true
+		}
+	}
+	return nil, false
+}
+
+// A Shaft describes a shaft for transmitting torque,
+// such as a motor shaft, and can create solids for the
+// shaft itself and for the bores of hubs that fit on it.
+type Shaft struct {
+	// Diameter is the diameter of the shaft.
+	Diameter float64
+
+	// Profile is the cross section of the shaft.
+	Profile ShaftProfile
+
+	// Direction is the direction, perpendicular to the
+	// shaft's axis, that the flat or keyway faces.
+	// If zero, an arbitrary direction is used.
+	Direction model3d.Coord3D
+
+	// FlatDepth is the depth of the flat of a D-shaft.
+	// If 0, DefaultShaftFlatDepth*Diameter is used.
+	FlatDepth float64
+
+	// Key is the key for a keyed shaft.
+	// If nil, StandardParallelKey(Diameter) is used, and
+	// creating solids panics if there is no standard key.
+	Key *ParallelKey
+}
+
+// Solid creates the shaft from p1 to p2.
+func (s *Shaft) Solid(p1, p2 model3d.Coord3D) model3d.Solid {
+	return &shaftSolid{Shaft: s, P1: p1, P2: p2}
+}
+
+// Bore creates a solid to subtract from a hub to make a
+// bore for the shaft from p1 to p2.
+//
+// The clearance is added to every side of the bore. For
+// keyed shafts, the bore includes the keyway of the hub.
+func (s *Shaft) Bore(p1, p2 model3d.Coord3D, clearance float64) model3d.Solid {
+	return &shaftSolid{Shaft: s, P1: p1, P2: p2, Bore: true, Clearance: clearance}
+}
+
+// KeySolid creates the key for a keyed shaft from p1 to
+// p2, seated in the shaft's keyway.
+func (s *Shaft) KeySolid(p1, p2 model3d.Coord3D) model3d.Solid {
+	key := s.key()
+	axis, u, v := s.basis(p1, p2)
+	r := s.Diameter / 2
+	p := model3d.ConvexPolytope{
+		&model3d.LinearConstraint{Normal: axis, Max: axis.Dot(p2)},
+		&model3d.LinearConstraint{Normal: axis.Scale(-1), Max: -axis.Dot(p1)},
+		&model3d.LinearConstraint{Normal: v, Max: v.Dot(p1) + key.Width/2},
+		&model3d.LinearConstraint{Normal: v.Scale(-1), Max: -v.Dot(p1) + key.Width/2},
+		&model3d.LinearConstraint{Normal: u, Max: u.Dot(p1) + r - key.ShaftDepth + key.Height},
+		&model3d.LinearConstraint{Normal: u.Scale(-1), Max: -u.Dot(p1) - r + key.ShaftDepth},
+	}
+	return p.Solid()
+}
+
+func (s *Shaft) key() *ParallelKey {
+	if s.Key != nil {
+		return s.Key
+	}
+	key, ok := StandardParallelKey(s.Diameter)
+	if !ok {
+		panic("no standard key for shaft diameter")
+	}
+	return key
+}
+
+func (s *Shaft) flatDepth() float64 {
+	if s.FlatDepth == 0 {
+		return s.Diameter * DefaultShaftFlatDepth
+	}
+	return s.FlatDepth
+}
+
+// basis gets the axis of the shaft, the direction u that
+// the flat or keyway faces, and a third direction v.
+func (s *Shaft) basis(p1, p2 model3d.Coord3D) (axis, u, v model3d.Coord3D) {
+	axis = p2.Sub(p1).Normalize()
+	u = s.Direction.ProjectOut(axis)
+	if u.Norm() < 1e-8 {
+		u, _ = axis.OrthoBasis()
+	}
+	u = u.Normalize()
+	v = axis.Cross(u)
+	return
+}
+
+type shaftSolid struct {
+	Shaft     *Shaft
+	P1        model3d.Coord3D
+	P2        model3d.Coord3D
+	Bore      bool
+	Clearance float64
+}
+
+func (s *shaftSolid) Min() model3d.Coord3D {
+	return s.bounds().Min()
+}
+
+func (s *shaftSolid) Max() model3d.Coord3D {
+	return s.bounds().Max()
+}
+
+func (s *shaftSolid) Contains(c model3d.Coord3D) bool {
+	axis, uAxis, vAxis := s.Shaft.basis(s.P1, s.P2)
+	rel := c.Sub(s.P1)
+	t := rel.Dot(axis)
+	if t < 0 || t > s.P2.Sub(s.P1).Norm() {
+		return false
+	}
+	u, v := rel.Dot(uAxis), rel.Dot(vAxis)
+	r := s.Shaft.Diameter/2 + s.Clearance
+	inCircle := u*u+v*v <= r*r
+
+	switch s.Shaft.Profile {
+	case ShaftD:
+		return inCircle && u <= r-s.Shaft.flatDepth()
+	case ShaftKeyed:
+		key := s.Shaft.key()
+		halfWidth := key.Width/2 + s.Clearance
+		inKeyway := math.Abs(v) <= halfWidth
+		if s.Bore {
+			return inCircle || (inKeyway && u >= 0 && u <= r+key.HubDepth)
+		}
+		return inCircle && !(inKeyway && u > r-key.ShaftDepth)
+	default:
+		return inCircle
+	}
+}
+
+func (s *shaftSolid) bounds() *model3d.Cylinder {
+	r := s.Shaft.Diameter/2 + s.Clearance
+	if s.Bore && s.Shaft.Profile == ShaftKeyed {
+		r += s.Shaft.key().HubDepth
+	}
+	return &model3d.Cylinder{P1: s.P1, P2: s.P2, Radius: r}
+}
+
+// A ShaftHub is a cylindrical hub with a bore for a
+// Shaft, optionally with a radial set screw that presses
+// against the flat or key of the shaft.
+type ShaftHub struct {
+	// Shaft is the shaft that the hub fits onto.
+	Shaft *Shaft
+
+	// P1 and P2 are the endpoints of the hub's axis.
+	P1 model3d.Coord3D
+	P2 model3d.Coord3D
+
+	// OuterDiameter is the diameter of the hub.
+	OuterDiameter float64
+
+	// Clearance is added to the bore and set screw hole.
+	Clearance float64
+
+	// SetScrew, if non-nil, is the thread of a set screw
+	// halfway between P1 and P2.
+	SetScrew *ThreadProfile
+
+	// SetScrewBoss is the distance that a boss around the
+	// set screw extends beyond the surface of the hub,
+	// providing more thread length. If 0, no boss is made.
+	SetScrewBoss float64
+}
+
+// Solid creates the hub, including its bore.
+func (s *ShaftHub) Solid() model3d.Solid {
+	hub := model3d.JoinedSolid{
+		&model3d.Cylinder{P1: s.P1, P2: s.P2, Radius: s.OuterDiameter / 2},
+	}
+	holes := model3d.JoinedSolid{
+		s.Shaft.Bore(s.P1, s.P2, s.Clearance),
+	}
+	if s.SetScrew != nil {
+		_, u, _ := s.Shaft.basis(s.P1, s.P2)
+		center := s.P1.Mid(s.P2)
+		outer := center.Add(u.Scale(s.OuterDiameter/2 + s.SetScrewBoss))
+		if s.SetScrewBoss != 0 {
+			hub = append(hub, &model3d.Cylinder{
+				P1:     center,
+				P2:     outer,
+				Radius: s.SetScrew.MajorDiameter,
+			})
+		}
+		holes = append(holes, s.SetScrew.Hole(center, outer, s.Clearance))
+	}
+	return &model3d.SubtractedSolid{
+		Positive: hub,
+		Negative: holes,
+	}
+}
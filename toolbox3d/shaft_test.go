@@ -0,0 +1,92 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestStandardParallelKey(t *testing.T) {
+	if _, ok := StandardParallelKey(5); ok {
+		t.Error("expected no key for small shaft")
+	}
+	if _, ok := StandardParallelKey(60); ok {
+		t.Error("expected no key for large shaft")
+	}
+	key, ok := StandardParallelKey(20)
+	if !ok || key.Width != 6 || key.Height != 6 {
+		t.Errorf("unexpected key: %v", key)
+	}
+}
+
+func TestShaftFitsBore(t *testing.T) {
+	p1, p2 := model3d.XYZ(1, 2, 3), model3d.XYZ(1, 2, 13)
+	for _, profile := range []ShaftProfile{ShaftRound, ShaftD, ShaftKeyed} {
+		shaft := &Shaft{
+			Diameter:  12,
+			Profile:   profile,
+			Direction: model3d.X(1),
+		}
+		solid := shaft.Solid(p1, p2)
+		bore := shaft.Bore(p1, p2, 0.1)
+		var key model3d.Solid
+		if profile == ShaftKeyed {
+			key = shaft.KeySolid(p1, p2)
+		}
+		for i := 0; i < 20000; i++ {
+			c := model3d.NewCoord3DRandBounds(bore.Min(), bore.Max())
+			if solid.Contains(c) && !bore.Contains(c) {
+				t.Fatalf("profile %d: shaft not in bore at %v", profile, c)
+			}
+			if key != nil && key.Contains(c) {
+				if solid.Contains(c) {
+					t.Fatalf("profile %d: key intersects shaft at %v", profile, c)
+				}
+				if !bore.Contains(c) {
+					t.Fatalf("profile %d: key not in bore at %v", profile, c)
+				}
+			}
+		}
+
+		// Check the flat or keyway.
+		expected := map[ShaftProfile][2]bool{
+			ShaftRound: {true, true},
+			ShaftD:     {false, true},
+			ShaftKeyed: {false, true},
+		}[profile]
+		c1 := p1.Add(model3d.XYZ(5.9, 0, 5))
+		c2 := p1.Add(model3d.XYZ(-5.9, 0, 5))
+		if solid.Contains(c1) != expected[0] || solid.Contains(c2) != expected[1] {
+			t.Errorf("profile %d: unexpected flat or keyway", profile)
+		}
+	}
+}
+
+func TestShaftHub(t *testing.T) {
+	shaft := &Shaft{Diameter: 5, Profile: ShaftD, Direction: model3d.Y(1)}
+	hub := &ShaftHub{
+		Shaft:         shaft,
+		P2:            model3d.Z(10),
+		OuterDiameter: 15,
+		Clearance:     0.1,
+		SetScrew:      ISOMetricThreads["M3"],
+		SetScrewBoss:  3,
+	}
+	solid := hub.Solid()
+	expected := map[model3d.Coord3D]bool{
+		model3d.XYZ(0, 0, 2):     false,
+		model3d.XYZ(6, 0, 2):     true,
+		model3d.XYZ(0, 6, 5):     false,
+		model3d.XYZ(0, 9.5, 5):   false,
+		model3d.XYZ(2.5, 9.5, 5): true,
+		model3d.XYZ(0, 11, 5):    false,
+	}
+	for c, exp := range expected {
+		if solid.Contains(c) != exp {
+			t.Errorf("point %v: expected %v", c, exp)
+		}
+	}
+	if !shaft.Bore(model3d.Origin, model3d.Z(1), 0.1).Contains(model3d.XYZ(0, 2.1, 0.5)) {
+		t.Error("bore should include the clearance past the flat")
+	}
+}
@@ -0,0 +1,232 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+const (
+	DefaultSnapFitLeadAngle      = math.Pi / 6
+	DefaultSnapFitRetentionAngle = math.Pi / 2
+)
+
+// A SnapFitClip is a model3d.Solid for a cantilever
+// snap-fit clip: a flexible arm with a hook at its tip,
+// which deflects while being pushed through a slot and
+// then catches behind the slot's wall.
+//
+// Clips can join parts of an enclosure without screws.
+// Use Slot() to create the matching opening in the other
+// part.
+type SnapFitClip struct {
+	// Base is the center of the root of the arm, where it
+	// attaches to the rest of the part.
+	Base model3d.Coord3D
+
+	// Direction is the direction in which the arm extends
+	// from Base.
+	Direction model3d.Coord3D
+
+	// HookDirection is the direction in which the hook
+	// protrudes from the arm. It should be perpendicular
+	// to Direction.
+	HookDirection model3d.Coord3D
+
+	// Length is the length of the arm, including the hook.
+	Length float64
+
+	// Width is the width of the arm.
+	Width float64
+
+	// Thickness is the thickness of the arm at its root,
+	// not including the hook.
+	Thickness float64
+
+	// TipThickness, if non-zero, is the thickness of the
+	// arm at its tip. Tapering the arm spreads the strain
+	// more evenly, allowing larger deflections.
+	TipThickness float64
+
+	// Undercut is the distance that the hook protrudes
+	// from the arm, which is also how far the arm must
+	// deflect during insertion.
+	Undercut float64
+
+	// LeadAngle is the angle in radians between the arm
+	// and the sloped face of the hook which slides along
+	// the slot during insertion.
+	// If 0, DefaultSnapFitLeadAngle is used.
+	LeadAngle float64
+
+	// RetentionAngle is the angle in radians between the
+	// arm and the face of the hook which catches on the
+	// slot. An angle of 90 degrees makes a permanent
+	// joint, while smaller angles allow the clip to be
+	// pulled back out.
+	// If 0, DefaultSnapFitRetentionAngle is used.
+	RetentionAngle float64
+}
+
+func (s *SnapFitClip) Min() model3d.Coord3D {
+	return s.bounds().Min()
+}
+
+func (s *SnapFitClip) Max() model3d.Coord3D {
+	return s.bounds().Max()
+}
+
+func (s *SnapFitClip) Contains(c model3d.Coord3D) bool {
+	frame := s.frame()
+	a, n, b := frame.Local(c)
+	if a < 0 || a > s.Length || math.Abs(b) > s.Width/2 {
+		return false
+	}
+	if n <= 0 {
+		return n >= -s.thicknessAt(a)
+	} else if n > s.Undercut {
+		return false
+	}
+	lead, retention := s.angles()
+	hookStart := s.hookStart()
+	return a >= hookStart+n/math.Tan(retention) && a <= s.Length-n/math.Tan(lead)
+}
+
+// Slot creates a solid to subtract from a wall of the
+// mating part, making an opening for the clip to pass
+// through.
+//
+// The opening is placed so that, once the clip is
+// inserted, the hook catches on the far side of a wall of
+// the given thickness. The clearance is added to every
+// side of the opening, and between the wall and the
+// hook.
+func (s *SnapFitClip) Slot(wallThickness, clearance float64) model3d.Solid {
+	frame := s.frame()
+	farSide := s.hookStart() - clearance
+	return &snapFitBox{
+		frame: frame,
+		min: [3]float64{
+			farSide - wallThickness - clearance,
+			-s.tipThickness() - s.Undercut - clearance,
+			-s.Width/2 - clearance,
+		},
+		max: [3]float64{
+			farSide + clearance,
+			clearance,
+			s.Width/2 + clearance,
+		},
+	}
+}
+
+func (s *SnapFitClip) hookStart() float64 {
+	lead, retention := s.angles()
+	return s.Length - s.Undercut/math.Tan(lead) - s.Undercut/math.Tan(retention)
+}
+
+func (s *SnapFitClip) angles() (lead, retention float64) {
+	lead, retention = s.LeadAngle, s.RetentionAngle
+	if lead == 0 {
+		lead = DefaultSnapFitLeadAngle
+	}
+	if retention == 0 {
+		retention = DefaultSnapFitRetentionAngle
+	}
+	return
+}
+
+func (s *SnapFitClip) tipThickness() float64 {
+	if s.TipThickness == 0 {
+		return s.Thickness
+	}
+	return s.TipThickness
+}
+
+func (s *SnapFitClip) thicknessAt(a float64) float64 {
+	frac := a / s.Length
+	return s.Thickness*(1-frac) + s.tipThickness()*frac
+}
+
+func (s *SnapFitClip) frame() *snapFitFrame {
+	axis := s.Direction.Normalize()
+	hook := s.HookDirection.Sub(axis.Scale(axis.Dot(s.HookDirection))).Normalize()
+	return &snapFitFrame{
+		Origin: s.Base,
+		Axes:   [3]model3d.Coord3D{axis, hook, axis.Cross(hook)},
+	}
+}
+
+func (s *SnapFitClip) bounds() *snapFitBox {
+	return &snapFitBox{
+		frame: s.frame(),
+		min: [3]float64{
+			0,
+			-math.Max(s.Thickness, s.tipThickness()),
+			-s.Width / 2,
+		},
+		max: [3]float64{s.Length, s.Undercut, s.Width / 2},
+	}
+}
+
+// snapFitFrame is an orthonormal coordinate frame.
+type snapFitFrame struct {
+	Origin model3d.Coord3D
+	Axes   [3]model3d.Coord3D
+}
+
+func (s *snapFitFrame) Local(c model3d.Coord3D) (float64, float64, float64) {
+	v := c.Sub(s.Origin)
+	return v.Dot(s.Axes[0]), v.Dot(s.Axes[1]), v.Dot(s.Axes[2])
+}
+
+func (s *snapFitFrame) Global(x, y, z float64) model3d.Coord3D {
+	return s.Origin.Add(s.Axes[0].Scale(x)).Add(s.Axes[1].Scale(y)).Add(s.Axes[2].Scale(z))
+}
+
+// snapFitBox is a box which is axis-aligned in a frame.
+type snapFitBox struct {
+	frame *snapFitFrame
+	min   [3]float64
+	max   [3]float64
+}
+
+func (s *snapFitBox) Min() model3d.Coord3D {
+	min, _ := s.globalBounds()
+	return min
+}
+
+func (s *snapFitBox) Max() model3d.Coord3D {
+	_, max := s.globalBounds()
+	return max
+}
+
+func (s *snapFitBox) Contains(c model3d.Coord3D) bool {
+	x, y, z := s.frame.Local(c)
+	local := [3]float64{x, y, z}
+	for i, v := range local {
+		if v < s.min[i] || v > s.max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *snapFitBox) globalBounds() (min, max model3d.Coord3D) {
+	for i := 0; i < 8; i++ {
+		var local [3]float64
+		for axis := range local {
+			if i&(1<<uint(axis)) == 0 {
+				local[axis] = s.min[axis]
+			} else {
+				local[axis] = s.max[axis]
+			}
+		}
+		c := s.frame.Global(local[0], local[1], local[2])
+		if i == 0 {
+			min, max = c, c
+		} else {
+			min, max = min.Min(c), max.Max(c)
+		}
+	}
+	return
+}
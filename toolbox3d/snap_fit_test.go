@@ -0,0 +1,103 @@
+package toolbox3d
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestSnapFitClip(t *testing.T) {
+	clip := &SnapFitClip{
+		Base:          model3d.XYZ(1, 2, 3),
+		Direction:     model3d.Z(1),
+		HookDirection: model3d.X(1),
+		Length:        10,
+		Width:         4,
+		Thickness:     1.5,
+		TipThickness:  1,
+		Undercut:      0.8,
+	}
+
+	expectedMin := model3d.XYZ(1-1.5, 2-2, 3)
+	expectedMax := model3d.XYZ(1+0.8, 2+2, 13)
+	if clip.Min().Dist(expectedMin) > 1e-8 || clip.Max().Dist(expectedMax) > 1e-8 {
+		t.Errorf("unexpected bounds: %v-%v", clip.Min(), clip.Max())
+	}
+
+	inside := []model3d.Coord3D{
+		model3d.XYZ(1-1.4, 2, 3.1),
+		model3d.XYZ(1-0.9, 2, 12.9),
+		model3d.XYZ(1+0.1, 2, 12.5),
+		model3d.XYZ(1+0.7, 2, 11.7),
+	}
+	outside := []model3d.Coord3D{
+		model3d.XYZ(1-1.4, 2, 12.9),
+		model3d.XYZ(1+0.1, 2, 5),
+		model3d.XYZ(1+0.7, 2, 12.9),
+		model3d.XYZ(1, 2+2.1, 5),
+	}
+	for _, c := range inside {
+		if !clip.Contains(c) {
+			t.Errorf("expected %v to be inside", c)
+		}
+	}
+	for _, c := range outside {
+		if clip.Contains(c) {
+			t.Errorf("expected %v to be outside", c)
+		}
+	}
+}
+
+func TestSnapFitClipSlot(t *testing.T) {
+	clip := &SnapFitClip{
+		Direction:      model3d.XYZ(1, 1, 0),
+		HookDirection:  model3d.XYZ(-1, 1, 0.3),
+		Length:         8,
+		Width:          3,
+		Thickness:      1,
+		Undercut:       0.5,
+		RetentionAngle: math.Pi / 3,
+	}
+	const wallThickness = 1.5
+	const clearance = 0.1
+	slot := clip.Slot(wallThickness, clearance)
+
+	// Create a wall whose far side is where the hook
+	// catches, and cut the slot out of it.
+	frame := clip.frame()
+	hookStart := clip.hookStart()
+	wall := &snapFitBox{
+		frame: frame,
+		min:   [3]float64{hookStart - clearance - wallThickness, -5, -5},
+		max:   [3]float64{hookStart - clearance, 5, 5},
+	}
+	cut := &model3d.SubtractedSolid{Positive: wall, Negative: slot}
+
+	// The inserted clip should not intersect the wall.
+	min, max := clip.Min(), clip.Max()
+	for i := 0; i < 100000; i++ {
+		c := model3d.XYZ(rand.Float64(), rand.Float64(), rand.Float64())
+		c = min.Add(c.Mul(max.Sub(min)))
+		if clip.Contains(c) && cut.Contains(c) {
+			t.Fatalf("clip intersects wall at %v", c)
+		}
+	}
+
+	// The hook should be stopped by the wall if the clip
+	// is pulled back out without deflecting.
+	hookPoint := frame.Global(hookStart+0.3, 0.3, 0)
+	if !clip.Contains(hookPoint) {
+		t.Fatal("expected point to be in hook")
+	}
+	if !cut.Contains(hookPoint.Sub(frame.Axes[0].Scale(0.5))) {
+		t.Error("hook would not catch on the wall")
+	}
+
+	// The deflected hook should pass through the slot.
+	deflected := frame.Global(hookStart, 0.5-0.5-clip.Thickness, 0)
+	if cut.Contains(deflected.Sub(frame.Axes[0].Scale(0.5))) {
+		t.Error("deflected arm would hit the wall")
+	}
+}
@@ -44,6 +44,13 @@ type Teardrop2D struct {
 	// If this is the zero vector, then a unit vector in
 	// the Y direction is used.
 	Direction model2d.Coord
+
+	// Truncation, if non-zero, is the distance from the
+	// center along Direction at which the tip is cut off.
+	//
+	// Setting this to Radius gives a flat top which can be
+	// bridged, reducing the height of the hole.
+	Truncation float64
 }
 
 func (t *Teardrop2D) Min() model2d.Coord {
@@ -57,17 +64,12 @@ func (t *Teardrop2D) Max() model2d.Coord {
 }
 
 func (t *Teardrop2D) Contains(c model2d.Coord) bool {
-	c2 := c.Sub(t.Center)
-	mag := c2.Norm()
-	if mag <= t.Radius {
-		return true
+	c2 := rotateToDirection(c.Sub(t.Center), t.Direction)
+	if t.Truncation != 0 && c2.Y > t.Truncation {
+		return false
 	}
-
-	// Rotate c2 so that the tip direction is Y.
-	if (t.Direction != model2d.Coord{}) {
-		axisY := t.Direction.Normalize()
-		axisX := model2d.XY(axisY.Y, -axisY.X)
-		c2 = model2d.XY(axisX.Dot(c2), axisY.Dot(c2))
+	if c2.Norm() <= t.Radius {
+		return true
 	}
 
 	// Under this Y value, we can't be in the tip.
@@ -85,21 +87,97 @@ func (t *Teardrop2D) Contains(c model2d.Coord) bool {
 	return true
 }
 
+// BridgedCircle2D is a 2D solid in the shape of a circle
+// where the half facing one direction is extended into a
+// square.
+//
+// This can be used to cut circles out of shapes without
+// support structures in FDM printing, since the flat top
+// of the hole can be bridged.
+// Unlike a Teardrop2D, it does not rely on 45 degree
+// overhangs, at the cost of a longer bridge.
+type BridgedCircle2D struct {
+	// Center is the center of the circle.
+	Center model2d.Coord
+
+	// Radius is the radius of the circle.
+	Radius float64
+
+	// Direction is the direction in which the flat side
+	// is facing.
+	// If this is the zero vector, then a unit vector in
+	// the Y direction is used.
+	Direction model2d.Coord
+}
+
+func (b *BridgedCircle2D) Min() model2d.Coord {
+	extraRadius := math.Sqrt2 * b.Radius
+	return b.Center.Sub(model2d.XY(extraRadius, extraRadius))
+}
+
+func (b *BridgedCircle2D) Max() model2d.Coord {
+	extraRadius := math.Sqrt2 * b.Radius
+	return b.Center.Add(model2d.XY(extraRadius, extraRadius))
+}
+
+func (b *BridgedCircle2D) Contains(c model2d.Coord) bool {
+	c2 := rotateToDirection(c.Sub(b.Center), b.Direction)
+	if c2.Y >= 0 {
+		return c2.Y <= b.Radius && math.Abs(c2.X) <= b.Radius
+	}
+	return c2.Norm() <= b.Radius
+}
+
+// rotateToDirection rotates c so that direction becomes
+// the Y axis.
+func rotateToDirection(c, direction model2d.Coord) model2d.Coord {
+	if (direction == model2d.Coord{}) {
+		return c
+	}
+	axisY := direction.Normalize()
+	axisX := model2d.XY(axisY.Y, -axisY.X)
+	return model2d.XY(axisX.Dot(c), axisY.Dot(c))
+}
+
 // Teardrop3D creates a 3D teardrop by extending a profile
 // Teardrop2D between p1 and p2.
 //
 // If possible, the point of the teardrop will be facing
 // into the positive Z direction to avoid supports.
 func Teardrop3D(p1, p2 model3d.Coord3D, radius float64) model3d.Solid {
+	return extrudeUpward(p1, p2, &Teardrop2D{Radius: radius})
+}
+
+// TruncatedTeardrop3D is like Teardrop3D, but cuts off
+// the tip of the teardrop to make a flat top.
+//
+// The truncation argument is the distance from the axis
+// to the top of the hole. See Teardrop2D.Truncation.
+func TruncatedTeardrop3D(p1, p2 model3d.Coord3D, radius, truncation float64) model3d.Solid {
+	return extrudeUpward(p1, p2, &Teardrop2D{Radius: radius, Truncation: truncation})
+}
+
+// BridgedCircle3D creates a hole between p1 and p2 by
+// extending a BridgedCircle2D profile.
+//
+// If possible, the flat side of the hole will be facing
+// into the positive Z direction, so that it can be
+// bridged instead of requiring supports.
+func BridgedCircle3D(p1, p2 model3d.Coord3D, radius float64) model3d.Solid {
+	return extrudeUpward(p1, p2, &BridgedCircle2D{Radius: radius})
+}
+
+// extrudeUpward extends a 2D profile between p1 and p2,
+// such that the profile's Y axis faces as close to the
+// positive Z direction as possible.
+func extrudeUpward(p1, p2 model3d.Coord3D, profile model2d.Solid) model3d.Solid {
 	length := p1.Dist(p2)
-	profileSolid := model3d.ProfileSolid(&Teardrop2D{
-		Radius: radius,
-	}, 0, length)
+	profileSolid := model3d.ProfileSolid(profile, 0, length)
 	zVec := p2.Sub(p1).Normalize()
 	yVec := model3d.Z(1).ProjectOut(zVec)
 	if yVec.Norm() < 1e-5 {
 		// If the profile is extended into the Z direction,
-		// then we default to use the Y axis for the tip.
+		// then we default to use the Y axis for the top.
 		yVec = model3d.Y(1).ProjectOut(zVec).Normalize()
 	} else {
 		yVec = yVec.Normalize()
@@ -0,0 +1,48 @@
+package toolbox3d
+
+import (
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestTeardrop2DTruncation(t *testing.T) {
+	td := &Teardrop2D{Center: model2d.XY(1, 2), Radius: 1, Truncation: 1.1}
+	if !td.Contains(model2d.XY(1, 3.05)) {
+		t.Error("expected point below truncation to be contained")
+	}
+	if td.Contains(model2d.XY(1, 3.15)) {
+		t.Error("expected point above truncation to be removed")
+	}
+	if !td.Contains(model2d.XY(1.9, 2.1)) {
+		t.Error("expected point in circle to be contained")
+	}
+}
+
+func TestBridgedCircle2D(t *testing.T) {
+	bc := &BridgedCircle2D{Radius: 2, Direction: model2d.X(-1)}
+	expected := map[model2d.Coord]bool{
+		model2d.XY(0, 0):       true,
+		model2d.XY(-1.9, 1.9):  true,
+		model2d.XY(-1.9, -1.9): true,
+		model2d.XY(-2.1, 0):    false,
+		model2d.XY(1.9, 1.9):   false,
+		model2d.XY(1.9, 0):     true,
+	}
+	for c, exp := range expected {
+		if bc.Contains(c) != exp {
+			t.Errorf("point %v: expected %v", c, exp)
+		}
+	}
+}
+
+func TestBridgedCircle3D(t *testing.T) {
+	solid := BridgedCircle3D(model3d.XYZ(0, 0, 1), model3d.XYZ(0, 5, 1), 1)
+	if !solid.Contains(model3d.XYZ(0.95, 2, 1.95)) {
+		t.Error("expected flat side to face up")
+	}
+	if solid.Contains(model3d.XYZ(0.95, 2, 0.05)) {
+		t.Error("expected round side to face down")
+	}
+}
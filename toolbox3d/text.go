@@ -0,0 +1,46 @@
+package toolbox3d
+
+import (
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+	"golang.org/x/image/font/sfnt"
+)
+
+// TextSolid creates a 3D solid from a string of text by
+// extruding the glyph outlines of a TrueType or OpenType
+// font along the Z axis, from Z=0 to Z=depth.
+//
+// The text is laid out in the XY plane just like
+// model2d.TextSolid, with the first line's baseline along
+// the x-axis.
+//
+// The result can be joined with or subtracted from other
+// solids to emboss or engrave labels. To emboss text onto
+// a curved surface, see Emboss.
+func TextSolid(f *sfnt.Font, text string, size, depth float64) (model3d.Solid, error) {
+	mesh, err := model2d.TextMesh(f, text, size, size*1e-3)
+	if err != nil {
+		return nil, err
+	}
+	if mesh.NumSegments() == 0 {
+		// Whitespace or empty text.
+		return model3d.FuncSolid(model3d.Origin, model3d.Origin, func(c model3d.Coord3D) bool {
+			return false
+		}), nil
+	}
+	solid2d := model2d.NewColliderSolid(model2d.MeshToCollider(mesh))
+	return model3d.ProfileSolid(solid2d, 0, depth), nil
+}
+
+// TextMesh is like TextSolid, but creates a closed mesh
+// directly from the glyph outlines.
+//
+// Curves in the outlines are flattened to segments within
+// the given tolerance.
+func TextMesh(f *sfnt.Font, text string, size, depth, tolerance float64) (*model3d.Mesh, error) {
+	mesh, err := model2d.TextMesh(f, text, size, tolerance)
+	if err != nil {
+		return nil, err
+	}
+	return model3d.ProfileMesh(mesh, 0, depth), nil
+}
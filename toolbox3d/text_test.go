@@ -0,0 +1,62 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model2d"
+	"github.com/unixpickle/model3d/model3d"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+)
+
+func TestText(t *testing.T) {
+	f, err := sfnt.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	solid, err := TextSolid(f, "Hi o", 2, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solid2d, err := model2d.TextSolid(f, "Hi o", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min := solid.Min(); min.Z != 0 || min.XY() != solid2d.Min() {
+		t.Errorf("unexpected min: %v", min)
+	}
+	if max := solid.Max(); max.Z != 0.5 || max.XY() != solid2d.Max() {
+		t.Errorf("unexpected max: %v", max)
+	}
+	for i := 0; i < 1000; i++ {
+		c := model3d.NewCoord3DRandBounds(solid.Min(), solid.Max())
+		if solid.Contains(c) != solid2d.Contains(c.XY()) {
+			t.Fatalf("unexpected containment at %v", c)
+		}
+	}
+
+	mesh, err := TextMesh(f, "Hi o", 2, 0.5, 1e-3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mesh.NeedsRepair() || len(mesh.SingularVertices()) != 0 {
+		t.Error("mesh is not manifold")
+	}
+	if _, n := mesh.RepairNormals(1e-5); n != 0 {
+		t.Errorf("mesh has %d flipped normals", n)
+	}
+	mesh2d, _ := model2d.TextMesh(f, "Hi o", 2, 1e-3)
+	if v, expected := mesh.Volume(), mesh2d.Area()*0.5; math.Abs(v-expected) > 1e-8 {
+		t.Errorf("expected volume %f but got %f", expected, v)
+	}
+
+	empty, err := TextSolid(f, " ", 2, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.Contains(model3d.Origin) {
+		t.Error("empty text should be empty")
+	}
+}
@@ -0,0 +1,237 @@
+package toolbox3d
+
+import (
+	"math"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+// A ThreadProfile describes the size of a standard 60
+// degree screw thread, its matching hex nut, and the heads
+// of common screws with the thread.
+//
+// All dimensions are in millimeters, including for
+// Unified (inch) threads.
+type ThreadProfile struct {
+	// MajorDiameter is the outer diameter of the thread.
+	MajorDiameter float64
+
+	// Pitch is the axial distance between threads.
+	Pitch float64
+
+	// NutWidth is the distance across the flats of a
+	// standard hex nut for the thread.
+	NutWidth float64
+
+	// NutHeight is the thickness of a standard hex nut.
+	NutHeight float64
+
+	// HeadDiameter is the diameter of the head of a
+	// socket head cap screw. The height of the head is
+	// equal to MajorDiameter.
+	HeadDiameter float64
+
+	// CountersunkDiameter is the diameter of the head of
+	// a flat (countersunk) head screw.
+	CountersunkDiameter float64
+
+	// CountersinkAngle is the included angle of the cone
+	// under the head of a flat head screw, in radians.
+	CountersinkAngle float64
+}
+
+// ISOMetricThreads maps names like "M3" to coarse ISO
+// metric threads, with nut sizes from ISO 4032 and head
+// sizes from ISO 4762 and ISO 10642.
+var ISOMetricThreads = map[string]*ThreadProfile{
+	"M2": {MajorDiameter: 2, Pitch: 0.4, NutWidth: 4, NutHeight: 1.6,
+		HeadDiameter: 3.8, CountersunkDiameter: 4.48, CountersinkAngle: math.Pi / 2},
+	"M2.5": {MajorDiameter: 2.5, Pitch: 0.45, NutWidth: 5, NutHeight: 2,
+		HeadDiameter: 4.5, CountersunkDiameter: 5.6, CountersinkAngle: math.Pi / 2},
+	"M3": {MajorDiameter: 3, Pitch: 0.5, NutWidth: 5.5, NutHeight: 2.4,
+		HeadDiameter: 5.5, CountersunkDiameter: 6.72, CountersinkAngle: math.Pi / 2},
+	"M4": {MajorDiameter: 4, Pitch: 0.7, NutWidth: 7, NutHeight: 3.2,
+		HeadDiameter: 7, CountersunkDiameter: 8.96, CountersinkAngle: math.Pi / 2},
+	"M5": {MajorDiameter: 5, Pitch: 0.8, NutWidth: 8, NutHeight: 4.7,
+		HeadDiameter: 8.5, CountersunkDiameter: 11.2, CountersinkAngle: math.Pi / 2},
+	"M6": {MajorDiameter: 6, Pitch: 1, NutWidth: 10, NutHeight: 5.2,
+		HeadDiameter: 10, CountersunkDiameter: 13.44, CountersinkAngle: math.Pi / 2},
+	"M8": {MajorDiameter: 8, Pitch: 1.25, NutWidth: 13, NutHeight: 6.8,
+		HeadDiameter: 13, CountersunkDiameter: 17.92, CountersinkAngle: math.Pi / 2},
+	"M10": {MajorDiameter: 10, Pitch: 1.5, NutWidth: 16, NutHeight: 8.4,
+		HeadDiameter: 16, CountersunkDiameter: 22.4, CountersinkAngle: math.Pi / 2},
+	"M12": {MajorDiameter: 12, Pitch: 1.75, NutWidth: 18, NutHeight: 10.8,
+		HeadDiameter: 18, CountersunkDiameter: 26.88, CountersinkAngle: math.Pi / 2},
+}
+
+// UNCThreads maps names like "1/4-20" to Unified coarse
+// threads.
+var UNCThreads = map[string]*ThreadProfile{
+	"#4-40":   newUnifiedThread(0.112, 40, 1.0/4, 3.0/32, 0.183, 0.255),
+	"#6-32":   newUnifiedThread(0.138, 32, 5.0/16, 7.0/64, 0.226, 0.307),
+	"#8-32":   newUnifiedThread(0.164, 32, 11.0/32, 1.0/8, 0.27, 0.359),
+	"#10-24":  newUnifiedThread(0.19, 24, 3.0/8, 1.0/8, 0.312, 0.411),
+	"1/4-20":  newUnifiedThread(0.25, 20, 7.0/16, 7.0/32, 0.375, 0.531),
+	"5/16-18": newUnifiedThread(0.3125, 18, 1.0/2, 17.0/64, 0.469, 0.656),
+	"3/8-16":  newUnifiedThread(0.375, 16, 9.0/16, 21.0/64, 0.5625, 0.781),
+	"1/2-13":  newUnifiedThread(0.5, 13, 3.0/4, 7.0/16, 0.75, 1.031),
+}
+
+// UNFThreads maps names like "1/4-28" to Unified fine
+// threads.
+var UNFThreads = map[string]*ThreadProfile{
+	"#4-48":   newUnifiedThread(0.112, 48, 1.0/4, 3.0/32, 0.183, 0.255),
+	"#6-40":   newUnifiedThread(0.138, 40, 5.0/16, 7.0/64, 0.226, 0.307),
+	"#8-36":   newUnifiedThread(0.164, 36, 11.0/32, 1.0/8, 0.27, 0.359),
+	"#10-32":  newUnifiedThread(0.19, 32, 3.0/8, 1.0/8, 0.312, 0.411),
+	"1/4-28":  newUnifiedThread(0.25, 28, 7.0/16, 7.0/32, 0.375, 0.531),
+	"5/16-24": newUnifiedThread(0.3125, 24, 1.0/2, 17.0/64, 0.469, 0.656),
+	"3/8-24":  newUnifiedThread(0.375, 24, 9.0/16, 21.0/64, 0.5625, 0.781),
+	"1/2-20":  newUnifiedThread(0.5, 20, 3.0/4, 7.0/16, 0.75, 1.031),
+}
+
+func newUnifiedThread(diameter, threadsPerInch, nutWidth, nutHeight, headDiameter,
+	countersunkDiameter float64) *ThreadProfile {
+	const mmPerInch = 25.4
+	return &ThreadProfile{
+		MajorDiameter:       diameter * mmPerInch,
+		Pitch:               mmPerInch / threadsPerInch,
+		NutWidth:            nutWidth * mmPerInch,
+		NutHeight:           nutHeight * mmPerInch,
+		HeadDiameter:        headDiameter * mmPerInch,
+		CountersunkDiameter: countersunkDiameter * mmPerInch,
+		CountersinkAngle:    82 * math.Pi / 180,
+	}
+}
+
+// MinorDiameter gets the diameter at the root of the
+// basic thread profile.
+func (t *ThreadProfile) MinorDiameter() float64 {
+	return t.MajorDiameter - 2*isoThreadDepth(t.Pitch)
+}
+
+// Screw creates an external thread from p1 to p2.
+//
+// The clearance is subtracted from the radius of the
+// thread, so that the screw fits into holes made with the
+// same clearance. For 3D printing, a clearance around
+// 0.1-0.2mm is typical.
+func (t *ThreadProfile) Screw(p1, p2 model3d.Coord3D, clearance float64) *ScrewSolid {
+	return &ScrewSolid{
+		P1:     p1,
+		P2:     p2,
+		Radius: t.MajorDiameter/2 - clearance,
+		Pitch:  t.Pitch,
+	}
+}
+
+// Hole creates a solid that can be subtracted from a part
+// to create an internal thread from p1 to p2.
+//
+// The clearance is added to the radius of the thread.
+func (t *ThreadProfile) Hole(p1, p2 model3d.Coord3D, clearance float64) *ScrewSolid {
+	return &ScrewSolid{
+		P1:     p1,
+		P2:     p2,
+		Radius: t.MajorDiameter/2 + clearance,
+		Pitch:  t.Pitch,
+	}
+}
+
+// NutCutout creates a hexagonal prism from p1 to p2 that
+// can be subtracted from a part to make a pocket for a
+// captive nut.
+//
+// The clearance is added to the distance from the center
+// of the nut to each flat.
+func (t *ThreadProfile) NutCutout(p1, p2 model3d.Coord3D, clearance float64) model3d.Solid {
+	return hexPrism(p1, p2, t.NutWidth/2+clearance)
+}
+
+// Nut creates a hex nut from p1 to p2, with an internal
+// thread of the given clearance.
+//
+// Typically, the distance from p1 to p2 should be
+// NutHeight.
+func (t *ThreadProfile) Nut(p1, p2 model3d.Coord3D, clearance float64) model3d.Solid {
+	return &model3d.SubtractedSolid{
+		Positive: hexPrism(p1, p2, t.NutWidth/2),
+		Negative: t.Hole(p1, p2, clearance),
+	}
+}
+
+// CounterboreHole creates a solid that can be subtracted
+// from a part to make a hole for a socket head cap screw,
+// inserted at p1 and passing through to p2.
+//
+// The counterbore for the head starts at p1 and has the
+// given depth. If depth is 0, the height of the head is
+// used, so that the head is flush with p1.
+//
+// The clearance is added to the radius of the through
+// hole and the counterbore.
+func (t *ThreadProfile) CounterboreHole(p1, p2 model3d.Coord3D, depth,
+	clearance float64) model3d.Solid {
+	if depth == 0 {
+		depth = t.MajorDiameter
+	}
+	axis := p2.Sub(p1).Normalize()
+	return model3d.JoinedSolid{
+		&model3d.Cylinder{
+			P1:     p1,
+			P2:     p2,
+			Radius: t.MajorDiameter/2 + clearance,
+		},
+		&model3d.Cylinder{
+			P1:     p1,
+			P2:     p1.Add(axis.Scale(depth)),
+			Radius: t.HeadDiameter/2 + clearance,
+		},
+	}
+}
+
+// CountersinkHole creates a solid that can be subtracted
+// from a part to make a hole for a flat head screw,
+// inserted at p1 and passing through to p2.
+//
+// The countersink is sized so that the top of the head
+// is flush with p1.
+//
+// The clearance is added to the radius of the through
+// hole and the countersink.
+func (t *ThreadProfile) CountersinkHole(p1, p2 model3d.Coord3D,
+	clearance float64) model3d.Solid {
+	axis := p2.Sub(p1).Normalize()
+	radius := t.CountersunkDiameter/2 + clearance
+	coneHeight := radius / math.Tan(t.CountersinkAngle/2)
+	return model3d.JoinedSolid{
+		&model3d.Cylinder{
+			P1:     p1,
+			P2:     p2,
+			Radius: t.MajorDiameter/2 + clearance,
+		},
+		&model3d.Cone{
+			Tip:    p1.Add(axis.Scale(coneHeight)),
+			Base:   p1,
+			Radius: radius,
+		},
+	}
+}
+
+func hexPrism(p1, p2 model3d.Coord3D, apothem float64) model3d.Solid {
+	axis := p2.Sub(p1).Normalize()
+	b1, b2 := axis.OrthoBasis()
+	p := model3d.ConvexPolytope{
+		&model3d.LinearConstraint{Normal: axis, Max: axis.Dot(p2)},
+		&model3d.LinearConstraint{Normal: axis.Scale(-1), Max: -axis.Dot(p1)},
+	}
+	for i := 0; i < 6; i++ {
+		theta := float64(i) * math.Pi / 3
+		normal := b1.Scale(math.Cos(theta)).Add(b2.Scale(math.Sin(theta)))
+		p = append(p, &model3d.LinearConstraint{
+			Normal: normal,
+			Max:    normal.Dot(p1) + apothem,
+		})
+	}
+	return p.Solid()
+}
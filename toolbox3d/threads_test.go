@@ -0,0 +1,85 @@
+package toolbox3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/unixpickle/model3d/model3d"
+)
+
+func TestThreadProfile(t *testing.T) {
+	for name, profile := range ISOMetricThreads {
+		p1, p2 := model3d.Z(-5), model3d.Z(5)
+		screw := profile.Screw(p1, p2, 0.1)
+		hole := profile.Hole(p1, p2, 0.1)
+		for i := 0; i < 10000; i++ {
+			c := model3d.NewCoord3DRandBounds(screw.Min(), screw.Max())
+			if screw.Contains(c) && !hole.Contains(c) {
+				t.Fatalf("%s: screw should fit inside hole at %v", name, c)
+			}
+		}
+
+		// The thread should span the full depth of the
+		// basic profile.
+		r := profile.MajorDiameter / 2
+		var minR, maxR = math.Inf(1), 0.0
+		for z := 0.0; z < profile.Pitch; z += profile.Pitch / 100 {
+			for radius := 0.0; radius < r; radius += r / 1000 {
+				if !screw.Contains(model3d.XYZ(radius, 0, z)) {
+					minR = math.Min(minR, radius)
+					maxR = math.Max(maxR, radius)
+					break
+				}
+			}
+		}
+		if math.Abs(maxR-(r-0.1)) > r/500 || math.Abs(minR-(profile.MinorDiameter()/2-0.1)) > r/500 {
+			t.Errorf("%s: unexpected thread radii %f to %f", name, minR, maxR)
+		}
+
+		nut := profile.Nut(p1, p1.Add(model3d.Z(profile.NutHeight)), 0.1)
+		cutout := profile.NutCutout(p1, p1.Add(model3d.Z(profile.NutHeight)), 0.1)
+		for i := 0; i < 1000; i++ {
+			c := model3d.NewCoord3DRandBounds(nut.Min(), nut.Max())
+			if nut.Contains(c) && !cutout.Contains(c) {
+				t.Fatalf("%s: nut should fit inside cutout", name)
+			}
+		}
+		if nut.Max().X-nut.Min().X < profile.NutWidth {
+			t.Errorf("%s: nut is too small", name)
+		}
+	}
+}
+
+func TestThreadProfileScrewHoles(t *testing.T) {
+	profile := ISOMetricThreads["M3"]
+	p1, p2 := model3d.Z(10), model3d.Z(0)
+
+	counterbore := profile.CounterboreHole(p1, p2, 0, 0.1)
+	expected := map[model3d.Coord3D]bool{
+		model3d.XYZ(2.7, 0, 9.9):  true,
+		model3d.XYZ(2.7, 0, 7.1):  true,
+		model3d.XYZ(2.7, 0, 6.9):  false,
+		model3d.XYZ(1.55, 0, 0.1): true,
+		model3d.XYZ(1.65, 0, 0.1): false,
+	}
+	for c, exp := range expected {
+		if counterbore.Contains(c) != exp {
+			t.Errorf("counterbore: point %v should have containment %v", c, exp)
+		}
+	}
+
+	countersink := profile.CountersinkHole(p1, p2, 0.1)
+	expected = map[model3d.Coord3D]bool{
+		model3d.XYZ(3.4, 0, 9.99): true,
+		model3d.XYZ(3.5, 0, 9.99): false,
+		model3d.XYZ(2.4, 0, 9):    true,
+		model3d.XYZ(2.4, 0, 8.8):  false,
+		model3d.XYZ(1.55, 0, 0.1): true,
+		model3d.XYZ(1.65, 0, 0.1): false,
+	}
+	for c, exp := range expected {
+		if countersink.Contains(c) != exp {
+			t.Errorf("countersink: point %v should have containment %v", c, exp)
+		}
+	}
+}